@@ -0,0 +1,99 @@
+package gttelemetry
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+const (
+	modulePath = "github.com/zetetos/gt-telemetry/v2"
+
+	// apiVersion is the semantic version of the exported API listed in api.txt. The minor version is incremented on
+	// release when exports have been added since the last release, the major version only changes with the module path.
+	apiVersion = "2.0.0"
+)
+
+// ErrIncompatibleAPI indicates that the API of the library does not provide the API version required by the caller.
+var ErrIncompatibleAPI = errors.New("incompatible API version")
+
+// Version returns the version of the module the library was built from, such as "v2.4.1", as recorded in the build
+// information of the binary. It is "(devel)" when built from a checkout of the repository and "unknown" when the
+// binary has no build information.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Path == modulePath {
+		return moduleVersion(&info.Main)
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return moduleVersion(dep)
+		}
+	}
+
+	return "(devel)"
+}
+
+// APIVersion returns the semantic version of the exported API, without a "v" prefix. Versions with the same major
+// version are compatible, and a newer minor version only adds to the API.
+func APIVersion() string {
+	return apiVersion
+}
+
+// CheckAPIVersion checks that the library provides the API of a version, such as "2.3" or "v2.3.0", so that an
+// application or plugin can fail at start up rather than part way through when linked against an older library.
+func CheckAPIVersion(required string) error {
+	want, err := parseAPIVersion(required)
+	if err != nil {
+		return err
+	}
+
+	have, _ := parseAPIVersion(apiVersion)
+
+	if want[0] != have[0] || want[1] > have[1] || (want[1] == have[1] && want[2] > have[2]) {
+		return fmt.Errorf("%w: %s required, library provides %s", ErrIncompatibleAPI, required, apiVersion)
+	}
+
+	return nil
+}
+
+// moduleVersion returns the version of a module, following a replace directive.
+func moduleVersion(module *debug.Module) string {
+	if module.Replace != nil && module.Replace.Version != "" {
+		return module.Replace.Version
+	}
+
+	if module.Version == "" {
+		return "(devel)"
+	}
+
+	return module.Version
+}
+
+// parseAPIVersion parses the major, minor and patch numbers of a version, with the minor and patch numbers optional.
+func parseAPIVersion(version string) ([3]int, error) {
+	parsed := [3]int{}
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+
+	if len(parts) > len(parsed) {
+		return parsed, fmt.Errorf("%w: %q is not a semantic version", ErrIncompatibleAPI, version)
+	}
+
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return parsed, fmt.Errorf("%w: %q is not a semantic version", ErrIncompatibleAPI, version)
+		}
+
+		parsed[i] = number
+	}
+
+	return parsed, nil
+}
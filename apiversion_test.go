@@ -0,0 +1,74 @@
+package gttelemetry_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/internal/apimanifest"
+)
+
+type APIVersionTestSuite struct {
+	suite.Suite
+}
+
+func TestAPIVersionTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(APIVersionTestSuite))
+}
+
+func (suite *APIVersionTestSuite) TestVersionIsReported() {
+	// Act
+	gotVersion := gttelemetry.Version()
+
+	// Assert
+	suite.NotEmpty(gotVersion)
+	suite.Regexp(`^\d+\.\d+\.\d+$`, gttelemetry.APIVersion())
+}
+
+func (suite *APIVersionTestSuite) TestCheckAPIVersion() {
+	tests := map[string]struct {
+		required  string
+		wantError bool
+	}{
+		"current version":     {required: gttelemetry.APIVersion()},
+		"prefixed version":    {required: "v" + gttelemetry.APIVersion()},
+		"major version only":  {required: "2"},
+		"older minor version": {required: "2.0"},
+		"newer minor version": {required: "2.999", wantError: true},
+		"older major version": {required: "1.9.0", wantError: true},
+		"newer major version": {required: "3.0.0", wantError: true},
+		"not a version":       {required: "latest", wantError: true},
+		"too many parts":      {required: "2.0.0.1", wantError: true},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Act
+			err := gttelemetry.CheckAPIVersion(tc.required)
+
+			// Assert
+			if tc.wantError {
+				suite.ErrorIs(err, gttelemetry.ErrIncompatibleAPI)
+			} else {
+				suite.NoError(err)
+			}
+		})
+	}
+}
+
+// TestAPIManifestIsCurrent fails when the exported API no longer matches api.txt, so that every change to the API is
+// reviewed as a change to the manifest. Regenerate it with make api/manifest.
+func (suite *APIVersionTestSuite) TestAPIManifestIsCurrent() {
+	// Arrange
+	wantManifest, err := os.ReadFile("api.txt")
+	suite.Require().NoError(err)
+
+	// Act
+	gotManifest, err := apimanifest.Generate(".", "github.com/zetetos/gt-telemetry/v2", gttelemetry.APIVersion(), "pkg")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(string(wantManifest), string(gotManifest), "the exported API has changed, run make api/manifest")
+}
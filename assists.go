@@ -0,0 +1,55 @@
+package gttelemetry
+
+const (
+	// assistMinInputPercent is the smallest throttle or brake input at which an intervention is estimated, as the
+	// share of a near zero input that is cut is dominated by rounding.
+	assistMinInputPercent = 5
+	// tcsFullSpin is the excess wheel spin at which traction control is treated as intervening fully, e.g. 0.3 for a
+	// wheel turning 30% faster than the car is moving.
+	tcsFullSpin = 0.3
+	// absMinLock is how much slower than the car a wheel must turn before a cut in brake output is put down to ABS
+	// rather than stability management.
+	absMinLock = 0.05
+)
+
+// TCSInterventionPercent estimates how hard traction control is intervening, from 0 when it is not intervening to
+// 100 when it cuts the throttle completely. While the game sets the TCS flag, the intervention is the larger of the
+// share of the throttle input that does not reach the engine and the excess spin of the fastest wheel, so that
+// interventions which brake a spinning wheel without cutting the throttle are also shown.
+func (t *Transformer) TCSInterventionPercent() float32 {
+	if !t.Flags().TCSActive {
+		return 0
+	}
+
+	spin := float32(0)
+	slip := t.TyreSlipRatio()
+
+	for _, ratio := range []float32{slip.FrontLeft, slip.FrontRight, slip.RearLeft, slip.RearRight} {
+		spin = max(spin, (ratio-1)/tcsFullSpin)
+	}
+
+	return 100 * min(max(inputCut(t.ThrottleInputPercent(), t.ThrottleOutputPercent()), spin), 1)
+}
+
+// ABSInterventionPercent estimates how hard ABS is intervening, from 0 when it is not intervening to 100 when it
+// releases the brakes completely. The game has no ABS flag, so the intervention is the share of the brake input that
+// does not reach the brakes, counted only while a wheel is turning slower than the car as it does when ABS is
+// modulating a wheel on the point of locking.
+func (t *Transformer) ABSInterventionPercent() float32 {
+	slip := t.TyreSlipRatio()
+
+	if min(slip.FrontLeft, slip.FrontRight, slip.RearLeft, slip.RearRight) > 1-absMinLock {
+		return 0
+	}
+
+	return 100 * inputCut(t.BrakeInputPercent(), t.BrakeOutputPercent())
+}
+
+// inputCut returns the share of an input that does not reach the output, from 0 to 1.
+func inputCut(input, output float32) float32 {
+	if input < assistMinInputPercent {
+		return 0
+	}
+
+	return min(max((input-output)/input, 0), 1)
+}
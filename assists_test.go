@@ -0,0 +1,95 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type AssistsTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestAssistsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(AssistsTestSuite))
+}
+
+func (suite *AssistsTestSuite) SetupTest() {
+	suite.transformer = gttelemetry.NewTransformer(nil)
+	suite.transformer.RawTelemetry.GroundSpeed = 20
+	suite.transformer.SetTyreRadius(0.3, 0.3, 0.3, 0.3)
+	suite.wheelSpeeds(20, 20, 20, 20)
+}
+
+// wheelSpeeds sets the speed of each wheel in metres per second.
+func (suite *AssistsTestSuite) wheelSpeeds(fl, fr, rl, rr float32) {
+	suite.transformer.SetWheelRadiansPerSecond(fl/0.3, fr/0.3, rl/0.3, rr/0.3)
+}
+
+func (suite *AssistsTestSuite) tcs(active bool) {
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, active)
+}
+
+func (suite *AssistsTestSuite) TestTCSInterventionPercent() {
+	tests := map[string]struct {
+		active   bool
+		throttle [2]uint8
+		rearLeft float32
+		want     float32
+	}{
+		"inactive":                {active: false, throttle: [2]uint8{255, 51}, rearLeft: 30},
+		"throttle cut":            {active: true, throttle: [2]uint8{255, 51}, rearLeft: 20, want: 80},
+		"wheel spin without cut":  {active: true, throttle: [2]uint8{255, 255}, rearLeft: 23, want: 50},
+		"larger of cut and spin":  {active: true, throttle: [2]uint8{200, 150}, rearLeft: 23, want: 50},
+		"full spin is capped":     {active: true, throttle: [2]uint8{255, 255}, rearLeft: 40, want: 100},
+		"no throttle and no spin": {active: true, throttle: [2]uint8{0, 0}, rearLeft: 20},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.tcs(tc.active)
+			suite.transformer.RawTelemetry.ThrottleInput = tc.throttle[0]
+			suite.transformer.RawTelemetry.ThrottleOutput = tc.throttle[1]
+			suite.wheelSpeeds(20, 20, tc.rearLeft, 20)
+
+			// Act
+			got := suite.transformer.TCSInterventionPercent()
+
+			// Assert
+			suite.InDelta(tc.want, got, 0.01)
+		})
+	}
+}
+
+func (suite *AssistsTestSuite) TestABSInterventionPercent() {
+	tests := map[string]struct {
+		brake     [2]uint8
+		frontLeft float32
+		want      float32
+	}{
+		"modulating a locking wheel":  {brake: [2]uint8{255, 153}, frontLeft: 17, want: 40},
+		"cut without a locking wheel": {brake: [2]uint8{255, 153}, frontLeft: 20},
+		"locking without a cut":       {brake: [2]uint8{255, 255}, frontLeft: 10},
+		"light braking":               {brake: [2]uint8{10, 0}, frontLeft: 10},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.transformer.RawTelemetry.BrakeInput = tc.brake[0]
+			suite.transformer.RawTelemetry.BrakeOutput = tc.brake[1]
+			suite.wheelSpeeds(tc.frontLeft, 20, 20, 20)
+
+			// Act
+			got := suite.transformer.ABSInterventionPercent()
+
+			// Assert
+			suite.InDelta(tc.want, got, 0.01)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package gttelemetry
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// boostFullThrottlePercent is the throttle input that counts as the throttle being applied for a boost response,
+	// allowing for pedals that do not quite reach the end of their travel.
+	boostFullThrottlePercent = 90
+	// boostLiftPercent is the throttle input the throttle must fall below before it is applied again, so that small
+	// corrections at full throttle are not measured as responses.
+	boostLiftPercent = 50
+	// boostResponseWindow is the longest time after the throttle is applied that the boost is followed for.
+	boostResponseWindow = 3 * time.Second
+	// boostSpoolFraction is the fraction of the peak boost the turbo must reach to count as spooled.
+	boostSpoolFraction = 0.9
+)
+
+// BoostResponse describes how quickly the turbo spooled after the throttle was applied.
+type BoostResponse struct {
+	SequenceID uint32 `json:"sequenceId"`
+	Lap        int16  `json:"lap"`
+	Gear       int    `json:"gear"`
+	// EngineRPM is the engine speed when the throttle was applied.
+	EngineRPM float32 `json:"engineRPM"`
+	// StartBoostBar is the boost when the throttle was applied, negative under vacuum.
+	StartBoostBar float32 `json:"startBoostBar"`
+	// PeakBoostBar is the highest boost seen in the gear at full throttle, the boost the turbo spooled towards.
+	PeakBoostBar float32 `json:"peakBoostBar"`
+	// SpoolTime is the time from the throttle being applied to the boost reaching 90% of PeakBoostBar.
+	SpoolTime time.Duration `json:"spoolTime"`
+}
+
+// GearBoost summarises the boost in a gear.
+type GearBoost struct {
+	Gear int `json:"gear"`
+	// PeakBoostBar is the highest boost seen in the gear at full throttle.
+	PeakBoostBar float32 `json:"peakBoostBar"`
+	// AverageSpoolTime is the average SpoolTime of the responses in the gear, zero when there were none.
+	AverageSpoolTime time.Duration `json:"averageSpoolTime"`
+	// Responses is the number of responses measured in the gear.
+	Responses int `json:"responses"`
+}
+
+type boostSample struct {
+	elapsed time.Duration
+	boost   float32
+}
+
+// BoostAnalyser measures the response of the turbo each time the throttle is applied, for tuning turbo cars.
+//
+// A response starts when the throttle is applied after being lifted or the gear changes at full throttle, and is
+// followed until the throttle is lifted, the gear changes or boostResponseWindow has passed. The spool time is
+// measured to 90% of the peak boost of the gear, so responses that end before the turbo has spooled, such as short
+// bursts of throttle, are not reported.
+type BoostAnalyser struct {
+	lastSequence uint32
+	lastGear     int
+	lifted       bool
+	following    bool
+	current      BoostResponse
+	samples      []boostSample
+	gears        map[int]*GearBoost
+	spoolTimes   map[int]time.Duration
+	responses    []BoostResponse
+}
+
+// NewBoostAnalyser creates a boost analyser without any responses.
+func NewBoostAnalyser() *BoostAnalyser {
+	return &BoostAnalyser{
+		gears:      make(map[int]*GearBoost),
+		spoolTimes: make(map[int]time.Duration),
+	}
+}
+
+// Update evaluates the latest telemetry frame and returns the boost response when one has just been measured.
+func (a *BoostAnalyser) Update(t *Transformer) (BoostResponse, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == a.lastSequence {
+		return BoostResponse{}, false
+	}
+
+	previousSequence := a.lastSequence
+	a.lastSequence = sequenceID
+
+	flags := t.Flags()
+	if flags.GamePaused {
+		return BoostResponse{}, false
+	}
+
+	if !flags.HasTurbo || !t.IsOnCircuit() {
+		a.following = false
+		a.lifted = false
+		a.lastGear = 0
+
+		return BoostResponse{}, false
+	}
+
+	throttle := t.ThrottleInputPercent()
+	gear := t.CurrentGear()
+	boost := t.TurboBoostBar()
+	fullThrottle := throttle >= boostFullThrottlePercent
+	inGear := flags.InGear && gear > 0
+
+	if fullThrottle && inGear {
+		a.gear(gear).PeakBoostBar = max(a.gear(gear).PeakBoostBar, boost)
+	}
+
+	var (
+		response BoostResponse
+		measured bool
+	)
+
+	if a.following {
+		elapsed := a.samples[len(a.samples)-1].elapsed + framesElapsed(previousSequence, sequenceID)
+
+		if !fullThrottle || gear != a.current.Gear || elapsed > boostResponseWindow {
+			a.following = false
+			response, measured = a.complete()
+		} else {
+			a.samples = append(a.samples, boostSample{elapsed: elapsed, boost: boost})
+		}
+	}
+
+	// The turbo spools again after the throttle is lifted or after the boost dips through a gear change
+	if fullThrottle && (a.lifted || gear != a.lastGear) && inGear && !a.following {
+		a.following = true
+		a.current = BoostResponse{
+			SequenceID:    sequenceID,
+			Lap:           t.CurrentLap(),
+			Gear:          gear,
+			EngineRPM:     t.EngineRPM(),
+			StartBoostBar: boost,
+		}
+		a.samples = append(a.samples[:0], boostSample{boost: boost})
+	}
+
+	a.lifted = throttle < boostLiftPercent || (a.lifted && !fullThrottle)
+	a.lastGear = gear
+
+	return response, measured
+}
+
+// Responses returns all boost responses measured so far.
+func (a *BoostAnalyser) Responses() []BoostResponse {
+	return a.responses
+}
+
+// Gears returns the boost in each gear driven at full throttle, in gear order.
+func (a *BoostAnalyser) Gears() []GearBoost {
+	gears := make([]GearBoost, 0, len(a.gears))
+
+	for _, gear := range a.gears {
+		summary := *gear
+		if summary.Responses > 0 {
+			summary.AverageSpoolTime = a.spoolTimes[gear.Gear] / time.Duration(summary.Responses)
+		}
+
+		gears = append(gears, summary)
+	}
+
+	slices.SortFunc(gears, func(a, b GearBoost) int {
+		return a.Gear - b.Gear
+	})
+
+	return gears
+}
+
+func (a *BoostAnalyser) gear(gear int) *GearBoost {
+	summary, ok := a.gears[gear]
+	if !ok {
+		summary = &GearBoost{Gear: gear}
+		a.gears[gear] = summary
+	}
+
+	return summary
+}
+
+// complete measures the spool time of the response being followed, returning false when the boost did not reach 90%
+// of the peak boost of the gear.
+func (a *BoostAnalyser) complete() (BoostResponse, bool) {
+	peak := a.gear(a.current.Gear).PeakBoostBar
+	if peak <= 0 || a.current.StartBoostBar >= peak*boostSpoolFraction {
+		return BoostResponse{}, false
+	}
+
+	for _, sample := range a.samples {
+		if sample.boost >= peak*boostSpoolFraction {
+			response := a.current
+			response.PeakBoostBar = peak
+			response.SpoolTime = sample.elapsed
+
+			a.responses = append(a.responses, response)
+			a.gear(response.Gear).Responses++
+			a.spoolTimes[response.Gear] += response.SpoolTime
+
+			return response, true
+		}
+	}
+
+	return BoostResponse{}, false
+}
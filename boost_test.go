@@ -0,0 +1,130 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type BoostTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	analyser    *gttelemetry.BoostAnalyser
+}
+
+func TestBoostTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(BoostTestSuite))
+}
+
+func (suite *BoostTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.SetFlags(true, false, false, true, true, false, false, false, false, false, false, false)
+	suite.transformer.SetTransmissionGear(3, 4)
+	suite.analyser = gttelemetry.NewBoostAnalyser()
+}
+
+// frame sends a frame with the given throttle input and boost.
+func (suite *BoostTestSuite) frame(throttle uint8, boost float32) (gttelemetry.BoostResponse, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.ThrottleInput = throttle
+	suite.transformer.RawTelemetry.ManifoldPressure = boost + 1
+
+	return suite.analyser.Update(suite.transformer)
+}
+
+// spool applies the throttle and raises the boost by step each frame up to peak, holding it for frames in total.
+func (suite *BoostTestSuite) spool(step, peak float32, frames int) (responses []gttelemetry.BoostResponse) {
+	for frame := range frames {
+		if response, ok := suite.frame(255, min(float32(frame)*step, peak)); ok {
+			responses = append(responses, response)
+		}
+	}
+
+	return responses
+}
+
+func (suite *BoostTestSuite) TestSpoolTimeIsMeasuredToNinetyPercentOfPeak() {
+	// Arrange
+	suite.frame(0, -0.5)
+	suite.spool(0.25, 2, 60)
+
+	// Act
+	response, ok := suite.frame(0, -0.5)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(3, response.Gear)
+	suite.InDelta(0, response.StartBoostBar, 1e-6)
+	suite.InDelta(2, response.PeakBoostBar, 1e-6)
+	suite.InDelta((8 * time.Second / gttelemetry.FrameRate).Seconds(), response.SpoolTime.Seconds(), 1e-6)
+}
+
+func (suite *BoostTestSuite) TestResponseEndsAfterWindow() {
+	// Arrange
+	suite.frame(0, -0.5)
+
+	// Act
+	responses := suite.spool(0.25, 2, 5*gttelemetry.FrameRate)
+
+	// Assert
+	suite.Len(responses, 1, "the response is measured once the window has passed, while still at full throttle")
+}
+
+func (suite *BoostTestSuite) TestShortBurstIsNotAResponse() {
+	// Arrange
+	suite.frame(0, -0.5)
+	suite.spool(0.25, 2, 60)
+	suite.frame(0, -0.5)
+
+	// Act
+	suite.spool(0.25, 2, 4)
+	_, ok := suite.frame(0, -0.5)
+
+	// Assert
+	suite.False(ok, "the boost did not reach 90% of the peak of the gear")
+	suite.Len(suite.analyser.Responses(), 1)
+}
+
+func (suite *BoostTestSuite) TestGearsSummariseEachGear() {
+	// Arrange
+	suite.frame(0, -0.5)
+	suite.spool(0.25, 2, 60)
+	suite.transformer.SetTransmissionGear(4, 5)
+	suite.spool(0.1, 1.5, 60)
+	suite.frame(0, -0.5)
+
+	// Act
+	gears := suite.analyser.Gears()
+
+	// Assert
+	suite.Require().Len(gears, 2)
+	suite.Equal(3, gears[0].Gear)
+	suite.InDelta(2, gears[0].PeakBoostBar, 1e-6)
+	suite.Equal(1, gears[0].Responses)
+	suite.Equal(4, gears[1].Gear)
+	suite.InDelta(1.5, gears[1].PeakBoostBar, 1e-6)
+	suite.Equal(1, gears[1].Responses, "a gear change at full throttle starts a response")
+	suite.Positive(gears[1].AverageSpoolTime)
+}
+
+func (suite *BoostTestSuite) TestNaturallyAspiratedCarsAreIgnored() {
+	// Arrange
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.frame(0, -0.5)
+	suite.spool(0.25, 2, 60)
+
+	// Act
+	_, ok := suite.frame(0, -0.5)
+
+	// Assert
+	suite.False(ok)
+	suite.Empty(suite.analyser.Gears())
+}
@@ -0,0 +1,86 @@
+package gttelemetry
+
+import (
+	"sync"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/telemetry"
+)
+
+type cacheKey int
+
+const (
+	cacheKeyCurrentGearRatio cacheKey = iota
+	cacheKeyFinalDriveEstimate
+	cacheKeySetupFingerprint
+	cacheKeyTelemetryFormat
+	cacheKeyTransmission
+)
+
+// frameCache holds values derived from a single telemetry frame, identified by the generation of the transformer, so
+// that repeated getter calls within the same frame do not recompute them. The generation advances with every frame
+// set by the client, including frames that repeat a sequence ID, such as after the console restarts.
+type frameCache struct {
+	mu         sync.Mutex
+	generation uint64
+	values     map[cacheKey]any
+}
+
+// load returns the cached value for the key if it was stored for the given generation. Values from earlier
+// generations are discarded.
+func (c *frameCache) load(generation uint64, key cacheKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		c.generation = generation
+		clear(c.values)
+
+		return nil, false
+	}
+
+	value, ok := c.values[key]
+
+	return value, ok
+}
+
+// store saves the value for the key if the cache still holds the given generation.
+func (c *frameCache) store(generation uint64, key cacheKey, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		return
+	}
+
+	if c.values == nil {
+		c.values = make(map[cacheKey]any)
+	}
+
+	c.values[key] = value
+}
+
+// cached returns the value for the key computed from the current frame, calling compute only on the first request
+// for each frame. Only frames set by the client are cached, frames set by hand are recomputed on every call as their
+// contents may be changed in place.
+func cached[T any](t *Transformer, key cacheKey, compute func() T) T {
+	generation := t.generation
+	if generation == 0 {
+		return compute()
+	}
+
+	if value, ok := t.cache.load(generation, key); ok {
+		return value.(T) //nolint:forcetypeassert // Values are only stored by this function with a matching type
+	}
+
+	// Computed outside the lock as derived values may themselves depend on other cached values
+	value := compute()
+	t.cache.store(generation, key, value)
+
+	return value
+}
+
+// setFrame replaces the frame read by the getters, starting a new generation of cached values.
+func (t *Transformer) setFrame(raw *telemetry.GranTurismoTelemetry) {
+	t.RawTelemetry = *raw
+	t.generation++
+}
@@ -0,0 +1,201 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/internal/telemetry"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+// sequenceIDOffset is the offset of the sequence ID in a deciphered packet.
+const sequenceIDOffset = 0x70
+
+type CacheTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestCacheTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CacheTestSuite))
+}
+
+func (suite *CacheTestSuite) SetupTest() {
+	inventory, _ := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry = telemetry.GranTurismoTelemetry{}
+	suite.transformer.SetTransmissionGearRatio([]float32{3.1, 2.2, 1.3})
+	suite.transformer.SetTransmissionGear(1, 15)
+}
+
+func (suite *CacheTestSuite) TestDerivedValuesAreCachedWithinDecodedFrame() {
+	// Arrange
+	var wantValue, gotValue float32
+
+	checked := false
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://data/replays/demo.gtz",
+		LogLevel: "error",
+		OnFrame: func(transformer *gttelemetry.Transformer) {
+			if checked || transformer.CurrentGear() != 2 {
+				return
+			}
+
+			checked = true
+			wantValue = transformer.CurrentGearRatio()
+
+			// Act
+			transformer.SetTransmissionGear(3, 15)
+			gotValue = transformer.CurrentGearRatio()
+		},
+	})
+	suite.Require().NoError(err)
+
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	// Assert
+	suite.Require().True(checked, "the demo recording has a frame in second gear")
+	suite.InEpsilon(wantValue, gotValue, 1e-5)
+}
+
+func (suite *CacheTestSuite) TestDerivedValuesAreRecomputedWhenSequenceRepeats() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	replay, err := client.OpenReplay()
+	suite.Require().NoError(err)
+
+	// A frame in second gear followed by a frame in third gear sent with the same sequence ID, as after the console
+	// restarts
+	var second, third []byte
+
+	for frame := range replay.Len() {
+		transformer, err := replay.Seek(frame)
+		suite.Require().NoError(err)
+
+		switch {
+		case second == nil && transformer.CurrentGear() == 2:
+			second = replay.Packet(frame)
+		case third == nil && transformer.CurrentGear() == 3:
+			third = bytes.Clone(replay.Packet(frame))
+		}
+	}
+
+	suite.Require().NotNil(second)
+	suite.Require().NotNil(third)
+	copy(third[sequenceIDOffset:sequenceIDOffset+4], second[sequenceIDOffset:sequenceIDOffset+4])
+
+	recording := filepath.Join(suite.T().TempDir(), "restart.gtr")
+	suite.Require().NoError(os.WriteFile(recording, slices.Concat(second, third), 0o600))
+
+	var ratios []float32
+
+	restarted, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://" + recording,
+		LogLevel: "error",
+		OnFrame: func(transformer *gttelemetry.Transformer) {
+			ratios = append(ratios, transformer.CurrentGearRatio())
+		},
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	for _, err := range restarted.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	// Assert
+	suite.Require().Len(ratios, 2)
+	suite.Less(ratios[1], ratios[0], "the ratio of third gear is computed for the repeated sequence ID")
+}
+
+func (suite *CacheTestSuite) TestDerivedValuesAreRecomputedForNewFrame() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 10
+	_ = suite.transformer.CurrentGearRatio()
+
+	// Act
+	suite.transformer.RawTelemetry.SequenceId = 11
+	suite.transformer.SetTransmissionGear(2, 15)
+	gotValue := suite.transformer.CurrentGearRatio()
+
+	// Assert
+	suite.InEpsilon(float32(2.2), gotValue, 1e-5)
+}
+
+func (suite *CacheTestSuite) TestDerivedValuesAreNotCachedForFramesSetByHand() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 10
+	_ = suite.transformer.CurrentGearRatio()
+
+	// Act
+	suite.transformer.SetTransmissionGear(3, 15)
+	gotValue := suite.transformer.CurrentGearRatio()
+
+	// Assert
+	suite.InEpsilon(float32(1.3), gotValue, 1e-5)
+}
+
+func (suite *CacheTestSuite) TestConcurrentGettersWithinFrame() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 10
+
+	var wg sync.WaitGroup
+
+	results := make([]float32, 8)
+
+	// Act
+	for i := range results {
+		wg.Go(func() {
+			results[i] = suite.transformer.CurrentGearRatio()
+		})
+	}
+
+	wg.Wait()
+
+	// Assert
+	for _, gotValue := range results {
+		suite.InEpsilon(float32(3.1), gotValue, 1e-5)
+	}
+}
+
+func BenchmarkDifferentialRatio(b *testing.B) {
+	// Only frames decoded by the client are cached, so the benchmark reads a clone of the first frame of the demo
+	var transformer *gttelemetry.Transformer
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://data/replays/demo.gtz",
+		LogLevel: "error",
+		OnFrame: func(frame *gttelemetry.Transformer) {
+			if transformer == nil {
+				transformer = frame.Clone()
+			}
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, err := range client.Scan(context.Background()) {
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for b.Loop() {
+		_ = transformer.DifferentialRatio()
+	}
+}
@@ -0,0 +1,91 @@
+package gttelemetry
+
+import (
+	"slices"
+)
+
+type channelFunc func(t *Transformer) float32
+
+// channels maps the name of each numeric telemetry channel to the function used to read it. Channel names are the
+// lower camel case form of the matching Transformer method, with corner sets expanded into one channel per corner.
+var channels = map[string]channelFunc{ //nolint:gochecknoglobals // Static lookup table
+	"absInterventionPercent":             (*Transformer).ABSInterventionPercent,
+	"brakeInputPercent":                  (*Transformer).BrakeInputPercent,
+	"brakeOutputPercent":                 (*Transformer).BrakeOutputPercent,
+	"clutchActuationPercent":             (*Transformer).ClutchActuationPercent,
+	"clutchEngagementPercent":            (*Transformer).ClutchEngagementPercent,
+	"clutchOutputRPM":                    (*Transformer).ClutchOutputRPM,
+	"currentGear":                        func(t *Transformer) float32 { return float32(t.CurrentGear()) },
+	"currentLap":                         func(t *Transformer) float32 { return float32(t.CurrentLap()) },
+	"currentLaptimeSeconds":              func(t *Transformer) float32 { return float32(t.CurrentLaptime().Seconds()) },
+	"bestLaptimeSeconds":                 func(t *Transformer) float32 { return float32(t.BestLaptime().Seconds()) },
+	"lastLaptimeSeconds":                 func(t *Transformer) float32 { return float32(t.LastLaptime().Seconds()) },
+	"energyRecovery":                     (*Transformer).EnergyRecovery,
+	"engineRPM":                          (*Transformer).EngineRPM,
+	"fuelCapacity":                       (*Transformer).FuelCapacity,
+	"fuelFlowLitresPerHour":              func(t *Transformer) float32 { return t.FuelFlow().LitresPerHour },
+	"fuelFlowLitresPerLap":               func(t *Transformer) float32 { return t.FuelFlow().LitresPerLap },
+	"fuelLevel":                          (*Transformer).FuelLevel,
+	"fuelLevelPercent":                   (*Transformer).FuelLevelPercent,
+	"gridPosition":                       func(t *Transformer) float32 { return float32(t.GridPosition()) },
+	"groundSpeedKPH":                     (*Transformer).GroundSpeedKPH,
+	"groundSpeedMetresPerSecond":         (*Transformer).GroundSpeedMetresPerSecond,
+	"heading":                            (*Transformer).Heading,
+	"oilPressureKPA":                     (*Transformer).OilPressureKPA,
+	"oilTemperatureCelsius":              (*Transformer).OilTemperatureCelsius,
+	"positionX":                          func(t *Transformer) float32 { return t.PositionalMapCoordinates().X },
+	"positionY":                          func(t *Transformer) float32 { return t.PositionalMapCoordinates().Y },
+	"positionZ":                          func(t *Transformer) float32 { return t.PositionalMapCoordinates().Z },
+	"raceEntrants":                       func(t *Transformer) float32 { return float32(t.RaceEntrants()) },
+	"raceLaps":                           func(t *Transformer) float32 { return float32(t.RaceLaps()) },
+	"rideHeightMetres":                   (*Transformer).RideHeightMetres,
+	"rotationPitch":                      func(t *Transformer) float32 { return t.RotationEnvelope().Pitch },
+	"rotationRoll":                       func(t *Transformer) float32 { return t.RotationEnvelope().Roll },
+	"rotationYaw":                        func(t *Transformer) float32 { return t.RotationEnvelope().Yaw },
+	"steeringRatioEstimate":              func(t *Transformer) float32 { return t.SteeringRatioEstimate().Ratio },
+	"steeringWheelAngleRadians":          (*Transformer).SteeringWheelAngleRadians,
+	"steeringWheelAngleRadiansPerSecond": (*Transformer).SteeringWheelAngleRadiansPerSecond,
+	"suspensionHeightMetresFrontLeft":    func(t *Transformer) float32 { return t.SuspensionHeightMetres().FrontLeft },
+	"suspensionHeightMetresFrontRight":   func(t *Transformer) float32 { return t.SuspensionHeightMetres().FrontRight },
+	"suspensionHeightMetresRearLeft":     func(t *Transformer) float32 { return t.SuspensionHeightMetres().RearLeft },
+	"suspensionHeightMetresRearRight":    func(t *Transformer) float32 { return t.SuspensionHeightMetres().RearRight },
+	"tcsInterventionPercent":             (*Transformer).TCSInterventionPercent,
+	"throttleInputPercent":               (*Transformer).ThrottleInputPercent,
+	"throttleOutputPercent":              (*Transformer).ThrottleOutputPercent,
+	"timeOfDaySeconds":                   func(t *Transformer) float32 { return float32(t.TimeOfDay().Seconds()) },
+	"turboBoostBar":                      (*Transformer).TurboBoostBar,
+	"tyreSlipRatioFrontLeft":             func(t *Transformer) float32 { return t.TyreSlipRatio().FrontLeft },
+	"tyreSlipRatioFrontRight":            func(t *Transformer) float32 { return t.TyreSlipRatio().FrontRight },
+	"tyreSlipRatioRearLeft":              func(t *Transformer) float32 { return t.TyreSlipRatio().RearLeft },
+	"tyreSlipRatioRearRight":             func(t *Transformer) float32 { return t.TyreSlipRatio().RearRight },
+	"tyreTemperatureCelsiusFrontLeft":    func(t *Transformer) float32 { return t.TyreTemperatureCelsius().FrontLeft },
+	"tyreTemperatureCelsiusFrontRight":   func(t *Transformer) float32 { return t.TyreTemperatureCelsius().FrontRight },
+	"tyreTemperatureCelsiusRearLeft":     func(t *Transformer) float32 { return t.TyreTemperatureCelsius().RearLeft },
+	"tyreTemperatureCelsiusRearRight":    func(t *Transformer) float32 { return t.TyreTemperatureCelsius().RearRight },
+	"velocityX":                          func(t *Transformer) float32 { return t.VelocityVector().X },
+	"velocityY":                          func(t *Transformer) float32 { return t.VelocityVector().Y },
+	"velocityZ":                          func(t *Transformer) float32 { return t.VelocityVector().Z },
+	"waterTemperatureCelsius":            (*Transformer).WaterTemperatureCelsius,
+}
+
+// ChannelNames returns the sorted names of all numeric channels that can be read with Transformer.Channel.
+func ChannelNames() []string {
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Channel returns the current value of the named numeric channel and whether the channel exists.
+func (t *Transformer) Channel(name string) (float32, bool) {
+	fn, ok := channels[name]
+	if !ok {
+		return 0, false
+	}
+
+	return fn(t), true
+}
@@ -0,0 +1,61 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type ChannelsTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestChannelsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ChannelsTestSuite))
+}
+
+func (suite *ChannelsTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB(nil, vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+}
+
+func (suite *ChannelsTestSuite) TestChannelNamesAreSortedAndReadable() {
+	// Act
+	names := gttelemetry.ChannelNames()
+
+	// Assert
+	suite.Require().NotEmpty(names)
+	suite.IsNonDecreasing(names)
+
+	for _, name := range names {
+		_, ok := suite.transformer.Channel(name)
+		suite.True(ok, name)
+	}
+}
+
+func (suite *ChannelsTestSuite) TestChannelReturnsCurrentValue() {
+	// Arrange
+	suite.transformer.RawTelemetry.EngineRpm = 6543
+
+	// Act
+	got, ok := suite.transformer.Channel("engineRPM")
+
+	// Assert
+	suite.True(ok)
+	suite.InDelta(float32(6543), got, 0)
+}
+
+func (suite *ChannelsTestSuite) TestChannelReportsUnknownName() {
+	// Act
+	_, ok := suite.transformer.Channel("doesNotExist")
+
+	// Assert
+	suite.False(ok)
+}
@@ -0,0 +1,19 @@
+"""Prints the speed and RPM sent by a gt daemon, e.g. python example.py http://raspberrypi.local:8080"""
+
+import sys
+
+from gttelemetry import Client
+
+
+def main() -> None:
+    client = Client(sys.argv[1] if len(sys.argv) > 1 else "http://localhost:8080")
+
+    vehicle = client.vehicle()
+    print(f"Driving the {vehicle['manufacturer']} {vehicle['model']}".strip())
+
+    for message in client.stream():
+        print(f"{message.channels.get('groundSpeedKPH', 0):6.1f} km/h {message.channels.get('engineRPM', 0):6.0f} rpm")
+
+
+if __name__ == "__main__":
+    main()
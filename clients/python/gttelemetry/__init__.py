@@ -0,0 +1,5 @@
+"""Python client for the telemetry served by ``gt daemon`` from the gt-telemetry Go library."""
+
+from .client import APIError, Client, Message, Standing
+
+__all__ = ["APIError", "Client", "Message", "Standing"]
@@ -0,0 +1,269 @@
+"""Client for the HTTP and WebSocket API served by ``gt daemon``.
+
+Only the Python standard library is used, so the client can be copied into projects that cannot install packages.
+"""
+
+from __future__ import annotations
+
+import base64
+import hashlib
+import http.client
+import json
+import os
+import socket
+import ssl
+import struct
+from dataclasses import dataclass, field
+from typing import Any, Iterator, Optional
+from urllib.parse import urlsplit
+
+# GUID appended to the WebSocket key to compute the accept key, from RFC 6455.
+_WEBSOCKET_GUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+_OP_CONTINUATION = 0x0
+_OP_TEXT = 0x1
+_OP_BINARY = 0x2
+_OP_CLOSE = 0x8
+_OP_PING = 0x9
+_OP_PONG = 0xA
+
+
+class APIError(Exception):
+    """An error response from the daemon or a failed WebSocket handshake."""
+
+    def __init__(self, status: int, message: str) -> None:
+        super().__init__(f"{status}: {message}")
+        self.status = status
+        self.message = message
+
+
+@dataclass
+class Message:
+    """Channel values sent on the WebSocket feed at every interval, keyed by channel name, e.g. ``engineRPM``."""
+
+    channels: dict[str, float] = field(default_factory=dict)
+
+
+@dataclass
+class Standing:
+    """Position of a driver on the live timing board. Times are in milliseconds."""
+
+    position: int
+    name: str
+    current_lap: int
+    laps_completed: int
+    current_laptime_ms: int
+    last_laptime_ms: int
+    best_laptime_ms: int
+    total_time_ms: int
+    gap_ms: int
+    laps_down: int
+    gap_ahead_ms: int
+    gap_behind_ms: int
+    on_circuit: bool
+
+    @classmethod
+    def from_json(cls, data: dict[str, Any]) -> "Standing":
+        return cls(
+            position=data["position"],
+            name=data["name"],
+            current_lap=data["currentLap"],
+            laps_completed=data["lapsCompleted"],
+            current_laptime_ms=data["currentLaptimeMs"],
+            last_laptime_ms=data["lastLaptimeMs"],
+            best_laptime_ms=data["bestLaptimeMs"],
+            total_time_ms=data["totalTimeMs"],
+            gap_ms=data["gapMs"],
+            laps_down=data["lapsDown"],
+            gap_ahead_ms=data["gapAheadMs"],
+            gap_behind_ms=data["gapBehindMs"],
+            on_circuit=data["onCircuit"],
+        )
+
+
+class Client:
+    """Reads telemetry from a ``gt daemon`` with the ``http`` exporter enabled.
+
+    ``url`` is the address of the HTTP exporter, e.g. ``http://raspberrypi.local:8080`` or ``https://rig.example.com``
+    when TLS is configured. ``token`` is the token of the exporter, when one is set.
+    """
+
+    def __init__(self, url: str, token: Optional[str] = None, timeout: float = 5.0) -> None:
+        parts = urlsplit(url)
+        if parts.scheme not in ("http", "https") or not parts.hostname:
+            raise ValueError(f"invalid daemon URL {url!r}, expected http://host:port or https://host:port")
+
+        self._secure = parts.scheme == "https"
+        self._host = parts.hostname
+        self._port = parts.port or (443 if self._secure else 80)
+        self._base_path = parts.path.rstrip("/")
+        self._token = token
+        self._timeout = timeout
+
+    def ready(self) -> bool:
+        """Reports whether the daemon has received telemetry in the last five seconds."""
+        status, _ = self._request("/readyz")
+        return status == 200
+
+    def standings(self) -> list[Standing]:
+        """Returns the live timing standings."""
+        return [Standing.from_json(standing) for standing in self._get_json("/standings.json")]
+
+    def vehicle(self) -> dict[str, Any]:
+        """Returns the vehicle of the current frame from the vehicle inventory, e.g. ``manufacturer`` and ``model``."""
+        return self._get_json("/vehicle.json")
+
+    def frame(self) -> dict[str, Any]:
+        """Returns the current frame in the versioned snapshot schema, e.g. ``schemaVersion``, ``flags`` and
+        ``channels``."""
+        return self._get_json("/frame.json")
+
+    def stream(self) -> Iterator[Message]:
+        """Yields the channels sent on the WebSocket feed until the daemon closes the connection."""
+        with _WebSocket(self._connect(), self._host_header(), self._path("/ws"), self._token) as ws:
+            for payload in ws.messages():
+                yield Message(channels=json.loads(payload).get("channels", {}))
+
+    def _get_json(self, path: str) -> Any:
+        status, body = self._request(path)
+        if status != 200:
+            raise APIError(status, body.decode(errors="replace").strip())
+
+        return json.loads(body)
+
+    def _request(self, path: str) -> tuple[int, bytes]:
+        if self._secure:
+            conn = http.client.HTTPSConnection(self._host, self._port, timeout=self._timeout)
+        else:
+            conn = http.client.HTTPConnection(self._host, self._port, timeout=self._timeout)
+
+        try:
+            conn.request("GET", self._path(path), headers=self._headers())
+            response = conn.getresponse()
+            return response.status, response.read()
+        finally:
+            conn.close()
+
+    def _connect(self) -> socket.socket:
+        sock = socket.create_connection((self._host, self._port), timeout=self._timeout)
+        if self._secure:
+            sock = ssl.create_default_context().wrap_socket(sock, server_hostname=self._host)
+
+        # Messages arrive continuously while telemetry is received, but the daemon may be idle for a long time
+        sock.settimeout(None)
+        return sock
+
+    def _path(self, path: str) -> str:
+        return self._base_path + path
+
+    def _host_header(self) -> str:
+        return f"{self._host}:{self._port}"
+
+    def _headers(self) -> dict[str, str]:
+        if self._token:
+            return {"Authorization": f"Bearer {self._token}"}
+
+        return {}
+
+
+class _WebSocket:
+    """The parts of an RFC 6455 client needed to receive the messages of the daemon."""
+
+    def __init__(self, sock: socket.socket, host: str, path: str, token: Optional[str] = None) -> None:
+        self._sock = sock
+        self._reader = sock.makefile("rb")
+        self._handshake(host, path, token)
+
+    def __enter__(self) -> "_WebSocket":
+        return self
+
+    def __exit__(self, *_: object) -> None:
+        try:
+            self._send(_OP_CLOSE, b"")
+        except OSError:
+            pass
+
+        self._reader.close()
+        self._sock.close()
+
+    def messages(self) -> Iterator[bytes]:
+        """Yields the payload of every text or binary message, answering pings, until the connection is closed."""
+        fragments: list[bytes] = []
+
+        while True:
+            fin, opcode, payload = self._read_frame()
+
+            if opcode == _OP_CLOSE:
+                return
+            if opcode == _OP_PING:
+                self._send(_OP_PONG, payload)
+                continue
+            if opcode not in (_OP_TEXT, _OP_BINARY, _OP_CONTINUATION):
+                continue
+
+            fragments.append(payload)
+            if fin:
+                yield b"".join(fragments)
+                fragments = []
+
+    def _handshake(self, host: str, path: str, token: Optional[str]) -> None:
+        key = base64.b64encode(os.urandom(16)).decode()
+        authorization = f"Authorization: Bearer {token}\r\n" if token else ""
+
+        request = (
+            f"GET {path} HTTP/1.1\r\n"
+            f"Host: {host}\r\n"
+            "Upgrade: websocket\r\n"
+            "Connection: Upgrade\r\n"
+            f"Sec-WebSocket-Key: {key}\r\n"
+            "Sec-WebSocket-Version: 13\r\n"
+            f"{authorization}"
+            "\r\n"
+        )
+        self._sock.sendall(request.encode())
+
+        status_line = self._reader.readline().decode(errors="replace").strip()
+        headers: dict[str, str] = {}
+        while True:
+            line = self._reader.readline().decode(errors="replace").strip()
+            if not line:
+                break
+
+            name, _, value = line.partition(":")
+            headers[name.strip().lower()] = value.strip()
+
+        parts = status_line.split(" ", 2)
+        status = int(parts[1]) if len(parts) > 1 and parts[1].isdigit() else 0
+        if status != 101:
+            raise APIError(status, parts[2] if len(parts) > 2 else status_line)
+
+        expected = base64.b64encode(hashlib.sha1((key + _WEBSOCKET_GUID).encode()).digest()).decode()
+        if headers.get("sec-websocket-accept") != expected:
+            raise APIError(status, "invalid Sec-WebSocket-Accept header")
+
+    def _read_frame(self) -> tuple[bool, int, bytes]:
+        header = self._read_exactly(2)
+        fin = bool(header[0] & 0x80)
+        opcode = header[0] & 0x0F
+        length = header[1] & 0x7F
+
+        if length == 126:
+            (length,) = struct.unpack("!H", self._read_exactly(2))
+        elif length == 127:
+            (length,) = struct.unpack("!Q", self._read_exactly(8))
+
+        # Frames from servers are never masked
+        return fin, opcode, self._read_exactly(length)
+
+    def _read_exactly(self, length: int) -> bytes:
+        data = self._reader.read(length)
+        if data is None or len(data) < length:
+            raise ConnectionError("WebSocket connection closed")
+
+        return data
+
+    def _send(self, opcode: int, payload: bytes) -> None:
+        # Frames from clients must be masked, and control frames hold at most 125 bytes
+        mask = os.urandom(4)
+        masked = bytes(b ^ mask[i % 4] for i, b in enumerate(payload[:125]))
+        self._sock.sendall(bytes([0x80 | opcode, 0x80 | len(masked)]) + mask + masked)
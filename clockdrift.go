@@ -0,0 +1,268 @@
+package gttelemetry
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// clockDriftMinBaseline is the time a clock is measured over before its drift corrects the session clock, long
+	// enough that the jitter of receive times is far smaller than the drift measured.
+	clockDriftMinBaseline = time.Minute
+	// clockDriftMaxPPM is the largest plausible drift between clocks, larger drifts come from a clock that stopped or
+	// jumped, such as a host that slept, and are ignored.
+	clockDriftMaxPPM = 10_000
+	// clockDriftMaxReceiveDelay is how much longer than the frames between them two frames may take to arrive before
+	// the time between them is left out of the measurement as a gap in reception, such as a reconnect or a stalled
+	// network. Shorter delays are jitter, which the baseline averages out.
+	clockDriftMaxReceiveDelay = time.Second
+)
+
+// ClockReference is the clock the session clock is corrected against.
+type ClockReference string
+
+const (
+	// ClockReferenceHost is the host clock, read from the receive times of frames.
+	ClockReferenceHost ClockReference = "host"
+	// ClockReferenceConsole is the in-game time of day with time progression removed, used for recordings without
+	// receive times.
+	ClockReferenceConsole ClockReference = "console"
+)
+
+// ClockDrift is the estimated drift between the clocks of the console and the host.
+//
+// Times derived from frames, such as lap traces, timeline events and video exports, count frames at the nominal
+// FrameRate. The console does not send at exactly that rate, so over a long session the frame clock slowly drifts
+// from real time. The rate frames are sent at is measured against the host clock, or against the in-game time of
+// day when frames carry no receive times, and corrects the session clock once it has been measured for a minute.
+type ClockDrift struct {
+	// Reference is the clock the frame rate is measured against, empty until it has been measured for long enough.
+	Reference ClockReference `json:"reference,omitempty"`
+	// Baseline is the time of the reference clock the frame rate is measured over.
+	Baseline time.Duration `json:"baseline"`
+	// FrameRate is the measured rate frames are sent at in packets per second, or FrameRate until measured.
+	FrameRate float64 `json:"frameRate"`
+	// FramePPM is how far the frame clock runs ahead of the reference clock in parts per million, negative when slow.
+	FramePPM float64 `json:"framePpm"`
+	// ConsolePPM is how far the in-game time of day, with time progression removed, runs ahead of the host clock in
+	// parts per million, zero until both clocks have been measured for long enough.
+	ConsolePPM float64 `json:"consolePpm"`
+	// TimeProgression is the multiple of real time the in-game time of day runs at, zero until measured.
+	TimeProgression int `json:"timeProgression"`
+}
+
+// clockState guards the measurement of the clock of a transformer, which is read from other goroutines.
+type clockState struct {
+	mu sync.Mutex
+	clockMeasurement
+}
+
+// clone returns a copy of the measurement.
+func (c *clockState) clone() *clockState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return &clockState{clockMeasurement: c.clockMeasurement}
+}
+
+// clockMeasurement measures the frame clock against the host clock and the in-game time of day.
+type clockMeasurement struct {
+	lastSequence uint32
+	// startSequence is the first frame of the sequence, which the session time is measured from
+	startSequence uint32
+
+	// lastReceivedAt is the receive time of lastHostSequence, the last frame with a receive time. hostElapsed is the
+	// host time measured since the first frame with a receive time and hostFrames the frames sent over it, both
+	// leaving out gaps in reception
+	lastReceivedAt   time.Time
+	lastHostSequence uint32
+	hostElapsed      time.Duration
+	hostFrames       time.Duration
+
+	// consoleStarted is set while the in-game clock runs, it stops while paused and off the circuit
+	consoleStarted   bool
+	consoleSequence  uint32
+	consoleHostStart time.Time
+	consoleHost      time.Duration
+	lastTimeOfDay    time.Duration
+	gameElapsed      time.Duration
+
+	drift ClockDrift
+	scale float64
+}
+
+// UpdateClock measures the frame clock with the current frame. The client calls this once for each decoded frame,
+// repeated frames are ignored and the measurement restarts when the sequence restarts.
+func (t *Transformer) UpdateClock() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	clock := t.clock
+	sequenceID := t.SequenceID()
+
+	if sequenceID == clock.lastSequence {
+		return
+	}
+
+	if clock.lastSequence == 0 || sequenceID < clock.lastSequence {
+		clock.clockMeasurement = clockMeasurement{startSequence: sequenceID}
+	}
+
+	clock.lastSequence = sequenceID
+	receivedAt := t.ReceivedAt()
+
+	if !receivedAt.IsZero() {
+		clock.updateHost(sequenceID, receivedAt)
+	}
+
+	clock.updateConsole(t, sequenceID, receivedAt)
+	clock.estimate()
+}
+
+// updateHost advances the host time measured with the receive time of the frame. Stretches where frames were not
+// received, such as while the stream reconnected or the network stalled, are left out rather than read as drift, and
+// restart the measurement of the in-game time of day against the host clock.
+func (c *clockMeasurement) updateHost(sequenceID uint32, receivedAt time.Time) {
+	if !c.lastReceivedAt.IsZero() {
+		frames := framesElapsed(c.lastHostSequence, sequenceID)
+		received := receivedAt.Sub(c.lastReceivedAt)
+
+		if received >= 0 && received-frames <= clockDriftMaxReceiveDelay {
+			c.hostFrames += frames
+			c.hostElapsed += received
+		} else {
+			c.consoleStarted = false
+		}
+	}
+
+	c.lastReceivedAt = receivedAt
+	c.lastHostSequence = sequenceID
+}
+
+// updateConsole advances the in-game time of day measured since the clock last started.
+func (c *clockMeasurement) updateConsole(t *Transformer, sequenceID uint32, receivedAt time.Time) {
+	if !t.IsOnCircuit() || t.Flags().GamePaused {
+		c.consoleStarted = false
+
+		return
+	}
+
+	timeOfDay := t.TimeOfDay()
+
+	if !c.consoleStarted {
+		c.consoleStarted = true
+		c.consoleSequence = sequenceID
+		c.consoleHostStart = receivedAt
+		c.consoleHost = 0
+		c.lastTimeOfDay = timeOfDay
+		c.gameElapsed = 0
+
+		return
+	}
+
+	delta := timeOfDay - c.lastTimeOfDay
+	if delta < 0 {
+		// The clock wrapped at midnight
+		delta += dayLength
+	}
+
+	c.gameElapsed += delta
+	c.lastTimeOfDay = timeOfDay
+
+	if !receivedAt.IsZero() && !c.consoleHostStart.IsZero() {
+		c.consoleHost = receivedAt.Sub(c.consoleHostStart)
+	}
+}
+
+// estimate updates the drift and the scale of the session clock from the clocks measured so far.
+func (c *clockMeasurement) estimate() {
+	c.drift = ClockDrift{FrameRate: FrameRate}
+	c.scale = 1
+
+	// Time progression is a whole multiple of real time, found against the host clock when frames carry receive times
+	consoleFrames := framesElapsed(c.consoleSequence, c.lastSequence)
+	consoleReal := consoleFrames
+	if c.consoleHost > 0 {
+		consoleReal = c.consoleHost
+	}
+
+	consoleElapsed := time.Duration(0)
+
+	if c.consoleStarted && consoleReal > 0 {
+		progression := int(math.Round(float64(c.gameElapsed) / float64(consoleReal)))
+		if progression >= 1 {
+			c.drift.TimeProgression = progression
+			consoleElapsed = c.gameElapsed / time.Duration(progression)
+		}
+	}
+
+	if consoleElapsed > 0 && c.consoleHost >= clockDriftMinBaseline {
+		ppm := driftPPM(consoleElapsed, c.consoleHost)
+		if math.Abs(ppm) <= clockDriftMaxPPM {
+			c.drift.ConsolePPM = ppm
+		}
+	}
+
+	switch {
+	case c.hostElapsed >= clockDriftMinBaseline:
+		c.applyReference(ClockReferenceHost, c.hostFrames, c.hostElapsed)
+	case c.lastReceivedAt.IsZero() && consoleElapsed >= clockDriftMinBaseline:
+		c.applyReference(ClockReferenceConsole, consoleFrames, consoleElapsed)
+	}
+}
+
+// applyReference corrects the session clock by the drift of the frame clock from the reference clock, unless the
+// drift is implausible.
+func (c *clockMeasurement) applyReference(reference ClockReference, frames, elapsed time.Duration) {
+	ppm := driftPPM(frames, elapsed)
+	if math.Abs(ppm) > clockDriftMaxPPM {
+		return
+	}
+
+	c.drift.Reference = reference
+	c.drift.Baseline = elapsed
+	c.drift.FramePPM = ppm
+	c.drift.FrameRate = FrameRate * float64(frames) / float64(elapsed)
+	c.scale = float64(elapsed) / float64(frames)
+}
+
+// driftPPM returns how far the measured time runs ahead of the reference time in parts per million.
+func driftPPM(measured, reference time.Duration) float64 {
+	return (float64(measured)/float64(reference) - 1) * 1e6
+}
+
+// ClockDrift returns the drift of the console clocks measured so far.
+func (t *Transformer) ClockDrift() ClockDrift {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	if t.clock.scale == 0 {
+		return ClockDrift{FrameRate: FrameRate}
+	}
+
+	return t.clock.drift
+}
+
+// CorrectedDuration returns a duration counted in frames at the nominal FrameRate corrected for the measured drift
+// of the frame clock, unchanged until the drift has been measured for long enough.
+func (t *Transformer) CorrectedDuration(nominal time.Duration) time.Duration {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	if t.clock.scale == 0 {
+		return nominal
+	}
+
+	return time.Duration(math.Round(float64(nominal) * t.clock.scale))
+}
+
+// SessionTime returns the time since the first frame of the sequence, corrected for the measured drift of the frame
+// clock. It is zero until the client has measured the clock, such as for transformers updated outside a client.
+func (t *Transformer) SessionTime() time.Duration {
+	t.clock.mu.Lock()
+	start, last := t.clock.startSequence, t.clock.lastSequence
+	t.clock.mu.Unlock()
+
+	return t.CorrectedDuration(framesElapsed(start, last))
+}
@@ -0,0 +1,180 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type ClockDriftTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestClockDriftTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClockDriftTestSuite))
+}
+
+func (suite *ClockDriftTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.RaceLaps = 0
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+	suite.transformer.RawTelemetry.SequenceId = 1000
+}
+
+// session is a simulated stream of frames, sent at frameRate frames per second of real time.
+type session struct {
+	frameRate float64
+	// progression is the multiple of real time the in-game time of day runs at
+	progression float64
+	// consoleRate is the rate the console clock runs at relative to real time
+	consoleRate float64
+	// withReceiveTimes tags the frames with the host time they were received at
+	withReceiveTimes bool
+	// receivedFrom is the host time the first frame is received at, after the start of the session
+	receivedFrom time.Duration
+}
+
+// run sends the frames of the session for the real duration.
+func (suite *ClockDriftTestSuite) run(s session, duration time.Duration) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	timeOfDay := 10 * time.Hour
+	frames := int(duration.Seconds() * s.frameRate)
+
+	for frame := range frames {
+		elapsed := time.Duration(float64(frame) / s.frameRate * float64(time.Second))
+		game := timeOfDay + time.Duration(float64(elapsed)*s.progression*s.consoleRate)
+
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.transformer.RawTelemetry.TimeOfDay = uint32(game.Milliseconds()) //nolint:gosec // test values fit
+
+		if s.withReceiveTimes {
+			suite.transformer.SetReceivedAt(start.Add(s.receivedFrom + elapsed))
+		}
+
+		suite.transformer.UpdateClock()
+	}
+}
+
+func (suite *ClockDriftTestSuite) TestFrameRateIsMeasuredAgainstHostClock() {
+	// Arrange
+	s := session{frameRate: 59.94, progression: 1, consoleRate: 1, withReceiveTimes: true}
+
+	// Act
+	suite.run(s, 5*time.Minute)
+	drift := suite.transformer.ClockDrift()
+
+	// Assert
+	suite.Equal(gttelemetry.ClockReferenceHost, drift.Reference)
+	suite.InDelta(59.94, drift.FrameRate, 0.001)
+	suite.InDelta(-1000, drift.FramePPM, 5)
+	suite.InDelta(0, drift.ConsolePPM, 5)
+	suite.Equal(1, drift.TimeProgression)
+	suite.InDelta(float64(time.Hour+3603600*time.Microsecond), float64(suite.transformer.CorrectedDuration(time.Hour)),
+		float64(20*time.Millisecond))
+	suite.InDelta(float64(5*time.Minute), float64(suite.transformer.SessionTime()), float64(20*time.Millisecond))
+}
+
+func (suite *ClockDriftTestSuite) TestConsoleDriftIsMeasuredWithTimeProgression() {
+	// Arrange — the console clock gains half a second an hour, with time running six times faster in the game
+	s := session{frameRate: 60, progression: 6, consoleRate: 1.000139, withReceiveTimes: true}
+
+	// Act
+	suite.run(s, 5*time.Minute)
+	drift := suite.transformer.ClockDrift()
+
+	// Assert
+	suite.Equal(6, drift.TimeProgression)
+	suite.InDelta(139, drift.ConsolePPM, 5)
+	suite.InDelta(0, drift.FramePPM, 5)
+}
+
+func (suite *ClockDriftTestSuite) TestConsoleClockIsTheReferenceWithoutReceiveTimes() {
+	// Arrange
+	s := session{frameRate: 59.94, progression: 2, consoleRate: 1}
+
+	// Act
+	suite.run(s, 5*time.Minute)
+	drift := suite.transformer.ClockDrift()
+
+	// Assert
+	suite.Equal(gttelemetry.ClockReferenceConsole, drift.Reference)
+	suite.Equal(2, drift.TimeProgression)
+	suite.InDelta(59.94, drift.FrameRate, 0.001)
+	suite.Zero(drift.ConsolePPM)
+}
+
+func (suite *ClockDriftTestSuite) TestNoCorrectionUntilMeasuredForLongEnough() {
+	// Arrange
+	s := session{frameRate: 59.94, progression: 1, consoleRate: 1, withReceiveTimes: true}
+
+	// Act
+	suite.run(s, 30*time.Second)
+	drift := suite.transformer.ClockDrift()
+
+	// Assert
+	suite.Empty(drift.Reference)
+	suite.Equal(float64(gttelemetry.FrameRate), drift.FrameRate)
+	suite.Equal(time.Hour, suite.transformer.CorrectedDuration(time.Hour))
+}
+
+func (suite *ClockDriftTestSuite) TestImplausibleDriftIsIgnored() {
+	// Arrange — receive times twice as far apart as the frames count, such as from a host that slept
+	s := session{frameRate: 30, progression: 1, consoleRate: 1, withReceiveTimes: true}
+
+	// Act
+	suite.run(s, 5*time.Minute)
+
+	// Assert
+	suite.Empty(suite.transformer.ClockDrift().Reference)
+	suite.Equal(time.Hour, suite.transformer.CorrectedDuration(time.Hour))
+}
+
+func (suite *ClockDriftTestSuite) TestGapsInReceptionAreLeftOut() {
+	// Arrange
+	s := session{frameRate: 60, progression: 1, consoleRate: 1, withReceiveTimes: true}
+	suite.run(s, 3*time.Minute)
+
+	// Act — the stream stalls for five seconds, then carries on from the next frame
+	s.receivedFrom = 3*time.Minute + 5*time.Second
+	suite.run(s, 3*time.Minute)
+	drift := suite.transformer.ClockDrift()
+
+	// Assert
+	suite.Equal(gttelemetry.ClockReferenceHost, drift.Reference)
+	suite.InDelta(0, drift.FramePPM, 5)
+	suite.InDelta(0, drift.ConsolePPM, 5)
+	suite.InDelta(float64(6*time.Minute), float64(suite.transformer.SessionTime()), float64(20*time.Millisecond))
+}
+
+func (suite *ClockDriftTestSuite) TestMeasurementRestartsWithSequence() {
+	// Arrange
+	s := session{frameRate: 59.94, progression: 1, consoleRate: 1, withReceiveTimes: true}
+	suite.run(s, 5*time.Minute)
+
+	// Act
+	suite.transformer.RawTelemetry.SequenceId = 0
+	suite.run(s, 10*time.Second)
+
+	// Assert
+	suite.Empty(suite.transformer.ClockDrift().Reference)
+	suite.InDelta(float64(10*time.Second), float64(suite.transformer.SessionTime()), float64(50*time.Millisecond))
+}
+
+func (suite *ClockDriftTestSuite) TestUncorrectedOutsideClient() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 5000
+
+	// Act & Assert
+	suite.Equal(gttelemetry.ClockDrift{FrameRate: gttelemetry.FrameRate}, suite.transformer.ClockDrift())
+	suite.Equal(time.Minute, suite.transformer.CorrectedDuration(time.Minute))
+	suite.Zero(suite.transformer.SessionTime())
+}
@@ -15,28 +15,38 @@ import (
 
 func main() {
 	var (
-		outFile    string
-		lapCapture bool
+		outFile     string
+		lapCapture  bool
+		splitLaps   bool
+		maxDuration time.Duration
 	)
 
 	flag.StringVar(&outFile, "o", "gt7-replay.gtz", "Output file name. Default: gt7-replay.gtz")
 	flag.BoolVar(&lapCapture, "lap", false, "Capture a single lap from live telemetry, starting and stopping at the start/finish line")
+	flag.BoolVar(&splitLaps, "split-laps", false, "Write each lap of a replay capture to its own file, with a manifest listing the lap files")
+	flag.DurationVar(&maxDuration, "max-duration", 0, "Stop recording after this duration, e.g. 90m. Default: no limit")
 	flag.Parse()
 
 	validateFileExtension(outFile)
 
-	client := createTelemetryClient()
+	var splitter *lapSplitter
+	if splitLaps && !lapCapture {
+		splitter = newLapSplitter(outFile)
+	}
+
+	client := createTelemetryClient(splitter)
 
 	startTelemetryClient(client)
 
 	sigChan := setupSignalHandling()
+	status := newCaptureStatus(maxDuration)
 
 	if lapCapture {
 		fmt.Println("Waiting for start/finish line crossing...")
-		captureLapLoop(client, outFile, sigChan)
+		captureLapLoop(client, outFile, status, sigChan)
 	} else {
 		fmt.Println("Waiting for replay to start...")
-		captureReplayLoop(client, outFile, sigChan)
+		captureReplayLoop(client, outFile, splitter, status, sigChan)
 	}
 }
 
@@ -55,12 +65,18 @@ func validateFileExtension(outFile string) {
 	}
 }
 
-func createTelemetryClient() *gttelemetry.Client {
-	client, err := gttelemetry.New(
-		gttelemetry.Options{
-			LogLevel: "info",
-		},
-	)
+// createTelemetryClient creates the client, splitting the recording on the decoding goroutine when splitting laps.
+func createTelemetryClient(splitter *lapSplitter) *gttelemetry.Client {
+	opts := gttelemetry.Options{
+		LogLevel:     "error",
+		StatsEnabled: true,
+	}
+
+	if splitter != nil {
+		opts.OnFrame = splitter.observe
+	}
+
+	client, err := gttelemetry.New(opts)
 	if err != nil {
 		log.Fatalf("Error creating GT client: %v", err)
 	}
@@ -87,7 +103,7 @@ func startTelemetryClient(client *gttelemetry.Client) {
 	}()
 }
 
-func captureReplayLoop(client *gttelemetry.Client, outFile string, sigChan chan os.Signal) {
+func captureReplayLoop(client *gttelemetry.Client, outFile string, splitter *lapSplitter, status *captureStatus, sigChan chan os.Signal) {
 	framesCaptured := 0
 	lastTimeOfDay := time.Duration(0)
 	sequenceID := ^uint32(0)
@@ -97,7 +113,8 @@ func captureReplayLoop(client *gttelemetry.Client, outFile string, sigChan chan
 	for {
 		select {
 		case <-sigChan:
-			handleInterrupt(client)
+			fmt.Println("\nInterrupt received, stopping recording...")
+			finishCapture(client, splitter)
 
 			return
 		default:
@@ -115,26 +132,50 @@ func captureReplayLoop(client *gttelemetry.Client, outFile string, sigChan chan
 				continue
 			}
 
-			if shouldStopRecording(recordingStarted, client, startTime, framesCaptured) {
-				handleReplayRestart(client, framesCaptured)
+			recording := client.IsRecording()
+			if splitter != nil {
+				recording = splitter.isRecording()
+			}
+
+			if shouldStopRecording(recordingStarted, recording, client, startTime, framesCaptured) {
+				handleReplayRestart(client, splitter, recording, framesCaptured)
 
 				return
 			}
 
 			if shouldStartRecording(recordingStarted, client, lastTimeOfDay) {
-				startRecording(client, outFile)
+				if splitter != nil {
+					splitter.start(client)
+				} else {
+					startRecording(client, outFile)
+				}
 
 				startTime = client.Telemetry.TimeOfDay()
 				recordingStarted = true
 
 				printSessionInfo(client, outFile)
+				status.start()
 			}
 
 			if recordingStarted {
 				framesCaptured++
 				lastTimeOfDay = client.Telemetry.TimeOfDay()
 
-				printFrameCount(framesCaptured)
+				currentFile := outFile
+
+				if splitter != nil {
+					currentFile = splitter.file()
+				}
+
+				if status.expired() {
+					fmt.Println("\nMaximum duration reached, stopping recording...")
+					finishCapture(client, splitter)
+					fmt.Printf("Capture complete, total frames: %d\n", framesCaptured)
+
+					return
+				}
+
+				status.update(client, framesCaptured, currentFile)
 			}
 
 			time.Sleep(4 * time.Millisecond)
@@ -150,26 +191,28 @@ func isFirstFrame(lastTimeOfDay time.Duration) bool {
 	return lastTimeOfDay == time.Duration(0)
 }
 
-func shouldStopRecording(recordingStarted bool, client *gttelemetry.Client, startTime time.Duration, framesCaptured int) bool {
+func shouldStopRecording(
+	recordingStarted, recording bool, client *gttelemetry.Client, startTime time.Duration, framesCaptured int,
+) bool {
 	if !recordingStarted {
 		return false
 	}
 
-	if !client.IsRecording() {
+	if !recording {
 		return true
 	}
 
 	return client.Telemetry.TimeOfDay() <= startTime && framesCaptured >= 60
 }
 
-func handleReplayRestart(client *gttelemetry.Client, framesCaptured int) {
-	if client.IsRecording() {
-		fmt.Println("Replay restart detected, stopping recording...")
+func handleReplayRestart(client *gttelemetry.Client, splitter *lapSplitter, recording bool, framesCaptured int) {
+	if recording {
+		fmt.Println("\nReplay restart detected, stopping recording...")
 	} else {
-		fmt.Println("Recording stopped externally...")
+		fmt.Println("\nRecording stopped externally...")
 	}
 
-	stopRecordingIfNeeded(client)
+	finishCapture(client, splitter)
 	fmt.Printf("Capture complete, total frames: %d\n", framesCaptured)
 }
 
@@ -177,17 +220,24 @@ func shouldStartRecording(recordingStarted bool, client *gttelemetry.Client, las
 	return !recordingStarted && client.Telemetry.TimeOfDay() != lastTimeOfDay
 }
 
-func printFrameCount(framesCaptured int) {
-	if framesCaptured%300 == 0 {
-		fmt.Printf("%d frames captured\n", framesCaptured)
-	}
-}
-
 func handleInterrupt(client *gttelemetry.Client) {
 	fmt.Println("\nInterrupt received, stopping recording...")
 	stopRecordingIfNeeded(client)
 }
 
+// finishCapture stops the recording, writing the manifest when splitting laps.
+func finishCapture(client *gttelemetry.Client, splitter *lapSplitter) {
+	if splitter != nil {
+		if splitter.finish() {
+			fmt.Printf("Manifest written to %s\n", splitter.manifestPath())
+		}
+
+		return
+	}
+
+	stopRecordingIfNeeded(client)
+}
+
 func stopRecordingIfNeeded(client *gttelemetry.Client) {
 	if client.IsRecording() {
 		err := client.StopRecording()
@@ -229,7 +279,7 @@ func updateLapStabilisation(currentLap int16, startLap *int16, stableFrames *int
 	}
 }
 
-func handleActiveLapRecording(client *gttelemetry.Client, currentLap, startLap int16, framesCaptured *int) bool {
+func handleActiveLapRecording(client *gttelemetry.Client, outFile string, status *captureStatus, currentLap, startLap int16, framesCaptured *int) bool {
 	if !client.IsRecording() {
 		fmt.Printf("\nRecording stopped, exiting...\n")
 		fmt.Printf("Capture complete, total frames: %d\n", *framesCaptured)
@@ -245,13 +295,21 @@ func handleActiveLapRecording(client *gttelemetry.Client, currentLap, startLap i
 		return true
 	}
 
+	if status.expired() {
+		fmt.Printf("\nMaximum duration reached, stopping recording...\n")
+		stopRecordingIfNeeded(client)
+		fmt.Printf("Capture complete, total frames: %d\n", *framesCaptured)
+
+		return true
+	}
+
 	(*framesCaptured)++
-	printFrameCount(*framesCaptured)
+	status.update(client, *framesCaptured, outFile)
 
 	return false
 }
 
-func captureLapLoop(client *gttelemetry.Client, outFile string, sigChan chan os.Signal) {
+func captureLapLoop(client *gttelemetry.Client, outFile string, status *captureStatus, sigChan chan os.Signal) {
 	framesCaptured := 0
 	sequenceID := ^uint32(0)
 	startLap := int16(-1)
@@ -299,6 +357,7 @@ func captureLapLoop(client *gttelemetry.Client, outFile string, sigChan chan os.
 
 					printSessionInfo(client, outFile)
 					fmt.Printf("Lap %d started, recording...\n", currentLap)
+					status.start()
 				}
 
 				time.Sleep(4 * time.Millisecond)
@@ -306,7 +365,7 @@ func captureLapLoop(client *gttelemetry.Client, outFile string, sigChan chan os.
 				continue
 			}
 
-			if handleActiveLapRecording(client, currentLap, startLap, &framesCaptured) {
+			if handleActiveLapRecording(client, outFile, status, currentLap, startLap, &framesCaptured) {
 				return
 			}
 
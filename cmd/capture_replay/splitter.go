@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+// lapEntry describes a single lap file in the manifest.
+type lapEntry struct {
+	Lap        int16  `json:"lap"`
+	File       string `json:"file"`
+	LaptimeMs  int64  `json:"laptimeMs,omitempty"`
+	Frames     int    `json:"frames"`
+	Complete   bool   `json:"complete"`
+	StartedAt  string `json:"startedAt"`
+	FinishedAt string `json:"finishedAt"`
+}
+
+// manifest describes the lap files written for a capture.
+type manifest struct {
+	Vehicle string     `json:"vehicle"`
+	Created string     `json:"created"`
+	Laps    []lapEntry `json:"laps"`
+}
+
+// lapSplitter writes each lap of a capture to its own file and records the files in a manifest. The lap files are
+// split by observe on the goroutine decoding the stream, so the frame the lap changes on starts the new file.
+type lapSplitter struct {
+	mu        sync.Mutex
+	client    *gttelemetry.Client
+	base      string
+	ext       string
+	manifest  manifest
+	lap       int16
+	lapFile   string
+	frames    int
+	startedAt time.Time
+}
+
+func newLapSplitter(outFile string) *lapSplitter {
+	ext := filepath.Ext(outFile)
+
+	return &lapSplitter{
+		base: strings.TrimSuffix(outFile, ext),
+		ext:  ext,
+	}
+}
+
+func (s *lapSplitter) manifestPath() string {
+	return s.base + "-manifest.json"
+}
+
+// start begins recording the current lap.
+func (s *lapSplitter) start(client *gttelemetry.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.client = client
+	s.manifest = manifest{
+		Vehicle: strings.TrimSpace(client.Telemetry.VehicleManufacturer() + " " + client.Telemetry.VehicleModel()),
+		Created: time.Now().Format(time.RFC3339),
+	}
+
+	s.startLap(client.Telemetry.CurrentLap())
+}
+
+func (s *lapSplitter) startLap(lap int16) {
+	s.lap = lap
+	s.lapFile = fmt.Sprintf("%s-lap%02d%s", s.base, lap, s.ext)
+	s.frames = 0
+	s.startedAt = time.Now()
+
+	startRecording(s.client, s.lapFile)
+}
+
+// observe counts the frame and moves on to a new file when the lap changes. It is called from
+// gttelemetry.Options.OnFrame, before the client records the frame.
+func (s *lapSplitter) observe(transformer *gttelemetry.Transformer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lapFile == "" {
+		return
+	}
+
+	currentLap := transformer.CurrentLap()
+	if currentLap == s.lap {
+		s.frames++
+
+		return
+	}
+
+	// The game reports a lap time once a lap is completed at the start/finish line
+	laptime := transformer.LastLaptime()
+	s.finishLap(laptime > 0, laptime)
+
+	fmt.Printf("Lap %d started, recording to new file...\n", currentLap)
+	s.startLap(currentLap)
+	s.frames = 1
+}
+
+// file returns the file the current lap is recorded to.
+func (s *lapSplitter) file() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lapFile
+}
+
+// isRecording reports whether the client is recording, without catching it between two lap files.
+func (s *lapSplitter) isRecording() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.client != nil && s.client.IsRecording()
+}
+
+// finish stops recording the final, incomplete lap and writes the manifest. It returns false if no lap was recorded.
+func (s *lapSplitter) finish() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lapFile == "" {
+		return false
+	}
+
+	s.finishLap(false, 0)
+	s.lapFile = ""
+
+	return true
+}
+
+func (s *lapSplitter) finishLap(complete bool, laptime time.Duration) {
+	stopRecordingIfNeeded(s.client)
+
+	file := s.lapFile
+
+	if complete {
+		file = fmt.Sprintf("%s-lap%02d-%s%s", s.base, s.lap, formatLaptime(laptime), s.ext)
+
+		err := os.Rename(s.lapFile, file)
+		if err != nil {
+			fmt.Printf("Failed to rename lap file: %v\n", err)
+
+			file = s.lapFile
+		}
+	} else {
+		laptime = 0
+	}
+
+	s.manifest.Laps = append(s.manifest.Laps, lapEntry{
+		Lap:        s.lap,
+		File:       filepath.Base(file),
+		LaptimeMs:  laptime.Milliseconds(),
+		Frames:     s.frames,
+		Complete:   complete,
+		StartedAt:  s.startedAt.Format(time.RFC3339),
+		FinishedAt: time.Now().Format(time.RFC3339),
+	})
+
+	fmt.Printf("\nLap %d saved to %s\n", s.lap, file)
+
+	err := s.writeManifest()
+	if err != nil {
+		fmt.Printf("Failed to write manifest: %v\n", err)
+	}
+}
+
+func (s *lapSplitter) writeManifest() error {
+	data, err := json.MarshalIndent(s.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	err = os.WriteFile(s.manifestPath(), data, 0o600)
+	if err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// formatLaptime formats a lap time for use in a file name, e.g. 1m23.456s.
+func formatLaptime(laptime time.Duration) string {
+	minutes := laptime / time.Minute
+	seconds := (laptime % time.Minute) / time.Second
+	millis := (laptime % time.Second) / time.Millisecond
+
+	return fmt.Sprintf("%dm%02d.%03ds", minutes, seconds, millis)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+const statusInterval = 250 * time.Millisecond
+
+// captureStatus prints a single, regularly refreshed status line while recording.
+type captureStatus struct {
+	maxDuration time.Duration
+	started     time.Time
+	lastPrinted time.Time
+}
+
+func newCaptureStatus(maxDuration time.Duration) *captureStatus {
+	return &captureStatus{
+		maxDuration: maxDuration,
+	}
+}
+
+// start marks the start of the recording.
+func (s *captureStatus) start() {
+	s.started = time.Now()
+}
+
+// expired reports whether the recording has reached the maximum duration.
+func (s *captureStatus) expired() bool {
+	return s.maxDuration > 0 && !s.started.IsZero() && time.Since(s.started) >= s.maxDuration
+}
+
+// update refreshes the status line, at most once per status interval.
+func (s *captureStatus) update(client *gttelemetry.Client, framesCaptured int, outFile string) {
+	if time.Since(s.lastPrinted) < statusInterval {
+		return
+	}
+
+	s.lastPrinted = time.Now()
+
+	elapsed := time.Since(s.started).Truncate(time.Second)
+
+	var size int64
+
+	info, err := os.Stat(outFile)
+	if err == nil {
+		size = info.Size()
+	}
+
+	fmt.Printf("\rElapsed %s | Frames %d | Lap %d | Dropped %d | Size %s    ",
+		elapsed,
+		framesCaptured,
+		client.Telemetry.CurrentLap(),
+		client.Statistics.PacketsDropped,
+		formatSize(size),
+	)
+}
+
+// formatSize formats a file size in bytes for display.
+func formatSize(size int64) string {
+	const unit = 1024
+
+	switch {
+	case size >= unit*unit:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(unit*unit))
+	case size >= unit:
+		return fmt.Sprintf("%.1f KiB", float64(size)/unit)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -89,6 +89,7 @@ func updateCircuitInfo(client *gttelemetry.Client, lapNumber int16, circuit gtci
 		circuitInfo, found := client.CircuitDB.GetCircuitByID(circuitID)
 		if found {
 			circuit = circuitInfo
+			circuit.Variation = client.CircuitDB.DisplayVariation(circuitID)
 		}
 	}
 
@@ -96,11 +97,9 @@ func updateCircuitInfo(client *gttelemetry.Client, lapNumber int16, circuit gtci
 }
 
 func renderTelemetry(client *gttelemetry.Client, clientConfig gttelemetry.Options, circuit gtcircuits.CircuitInfo) { //nolint:maintidx // long but simple enough
-	suggestedGear := client.Telemetry.SuggestedGear()
-
-	suggestedGearStr := fmt.Sprintf("[%d]", suggestedGear)
-	if suggestedGear == 15 {
-		suggestedGearStr = ""
+	suggestedGearStr := ""
+	if suggestion := client.Telemetry.GearSuggestion(); suggestion.Valid {
+		suggestedGearStr = fmt.Sprintf("[%d]", suggestion.Gear)
 	}
 
 	hasTurbo := client.Telemetry.Flags().HasTurbo
@@ -119,6 +118,12 @@ func renderTelemetry(client *gttelemetry.Client, clientConfig gttelemetry.Option
 		raceType = "Endurance"
 	case gtmodels.RaceTypeTimeTrial:
 		raceType = "Time Trial"
+	case gtmodels.RaceTypeQualifying:
+		raceType = "Qualifying"
+	case gtmodels.RaceTypeLicenceTest:
+		raceType = "Licence Test"
+	case gtmodels.RaceTypeMission:
+		raceType = "Mission"
 	default:
 		raceType = ""
 	}
@@ -367,6 +372,15 @@ func renderTelemetry(client *gttelemetry.Client, clientConfig gttelemetry.Option
 			client.Statistics.DecodeTimeAvg.Microseconds(),
 			client.Statistics.DecodeTimeMax.Microseconds(),
 		)
+
+		for _, target := range client.Statistics.FanOut() {
+			fmt.Printf("Fan-out       %-21s Sent: %9d      Errors: %9d     Dropped: %9d\n",
+				target.Address,
+				target.Sent,
+				target.Errors,
+				target.Dropped,
+			)
+		}
 	}
 }
 
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var errBoostArguments = errors.New("boost requires a recording")
+
+// runBoost analyses how quickly the turbo spooled in a recording, for each gear.
+func runBoost(args []string) error {
+	flags := flag.NewFlagSet("boost", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Write every boost response and the summary of each gear as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt boost [flags] <recording>
+
+Prints the peak boost of each gear at full throttle and the average time for the turbo to spool to 90% of it after
+the throttle is applied.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errBoostArguments
+	}
+
+	analyser := gttelemetry.NewBoostAnalyser()
+
+	err := scanRecording(flags.Arg(0), func(t *gttelemetry.Transformer) error {
+		analyser.Update(t)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		output := struct {
+			Gears     []gttelemetry.GearBoost     `json:"gears"`
+			Responses []gttelemetry.BoostResponse `json:"responses"`
+		}{
+			Gears:     analyser.Gears(),
+			Responses: analyser.Responses(),
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(output)
+		if err != nil {
+			return fmt.Errorf("encode boost: %w", err)
+		}
+
+		return nil
+	}
+
+	return printBoost(os.Stdout, analyser.Gears())
+}
+
+// printBoost prints a table of the boost in each gear.
+func printBoost(w io.Writer, gears []gttelemetry.GearBoost) error {
+	if len(gears) == 0 {
+		fmt.Fprintln(w, "No turbo boost found")
+
+		return nil
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(table, "Gear\tPeak boost (bar)\tSpool time (s)\tResponses\t")
+
+	for _, gear := range gears {
+		spool := "-"
+		if gear.Responses > 0 {
+			spool = fmt.Sprintf("%.2f", gear.AverageSpoolTime.Seconds())
+		}
+
+		fmt.Fprintf(table, "%d\t%.2f\t%s\t%d\t\n", gear.Gear, gear.PeakBoostBar, spool, gear.Responses)
+	}
+
+	return table.Flush()
+}
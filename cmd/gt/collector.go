@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/cluster"
+	"github.com/zetetos/gt-telemetry/v2/pkg/httpauth"
+	"github.com/zetetos/gt-telemetry/v2/pkg/livetiming"
+)
+
+// runCollector receives the frames forwarded by the daemons of several drivers and serves the combined live timing
+// until interrupted.
+func runCollector(args []string) error {
+	flags := flag.NewFlagSet("collector", flag.ExitOnError)
+	address := flags.String("address", livetiming.DefaultAddress, "TCP address to listen on")
+	staleAfter := flags.Duration("stale", cluster.DefaultStaleAfter, "Time after the last frame a driver is shown off circuit")
+	maxDrivers := flags.Int("max-drivers", cluster.DefaultMaxDrivers, "Number of drivers accepted, frames from further drivers are rejected")
+	token := flags.String("token", os.Getenv("GT_COLLECTOR_TOKEN"), "Token required from forwarders and spectators, defaults to $GT_COLLECTOR_TOKEN")
+	tlsCert := flags.String("tls-cert", "", "PEM certificate file to serve HTTPS with")
+	tlsKey := flags.String("tls-key", "", "PEM private key file of the certificate")
+	logLevel := flags.String("log-level", "info", "Log level")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt collector [flags]
+
+Receives frames from daemons configured with a cluster exporter and serves the combined live timing page at /, the
+standings at /standings.json and the latest frame of every driver at /drivers.json and /drivers/{name}.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	log := newLogger(*logLevel)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	collector := cluster.NewCollector(cluster.CollectorOptions{
+		Address:    *address,
+		StaleAfter: *staleAfter,
+		MaxDrivers: *maxDrivers,
+		Token:      *token,
+		TLS:        httpauth.TLS{CertFile: *tlsCert, KeyFile: *tlsKey},
+		Logger:     &log,
+	})
+
+	return collector.Run(ctx)
+}
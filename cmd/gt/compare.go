@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var (
+	errCompareArguments = errors.New("compare requires a reference lap and a lap to compare")
+	errTraceArguments   = errors.New("trace requires a recording")
+	errLapNotFound      = errors.New("lap not found in recording")
+)
+
+// runCompare compares two laps, read from lap trace CSV files or from recordings, and prints the time gained or lost
+// through each corner.
+func runCompare(args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	step := flags.Float64("step", gttelemetry.DefaultCompareStep, "Distance between compared points in metres")
+	tracePath := flags.String("trace", "", "Write the delta traces aligned by distance to this CSV file")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt compare [flags] <reference> <lap>
+
+Each lap is a lap trace CSV file written by "gt trace", or a recording followed by the lap number, e.g.
+session.gtz:3. The fastest lap of a recording is used when no lap number is given.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+
+		return errCompareArguments
+	}
+
+	reference, err := loadLap(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	lap, err := loadLap(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	comparison, err := gttelemetry.CompareLaps(reference, lap, float32(*step))
+	if err != nil {
+		return fmt.Errorf("compare laps: %w", err)
+	}
+
+	if *tracePath != "" {
+		err = writeFile(*tracePath, comparison.WriteCSV)
+		if err != nil {
+			return err
+		}
+	}
+
+	return printComparison(os.Stdout, reference, lap, comparison)
+}
+
+// runTrace writes the trace of a lap in a recording to stdout as CSV, for comparing later with "gt compare".
+func runTrace(args []string) error {
+	flags := flag.NewFlagSet("trace", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt trace <recording>[:lap]
+
+Writes the trace of a lap to stdout as CSV. The fastest lap is used when no lap number is given.
+`)
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errTraceArguments
+	}
+
+	lap, err := loadLap(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return lap.WriteCSV(os.Stdout)
+}
+
+// loadLap reads a lap from a lap trace CSV file, or from a recording with an optional ":lap" suffix.
+func loadLap(spec string) (gttelemetry.LapTrace, error) {
+	if strings.EqualFold(filepath.Ext(spec), ".csv") {
+		file, err := os.Open(spec)
+		if err != nil {
+			return gttelemetry.LapTrace{}, fmt.Errorf("open lap trace: %w", err)
+		}
+		defer file.Close()
+
+		return gttelemetry.ReadLapTraceCSV(file)
+	}
+
+	path, lapNumber := splitLapSpec(spec)
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://" + path,
+		LogLevel: "error",
+	})
+	if err != nil {
+		return gttelemetry.LapTrace{}, fmt.Errorf("open recording: %w", err)
+	}
+
+	recorder := gttelemetry.NewLapTraceRecorder()
+
+	for transformer, err := range client.Scan(context.Background()) {
+		if err != nil {
+			return gttelemetry.LapTrace{}, fmt.Errorf("read recording: %w", err)
+		}
+
+		recorder.Update(transformer)
+	}
+
+	var (
+		lap   gttelemetry.LapTrace
+		found bool
+	)
+
+	if lapNumber < 0 {
+		lap, found = recorder.Fastest()
+	} else {
+		lap, found = recorder.Trace(int16(lapNumber))
+	}
+
+	if !found {
+		return gttelemetry.LapTrace{}, fmt.Errorf("%w: %s", errLapNotFound, spec)
+	}
+
+	return lap, nil
+}
+
+// splitLapSpec splits a recording path with an optional ":lap" suffix, returning -1 when no lap number is given.
+func splitLapSpec(spec string) (string, int) {
+	if index := strings.LastIndex(spec, ":"); index > 0 {
+		number, err := strconv.Atoi(spec[index+1:])
+		if err == nil {
+			return spec[:index], number
+		}
+	}
+
+	return spec, -1
+}
+
+// writeFile creates a file and writes it with the write function.
+func writeFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+
+	err = write(file)
+	if err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// printComparison prints the lap times and the time delta through each corner.
+func printComparison(w io.Writer, reference, lap gttelemetry.LapTrace, comparison gttelemetry.LapComparison) error {
+	fmt.Fprintf(w, "Reference lap %d: %s\n", reference.Lap, formatLaptime(reference.Laptime))
+	fmt.Fprintf(w, "Compared lap %d:  %s (%s)\n\n", lap.Lap, formatLaptime(lap.Laptime), formatDelta(comparison.LaptimeDelta))
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(table, "Corner\tApex (m)\tMin speed (km/h)\tMin gear\tDelta (s)\t")
+
+	for _, corner := range comparison.Corners {
+		fmt.Fprintf(table, "%d\t%.0f\t%.1f / %.1f\t%d / %d\t%s\t\n",
+			corner.Corner,
+			corner.ApexDistance,
+			corner.ReferenceMinimumSpeed,
+			corner.MinimumSpeed,
+			corner.ReferenceMinimumGear,
+			corner.MinimumGear,
+			formatDelta(corner.TimeDelta),
+		)
+	}
+
+	return table.Flush()
+}
+
+// formatLaptime formats a lap time as m:ss.SSS.
+func formatLaptime(laptime time.Duration) string {
+	minutes := laptime / time.Minute
+	seconds := float64(laptime%time.Minute) / float64(time.Second)
+
+	return fmt.Sprintf("%d:%06.3f", minutes, seconds)
+}
+
+// formatDelta formats a time delta in seconds with an explicit sign.
+func formatDelta(delta time.Duration) string {
+	return fmt.Sprintf("%+.3f", delta.Seconds())
+}
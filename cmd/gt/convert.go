@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+var errConvertArguments = errors.New("convert requires a source and a destination recording")
+
+// runConvert re-encodes a recording in another telemetry format.
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	format := flags.String("format", string(models.Standard), "Telemetry format to convert to: A, B, ~ or C")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt convert [flags] <recording> <converted recording>
+
+Re-encodes the packets of a recording in another telemetry format, so that tools built for one format can read
+captures of another. Fields missing from the source format are zero filled, and fields the target format lacks are
+dropped. The converted recording is plain or compressed by its file extension, and encrypted recordings are read and
+written with the password in GT_RECORDING_PASSWORD.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+
+		return errConvertArguments
+	}
+
+	report, err := gttelemetry.ConvertRecording(context.Background(), flags.Arg(0), flags.Arg(1), gttelemetry.ConvertOptions{
+		Format:   models.Name(*format),
+		Password: os.Getenv("GT_RECORDING_PASSWORD"),
+	})
+	if err != nil {
+		return fmt.Errorf("convert recording: %w", err)
+	}
+
+	fmt.Printf("Converted %d packets to format %s, %d extended, %d truncated, %d invalid packets left out\n",
+		report.Packets, *format, report.Extended, report.Truncated, report.Invalid)
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var (
+	errCornersArguments = errors.New("corners requires a recording")
+	errUnknownCorner    = errors.New("no such corner")
+)
+
+// runCorners prints the minimum speed of every lap in a recording through each corner.
+func runCorners(args []string) error {
+	flags := flag.NewFlagSet("corners", flag.ExitOnError)
+	trace := flags.Int("trace", 0, "Write the speed, inputs and gear of every lap through this corner as CSV instead")
+	step := flags.Float64("step", gttelemetry.DefaultCompareStep, "Distance between the points of a corner trace in metres")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt corners [flags] <recording>
+
+Prints the minimum speed of every completed lap through each corner, with the best of the session marked with *.
+Corners are found in the speed of the fastest lap.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errCornersArguments
+	}
+
+	recorder := gttelemetry.NewLapTraceRecorder()
+
+	err := scanRecording(flags.Arg(0), func(t *gttelemetry.Transformer) error {
+		recorder.Update(t)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *trace != 0 {
+		return writeCornerTrace(os.Stdout, recorder, *trace, float32(*step))
+	}
+
+	table, err := recorder.CornerSpeeds()
+	if err != nil {
+		return fmt.Errorf("corner speeds: %w", err)
+	}
+
+	return printCornerSpeeds(os.Stdout, table)
+}
+
+// writeCornerTrace writes the trace of every lap through a corner as CSV.
+func writeCornerTrace(w io.Writer, recorder *gttelemetry.LapTraceRecorder, corner int, step float32) error {
+	traces, err := recorder.CornerTraces(step)
+	if err != nil {
+		return fmt.Errorf("corner traces: %w", err)
+	}
+
+	if corner < 1 || corner > len(traces) {
+		return fmt.Errorf("%w: %d of %d", errUnknownCorner, corner, len(traces))
+	}
+
+	return traces[corner-1].WriteCSV(w)
+}
+
+// printCornerSpeeds prints a row for each lap and a column for each corner, in km/h.
+func printCornerSpeeds(w io.Writer, table gttelemetry.CornerSpeedTable) error {
+	fmt.Fprintf(w, "Corners found in lap %d, minimum speeds in km/h\n\n", table.ReferenceLap)
+
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	var header strings.Builder
+
+	header.WriteString("Lap\t")
+
+	for corner, apex := range table.ApexDistances {
+		fmt.Fprintf(&header, "T%d (%.0fm)\t", corner+1, apex)
+	}
+
+	fmt.Fprintln(writer, header.String())
+
+	for _, lap := range table.Laps {
+		fmt.Fprintf(writer, "%d\t", lap.Lap)
+
+		for _, corner := range lap.Corners {
+			best := " "
+			if corner.Best {
+				best = "*"
+			}
+
+			fmt.Fprintf(writer, "%.1f%s\t", corner.MinimumSpeed, best)
+		}
+
+		fmt.Fprintln(writer)
+	}
+
+	return writer.Flush()
+}
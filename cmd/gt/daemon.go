@@ -0,0 +1,786 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/cluster"
+	"github.com/zetetos/gt-telemetry/v2/pkg/homeassistant"
+	"github.com/zetetos/gt-telemetry/v2/pkg/httpauth"
+	"github.com/zetetos/gt-telemetry/v2/pkg/livetiming"
+	"github.com/zetetos/gt-telemetry/v2/pkg/osc"
+	"github.com/zetetos/gt-telemetry/v2/pkg/serialdash"
+	"github.com/zetetos/gt-telemetry/v2/pkg/upload"
+	"github.com/zetetos/gt-telemetry/v2/pkg/webhook"
+	"github.com/zetetos/gt-telemetry/v2/pkg/websocket"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// readyTimeout is how long after the last packet the daemon is still reported ready.
+	readyTimeout = 5 * time.Second
+	// reconnectDelay is the pause before the telemetry stream is restarted after a recoverable error.
+	reconnectDelay = time.Second
+
+	// exporterRetryDelay is the pause before a failed exporter is restarted, doubling with each failure in a row up to
+	// exporterMaxRetryDelay.
+	exporterRetryDelay    = time.Second
+	exporterMaxRetryDelay = time.Minute
+	// exporterStableTime is how long a restarted exporter must run without failing to be reported healthy again.
+	exporterStableTime = 10 * time.Second
+
+	readHeaderTimeout = 5 * time.Second
+	shutdownTimeout   = 5 * time.Second
+
+	// webhookPollInterval is how often the telemetry is checked for session events, a few frames at 60Hz.
+	webhookPollInterval = 50 * time.Millisecond
+
+	// expvarName is the name the client statistics are published under.
+	expvarName = "gttelemetry"
+)
+
+// errExporterStopped indicates an exporter that returned before the daemon stopped.
+var errExporterStopped = errors.New("exporter stopped")
+
+// runDaemon runs the telemetry client and the configured exporters until interrupted. The configuration is reloaded
+// on SIGHUP, an invalid configuration is logged and the running configuration kept.
+func runDaemon(args []string) error {
+	flags := flag.NewFlagSet("daemon", flag.ExitOnError)
+	configPath := flags.String("config", "gt.yaml", "Path to the YAML configuration file")
+	_ = flags.Parse(args)
+
+	cfg, err := gttelemetry.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		log := newLogger(cfg.Client.LogLevel)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		go func() {
+			done <- serve(ctx, cfg, log)
+		}()
+
+		reload := false
+
+		for !reload {
+			select {
+			case err = <-done:
+				cancel()
+
+				return err
+			case sig := <-signals:
+				if sig != syscall.SIGHUP {
+					log.Info().Str("signal", sig.String()).Msg("stopping daemon")
+					cancel()
+
+					return <-done
+				}
+
+				newCfg, err := gttelemetry.LoadConfig(*configPath)
+				if err != nil {
+					log.Error().Err(err).Msg("failed to reload configuration, keeping the running configuration")
+
+					continue
+				}
+
+				log.Info().Msg("reloading configuration")
+
+				cfg = newCfg
+				reload = true
+			}
+		}
+
+		cancel()
+
+		err = <-done
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// newLogger creates the daemon logger, defaulting to the info level.
+func newLogger(level string) zerolog.Logger {
+	logLevel, err := zerolog.ParseLevel(level)
+	if err != nil || level == "" {
+		logLevel = zerolog.InfoLevel
+	}
+
+	return zerolog.New(os.Stdout).Level(logLevel).With().Timestamp().Logger()
+}
+
+// serve runs the telemetry client and exporters until the context is cancelled.
+func serve(ctx context.Context, cfg gttelemetry.Config, log zerolog.Logger) error {
+	opts := cfg.Options()
+	opts.Logger = &log
+	opts.OnPacketDropped = func(drop gttelemetry.PacketDrop) {
+		log.Debug().Int("count", drop.Count).Float64("packetRate", drop.PacketRate).
+			Dur("sinceLastDrop", drop.SinceLastDrop).Dur("processingTime", drop.ProcessingTime).
+			Str("cause", string(drop.Cause)).Msg("packet drop")
+	}
+
+	if cfg.Exporters.HTTP != nil && cfg.Exporters.HTTP.Expvar {
+		opts.ExpvarName = expvarName
+	}
+
+	frames := &frameFeed{}
+	opts.OnFrame = frames.observe
+
+	client, err := gttelemetry.New(opts)
+	if err != nil {
+		return fmt.Errorf("create telemetry client: %w", err)
+	}
+
+	// The exporters read the empty frame of the client until the first packet is decoded
+	frames.observe(client.Telemetry)
+
+	group, ctx := errgroup.WithContext(ctx)
+	health := &healthMonitor{}
+
+	frames.onFrame(func(*gttelemetry.Transformer) { health.received() })
+
+	if cfg.Exporters.HTTP != nil {
+		handler, err := httpHandler(ctx, group, cfg, client, frames, health, log)
+		if err != nil {
+			return err
+		}
+
+		group.Go(func() error { return serveHTTP(ctx, *cfg.Exporters.HTTP, handler, log) })
+	}
+
+	if cfg.Exporters.OSC != nil {
+		policy := cfg.ChannelPolicy("osc")
+
+		exporter, err := osc.NewExporter(osc.Options{
+			Address:  cfg.Exporters.OSC.Address,
+			Prefix:   cfg.Exporters.OSC.Prefix,
+			Channels: redactChannels(policy, "osc", cfg.Exporters.OSC.Channels, log),
+		})
+		if err != nil {
+			return fmt.Errorf("create OSC exporter: %w", err)
+		}
+
+		source := policy.Channels(frames)
+
+		group.Go(func() error {
+			defer exporter.Close()
+
+			return superviseExporter(ctx, "osc", health, log, func(ctx context.Context) error {
+				return exportOSC(ctx, exporter, source, cfg.Exporters.OSC.Interval)
+			})
+		})
+	}
+
+	if cfg.Exporters.MQTT != nil {
+		policy := cfg.ChannelPolicy("mqtt")
+
+		group.Go(func() error {
+			return superviseExporter(ctx, "mqtt", health, log, func(ctx context.Context) error {
+				return exportMQTT(ctx, *cfg.Exporters.MQTT, policy, cfg.Precision, frames, log)
+			})
+		})
+	}
+
+	if cfg.Exporters.Serial != nil {
+		source := cfg.ChannelPolicy("serial").Channels(frames)
+
+		dashboard, err := serialdash.NewDashboard(source, serialdash.Options{
+			Port:     cfg.Exporters.Serial.Port,
+			Baud:     cfg.Exporters.Serial.Baud,
+			Template: cfg.Exporters.Serial.Template,
+			Interval: cfg.Exporters.Serial.Interval,
+			Logger:   &log,
+		})
+		if err != nil {
+			return fmt.Errorf("create serial dashboard: %w", err)
+		}
+
+		group.Go(func() error { return superviseExporter(ctx, "serial", health, log, dashboard.Run) })
+	}
+
+	if cfg.Exporters.Cluster != nil {
+		forwarder, err := cluster.NewForwarder(client.Telemetry, cluster.ForwarderOptions{
+			CollectorURL: cfg.Exporters.Cluster.Collector,
+			Driver:       cfg.Exporters.Cluster.Driver,
+			Token:        cfg.Exporters.Cluster.Token,
+			Channels:     redactChannels(cfg.ChannelPolicy("cluster"), "cluster", cfg.Exporters.Cluster.Channels, log),
+			Interval:     cfg.Exporters.Cluster.Interval,
+			Logger:       &log,
+		})
+		if err != nil {
+			return fmt.Errorf("create cluster forwarder: %w", err)
+		}
+
+		frames.onFrame(func(telemetry *gttelemetry.Transformer) { forwarder.Observe(telemetry) })
+		group.Go(func() error { return superviseExporter(ctx, "cluster", health, log, forwarder.Run) })
+	}
+
+	if cfg.Exporters.Upload != nil {
+		uploader, err := upload.New(upload.Options{
+			Endpoint:        cfg.Exporters.Upload.Endpoint,
+			Region:          cfg.Exporters.Upload.Region,
+			Bucket:          cfg.Exporters.Upload.Bucket,
+			Prefix:          cfg.Exporters.Upload.Prefix,
+			AccessKeyID:     cmp.Or(cfg.Exporters.Upload.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID")),
+			SecretAccessKey: cmp.Or(cfg.Exporters.Upload.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			PartSize:        cfg.Exporters.Upload.PartSize,
+			Metadata:        cfg.Exporters.Upload.Metadata,
+			Tags:            cfg.Exporters.Upload.Tags,
+			Logger:          &log,
+		})
+		if err != nil {
+			return fmt.Errorf("create uploader: %w", err)
+		}
+
+		group.Go(func() error {
+			return superviseExporter(ctx, "upload", health, log, func(ctx context.Context) error {
+				return uploadRecordings(ctx, uploader, client, cfg.Recording.Directory, cfg.Exporters.Upload.Live, log)
+			})
+		})
+	}
+
+	if cfg.Exporters.Webhook != nil {
+		sink, err := webhook.New(webhook.Options{
+			URLs:    cfg.Exporters.Webhook.URLs,
+			Headers: cfg.Exporters.Webhook.Headers,
+			Secret:  cfg.Exporters.Webhook.Secret,
+			Retries: cfg.Exporters.Webhook.Retries,
+			Logger:  &log,
+		})
+		if err != nil {
+			return fmt.Errorf("create webhook sink: %w", err)
+		}
+
+		group.Go(func() error { return superviseExporter(ctx, "webhook", health, log, sink.Run) })
+		group.Go(func() error { return exportWebhook(ctx, sink, frames, cfg.Exporters.Webhook.Events) })
+	}
+
+	// Observers are added before the client starts decoding frames
+	group.Go(func() error { return stream(ctx, client, log) })
+
+	return group.Wait()
+}
+
+// redactChannels returns the channels of an exporter that its policy allows, warning about the channels hidden.
+func redactChannels(policy gttelemetry.ChannelPolicy, exporter string, channels []string, log zerolog.Logger) []string {
+	if redacted := policy.Redacted(channels); len(redacted) > 0 {
+		log.Warn().Str("exporter", exporter).Strs("channels", redacted).Msg("channels redacted")
+	}
+
+	return policy.Filter(channels)
+}
+
+// stream reads telemetry until the context is cancelled, restarting the stream after recoverable errors.
+func stream(ctx context.Context, client *gttelemetry.Client, log zerolog.Logger) error {
+	for ctx.Err() == nil {
+		recoverable, err := client.Stream(ctx)
+		if err != nil && ctx.Err() == nil {
+			if !recoverable {
+				return fmt.Errorf("telemetry stream: %w", err)
+			}
+
+			log.Warn().Err(err).Msg("telemetry stream interrupted, reconnecting")
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectDelay):
+		}
+	}
+
+	return nil
+}
+
+// httpHandler builds the handler serving the live timing page, the health endpoints, the current vehicle and frame,
+// the circuit maps, the WebSocket feeds, the packet drop summary and the diagnostics endpoints.
+func httpHandler(
+	ctx context.Context, group *errgroup.Group, cfg gttelemetry.Config, client *gttelemetry.Client, frames *frameFeed,
+	health *healthMonitor, log zerolog.Logger,
+) (http.Handler, error) {
+	timingFeed := &livetiming.Feed{}
+	frames.onFrame(func(telemetry *gttelemetry.Transformer) { timingFeed.Observe(telemetry) })
+
+	board := livetiming.NewBoard()
+	board.AddSource("Driver", timingFeed)
+
+	timing := livetiming.NewServer(board, livetiming.Options{Logger: &log})
+
+	mux := http.NewServeMux()
+	mux.Handle("/", timing.Handler())
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if !health.ready() {
+			http.Error(w, "no telemetry received", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		if failures := health.exporterFailures(); len(failures) > 0 {
+			http.Error(w, strings.Join(failures, "\n"), http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("ok\n"))
+	})
+
+	mux.HandleFunc("GET /vehicle.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		err := json.NewEncoder(w).Encode(frames.frame().CurrentVehicle())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to encode vehicle")
+		}
+	})
+
+	httpPolicy := cfg.ChannelPolicy("http")
+
+	mux.HandleFunc("GET /frame.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		err := json.NewEncoder(w).Encode(cfg.Precision.RoundSnapshot(httpPolicy.RedactSnapshot(frames.frame().Snapshot())))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to encode frame")
+		}
+	})
+
+	mux.HandleFunc("GET /maps/{file}", func(w http.ResponseWriter, r *http.Request) {
+		circuitID, ok := strings.CutSuffix(r.PathValue("file"), ".svg")
+
+		trackMap, found := client.CircuitDB.TrackMap(circuitID)
+		if !ok || !found {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = w.Write(trackMap.SVG())
+	})
+
+	mux.HandleFunc("GET /drops.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		err := json.NewEncoder(w).Encode(client.PacketDrops())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to encode packet drops")
+		}
+	})
+
+	if cfg.Exporters.HTTP.Expvar {
+		mux.Handle("GET /debug/vars", expvar.Handler())
+	}
+
+	if cfg.Exporters.HTTP.Pprof {
+		mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+		mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	}
+
+	group.Go(func() error {
+		ticker := time.NewTicker(livetiming.DefaultUpdateInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				board.Update()
+			}
+		}
+	})
+
+	if cfg.Exporters.WebSocket != nil {
+		policy := cfg.ChannelPolicy("websocket")
+
+		source := cfg.Precision.Channels(policy.Channels(frames))
+
+		feed, err := websocket.NewServer(source, websocket.Options{
+			Channels: redactChannels(policy, "websocket", cfg.Exporters.WebSocket.Channels, log),
+			Interval: cfg.Exporters.WebSocket.Interval,
+			Logger:   &log,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create WebSocket server: %w", err)
+		}
+
+		mux.Handle("GET /ws", feed)
+		group.Go(func() error {
+			feed.Run(ctx)
+
+			return nil
+		})
+
+		if cfg.Exporters.WebSocket.Map {
+			mapFeed := websocket.NewMapServer(newMapTracker(client.CircuitDB, frames), websocket.Options{
+				Interval: cfg.Exporters.WebSocket.MapInterval,
+				Logger:   &log,
+			})
+
+			mux.Handle("GET /ws/map", mapFeed)
+			group.Go(func() error {
+				mapFeed.Run(ctx)
+
+				return nil
+			})
+		}
+	}
+
+	return httpauth.RequireToken(cfg.Exporters.HTTP.Token, mux, "/healthz", "/readyz"), nil
+}
+
+// serveHTTP serves HTTP requests until the context is cancelled.
+func serveHTTP(ctx context.Context, cfg gttelemetry.HTTPExporterConfig, handler http.Handler, log zerolog.Logger) error {
+	server := &http.Server{
+		Addr:              cfg.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Info().Str("address", cfg.Address).Bool("tls", cfg.TLS().Enabled()).Msg("serving HTTP")
+
+	err := httpauth.ListenAndServe(server, cfg.TLS())
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve HTTP: %w", err)
+	}
+
+	return nil
+}
+
+// superviseExporter runs an exporter until the context is cancelled, restarting it with a growing delay whenever it
+// fails, so that an unreachable receiver or broker neither stops the daemon nor the other exporters. The exporter is
+// reported as failing by the readiness endpoint until it has run for exporterStableTime after a restart.
+func superviseExporter(
+	ctx context.Context, name string, health *healthMonitor, log zerolog.Logger, run func(ctx context.Context) error,
+) error {
+	delay := exporterRetryDelay
+
+	for {
+		started := time.Now()
+		stable := time.AfterFunc(exporterStableTime, func() { health.exporterRecovered(name) })
+
+		err := run(ctx)
+
+		stable.Stop()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err == nil {
+			err = errExporterStopped
+		}
+
+		if time.Since(started) >= exporterStableTime {
+			delay = exporterRetryDelay
+		}
+
+		health.exporterFailed(name, err)
+		log.Warn().Err(err).Str("exporter", name).Dur("retryIn", delay).Msg("exporter failed, restarting")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, exporterMaxRetryDelay)
+	}
+}
+
+// exportOSC sends the configured channels to the OSC receiver at every interval.
+func exportOSC(ctx context.Context, exporter *osc.Exporter, source osc.ChannelSource, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := exporter.Send(source)
+			if err != nil {
+				return fmt.Errorf("send OSC bundle: %w", err)
+			}
+		}
+	}
+}
+
+// exportWebhook emits the session events detected in the telemetry to the webhook sink, skipping event types that are
+// not listed when events is set.
+func exportWebhook(ctx context.Context, sink *webhook.Sink, frames *frameFeed, events []string) error {
+	detector := gttelemetry.NewSessionEventDetector(gttelemetry.TimelineOptions{})
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, event := range detector.Update(frames.frame()) {
+				if len(events) == 0 || slices.Contains(events, string(event.Type)) {
+					sink.Emit(event)
+				}
+			}
+		}
+	}
+}
+
+// exportMQTT publishes the Home Assistant discovery messages, then the sensor state at every interval. Sensors of
+// channels hidden by the policy are neither announced nor published.
+func exportMQTT(
+	ctx context.Context, cfg gttelemetry.MQTTExporterConfig, policy gttelemetry.ChannelPolicy, precision gttelemetry.ChannelPrecision,
+	frames *frameFeed, log zerolog.Logger,
+) error {
+	haOpts := homeassistant.Options{
+		DiscoveryPrefix: cfg.DiscoveryPrefix,
+		NodeID:          cfg.NodeID,
+		DeviceName:      cfg.DeviceName,
+	}
+
+	var sensors []homeassistant.Sensor
+
+	for _, sensor := range homeassistant.DefaultSensors() {
+		if sensor.Channel == "" || policy.Allowed(sensor.Channel) {
+			sensors = append(sensors, sensor)
+		} else {
+			haOpts.Exclude = append(haOpts.Exclude, sensor.Key)
+		}
+	}
+
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = homeassistant.DefaultNodeID
+	}
+
+	publisher, err := dialMQTT(cfg.Address, clientID, cfg.Username, cfg.Password)
+	if err != nil {
+		return err
+	}
+
+	defer publisher.Close()
+
+	discovery, err := homeassistant.DiscoveryMessages(haOpts, sensors)
+	if err != nil {
+		return fmt.Errorf("build discovery messages: %w", err)
+	}
+
+	for _, message := range discovery {
+		err = publisher.Publish(message.Topic, message.Payload, message.Retain)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info().Str("address", cfg.Address).Msg("publishing to MQTT broker")
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-publisher.Lost():
+			return errMQTTConnectionLost
+		case <-ticker.C:
+			message, err := homeassistant.StateMessage(haOpts, roundedState{StateSource: frames.frame(), precision: precision})
+			if err != nil {
+				return fmt.Errorf("build state message: %w", err)
+			}
+
+			err = publisher.Publish(message.Topic, message.Payload, message.Retain)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// roundedState rounds the channels of the Home Assistant state to their precision.
+type roundedState struct {
+	homeassistant.StateSource
+
+	precision gttelemetry.ChannelPrecision
+}
+
+func (s roundedState) GroundSpeedKPH() float32 {
+	return s.precision.Round("groundSpeedKPH", s.StateSource.GroundSpeedKPH())
+}
+
+func (s roundedState) EngineRPM() float32 {
+	return s.precision.Round("engineRPM", s.StateSource.EngineRPM())
+}
+
+// healthMonitor tracks when telemetry was last received and which exporters are failing, for the readiness endpoint.
+type healthMonitor struct {
+	lastPacket atomic.Int64
+
+	mu       sync.Mutex
+	failures map[string]error
+}
+
+// received records that a frame was decoded.
+func (h *healthMonitor) received() {
+	h.lastPacket.Store(time.Now().UnixNano())
+}
+
+// ready reports whether telemetry has been received recently.
+func (h *healthMonitor) ready() bool {
+	lastPacket := h.lastPacket.Load()
+
+	return lastPacket != 0 && time.Since(time.Unix(0, lastPacket)) < readyTimeout
+}
+
+// exporterFailed records the error an exporter failed with.
+func (h *healthMonitor) exporterFailed(name string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.failures == nil {
+		h.failures = map[string]error{}
+	}
+
+	h.failures[name] = err
+}
+
+// exporterRecovered clears the failure of an exporter that has run without failing since it was restarted.
+func (h *healthMonitor) exporterRecovered(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.failures, name)
+}
+
+// exporterFailures describes the exporters that are failing, sorted by name.
+func (h *healthMonitor) exporterFailures() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	failures := make([]string, 0, len(h.failures))
+	for name, err := range h.failures {
+		failures = append(failures, fmt.Sprintf("exporter %s failing: %v", name, err))
+	}
+
+	slices.Sort(failures)
+
+	return failures
+}
+
+// frameFeed hands the frames decoded by the client to the exporters. The client overwrites its Telemetry with every
+// packet on the decoding goroutine, so exporters running on other goroutines either copy what they need in an
+// observer, which runs on the decoding goroutine, or read the clone of the latest frame kept by the feed.
+type frameFeed struct {
+	latest    atomic.Pointer[gttelemetry.Transformer]
+	observers []func(*gttelemetry.Transformer)
+}
+
+// onFrame adds an observer called with every decoded frame. Observers must be added before the client starts
+// streaming.
+func (f *frameFeed) onFrame(observe func(*gttelemetry.Transformer)) {
+	f.observers = append(f.observers, observe)
+}
+
+// observe keeps a clone of the frame for the exporters and passes the frame to the observers.
+func (f *frameFeed) observe(telemetry *gttelemetry.Transformer) {
+	f.latest.Store(telemetry.Clone())
+
+	for _, observe := range f.observers {
+		observe(telemetry)
+	}
+}
+
+// frame returns the clone of the latest frame, which is shared by the exporters and must not be modified.
+func (f *frameFeed) frame() *gttelemetry.Transformer {
+	return f.latest.Load()
+}
+
+// Channel returns the smoothed value of the named channel in the latest frame, as the channel source of the exporters.
+func (f *frameFeed) Channel(name string) (float32, bool) {
+	return f.frame().FilteredChannels().Channel(name)
+}
+
+// mapTracker follows the vehicle on the map of the circuit being driven, for the WebSocket map channel. It is only
+// read by the map server, so the path matcher is not shared.
+type mapTracker struct {
+	circuitDB *circuits.CircuitDB
+	frames    *frameFeed
+	matcher   *circuits.PathMatcher
+}
+
+// newMapTracker creates a map tracker identifying circuits in the circuit database.
+func newMapTracker(circuitDB *circuits.CircuitDB, frames *frameFeed) *mapTracker {
+	return &mapTracker{circuitDB: circuitDB, frames: frames, matcher: circuits.NewPathMatcher(circuitDB)}
+}
+
+// MapPosition returns the position of the vehicle on the map of the identified circuit.
+func (m *mapTracker) MapPosition() (websocket.MapPosition, bool) {
+	frame := m.frames.frame()
+
+	if !frame.IsOnCircuit() {
+		m.matcher.Reset()
+
+		return websocket.MapPosition{}, false
+	}
+
+	coordinate := frame.PositionalMapCoordinates()
+
+	ident := m.matcher.Identify(coordinate)
+	if !ident.Identified() {
+		return websocket.MapPosition{}, false
+	}
+
+	trackMap, found := m.circuitDB.TrackMap(ident.CircuitID)
+	if !found {
+		return websocket.MapPosition{}, false
+	}
+
+	x, y, _ := trackMap.Position(coordinate)
+
+	return websocket.MapPosition{CircuitID: ident.CircuitID, X: x, Y: y, Confidence: ident.Confidence}, true
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/homeassistant"
+	"github.com/zetetos/gt-telemetry/v2/pkg/osc"
+	"github.com/zetetos/gt-telemetry/v2/pkg/webhook"
+	"golang.org/x/sync/errgroup"
+)
+
+// streamDuration is how long the demo recording is streamed while the exporters read it.
+const streamDuration = time.Second
+
+type DaemonTestSuite struct {
+	suite.Suite
+}
+
+func TestDaemonTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(DaemonTestSuite))
+}
+
+// connectWebSocket performs the WebSocket handshake for the path and returns a reader positioned after the response.
+func (suite *DaemonTestSuite) connectWebSocket(serverURL, path string) *bufio.Reader {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { _ = conn.Close() })
+	suite.Require().NoError(conn.SetDeadline(time.Now().Add(streamDuration + 2*time.Second)))
+
+	_, err = io.WriteString(conn, "GET "+path+" HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	suite.Require().NoError(err)
+
+	reader := bufio.NewReader(conn)
+
+	response, err := http.ReadResponse(reader, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusSwitchingProtocols, response.StatusCode)
+
+	return reader
+}
+
+// get requests the path and returns the status code and body.
+func (suite *DaemonTestSuite) get(serverURL, path string) (int, []byte) {
+	resp, err := http.Get(serverURL + path) //nolint:noctx // test request
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	suite.Require().NoError(err)
+
+	return resp.StatusCode, body
+}
+
+func (suite *DaemonTestSuite) TestExportersReadTelemetryWhileStreaming() {
+	// Arrange
+	log := zerolog.Nop()
+
+	cfg := gttelemetry.Config{
+		Exporters: gttelemetry.ExportersConfig{
+			HTTP: &gttelemetry.HTTPExporterConfig{},
+			WebSocket: &gttelemetry.WebSocketExporterConfig{
+				Channels:    []string{"engineRPM", "groundSpeedKPH"},
+				Interval:    10 * time.Millisecond,
+				Map:         true,
+				MapInterval: 10 * time.Millisecond,
+			},
+		},
+	}
+
+	frames := &frameFeed{}
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://../../data/replays/demo.gtz",
+		LogLevel: "error",
+		OnFrame:  frames.observe,
+	})
+	suite.Require().NoError(err)
+
+	frames.observe(client.Telemetry)
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamDuration)
+	defer cancel()
+
+	group, ctx := errgroup.WithContext(ctx)
+	health := &healthMonitor{}
+
+	frames.onFrame(func(*gttelemetry.Transformer) { health.received() })
+
+	handler, err := httpHandler(ctx, group, cfg, client, frames, health, log)
+	suite.Require().NoError(err)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	receiver, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	defer receiver.Close()
+
+	exporter, err := osc.NewExporter(osc.Options{Address: receiver.LocalAddr().String(), Channels: []string{"engineRPM"}})
+	suite.Require().NoError(err)
+
+	defer exporter.Close()
+
+	hooks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hooks.Close()
+
+	sink, err := webhook.New(webhook.Options{URLs: []string{hooks.URL}, Retries: -1, Logger: &log})
+	suite.Require().NoError(err)
+
+	group.Go(func() error { return exportOSC(ctx, exporter, frames, time.Millisecond) })
+	group.Go(func() error { return sink.Run(ctx) })
+	group.Go(func() error { return exportWebhook(ctx, sink, frames, nil) })
+	group.Go(func() error {
+		for ctx.Err() == nil {
+			_, err := homeassistant.StateMessage(homeassistant.Options{}, roundedState{StateSource: frames.frame()})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	feed := suite.connectWebSocket(server.URL, "/ws")
+	mapFeed := suite.connectWebSocket(server.URL, "/ws/map")
+
+	group.Go(func() error {
+		_, err := client.Stream(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return err
+	})
+
+	// Act
+	requests := 0
+
+	for ctx.Err() == nil {
+		for _, path := range []string{"/standings.json", "/vehicle.json", "/frame.json", "/readyz"} {
+			_, _ = suite.get(server.URL, path)
+		}
+
+		requests++
+	}
+
+	suite.Require().NoError(group.Wait())
+
+	status, body := suite.get(server.URL, "/frame.json")
+
+	var frame map[string]any
+
+	suite.Require().NoError(json.Unmarshal(body, &frame))
+
+	// Assert
+	suite.Positive(requests)
+	suite.Equal(http.StatusOK, status)
+	suite.NotEmpty(frame)
+	suite.True(health.ready(), "frames were received from the recording")
+
+	_, err = feed.Peek(1)
+	suite.Require().NoError(err, "the WebSocket feed sent frames")
+
+	_, err = mapFeed.Peek(1)
+	suite.Require().NoError(err, "the map feed sent frames")
+}
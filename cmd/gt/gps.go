@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
+)
+
+var (
+	errGPSArguments = errors.New("gps requires a recording")
+	errGPSFormat    = errors.New("unknown GPS format")
+)
+
+// runGPS converts a recording into synthesised GPS data, for GPS based lap analysis tools.
+func runGPS(args []string) error {
+	flags := flag.NewFlagSet("gps", flag.ExitOnError)
+	format := flags.String("format", "gpx", "Output format: gpx or nmea")
+	start := flags.String("start", "", "Time of the first frame in RFC 3339 format, defaults to the recording modification time")
+	output := flags.String("o", "", "Write to this file instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt gps [flags] <recording>
+
+Writes the laps of a recording as a GPX track, with a segment for each lap, or as NMEA sentences. Positions are
+synthesised from the map coordinates around the real location of the circuit, or around Greenwich for fictional
+circuits.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errGPSArguments
+	}
+
+	path := flags.Arg(0)
+
+	startTime, err := gpsStartTime(path, *start)
+	if err != nil {
+		return err
+	}
+
+	anchor, err := recordingAnchor(path)
+	if err != nil {
+		return err
+	}
+
+	converter := gttelemetry.NewGPSConverter(gttelemetry.GPSOptions{Anchor: &anchor, Start: startTime})
+
+	switch *format {
+	case "gpx":
+		return writeOutput(*output, func(w io.Writer) error {
+			return writeGPX(w, path, converter)
+		})
+	case "nmea":
+		return writeOutput(*output, func(w io.Writer) error {
+			return scanRecording(path, func(t *gttelemetry.Transformer) error {
+				fix, ok := converter.Fix(t)
+				if !ok {
+					return nil
+				}
+
+				return geo.WriteNMEA(w, fix)
+			})
+		})
+	}
+
+	return fmt.Errorf("%w: %s", errGPSFormat, *format)
+}
+
+// writeGPX writes a recording as a GPX track, starting a new segment for each lap.
+func writeGPX(w io.Writer, path string, converter *gttelemetry.GPSConverter) error {
+	writer := geo.NewGPXWriter(w, filepath.Base(path))
+	lap := int16(-1)
+
+	err := scanRecording(path, func(t *gttelemetry.Transformer) error {
+		fix, ok := converter.Fix(t)
+		if !ok {
+			return nil
+		}
+
+		if t.CurrentLap() != lap {
+			lap = t.CurrentLap()
+
+			err := writer.NewSegment()
+			if err != nil {
+				return err
+			}
+		}
+
+		return writer.Write(fix)
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// gpsStartTime parses the start time flag, falling back to the modification time of the recording.
+func gpsStartTime(path, start string) (time.Time, error) {
+	if start != "" {
+		startTime, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse start time: %w", err)
+		}
+
+		return startTime, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("stat recording: %w", err)
+	}
+
+	return info.ModTime().Truncate(time.Second), nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var errHeatmapArguments = errors.New("heatmap requires a recording")
+
+// runHeatmap draws the positions visited in a recording as a PNG heatmap aligned to the circuit map.
+func runHeatmap(args []string) error {
+	flags := flag.NewFlagSet("heatmap", flag.ExitOnError)
+	cellSize := flags.Float64("cell", gttelemetry.DefaultHeatmapCellSize, "Size of each pixel in metres")
+	output := flags.String("o", "", "Write to this file instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt heatmap [flags] <recording>
+
+Draws the positions visited in a recording as a PNG, coloured by the number of laps that passed through each pixel.
+The area of the circuit map covered by the image is written to stderr, for lining the image up with a map.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errHeatmapArguments
+	}
+
+	heatmap := gttelemetry.NewHeatmap(float32(*cellSize))
+
+	err := scanRecording(flags.Arg(0), func(t *gttelemetry.Transformer) error {
+		heatmap.Update(t)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	bounds, ok := heatmap.Bounds()
+	if !ok {
+		return gttelemetry.ErrEmptyHeatmap
+	}
+
+	fmt.Fprintf(os.Stderr, "Map area: X %.0f to %.0f, Z %.0f to %.0f, %.2fm per pixel, %d laps\n",
+		bounds.MinX, bounds.MaxX, bounds.MinZ, bounds.MaxZ, bounds.CellSize, heatmap.Laps())
+
+	return writeOutput(*output, heatmap.WritePNG)
+}
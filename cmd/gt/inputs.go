@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var errInputsArguments = errors.New("inputs requires a recording")
+
+// runInputs summarises how the throttle, brake and steering were used in a recording, for each lap and the session.
+func runInputs(args []string) error {
+	flags := flag.NewFlagSet("inputs", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Write the full histograms as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt inputs [flags] <recording>
+
+Prints the percentage of time at full throttle, braking and coasting and in each gear for each lap and the whole
+recording, or writes the throttle, brake and steering histograms and the engine operating map as JSON.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errInputsArguments
+	}
+
+	collector := gttelemetry.NewHistogramCollector()
+
+	err := scanRecording(flags.Arg(0), func(t *gttelemetry.Transformer) error {
+		collector.Update(t)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeInputsJSON(os.Stdout, collector)
+	}
+
+	return printInputs(os.Stdout, collector)
+}
+
+// printInputs prints a table of the pedal usage of each lap and the session.
+func printInputs(w io.Writer, collector *gttelemetry.HistogramCollector) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(table, "Lap\tFull throttle (%)\tBraking (%)\tCoasting (%)\t")
+
+	row := func(label string, histograms gttelemetry.InputHistograms) {
+		fmt.Fprintf(table, "%s\t%.1f\t%.1f\t%.1f\t\n",
+			label,
+			histograms.FullThrottlePercent(),
+			histograms.BrakingPercent(),
+			histograms.CoastingPercent(),
+		)
+	}
+
+	for _, lap := range collector.Laps() {
+		histograms, _ := collector.Lap(lap)
+		row(fmt.Sprint(lap), histograms)
+	}
+
+	row("Session", collector.Session())
+
+	err := table.Flush()
+	if err != nil {
+		return err
+	}
+
+	return printGearUsage(w, collector)
+}
+
+// printGearUsage prints a table of the percentage of time spent in each gear in each lap and the session.
+func printGearUsage(w io.Writer, collector *gttelemetry.HistogramCollector) error {
+	session := collector.Session()
+	gears := session.Gears()
+
+	if len(gears) == 0 {
+		return nil
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprint(w, "\n")
+	fmt.Fprint(table, "Lap\t")
+
+	for _, gear := range gears {
+		fmt.Fprintf(table, "Gear %d (%%)\t", gear)
+	}
+
+	fmt.Fprintln(table)
+
+	row := func(label string, histograms gttelemetry.InputHistograms) {
+		fmt.Fprintf(table, "%s\t", label)
+
+		for _, gear := range gears {
+			fmt.Fprintf(table, "%.1f\t", histograms.GearPercent(gear))
+		}
+
+		fmt.Fprintln(table)
+	}
+
+	for _, lap := range collector.Laps() {
+		histograms, _ := collector.Lap(lap)
+		row(fmt.Sprint(lap), histograms)
+	}
+
+	row("Session", session)
+
+	return table.Flush()
+}
+
+// writeInputsJSON writes the histograms of the session and each lap.
+func writeInputsJSON(w io.Writer, collector *gttelemetry.HistogramCollector) error {
+	type lapInputs struct {
+		Lap int16 `json:"lap"`
+		gttelemetry.InputHistograms
+	}
+
+	output := struct {
+		Session gttelemetry.InputHistograms `json:"session"`
+		Laps    []lapInputs                 `json:"laps"`
+	}{
+		Session: collector.Session(),
+		Laps:    make([]lapInputs, 0, len(collector.Laps())),
+	}
+
+	for _, lap := range collector.Laps() {
+		histograms, _ := collector.Lap(lap)
+		output.Laps = append(output.Laps, lapInputs{Lap: lap, InputHistograms: histograms})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(output)
+	if err != nil {
+		return fmt.Errorf("encode inputs: %w", err)
+	}
+
+	return nil
+}
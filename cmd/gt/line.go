@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+)
+
+var errLineArguments = errors.New("line requires a recording")
+
+// runLine writes the racing line of a lap as JSON, for track map renderers and comparison tools.
+func runLine(args []string) error {
+	flags := flag.NewFlagSet("line", flag.ExitOnError)
+	step := flags.Float64("step", gttelemetry.DefaultRacingLineStep, "Distance between points in metres")
+	output := flags.String("o", "", "Write to this file instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt line [flags] <recording>[:lap]
+
+Writes the position, speed and gear of a lap at fixed distance steps as racing line JSON. The lap is a lap trace CSV
+file written by "gt trace", or a recording followed by the lap number. The fastest lap of a recording is used when no
+lap number is given.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errLineArguments
+	}
+
+	lap, err := loadLap(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	line, err := gttelemetry.NewRacingLine(lap, float32(*step))
+	if err != nil {
+		return fmt.Errorf("racing line: %w", err)
+	}
+
+	if !strings.EqualFold(filepath.Ext(flags.Arg(0)), ".csv") {
+		path, _ := splitLapSpec(flags.Arg(0))
+
+		line.CircuitID, err = recordingCircuitID(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeOutput(*output, line.WriteJSON)
+}
+
+// recordingCircuitID returns the first circuit identified in a recording, empty when none is identified.
+func recordingCircuitID(path string) (string, error) {
+	client, err := openRecording(path)
+	if err != nil {
+		return "", err
+	}
+
+	matcher := circuits.NewPathMatcher(client.CircuitDB)
+
+	for transformer, err := range client.Scan(context.Background()) {
+		if err != nil {
+			return "", fmt.Errorf("read recording: %w", err)
+		}
+
+		if !transformer.IsOnCircuit() {
+			continue
+		}
+
+		circuitID, found := matcher.Update(transformer.PositionalMapCoordinates())
+		if found {
+			return circuitID, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,101 @@
+// Command gt is a command line companion to the gt-telemetry library.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: gt <command> [flags]
+
+Commands:
+  daemon    Run the telemetry client and exporters from a configuration file
+  collector Combine the frames forwarded by the daemons of several drivers into live timing
+  compare   Compare two laps and show where time was gained or lost
+  trace     Write the trace of a lap in a recording as CSV
+  line      Write the racing line of a lap in a recording as JSON
+  corners   Print the minimum speed of every lap in a recording through each corner
+  starts    Analyse the standing starts in a recording
+  overlay   Write a subtitle or keyframe track of a recording for video overlays
+  gps       Write a recording as a GPX track or NMEA sentences
+  setups    Rank the vehicles and setups driven on each circuit over a set of recordings
+  pitloss   Measure the time lost to each pit stop in a recording
+  stints    Split a recording into stints at each pit stop
+  inputs    Summarise the throttle, brake and steering usage of a recording
+  boost     Measure how quickly the turbo spools in each gear of a recording
+  heatmap   Draw the positions visited in a recording as a PNG heatmap
+  scrub     Step through the frames of a recording in an interactive terminal UI
+  markers   List or add the markers of a recording
+  convert   Re-encode a recording in another telemetry format
+  upload    Upload recordings to S3 compatible storage
+  status    Print a single line status of the vehicle for chat bots and stream titles
+  schema    Print the JSON Schemas of the JSON documents, or validate documents against them
+
+Run "gt <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "daemon":
+		err = runDaemon(os.Args[2:])
+	case "collector":
+		err = runCollector(os.Args[2:])
+	case "compare":
+		err = runCompare(os.Args[2:])
+	case "trace":
+		err = runTrace(os.Args[2:])
+	case "line":
+		err = runLine(os.Args[2:])
+	case "corners":
+		err = runCorners(os.Args[2:])
+	case "starts":
+		err = runStarts(os.Args[2:])
+	case "overlay":
+		err = runOverlay(os.Args[2:])
+	case "gps":
+		err = runGPS(os.Args[2:])
+	case "setups":
+		err = runSetups(os.Args[2:])
+	case "pitloss":
+		err = runPitLoss(os.Args[2:])
+	case "stints":
+		err = runStints(os.Args[2:])
+	case "inputs":
+		err = runInputs(os.Args[2:])
+	case "boost":
+		err = runBoost(os.Args[2:])
+	case "heatmap":
+		err = runHeatmap(os.Args[2:])
+	case "scrub":
+		err = runScrub(os.Args[2:])
+	case "markers":
+		err = runMarkers(os.Args[2:])
+	case "convert":
+		err = runConvert(os.Args[2:])
+	case "upload":
+		err = runUpload(os.Args[2:])
+	case "status":
+		err = runStatus(os.Args[2:])
+	case "schema":
+		err = runSchema(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var errMarkersArguments = errors.New("markers requires a recording")
+
+// markerDetails is a marker with the state of the frame it marks.
+type markerDetails struct {
+	gttelemetry.ReplayMarker
+
+	Elapsed        string  `json:"elapsed"`
+	Lap            int16   `json:"lap"`
+	GroundSpeedKPH float32 `json:"groundSpeedKPH"`
+}
+
+// runMarkers lists the markers of a recording, or adds a marker to it.
+func runMarkers(args []string) error {
+	flags := flag.NewFlagSet("markers", flag.ExitOnError)
+	label := flags.String("add", "", "Add a marker with this label instead of listing the markers")
+	sequenceID := flags.Uint("sequence", 0, "Sequence ID of the frame to mark with -add")
+	asJSON := flags.Bool("json", false, "Write the markers as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt markers [flags] <recording>
+
+Lists the markers of a recording with the time, lap and speed of each marked frame, or adds a marker. Markers are
+saved alongside the recording with the `+gttelemetry.RecordingMarkersSuffix+` suffix and can also be added in gt scrub.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errMarkersArguments
+	}
+
+	if *label != "" {
+		return gttelemetry.AddRecordingMarker(flags.Arg(0), gttelemetry.Marker{
+			SequenceID: uint32(*sequenceID), //nolint:gosec // sequence IDs are 32 bit
+			Label:      *label,
+		})
+	}
+
+	client, err := openRecording(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	replay, err := client.OpenReplay()
+	if err != nil {
+		return fmt.Errorf("read recording: %w", err)
+	}
+
+	markers := make([]markerDetails, 0, len(replay.Markers()))
+
+	for _, marker := range replay.Markers() {
+		details := markerDetails{ReplayMarker: marker}
+
+		if marker.Frame >= 0 {
+			transformer, err := replay.Seek(marker.Frame)
+			if err != nil {
+				return err
+			}
+
+			details.Elapsed = formatLaptime(replay.Elapsed(marker.Frame))
+			details.Lap = transformer.CurrentLap()
+			details.GroundSpeedKPH = transformer.GroundSpeedKPH()
+		}
+
+		markers = append(markers, details)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(markers)
+		if err != nil {
+			return fmt.Errorf("encode markers: %w", err)
+		}
+
+		return nil
+	}
+
+	return printMarkers(os.Stdout, markers)
+}
+
+// printMarkers prints a table of markers.
+func printMarkers(w io.Writer, markers []markerDetails) error {
+	if len(markers) == 0 {
+		fmt.Fprintln(w, "No markers found")
+
+		return nil
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(table, "Time\tFrame\tSequence\tLap\tSpeed (km/h)\tLabel")
+
+	for _, marker := range markers {
+		if marker.Frame < 0 {
+			fmt.Fprintf(table, "-\t-\t%d\t-\t-\t%s\n", marker.SequenceID, marker.Label)
+
+			continue
+		}
+
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%.1f\t%s\n",
+			marker.Elapsed, marker.Frame+1, marker.SequenceID, marker.Lap, marker.GroundSpeedKPH, marker.Label)
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// mqttKeepAlive is the keep alive interval sent to the broker, a ping is sent at half this interval. A broker that
+	// sends nothing for this long, not even a response to a ping, is treated as lost.
+	mqttKeepAlive = 60 * time.Second
+	// mqttTimeout is how long connecting to the broker and writing a packet may take.
+	mqttTimeout = 10 * time.Second
+)
+
+const (
+	mqttPacketConnect    byte = 0x10
+	mqttPacketConnAck    byte = 0x20
+	mqttPacketPublish    byte = 0x30
+	mqttPacketPingReq    byte = 0xC0
+	mqttPacketDisconnect byte = 0xE0
+
+	mqttFlagRetain       byte = 0x01
+	mqttFlagCleanSession byte = 0x02
+	mqttFlagPassword     byte = 0x40
+	mqttFlagUsername     byte = 0x80
+)
+
+var (
+	errMQTTConnectionRefused = errors.New("MQTT connection refused")
+	errMQTTConnectionLost    = errors.New("MQTT connection lost")
+)
+
+// mqttPublisher is a minimal MQTT 3.1.1 client that publishes messages at QoS 0, which is all that is needed to feed
+// Home Assistant.
+type mqttPublisher struct {
+	conn net.Conn
+	mu   sync.Mutex
+	done chan struct{}
+	// lost is closed once the connection to the broker is lost
+	lost chan struct{}
+}
+
+// dialMQTT connects to an MQTT broker at a host:port address.
+func dialMQTT(address, clientID, username, password string) (*mqttPublisher, error) {
+	conn, err := net.DialTimeout("tcp", address, mqttTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("connect to MQTT broker: %w", err)
+	}
+
+	flags := mqttFlagCleanSession
+	payload := mqttString(clientID)
+
+	if username != "" {
+		flags |= mqttFlagUsername
+		payload = append(payload, mqttString(username)...)
+	}
+
+	if password != "" {
+		flags |= mqttFlagPassword
+		payload = append(payload, mqttString(password)...)
+	}
+
+	variableHeader := append(mqttString("MQTT"), 0x04, flags)
+	variableHeader = binary.BigEndian.AppendUint16(variableHeader, uint16(mqttKeepAlive.Seconds()))
+
+	reader := bufio.NewReader(conn)
+
+	_ = conn.SetDeadline(time.Now().Add(mqttTimeout))
+
+	err = writeMQTTPacket(conn, mqttPacketConnect, append(variableHeader, payload...))
+	if err == nil {
+		err = readMQTTConnAck(reader)
+	}
+
+	if err != nil {
+		conn.Close()
+
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	publisher := &mqttPublisher{
+		conn: conn,
+		done: make(chan struct{}),
+		lost: make(chan struct{}),
+	}
+
+	go publisher.keepAlive()
+	go publisher.receive(reader)
+
+	return publisher, nil
+}
+
+// Publish sends a message at QoS 0.
+func (p *mqttPublisher) Publish(topic string, payload []byte, retain bool) error {
+	flags := mqttPacketPublish
+	if retain {
+		flags |= mqttFlagRetain
+	}
+
+	select {
+	case <-p.lost:
+		return errMQTTConnectionLost
+	default:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_ = p.conn.SetWriteDeadline(time.Now().Add(mqttTimeout))
+
+	return writeMQTTPacket(p.conn, flags, append(mqttString(topic), payload...))
+}
+
+// Lost returns a channel that is closed once the connection to the broker is lost.
+func (p *mqttPublisher) Lost() <-chan struct{} {
+	return p.lost
+}
+
+// Close disconnects from the broker.
+func (p *mqttPublisher) Close() error {
+	close(p.done)
+
+	p.mu.Lock()
+	_ = writeMQTTPacket(p.conn, mqttPacketDisconnect, nil)
+	p.mu.Unlock()
+
+	return p.conn.Close()
+}
+
+// receive reads from the broker until the connection is lost. Responses to pings are the only packets expected, so
+// a broker that sends nothing for the keep alive interval has stopped responding.
+func (p *mqttPublisher) receive(reader *bufio.Reader) {
+	defer close(p.lost)
+
+	for {
+		_ = p.conn.SetReadDeadline(time.Now().Add(mqttKeepAlive))
+
+		_, err := reader.Discard(1)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// keepAlive pings the broker so that the connection is not dropped while nothing is published.
+func (p *mqttPublisher) keepAlive() {
+	ticker := time.NewTicker(mqttKeepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			_ = p.conn.SetWriteDeadline(time.Now().Add(mqttTimeout))
+			_ = writeMQTTPacket(p.conn, mqttPacketPingReq, nil)
+			p.mu.Unlock()
+		}
+	}
+}
+
+// readMQTTConnAck reads the broker's response to a connect packet.
+func readMQTTConnAck(reader *bufio.Reader) error {
+	packet := make([]byte, 4)
+
+	_, err := io.ReadFull(reader, packet)
+	if err != nil {
+		return fmt.Errorf("read MQTT connection acknowledgement: %w", err)
+	}
+
+	if packet[0] != mqttPacketConnAck || packet[3] != 0 {
+		return fmt.Errorf("%w: return code %d", errMQTTConnectionRefused, packet[3])
+	}
+
+	return nil
+}
+
+// writeMQTTPacket writes a packet with the given fixed header byte and body.
+func writeMQTTPacket(w io.Writer, header byte, body []byte) error {
+	packet := []byte{header}
+
+	// The remaining length is encoded seven bits at a time, least significant first
+	length := len(body)
+	for {
+		encoded := byte(length % 128) //nolint:gosec // always less than 128
+		length /= 128
+
+		if length > 0 {
+			encoded |= 0x80
+		}
+
+		packet = append(packet, encoded)
+
+		if length == 0 {
+			break
+		}
+	}
+
+	_, err := w.Write(append(packet, body...))
+	if err != nil {
+		return fmt.Errorf("write MQTT packet: %w", err)
+	}
+
+	return nil
+}
+
+// mqttString encodes a length prefixed UTF-8 string.
+func mqttString(value string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(value))), value...) //nolint:gosec // MQTT strings are short
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
+	"github.com/zetetos/gt-telemetry/v2/pkg/process"
+)
+
+var (
+	errOverlayArguments = errors.New("overlay requires a recording")
+	errOverlayFormat    = errors.New("unknown overlay format")
+	errOverlayPrecision = errors.New("invalid precision, expected channel=places")
+)
+
+// runOverlay writes a subtitle, keyframe or overlay tool CSV track of a recording, for overlaying telemetry onto
+// gameplay video.
+func runOverlay(args []string) error {
+	flags := flag.NewFlagSet("overlay", flag.ExitOnError)
+	format := flags.String("format", "srt", "Output format: srt, ass, json, racerender or telemetry-overlay")
+	interval := flags.Duration("interval", gttelemetry.DefaultOverlayInterval, "Minimum time between samples")
+	offset := flags.Duration("offset", 0, "Time added to the recording to line it up with the video, may be negative")
+	output := flags.String("o", "", "Write to this file instead of stdout")
+	precisionList := flags.String("precision", "", "Decimal places of the CSV columns by channel, e.g. engineRPM=0,groundSpeedKPH=1")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt overlay [flags] <recording>
+
+Writes the speed, gear and lap time of a recording as SRT or ASS subtitles, or as JSON keyframes, timed from the
+first frame of the recording.
+
+The racerender and telemetry-overlay formats write every frame as CSV for those tools, with GPS positions
+synthesised from the map coordinates around the real location of the circuit. Line the data up with the video in the tool, -interval and -offset are not
+used. Numbers are always written with a "." decimal separator, set the decimal places of a column with -precision.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errOverlayArguments
+	}
+
+	overlayOpts := gttelemetry.OverlayOptions{
+		Interval: *interval,
+		Offset:   *offset,
+	}
+
+	switch *format {
+	case "srt", "ass":
+		// Subtitles are written as the recording is read, so long sessions are not held in memory
+		return writeOutput(*output, func(w io.Writer) error {
+			stream, err := gttelemetry.NewOverlayStream(w, *format, overlayOpts)
+			if err != nil {
+				return err
+			}
+
+			err = scanRecording(flags.Arg(0), stream.Update)
+			if err != nil {
+				return err
+			}
+
+			return stream.Close()
+		})
+	case "json":
+	default:
+		preset, err := gttelemetry.ParseVideoCSVPreset(*format)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errOverlayFormat, *format)
+		}
+
+		anchor, err := recordingAnchor(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		precision, err := parsePrecision(*precisionList)
+		if err != nil {
+			return err
+		}
+
+		return writeOutput(*output, func(w io.Writer) error {
+			writer := gttelemetry.NewVideoCSVWriter(w, preset, anchor)
+			writer.SetPrecision(precision)
+
+			err := scanRecording(flags.Arg(0), writer.Write)
+			if err != nil {
+				return err
+			}
+
+			return writer.Flush()
+		})
+	}
+
+	track := gttelemetry.NewOverlayTrack(overlayOpts)
+
+	err := scanRecording(flags.Arg(0), func(t *gttelemetry.Transformer) error {
+		track.Update(t)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(*output, track.WriteJSON)
+}
+
+// openRecording creates a client that reads a recording, decrypting encrypted recordings with the password in
+// $GT_RECORDING_PASSWORD.
+func openRecording(path string) (*gttelemetry.Client, error) {
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:            "file://" + path,
+		LogLevel:          "error",
+		RecordingPassword: os.Getenv("GT_RECORDING_PASSWORD"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open recording: %w", err)
+	}
+
+	return client, nil
+}
+
+// scanRecording reads every frame of a recording, passing each one to update.
+func scanRecording(path string, update func(t *gttelemetry.Transformer) error) error {
+	_, err := process.FileWithOptions(context.Background(), path, process.Options{
+		Password: os.Getenv("GT_RECORDING_PASSWORD"),
+		Workers:  -1,
+	}, process.VisitorFunc(update))
+
+	return err
+}
+
+// writeOutput writes to the file at path, or to stdout when path is empty.
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path != "" {
+		return writeFile(path, write)
+	}
+
+	return write(os.Stdout)
+}
+
+// recordingAnchor returns the real-world location of the first circuit identified in a recording, or
+// geo.DefaultAnchor when the circuit is fictional or cannot be identified.
+func recordingAnchor(path string) (geo.Anchor, error) {
+	client, err := openRecording(path)
+	if err != nil {
+		return geo.Anchor{}, err
+	}
+
+	for transformer, err := range client.Scan(context.Background()) {
+		if err != nil {
+			return geo.Anchor{}, fmt.Errorf("read recording: %w", err)
+		}
+
+		if !transformer.IsOnCircuit() {
+			continue
+		}
+
+		anchor, found := client.CircuitDB.GetAnchorAtCoordinate(transformer.PositionalMapCoordinates())
+		if found {
+			return anchor, nil
+		}
+	}
+
+	return geo.DefaultAnchor, nil
+}
+
+// parsePrecision parses a comma separated list of channel=places pairs.
+func parsePrecision(list string) (gttelemetry.ChannelPrecision, error) {
+	precision := gttelemetry.ChannelPrecision{}
+
+	if list == "" {
+		return precision, nil
+	}
+
+	for pair := range strings.SplitSeq(list, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+
+		places, err := strconv.Atoi(value)
+		if !ok || err != nil || places < 0 || places > gttelemetry.MaxChannelPrecision {
+			return nil, fmt.Errorf("%w: %s", errOverlayPrecision, pair)
+		}
+
+		precision[strings.TrimSpace(name)] = places
+	}
+
+	return precision, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+)
+
+var errPitLossArguments = errors.New("pitloss requires a recording")
+
+// runPitLoss measures the time lost to each pit stop in a recording and compares it with the pit lane loss of the
+// circuit in the inventory.
+func runPitLoss(args []string) error {
+	flags := flag.NewFlagSet("pitloss", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt pitloss <recording>
+
+Measures the time lost to each pit stop, from the laps either side of the stop compared with the median lap, and
+compares the pit lane loss with the pitLossSeconds of the circuit in the inventory.
+`)
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errPitLossArguments
+	}
+
+	client, err := openRecording(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var (
+		history   = gttelemetry.NewLapHistory()
+		detector  = gttelemetry.NewPitStopDetector()
+		matcher   = circuits.NewPathMatcher(client.CircuitDB)
+		circuitID string
+	)
+
+	for transformer, err := range client.Scan(context.Background()) {
+		if err != nil {
+			return fmt.Errorf("read recording: %w", err)
+		}
+
+		history.Update(transformer)
+		detector.Update(transformer)
+
+		if circuitID == "" && transformer.IsOnCircuit() {
+			circuitID, _ = matcher.Update(transformer.PositionalMapCoordinates())
+		}
+	}
+
+	var expected time.Duration
+
+	if seconds, found := client.CircuitDB.PitLossSeconds(circuitID); found {
+		expected = time.Duration(seconds * float64(time.Second))
+	}
+
+	return printPitLoss(os.Stdout, circuitID, expected, detector.Stops(), history.Snapshots())
+}
+
+// printPitLoss prints the measured loss of each pit stop.
+func printPitLoss(w io.Writer, circuitID string, expected time.Duration, stops []gttelemetry.PitStop, snapshots []gttelemetry.LapSnapshot) error {
+	if circuitID == "" {
+		circuitID = "unknown"
+	}
+
+	expectedText := "not measured"
+	if expected > 0 {
+		expectedText = fmt.Sprintf("%.1fs", expected.Seconds())
+	}
+
+	fmt.Fprintf(w, "Circuit: %s\nExpected pit lane loss: %s\n\n", circuitID, expectedText)
+
+	if len(stops) == 0 {
+		fmt.Fprintln(w, "No pit stops found")
+
+		return nil
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(table, "Lap\tTotal (s)\tStationary (s)\tPit lane (s)\tDelta (s)\t")
+
+	for _, stop := range stops {
+		comparison, ok := gttelemetry.ComparePitLoss(stop, snapshots, expected)
+		if !ok {
+			fmt.Fprintf(table, "%d\t-\t%.1f\t-\t-\t\n", stop.Lap, stop.Duration.Seconds())
+
+			continue
+		}
+
+		delta := "-"
+		if expected > 0 {
+			delta = formatDelta(comparison.Delta)
+		}
+
+		fmt.Fprintf(table, "%d\t%.1f\t%.1f\t%.1f\t%s\t\n",
+			comparison.Lap,
+			comparison.Total.Seconds(),
+			comparison.Stationary.Seconds(),
+			comparison.PitLane.Seconds(),
+			delta,
+		)
+	}
+
+	return table.Flush()
+}
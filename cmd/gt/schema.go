@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/schemas"
+)
+
+var errInvalidDocuments = errors.New("documents do not match the schema")
+
+// runSchema lists, prints or writes the JSON Schemas of the published JSON documents, or validates documents against
+// one of them.
+func runSchema(args []string) error {
+	flags := flag.NewFlagSet("schema", flag.ExitOnError)
+	outputDir := flags.String("o", "", "Write every schema to this directory as <name>.json")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt schema [flags] [name [document...]]
+
+Lists the JSON Schemas of the JSON documents gt-telemetry reads and writes without a name, prints the schema with
+the name, or validates each document against it.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if *outputDir != "" {
+		return writeSchemas(*outputDir)
+	}
+
+	if flags.NArg() == 0 {
+		for _, name := range schemas.Names() {
+			fmt.Println(name)
+		}
+
+		return nil
+	}
+
+	name := flags.Arg(0)
+
+	schema, err := schemas.Schema(name)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 1 {
+		_, err = os.Stdout.Write(schema)
+		if err != nil {
+			return fmt.Errorf("write schema: %w", err)
+		}
+
+		return nil
+	}
+
+	invalid := 0
+
+	for _, path := range flags.Args()[1:] {
+		document, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read document: %w", err)
+		}
+
+		err = schemas.Validate(name, document)
+		if err != nil {
+			fmt.Printf("%s: %s\n", path, err)
+
+			invalid++
+
+			continue
+		}
+
+		fmt.Printf("%s: valid\n", path)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%w: %d of %d", errInvalidDocuments, invalid, flags.NArg()-1)
+	}
+
+	return nil
+}
+
+// writeSchemas writes every schema to the directory.
+func writeSchemas(dir string) error {
+	err := os.MkdirAll(dir, 0o755) //nolint:gosec // schemas are published to be read by anyone
+	if err != nil {
+		return fmt.Errorf("create schema directory: %w", err)
+	}
+
+	for _, name := range schemas.Names() {
+		schema, err := schemas.Schema(name)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(filepath.Join(dir, name+".json"), schema, 0o644) //nolint:gosec // as above
+		if err != nil {
+			return fmt.Errorf("write schema: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+const (
+	// scrubTimelineWidth is the width of the timeline in characters.
+	scrubTimelineWidth = 72
+	// scrubHexBytes is the number of packet bytes shown on each line of the hex dump.
+	scrubHexBytes = 16
+
+	// Terminal control sequences
+	enterAlternateScreen = "\033[?1049h\033[?25l"
+	leaveAlternateScreen = "\033[?25h\033[?1049l"
+	cursorHome           = "\033[H"
+	clearLine            = "\033[K"
+	clearBelow           = "\033[J"
+)
+
+var (
+	errScrubArguments = errors.New("scrub requires a recording")
+	errEmptyRecording = errors.New("recording has no frames")
+	errUnknownChannel = errors.New("unknown channel")
+)
+
+// scrubKeys maps the bytes sent by a key press to the number of frames it steps. Keys that do not step are handled
+// separately.
+var scrubKeys = map[string]int{ //nolint:gochecknoglobals // Static lookup table
+	"\033[C": 1, "l": 1, ".": 1,
+	"\033[D": -1, "h": -1, ",": -1,
+	"\033[A": gttelemetry.FrameRate, "k": gttelemetry.FrameRate,
+	"\033[B": -gttelemetry.FrameRate, "j": -gttelemetry.FrameRate,
+	"\033[5~": 10 * gttelemetry.FrameRate, "]": 10 * gttelemetry.FrameRate,
+	"\033[6~": -10 * gttelemetry.FrameRate, "[": -10 * gttelemetry.FrameRate,
+}
+
+// runScrub opens a recording in an interactive terminal UI for stepping through its frames.
+func runScrub(args []string) error {
+	flags := flag.NewFlagSet("scrub", flag.ExitOnError)
+	channelList := flags.String("channels", "", "Comma separated channels to show, defaults to every channel")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt scrub [flags] <recording>
+
+Steps through the frames of a recording with a timeline and readouts of every channel.
+
+Keys:
+  space          play or pause
+  left, right    step one frame (also h, l or comma, full stop)
+  down, up       step one second (also j, k)
+  pgdn, pgup     step ten seconds (also [, ])
+  home, end      first or last frame (also g, G)
+  m              mark the frame with a label, saved alongside the recording
+  n, p           next or previous marker
+  x              show or hide the hex dump of the packet
+  q              quit
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errScrubArguments
+	}
+
+	channels := gttelemetry.ChannelNames()
+	if *channelList != "" {
+		channels = strings.Split(*channelList, ",")
+	}
+
+	client, err := openRecording(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	replay, err := client.OpenReplay()
+	if err != nil {
+		return fmt.Errorf("read recording: %w", err)
+	}
+
+	if replay.Len() == 0 {
+		return errEmptyRecording
+	}
+
+	for _, channel := range channels {
+		if _, ok := client.Telemetry.Channel(channel); !ok {
+			return fmt.Errorf("%w: %q", errUnknownChannel, channel)
+		}
+	}
+
+	restore, err := makeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+
+	defer restore()
+
+	fmt.Fprint(os.Stdout, enterAlternateScreen)
+	defer fmt.Fprint(os.Stdout, leaveAlternateScreen)
+
+	scrubber := &scrubber{replay: replay, path: flags.Arg(0), channels: channels, out: os.Stdout}
+
+	return scrubber.run(readKeys(os.Stdin))
+}
+
+// scrubber holds the state of the scrub terminal UI.
+type scrubber struct {
+	replay   *gttelemetry.Replay
+	path     string
+	channels []string
+	out      io.Writer
+	playing  bool
+	hex      bool
+	// labelling is true while the label of a new marker is typed
+	labelling bool
+	label     string
+	status    string
+}
+
+// run handles key presses and plays the recording until quit.
+func (s *scrubber) run(keys <-chan string) error {
+	ticker := time.NewTicker(gttelemetry.FrameInterval)
+	defer ticker.Stop()
+
+	transformer, err := s.replay.Seek(0)
+	if err != nil {
+		return err
+	}
+
+	for {
+		s.render(transformer)
+
+		select {
+		case key, ok := <-keys:
+			if !ok || key == "\x03" || (key == "q" && !s.labelling) {
+				return nil
+			}
+
+			if s.labelling {
+				s.editLabel(key)
+
+				continue
+			}
+
+			transformer, err = s.handle(key)
+		case <-ticker.C:
+			if !s.playing {
+				continue
+			}
+
+			transformer, err = s.replay.Step(1)
+			s.playing = s.replay.Position() < s.replay.Len()-1
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle applies a key press and returns the frame to show.
+func (s *scrubber) handle(key string) (*gttelemetry.Transformer, error) {
+	if frames, ok := scrubKeys[key]; ok {
+		s.playing = false
+
+		return s.replay.Step(frames)
+	}
+
+	s.status = ""
+
+	switch key {
+	case " ":
+		s.playing = !s.playing && s.replay.Position() < s.replay.Len()-1
+	case "x":
+		s.hex = !s.hex
+	case "m":
+		s.playing = false
+		s.labelling = true
+		s.label = ""
+	case "n", "p":
+		s.playing = false
+
+		return s.replay.Seek(s.adjacentMarker(key == "n"))
+	case "\033[H", "\033[1~", "g":
+		s.playing = false
+
+		return s.replay.Seek(0)
+	case "\033[F", "\033[4~", "G":
+		s.playing = false
+
+		return s.replay.Seek(s.replay.Len() - 1)
+	}
+
+	return s.replay.Seek(s.replay.Position())
+}
+
+// editLabel applies a key press to the label of a new marker, saving the marker on enter and discarding it on
+// escape.
+func (s *scrubber) editLabel(key string) {
+	switch key {
+	case "\r", "\n":
+		s.labelling = false
+
+		marker, err := s.replay.AddMarker(s.label)
+		if err != nil {
+			s.status = err.Error()
+		} else {
+			s.status = fmt.Sprintf("Marked frame %d as %q", marker.Frame+1, marker.Label)
+		}
+	case "\033":
+		s.labelling = false
+	case "\x7f", "\b":
+		runes := []rune(s.label)
+		s.label = string(runes[:max(len(runes)-1, 0)])
+	default:
+		if !strings.HasPrefix(key, "\033") {
+			s.label += key
+		}
+	}
+}
+
+// adjacentMarker returns the frame of the next or previous marker, or the current frame when there is none.
+func (s *scrubber) adjacentMarker(next bool) int {
+	position := s.replay.Position()
+	markers := s.replay.Markers()
+
+	if next {
+		for _, marker := range markers {
+			if marker.Frame > position {
+				return marker.Frame
+			}
+		}
+	} else {
+		for _, marker := range slices.Backward(markers) {
+			if marker.Frame >= 0 && marker.Frame < position {
+				return marker.Frame
+			}
+		}
+	}
+
+	return position
+}
+
+// render draws the timeline, the frame details and the channel readouts of the current frame.
+func (s *scrubber) render(t *gttelemetry.Transformer) {
+	var screen bytes.Buffer
+
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&screen, format, args...)
+		screen.WriteString(clearLine + "\n")
+	}
+
+	position := s.replay.Position()
+	state := "paused"
+
+	if s.playing {
+		state = "playing"
+	}
+
+	screen.WriteString(cursorHome)
+	line("%s  [%s]", s.path, state)
+	line("%s", s.timeline(position))
+	line("Frame %d/%d  %s / %s  sequence %d  format %s  %d bytes",
+		position+1, s.replay.Len(),
+		formatLaptime(s.replay.Elapsed(position)), formatLaptime(s.replay.Duration()),
+		t.SequenceID(), t.TelemetryFormat(), len(s.replay.Packet(position)))
+	line("Flags: %s", activeFlags(t.Flags()))
+	line("Markers: %s", s.markersAt(position))
+	line("")
+
+	rows := (len(s.channels) + 1) / 2
+	for row := range rows {
+		left := s.readout(t, row)
+		right := ""
+
+		if row+rows < len(s.channels) {
+			right = s.readout(t, row+rows)
+		}
+
+		line("%-52s%s", left, right)
+	}
+
+	if s.hex {
+		line("")
+
+		packet := s.replay.Packet(position)
+		for offset := 0; offset < len(packet); offset += scrubHexBytes {
+			line("%04x  % x", offset, packet[offset:min(offset+scrubHexBytes, len(packet))])
+		}
+	}
+
+	line("")
+
+	switch {
+	case s.labelling:
+		line("Marker label: %s_  (enter to save, esc to cancel)", s.label)
+	case s.status != "":
+		line("%s", s.status)
+	default:
+		line("space play/pause  ←/→ frame  ↓/↑ second  pgdn/pgup 10s  home/end  m mark  n/p marker  x hex  q quit")
+	}
+	screen.WriteString(clearBelow)
+
+	_, _ = s.out.Write(screen.Bytes())
+}
+
+// readout formats the value of a channel.
+func (s *scrubber) readout(t *gttelemetry.Transformer, index int) string {
+	value, _ := t.Channel(s.channels[index])
+
+	return fmt.Sprintf("%-36s %14.3f", s.channels[index], value)
+}
+
+// markersAt lists the labels of the markers on a frame.
+func (s *scrubber) markersAt(position int) string {
+	var labels []string
+
+	for _, marker := range s.replay.Markers() {
+		if marker.Frame == position {
+			labels = append(labels, marker.Label)
+		}
+	}
+
+	if len(labels) == 0 {
+		return "none"
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// timeline draws a bar with the markers of the recording as stars and the position of the frame as a bar.
+func (s *scrubber) timeline(position int) string {
+	column := func(frame int) int {
+		if s.replay.Len() <= 1 {
+			return 0
+		}
+
+		return frame * (scrubTimelineWidth - 1) / (s.replay.Len() - 1)
+	}
+
+	bar := []byte(strings.Repeat("-", scrubTimelineWidth))
+
+	for _, marker := range s.replay.Markers() {
+		if marker.Frame >= 0 {
+			bar[column(marker.Frame)] = '*'
+		}
+	}
+
+	bar[column(position)] = '|'
+
+	return "[" + string(bar) + "]"
+}
+
+// activeFlags lists the names of the flags that are set.
+func activeFlags(flags gttelemetry.Flags) string {
+	named := []struct {
+		name string
+		set  bool
+	}{
+		{"live", flags.Live},
+		{"paused", flags.GamePaused},
+		{"loading", flags.Loading},
+		{"inGear", flags.InGear},
+		{"hasTurbo", flags.HasTurbo},
+		{"revLimiter", flags.RevLimiterAlert},
+		{"handbrake", flags.HandbrakeActive},
+		{"headlights", flags.HeadlightsActive},
+		{"highBeam", flags.HighBeamActive},
+		{"lowBeam", flags.LowBeamActive},
+		{"asm", flags.ASMActive},
+		{"tcs", flags.TCSActive},
+		{"flag13", flags.Flag13},
+		{"flag14", flags.Flag14},
+		{"flag15", flags.Flag15},
+		{"flag16", flags.Flag16},
+	}
+
+	names := make([]string, 0, len(named))
+
+	for _, flag := range named {
+		if flag.set {
+			names = append(names, flag.name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, " ")
+}
+
+// readKeys sends each key press read from the terminal, closing the channel when the input ends.
+func readKeys(r io.Reader) <-chan string {
+	keys := make(chan string)
+
+	go func() {
+		defer close(keys)
+
+		buffer := make([]byte, 8)
+
+		for {
+			n, err := r.Read(buffer)
+			if err != nil {
+				return
+			}
+
+			keys <- string(buffer[:n])
+		}
+	}()
+
+	return keys
+}
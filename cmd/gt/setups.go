@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+)
+
+var errSetupsArguments = errors.New("setups requires recordings or a directory of recordings")
+
+// runSetups compares the vehicles and setups driven on each circuit over a set of recordings.
+func runSetups(args []string) error {
+	flags := flag.NewFlagSet("setups", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Write the results of each circuit as JSON")
+	circuitID := flags.String("circuit", "", "Only compare the laps of the circuit with this ID")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt setups [flags] <recording or directory>...
+
+Ranks the vehicles and setups driven on each circuit by their best lap, with the time of each third of the lap and
+the top speed, to choose the vehicle for a race. Directories are searched for gtr and gtz recordings, such as the
+directory of automatic recordings.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+
+		return errSetupsArguments
+	}
+
+	paths, err := recordingPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	if err != nil {
+		return fmt.Errorf("load circuits: %w", err)
+	}
+
+	comparison := gttelemetry.NewSetupComparison(db)
+
+	for _, path := range paths {
+		err = scanRecording(path, func(t *gttelemetry.Transformer) error {
+			comparison.Update(t)
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	circuitIDs := comparison.Circuits()
+	if *circuitID != "" {
+		circuitIDs = []string{*circuitID}
+	}
+
+	if *asJSON {
+		results := make(map[string][]gttelemetry.SetupResult, len(circuitIDs))
+		for _, id := range circuitIDs {
+			results[id] = comparison.Results(id)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(results)
+		if err != nil {
+			return fmt.Errorf("encode setup results: %w", err)
+		}
+
+		return nil
+	}
+
+	printSetups(os.Stdout, db, comparison, circuitIDs)
+
+	return nil
+}
+
+// recordingPaths expands directories in the arguments to the recordings they hold.
+func recordingPaths(args []string) ([]string, error) {
+	var paths []string
+
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("open recording: %w", err)
+		}
+
+		if !info.IsDir() {
+			paths = append(paths, arg)
+
+			continue
+		}
+
+		for _, pattern := range []string{"*.gtr", "*.gtz"} {
+			matches, err := filepath.Glob(filepath.Join(arg, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("list recordings: %w", err)
+			}
+
+			paths = append(paths, matches...)
+		}
+	}
+
+	return paths, nil
+}
+
+// printSetups prints a table of the results of each circuit, fastest first.
+func printSetups(w io.Writer, db *circuits.CircuitDB, comparison *gttelemetry.SetupComparison, circuitIDs []string) {
+	if len(circuitIDs) == 0 {
+		fmt.Fprintln(w, "No laps found on known circuits")
+
+		return
+	}
+
+	for i, circuitID := range circuitIDs {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		name := db.DisplayVariation(circuitID)
+		if name == "" {
+			name = circuitID
+		}
+
+		fmt.Fprintln(w, name)
+
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "Vehicle\tSetup\tLaps\tBest lap\tGap (s)\tS1 (s)\tS2 (s)\tS3 (s)\tOptimal\tTop speed (km/h)")
+
+		for _, result := range comparison.Results(circuitID) {
+			vehicle := result.Vehicle
+			if vehicle == "" {
+				vehicle = fmt.Sprintf("Car %d", result.VehicleID)
+			}
+
+			sectors := make([]string, len(result.BestLapSectors))
+			for j, sector := range result.BestLapSectors {
+				sectors[j] = fmt.Sprintf("%.3f", sector.Seconds())
+			}
+
+			fmt.Fprintf(table, "%s\t%.8s\t%d\t%s\t%s\t%s\t%s\t%.1f\n",
+				vehicle, result.Setup, result.Laps, formatLaptime(result.BestLap), formatDelta(result.Gap),
+				strings.Join(sectors, "\t"), formatLaptime(result.OptimalLap()), result.TopSpeedKPH)
+		}
+
+		_ = table.Flush()
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var errStartsArguments = errors.New("starts requires a recording")
+
+// runStarts analyses the standing starts in a recording.
+func runStarts(args []string) error {
+	flags := flag.NewFlagSet("starts", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Write the full reports, including the trace of every frame, as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt starts [flags] <recording>
+
+Prints the reaction time, time to 100km/h and wheel slip of each standing start in the first 5 seconds after lights
+out.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errStartsArguments
+	}
+
+	analyser := gttelemetry.NewStartAnalyser()
+
+	err := scanRecording(flags.Arg(0), func(t *gttelemetry.Transformer) error {
+		analyser.Update(t)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(analyser.Reports())
+		if err != nil {
+			return fmt.Errorf("encode start reports: %w", err)
+		}
+
+		return nil
+	}
+
+	printStarts(os.Stdout, analyser.Reports())
+
+	return nil
+}
+
+// printStarts prints a summary of each start report.
+func printStarts(w io.Writer, reports []gttelemetry.StartReport) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No standing starts found")
+
+		return
+	}
+
+	for i, report := range reports {
+		timeTo100 := "not reached"
+		if report.TimeTo100KPH > 0 {
+			timeTo100 = fmt.Sprintf("%.2fs", report.TimeTo100KPH.Seconds())
+		}
+
+		slip := report.DrivenSlip()
+
+		fmt.Fprintf(w, "Start %d\n", i+1)
+		fmt.Fprintf(w, "  Reaction time:   %.3fs\n", report.ReactionTime.Seconds())
+		fmt.Fprintf(w, "  0-100 km/h:      %s\n", timeTo100)
+		fmt.Fprintf(w, "  Speed after 5s:  %.1f km/h\n", report.SpeedKPH)
+		fmt.Fprintf(w, "  Wheel slip:      %.0f%% peak, %.0f%% mean (%s axle)\n", slip.Peak*100, slip.Mean*100, report.DrivenAxle)
+	}
+}
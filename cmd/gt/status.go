@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+// statusPollInterval is how often the telemetry is checked for a new status line.
+const statusPollInterval = 50 * time.Millisecond
+
+var errNoTelemetry = errors.New("no telemetry received")
+
+// runStatus prints a single line status of the vehicle, once or at a limited rate, for chat bots and stream titles.
+func runStatus(args []string) error {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	source := flags.String("source", "", "Telemetry source URL, defaults to discovering the console on the network")
+	follow := flags.Bool("follow", false, "Keep printing a line whenever the status changes, at most once per interval")
+	interval := flags.Duration("interval", gttelemetry.DefaultStatusInterval, "Minimum time between lines with -follow")
+	imperial := flags.Bool("imperial", false, "Show the speed in mph rather than km/h")
+	timeout := flags.Duration("timeout", 10*time.Second, "How long to wait for telemetry without -follow")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt status [flags]
+
+Prints a compact status line of gear, speed, lap and the delta of the last lap to the best lap, e.g.
+
+  Gear 4 | 187 km/h | Lap 3/10 | Last 1:31.204 (+0.412)
+
+With -follow a line is printed whenever the status changes, at most once per interval, so the output can be piped to
+a chat bot or used to update a stream title.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	log := zerolog.New(os.Stderr).Level(zerolog.WarnLevel).With().Timestamp().Logger()
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source: *source,
+		Logger: &log,
+	})
+	if err != nil {
+		return fmt.Errorf("create telemetry client: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if !*follow {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	group, ctx := errgroup.WithContext(ctx)
+	formatter := gttelemetry.NewStatusFormatter(gttelemetry.StatusOptions{Interval: *interval, Imperial: *imperial})
+
+	group.Go(func() error { return stream(ctx, client, log) })
+	group.Go(func() error {
+		defer cancel()
+
+		return printStatus(ctx, client, formatter, *follow)
+	})
+
+	return group.Wait()
+}
+
+// printStatus prints the status of the first frame received, or with follow every line of the formatter until the
+// context is cancelled.
+func printStatus(ctx context.Context, client *gttelemetry.Client, formatter *gttelemetry.StatusFormatter, follow bool) error {
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if !follow && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errNoTelemetry
+			}
+
+			return nil
+		case <-ticker.C:
+		}
+
+		if client.Telemetry.SequenceID() == 0 {
+			continue
+		}
+
+		if !follow {
+			fmt.Println(formatter.Format(client.Telemetry))
+
+			return nil
+		}
+
+		line, ok := formatter.Update(client.Telemetry)
+		if ok {
+			fmt.Println(line)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+var errStintsArguments = errors.New("stints requires a recording")
+
+// runStints splits a recording into stints at each pit stop.
+func runStints(args []string) error {
+	flags := flag.NewFlagSet("stints", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Write the stints as JSON")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt stints [flags] <recording>
+
+Splits the last session of a recording into stints at each pit stop, with the laps, average pace, fuel used and tyre
+temperature trend of each stint.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errStintsArguments
+	}
+
+	client, err := openRecording(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	tracker := gttelemetry.NewStintTracker()
+
+	for transformer, err := range client.Scan(context.Background()) {
+		if err != nil {
+			return fmt.Errorf("read recording: %w", err)
+		}
+
+		tracker.Update(transformer)
+	}
+
+	stints := tracker.StintHistory()
+	if current := tracker.CurrentStint(); current.Number > 0 {
+		stints = append(stints, current)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(stints)
+		if err != nil {
+			return fmt.Errorf("encode stints: %w", err)
+		}
+
+		return nil
+	}
+
+	return printStints(os.Stdout, stints)
+}
+
+// printStints prints a table of the stints, the last being the stint in progress when the recording ended.
+func printStints(w io.Writer, stints []gttelemetry.Stint) error {
+	if len(stints) == 0 {
+		fmt.Fprintln(w, "No stints found")
+
+		return nil
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(table, "Stint\tLaps\tFrom lap\tTo lap\tDuration\tAverage pace\tFuel used (l)\tFuel per lap (l)\tTyre trend (°C/lap)\tStop (s)\t")
+
+	for _, stint := range stints {
+		stop := "-"
+		if stint.PitStop != nil {
+			stop = fmt.Sprintf("%.1f", stint.PitStop.Duration.Seconds())
+		}
+
+		pace := "-"
+		if stint.AveragePace > 0 {
+			pace = formatLaptime(stint.AveragePace)
+		}
+
+		fmt.Fprintf(table, "%d\t%d\t%d\t%d\t%s\t%s\t%.1f\t%.2f\t%+.1f\t%s\t\n",
+			stint.Number,
+			stint.Laps,
+			stint.StartLap,
+			stint.EndLap,
+			formatLaptime(stint.Duration),
+			pace,
+			stint.FuelUsed,
+			stint.FuelPerLap,
+			stint.TyreTemperatureTrend,
+			stop,
+		)
+	}
+
+	return table.Flush()
+}
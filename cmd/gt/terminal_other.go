@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package main
+
+import "errors"
+
+var errTerminalUnsupported = errors.New("interactive terminal is not supported on this platform")
+
+func makeRaw(int) (func(), error) {
+	return nil, errTerminalUnsupported
+}
@@ -0,0 +1,31 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// makeRaw switches the terminal to reading single unechoed key presses and returns a function restoring the previous
+// mode.
+func makeRaw(fd int) (func(), error) {
+	previous, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, fmt.Errorf("get terminal attributes: %w", err)
+	}
+
+	raw := *previous
+	raw.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Iflag &^= unix.IXON | unix.ICRNL
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	err = unix.IoctlSetTermios(fd, ioctlSetTermios, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("set terminal attributes: %w", err)
+	}
+
+	return func() { _ = unix.IoctlSetTermios(fd, ioctlSetTermios, previous) }, nil
+}
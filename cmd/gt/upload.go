@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/upload"
+)
+
+// uploadPollInterval is how often the daemon checks whether a recording has started or finished.
+const uploadPollInterval = time.Second
+
+// errInvalidKeyValue indicates a metadata or tag flag that is not in the form key=value.
+var errInvalidKeyValue = errors.New("expected key=value")
+
+// keyValueFlag collects repeated key=value flags.
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for key, value := range f {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (f keyValueFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("%w: %q", errInvalidKeyValue, value)
+	}
+
+	f[key] = val
+
+	return nil
+}
+
+// runUpload uploads recordings to S3 compatible storage, continuing uploads that were interrupted.
+func runUpload(args []string) error {
+	metadata := keyValueFlag{}
+	tags := keyValueFlag{}
+
+	flags := flag.NewFlagSet("upload", flag.ExitOnError)
+	endpoint := flags.String("endpoint", "", "Base URL of the storage service, e.g. https://s3.eu-west-2.amazonaws.com")
+	region := flags.String("region", "", "Region of the bucket, defaults to us-east-1")
+	bucket := flags.String("bucket", "", "Bucket to upload to")
+	prefix := flags.String("prefix", "", "Prefix of the key of each recording, e.g. team/alice/")
+	partSize := flags.Int64("part-size", upload.DefaultPartSize>>20, "Size of each part in MiB, at least 5")
+	flags.Var(metadata, "meta", "Metadata stored with each recording as key=value, may be repeated")
+	flags.Var(tags, "tag", "Tag set on each recording as key=value, may be repeated")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), `Usage: gt upload [flags] <recording>...
+
+Uploads recordings, along with their times, markers and gaps files, to S3 compatible storage as multipart uploads.
+An upload that is interrupted continues from the last part sent when the command is run again. The credentials are
+read from $AWS_ACCESS_KEY_ID, $AWS_SECRET_ACCESS_KEY and, for temporary credentials, $AWS_SESSION_TOKEN.
+
+Flags:
+`)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	log := newLogger("info")
+
+	uploader, err := upload.New(upload.Options{
+		Endpoint:        *endpoint,
+		Region:          *region,
+		Bucket:          *bucket,
+		Prefix:          *prefix,
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		PartSize:        *partSize << 20,
+		Metadata:        metadata,
+		Tags:            tags,
+		Logger:          &log,
+	})
+	if err != nil {
+		return fmt.Errorf("create uploader: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	for _, path := range flags.Args() {
+		err = uploadRecording(ctx, uploader, path, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uploadRecording uploads a recording followed by the files written alongside it. The recording is uploaded while it
+// is written until finished is closed, or straight away when finished is nil.
+func uploadRecording(ctx context.Context, uploader *upload.Uploader, path string, finished <-chan struct{}) error {
+	var err error
+
+	if finished == nil {
+		err = uploader.Upload(ctx, path)
+	} else {
+		err = uploader.UploadLive(ctx, path, finished)
+	}
+
+	if err != nil {
+		return fmt.Errorf("upload %s: %w", path, err)
+	}
+
+	for _, suffix := range []string{gttelemetry.RecordingTimesSuffix, gttelemetry.RecordingMarkersSuffix, gttelemetry.RecordingGapsSuffix} {
+		_, err = os.Stat(path + suffix)
+		if err != nil {
+			continue
+		}
+
+		err = uploader.Upload(ctx, path+suffix)
+		if err != nil {
+			return fmt.Errorf("upload %s: %w", path+suffix, err)
+		}
+	}
+
+	return nil
+}
+
+// uploadRecordings uploads each recording made by the daemon once it is complete, or while it is written when live.
+// Uploads interrupted when the daemon last stopped are continued first. A failed upload is logged and left to be
+// continued the next time the daemon starts, rather than stopping the daemon.
+func uploadRecordings(
+	ctx context.Context, uploader *upload.Uploader, client *gttelemetry.Client, dir string, live bool, log zerolog.Logger,
+) error {
+	var uploads sync.WaitGroup
+	defer uploads.Wait()
+
+	start := func(path string, finished <-chan struct{}) {
+		uploads.Go(func() {
+			err := uploadRecording(ctx, uploader, path, finished)
+			if err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to upload recording")
+			}
+		})
+	}
+
+	states, _ := filepath.Glob(filepath.Join(dir, "*"+upload.StateSuffix))
+	for _, state := range states {
+		path := strings.TrimSuffix(state, upload.StateSuffix)
+		if path != client.RecordingPath() {
+			start(path, nil)
+		}
+	}
+
+	ticker := time.NewTicker(uploadPollInterval)
+	defer ticker.Stop()
+
+	var (
+		current  string
+		finished chan struct{}
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		path := client.RecordingPath()
+		if path == current {
+			continue
+		}
+
+		// The recording file is closed before its path is cleared, so the previous recording is complete
+		switch {
+		case current != "" && live:
+			close(finished)
+		case current != "":
+			start(current, nil)
+		}
+
+		current = path
+
+		if current != "" && live {
+			finished = make(chan struct{})
+			start(current, finished)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+// #include <stdint.h>
+// #include <stdlib.h>
+import "C"
+
+// The functions below convert strings to and from C for the tests, which cannot use cgo themselves.
+
+// cString copies the string into C memory, which must be released with gt_free.
+func cString(s string) *C.char {
+	return C.CString(s)
+}
+
+// cHandle converts a client handle to the C type the library functions take.
+func cHandle(handle int64) C.int64_t {
+	return C.int64_t(handle)
+}
+
+// cBuffer allocates a buffer of the size in C memory, which must be released with gt_free.
+func cBuffer(size int) (*C.char, C.size_t) {
+	return (*C.char)(C.malloc(C.size_t(size))), C.size_t(size)
+}
+
+// goString copies a NUL terminated C string into a Go string.
+func goString(s *C.char) string {
+	return C.GoString(s)
+}
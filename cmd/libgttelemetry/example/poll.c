@@ -0,0 +1,52 @@
+// Prints the speed of every frame of a recording using the gt-telemetry C shared library.
+//
+//   go build -buildmode=c-shared -o libgttelemetry.so ./cmd/libgttelemetry
+//   cc -I. -o poll cmd/libgttelemetry/example/poll.c -L. -lgttelemetry
+//   LD_LIBRARY_PATH=. ./poll file://data/replays/demo.gtz
+#include <stdio.h>
+#include <time.h>
+
+#include "libgttelemetry.h"
+
+int main(int argc, char **argv) {
+	if (argc != 2) {
+		fprintf(stderr, "usage: %s <source>\n", argv[0]);
+		return 2;
+	}
+
+	if (gt_abi_version() != GT_ABI_VERSION) {
+		fprintf(stderr, "library ABI %d does not match header ABI %d\n", gt_abi_version(), GT_ABI_VERSION);
+		return 1;
+	}
+
+	char message[256];
+
+	int64_t client = gt_open(argv[1], "");
+	if (client == 0) {
+		gt_last_error(message, sizeof(message));
+		fprintf(stderr, "open: %s\n", message);
+		return 1;
+	}
+
+	struct timespec wait = {0, 4000000};
+	gt_frame frame;
+	int code;
+
+	while ((code = gt_poll_frame(client, &frame)) != GT_FINISHED) {
+		if (code == GT_ERROR) {
+			gt_last_error(message, sizeof(message));
+			fprintf(stderr, "poll: %s\n", message);
+			break;
+		}
+
+		if (code == GT_OK) {
+			printf("%u lap %d %.1f km/h %.0f rpm\n", frame.sequence_id, frame.current_lap, frame.ground_speed_kph, frame.engine_rpm);
+		}
+
+		nanosleep(&wait, NULL);
+	}
+
+	gt_close(client);
+
+	return 0;
+}
@@ -0,0 +1,417 @@
+// Command libgttelemetry is built as a C shared library so that C, C++, Python, Rust and other applications can
+// embed the telemetry client without reimplementing the protocol:
+//
+//	go build -buildmode=c-shared -o libgttelemetry.so ./cmd/libgttelemetry
+//
+// The build also writes libgttelemetry.h declaring the functions below. Clients are referred to by integer handles,
+// frames are copied into caller owned structs or returned as JSON, and nothing else crosses the boundary, so the ABI
+// only changes when GT_ABI_VERSION is incremented. Fields are only ever added to the end of gt_frame.
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+// GT_ABI_VERSION is incremented whenever a function signature or the layout of gt_frame changes incompatibly.
+#define GT_ABI_VERSION 2
+
+// Return codes of the library functions.
+#define GT_OK 0
+#define GT_NO_FRAME 1
+#define GT_FINISHED 2
+#define GT_ERROR -1
+
+// Bits of gt_frame.flags.
+#define GT_FLAG_LIVE 0x0001
+#define GT_FLAG_PAUSED 0x0002
+#define GT_FLAG_LOADING 0x0004
+#define GT_FLAG_IN_GEAR 0x0008
+#define GT_FLAG_HAS_TURBO 0x0010
+#define GT_FLAG_REV_LIMITER 0x0020
+#define GT_FLAG_HANDBRAKE 0x0040
+#define GT_FLAG_HEADLIGHTS 0x0080
+#define GT_FLAG_HIGH_BEAM 0x0100
+#define GT_FLAG_LOW_BEAM 0x0200
+#define GT_FLAG_ASM 0x0400
+#define GT_FLAG_TCS 0x0800
+
+// gt_frame is a summary of the latest frame of telemetry.
+typedef struct {
+	uint32_t sequence_id;
+	uint32_t vehicle_id;
+	uint16_t flags;
+	int16_t current_lap;
+	int16_t race_laps;
+	int16_t current_gear;
+	int32_t current_laptime_ms;
+	int32_t last_laptime_ms;
+	int32_t best_laptime_ms;
+	float ground_speed_kph;
+	float engine_rpm;
+	float throttle_percent;
+	float brake_percent;
+	float fuel_level_percent;
+	float position_x;
+	float position_y;
+	float position_z;
+} gt_frame;
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/rs/zerolog"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// reconnectDelay is how long to wait before restarting the stream after a recoverable error.
+const reconnectDelay = time.Second
+
+var errUnknownHandle = errors.New("unknown client handle")
+
+var (
+	//nolint:gochecknoglobals // the C API refers to clients by handle
+	sessions = struct {
+		sync.Mutex
+
+		next    int64
+		clients map[int64]*session
+	}{clients: make(map[int64]*session)}
+
+	//nolint:gochecknoglobals // copied out by gt_last_error until the next failure
+	lastError = struct {
+		sync.Mutex
+
+		message string
+	}{}
+)
+
+// session is a client streaming telemetry in the background for the C API.
+type session struct {
+	client *gttelemetry.Client
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// mu guards the fields below. The latest frame is copied by the goroutine decoding the stream and only the copy
+	// is read by the poll functions, as the Transformer is overwritten while each packet is decoded.
+	mu           sync.Mutex
+	latest       polledFrame
+	lastSequence uint32
+	err          error
+}
+
+// polledFrame is a frame copied from the Transformer for the poll functions.
+type polledFrame struct {
+	frame C.gt_frame
+	json  frameJSON
+}
+
+// frameJSON is the JSON form of a frame returned by gt_poll_json, with flags packed into GT_FLAG bits.
+type frameJSON struct {
+	SequenceID          uint32             `json:"sequenceId"`
+	VehicleID           uint32             `json:"vehicleId"`
+	VehicleManufacturer string             `json:"vehicleManufacturer"`
+	VehicleModel        string             `json:"vehicleModel"`
+	Flags               uint16             `json:"flags"`
+	Channels            map[string]float32 `json:"channels"`
+}
+
+func main() {}
+
+// gt_abi_version returns the GT_ABI_VERSION the library was built with, to check against the header in use.
+//
+//export gt_abi_version
+func gt_abi_version() C.int {
+	return C.GT_ABI_VERSION
+}
+
+// gt_open creates a client for the source, e.g. "udp://192.168.1.10:33739" or "file://replay.gtz", and starts
+// streaming in the background. An empty format negotiates the richest format. Returns a handle greater than zero,
+// or zero on failure.
+//
+//export gt_open
+func gt_open(source *C.char, format *C.char) C.int64_t {
+	log := zerolog.New(os.Stderr).Level(zerolog.WarnLevel).With().Timestamp().Logger()
+
+	s := &session{done: make(chan struct{})}
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:  C.GoString(source),
+		Format:  models.Name(C.GoString(format)),
+		Logger:  &log,
+		OnFrame: s.copyFrame,
+	})
+	if err != nil {
+		setLastError(err)
+
+		return 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.client = client
+	s.cancel = cancel
+
+	go s.stream(ctx)
+
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	sessions.next++
+	sessions.clients[sessions.next] = s
+
+	return C.int64_t(sessions.next)
+}
+
+// gt_close stops the client and any recording in progress and releases the handle.
+//
+//export gt_close
+func gt_close(handle C.int64_t) C.int {
+	sessions.Lock()
+	s, ok := sessions.clients[int64(handle)]
+	delete(sessions.clients, int64(handle))
+	sessions.Unlock()
+
+	if !ok {
+		return fail(fmt.Errorf("%w: %d", errUnknownHandle, handle))
+	}
+
+	s.cancel()
+	<-s.done
+
+	return C.GT_OK
+}
+
+// gt_poll_frame copies the latest frame into the struct when it has not been returned before. Returns GT_OK when a
+// frame was copied, GT_NO_FRAME when there is no new frame, GT_FINISHED at the end of a file source, or GT_ERROR.
+//
+//export gt_poll_frame
+func gt_poll_frame(handle C.int64_t, frame *C.gt_frame) C.int {
+	latest, code := poll(handle)
+	if code != C.GT_OK {
+		return code
+	}
+
+	*frame = latest.frame
+
+	return C.GT_OK
+}
+
+// gt_poll_json returns the latest frame with every channel as JSON when it has not been returned before. The string
+// must be released with gt_free. Returns the same codes as gt_poll_frame, setting out only on GT_OK.
+//
+//export gt_poll_json
+func gt_poll_json(handle C.int64_t, out **C.char) C.int {
+	latest, code := poll(handle)
+	if code != C.GT_OK {
+		return code
+	}
+
+	data, err := json.Marshal(latest.json)
+	if err != nil {
+		return fail(fmt.Errorf("encode frame: %w", err))
+	}
+
+	*out = C.CString(string(data))
+
+	return C.GT_OK
+}
+
+// gt_start_recording records the telemetry to a .gtr or .gtz file until gt_stop_recording or gt_close.
+//
+//export gt_start_recording
+func gt_start_recording(handle C.int64_t, path *C.char) C.int {
+	s, err := lookup(handle)
+	if err == nil {
+		err = s.client.StartRecording(C.GoString(path))
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	return C.GT_OK
+}
+
+// gt_stop_recording stops the recording in progress.
+//
+//export gt_stop_recording
+func gt_stop_recording(handle C.int64_t) C.int {
+	s, err := lookup(handle)
+	if err == nil {
+		err = s.client.StopRecording()
+	}
+
+	if err != nil {
+		return fail(err)
+	}
+
+	return C.GT_OK
+}
+
+// gt_last_error copies the message of the last failure on any thread into the buffer of the size in bytes, truncated
+// to fit and terminated with a NUL. Returns the length of the whole message without the terminator, like snprintf, so
+// a return of size or more means the message was truncated. Nothing is copied when the buffer is NULL or empty.
+//
+//export gt_last_error
+func gt_last_error(buf *C.char, size C.size_t) C.int {
+	lastError.Lock()
+	message := lastError.message
+	lastError.Unlock()
+
+	if buf != nil && size > 0 {
+		out := unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(size))
+		out[copy(out[:len(out)-1], message)] = 0
+	}
+
+	return C.int(len(message))
+}
+
+// gt_free releases a string returned by the library.
+//
+//export gt_free
+func gt_free(ptr *C.char) {
+	C.free(unsafe.Pointer(ptr))
+}
+
+// stream reads telemetry until the context is cancelled or a file source ends, restarting the stream after
+// recoverable errors.
+func (s *session) stream(ctx context.Context) {
+	defer close(s.done)
+
+	for ctx.Err() == nil && !s.client.Finished {
+		recoverable, err := s.client.Stream(ctx)
+		if err != nil && ctx.Err() == nil && !recoverable {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// copyFrame copies the frame just decoded for the poll functions. It is called on the goroutine decoding the stream.
+func (s *session) copyFrame(t *gttelemetry.Transformer) {
+	snapshot := t.Snapshot()
+	flags := flagBits(t.Flags())
+	position := t.PositionalMapCoordinates()
+
+	latest := polledFrame{
+		frame: C.gt_frame{
+			sequence_id:        C.uint32_t(t.SequenceID()),
+			vehicle_id:         C.uint32_t(t.VehicleID()),
+			flags:              C.uint16_t(flags),
+			current_lap:        C.int16_t(t.CurrentLap()),
+			race_laps:          C.int16_t(t.RaceLaps()),
+			current_gear:       C.int16_t(t.CurrentGear()),
+			current_laptime_ms: C.int32_t(t.CurrentLaptime().Milliseconds()),
+			last_laptime_ms:    C.int32_t(t.LastLaptime().Milliseconds()),
+			best_laptime_ms:    C.int32_t(t.BestLaptime().Milliseconds()),
+			ground_speed_kph:   C.float(t.GroundSpeedKPH()),
+			engine_rpm:         C.float(t.EngineRPM()),
+			throttle_percent:   C.float(t.ThrottleOutputPercent()),
+			brake_percent:      C.float(t.BrakeOutputPercent()),
+			fuel_level_percent: C.float(t.FuelLevelPercent()),
+			position_x:         C.float(position.X),
+			position_y:         C.float(position.Y),
+			position_z:         C.float(position.Z),
+		},
+		// The channels map is created for each frame and never changed, so it can be encoded after the lock is released
+		json: frameJSON{
+			SequenceID:          snapshot.SequenceID,
+			VehicleID:           snapshot.VehicleID,
+			VehicleManufacturer: snapshot.VehicleManufacturer,
+			VehicleModel:        snapshot.VehicleModel,
+			Flags:               flags,
+			Channels:            snapshot.Channels,
+		},
+	}
+
+	s.mu.Lock()
+	s.latest = latest
+	s.mu.Unlock()
+}
+
+// poll returns a copy of the latest frame of the session when it has not been returned before.
+func poll(handle C.int64_t) (polledFrame, C.int) {
+	s, err := lookup(handle)
+	if err != nil {
+		return polledFrame{}, fail(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return polledFrame{}, fail(fmt.Errorf("telemetry stream: %w", s.err))
+	}
+
+	if uint32(s.latest.frame.sequence_id) == s.lastSequence {
+		if s.client.IsFinished() {
+			return polledFrame{}, C.GT_FINISHED
+		}
+
+		return polledFrame{}, C.GT_NO_FRAME
+	}
+
+	s.lastSequence = uint32(s.latest.frame.sequence_id)
+
+	return s.latest, C.GT_OK
+}
+
+func lookup(handle C.int64_t) (*session, error) {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	s, ok := sessions.clients[int64(handle)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errUnknownHandle, handle)
+	}
+
+	return s, nil
+}
+
+// fail stores the error for gt_last_error and returns GT_ERROR.
+func fail(err error) C.int {
+	setLastError(err)
+
+	return C.GT_ERROR
+}
+
+func setLastError(err error) {
+	lastError.Lock()
+	defer lastError.Unlock()
+
+	lastError.message = err.Error()
+}
+
+// flagBits packs the flags into the GT_FLAG bits of gt_frame.
+func flagBits(flags gttelemetry.Flags) uint16 {
+	bits := uint16(0)
+
+	for bit, set := range []bool{
+		flags.Live, flags.GamePaused, flags.Loading, flags.InGear, flags.HasTurbo, flags.RevLimiterAlert,
+		flags.HandbrakeActive, flags.HeadlightsActive, flags.HighBeamActive, flags.LowBeamActive, flags.ASMActive,
+		flags.TCSActive,
+	} {
+		if set {
+			bits |= 1 << bit
+		}
+	}
+
+	return bits
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+const demoSource = "file://../../data/replays/demo.gtz"
+
+// Return codes of the library functions, as GT_OK, GT_NO_FRAME and GT_ERROR.
+const (
+	codeOK       = 0
+	codeNoFrame  = 1
+	codeFinished = 2
+	codeError    = -1
+)
+
+type LibraryTestSuite struct {
+	suite.Suite
+}
+
+func TestLibraryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LibraryTestSuite))
+}
+
+// pointee returns a new value of the type a C function takes a pointer to, such as the gt_frame of gt_poll_frame, as
+// the tests cannot refer to C types.
+func pointee[H, R, T any](_ func(H, *T) R) *T {
+	return new(T)
+}
+
+// copyLastError returns the message copied by gt_last_error into a buffer of the size, and the length it returned.
+func copyLastError(size int) (string, int) {
+	buf, bufSize := cBuffer(size)
+	defer gt_free(buf)
+
+	length := gt_last_error(buf, bufSize)
+
+	return goString(buf), int(length)
+}
+
+// open opens a client for the source with a negotiated format and closes it when the test ends.
+func (suite *LibraryTestSuite) open(source string) int64 {
+	cSource := cString(source)
+	defer gt_free(cSource)
+
+	format := cString("")
+	defer gt_free(format)
+
+	handle := gt_open(cSource, format)
+	suite.Require().Positive(int64(handle))
+
+	suite.T().Cleanup(func() { gt_close(handle) })
+
+	return int64(handle)
+}
+
+// frameFields holds the fields of a gt_frame compared by the tests.
+type frameFields struct {
+	sequenceID uint32
+	speeds     [2]float32 // engine speed and ground speed
+}
+
+// pollFrames polls frames from the client until it has the count or the source ends.
+func (suite *LibraryTestSuite) pollFrames(handle int64, count int) []frameFields {
+	var frames []frameFields
+
+	frame := pointee(gt_poll_frame)
+
+	suite.Require().Eventually(func() bool {
+		switch code := int(gt_poll_frame(cHandle(handle), frame)); code {
+		case codeOK:
+			frames = append(frames, frameFields{
+				sequenceID: uint32(frame.sequence_id),
+				speeds:     [2]float32{float32(frame.engine_rpm), float32(frame.ground_speed_kph)},
+			})
+		case codeNoFrame:
+		case codeFinished:
+			return true
+		default:
+			message, _ := copyLastError(256)
+			suite.Require().Failf("poll failed", "code %d: %s", code, message)
+		}
+
+		return len(frames) >= count
+	}, 30*time.Second, time.Millisecond)
+
+	return frames
+}
+
+// scannedFrames returns the engine speed and ground speed of each frame of the recording, keyed by sequence ID.
+func (suite *LibraryTestSuite) scannedFrames(source string) map[uint32][2]float32 {
+	client, err := gttelemetry.New(gttelemetry.Options{Source: source, LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	frames := make(map[uint32][2]float32)
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		frames[transformer.SequenceID()] = [2]float32{transformer.EngineRPM(), transformer.GroundSpeedKPH()}
+	}
+
+	return frames
+}
+
+func (suite *LibraryTestSuite) TestABIVersion() {
+	// Act
+	version := gt_abi_version()
+
+	// Assert
+	suite.Equal(2, int(version))
+}
+
+func (suite *LibraryTestSuite) TestStreamFailureIsReported() {
+	// Arrange
+	source := cString("file://missing.gtz")
+	defer gt_free(source)
+
+	format := cString("")
+	defer gt_free(format)
+
+	handle := gt_open(source, format)
+	suite.Require().Positive(int64(handle))
+
+	frame := pointee(gt_poll_frame)
+	code := codeNoFrame
+
+	// Act
+	suite.Require().Eventually(func() bool {
+		code = int(gt_poll_frame(handle, frame))
+
+		return code != codeNoFrame
+	}, 5*time.Second, time.Millisecond)
+
+	message, _ := copyLastError(256)
+
+	// Assert
+	suite.Equal(codeError, code)
+	suite.True(strings.HasPrefix(message, "telemetry stream: "), message)
+	suite.Equal(codeOK, int(gt_close(handle)))
+	suite.Equal(codeError, int(gt_close(handle)), "the handle is released")
+}
+
+func (suite *LibraryTestSuite) TestLastErrorIsCopied() {
+	// Arrange
+	suite.Require().Equal(codeError, int(gt_close(12345)))
+
+	// Act
+	message, length := copyLastError(256)
+
+	// Assert
+	suite.Equal("unknown client handle: 12345", message)
+	suite.Equal(len(message), length)
+}
+
+func (suite *LibraryTestSuite) TestLastErrorIsTruncated() {
+	// Arrange
+	suite.Require().Equal(codeError, int(gt_stop_recording(12345)))
+
+	// Act
+	message, length := copyLastError(8)
+
+	// Assert
+	suite.Equal("unknown", message, "the message is truncated to leave room for the terminator")
+	suite.Equal(len("unknown client handle: 12345"), length)
+}
+
+func (suite *LibraryTestSuite) TestLastErrorLengthWithoutBuffer() {
+	// Arrange
+	suite.Require().Equal(codeError, int(gt_close(12345)))
+
+	// Act
+	length := gt_last_error(nil, 0)
+
+	// Assert
+	suite.Equal(len("unknown client handle: 12345"), int(length))
+}
+
+// TestConcurrentFailures fails on many threads at once, each copying out the last error while the others replace it
+// with a message of a different length.
+func (suite *LibraryTestSuite) TestConcurrentFailures() {
+	// Arrange
+	var wg sync.WaitGroup
+
+	messages := make(chan string, 100)
+
+	// Act
+	for i := range cap(messages) {
+		wg.Go(func() {
+			if i%2 == 0 {
+				gt_close(0)
+			} else {
+				gt_close(1234567890)
+			}
+
+			message, _ := copyLastError(64)
+			messages <- message
+		})
+	}
+
+	wg.Wait()
+	close(messages)
+
+	// Assert
+	for message := range messages {
+		var handle int
+
+		_, err := fmt.Sscanf(message, "unknown client handle: %d", &handle)
+		suite.Require().NoError(err, message)
+		suite.False(strings.ContainsRune(message, 0))
+	}
+}
+
+func (suite *LibraryTestSuite) TestPollFrameMatchesRecording() {
+	// Arrange
+	scanned := suite.scannedFrames(demoSource)
+	handle := suite.open(demoSource)
+
+	// Act
+	frames := suite.pollFrames(handle, 60)
+
+	// Assert
+	suite.Require().Len(frames, 60)
+
+	for i, frame := range frames {
+		if i > 0 {
+			suite.Greater(frame.sequenceID, frames[i-1].sequenceID, "frames are only returned once")
+		}
+
+		want, ok := scanned[frame.sequenceID]
+		suite.Require().True(ok, "sequence %d is in the recording", frame.sequenceID)
+		suite.Equal(want, frame.speeds, "the fields of sequence %d belong to that frame", frame.sequenceID)
+	}
+}
+
+func (suite *LibraryTestSuite) TestPollJSON() {
+	// Arrange
+	scanned := suite.scannedFrames(demoSource)
+	handle := suite.open(demoSource)
+
+	out := pointee(gt_poll_json)
+
+	// Act
+	suite.Require().Eventually(func() bool {
+		return int(gt_poll_json(cHandle(handle), out)) == codeOK
+	}, 30*time.Second, time.Millisecond)
+
+	data := goString(*out)
+	gt_free(*out)
+
+	var frame frameJSON
+
+	// Assert
+	suite.Require().NoError(json.Unmarshal([]byte(data), &frame), data)
+	suite.Require().Contains(scanned, frame.SequenceID)
+	suite.Positive(frame.VehicleID)
+	suite.NotEmpty(frame.VehicleModel)
+	suite.Equal(scanned[frame.SequenceID][0], frame.Channels["engineRPM"])
+	suite.Equal(codeNoFrame, int(gt_poll_json(cHandle(handle), out)), "the frame is only returned once")
+}
+
+func (suite *LibraryTestSuite) TestRecordingRoundTrip() {
+	// Arrange
+	handle := suite.open(demoSource)
+	path := filepath.Join(suite.T().TempDir(), "recording.gtr")
+
+	cPath := cString(path)
+	defer gt_free(cPath)
+
+	// Act
+	suite.Require().Equal(codeOK, int(gt_start_recording(cHandle(handle), cPath)))
+
+	recorded := suite.pollFrames(handle, 30)
+
+	suite.Require().Equal(codeOK, int(gt_stop_recording(cHandle(handle))))
+	suite.Equal(codeError, int(gt_stop_recording(cHandle(handle))), "the recording is already stopped")
+
+	// Assert
+	scanned := suite.scannedFrames("file://" + path)
+	suite.Require().Len(recorded, 30)
+	suite.GreaterOrEqual(len(scanned), len(recorded)-1, "frames polled while recording are in the recording")
+	suite.Contains(scanned, recorded[len(recorded)-1].sequenceID)
+}
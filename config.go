@@ -0,0 +1,540 @@
+//go:build !tinygo
+
+package gttelemetry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/internal/reader"
+	"github.com/zetetos/gt-telemetry/v2/pkg/httpauth"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultOSCInterval is the interval at which the OSC exporter sends channels when no interval is configured.
+	DefaultOSCInterval = 50 * time.Millisecond
+	// DefaultMQTTInterval is the interval at which the MQTT exporter publishes state when no interval is configured.
+	DefaultMQTTInterval = time.Second
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid configuration")
+
+	errConfigRequired       = errors.New("is required")
+	errConfigUnknownChannel = errors.New("is not a known channel")
+	errConfigNeedsHTTP      = errors.New("requires exporters.http to be configured")
+	errConfigNeedsRecording = errors.New("requires recording.directory to be configured")
+	errConfigInvalidValue   = errors.New("has an invalid value")
+	errConfigUnknownEvent   = errors.New("is not a known event")
+	errConfigNotRedactable  = errors.New("is not an exporter that publishes channels")
+	errConfigAllRedacted    = errors.New("are all redacted")
+)
+
+// Config is the configuration file schema, read with LoadConfig. Field names in the file are the lower camel case
+// form of the Go field names, see cmd/gt/gt.example.yaml for an example.
+type Config struct {
+	Client    ClientConfig    `yaml:"client"`
+	Recording RecordingConfig `yaml:"recording"`
+	Exporters ExportersConfig `yaml:"exporters"`
+	Redaction RedactionConfig `yaml:"redaction"`
+	// Precision sets the number of decimal places of channels published as JSON by the http, websocket and mqtt
+	// exporters, e.g. {engineRPM: 0, groundSpeedKPH: 1}.
+	Precision ChannelPrecision `yaml:"precision"`
+}
+
+// ClientConfig holds the client options that can be set from a configuration file, see Options for their meaning.
+type ClientConfig struct {
+	Source             string   `yaml:"source"`
+	Format             string   `yaml:"format"`
+	LogLevel           string   `yaml:"logLevel"`
+	StatsEnabled       bool     `yaml:"statsEnabled"`
+	CachePath          string   `yaml:"cachePath"`
+	UpdateBaseURL      string   `yaml:"updateBaseURL"`
+	VehicleDB          string   `yaml:"vehicleDB"`
+	VehicleOverrides   string   `yaml:"vehicleOverrides"`
+	CustomCircuitsDir  string   `yaml:"customCircuitsDir"`
+	LowLatency         bool     `yaml:"lowLatency"`
+	RealtimeScheduling bool     `yaml:"realtimeScheduling"`
+	ReceiveBufferSize  int      `yaml:"receiveBufferSize"`
+	ReusePort          bool     `yaml:"reusePort"`
+	Interface          string   `yaml:"interface"`
+	FanOut             []string `yaml:"fanOut"`
+	// DisableInventories skips loading the vehicle and circuit inventories, for memory constrained deployments.
+	DisableInventories bool `yaml:"disableInventories"`
+	// Filters maps channel names to the time constant of the smoothing applied to them, e.g. 100ms.
+	Filters map[string]time.Duration `yaml:"filters"`
+	// FinalDrive configures the estimation of the final drive ratio, e.g. {rollingRadiusCorrection: 1.02}.
+	FinalDrive FinalDriveOptions `yaml:"finalDrive"`
+}
+
+// RecordingConfig holds the automatic recording rules.
+type RecordingConfig struct {
+	// Directory that sessions on circuit and replays are recorded to, leave empty to disable automatic recording.
+	Directory string `yaml:"directory"`
+	// Format is the recording file format, either gtz for compressed or gtr for plain files. Defaults to gtz.
+	Format string `yaml:"format"`
+	// Password encrypts gtz recordings and decrypts encrypted recordings read from a file source.
+	Password string `yaml:"password"`
+}
+
+// ExportersConfig configures the exporters run by the gt daemon. Exporters that are not set are disabled.
+type ExportersConfig struct {
+	HTTP      *HTTPExporterConfig      `yaml:"http"`
+	WebSocket *WebSocketExporterConfig `yaml:"websocket"`
+	OSC       *OSCExporterConfig       `yaml:"osc"`
+	MQTT      *MQTTExporterConfig      `yaml:"mqtt"`
+	Serial    *SerialExporterConfig    `yaml:"serial"`
+	Cluster   *ClusterExporterConfig   `yaml:"cluster"`
+	Upload    *UploadExporterConfig    `yaml:"upload"`
+	Webhook   *WebhookExporterConfig   `yaml:"webhook"`
+}
+
+// HTTPExporterConfig configures the HTTP server, which serves the live timing page, the health endpoints, the WebSocket
+// feed and the optional diagnostics endpoints.
+type HTTPExporterConfig struct {
+	Address string `yaml:"address"`
+	// Expvar publishes the client statistics with expvar and serves them at /debug/vars.
+	Expvar bool `yaml:"expvar"`
+	// Pprof serves the runtime profiling endpoints at /debug/pprof/. The profiles reveal details of the process, so
+	// only enable this on a trusted network.
+	Pprof bool `yaml:"pprof"`
+	// Token is required by every request except the health endpoints when set, either as a bearer token or in the
+	// token query parameter, e.g. /?token=secret. Set a token before exposing the server beyond a trusted network.
+	Token string `yaml:"token"`
+	// TLSCert and TLSKey are the PEM encoded certificate and key files to serve HTTPS with, leave empty for HTTP.
+	TLSCert string `yaml:"tlsCert"`
+	TLSKey  string `yaml:"tlsKey"`
+}
+
+// TLS returns the certificate and key files of the server.
+func (cfg HTTPExporterConfig) TLS() httpauth.TLS {
+	return httpauth.TLS{CertFile: cfg.TLSCert, KeyFile: cfg.TLSKey}
+}
+
+// WebSocketExporterConfig configures the WebSocket feed served by the HTTP server.
+type WebSocketExporterConfig struct {
+	Channels []string      `yaml:"channels"`
+	Interval time.Duration `yaml:"interval"`
+	// Map serves the map channel at /ws/map, the position of the vehicle on the map of the circuit being driven, to
+	// draw over the circuit maps served at /maps/<circuit ID>.svg.
+	Map bool `yaml:"map"`
+	// MapInterval is how often the map channel is sent, defaults to 250ms.
+	MapInterval time.Duration `yaml:"mapInterval"`
+}
+
+// OSCExporterConfig configures the OSC exporter.
+type OSCExporterConfig struct {
+	Address  string        `yaml:"address"`
+	Prefix   string        `yaml:"prefix"`
+	Channels []string      `yaml:"channels"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// MQTTExporterConfig configures publishing of Home Assistant sensors to an MQTT broker.
+type MQTTExporterConfig struct {
+	Address         string        `yaml:"address"`
+	ClientID        string        `yaml:"clientId"`
+	Username        string        `yaml:"username"`
+	Password        string        `yaml:"password"`
+	DiscoveryPrefix string        `yaml:"discoveryPrefix"`
+	NodeID          string        `yaml:"nodeId"`
+	DeviceName      string        `yaml:"deviceName"`
+	Interval        time.Duration `yaml:"interval"`
+}
+
+// SerialExporterConfig configures a serial dashboard, see pkg/serialdash for the template syntax.
+type SerialExporterConfig struct {
+	Port     string        `yaml:"port"`
+	Baud     int           `yaml:"baud"`
+	Template string        `yaml:"template"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// ClusterExporterConfig configures forwarding of summarised frames to a cluster collector, see pkg/cluster.
+type ClusterExporterConfig struct {
+	// Collector is the base URL of the collector, e.g. http://timing.example.com:8080.
+	Collector string `yaml:"collector"`
+	// Driver is the name the driver is shown under in the standings.
+	Driver string `yaml:"driver"`
+	// Token is sent with every frame, matching the token the collector was started with.
+	Token string `yaml:"token"`
+	// Channels lists the channels forwarded with the lap timing, leave empty to forward only the lap timing.
+	Channels []string      `yaml:"channels"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// UploadExporterConfig configures uploading of automatic recordings to S3 compatible storage, see pkg/upload.
+type UploadExporterConfig struct {
+	// Endpoint is the base URL of the storage service, e.g. https://s3.eu-west-2.amazonaws.com.
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+	Bucket   string `yaml:"bucket"`
+	// Prefix is prepended to the file name of each recording to form its key, e.g. team/alice/.
+	Prefix string `yaml:"prefix"`
+	// AccessKeyID and SecretAccessKey default to $AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY.
+	AccessKeyID     string `yaml:"accessKeyId"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	// PartSize is the size of each part of an upload in bytes, at least 5 MiB, defaults to 8 MiB.
+	PartSize int64 `yaml:"partSize"`
+	// Metadata is stored with every recording as user metadata, and Tags set on it as object tags.
+	Metadata map[string]string `yaml:"metadata"`
+	Tags     map[string]string `yaml:"tags"`
+	// Live uploads each recording in parts while it is written, rather than once it is complete.
+	Live bool `yaml:"live"`
+}
+
+// WebhookExporterConfig configures delivery of session events to HTTP webhooks, see pkg/webhook.
+type WebhookExporterConfig struct {
+	URLs []string `yaml:"urls"`
+	// Headers are sent with every request, e.g. {Authorization: Bearer secret}.
+	Headers map[string]string `yaml:"headers"`
+	// Secret signs the body of every request with HMAC-SHA256 in the X-GT-Signature header.
+	Secret string `yaml:"secret"`
+	// Retries is the number of times a failed delivery is retried, defaults to 3, -1 disables retries.
+	Retries int `yaml:"retries"`
+	// Events lists the session event types delivered, leave empty to deliver every event.
+	Events []string `yaml:"events"`
+}
+
+// RedactionConfig hides channels from the exporters, such as to keep the fuel strategy off a public stream overlay.
+// Recordings hold the raw packets and are not redacted, so keep them private when channels are hidden.
+type RedactionConfig struct {
+	// Block lists the channels hidden from every exporter.
+	Block []string `yaml:"block"`
+	// Exporters holds the policy of each exporter by its name in the exporters section, one of http, websocket, osc,
+	// mqtt, serial or cluster, e.g. {websocket: {allow: [groundSpeedKPH, currentGear]}}.
+	Exporters map[string]ChannelPolicy `yaml:"exporters"`
+}
+
+// redactableExporters are the exporters that publish channels, and so can have a channel policy.
+//
+//nolint:gochecknoglobals // read-only list of exporter names
+var redactableExporters = []string{"http", "websocket", "osc", "mqtt", "serial", "cluster"}
+
+// ChannelPolicy returns the channel policy of an exporter, combining the channels blocked for every exporter with the
+// policy of the exporter itself.
+func (cfg Config) ChannelPolicy(exporter string) ChannelPolicy {
+	policy := cfg.Redaction.Exporters[exporter]
+
+	return ChannelPolicy{
+		Allow: policy.Allow,
+		Block: slices.Concat(cfg.Redaction.Block, policy.Block),
+	}
+}
+
+// NewFromConfig creates a client from the client and recording sections of a YAML configuration file.
+func NewFromConfig(path string) (*Client, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(cfg.Options())
+}
+
+// LoadConfig reads and validates a YAML configuration file. Unknown fields are rejected so that typos are not
+// silently ignored, and every invalid field is reported by its path in the file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config: %w", err)
+	}
+
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ParseConfig parses and validates a YAML configuration, applying defaults to unset exporter intervals.
+func ParseConfig(data []byte) (Config, error) {
+	var cfg Config
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(&cfg)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return Config{}, fmt.Errorf("%w:\n%w", ErrInvalidConfig, err)
+	}
+
+	return cfg, nil
+}
+
+// Options returns the client options described by the configuration.
+func (cfg Config) Options() Options {
+	return Options{
+		Source:             cfg.Client.Source,
+		Format:             models.Name(cfg.Client.Format),
+		LogLevel:           cfg.Client.LogLevel,
+		StatsEnabled:       cfg.Client.StatsEnabled,
+		CachePath:          cfg.Client.CachePath,
+		UpdateBaseURL:      cfg.Client.UpdateBaseURL,
+		VehicleDB:          cfg.Client.VehicleDB,
+		VehicleOverrides:   cfg.Client.VehicleOverrides,
+		CustomCircuitsDir:  cfg.Client.CustomCircuitsDir,
+		LowLatency:         cfg.Client.LowLatency,
+		RealtimeScheduling: cfg.Client.RealtimeScheduling,
+		ReceiveBufferSize:  cfg.Client.ReceiveBufferSize,
+		ReusePort:          cfg.Client.ReusePort,
+		Interface:          cfg.Client.Interface,
+		FanOut:             cfg.Client.FanOut,
+		DisableInventories: cfg.Client.DisableInventories,
+		RecordingDir:       cfg.Recording.Directory,
+		RecordingFormat:    cfg.Recording.Format,
+		RecordingPassword:  cfg.Recording.Password,
+		Filters:            cfg.Client.Filters,
+		FinalDrive:         cfg.Client.FinalDrive,
+	}
+}
+
+// validate checks every field and returns all of the problems found, applying defaults to unset intervals.
+func (cfg *Config) validate() error {
+	var errs []error
+
+	invalid := func(field string, err error, value any) {
+		if value == nil {
+			errs = append(errs, fmt.Errorf("%s %w", field, err))
+		} else {
+			errs = append(errs, fmt.Errorf("%s %w: %v", field, err, value))
+		}
+	}
+
+	if cfg.Client.Source != "" {
+		sourceURL, err := url.Parse(cfg.Client.Source)
+		if err != nil || (sourceURL.Scheme != reader.SchemeUDP && sourceURL.Scheme != reader.SchemeFile) {
+			invalid("client.source", errConfigInvalidValue, cfg.Client.Source+" (use udp://host:port or file://path)")
+		}
+	}
+
+	if cfg.Client.Format != "" && !slices.Contains(negotiationOrder, models.Name(cfg.Client.Format)) {
+		invalid("client.format", errConfigInvalidValue, fmt.Sprintf("%q (use one of %q)", cfg.Client.Format, negotiationOrder))
+	}
+
+	if cfg.Client.LogLevel != "" {
+		if _, err := zerolog.ParseLevel(cfg.Client.LogLevel); err != nil {
+			invalid("client.logLevel", errConfigInvalidValue, cfg.Client.LogLevel)
+		}
+	}
+
+	if cfg.Client.ReceiveBufferSize < 0 {
+		invalid("client.receiveBufferSize", errConfigInvalidValue, cfg.Client.ReceiveBufferSize)
+	}
+
+	err := cfg.Client.FinalDrive.validate()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("client.finalDrive: %w", err))
+	}
+
+	for name, timeConstant := range cfg.Client.Filters {
+		if _, ok := channels[name]; !ok {
+			errs = append(errs, fmt.Errorf("client.filters: %q %w", name, errConfigUnknownChannel))
+		} else if timeConstant <= 0 {
+			invalid("client.filters."+name, errConfigInvalidValue, timeConstant)
+		}
+	}
+
+	if cfg.Recording.Format != "" && cfg.Recording.Format != "gtz" && cfg.Recording.Format != "gtr" {
+		invalid("recording.format", errConfigInvalidValue, cfg.Recording.Format+" (use gtz or gtr)")
+	}
+
+	if cfg.Recording.Password != "" && cfg.Recording.Format == "gtr" {
+		invalid("recording.password", errConfigInvalidValue, "only gtz recordings can be encrypted")
+	}
+
+	exporters := &cfg.Exporters
+
+	if exporters.HTTP != nil {
+		if exporters.HTTP.Address == "" {
+			invalid("exporters.http.address", errConfigRequired, nil)
+		}
+
+		if err := exporters.HTTP.TLS().Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("exporters.http: %w", err))
+		}
+	}
+
+	if exporters.WebSocket != nil {
+		if exporters.HTTP == nil {
+			invalid("exporters.websocket", errConfigNeedsHTTP, nil)
+		}
+
+		errs = append(errs, validateChannels("exporters.websocket.channels", exporters.WebSocket.Channels)...)
+
+		if len(exporters.WebSocket.Channels) > 0 && len(cfg.ChannelPolicy("websocket").Filter(exporters.WebSocket.Channels)) == 0 {
+			invalid("exporters.websocket.channels", errConfigAllRedacted, nil)
+		}
+	}
+
+	if exporters.OSC != nil {
+		if exporters.OSC.Address == "" {
+			invalid("exporters.osc.address", errConfigRequired, nil)
+		}
+
+		errs = append(errs, validateChannels("exporters.osc.channels", exporters.OSC.Channels)...)
+
+		if len(exporters.OSC.Channels) > 0 && len(cfg.ChannelPolicy("osc").Filter(exporters.OSC.Channels)) == 0 {
+			invalid("exporters.osc.channels", errConfigAllRedacted, nil)
+		}
+
+		if exporters.OSC.Interval <= 0 {
+			exporters.OSC.Interval = DefaultOSCInterval
+		}
+	}
+
+	if exporters.MQTT != nil {
+		if exporters.MQTT.Address == "" {
+			invalid("exporters.mqtt.address", errConfigRequired, nil)
+		}
+
+		// MQTT 3.1.1 only allows a password to be sent along with a username.
+		if exporters.MQTT.Password != "" && exporters.MQTT.Username == "" {
+			invalid("exporters.mqtt.password", errConfigInvalidValue, "a password requires a username")
+		}
+
+		if exporters.MQTT.Interval <= 0 {
+			exporters.MQTT.Interval = DefaultMQTTInterval
+		}
+	}
+
+	if exporters.Serial != nil {
+		if exporters.Serial.Port == "" {
+			invalid("exporters.serial.port", errConfigRequired, nil)
+		}
+
+		if exporters.Serial.Baud < 0 {
+			invalid("exporters.serial.baud", errConfigInvalidValue, exporters.Serial.Baud)
+		}
+	}
+
+	if exporters.Cluster != nil {
+		if exporters.Cluster.Collector == "" {
+			invalid("exporters.cluster.collector", errConfigRequired, nil)
+		}
+
+		if exporters.Cluster.Driver == "" {
+			invalid("exporters.cluster.driver", errConfigRequired, nil)
+		}
+
+		for _, name := range exporters.Cluster.Channels {
+			if _, ok := channels[name]; !ok {
+				errs = append(errs, fmt.Errorf("exporters.cluster.channels: %q %w", name, errConfigUnknownChannel))
+			}
+		}
+	}
+
+	if exporters.Upload != nil {
+		if cfg.Recording.Directory == "" {
+			invalid("exporters.upload", errConfigNeedsRecording, nil)
+		}
+
+		if exporters.Upload.Endpoint == "" {
+			invalid("exporters.upload.endpoint", errConfigRequired, nil)
+		}
+
+		if exporters.Upload.Bucket == "" {
+			invalid("exporters.upload.bucket", errConfigRequired, nil)
+		}
+
+		if exporters.Upload.PartSize != 0 && exporters.Upload.PartSize < 5<<20 {
+			invalid("exporters.upload.partSize", errConfigInvalidValue, fmt.Sprintf("%d (at least 5 MiB)", exporters.Upload.PartSize))
+		}
+	}
+
+	if exporters.Webhook != nil {
+		if len(exporters.Webhook.URLs) == 0 {
+			invalid("exporters.webhook.urls", errConfigRequired, nil)
+		}
+
+		for _, name := range exporters.Webhook.Events {
+			if !sessionEventTypes[SessionEventType(name)] {
+				errs = append(errs, fmt.Errorf("exporters.webhook.events: %q %w", name, errConfigUnknownEvent))
+			}
+		}
+	}
+
+	errs = append(errs, cfg.Redaction.validate()...)
+	errs = append(errs, cfg.Precision.validate()...)
+
+	return errors.Join(errs...)
+}
+
+// validate checks that every channel in the precision section is known and has a valid number of decimal places.
+func (p ChannelPrecision) validate() []error {
+	var errs []error
+
+	for _, name := range slices.Sorted(maps.Keys(p)) {
+		if _, ok := channels[name]; !ok {
+			errs = append(errs, fmt.Errorf("precision: %q %w", name, errConfigUnknownChannel))
+
+			continue
+		}
+
+		if places := p[name]; places < 0 || places > MaxChannelPrecision {
+			errs = append(errs, fmt.Errorf("precision.%s %w: %d (0 to %d)", name, errConfigInvalidValue, places, MaxChannelPrecision))
+		}
+	}
+
+	return errs
+}
+
+// validate checks that every channel and exporter named in the redaction section is known.
+func (r RedactionConfig) validate() []error {
+	var errs []error
+
+	unknownChannels := func(field string, names []string) {
+		for _, name := range names {
+			if _, ok := channels[name]; !ok {
+				errs = append(errs, fmt.Errorf("%s: %q %w", field, name, errConfigUnknownChannel))
+			}
+		}
+	}
+
+	unknownChannels("redaction.block", r.Block)
+
+	for _, exporter := range slices.Sorted(maps.Keys(r.Exporters)) {
+		if !slices.Contains(redactableExporters, exporter) {
+			errs = append(errs, fmt.Errorf("redaction.exporters: %q %w", exporter, errConfigNotRedactable))
+
+			continue
+		}
+
+		unknownChannels("redaction.exporters."+exporter+".allow", r.Exporters[exporter].Allow)
+		unknownChannels("redaction.exporters."+exporter+".block", r.Exporters[exporter].Block)
+	}
+
+	return errs
+}
+
+// validateChannels checks that at least one channel is listed and that every channel is known.
+func validateChannels(field string, names []string) []error {
+	if len(names) == 0 {
+		return []error{fmt.Errorf("%s %w", field, errConfigRequired)}
+	}
+
+	var errs []error
+
+	for _, name := range names {
+		if _, ok := channels[name]; !ok {
+			errs = append(errs, fmt.Errorf("%s: %q %w", field, name, errConfigUnknownChannel))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,261 @@
+//go:build !tinygo
+
+package gttelemetry_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type ConfigTestSuite struct {
+	suite.Suite
+}
+
+func TestConfigTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+func (suite *ConfigTestSuite) TestExampleConfigIsValid() {
+	// Act
+	cfg, err := gttelemetry.LoadConfig("cmd/gt/gt.example.yaml")
+
+	// Assert
+	suite.Require().NoError(err)
+
+	opts := cfg.Options()
+	suite.Equal("udp://192.168.1.10:33739", opts.Source)
+	suite.Equal("/var/lib/gt/recordings", opts.RecordingDir)
+	suite.Equal("gtz", opts.RecordingFormat)
+	suite.Require().NotNil(cfg.Exporters.WebSocket)
+	suite.Equal(50*time.Millisecond, cfg.Exporters.WebSocket.Interval)
+	suite.Require().NotNil(cfg.Exporters.MQTT)
+	suite.Equal(time.Second, cfg.Exporters.MQTT.Interval)
+}
+
+func (suite *ConfigTestSuite) TestIntervalsDefaultWhenUnset() {
+	// Act
+	cfg, err := gttelemetry.ParseConfig([]byte(`
+exporters:
+  osc:
+    address: 127.0.0.1:9000
+    channels: [engineRPM]
+  mqtt:
+    address: 127.0.0.1:1883
+`))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(gttelemetry.DefaultOSCInterval, cfg.Exporters.OSC.Interval)
+	suite.Equal(gttelemetry.DefaultMQTTInterval, cfg.Exporters.MQTT.Interval)
+}
+
+func (suite *ConfigTestSuite) TestClientFormatIsMapped() {
+	// Act
+	cfg, err := gttelemetry.ParseConfig([]byte("client:\n  format: B\n"))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(models.Addendum1, cfg.Options().Format)
+}
+
+func (suite *ConfigTestSuite) TestChannelPolicyCombinesBlockLists() {
+	// Arrange
+	cfg, err := gttelemetry.ParseConfig([]byte(`
+redaction:
+  block: [fuelLevel]
+  exporters:
+    websocket:
+      block: [fuelLevelPercent]
+    mqtt:
+      allow: [groundSpeedKPH]
+`))
+	suite.Require().NoError(err)
+
+	// Act
+	websocket := cfg.ChannelPolicy("websocket")
+	mqtt := cfg.ChannelPolicy("mqtt")
+	osc := cfg.ChannelPolicy("osc")
+
+	// Assert
+	suite.Equal([]string{"engineRPM"}, websocket.Filter([]string{"fuelLevel", "fuelLevelPercent", "engineRPM"}))
+	suite.Equal([]string{"groundSpeedKPH"}, mqtt.Filter([]string{"engineRPM", "groundSpeedKPH"}))
+	suite.False(osc.Allowed("fuelLevel"))
+	suite.True(osc.Allowed("fuelLevelPercent"))
+}
+
+func (suite *ConfigTestSuite) TestInvalidConfigReportsEveryField() {
+	tests := map[string]struct {
+		config    string
+		wantField string
+	}{
+		"unknown field": {
+			config:    "client:\n  sauce: udp://127.0.0.1:33739\n",
+			wantField: "field sauce not found",
+		},
+		"source scheme": {
+			config:    "client:\n  source: tcp://127.0.0.1:33739\n",
+			wantField: "client.source",
+		},
+		"format": {
+			config:    "client:\n  format: Z\n",
+			wantField: "client.format",
+		},
+		"log level": {
+			config:    "client:\n  logLevel: loud\n",
+			wantField: "client.logLevel",
+		},
+		"unknown filter channel": {
+			config:    "client:\n  filters:\n    warpSpeed: 100ms\n",
+			wantField: `client.filters: "warpSpeed"`,
+		},
+		"recording format": {
+			config:    "recording:\n  format: zip\n",
+			wantField: "recording.format",
+		},
+		"encrypted plain recording": {
+			config:    "recording:\n  format: gtr\n  password: secret\n",
+			wantField: "recording.password",
+		},
+		"upload without recording": {
+			config:    "exporters:\n  upload:\n    endpoint: https://s3.example.com\n    bucket: sessions\n",
+			wantField: "exporters.upload",
+		},
+		"rolling radius correction out of range": {
+			config:    "client:\n  finalDrive:\n    rollingRadiusCorrection: 2\n",
+			wantField: "client.finalDrive",
+		},
+		"webhook without urls": {
+			config:    "exporters:\n  webhook:\n    secret: s3cret\n",
+			wantField: "exporters.webhook.urls",
+		},
+		"unknown webhook event": {
+			config:    "exporters:\n  webhook:\n    urls: [https://example.com/hook]\n    events: [lap, crash]\n",
+			wantField: `exporters.webhook.events: "crash"`,
+		},
+		"unknown redacted channel": {
+			config:    "redaction:\n  block: [fuelStrategy]\n",
+			wantField: `redaction.block: "fuelStrategy"`,
+		},
+		"unknown redacted exporter": {
+			config:    "redaction:\n  exporters:\n    recording:\n      block: [fuelLevel]\n",
+			wantField: `redaction.exporters: "recording"`,
+		},
+		"unknown precision channel": {
+			config:    "precision:\n  warpSpeed: 1\n",
+			wantField: `precision: "warpSpeed"`,
+		},
+		"precision out of range": {
+			config:    "precision:\n  engineRPM: 12\n",
+			wantField: "precision.engineRPM has an invalid value: 12",
+		},
+		"every websocket channel redacted": {
+			config: "exporters:\n  http:\n    address: :8080\n  websocket:\n    channels: [fuelLevel]\n" +
+				"redaction:\n  exporters:\n    websocket:\n      allow: [engineRPM]\n",
+			wantField: "exporters.websocket.channels are all redacted",
+		},
+		"websocket without http": {
+			config:    "exporters:\n  websocket:\n    channels: [engineRPM]\n",
+			wantField: "exporters.websocket",
+		},
+		"unknown channel": {
+			config:    "exporters:\n  osc:\n    address: 127.0.0.1:9000\n    channels: [warpSpeed]\n",
+			wantField: `exporters.osc.channels: "warpSpeed"`,
+		},
+		"missing address": {
+			config:    "exporters:\n  mqtt:\n    interval: 1s\n",
+			wantField: "exporters.mqtt.address",
+		},
+		"mqtt password without username": {
+			config:    "exporters:\n  mqtt:\n    address: 127.0.0.1:1883\n    password: secret\n",
+			wantField: "exporters.mqtt.password has an invalid value: a password requires a username",
+		},
+		"missing serial port": {
+			config:    "exporters:\n  serial:\n    baud: 9600\n",
+			wantField: "exporters.serial.port",
+		},
+		"incomplete TLS": {
+			config:    "exporters:\n  http:\n    address: :8080\n    tlsCert: cert.pem\n",
+			wantField: "exporters.http: TLS requires both",
+		},
+		"missing cluster driver": {
+			config:    "exporters:\n  cluster:\n    collector: http://127.0.0.1:8080\n",
+			wantField: "exporters.cluster.driver",
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			_, err := gttelemetry.ParseConfig([]byte(test.config))
+
+			// Assert
+			suite.Require().ErrorIs(err, gttelemetry.ErrInvalidConfig)
+			suite.Contains(err.Error(), test.wantField)
+		})
+	}
+}
+
+func (suite *ConfigTestSuite) TestNewFromConfigRecordsSessions() {
+	// Arrange
+	dir := suite.T().TempDir()
+	recordingDir := filepath.Join(dir, "recordings")
+	configPath := filepath.Join(dir, "gt.yaml")
+
+	err := os.WriteFile(configPath, []byte(`
+client:
+  source: file://data/replays/demo.gtz
+  logLevel: error
+recording:
+  directory: `+recordingDir+`
+  format: gtr
+`), 0o600)
+	suite.Require().NoError(err)
+
+	client, err := gttelemetry.NewFromConfig(configPath)
+	suite.Require().NoError(err)
+
+	// Act
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	// Assert
+	if client.IsRecording() {
+		suite.Require().NoError(client.StopRecording())
+	}
+
+	recordings, err := filepath.Glob(filepath.Join(recordingDir, "*.gtr"))
+	suite.Require().NoError(err)
+	suite.NotEmpty(recordings)
+}
+
+func (suite *ConfigTestSuite) TestDisableInventoriesSkipsLoading() {
+	// Arrange
+	cfg, err := gttelemetry.ParseConfig([]byte(`
+client:
+  source: file://data/replays/demo.gtz
+  logLevel: error
+  disableInventories: true
+`))
+	suite.Require().NoError(err)
+
+	// Act
+	client, err := gttelemetry.New(cfg.Options())
+	suite.Require().NoError(err)
+
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	// Assert
+	suite.Empty(client.CircuitDB.GetAllCircuitIDs())
+	suite.Empty(client.Telemetry.VehicleManufacturer())
+}
@@ -0,0 +1,185 @@
+package gttelemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// ErrUnknownConversionFormat indicates a recording converted to a format other than Standard, Addendum1, Addendum2
+// or Addendum3.
+var ErrUnknownConversionFormat = errors.New("unknown conversion format")
+
+// ConvertOptions configures ConvertRecording.
+type ConvertOptions struct {
+	// Format is the telemetry format the packets of the recording are re-encoded in.
+	Format models.Name
+	// Password decrypts an encrypted source recording and encrypts the converted recording, which must then be a gtz
+	// file.
+	Password string
+	// Logger receives the log messages of the client reading the source recording, which are discarded when nil.
+	Logger *logging.Logger
+}
+
+// ConversionReport counts the packets of a recording converted by ConvertRecording.
+type ConversionReport struct {
+	// Packets is the number of packets written to the converted recording.
+	Packets int
+	// Extended is the number of packets of a sparser format extended with the fields they lack zero filled.
+	Extended int
+	// Truncated is the number of packets of a richer format with the fields of the richer format dropped.
+	Truncated int
+	// Invalid is the number of packets that could not be parsed, which are left out of the converted recording.
+	Invalid int
+}
+
+// formatPacketSize returns the size of the packets of a telemetry format. Each format appends its fields to the
+// packets of the format before it, so packets convert between formats by zero filling or truncating the end.
+func formatPacketSize(format models.Name) (int, bool) {
+	switch format {
+	case models.Standard:
+		return 296, true
+	case models.Addendum1:
+		return 316, true
+	case models.Addendum2:
+		return 344, true
+	case models.Addendum3:
+		return 368, true
+	default:
+		return 0, false
+	}
+}
+
+// ConvertRecording re-encodes the packets of the recording at sourcePath in another telemetry format, writing them to
+// a new recording at destinationPath, plain or compressed by its file extension, so that tools built for one format
+// can read captures of another. The times, markers and gaps of the recording, which refer to frames by sequence ID,
+// are copied alongside. The converted recording is removed if the conversion fails.
+func ConvertRecording(
+	ctx context.Context, sourcePath string, destinationPath string, opts ConvertOptions,
+) (ConversionReport, error) {
+	size, ok := formatPacketSize(opts.Format)
+	if !ok {
+		return ConversionReport{}, fmt.Errorf("%w: %q", ErrUnknownConversionFormat, opts.Format)
+	}
+
+	if strings.HasSuffix(destinationPath, ".gtr") && opts.Password != "" {
+		return ConversionReport{}, ErrRecordingNotEncryptable
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		nop := logging.Nop()
+		logger = &nop
+	}
+
+	client, err := New(Options{
+		Source:             "file://" + sourcePath,
+		Logger:             logger,
+		DisableInventories: true,
+		RecordingPassword:  opts.Password,
+	})
+	if err != nil {
+		return ConversionReport{}, err
+	}
+
+	file, buffer, err := createRecording(destinationPath, opts.Password)
+	if err != nil {
+		return ConversionReport{}, err
+	}
+
+	report, err := convertPackets(ctx, client, buffer, size)
+
+	closeErr := file.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close recording file: %w", closeErr)
+	}
+
+	if err == nil {
+		err = copyRecordingSidecars(sourcePath, destinationPath)
+	}
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		removeRecordingFiles(destinationPath)
+
+		return report, err
+	}
+
+	return report, nil
+}
+
+// convertPackets writes every valid packet scanned by the client to the writer, resized to the packet size.
+func convertPackets(ctx context.Context, client *Client, writer io.Writer, size int) (ConversionReport, error) {
+	report := ConversionReport{}
+	packet := make([]byte, size)
+
+	for _, err := range client.Scan(ctx) {
+		if err != nil {
+			return report, err
+		}
+
+		if client.Statistics.PacketsInvalid > report.Invalid {
+			report.Invalid = client.Statistics.PacketsInvalid
+
+			continue
+		}
+
+		source := client.DecipheredPacket
+
+		switch {
+		case len(source) < size:
+			report.Extended++
+		case len(source) > size:
+			report.Truncated++
+		}
+
+		clear(packet)
+		copy(packet, source)
+
+		_, err = writer.Write(packet)
+		if err != nil {
+			return report, fmt.Errorf("failed to write packet: %w", err)
+		}
+
+		report.Packets++
+	}
+
+	return report, nil
+}
+
+// copyRecordingSidecars copies the times, markers and gaps files of a recording, where present, to another recording.
+func copyRecordingSidecars(sourcePath, destinationPath string) error {
+	for _, suffix := range []string{RecordingTimesSuffix, RecordingMarkersSuffix, RecordingGapsSuffix} {
+		content, err := os.ReadFile(sourcePath + suffix)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to read recording %s: %w", strings.TrimPrefix(suffix, "."), err)
+		}
+
+		err = os.WriteFile(destinationPath+suffix, content, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write recording %s: %w", strings.TrimPrefix(suffix, "."), err)
+		}
+	}
+
+	return nil
+}
+
+// removeRecordingFiles removes a recording and its sidecar files, ignoring those that do not exist.
+func removeRecordingFiles(recordingPath string) {
+	for _, suffix := range []string{"", RecordingTimesSuffix, RecordingMarkersSuffix, RecordingGapsSuffix} {
+		_ = os.Remove(recordingPath + suffix)
+	}
+}
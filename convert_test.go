@@ -0,0 +1,194 @@
+package gttelemetry_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type ConvertTestSuite struct {
+	suite.Suite
+
+	tmpDir string
+}
+
+func TestConvertTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ConvertTestSuite))
+}
+
+func (suite *ConvertTestSuite) SetupTest() {
+	suite.tmpDir = suite.T().TempDir()
+}
+
+// convertedFrame is the part of a frame compared between recordings.
+type convertedFrame struct {
+	sequenceID uint32
+	format     models.Name
+	engineRPM  float32
+	steering   float32
+}
+
+// readFrames reads every frame of a recording.
+func (suite *ConvertTestSuite) readFrames(path string) []convertedFrame {
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://" + path, LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	var frames []convertedFrame
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		frames = append(frames, convertedFrame{
+			sequenceID: transformer.SequenceID(),
+			format:     transformer.TelemetryFormat(),
+			engineRPM:  transformer.EngineRPM(),
+			steering:   transformer.SteeringWheelAngleRadians(),
+		})
+	}
+
+	return frames
+}
+
+func (suite *ConvertTestSuite) TestConvertsBetweenFormats() {
+	tests := map[string]struct {
+		format        models.Name
+		extension     string
+		wantExtended  bool
+		wantTruncated bool
+		wantSteering  bool
+	}{
+		"downgrade to standard":  {format: models.Standard, extension: "gtr", wantTruncated: true},
+		"downgrade to addendum1": {format: models.Addendum1, extension: "gtz", wantTruncated: true, wantSteering: true},
+		"downgrade to addendum2": {format: models.Addendum2, extension: "gtr", wantTruncated: true, wantSteering: true},
+		"same format":            {format: models.Addendum3, extension: "gtz", wantSteering: true},
+	}
+
+	// Arrange
+	source := suite.readFrames("data/replays/demo.gtz")
+	suite.Require().NotEmpty(source)
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			path := filepath.Join(suite.tmpDir, string(tc.format)+"."+tc.extension)
+
+			// Act
+			report, err := gttelemetry.ConvertRecording(context.Background(), "data/replays/demo.gtz", path,
+				gttelemetry.ConvertOptions{Format: tc.format})
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.Equal(len(source), report.Packets)
+			suite.Equal(tc.wantExtended, report.Extended > 0)
+			suite.Equal(tc.wantTruncated, report.Truncated > 0)
+			suite.Zero(report.Invalid)
+
+			converted := suite.readFrames(path)
+			suite.Require().Len(converted, len(source))
+
+			for i, frame := range converted {
+				suite.Equal(tc.format, frame.format)
+				suite.Equal(source[i].sequenceID, frame.sequenceID)
+				suite.InDelta(source[i].engineRPM, frame.engineRPM, 0)
+
+				if tc.wantSteering {
+					suite.InDelta(source[i].steering, frame.steering, 0)
+				} else {
+					suite.Zero(frame.steering)
+				}
+			}
+		})
+	}
+}
+
+func (suite *ConvertTestSuite) TestUpgradeZeroFillsMissingFields() {
+	// Arrange
+	standard := filepath.Join(suite.tmpDir, "standard.gtr")
+	upgraded := filepath.Join(suite.tmpDir, "upgraded.gtz")
+
+	_, err := gttelemetry.ConvertRecording(context.Background(), "data/replays/demo.gtz", standard,
+		gttelemetry.ConvertOptions{Format: models.Standard})
+	suite.Require().NoError(err)
+
+	// Act
+	report, err := gttelemetry.ConvertRecording(context.Background(), standard, upgraded,
+		gttelemetry.ConvertOptions{Format: models.Addendum3})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(report.Packets, report.Extended)
+
+	frames := suite.readFrames(upgraded)
+	suite.Require().Len(frames, report.Packets)
+	suite.Equal(models.Addendum3, frames[0].format)
+	suite.NotZero(frames[len(frames)/2].engineRPM)
+
+	for _, frame := range frames {
+		suite.Zero(frame.steering)
+	}
+}
+
+func (suite *ConvertTestSuite) TestSidecarsAreCopied() {
+	// Arrange
+	source := filepath.Join(suite.tmpDir, "source.gtr")
+	converted := filepath.Join(suite.tmpDir, "converted.gtr")
+
+	_, err := gttelemetry.ConvertRecording(context.Background(), "data/replays/demo.gtz", source,
+		gttelemetry.ConvertOptions{Format: models.Addendum3})
+	suite.Require().NoError(err)
+	suite.Require().NoError(gttelemetry.AddRecordingMarker(source, gttelemetry.Marker{SequenceID: 1200, Label: "spin"}))
+
+	// Act
+	_, err = gttelemetry.ConvertRecording(context.Background(), source, converted,
+		gttelemetry.ConvertOptions{Format: models.Addendum1})
+
+	// Assert
+	suite.Require().NoError(err)
+
+	markers, err := gttelemetry.ReadRecordingMarkers(converted)
+	suite.Require().NoError(err)
+	suite.Equal([]gttelemetry.Marker{{SequenceID: 1200, Label: "spin"}}, markers)
+}
+
+func (suite *ConvertTestSuite) TestInvalidConversions() {
+	tests := map[string]struct {
+		opts    gttelemetry.ConvertOptions
+		path    string
+		wantErr error
+	}{
+		"unknown format":        {opts: gttelemetry.ConvertOptions{Format: "X"}, path: "x.gtz", wantErr: gttelemetry.ErrUnknownConversionFormat},
+		"encrypted plain file":  {opts: gttelemetry.ConvertOptions{Format: models.Standard, Password: "secret"}, path: "x.gtr", wantErr: gttelemetry.ErrRecordingNotEncryptable},
+		"unsupported extension": {opts: gttelemetry.ConvertOptions{Format: models.Standard}, path: "x.csv", wantErr: gttelemetry.ErrUnsupportedFileExtension},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Act
+			_, err := gttelemetry.ConvertRecording(context.Background(), "data/replays/demo.gtz",
+				filepath.Join(suite.tmpDir, tc.path), tc.opts)
+
+			// Assert
+			suite.Require().ErrorIs(err, tc.wantErr)
+		})
+	}
+}
+
+func (suite *ConvertTestSuite) TestCancelledConversionIsRemoved() {
+	// Arrange
+	path := filepath.Join(suite.tmpDir, "cancelled.gtz")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, err := gttelemetry.ConvertRecording(ctx, "data/replays/demo.gtz", path,
+		gttelemetry.ConvertOptions{Format: models.Standard})
+
+	// Assert
+	suite.Require().ErrorIs(err, context.Canceled)
+	suite.NoFileExists(path)
+}
@@ -0,0 +1,105 @@
+package gttelemetry
+
+import "fmt"
+
+// CornerSpeed is the minimum speed of a lap through a corner.
+type CornerSpeed struct {
+	MinimumSpeed float32 `json:"minimumSpeed"`
+	MinimumGear  int     `json:"minimumGear"`
+	// Best is true for the first lap with the highest minimum speed of the session through the corner.
+	Best bool `json:"best"`
+}
+
+// LapCornerSpeeds is the minimum speed of a lap through each corner, in corner order.
+type LapCornerSpeeds struct {
+	Lap     int16         `json:"lap"`
+	Corners []CornerSpeed `json:"corners"`
+}
+
+// CornerSpeedTable is the minimum speed of every lap of a session through each corner.
+type CornerSpeedTable struct {
+	// ReferenceLap is the lap the corners were found in.
+	ReferenceLap int16 `json:"referenceLap"`
+	// ApexDistances is the distance of the apex of each corner from the start of the reference lap in metres.
+	ApexDistances []float32         `json:"apexDistances"`
+	Laps          []LapCornerSpeeds `json:"laps"`
+}
+
+// NewCornerSpeedTable builds a table of the minimum speed of each lap through every corner. The inventory does not
+// describe the corners of a circuit, so they are found in the speed of the reference lap, in the same way as
+// CompareLaps, and the other laps are aligned with it by distance. The fastest lap is a good reference.
+func NewCornerSpeedTable(reference LapTrace, laps []LapTrace) (CornerSpeedTable, error) {
+	table := CornerSpeedTable{
+		ReferenceLap: reference.Lap,
+		Laps:         make([]LapCornerSpeeds, 0, len(laps)),
+	}
+
+	for _, lap := range laps {
+		comparison, err := CompareLaps(reference, lap, DefaultCompareStep)
+		if err != nil {
+			return CornerSpeedTable{}, fmt.Errorf("lap %d: %w", lap.Lap, err)
+		}
+
+		if table.ApexDistances == nil {
+			table.ApexDistances = make([]float32, 0, len(comparison.Corners))
+			for _, corner := range comparison.Corners {
+				table.ApexDistances = append(table.ApexDistances, corner.ApexDistance)
+			}
+		}
+
+		speeds := LapCornerSpeeds{Lap: lap.Lap, Corners: make([]CornerSpeed, 0, len(comparison.Corners))}
+		for _, corner := range comparison.Corners {
+			speeds.Corners = append(speeds.Corners, CornerSpeed{
+				MinimumSpeed: corner.MinimumSpeed,
+				MinimumGear:  corner.MinimumGear,
+			})
+		}
+
+		table.Laps = append(table.Laps, speeds)
+	}
+
+	table.markBest()
+
+	return table, nil
+}
+
+// Best returns the highest minimum speed of the session through each corner.
+func (c CornerSpeedTable) Best() []float32 {
+	best := make([]float32, len(c.ApexDistances))
+
+	for _, lap := range c.Laps {
+		for corner, speed := range lap.Corners {
+			if speed.Best {
+				best[corner] = speed.MinimumSpeed
+			}
+		}
+	}
+
+	return best
+}
+
+func (c CornerSpeedTable) markBest() {
+	for corner := range c.ApexDistances {
+		bestLap := -1
+
+		for i, lap := range c.Laps {
+			if bestLap < 0 || lap.Corners[corner].MinimumSpeed > c.Laps[bestLap].Corners[corner].MinimumSpeed {
+				bestLap = i
+			}
+		}
+
+		if bestLap >= 0 {
+			c.Laps[bestLap].Corners[corner].Best = true
+		}
+	}
+}
+
+// CornerSpeeds builds the corner speed table of every completed lap, with the corners found in the fastest lap.
+func (r *LapTraceRecorder) CornerSpeeds() (CornerSpeedTable, error) {
+	reference, ok := r.Fastest()
+	if !ok {
+		return CornerSpeedTable{}, ErrEmptyLapTrace
+	}
+
+	return NewCornerSpeedTable(reference, r.traces)
+}
@@ -0,0 +1,55 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type CornerSpeedTableTestSuite struct {
+	suite.Suite
+}
+
+func TestCornerSpeedTableTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CornerSpeedTableTestSuite))
+}
+
+func (suite *CornerSpeedTableTestSuite) TestMinimumSpeedsWithBestOfSession() {
+	// Arrange
+	reference := syntheticLap(2, 90)
+	laps := []gttelemetry.LapTrace{syntheticLap(1, 80), reference, syntheticLap(3, 95)}
+
+	// Act
+	table, err := gttelemetry.NewCornerSpeedTable(reference, laps)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(int16(2), table.ReferenceLap)
+	suite.Require().Len(table.ApexDistances, 1)
+	suite.InDelta(500, table.ApexDistances[0], 0.01)
+	suite.Require().Len(table.Laps, 3)
+	suite.InDelta(80, table.Laps[0].Corners[0].MinimumSpeed, 0.01)
+	suite.Equal(3, table.Laps[0].Corners[0].MinimumGear)
+	suite.False(table.Laps[0].Corners[0].Best)
+	suite.False(table.Laps[1].Corners[0].Best)
+	suite.True(table.Laps[2].Corners[0].Best)
+	suite.InDelta(95, table.Best()[0], 0.01)
+}
+
+func (suite *CornerSpeedTableTestSuite) TestEmptyLapIsRejected() {
+	// Act
+	_, err := gttelemetry.NewCornerSpeedTable(syntheticLap(1, 90), []gttelemetry.LapTrace{{Lap: 2}})
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrEmptyLapTrace)
+}
+
+func (suite *CornerSpeedTableTestSuite) TestRecorderWithoutLaps() {
+	// Act
+	_, err := gttelemetry.NewLapTraceRecorder().CornerSpeeds()
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrEmptyLapTrace)
+}
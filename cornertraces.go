@@ -0,0 +1,159 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CornerTracePoint is the state of a lap at a point through a corner.
+type CornerTracePoint struct {
+	// Distance is the distance from the start of the corner on the reference lap in metres.
+	Distance float32 `json:"distance"`
+	// Elapsed is the time since the lap entered the corner.
+	Elapsed         time.Duration `json:"elapsed"`
+	SpeedKPH        float32       `json:"speedKPH"`
+	ThrottlePercent float32       `json:"throttlePercent"`
+	BrakePercent    float32       `json:"brakePercent"`
+	Gear            int           `json:"gear"`
+}
+
+// CornerLapTrace is the trace of a lap through a corner.
+type CornerLapTrace struct {
+	Lap int16 `json:"lap"`
+	// Time is the time the lap took through the corner.
+	Time   time.Duration      `json:"time"`
+	Points []CornerTracePoint `json:"points"`
+}
+
+// CornerTrace is the trace of every lap through a corner, sampled at the same distances through the corner so that
+// the laps can be overlaid.
+type CornerTrace struct {
+	// Corner is the number of the corner, counting from 1 at the start of the lap.
+	Corner int `json:"corner"`
+	// StartDistance, ApexDistance and EndDistance are the distances of the corner from the start of the reference
+	// lap in metres.
+	StartDistance float32          `json:"startDistance"`
+	ApexDistance  float32          `json:"apexDistance"`
+	EndDistance   float32          `json:"endDistance"`
+	Laps          []CornerLapTrace `json:"laps"`
+}
+
+// NewCornerTraces extracts the speed, inputs and gear of every lap through each corner, at points every step metres
+// through the corner, defaulting to DefaultCompareStep. Corners are found in the speed of the reference lap in the
+// same way as CompareLaps, and the laps are aligned with it by the fraction of the lap completed.
+func NewCornerTraces(reference LapTrace, laps []LapTrace, step float32) ([]CornerTrace, error) {
+	corners, err := CompareLaps(reference, reference, step)
+	if err != nil {
+		return nil, fmt.Errorf("reference lap %d: %w", reference.Lap, err)
+	}
+
+	if step <= 0 {
+		step = DefaultCompareStep
+	}
+
+	referenceLength := reference.Samples[len(reference.Samples)-1].Distance
+
+	traces := make([]CornerTrace, 0, len(corners.Corners))
+	for _, corner := range corners.Corners {
+		traces = append(traces, CornerTrace{
+			Corner:        corner.Corner,
+			StartDistance: corner.StartDistance,
+			ApexDistance:  corner.ApexDistance,
+			EndDistance:   corner.EndDistance,
+			Laps:          make([]CornerLapTrace, 0, len(laps)),
+		})
+	}
+
+	for _, lap := range laps {
+		if len(lap.Samples) == 0 {
+			return nil, fmt.Errorf("lap %d: %w", lap.Lap, ErrEmptyLapTrace)
+		}
+
+		scale := float32(1)
+		if referenceLength > 0 {
+			scale = lap.Samples[len(lap.Samples)-1].Distance / referenceLength
+		}
+
+		for i := range traces {
+			traces[i].Laps = append(traces[i].Laps, traces[i].traceLap(lap, scale, step))
+		}
+	}
+
+	return traces, nil
+}
+
+// traceLap samples a lap through the corner, with the distances of the lap scaled to those of the reference lap.
+func (c CornerTrace) traceLap(lap LapTrace, scale, step float32) CornerLapTrace {
+	trace := CornerLapTrace{Lap: lap.Lap}
+	entry := sampleAtDistance(lap.Samples, c.StartDistance*scale)
+
+	for distance := c.StartDistance; ; distance += step {
+		distance = min(distance, c.EndDistance)
+		sample := sampleAtDistance(lap.Samples, distance*scale)
+
+		trace.Points = append(trace.Points, CornerTracePoint{
+			Distance:        distance - c.StartDistance,
+			Elapsed:         sample.Elapsed - entry.Elapsed,
+			SpeedKPH:        sample.SpeedKPH,
+			ThrottlePercent: sample.ThrottlePercent,
+			BrakePercent:    sample.BrakePercent,
+			Gear:            sample.Gear,
+		})
+
+		if distance >= c.EndDistance {
+			break
+		}
+	}
+
+	trace.Time = trace.Points[len(trace.Points)-1].Elapsed
+
+	return trace
+}
+
+// WriteCSV writes the points of every lap through the corner to w as CSV with a header row, one row per point.
+// Elapsed time is written in seconds.
+func (c CornerTrace) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"lap", "distanceMetres", "elapsedSeconds", "speedKPH", "throttlePercent", "brakePercent", "gear"})
+	if err != nil {
+		return fmt.Errorf("write corner trace CSV header: %w", err)
+	}
+
+	for _, lap := range c.Laps {
+		number := strconv.Itoa(int(lap.Lap))
+
+		for _, point := range lap.Points {
+			err = writer.Write([]string{
+				number,
+				formatDecimal(float64(point.Distance), 2),
+				formatDecimal(point.Elapsed.Seconds(), 3),
+				formatDecimal(float64(point.SpeedKPH), 2),
+				formatDecimal(float64(point.ThrottlePercent), 1),
+				formatDecimal(float64(point.BrakePercent), 1),
+				strconv.Itoa(point.Gear),
+			})
+			if err != nil {
+				return fmt.Errorf("write corner trace CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// CornerTraces extracts the trace of every completed lap through each corner, with the corners found in the fastest
+// lap.
+func (r *LapTraceRecorder) CornerTraces(step float32) ([]CornerTrace, error) {
+	reference, ok := r.Fastest()
+	if !ok {
+		return nil, ErrEmptyLapTrace
+	}
+
+	return NewCornerTraces(reference, r.traces, step)
+}
@@ -0,0 +1,86 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type CornerTracesTestSuite struct {
+	suite.Suite
+}
+
+func TestCornerTracesTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CornerTracesTestSuite))
+}
+
+func (suite *CornerTracesTestSuite) TestLapsAreAlignedThroughCorner() {
+	// Arrange
+	reference := syntheticLap(2, 100)
+	laps := []gttelemetry.LapTrace{reference, syntheticLap(3, 80)}
+
+	// Act
+	traces, err := gttelemetry.NewCornerTraces(reference, laps, 10)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(traces, 1)
+
+	corner := traces[0]
+	suite.Equal(1, corner.Corner)
+	suite.InDelta(500, corner.ApexDistance, 0.01)
+	suite.Require().Len(corner.Laps, 2)
+	suite.Equal(int16(3), corner.Laps[1].Lap)
+
+	fast, slow := corner.Laps[0], corner.Laps[1]
+	suite.Require().Len(slow.Points, len(fast.Points))
+
+	apex := int((corner.ApexDistance - corner.StartDistance) / 10)
+	suite.InDelta(0, fast.Points[0].Distance, 0.01)
+	suite.Zero(fast.Points[0].Elapsed)
+	suite.InDelta(fast.Points[apex].Distance, slow.Points[apex].Distance, 0.01)
+	suite.InDelta(100, fast.Points[apex].SpeedKPH, 0.01)
+	suite.InDelta(80, slow.Points[apex].SpeedKPH, 0.01)
+	suite.Greater(slow.Time, fast.Time)
+}
+
+func (suite *CornerTracesTestSuite) TestWriteCSV() {
+	// Arrange
+	reference := syntheticLap(2, 100)
+
+	traces, err := gttelemetry.NewCornerTraces(reference, []gttelemetry.LapTrace{reference}, 10)
+	suite.Require().NoError(err)
+
+	var buf bytes.Buffer
+
+	// Act
+	err = traces[0].WriteCSV(&buf)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	suite.Equal("lap,distanceMetres,elapsedSeconds,speedKPH,throttlePercent,brakePercent,gear", lines[0])
+	suite.Len(lines, len(traces[0].Laps[0].Points)+1)
+	suite.True(strings.HasPrefix(lines[1], "2,0.00,0.000,"))
+}
+
+func (suite *CornerTracesTestSuite) TestEmptyLapIsRejected() {
+	// Act
+	_, err := gttelemetry.NewCornerTraces(syntheticLap(1, 90), []gttelemetry.LapTrace{{Lap: 2}}, 0)
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrEmptyLapTrace)
+}
+
+func (suite *CornerTracesTestSuite) TestRecorderWithoutLaps() {
+	// Act
+	_, err := gttelemetry.NewLapTraceRecorder().CornerTraces(0)
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrEmptyLapTrace)
+}
@@ -0,0 +1,185 @@
+package gttelemetry
+
+import (
+	"sync"
+	"time"
+)
+
+// dropRateSmoothing is the weight of the latest interval between packets in the packet rate reported with drops.
+const dropRateSmoothing = 0.1
+
+// DropCause is the likely cause of a burst of dropped packets.
+type DropCause string
+
+const (
+	// DropCauseNetwork is packets lost before they reached the client while it kept up with the stream, typically
+	// interference or congestion on a Wi-Fi network.
+	DropCauseNetwork DropCause = "network"
+	// DropCauseStall is packets dropped while the client was still processing the packet before them, such as when a
+	// slow disk held up recording, so the receive buffer overflowed.
+	DropCauseStall DropCause = "stall"
+)
+
+// PacketDrop describes a burst of packets missing from the stream, found from the jump in sequence ID of the first
+// packet received after it.
+type PacketDrop struct {
+	// SequenceID is the sequence ID of the first packet received after the drop.
+	SequenceID uint32 `json:"sequenceId"`
+	// Count is the number of packets dropped in the burst.
+	Count int `json:"count"`
+	// ReceivedAt is the time the first packet after the drop was received, zero for recordings without times.
+	ReceivedAt time.Time `json:"receivedAt,omitzero"`
+	// PacketRate is the rate packets were received at before the drop, in packets per second, zero when unknown.
+	PacketRate float64 `json:"packetRate"`
+	// SinceLastDrop is the stream time since the previous drop, counted in frames, or zero for the first drop.
+	SinceLastDrop time.Duration `json:"sinceLastDrop"`
+	// ReceiveGap is the time between receiving the packets either side of the drop, zero when unknown.
+	ReceiveGap time.Duration `json:"receiveGap"`
+	// ProcessingTime is the time the client spent processing the packet before the drop.
+	ProcessingTime time.Duration `json:"processingTime"`
+	// Cause is the likely cause of the drop.
+	Cause DropCause `json:"cause"`
+}
+
+// DropSummary summarises the packet drops of a client.
+type DropSummary struct {
+	// Drops is the number of bursts of dropped packets.
+	Drops int `json:"drops"`
+	// PacketsDropped is the number of packets dropped across all bursts.
+	PacketsDropped int `json:"packetsDropped"`
+	// LargestBurst is the most packets dropped in a single burst.
+	LargestBurst int `json:"largestBurst"`
+	// NetworkDrops and StallDrops count the bursts by cause.
+	NetworkDrops int `json:"networkDrops"`
+	StallDrops   int `json:"stallDrops"`
+	// MeanTimeBetweenDrops is the average stream time between consecutive drops, zero with fewer than two drops.
+	MeanTimeBetweenDrops time.Duration `json:"meanTimeBetweenDrops"`
+	// LastDrop is the most recent drop.
+	LastDrop *PacketDrop `json:"lastDrop,omitempty"`
+}
+
+// dropMonitor detects dropped packets in the frames processed by the client, with the context needed to tell network
+// loss apart from the client stalling.
+type dropMonitor struct {
+	onDrop func(PacketDrop)
+
+	// mu guards the summary, which is read from other goroutines
+	mu      sync.Mutex
+	summary DropSummary
+
+	lastSequence     uint32
+	lastReceivedAt   time.Time
+	lastDropSequence uint32
+	framesBetween    uint64
+	interval         float64
+	processing       time.Duration
+}
+
+// observe checks the frame for packets missing since the previous frame.
+func (m *dropMonitor) observe(sequenceID uint32, receivedAt time.Time) {
+	lastSequence, lastReceivedAt := m.lastSequence, m.lastReceivedAt
+	m.lastSequence, m.lastReceivedAt = sequenceID, receivedAt
+
+	// The first frame, a repeated frame and a sequence ID that goes backwards have nothing to measure from
+	if lastSequence == 0 || sequenceID <= lastSequence {
+		return
+	}
+
+	receiveGap := time.Duration(0)
+	if !receivedAt.IsZero() && !lastReceivedAt.IsZero() {
+		receiveGap = receivedAt.Sub(lastReceivedAt)
+	}
+
+	count := int(sequenceID - lastSequence - 1)
+	if count == 0 {
+		m.observeInterval(receiveGap)
+
+		return
+	}
+
+	drop := PacketDrop{
+		SequenceID:     sequenceID,
+		Count:          count,
+		ReceivedAt:     receivedAt,
+		ReceiveGap:     receiveGap,
+		ProcessingTime: m.processing,
+		Cause:          dropCause(count, m.processing),
+	}
+
+	if m.interval > 0 {
+		drop.PacketRate = 1 / m.interval
+	}
+
+	m.mu.Lock()
+
+	if m.lastDropSequence != 0 && lastSequence > m.lastDropSequence {
+		drop.SinceLastDrop = framesElapsed(m.lastDropSequence, lastSequence)
+		m.framesBetween += uint64(lastSequence - m.lastDropSequence)
+		m.summary.MeanTimeBetweenDrops = framesDuration(m.framesBetween) / time.Duration(m.summary.Drops)
+	}
+
+	m.lastDropSequence = sequenceID
+	m.summary.Drops++
+	m.summary.PacketsDropped += count
+	m.summary.LargestBurst = max(m.summary.LargestBurst, count)
+
+	switch drop.Cause {
+	case DropCauseNetwork:
+		m.summary.NetworkDrops++
+	case DropCauseStall:
+		m.summary.StallDrops++
+	}
+
+	m.summary.LastDrop = &drop
+
+	m.mu.Unlock()
+
+	if m.onDrop != nil {
+		m.onDrop(drop)
+	}
+}
+
+// observeInterval updates the average interval between consecutive packets with the latest, ignoring unknown intervals.
+func (m *dropMonitor) observeInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	if m.interval == 0 {
+		m.interval = interval.Seconds()
+
+		return
+	}
+
+	m.interval = dropRateSmoothing*interval.Seconds() + (1-dropRateSmoothing)*m.interval
+}
+
+// processed records the time taken to process the latest frame.
+func (m *dropMonitor) processed(processing time.Duration) {
+	m.processing = processing
+}
+
+// dropCause returns the likely cause of a burst of dropped packets. Packets keep arriving while the client processes
+// a frame, so a client busy for at least half the time the burst would have taken to send stalled the stream.
+func dropCause(count int, processing time.Duration) DropCause {
+	if processing >= time.Duration(count)*FrameInterval/2 {
+		return DropCauseStall
+	}
+
+	return DropCauseNetwork
+}
+
+// PacketDrops returns a summary of the packets dropped from the stream so far, which is updated whether or not
+// Options.OnPacketDropped or statistics are enabled.
+func (c *Client) PacketDrops() DropSummary {
+	c.drops.mu.Lock()
+	defer c.drops.mu.Unlock()
+
+	summary := c.drops.summary
+	if summary.LastDrop != nil {
+		last := *summary.LastDrop
+		summary.LastDrop = &last
+	}
+
+	return summary
+}
@@ -0,0 +1,157 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type DropsTestSuite struct {
+	suite.Suite
+}
+
+func TestDropsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(DropsTestSuite))
+}
+
+// demoPackets returns the packets of the demo replay.
+func (suite *DropsTestSuite) demoPackets() [][]byte {
+	file, err := os.Open("data/replays/demo.gtz")
+	suite.Require().NoError(err)
+
+	defer file.Close()
+
+	decompressed, err := gzip.NewReader(file)
+	suite.Require().NoError(err)
+
+	content, err := io.ReadAll(decompressed)
+	suite.Require().NoError(err)
+
+	header := []byte{0x30, 0x53, 0x37, 0x47}
+
+	var packets [][]byte
+
+	for len(content) > 0 {
+		end := bytes.Index(content[len(header):], header)
+		if end == -1 {
+			packets = append(packets, content)
+
+			break
+		}
+
+		packets = append(packets, content[:len(header)+end])
+		content = content[len(header)+end:]
+	}
+
+	return packets
+}
+
+// scanWithout scans a recording of the first packets of the demo replay with the packets at the given indexes left
+// out, returning the drops reported and the client.
+func (suite *DropsTestSuite) scanWithout(
+	packets int, dropped []int, onDrop func(gttelemetry.PacketDrop),
+) ([]gttelemetry.PacketDrop, *gttelemetry.Client) {
+	skip := make(map[int]bool, len(dropped))
+	for _, index := range dropped {
+		skip[index] = true
+	}
+
+	var recording bytes.Buffer
+
+	for index, packet := range suite.demoPackets()[:packets] {
+		if !skip[index] {
+			recording.Write(packet)
+		}
+	}
+
+	path := filepath.Join(suite.T().TempDir(), "drops.gtr")
+	suite.Require().NoError(os.WriteFile(path, recording.Bytes(), 0o600))
+
+	var drops []gttelemetry.PacketDrop
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:       "file://" + path,
+		LogLevel:     "error",
+		StatsEnabled: true,
+		OnPacketDropped: func(drop gttelemetry.PacketDrop) {
+			drops = append(drops, drop)
+			if onDrop != nil {
+				onDrop(drop)
+			}
+		},
+	})
+	suite.Require().NoError(err)
+
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	return drops, client
+}
+
+func (suite *DropsTestSuite) TestNoDropsInCompleteRecording() {
+	// Arrange & Act
+	drops, client := suite.scanWithout(1000, nil, nil)
+
+	// Assert
+	suite.Empty(drops)
+	suite.Equal(gttelemetry.DropSummary{}, client.PacketDrops())
+}
+
+func (suite *DropsTestSuite) TestBurstsAreReportedWithContext() {
+	// Arrange
+	dropped := []int{100, 101, 102, 400, 700, 701, 702, 703, 704}
+
+	// Act
+	drops, client := suite.scanWithout(1000, dropped, nil)
+	summary := client.PacketDrops()
+
+	// Assert
+	suite.Require().Len(drops, 3)
+	suite.Equal([]int{3, 1, 5}, []int{drops[0].Count, drops[1].Count, drops[2].Count})
+	suite.Zero(drops[0].SinceLastDrop)
+	suite.Equal(296*time.Second/gttelemetry.FrameRate, drops[1].SinceLastDrop)
+	suite.Equal(298*time.Second/gttelemetry.FrameRate, drops[2].SinceLastDrop)
+
+	for _, drop := range drops {
+		suite.Equal(gttelemetry.DropCauseNetwork, drop.Cause)
+	}
+
+	suite.Equal(3, summary.Drops)
+	suite.Equal(len(dropped), summary.PacketsDropped)
+	suite.Equal(client.Statistics.PacketsDropped, summary.PacketsDropped)
+	suite.Equal(5, summary.LargestBurst)
+	suite.Equal(3, summary.NetworkDrops)
+	suite.Zero(summary.StallDrops)
+	suite.Equal((296+298)*time.Second/gttelemetry.FrameRate/2, summary.MeanTimeBetweenDrops)
+	suite.Require().NotNil(summary.LastDrop)
+	suite.Equal(drops[2], *summary.LastDrop)
+}
+
+func (suite *DropsTestSuite) TestSlowProcessingIsAStall() {
+	// Arrange
+	dropped := []int{100, 101, 103, 104}
+	slow := func(gttelemetry.PacketDrop) {
+		// Holding up the decoding goroutine on the first drop stalls the stream before the second
+		time.Sleep(3 * gttelemetry.FrameInterval)
+	}
+
+	// Act
+	drops, client := suite.scanWithout(200, dropped, slow)
+
+	// Assert
+	suite.Require().Len(drops, 2)
+	suite.Equal(gttelemetry.DropCauseNetwork, drops[0].Cause)
+	suite.Equal(gttelemetry.DropCauseStall, drops[1].Cause)
+	suite.GreaterOrEqual(drops[1].ProcessingTime, 3*gttelemetry.FrameInterval)
+	suite.Equal(1, client.PacketDrops().StallDrops)
+}
@@ -0,0 +1,68 @@
+//go:build !tinygo
+
+package gttelemetry
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrExpvarNameInUse indicates an expvar name that has already been published by another package.
+var ErrExpvarNameInUse = errors.New("expvar name already in use")
+
+// expvarStatistics maps each name published by publishStatistics to the statistics currently read under it. expvar
+// cannot unpublish a name, so a client created later with the same name replaces the statistics instead.
+var expvarStatistics sync.Map //nolint:gochecknoglobals // mirrors the process wide expvar registry
+
+// publishStatistics publishes the statistics with expvar under the name.
+func publishStatistics(name string, stats *statistics) error {
+	current := &atomic.Pointer[statistics]{}
+
+	existing, loaded := expvarStatistics.LoadOrStore(name, current)
+	if loaded {
+		existing.(*atomic.Pointer[statistics]).Store(stats) //nolint:forcetypeassert // only pointers are stored
+
+		return nil
+	}
+
+	if expvar.Get(name) != nil {
+		expvarStatistics.Delete(name)
+
+		return fmt.Errorf("%w: %s", ErrExpvarNameInUse, name)
+	}
+
+	current.Store(stats)
+	expvar.Publish(name, expvar.Func(func() any {
+		return current.Load().expvarValue()
+	}))
+
+	return nil
+}
+
+// expvarValue returns the statistics in the form published with expvar, with durations in microseconds.
+func (s *statistics) expvarValue() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value := map[string]any{
+		"decodeTimeAvgMicroseconds": s.DecodeTimeAvg.Microseconds(),
+		"decodeTimeMaxMicroseconds": s.DecodeTimeMax.Microseconds(),
+		"packetRateAvg":             s.PacketRateAvg,
+		"packetRateCurrent":         s.PacketRateCurrent,
+		"packetRateMax":             s.PacketRateMax,
+		"packetsDropped":            s.PacketsDropped,
+		"packetsInvalid":            s.PacketsInvalid,
+		"packetsTotal":              s.PacketsTotal,
+		"packetsUnexpectedSource":   s.PacketsUnexpectedSource,
+		"packetSize":                s.PacketSize,
+	}
+
+	if s.fanOut != nil {
+		value["fanOut"] = s.fanOut.Stats()
+	}
+
+	return value
+}
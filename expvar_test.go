@@ -0,0 +1,94 @@
+//go:build !tinygo
+
+package gttelemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type ExpvarTestSuite struct {
+	suite.Suite
+}
+
+func TestExpvarTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ExpvarTestSuite))
+}
+
+// packetsTotal returns the total packet count published under the expvar name.
+func (suite *ExpvarTestSuite) packetsTotal(name string) int {
+	published := expvar.Get(name)
+	suite.Require().NotNil(published)
+
+	var stats struct {
+		PacketsTotal int `json:"packetsTotal"`
+	}
+
+	suite.Require().NoError(json.Unmarshal([]byte(published.String()), &stats))
+
+	return stats.PacketsTotal
+}
+
+func (suite *ExpvarTestSuite) TestStatisticsArePublished() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:     "file://data/replays/demo.gtz",
+		LogLevel:   "error",
+		ExpvarName: "gttelemetryPublished",
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	// Assert
+	suite.Positive(suite.packetsTotal("gttelemetryPublished"))
+}
+
+func (suite *ExpvarTestSuite) TestLaterClientReplacesStatistics() {
+	// Arrange
+	first, err := gttelemetry.New(gttelemetry.Options{
+		Source:     "file://data/replays/demo.gtz",
+		LogLevel:   "error",
+		ExpvarName: "gttelemetryReplaced",
+	})
+	suite.Require().NoError(err)
+
+	for _, err := range first.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	// Act
+	_, err = gttelemetry.New(gttelemetry.Options{
+		Source:     "file://data/replays/demo.gtz",
+		LogLevel:   "error",
+		ExpvarName: "gttelemetryReplaced",
+	})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Zero(suite.packetsTotal("gttelemetryReplaced"))
+}
+
+func (suite *ExpvarTestSuite) TestNameInUseIsRejected() {
+	// Arrange
+	expvar.NewInt("gttelemetryTaken")
+
+	// Act
+	_, err := gttelemetry.New(gttelemetry.Options{
+		Source:     "file://data/replays/demo.gtz",
+		LogLevel:   "error",
+		ExpvarName: "gttelemetryTaken",
+	})
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrExpvarNameInUse)
+}
@@ -0,0 +1,15 @@
+//go:build tinygo
+
+package gttelemetry
+
+import (
+	"errors"
+)
+
+// ErrExpvarUnsupported indicates that statistics cannot be published with expvar in TinyGo builds.
+var ErrExpvarUnsupported = errors.New("expvar is not supported in TinyGo builds")
+
+// publishStatistics is not supported in TinyGo builds, read Client.Statistics directly instead.
+func publishStatistics(_ string, _ *statistics) error {
+	return ErrExpvarUnsupported
+}
@@ -0,0 +1,124 @@
+package gttelemetry
+
+import "time"
+
+// standardGravity is the acceleration due to gravity in metres per second squared, for expressing accelerations in g.
+const standardGravity = 9.80665
+
+// ChannelExtremes is the lowest and highest value of a channel.
+type ChannelExtremes struct {
+	Min float32 `json:"min"`
+	Max float32 `json:"max"`
+}
+
+// Extremes holds the lowest and highest value of every channel since the tracker was created or last reset, with the
+// values commonly shown at the end of a session picked out.
+type Extremes struct {
+	// Channels maps each channel name from ChannelNames to its extremes.
+	Channels                  map[string]ChannelExtremes `json:"channels"`
+	TopSpeedKPH               float32                    `json:"topSpeedKPH"`
+	MaxEngineRPM              float32                    `json:"maxEngineRPM"`
+	PeakBoostBar              float32                    `json:"peakBoostBar"`
+	MaxTyreTemperatureCelsius float32                    `json:"maxTyreTemperatureCelsius"`
+	// PeakDecelerationG is the hardest braking, derived from the change in ground speed between frames.
+	PeakDecelerationG float32 `json:"peakDecelerationG"`
+	// Duration is the time on circuit over which the extremes were collected.
+	Duration time.Duration `json:"duration"`
+}
+
+// ExtremesTracker records the extremes of every channel while the vehicle is on circuit, for end of session screens.
+// Frames in menus and while the game is paused are ignored, so the extremes are not skewed by the values reported
+// outside of driving.
+type ExtremesTracker struct {
+	lastSequence uint32
+	hasLastSpeed bool
+	lastSpeed    float32
+	channels     map[string]ChannelExtremes
+	deceleration float32
+	frames       uint64
+}
+
+// NewExtremesTracker creates a tracker without any extremes.
+func NewExtremesTracker() *ExtremesTracker {
+	return &ExtremesTracker{channels: make(map[string]ChannelExtremes, len(channels))}
+}
+
+// Update records the values of the latest telemetry frame.
+func (e *ExtremesTracker) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID == e.lastSequence {
+		return
+	}
+
+	previousSequence := e.lastSequence
+	e.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() || t.Flags().GamePaused {
+		e.hasLastSpeed = false
+
+		return
+	}
+
+	for name, read := range channels {
+		value := read(t)
+
+		extremes, ok := e.channels[name]
+		if !ok {
+			e.channels[name] = ChannelExtremes{Min: value, Max: value}
+
+			continue
+		}
+
+		extremes.Min = min(extremes.Min, value)
+		extremes.Max = max(extremes.Max, value)
+		e.channels[name] = extremes
+	}
+
+	speed := t.GroundSpeedMetresPerSecond()
+
+	if e.hasLastSpeed {
+		elapsed := framesElapsed(previousSequence, sequenceID)
+		if elapsed > 0 {
+			deceleration := (e.lastSpeed - speed) / float32(elapsed.Seconds()) / standardGravity
+			e.deceleration = max(e.deceleration, deceleration)
+			e.frames += uint64(sequenceID - previousSequence)
+		}
+	}
+
+	e.lastSpeed = speed
+	e.hasLastSpeed = true
+}
+
+// Extremes returns the extremes recorded so far.
+func (e *ExtremesTracker) Extremes() Extremes {
+	extremes := Extremes{
+		Channels:          make(map[string]ChannelExtremes, len(e.channels)),
+		PeakDecelerationG: e.deceleration,
+		Duration:          framesDuration(e.frames),
+	}
+
+	for name, channel := range e.channels {
+		extremes.Channels[name] = channel
+	}
+
+	extremes.TopSpeedKPH = e.channels["groundSpeedKPH"].Max
+	extremes.MaxEngineRPM = e.channels["engineRPM"].Max
+	extremes.PeakBoostBar = e.channels["turboBoostBar"].Max
+
+	extremes.MaxTyreTemperatureCelsius = max(
+		e.channels["tyreTemperatureCelsiusFrontLeft"].Max,
+		e.channels["tyreTemperatureCelsiusFrontRight"].Max,
+		e.channels["tyreTemperatureCelsiusRearLeft"].Max,
+		e.channels["tyreTemperatureCelsiusRearRight"].Max,
+	)
+
+	return extremes
+}
+
+// Reset clears the extremes, e.g. at the start of a new stint.
+func (e *ExtremesTracker) Reset() {
+	e.channels = make(map[string]ChannelExtremes, len(channels))
+	e.deceleration = 0
+	e.frames = 0
+	e.hasLastSpeed = false
+}
@@ -0,0 +1,107 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type ExtremesTrackerTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	tracker     *gttelemetry.ExtremesTracker
+}
+
+func TestExtremesTrackerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ExtremesTrackerTestSuite))
+}
+
+func (suite *ExtremesTrackerTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.transformer.SetTyreTemperature(80, 80, 80, 80)
+
+	suite.tracker = gttelemetry.NewExtremesTracker()
+}
+
+func (suite *ExtremesTrackerTestSuite) frame(speed, rpm float32) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.GroundSpeed = speed
+	suite.transformer.RawTelemetry.EngineRpm = rpm
+
+	suite.tracker.Update(suite.transformer)
+}
+
+func (suite *ExtremesTrackerTestSuite) TestExtremesAreTracked() {
+	// Arrange
+	suite.frame(40, 6000)
+	suite.transformer.SetTyreTemperature(80, 95, 82, 83)
+	suite.frame(60, 8000)
+
+	// Act
+	suite.frame(50, 4000)
+
+	// Assert
+	extremes := suite.tracker.Extremes()
+	suite.InDelta(216, extremes.TopSpeedKPH, 0.01)
+	suite.InDelta(8000, extremes.MaxEngineRPM, 0.01)
+	suite.InDelta(95, extremes.MaxTyreTemperatureCelsius, 0.01)
+	suite.InDelta(4000, extremes.Channels["engineRPM"].Min, 0.01)
+	suite.InDelta(144, extremes.Channels["groundSpeedKPH"].Min, 0.01)
+	suite.InDelta(2*gttelemetry.FrameInterval.Seconds(), extremes.Duration.Seconds(), 0.0001)
+}
+
+func (suite *ExtremesTrackerTestSuite) TestPeakDecelerationIsDerivedFromSpeed() {
+	// Arrange
+	suite.frame(60, 8000)
+
+	// Act
+	suite.frame(59.8, 7900)
+
+	// Assert
+	want := 0.2 / gttelemetry.FrameInterval.Seconds() / 9.80665
+	suite.InDelta(want, suite.tracker.Extremes().PeakDecelerationG, 0.05)
+}
+
+func (suite *ExtremesTrackerTestSuite) TestPausedAndMenuFramesAreIgnored() {
+	// Arrange
+	suite.frame(40, 6000)
+	suite.transformer.SetFlags(true, true, false, true, false, false, false, false, false, false, false, false)
+	suite.frame(0, 9000)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.transformer.RawTelemetry.RaceLaps = -1
+
+	// Act
+	suite.frame(0, 9500)
+
+	// Assert
+	extremes := suite.tracker.Extremes()
+	suite.InDelta(6000, extremes.MaxEngineRPM, 0.01)
+	suite.InDelta(144, extremes.Channels["groundSpeedKPH"].Min, 0.01)
+	suite.InDelta(0, extremes.PeakDecelerationG, 0.001)
+}
+
+func (suite *ExtremesTrackerTestSuite) TestResetClearsExtremes() {
+	// Arrange
+	suite.frame(60, 8000)
+	suite.frame(40, 6000)
+
+	// Act
+	suite.tracker.Reset()
+	suite.frame(30, 5000)
+
+	// Assert
+	extremes := suite.tracker.Extremes()
+	suite.InDelta(108, extremes.TopSpeedKPH, 0.01)
+	suite.InDelta(5000, extremes.MaxEngineRPM, 0.01)
+	suite.InDelta(0, extremes.PeakDecelerationG, 0.001)
+	suite.Equal(time.Duration(0), extremes.Duration)
+}
@@ -0,0 +1,145 @@
+package gttelemetry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownChannel indicates a channel name that is not one of ChannelNames.
+	ErrUnknownChannel = errors.New("unknown channel")
+	// ErrInvalidFilter indicates a filter with a time constant that is not positive.
+	ErrInvalidFilter = errors.New("filter time constant must be positive")
+)
+
+// filterBank holds the exponential moving average of each filtered channel.
+type filterBank struct {
+	mu           sync.Mutex
+	filters      map[string]*channelFilter
+	lastSequence uint32
+}
+
+// channelFilter is the smoothing state of a single channel.
+type channelFilter struct {
+	read         channelFunc
+	timeConstant time.Duration
+	value        float32
+	primed       bool
+}
+
+// clone returns a copy of the filter bank that is not advanced with the original.
+func (b *filterBank) clone() *filterBank {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	filters := make(map[string]*channelFilter, len(b.filters))
+	for name, filter := range b.filters {
+		copied := *filter
+		filters[name] = &copied
+	}
+
+	return &filterBank{filters: filters, lastSequence: b.lastSequence}
+}
+
+// SetFilters configures low-pass filters on noisy channels, mapping each channel name to the time constant of an
+// exponential moving average. Larger time constants give smoother values that lag further behind the raw values.
+// The filters replace any configured previously, and their state is reset.
+func (t *Transformer) SetFilters(timeConstants map[string]time.Duration) error {
+	filters := make(map[string]*channelFilter, len(timeConstants))
+
+	for name, timeConstant := range timeConstants {
+		read, ok := channels[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownChannel, name)
+		}
+
+		if timeConstant <= 0 {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidFilter, name, timeConstant)
+		}
+
+		filters[name] = &channelFilter{read: read, timeConstant: timeConstant}
+	}
+
+	t.filters.mu.Lock()
+	defer t.filters.mu.Unlock()
+
+	t.filters.filters = filters
+	t.filters.lastSequence = 0
+
+	return nil
+}
+
+// UpdateFilters advances the channel filters with the current frame. The client calls this once for each decoded
+// frame, repeated frames are ignored and the filters are reset when the sequence restarts.
+func (t *Transformer) UpdateFilters() {
+	t.filters.mu.Lock()
+	defer t.filters.mu.Unlock()
+
+	if len(t.filters.filters) == 0 {
+		return
+	}
+
+	sequenceID := t.SequenceID()
+	if sequenceID == t.filters.lastSequence {
+		return
+	}
+
+	elapsed := time.Second / FrameRate
+	reset := sequenceID < t.filters.lastSequence
+
+	if t.filters.lastSequence != 0 && !reset {
+		elapsed = framesElapsed(t.filters.lastSequence, sequenceID)
+	}
+
+	t.filters.lastSequence = sequenceID
+
+	for _, filter := range t.filters.filters {
+		value := filter.read(t)
+
+		if !filter.primed || reset {
+			filter.value = value
+			filter.primed = true
+
+			continue
+		}
+
+		alpha := 1 - math.Exp(-elapsed.Seconds()/filter.timeConstant.Seconds())
+		filter.value += float32(alpha) * (value - filter.value)
+	}
+}
+
+// Filtered returns the smoothed value of the named channel and whether the channel exists. Channels without a filter
+// return their raw value, which is also still available from Channel.
+func (t *Transformer) Filtered(name string) (float32, bool) {
+	t.filters.mu.Lock()
+	filter, ok := t.filters.filters[name]
+
+	if ok && filter.primed {
+		value := filter.value
+		t.filters.mu.Unlock()
+
+		return value, true
+	}
+
+	t.filters.mu.Unlock()
+
+	return t.Channel(name)
+}
+
+// FilteredChannels reads smoothed channel values, for passing to exporters that read channels by name.
+type FilteredChannels struct {
+	t *Transformer
+}
+
+// FilteredChannels returns a channel source that reads channels with Filtered.
+func (t *Transformer) FilteredChannels() FilteredChannels {
+	return FilteredChannels{t: t}
+}
+
+// Channel returns the smoothed value of the named channel and whether the channel exists.
+func (f FilteredChannels) Channel(name string) (float32, bool) {
+	return f.t.Filtered(name)
+}
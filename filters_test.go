@@ -0,0 +1,123 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type FiltersTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestFiltersTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(FiltersTestSuite))
+}
+
+func (suite *FiltersTestSuite) SetupTest() {
+	suite.transformer = gttelemetry.NewTransformer(nil)
+}
+
+// advance sets the engine RPM of the next frame and updates the filters.
+func (suite *FiltersTestSuite) advance(rpm float32) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.EngineRpm = rpm
+	suite.transformer.UpdateFilters()
+}
+
+func (suite *FiltersTestSuite) TestFilteredSmoothsStep() {
+	// Arrange
+	err := suite.transformer.SetFilters(map[string]time.Duration{"engineRPM": 100 * time.Millisecond})
+	suite.Require().NoError(err)
+
+	suite.advance(1000)
+
+	// Act
+	suite.advance(2000)
+
+	// Assert
+	filtered, ok := suite.transformer.Filtered("engineRPM")
+	suite.True(ok)
+	suite.InDelta(1153.5, filtered, 0.5)
+
+	raw, ok := suite.transformer.Channel("engineRPM")
+	suite.True(ok)
+	suite.InDelta(2000, raw, 0)
+}
+
+func (suite *FiltersTestSuite) TestFilteredConvergesOnSteadyValue() {
+	// Arrange
+	err := suite.transformer.SetFilters(map[string]time.Duration{"engineRPM": 100 * time.Millisecond})
+	suite.Require().NoError(err)
+
+	suite.advance(1000)
+
+	// Act
+	for range 120 {
+		suite.advance(3000)
+	}
+
+	// Assert
+	filtered, _ := suite.transformer.Filtered("engineRPM")
+	suite.InDelta(3000, filtered, 1)
+}
+
+func (suite *FiltersTestSuite) TestRepeatedFrameIsIgnored() {
+	// Arrange
+	err := suite.transformer.SetFilters(map[string]time.Duration{"engineRPM": 100 * time.Millisecond})
+	suite.Require().NoError(err)
+
+	suite.advance(1000)
+	suite.advance(2000)
+	want, _ := suite.transformer.Filtered("engineRPM")
+
+	// Act
+	suite.transformer.UpdateFilters()
+
+	// Assert
+	got, _ := suite.transformer.Filtered("engineRPM")
+	suite.InDelta(want, got, 0)
+}
+
+func (suite *FiltersTestSuite) TestUnfilteredChannelReturnsRawValue() {
+	// Arrange
+	suite.advance(4500)
+
+	// Act
+	got, ok := suite.transformer.FilteredChannels().Channel("engineRPM")
+
+	// Assert
+	suite.True(ok)
+	suite.InDelta(4500, got, 0)
+}
+
+func (suite *FiltersTestSuite) TestSetFiltersRejectsInvalidFilters() {
+	tests := map[string]struct {
+		filters map[string]time.Duration
+		wantErr error
+	}{
+		"unknown channel": {
+			filters: map[string]time.Duration{"warpSpeed": time.Second},
+			wantErr: gttelemetry.ErrUnknownChannel,
+		},
+		"zero time constant": {
+			filters: map[string]time.Duration{"engineRPM": 0},
+			wantErr: gttelemetry.ErrInvalidFilter,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			err := suite.transformer.SetFilters(test.filters)
+
+			// Assert
+			suite.ErrorIs(err, test.wantErr)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package gttelemetry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+const (
+	// DefaultSlipTolerance is the largest difference between the surface speed of the driven wheels and the ground
+	// speed, as a fraction of the ground speed, for a frame to be used to measure the final drive ratio.
+	DefaultSlipTolerance = 0.05
+
+	// finalDriveMinSpeed is the ground speed in metres per second below which the final drive ratio is not measured,
+	// as the wheel speeds are too coarse at walking pace.
+	finalDriveMinSpeed = 5
+	// finalDriveMinEngagement is the clutch engagement above which the engine is taken to be coupled to the wheels.
+	finalDriveMinEngagement = 0.99
+	// finalDriveMinRatio and finalDriveMaxRatio bound the final drive ratios found in road and race cars.
+	finalDriveMinRatio = 1.5
+	finalDriveMaxRatio = 8
+	// topSpeedConfidence is the confidence in a ratio estimated from the top speed rather than measured.
+	topSpeedConfidence = 0.25
+	// unknownDrivetrainConfidence scales the confidence in a ratio measured without knowing which axle is driven.
+	unknownDrivetrainConfidence = 0.8
+)
+
+// ErrInvalidFinalDriveOptions indicates a rolling radius correction or slip tolerance outside its allowed range.
+var ErrInvalidFinalDriveOptions = errors.New("invalid final drive options")
+
+// FinalDriveSource identifies how a final drive ratio was estimated.
+type FinalDriveSource string
+
+const (
+	// FinalDriveSourceNone marks a frame without enough data to estimate the ratio.
+	FinalDriveSourceNone FinalDriveSource = ""
+	// FinalDriveSourceMeasured marks a ratio measured from the engine and driven wheel speeds while the clutch was
+	// engaged, in the current frame or an earlier frame with the same setup.
+	FinalDriveSourceMeasured FinalDriveSource = "measured"
+	// FinalDriveSourceTopSpeed marks a ratio estimated from the transmission top speed ratio, which is only a rough
+	// guide as the game does not report the final drive directly.
+	FinalDriveSourceTopSpeed FinalDriveSource = "topSpeed"
+)
+
+// FinalDriveOptions configures the estimation of the final drive ratio.
+type FinalDriveOptions struct {
+	// RollingRadiusCorrection scales the tyre radius reported by the game to the rolling radius of the tyre at speed,
+	// which is used to tell wheelspin and locked wheels from grip, e.g. 1.02 for tyres that grow at speed. Defaults to
+	// 1, between 0.8 and 1.2.
+	RollingRadiusCorrection float32 `yaml:"rollingRadiusCorrection"`
+	// SlipTolerance is the largest difference between the surface speed of the driven wheels and the ground speed, as
+	// a fraction of the ground speed, for a frame to be measured. Defaults to DefaultSlipTolerance, less than 1.
+	SlipTolerance float32 `yaml:"slipTolerance"`
+}
+
+// FinalDriveEstimate is an estimate of the final drive ratio with the confidence in it, from 0 for no estimate to 1
+// for a ratio measured with the driven wheels rolling exactly at the ground speed.
+type FinalDriveEstimate struct {
+	Ratio      float32          `json:"ratio"`
+	Confidence float32          `json:"confidence"`
+	Source     FinalDriveSource `json:"source,omitempty"`
+}
+
+// withDefaults returns the options with the defaults of unset fields filled in.
+func (o FinalDriveOptions) withDefaults() FinalDriveOptions {
+	if o.RollingRadiusCorrection == 0 {
+		o.RollingRadiusCorrection = 1
+	}
+
+	if o.SlipTolerance == 0 {
+		o.SlipTolerance = DefaultSlipTolerance
+	}
+
+	return o
+}
+
+// validate checks the rolling radius correction and slip tolerance are within their allowed ranges.
+func (o FinalDriveOptions) validate() error {
+	o = o.withDefaults()
+
+	if o.RollingRadiusCorrection < 0.8 || o.RollingRadiusCorrection > 1.2 {
+		return fmt.Errorf("%w: rolling radius correction %v is not between 0.8 and 1.2", ErrInvalidFinalDriveOptions, o.RollingRadiusCorrection)
+	}
+
+	if o.SlipTolerance < 0 || o.SlipTolerance >= 1 {
+		return fmt.Errorf("%w: slip tolerance %v is not between 0 and 1", ErrInvalidFinalDriveOptions, o.SlipTolerance)
+	}
+
+	return nil
+}
+
+// finalDriveState holds the final drive options and the last ratio measured, which is kept while the setup of the
+// vehicle is unchanged so that frames during gear changes do not fall back to the top speed estimate.
+type finalDriveState struct {
+	mu       sync.Mutex
+	opts     FinalDriveOptions
+	setup    SetupFingerprint
+	measured FinalDriveEstimate
+}
+
+// clone returns a copy of the options and the ratio measured.
+func (s *finalDriveState) clone() *finalDriveState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &finalDriveState{opts: s.opts, setup: s.setup, measured: s.measured}
+}
+
+// SetFinalDriveOptions configures the estimation of the final drive ratio, and forgets any ratio already measured.
+func (t *Transformer) SetFinalDriveOptions(opts FinalDriveOptions) error {
+	err := opts.validate()
+	if err != nil {
+		return err
+	}
+
+	t.finalDrive.mu.Lock()
+	defer t.finalDrive.mu.Unlock()
+
+	t.finalDrive.opts = opts.withDefaults()
+	t.finalDrive.setup = ""
+	t.finalDrive.measured = FinalDriveEstimate{}
+
+	return nil
+}
+
+// FinalDriveEstimate returns the final drive ratio of the vehicle with the confidence in it.
+//
+// The ratio is measured from the engine speed and the speed of the driven wheels whenever the clutch is engaged and
+// the driven wheels are rolling within the slip tolerance of the ground speed. The most confident measurement is
+// returned for other frames until the setup of the vehicle changes. Before the first measurement the ratio is
+// estimated from the transmission top speed ratio with a low confidence, which can be far from the real ratio.
+func (t *Transformer) FinalDriveEstimate() FinalDriveEstimate {
+	return cached(t, cacheKeyFinalDriveEstimate, func() FinalDriveEstimate {
+		setup := t.SetupFingerprint()
+
+		t.finalDrive.mu.Lock()
+		defer t.finalDrive.mu.Unlock()
+
+		if t.finalDrive.setup != setup {
+			t.finalDrive.setup = setup
+			t.finalDrive.measured = FinalDriveEstimate{}
+		}
+
+		measured, ok := t.measureFinalDrive(t.finalDrive.opts.withDefaults())
+		if ok && measured.Confidence >= t.finalDrive.measured.Confidence {
+			t.finalDrive.measured = measured
+		}
+
+		if t.finalDrive.measured.Source == FinalDriveSourceMeasured {
+			return t.finalDrive.measured
+		}
+
+		return t.topSpeedFinalDrive()
+	})
+}
+
+// measureFinalDrive measures the final drive ratio from the current frame, returning false when the engine is not
+// coupled to the wheels or the driven wheels are slipping.
+func (t *Transformer) measureFinalDrive(opts FinalDriveOptions) (FinalDriveEstimate, bool) {
+	transmission := t.Transmission()
+	gear := t.CurrentGear()
+	groundSpeed := t.GroundSpeedMetresPerSecond()
+
+	if !t.Flags().InGear || gear < 1 || gear > transmission.Gears || transmission.GearRatios[gear-1] <= 0 ||
+		t.RawTelemetry.ClutchEngagement < finalDriveMinEngagement || groundSpeed < finalDriveMinSpeed || t.EngineRPM() <= 0 {
+		return FinalDriveEstimate{}, false
+	}
+
+	rps := t.WheelSpeedRadiansPerSecond()
+	radius := t.TyreRadiusMetres()
+
+	// Four wheel drive vehicles are measured at the rear axle, where most of the drive is sent
+	wheelRPS := (abs32(rps.RearLeft) + abs32(rps.RearRight)) / 2
+	wheelRadius := (radius.RearLeft + radius.RearRight) / 2
+
+	if t.VehicleDrivetrain() == "FF" {
+		wheelRPS = (abs32(rps.FrontLeft) + abs32(rps.FrontRight)) / 2
+		wheelRadius = (radius.FrontLeft + radius.FrontRight) / 2
+	}
+
+	if wheelRPS <= 0 || wheelRadius <= 0 {
+		return FinalDriveEstimate{}, false
+	}
+
+	slip := abs32(wheelRPS*wheelRadius*opts.RollingRadiusCorrection/groundSpeed - 1)
+	if slip > opts.SlipTolerance {
+		return FinalDriveEstimate{}, false
+	}
+
+	wheelRPM := wheelRPS * 60 / (2 * math.Pi)
+
+	ratio := t.EngineRPM() / wheelRPM / transmission.GearRatios[gear-1]
+	if ratio < finalDriveMinRatio || ratio > finalDriveMaxRatio {
+		return FinalDriveEstimate{}, false
+	}
+
+	confidence := 1 - slip/opts.SlipTolerance/2
+
+	if t.VehicleDrivetrain() == "" {
+		confidence *= unknownDrivetrainConfidence
+	}
+
+	return FinalDriveEstimate{Ratio: ratio, Confidence: confidence, Source: FinalDriveSourceMeasured}, true
+}
+
+// topSpeedFinalDrive estimates the final drive ratio from the calculated top speed and the transmission top speed
+// ratio.
+func (t *Transformer) topSpeedFinalDrive() FinalDriveEstimate {
+	transmission := t.Transmission()
+	if transmission.Gears == 0 {
+		return FinalDriveEstimate{Ratio: -1}
+	}
+
+	highestRatio := transmission.GearRatios[transmission.Gears-1]
+	vMax := t.CalculatedVmax()
+
+	var rollingDiameter float32
+
+	switch t.VehicleDrivetrain() {
+	case "FF":
+		rollingDiameter = t.TyreDiameterMetres().FrontLeft
+	default:
+		rollingDiameter = t.TyreDiameterMetres().RearLeft
+	}
+
+	if highestRatio <= 0 || rollingDiameter <= 0 || vMax.Speed == 0 {
+		return FinalDriveEstimate{Ratio: -1}
+	}
+
+	vMaxMetresPerMinute := float32(vMax.Speed) * 1000 / 60
+	wheelRpm := vMaxMetresPerMinute / (rollingDiameter * math.Pi)
+	ratio := (float32(vMax.RPM) / highestRatio) / wheelRpm
+
+	confidence := float32(topSpeedConfidence)
+	if ratio < finalDriveMinRatio || ratio > finalDriveMaxRatio {
+		confidence /= 2
+	}
+
+	return FinalDriveEstimate{Ratio: ratio, Confidence: confidence, Source: FinalDriveSourceTopSpeed}
+}
+
+// abs32 returns the absolute value of x.
+func abs32(x float32) float32 {
+	return float32(math.Abs(float64(x)))
+}
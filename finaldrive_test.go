@@ -0,0 +1,200 @@
+package gttelemetry_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+// gearbox is the published transmission of a production car.
+type gearbox struct {
+	drivetrain string
+	gears      []float32
+	finalDrive float32
+	tyreRadius float32
+}
+
+//nolint:gochecknoglobals // read-only test data
+var (
+	// mazdaMX5 is the ND MX-5 2.0 six speed manual on 195/50 R16 tyres.
+	mazdaMX5 = gearbox{
+		drivetrain: "FR",
+		gears:      []float32{5.087, 2.991, 2.035, 1.594, 1.286, 1.000},
+		finalDrive: 2.866,
+		tyreRadius: 0.3007,
+	}
+	// toyotaGR86 is the GR86 six speed manual on 215/40 R18 tyres.
+	toyotaGR86 = gearbox{
+		drivetrain: "FR",
+		gears:      []float32{3.626, 2.188, 1.541, 1.213, 1.000, 0.767},
+		finalDrive: 4.300,
+		tyreRadius: 0.3146,
+	}
+	// hondaCivicTypeR is the FK8 Civic Type R six speed manual on 245/30 R20 tyres.
+	hondaCivicTypeR = gearbox{
+		drivetrain: "FF",
+		gears:      []float32{3.625, 2.115, 1.529, 1.125, 0.911, 0.734},
+		finalDrive: 4.111,
+		tyreRadius: 0.3275,
+	}
+)
+
+type FinalDriveTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestFinalDriveTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(FinalDriveTestSuite))
+}
+
+func (suite *FinalDriveTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.transformer.RawTelemetry.ClutchEngagement = 1
+	suite.transformer.RawTelemetry.TransmissionTopSpeedRatio = 2.7
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 230
+}
+
+// drive sets up a frame of the car driven at the speed in a gear, with the tyres rolling on a radius of the reported
+// radius scaled by rolling and the driven wheels turning faster than the ground speed by slip.
+func (suite *FinalDriveTestSuite) drive(car gearbox, gear int, speedKPH, rolling, slip float32) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.SetVehicle(vehicles.Vehicle{Drivetrain: car.drivetrain})
+	suite.transformer.SetTransmissionGearRatio(append(car.gears, make([]float32, 8-len(car.gears))...))
+	suite.transformer.SetTransmissionGear(uint64(gear), 15) //nolint:gosec // test gears are small
+	suite.transformer.SetTyreRadius(car.tyreRadius, car.tyreRadius, car.tyreRadius, car.tyreRadius)
+
+	groundSpeed := speedKPH / 3.6
+	wheelRPS := groundSpeed / (car.tyreRadius * rolling) * (1 + slip)
+
+	suite.transformer.RawTelemetry.GroundSpeed = groundSpeed
+	suite.transformer.RawTelemetry.EngineRpm = wheelRPS * 60 / (2 * math.Pi) * car.gears[gear-1] * car.finalDrive
+	suite.transformer.SetWheelRadiansPerSecond(wheelRPS, wheelRPS, wheelRPS, wheelRPS)
+}
+
+func (suite *FinalDriveTestSuite) TestMeasuresPublishedGearboxes() {
+	tests := map[string]gearbox{
+		"Mazda MX-5":         mazdaMX5,
+		"Toyota GR86":        toyotaGR86,
+		"Honda Civic Type R": hondaCivicTypeR,
+	}
+
+	for name, car := range tests {
+		for gear := 2; gear <= len(car.gears); gear++ {
+			suite.Run(fmt.Sprintf("%s gear %d", name, gear), func() {
+				// Arrange
+				suite.SetupTest()
+				suite.drive(car, gear, float32(gear)*30, 0.98, 0)
+
+				// Act
+				estimate := suite.transformer.FinalDriveEstimate()
+
+				// Assert
+				suite.Equal(gttelemetry.FinalDriveSourceMeasured, estimate.Source)
+				suite.InEpsilon(car.finalDrive, estimate.Ratio, 1e-4)
+				suite.Greater(estimate.Confidence, float32(0.75))
+				suite.Equal(estimate.Ratio, suite.transformer.DifferentialRatio())
+			})
+		}
+	}
+}
+
+func (suite *FinalDriveTestSuite) TestRollingRadiusCorrectionRaisesConfidence() {
+	// Arrange
+	suite.drive(toyotaGR86, 4, 120, 0.97, 0)
+	uncorrected := suite.transformer.FinalDriveEstimate()
+
+	suite.Require().NoError(suite.transformer.SetFinalDriveOptions(gttelemetry.FinalDriveOptions{RollingRadiusCorrection: 0.97}))
+
+	// Act
+	suite.drive(toyotaGR86, 4, 120, 0.97, 0)
+	corrected := suite.transformer.FinalDriveEstimate()
+
+	// Assert
+	suite.InDelta(1, corrected.Confidence, 1e-4)
+	suite.Less(uncorrected.Confidence, corrected.Confidence)
+	suite.InEpsilon(toyotaGR86.finalDrive, corrected.Ratio, 1e-4)
+}
+
+func (suite *FinalDriveTestSuite) TestWheelspinIsNotMeasured() {
+	// Arrange
+	suite.drive(mazdaMX5, 2, 60, 1, 0.2)
+	spinning := suite.transformer.FinalDriveEstimate()
+
+	suite.drive(mazdaMX5, 3, 90, 1, 0)
+	suite.transformer.FinalDriveEstimate()
+
+	// Act
+	suite.drive(mazdaMX5, 2, 60, 1, 0.2)
+	spinningAgain := suite.transformer.FinalDriveEstimate()
+
+	// Assert
+	suite.Equal(gttelemetry.FinalDriveSourceTopSpeed, spinning.Source)
+	suite.InDelta(0.25, spinning.Confidence, 1e-6)
+	suite.Equal(gttelemetry.FinalDriveSourceMeasured, spinningAgain.Source, "The last measurement should be kept")
+	suite.InEpsilon(mazdaMX5.finalDrive, spinningAgain.Ratio, 1e-4)
+}
+
+func (suite *FinalDriveTestSuite) TestSetupChangeForgetsMeasurement() {
+	// Arrange
+	suite.drive(mazdaMX5, 3, 90, 1, 0)
+	suite.Require().Equal(gttelemetry.FinalDriveSourceMeasured, suite.transformer.FinalDriveEstimate().Source)
+
+	// Act
+	suite.transformer.RawTelemetry.ClutchEngagement = 0
+	suite.drive(toyotaGR86, 3, 90, 1, 0)
+	estimate := suite.transformer.FinalDriveEstimate()
+
+	// Assert
+	suite.Equal(gttelemetry.FinalDriveSourceTopSpeed, estimate.Source)
+}
+
+func (suite *FinalDriveTestSuite) TestMeasuresRecordedVehicle() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	var estimate gttelemetry.FinalDriveEstimate
+
+	// Act
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		estimate = transformer.FinalDriveEstimate()
+	}
+
+	// Assert
+	suite.Equal(gttelemetry.FinalDriveSourceMeasured, estimate.Source)
+	suite.InDelta(5.1, estimate.Ratio, 0.02, "Engine and wheel speeds in every gear put the ratio at 5.1")
+	suite.Greater(estimate.Confidence, float32(0.9))
+}
+
+func (suite *FinalDriveTestSuite) TestSetFinalDriveOptionsValidates() {
+	tests := map[string]gttelemetry.FinalDriveOptions{
+		"correction too small": {RollingRadiusCorrection: 0.5},
+		"correction too large": {RollingRadiusCorrection: 1.5},
+		"negative tolerance":   {SlipTolerance: -0.1},
+		"tolerance of 1":       {SlipTolerance: 1},
+	}
+
+	for name, opts := range tests {
+		suite.Run(name, func() {
+			// Act
+			err := suite.transformer.SetFinalDriveOptions(opts)
+
+			// Assert
+			suite.ErrorIs(err, gttelemetry.ErrInvalidFinalDriveOptions)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package gttelemetry
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash/fnv"
+	"math"
+)
+
+// SetupFingerprint identifies a combination of vehicle and setup, the gear ratios, top speed ratio, tyre radius and
+// calculated top speed of the vehicle.
+type SetupFingerprint string
+
+// fingerprint hashes a vehicle ID and a list of values into a stable hex string.
+func fingerprint(vehicleID uint32, values ...float32) string {
+	hash := fnv.New64a()
+	buf := make([]byte, 4)
+
+	binary.LittleEndian.PutUint32(buf, vehicleID)
+	hash.Write(buf)
+
+	for _, value := range values {
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(value))
+		hash.Write(buf)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
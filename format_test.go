@@ -0,0 +1,151 @@
+package gttelemetry_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"golang.org/x/crypto/salsa20"
+)
+
+type FormatTestSuite struct {
+	suite.Suite
+
+	client *gttelemetry.Client
+}
+
+func TestFormatTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(FormatTestSuite))
+}
+
+func (suite *FormatTestSuite) SetupTest() {
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "udp://127.0.0.1:33739",
+		Format:   models.Standard,
+		LogLevel: "error",
+	})
+	suite.Require().NoError(err)
+
+	suite.client = client
+}
+
+func (suite *FormatTestSuite) TestRequestFormatBeforeStreamSetsFormat() {
+	// Act
+	err := suite.client.RequestFormat(models.Addendum2)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(models.Addendum2, suite.client.Format())
+}
+
+func (suite *FormatTestSuite) TestRequestFormatReturnsErrorForUnknownFormat() {
+	// Act
+	err := suite.client.RequestFormat(models.Unknown)
+
+	// Assert
+	suite.Require().ErrorIs(err, gttelemetry.ErrUnsupportedFormat)
+	suite.Equal(models.Standard, suite.client.Format())
+}
+
+func (suite *FormatTestSuite) TestNegotiatedFormatIsConfiguredFormatWhenSet() {
+	// Act
+	gotValue := suite.client.NegotiatedFormat()
+
+	// Assert
+	suite.Equal(models.Standard, gotValue)
+}
+
+func (suite *FormatTestSuite) TestStreamNegotiatesFormatSentByConsole() {
+	// Arrange
+	console, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+
+	defer console.Close()
+
+	consolePort := console.LocalAddr().(*net.UDPAddr).Port //nolint:forcetypeassert // Always a UDP address
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "udp://127.0.0.1:" + strconv.Itoa(consolePort),
+		LogLevel: "error",
+	})
+	suite.Require().NoError(err)
+	suite.Equal(models.Unknown, client.NegotiatedFormat())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() { _, _ = client.Stream(ctx) }()
+
+	heartbeat := make([]byte, 16)
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(5 * time.Second)))
+
+	bufLen, clientAddr, err := console.ReadFromUDP(heartbeat)
+	suite.Require().NoError(err)
+	suite.Equal("C", string(heartbeat[:bufLen]))
+
+	// Act
+	// The console only supports format "B" and sends it in response to the request for "C"
+	_, err = console.WriteToUDP(consolePacket(316, 0xDEADBEEF), clientAddr)
+	suite.Require().NoError(err)
+
+	bufLen, _, err = console.ReadFromUDP(heartbeat)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("B", string(heartbeat[:bufLen]))
+	suite.Equal(models.Addendum1, client.NegotiatedFormat())
+}
+
+// consolePacket builds an encrypted packet of the given size as the console would send it.
+func consolePacket(size int, ivSeed uint32) []byte {
+	plain := make([]byte, size)
+	binary.LittleEndian.PutUint32(plain[0:4], 0x47375330)
+	binary.LittleEndian.PutUint32(plain[0x70:0x74], 1)
+
+	iv := uint32(0x12345678)
+
+	key := [32]byte{}
+	copy(key[:], "Simulator Interface Packet GT7 ver 0.0")
+
+	nonce := make([]byte, 8)
+	binary.LittleEndian.PutUint32(nonce, iv^ivSeed)
+	binary.LittleEndian.PutUint32(nonce[4:], iv)
+
+	encrypted := make([]byte, size)
+	salsa20.XORKeyStream(encrypted, plain, nonce, &key)
+	binary.LittleEndian.PutUint32(encrypted[0x40:0x44], iv)
+
+	return encrypted
+}
+
+func (suite *FormatTestSuite) TestStreamNegotiatesFormatOfRecording() {
+	// Arrange
+	recording := filepath.Join(suite.T().TempDir(), "standard.gtr")
+
+	_, err := gttelemetry.ConvertRecording(context.Background(), "data/replays/demo.gtz", recording,
+		gttelemetry.ConvertOptions{Format: models.Standard})
+	suite.Require().NoError(err)
+
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://" + recording, LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	go func() { _, _ = client.Stream(ctx) }()
+
+	// Assert
+	// Recordings cannot be asked for a format, so the format of their packets is accepted as negotiated
+	suite.Eventually(func() bool {
+		return client.NegotiatedFormat() == models.Standard
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,27 @@
+package gttelemetry
+
+import "time"
+
+const (
+	// FrameRate is the nominal rate at which telemetry packets are sent by the game, in packets per second.
+	FrameRate = 60
+	// FrameInterval is the nominal time between consecutive telemetry packets.
+	FrameInterval = time.Second / FrameRate
+)
+
+// framesElapsed returns the nominal time elapsed between two packet sequence IDs. Sequence IDs are used instead of
+// the wall clock so that results are the same when processing a replay file at any speed. The frames are counted
+// before dividing by the frame rate, as FrameInterval is rounded down and a multiple of it falls short.
+func framesElapsed(from, to uint32) time.Duration {
+	if to <= from {
+		return 0
+	}
+
+	return time.Duration(to-from) * time.Second / FrameRate
+}
+
+// framesDuration returns the nominal duration of a number of frames. Frames are counted rather than summing the
+// duration of each frame, which would drift through rounding.
+func framesDuration(frames uint64) time.Duration {
+	return time.Duration(frames) * time.Second / FrameRate //nolint:gosec // sessions are far shorter than the overflow
+}
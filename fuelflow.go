@@ -0,0 +1,147 @@
+package gttelemetry
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// fuelFlowTimeConstant is the time constant of the exponential moving average of the fuel flow rate. The fuel level
+// changes by a small fraction of a litre each frame, so the rate of a single frame is mostly noise.
+const fuelFlowTimeConstant = 10 * time.Second
+
+// FuelFlow is the rate fuel is used at, for strategy screens that need more than the fuel level. The rates follow
+// the fuel level reported by the game and are in its units, so for cars that report the charge of their battery as
+// the fuel level they are the rate energy is used at.
+type FuelFlow struct {
+	// LitresPerHour is the smoothed rate fuel is used at while driving, excluding pauses and refuelling.
+	LitresPerHour float32 `json:"litresPerHour"`
+	// LitresPerLap is the fuel used over the last lap completed without refuelling, zero until a lap is completed.
+	LitresPerLap float32 `json:"litresPerLap"`
+	// LapsRemaining is the number of laps the fuel on board lasts at LitresPerLap, zero until a lap is completed.
+	LapsRemaining float32 `json:"lapsRemaining"`
+	// TimeRemaining is how long the fuel on board lasts at LitresPerHour, zero until the rate has been measured.
+	TimeRemaining time.Duration `json:"timeRemaining"`
+	// Valid is false until the rate has been measured for the time constant of the smoothing, in which case
+	// LitresPerHour and TimeRemaining are zero.
+	Valid bool `json:"valid"`
+}
+
+// fuelFlowState guards the measurement of the fuel flow of a transformer, which is read from other goroutines.
+type fuelFlowState struct {
+	mu sync.Mutex
+	fuelFlowMeasurement
+}
+
+// clone returns a copy of the measurement.
+func (f *fuelFlowState) clone() *fuelFlowState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &fuelFlowState{fuelFlowMeasurement: f.fuelFlowMeasurement}
+}
+
+// fuelFlowMeasurement measures the rate fuel is used at over time and over each lap.
+type fuelFlowMeasurement struct {
+	lastSequence uint32
+	lastLevel    float32
+	// rate is the smoothed rate in litres per second, measured over the driving time in measured
+	rate     float64
+	measured time.Duration
+
+	lastLap  int16
+	lapStart float32
+	// lapUnmeasured is set when the fuel used over the lap is unknown, as the lap was refuelled or started before the
+	// first frame
+	lapUnmeasured bool
+	litresPerLap  float32
+}
+
+// UpdateFuelFlow measures the fuel flow with the current frame. The client calls this once for each decoded frame,
+// repeated frames are ignored and the measurement is reset when the sequence restarts or a new session starts.
+func (t *Transformer) UpdateFuelFlow() {
+	t.fuelFlow.mu.Lock()
+	defer t.fuelFlow.mu.Unlock()
+
+	flow := t.fuelFlow
+	sequenceID := t.SequenceID()
+
+	if sequenceID == flow.lastSequence {
+		return
+	}
+
+	level := t.FuelLevel()
+	lap := t.CurrentLap()
+
+	if sequenceID < flow.lastSequence || lap < flow.lastLap || flow.lastSequence == 0 {
+		flow.fuelFlowMeasurement = fuelFlowMeasurement{
+			lastSequence:  sequenceID,
+			lastLevel:     level,
+			lastLap:       lap,
+			lapStart:      level,
+			lapUnmeasured: true,
+		}
+
+		return
+	}
+
+	elapsed := t.CorrectedDuration(framesElapsed(flow.lastSequence, sequenceID))
+	used := flow.lastLevel - level
+	flags := t.Flags()
+
+	flow.lastSequence = sequenceID
+	flow.lastLevel = level
+
+	switch {
+	case used < 0:
+		flow.lapUnmeasured = true
+	case t.IsOnCircuit() && flags.Live && !flags.GamePaused:
+		rate := float64(used) / elapsed.Seconds()
+
+		if flow.measured == 0 {
+			flow.rate = rate
+		} else {
+			alpha := 1 - math.Exp(-elapsed.Seconds()/fuelFlowTimeConstant.Seconds())
+			flow.rate += alpha * (rate - flow.rate)
+		}
+
+		flow.measured += elapsed
+	}
+
+	if lap != flow.lastLap {
+		if flow.lastLap >= 1 && !flow.lapUnmeasured && flow.lapStart > level {
+			flow.litresPerLap = flow.lapStart - level
+		}
+
+		flow.lastLap = lap
+		flow.lapStart = level
+		flow.lapUnmeasured = false
+	}
+}
+
+// FuelFlow returns the fuel flow measured so far.
+func (t *Transformer) FuelFlow() FuelFlow {
+	t.fuelFlow.mu.Lock()
+	rate, measured, litresPerLap := t.fuelFlow.rate, t.fuelFlow.measured, t.fuelFlow.litresPerLap
+	t.fuelFlow.mu.Unlock()
+
+	level := t.FuelLevel()
+	flow := FuelFlow{LitresPerLap: litresPerLap}
+
+	if litresPerLap > 0 {
+		flow.LapsRemaining = level / litresPerLap
+	}
+
+	if measured < fuelFlowTimeConstant {
+		return flow
+	}
+
+	flow.Valid = true
+	flow.LitresPerHour = float32(rate * time.Hour.Seconds())
+
+	if rate > 0 {
+		flow.TimeRemaining = time.Duration(float64(level) / rate * float64(time.Second))
+	}
+
+	return flow
+}
@@ -0,0 +1,129 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type FuelFlowTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestFuelFlowTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(FuelFlowTestSuite))
+}
+
+func (suite *FuelFlowTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.transformer.RawTelemetry.FuelLevel = 100
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+}
+
+// drive drives for the duration using fuel at the rate in litres per hour.
+func (suite *FuelFlowTestSuite) drive(duration time.Duration, litresPerHour float32) {
+	for range int(duration / gttelemetry.FrameInterval) {
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.transformer.RawTelemetry.FuelLevel -= litresPerHour / 3600 / gttelemetry.FrameRate
+		suite.transformer.UpdateFuelFlow()
+	}
+}
+
+// completeLap starts the next lap.
+func (suite *FuelFlowTestSuite) completeLap() {
+	suite.transformer.RawTelemetry.CurrentLap++
+	suite.drive(gttelemetry.FrameInterval, 0)
+}
+
+func (suite *FuelFlowTestSuite) TestRateIsSmoothed() {
+	// Arrange
+	suite.drive(time.Minute, 36)
+
+	// Act
+	suite.drive(time.Second, 360)
+	flow := suite.transformer.FuelFlow()
+
+	// Assert
+	suite.True(flow.Valid)
+	suite.Greater(flow.LitresPerHour, float32(36))
+	suite.Less(flow.LitresPerHour, float32(72), "A second of high flow moves the rate a tenth of the way towards it")
+}
+
+func (suite *FuelFlowTestSuite) TestRateIsInvalidUntilMeasured() {
+	// Arrange
+	suite.drive(5*time.Second, 36)
+
+	// Act
+	flow := suite.transformer.FuelFlow()
+
+	// Assert
+	suite.False(flow.Valid)
+	suite.Zero(flow.LitresPerHour)
+	suite.Zero(flow.TimeRemaining)
+}
+
+func (suite *FuelFlowTestSuite) TestLitresPerLap() {
+	// Arrange
+	suite.drive(30*time.Second, 36)
+	suite.completeLap()
+
+	// Act
+	suite.drive(time.Minute, 36)
+	suite.completeLap()
+	flow := suite.transformer.FuelFlow()
+
+	// Assert
+	suite.True(flow.Valid)
+	suite.InDelta(0.6, flow.LitresPerLap, 0.01, "The first lap started before the first frame")
+	suite.InDelta(36, flow.LitresPerHour, 0.5, "The fuel level is single precision")
+	suite.InDelta(98.9/0.6, flow.LapsRemaining, 1)
+	suite.InDelta(float64(165*time.Minute), float64(flow.TimeRemaining), float64(time.Minute))
+}
+
+func (suite *FuelFlowTestSuite) TestPausesAndRefuellingAreLeftOut() {
+	// Arrange
+	suite.drive(time.Minute, 36)
+	suite.completeLap()
+	suite.drive(time.Minute, 36)
+	suite.completeLap()
+
+	// Act
+	suite.transformer.SetFlags(true, true, false, true, false, false, false, false, false, false, false, false)
+	suite.drive(time.Minute, 0)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.drive(10*time.Second, -360)
+	suite.drive(50*time.Second, 36)
+	suite.completeLap()
+	flow := suite.transformer.FuelFlow()
+
+	// Assert
+	suite.InDelta(36, flow.LitresPerHour, 0.5, "The fuel level is single precision")
+	suite.InDelta(0.6, flow.LitresPerLap, 0.01, "The refuelled lap keeps the last lap measured")
+}
+
+func (suite *FuelFlowTestSuite) TestNewSessionResetsMeasurement() {
+	// Arrange
+	suite.drive(time.Minute, 36)
+	suite.completeLap()
+	suite.drive(time.Minute, 36)
+	suite.completeLap()
+
+	// Act
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.drive(time.Second, 36)
+	flow := suite.transformer.FuelFlow()
+
+	// Assert
+	suite.False(flow.Valid)
+	suite.Zero(flow.LitresPerLap)
+}
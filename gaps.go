@@ -0,0 +1,194 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// gapMinimumLostFrames is the number of consecutive packets that must be lost for the loss to be recorded as a gap,
+// so that the odd dropped packet on a busy network is not.
+const gapMinimumLostFrames = FrameRate / 2
+
+// RecordingGapsSuffix is appended to the path of a recording to name the file holding its gaps, e.g.
+// session.gtz.gaps. The file is plain CSV and is only written when a gap occurs during the recording.
+const RecordingGapsSuffix = ".gaps"
+
+// ErrInvalidRecordingGaps indicates a recording gaps file that cannot be read.
+var ErrInvalidRecordingGaps = errors.New("invalid recording gaps")
+
+// GapReason is the cause of a gap in the telemetry.
+type GapReason string
+
+const (
+	// GapPaused is a gap while the game was paused. The game keeps sending frames while paused, holding the values
+	// of the frame the pause started at.
+	GapPaused GapReason = "paused"
+	// GapPacketLoss is a gap where packets were not received, such as when the network dropped out.
+	GapPacketLoss GapReason = "packetLoss"
+)
+
+// Gap is a stretch of telemetry that does not reflect the vehicle being driven, which exports and lap timing exclude
+// instead of treating it as driving time. SequenceID is the last frame before the gap and EndSequenceID the first
+// frame after it.
+type Gap struct {
+	SequenceID    uint32        `json:"sequenceId"`
+	EndSequenceID uint32        `json:"endSequenceId"`
+	Reason        GapReason     `json:"reason"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// Contains returns true when the frame with the given sequence ID falls inside the gap.
+func (g Gap) Contains(sequenceID uint32) bool {
+	return sequenceID > g.SequenceID && sequenceID < g.EndSequenceID
+}
+
+// GapDetector detects pauses and lost packets in the telemetry. Packets lost while the game is paused are part of
+// the pause.
+type GapDetector struct {
+	lastSequence uint32
+	paused       bool
+	pauseStart   uint32
+	gaps         []Gap
+	// discard skips keeping the gaps, for detectors whose gaps are only used as they end
+	discard bool
+}
+
+// NewGapDetector creates a gap detector.
+func NewGapDetector() *GapDetector {
+	return &GapDetector{}
+}
+
+// Update evaluates the latest telemetry frame and returns the gap when it has just ended.
+func (d *GapDetector) Update(t *Transformer) (Gap, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == d.lastSequence {
+		return Gap{}, false
+	}
+
+	previousSequence := d.lastSequence
+	d.lastSequence = sequenceID
+
+	// A sequence ID that goes backwards is a new session, so there is nothing to measure the gap from
+	if previousSequence == 0 || sequenceID < previousSequence {
+		d.paused = t.Flags().GamePaused
+		d.pauseStart = sequenceID
+
+		return Gap{}, false
+	}
+
+	paused := t.Flags().GamePaused
+
+	switch {
+	case paused && !d.paused:
+		d.paused = true
+		d.pauseStart = previousSequence
+	case paused:
+	case d.paused:
+		d.paused = false
+
+		return d.record(Gap{SequenceID: d.pauseStart, EndSequenceID: sequenceID, Reason: GapPaused}), true
+	case sequenceID-previousSequence > gapMinimumLostFrames:
+		return d.record(Gap{SequenceID: previousSequence, EndSequenceID: sequenceID, Reason: GapPacketLoss}), true
+	}
+
+	return Gap{}, false
+}
+
+// Paused returns true while the game is paused.
+func (d *GapDetector) Paused() bool {
+	return d.paused
+}
+
+// Gaps returns all gaps detected so far.
+func (d *GapDetector) Gaps() []Gap {
+	return d.gaps
+}
+
+func (d *GapDetector) record(gap Gap) Gap {
+	gap.Duration = framesElapsed(gap.SequenceID, gap.EndSequenceID)
+
+	if !d.discard {
+		d.gaps = append(d.gaps, gap)
+	}
+
+	return gap
+}
+
+// ReadRecordingGaps reads the gaps of a recording in the order they occurred, returning nil when the recording has
+// none.
+func ReadRecordingGaps(recordingPath string) ([]Gap, error) {
+	file, err := os.Open(recordingPath + RecordingGapsSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("open recording gaps: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 3
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRecordingGaps, err)
+	}
+
+	gaps := make([]Gap, 0, max(len(records)-1, 0))
+
+	for row, record := range records[min(1, len(records)):] {
+		start, err := strconv.ParseUint(record[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %w", ErrInvalidRecordingGaps, row+2, err)
+		}
+
+		end, err := strconv.ParseUint(record[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %w", ErrInvalidRecordingGaps, row+2, err)
+		}
+
+		gap := Gap{SequenceID: uint32(start), EndSequenceID: uint32(end), Reason: GapReason(record[2])}
+		gap.Duration = framesElapsed(gap.SequenceID, gap.EndSequenceID)
+		gaps = append(gaps, gap)
+	}
+
+	return gaps, nil
+}
+
+// AddRecordingGap appends a gap to the gaps file of a recording, creating the file if needed.
+func AddRecordingGap(recordingPath string, gap Gap) error {
+	path := recordingPath + RecordingGapsSuffix
+
+	_, err := os.Stat(path)
+	newFile := errors.Is(err, os.ErrNotExist)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open recording gaps: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+
+	if newFile {
+		_ = writer.Write([]string{"sequenceId", "endSequenceId", "reason"})
+	}
+
+	_ = writer.Write([]string{
+		strconv.FormatUint(uint64(gap.SequenceID), 10),
+		strconv.FormatUint(uint64(gap.EndSequenceID), 10),
+		string(gap.Reason),
+	})
+	writer.Flush()
+
+	err = writer.Error()
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("write recording gaps: %w", err)
+	}
+
+	return file.Close()
+}
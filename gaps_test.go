@@ -0,0 +1,121 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type GapsTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	detector    *gttelemetry.GapDetector
+}
+
+func TestGapsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(GapsTestSuite))
+}
+
+func (suite *GapsTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.detector = gttelemetry.NewGapDetector()
+}
+
+// frames sends frames with the given pause state, advancing the sequence ID by step for each.
+func (suite *GapsTestSuite) frames(count int, step uint32, paused bool) (gaps []gttelemetry.Gap) {
+	suite.transformer.SetFlags(true, paused, false, true, false, false, false, false, false, false, false, false)
+
+	for range count {
+		suite.transformer.RawTelemetry.SequenceId += step
+
+		if gap, ok := suite.detector.Update(suite.transformer); ok {
+			gaps = append(gaps, gap)
+		}
+	}
+
+	return gaps
+}
+
+func (suite *GapsTestSuite) TestPauseIsAGap() {
+	// Arrange
+	suite.frames(10, 1, false)
+	suite.frames(120, 1, true)
+
+	// Act
+	gaps := suite.frames(1, 1, false)
+
+	// Assert
+	suite.Require().Len(gaps, 1)
+	suite.Equal(gttelemetry.GapPaused, gaps[0].Reason)
+	suite.Equal(uint32(10), gaps[0].SequenceID)
+	suite.Equal(uint32(131), gaps[0].EndSequenceID)
+	suite.Equal(121*time.Second/gttelemetry.FrameRate, gaps[0].Duration)
+	suite.True(gaps[0].Contains(50))
+	suite.False(gaps[0].Contains(131))
+	suite.False(suite.detector.Paused())
+}
+
+func (suite *GapsTestSuite) TestPacketLoss() {
+	// Arrange
+	tests := map[string]struct {
+		step     uint32
+		wantGaps int
+	}{
+		"odd dropped packet": {step: 3},
+		"network dropout":    {step: 2 * gttelemetry.FrameRate, wantGaps: 1},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			suite.SetupTest()
+			suite.frames(10, 1, false)
+
+			// Act
+			gaps := suite.frames(1, test.step, false)
+
+			// Assert
+			suite.Len(gaps, test.wantGaps)
+
+			if test.wantGaps > 0 {
+				suite.Equal(gttelemetry.GapPacketLoss, gaps[0].Reason)
+				suite.Equal(2*time.Second, gaps[0].Duration)
+			}
+		})
+	}
+}
+
+func (suite *GapsTestSuite) TestPacketsLostWhilePausedArePartOfThePause() {
+	// Arrange
+	suite.frames(10, 1, false)
+	suite.frames(10, 1, true)
+	suite.frames(1, 600, true)
+
+	// Act
+	gaps := suite.frames(1, 1, false)
+
+	// Assert
+	suite.Require().Len(gaps, 1)
+	suite.Equal(gttelemetry.GapPaused, gaps[0].Reason)
+	suite.Len(suite.detector.Gaps(), 1)
+}
+
+func (suite *GapsTestSuite) TestNewSessionIsNotAGap() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 5000
+	suite.frames(10, 1, false)
+	suite.transformer.RawTelemetry.SequenceId = 0
+
+	// Act
+	gaps := suite.frames(10, 1, false)
+
+	// Assert
+	suite.Empty(gaps)
+}
@@ -0,0 +1,64 @@
+package gttelemetry
+
+import (
+	"math"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// Map coordinates use X and Z for the horizontal plane and Y for elevation. The helpers in this file work in the
+// horizontal plane only.
+
+// planarDistance returns the horizontal distance between two coordinates.
+func planarDistance(a, b models.Coordinate) float64 {
+	return math.Hypot(float64(b.X-a.X), float64(b.Z-a.Z))
+}
+
+// segmentIntersection returns the fraction along the segment p1-p2 at which it crosses the segment q1-q2, and
+// whether the segments intersect at all.
+func segmentIntersection(p1, p2, q1, q2 models.Coordinate) (float64, bool) {
+	rX, rZ := float64(p2.X-p1.X), float64(p2.Z-p1.Z)
+	sX, sZ := float64(q2.X-q1.X), float64(q2.Z-q1.Z)
+
+	denominator := rX*sZ - rZ*sX
+	if denominator == 0 {
+		return 0, false
+	}
+
+	qpX, qpZ := float64(q1.X-p1.X), float64(q1.Z-p1.Z)
+	along := (qpX*sZ - qpZ*sX) / denominator
+	across := (qpX*rZ - qpZ*rX) / denominator
+
+	if along < 0 || along > 1 || across < 0 || across > 1 {
+		return 0, false
+	}
+
+	return along, true
+}
+
+// pointSegmentDistance returns the horizontal distance from a point to the closest point on the segment a-b, along
+// with the fraction along the segment of that closest point.
+func pointSegmentDistance(point, a, b models.Coordinate) (float64, float64) {
+	dX, dZ := float64(b.X-a.X), float64(b.Z-a.Z)
+	lengthSquared := dX*dX + dZ*dZ
+
+	fraction := 0.0
+	if lengthSquared > 0 {
+		fraction = (float64(point.X-a.X)*dX + float64(point.Z-a.Z)*dZ) / lengthSquared
+		fraction = math.Max(0, math.Min(1, fraction))
+	}
+
+	closestX := float64(a.X) + fraction*dX
+	closestZ := float64(a.Z) + fraction*dZ
+
+	return math.Hypot(float64(point.X)-closestX, float64(point.Z)-closestZ), fraction
+}
+
+// lerpCoordinate returns the coordinate at the given fraction between a and b.
+func lerpCoordinate(a, b models.Coordinate, fraction float32) models.Coordinate {
+	return models.Coordinate{
+		X: a.X + (b.X-a.X)*fraction,
+		Y: a.Y + (b.Y-a.Y)*fraction,
+		Z: a.Z + (b.Z-a.Z)*fraction,
+	}
+}
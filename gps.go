@@ -0,0 +1,68 @@
+package gttelemetry
+
+import (
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
+)
+
+// GPSOptions configures the conversion of telemetry frames into GPS fixes.
+type GPSOptions struct {
+	// Anchor places the circuit map on the Earth, defaulting to geo.DefaultAnchor.
+	Anchor *geo.Anchor
+	// Start is the time of the first fix. The time of later fixes is counted in frames from it.
+	Start time.Time
+}
+
+// GPSConverter synthesises GPS fixes from the map coordinates of telemetry frames, so that GPS based analysis tools
+// can read Gran Turismo laps through geo.WriteNMEA or a geo.GPXWriter.
+type GPSConverter struct {
+	anchor       geo.Anchor
+	start        time.Time
+	started      bool
+	lastSequence uint32
+	frames       uint64
+}
+
+// NewGPSConverter creates a converter with the given options.
+func NewGPSConverter(opts GPSOptions) *GPSConverter {
+	anchor := geo.DefaultAnchor
+	if opts.Anchor != nil {
+		anchor = *opts.Anchor
+	}
+
+	return &GPSConverter{
+		anchor: anchor,
+		start:  opts.Start,
+	}
+}
+
+// Fix returns the GPS fix for the latest telemetry frame, and false for repeated frames and frames where the vehicle
+// is not on circuit.
+func (g *GPSConverter) Fix(t *Transformer) (geo.Fix, bool) {
+	sequenceID := t.SequenceID()
+	if g.started && sequenceID == g.lastSequence {
+		return geo.Fix{}, false
+	}
+
+	if g.started && sequenceID > g.lastSequence {
+		g.frames += uint64(sequenceID - g.lastSequence)
+	}
+
+	g.started = true
+	g.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() {
+		return geo.Fix{}, false
+	}
+
+	coordinates := t.PositionalMapCoordinates()
+
+	return geo.Fix{
+		Time:     g.start.Add(t.CorrectedDuration(framesDuration(g.frames))),
+		Position: g.anchor.Project(float64(coordinates.X), float64(coordinates.Z)),
+		Altitude: float64(coordinates.Y),
+		Speed:    float64(t.GroundSpeedMetresPerSecond()),
+		Course:   headingDegrees(t.Heading(), g.anchor.Rotation),
+	}, true
+}
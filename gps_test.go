@@ -0,0 +1,85 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type GPSTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestGPSTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(GPSTestSuite))
+}
+
+func (suite *GPSTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.GroundSpeed = 40
+	suite.transformer.SetMapPositionCoordinates(0, 100, 0)
+}
+
+func (suite *GPSTestSuite) TestFixIsTimedInFramesFromStart() {
+	// Arrange
+	start := time.Date(2026, 5, 1, 14, 0, 0, 0, time.UTC)
+	anchor := geo.Anchor{Latitude: 35.37, Longitude: 138.93}
+	converter := gttelemetry.NewGPSConverter(gttelemetry.GPSOptions{Anchor: &anchor, Start: start})
+
+	suite.transformer.RawTelemetry.SequenceId = 100
+	_, ok := converter.Fix(suite.transformer)
+	suite.Require().True(ok)
+
+	// Act
+	suite.transformer.RawTelemetry.SequenceId = 130
+	fix, ok := converter.Fix(suite.transformer)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(start.Add(500*time.Millisecond), fix.Time)
+	suite.InDelta(35.37, fix.Position.Latitude, 1e-9)
+	suite.InDelta(138.93, fix.Position.Longitude, 1e-9)
+	suite.InDelta(100, fix.Altitude, 1e-9)
+	suite.InDelta(40, fix.Speed, 1e-9)
+}
+
+func (suite *GPSTestSuite) TestFixSkipsRepeatedFramesAndMenus() {
+	// Arrange
+	converter := gttelemetry.NewGPSConverter(gttelemetry.GPSOptions{})
+	suite.transformer.RawTelemetry.SequenceId = 1
+	_, ok := converter.Fix(suite.transformer)
+	suite.Require().True(ok)
+
+	// Act
+	_, repeated := converter.Fix(suite.transformer)
+
+	suite.transformer.RawTelemetry.SequenceId = 2
+	suite.transformer.RawTelemetry.RaceEntrants = -1
+	_, inMenu := converter.Fix(suite.transformer)
+
+	// Assert
+	suite.False(repeated)
+	suite.False(inMenu)
+}
+
+func (suite *GPSTestSuite) TestDefaultAnchorIsUsed() {
+	// Arrange
+	converter := gttelemetry.NewGPSConverter(gttelemetry.GPSOptions{})
+
+	// Act
+	fix, ok := converter.Fix(suite.transformer)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.InDelta(geo.DefaultAnchor.Latitude, fix.Position.Latitude, 1e-9)
+}
@@ -0,0 +1,216 @@
+package gttelemetry
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// DefaultHeatmapCellSize is the size of each heatmap cell in metres, about the width of a car's tyre track.
+const DefaultHeatmapCellSize = 1
+
+// heatmapMaxPixels limits the size of the image written by a heatmap, which is well beyond the largest circuit at the
+// default cell size.
+const heatmapMaxPixels = 8192
+
+// ErrEmptyHeatmap indicates that a heatmap cannot be written because no positions have been added.
+var ErrEmptyHeatmap = errors.New("heatmap has no positions")
+
+// heatmapCell is the position of a heatmap cell, counted in cells from the map origin.
+type heatmapCell struct {
+	X, Z int32
+}
+
+// HeatmapBounds is the area of the circuit map covered by a heatmap image, in map coordinates in metres. The top
+// left pixel of the image is at MinX, MinZ, so the image lines up with any other image of the circuit map drawn with
+// the same scale.
+type HeatmapBounds struct {
+	MinX     float32 `json:"minX"`
+	MinZ     float32 `json:"minZ"`
+	MaxX     float32 `json:"maxX"`
+	MaxZ     float32 `json:"maxZ"`
+	CellSize float32 `json:"cellSize"`
+}
+
+// Heatmap accumulates the positions visited on circuit into a grid aligned to the circuit map, showing the racing line
+// used across a session and how consistently it was followed. Each cell counts the number of laps that passed
+// through it, so the count is not skewed by the time spent in slow corners. The path between consecutive frames is
+// filled in so that fast sections do not leave gaps.
+type Heatmap struct {
+	cellSize     float32
+	lastSequence uint32
+	lap          int16
+	hasLast      bool
+	lastX        float32
+	lastZ        float32
+	counts       map[heatmapCell]uint32
+	visited      map[heatmapCell]struct{}
+	laps         uint32
+}
+
+// NewHeatmap creates an empty heatmap with cells of the given size in metres, DefaultHeatmapCellSize when zero or
+// negative.
+func NewHeatmap(cellSize float32) *Heatmap {
+	if cellSize <= 0 {
+		cellSize = DefaultHeatmapCellSize
+	}
+
+	return &Heatmap{
+		cellSize: cellSize,
+		counts:   make(map[heatmapCell]uint32),
+		visited:  make(map[heatmapCell]struct{}),
+	}
+}
+
+// Update adds the position of the latest telemetry frame.
+func (h *Heatmap) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID == h.lastSequence {
+		return
+	}
+
+	h.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() {
+		h.hasLast = false
+
+		return
+	}
+
+	if lap := t.CurrentLap(); lap != h.lap || h.laps == 0 {
+		h.lap = lap
+		h.laps++
+		clear(h.visited)
+	}
+
+	coordinates := t.PositionalMapCoordinates()
+	x, z := coordinates.X, coordinates.Z
+
+	if !h.hasLast {
+		h.lastX, h.lastZ = x, z
+		h.hasLast = true
+	}
+
+	// Visit the cells along the path from the previous frame in steps of half a cell.
+	steps := int(math.Ceil(math.Hypot(float64(x-h.lastX), float64(z-h.lastZ)) / float64(h.cellSize/2)))
+	steps = min(max(steps, 1), heatmapMaxPixels)
+
+	for step := 1; step <= steps; step++ {
+		fraction := float32(step) / float32(steps)
+		h.visit(h.lastX+(x-h.lastX)*fraction, h.lastZ+(z-h.lastZ)*fraction)
+	}
+
+	h.lastX, h.lastZ = x, z
+}
+
+func (h *Heatmap) visit(x, z float32) {
+	cell := heatmapCell{
+		X: int32(math.Floor(float64(x / h.cellSize))),
+		Z: int32(math.Floor(float64(z / h.cellSize))),
+	}
+
+	if _, ok := h.visited[cell]; ok {
+		return
+	}
+
+	h.visited[cell] = struct{}{}
+	h.counts[cell]++
+}
+
+// Laps returns the number of laps, or parts of laps, added to the heatmap.
+func (h *Heatmap) Laps() uint32 {
+	return h.laps
+}
+
+// Count returns the number of laps that passed through the cell containing the map coordinates.
+func (h *Heatmap) Count(x, z float32) uint32 {
+	return h.counts[heatmapCell{
+		X: int32(math.Floor(float64(x / h.cellSize))),
+		Z: int32(math.Floor(float64(z / h.cellSize))),
+	}]
+}
+
+// Bounds returns the area of the circuit map covered by the heatmap, false when no positions have been added.
+func (h *Heatmap) Bounds() (HeatmapBounds, bool) {
+	if len(h.counts) == 0 {
+		return HeatmapBounds{}, false
+	}
+
+	minCell := heatmapCell{X: math.MaxInt32, Z: math.MaxInt32}
+	maxCell := heatmapCell{X: math.MinInt32, Z: math.MinInt32}
+
+	for cell := range h.counts {
+		minCell.X, minCell.Z = min(minCell.X, cell.X), min(minCell.Z, cell.Z)
+		maxCell.X, maxCell.Z = max(maxCell.X, cell.X), max(maxCell.Z, cell.Z)
+	}
+
+	return HeatmapBounds{
+		MinX:     float32(minCell.X) * h.cellSize,
+		MinZ:     float32(minCell.Z) * h.cellSize,
+		MaxX:     float32(maxCell.X+1) * h.cellSize,
+		MaxZ:     float32(maxCell.Z+1) * h.cellSize,
+		CellSize: h.cellSize,
+	}, true
+}
+
+// Image draws the heatmap with one pixel per cell on a transparent background, for overlaying on a circuit map. The
+// map X axis runs to the right and the Z axis down the image. Cells passed through on few laps are drawn blue, moving
+// to red for cells passed through on every lap.
+func (h *Heatmap) Image() image.Image {
+	bounds, ok := h.Bounds()
+	if !ok {
+		return image.NewNRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	originX := int32(math.Round(float64(bounds.MinX / h.cellSize)))
+	originZ := int32(math.Round(float64(bounds.MinZ / h.cellSize)))
+	width := min(int(math.Round(float64((bounds.MaxX-bounds.MinX)/h.cellSize))), heatmapMaxPixels)
+	height := min(int(math.Round(float64((bounds.MaxZ-bounds.MinZ)/h.cellSize))), heatmapMaxPixels)
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	var maxCount uint32
+	for _, count := range h.counts {
+		maxCount = max(maxCount, count)
+	}
+
+	for cell, count := range h.counts {
+		x, y := int(cell.X-originX), int(cell.Z-originZ)
+		if x >= width || y >= height {
+			continue
+		}
+
+		img.SetNRGBA(x, y, heatmapColour(float32(count)/float32(maxCount)))
+	}
+
+	return img
+}
+
+// WritePNG writes the heatmap image as a PNG, ErrEmptyHeatmap when no positions have been added.
+func (h *Heatmap) WritePNG(w io.Writer) error {
+	if len(h.counts) == 0 {
+		return ErrEmptyHeatmap
+	}
+
+	err := png.Encode(w, h.Image())
+	if err != nil {
+		return fmt.Errorf("encode heatmap: %w", err)
+	}
+
+	return nil
+}
+
+// heatmapColour returns the colour of a cell, from blue for the lowest intensity through green to red.
+func heatmapColour(intensity float32) color.NRGBA {
+	intensity = min(max(intensity, 0), 1)
+
+	if intensity < 0.5 {
+		return color.NRGBA{G: uint8(intensity * 2 * 255), B: uint8((1 - intensity*2) * 255), A: 255}
+	}
+
+	return color.NRGBA{R: uint8((intensity - 0.5) * 2 * 255), G: uint8((1 - (intensity-0.5)*2) * 255), A: 255}
+}
@@ -0,0 +1,116 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type HeatmapTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	heatmap     *gttelemetry.Heatmap
+}
+
+func TestHeatmapTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(HeatmapTestSuite))
+}
+
+func (suite *HeatmapTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+
+	suite.heatmap = gttelemetry.NewHeatmap(1)
+}
+
+func (suite *HeatmapTestSuite) frame(x, z float32) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.SetMapPositionCoordinates(x, 0, z)
+
+	suite.heatmap.Update(suite.transformer)
+}
+
+func (suite *HeatmapTestSuite) TestPathBetweenFramesIsFilled() {
+	// Act
+	suite.frame(0.5, 0.5)
+	suite.frame(10.5, 0.5)
+
+	// Assert
+	for x := float32(0.5); x < 11; x++ {
+		suite.Equal(uint32(1), suite.heatmap.Count(x, 0.5), "x %v", x)
+	}
+
+	suite.Equal(uint32(0), suite.heatmap.Count(0.5, 1.5))
+}
+
+func (suite *HeatmapTestSuite) TestCellsCountLapsNotFrames() {
+	// Arrange
+	suite.frame(0.5, 0.5)
+	suite.frame(0.6, 0.5)
+	suite.frame(0.7, 0.5)
+	suite.transformer.RawTelemetry.CurrentLap = 2
+
+	// Act
+	suite.frame(0.5, 0.5)
+
+	// Assert
+	suite.Equal(uint32(2), suite.heatmap.Count(0.5, 0.5))
+	suite.Equal(uint32(2), suite.heatmap.Laps())
+}
+
+func (suite *HeatmapTestSuite) TestBoundsAreAlignedToCells() {
+	// Arrange
+	suite.frame(-3.5, 2.2)
+
+	// Act
+	suite.frame(1.2, 4.9)
+
+	// Assert
+	bounds, ok := suite.heatmap.Bounds()
+	suite.Require().True(ok)
+	suite.Equal(gttelemetry.HeatmapBounds{MinX: -4, MinZ: 2, MaxX: 2, MaxZ: 5, CellSize: 1}, bounds)
+}
+
+func (suite *HeatmapTestSuite) TestWritePNG() {
+	// Arrange
+	suite.frame(0.5, 0.5)
+	suite.frame(4.5, 2.5)
+
+	var buffer bytes.Buffer
+
+	// Act
+	err := suite.heatmap.WritePNG(&buffer)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	img, err := png.Decode(&buffer)
+	suite.Require().NoError(err)
+	suite.Equal(5, img.Bounds().Dx())
+	suite.Equal(3, img.Bounds().Dy())
+
+	_, _, _, alpha := img.At(0, 0).RGBA()
+	suite.NotZero(alpha)
+
+	_, _, _, alpha = img.At(4, 0).RGBA()
+	suite.Zero(alpha)
+}
+
+func (suite *HeatmapTestSuite) TestEmptyHeatmap() {
+	// Act
+	_, ok := suite.heatmap.Bounds()
+
+	// Assert
+	suite.False(ok)
+	suite.True(suite.heatmap.Image().Bounds().Empty())
+	suite.ErrorIs(suite.heatmap.WritePNG(&bytes.Buffer{}), gttelemetry.ErrEmptyHeatmap)
+}
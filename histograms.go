@@ -0,0 +1,323 @@
+package gttelemetry
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// pedalHistogramBins divides pedal travel into bins of 10 percent.
+	pedalHistogramBins = 10
+	// steeringHistogramRange is the steering wheel angle in degrees either side of centre covered by the steering
+	// histogram, larger angles are counted in the outermost bins.
+	steeringHistogramRange = 360
+	// steeringHistogramBins divides the steering range into bins of 30 degrees.
+	steeringHistogramBins = 24
+	// histogramFullThrottle is the throttle input in percent counted as full throttle, allowing for pedals that do not
+	// quite reach the end of their travel.
+	histogramFullThrottle = 99
+	// engineMapMaxRPM is the engine speed covered by the engine operating map, faster engine speeds are counted in the
+	// last bin.
+	engineMapMaxRPM = 12000
+	// engineMapRPMBins divides the engine speed into bins of 500 RPM.
+	engineMapRPMBins = 24
+)
+
+// Histogram counts the frames in which a channel was within each of a series of equally sized bins.
+type Histogram struct {
+	// Min is the lower edge of the first bin.
+	Min float32 `json:"min"`
+	// Width is the width of each bin.
+	Width float32 `json:"width"`
+	// Counts is the number of frames in each bin.
+	Counts []uint64 `json:"counts"`
+}
+
+func newHistogram(minimum, maximum float32, bins int) Histogram {
+	return Histogram{
+		Min:    minimum,
+		Width:  (maximum - minimum) / float32(bins),
+		Counts: make([]uint64, bins),
+	}
+}
+
+// add counts a value, values outside of the histogram are counted in the first or last bin.
+func (h *Histogram) add(value float32) {
+	bin := int(math.Floor(float64((value - h.Min) / h.Width)))
+	bin = min(max(bin, 0), len(h.Counts)-1)
+
+	h.Counts[bin]++
+}
+
+func (h *Histogram) clone() Histogram {
+	clone := *h
+	clone.Counts = slices.Clone(h.Counts)
+
+	return clone
+}
+
+// Total returns the number of frames counted.
+func (h *Histogram) Total() uint64 {
+	var total uint64
+
+	for _, count := range h.Counts {
+		total += count
+	}
+
+	return total
+}
+
+// Fractions returns the fraction of the frames counted in each bin, all zero when no frames have been counted.
+func (h *Histogram) Fractions() []float64 {
+	fractions := make([]float64, len(h.Counts))
+
+	total := h.Total()
+	if total == 0 {
+		return fractions
+	}
+
+	for i, count := range h.Counts {
+		fractions[i] = float64(count) / float64(total)
+	}
+
+	return fractions
+}
+
+// Histogram2D counts the frames in which a pair of channels were within each of a grid of equally sized bins.
+type Histogram2D struct {
+	// MinX and MinY are the lower edges of the first bins.
+	MinX float32 `json:"minX"`
+	MinY float32 `json:"minY"`
+	// WidthX and WidthY are the widths of each bin.
+	WidthX float32 `json:"widthX"`
+	WidthY float32 `json:"widthY"`
+	// Counts is the number of frames in each bin, indexed by the X bin then the Y bin.
+	Counts [][]uint64 `json:"counts"`
+}
+
+func newHistogram2D(minX, maxX float32, binsX int, minY, maxY float32, binsY int) Histogram2D {
+	counts := make([][]uint64, binsX)
+	for i := range counts {
+		counts[i] = make([]uint64, binsY)
+	}
+
+	return Histogram2D{
+		MinX:   minX,
+		MinY:   minY,
+		WidthX: (maxX - minX) / float32(binsX),
+		WidthY: (maxY - minY) / float32(binsY),
+		Counts: counts,
+	}
+}
+
+// add counts a pair of values, values outside of the histogram are counted in the first or last bins.
+func (h *Histogram2D) add(x, y float32) {
+	binX := int(math.Floor(float64((x - h.MinX) / h.WidthX)))
+	binX = min(max(binX, 0), len(h.Counts)-1)
+
+	binY := int(math.Floor(float64((y - h.MinY) / h.WidthY)))
+	binY = min(max(binY, 0), len(h.Counts[binX])-1)
+
+	h.Counts[binX][binY]++
+}
+
+func (h *Histogram2D) clone() Histogram2D {
+	clone := *h
+
+	clone.Counts = make([][]uint64, len(h.Counts))
+	for i, counts := range h.Counts {
+		clone.Counts[i] = slices.Clone(counts)
+	}
+
+	return clone
+}
+
+// InputHistograms describes how the throttle, brake and steering were used, for analysing driving style.
+type InputHistograms struct {
+	// Throttle and Brake count the input in percent, in bins of 10 percent.
+	Throttle Histogram `json:"throttle"`
+	Brake    Histogram `json:"brake"`
+	// Steering counts the steering wheel angle in degrees, in bins of 30 degrees.
+	Steering Histogram `json:"steering"`
+	// EngineMap counts the engine operating point, the engine speed in bins of 500 RPM against the throttle input in
+	// bins of 10 percent.
+	EngineMap Histogram2D `json:"engineMap"`
+	// GearFrames is the number of frames in each gear, for choosing gear and final drive ratios.
+	GearFrames map[int]uint64 `json:"gearFrames"`
+	// Frames is the number of frames counted.
+	Frames uint64 `json:"frames"`
+	// FullThrottleFrames is the number of frames at full throttle.
+	FullThrottleFrames uint64 `json:"fullThrottleFrames"`
+	// BrakingFrames is the number of frames with any brake input.
+	BrakingFrames uint64 `json:"brakingFrames"`
+	// CoastingFrames is the number of frames with neither throttle nor brake input.
+	CoastingFrames uint64 `json:"coastingFrames"`
+}
+
+// NewInputHistograms creates histograms without any frames counted.
+func NewInputHistograms() InputHistograms {
+	return InputHistograms{
+		Throttle:   newHistogram(0, 100, pedalHistogramBins),
+		Brake:      newHistogram(0, 100, pedalHistogramBins),
+		Steering:   newHistogram(-steeringHistogramRange, steeringHistogramRange, steeringHistogramBins),
+		EngineMap:  newHistogram2D(0, engineMapMaxRPM, engineMapRPMBins, 0, 100, pedalHistogramBins),
+		GearFrames: make(map[int]uint64),
+	}
+}
+
+// FullThrottlePercent returns the percentage of the frames at full throttle.
+func (h *InputHistograms) FullThrottlePercent() float64 {
+	return framesPercent(h.FullThrottleFrames, h.Frames)
+}
+
+// BrakingPercent returns the percentage of the frames with any brake input.
+func (h *InputHistograms) BrakingPercent() float64 {
+	return framesPercent(h.BrakingFrames, h.Frames)
+}
+
+// CoastingPercent returns the percentage of the frames with neither throttle nor brake input.
+func (h *InputHistograms) CoastingPercent() float64 {
+	return framesPercent(h.CoastingFrames, h.Frames)
+}
+
+// GearTime returns the time spent in a gear.
+func (h *InputHistograms) GearTime(gear int) time.Duration {
+	return framesDuration(h.GearFrames[gear])
+}
+
+// GearPercent returns the percentage of the frames spent in a gear.
+func (h *InputHistograms) GearPercent(gear int) float64 {
+	return framesPercent(h.GearFrames[gear], h.Frames)
+}
+
+// Gears returns the gears with frames counted in gear order.
+func (h *InputHistograms) Gears() []int {
+	gears := make([]int, 0, len(h.GearFrames))
+	for gear := range h.GearFrames {
+		gears = append(gears, gear)
+	}
+
+	slices.Sort(gears)
+
+	return gears
+}
+
+func (h *InputHistograms) add(t *Transformer) {
+	throttle := t.ThrottleInputPercent()
+	brake := t.BrakeInputPercent()
+
+	h.Throttle.add(throttle)
+	h.Brake.add(brake)
+	h.Steering.add(t.SteeringWheelAngleDegrees())
+	h.EngineMap.add(t.EngineRPM(), throttle)
+	h.Frames++
+
+	if gear := t.CurrentGear(); t.Flags().InGear && gear > 0 {
+		h.GearFrames[gear]++
+	}
+
+	switch {
+	case brake > 0:
+		h.BrakingFrames++
+	case throttle == 0:
+		h.CoastingFrames++
+	}
+
+	if throttle >= histogramFullThrottle {
+		h.FullThrottleFrames++
+	}
+}
+
+func (h *InputHistograms) clone() InputHistograms {
+	clone := *h
+	clone.Throttle = h.Throttle.clone()
+	clone.Brake = h.Brake.clone()
+	clone.Steering = h.Steering.clone()
+	clone.EngineMap = h.EngineMap.clone()
+	clone.GearFrames = maps.Clone(h.GearFrames)
+
+	return clone
+}
+
+func framesPercent(frames, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(frames) / float64(total) * 100
+}
+
+// HistogramCollector counts the throttle, brake and steering inputs, engine operating point and gear of every frame on
+// circuit, for the whole session and for each lap. Frames in menus and while the game is paused are ignored.
+type HistogramCollector struct {
+	lastSequence uint32
+	session      InputHistograms
+	laps         map[int16]*InputHistograms
+}
+
+// NewHistogramCollector creates a collector without any frames counted.
+func NewHistogramCollector() *HistogramCollector {
+	return &HistogramCollector{
+		session: NewInputHistograms(),
+		laps:    make(map[int16]*InputHistograms),
+	}
+}
+
+// Update counts the inputs of the latest telemetry frame.
+func (c *HistogramCollector) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID == c.lastSequence {
+		return
+	}
+
+	c.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() || t.Flags().GamePaused {
+		return
+	}
+
+	c.session.add(t)
+
+	lap := t.CurrentLap()
+	if lap < 1 {
+		return
+	}
+
+	histograms, ok := c.laps[lap]
+	if !ok {
+		lapHistograms := NewInputHistograms()
+		histograms = &lapHistograms
+		c.laps[lap] = histograms
+	}
+
+	histograms.add(t)
+}
+
+// Session returns the histograms of every frame counted.
+func (c *HistogramCollector) Session() InputHistograms {
+	return c.session.clone()
+}
+
+// Lap returns the histograms of a lap.
+func (c *HistogramCollector) Lap(lap int16) (InputHistograms, bool) {
+	histograms, ok := c.laps[lap]
+	if !ok {
+		return InputHistograms{}, false
+	}
+
+	return histograms.clone(), true
+}
+
+// Laps returns the laps with frames counted in lap order.
+func (c *HistogramCollector) Laps() []int16 {
+	laps := make([]int16, 0, len(c.laps))
+	for lap := range c.laps {
+		laps = append(laps, lap)
+	}
+
+	slices.Sort(laps)
+
+	return laps
+}
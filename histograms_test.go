@@ -0,0 +1,132 @@
+package gttelemetry_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type HistogramCollectorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	collector   *gttelemetry.HistogramCollector
+}
+
+func TestHistogramCollectorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(HistogramCollectorTestSuite))
+}
+
+func (suite *HistogramCollectorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+
+	suite.collector = gttelemetry.NewHistogramCollector()
+}
+
+func (suite *HistogramCollectorTestSuite) frame(throttle, brake uint8, steering float32) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.ThrottleInput = throttle
+	suite.transformer.RawTelemetry.BrakeInput = brake
+	suite.transformer.RawTelemetry.SteeringWheelAngleRadians = steering
+
+	suite.collector.Update(suite.transformer)
+}
+
+func (suite *HistogramCollectorTestSuite) TestSessionHistograms() {
+	// Arrange
+	suite.frame(255, 0, 0)
+	suite.frame(255, 0, 0)
+	suite.frame(0, 128, math.Pi/2)
+	suite.frame(0, 0, -4*math.Pi)
+
+	// Act
+	histograms := suite.collector.Session()
+
+	// Assert
+	suite.Equal(uint64(4), histograms.Frames)
+	suite.InDelta(50, histograms.FullThrottlePercent(), 0.001)
+	suite.InDelta(25, histograms.BrakingPercent(), 0.001)
+	suite.InDelta(25, histograms.CoastingPercent(), 0.001)
+	suite.Equal([]uint64{2, 0, 0, 0, 0, 0, 0, 0, 0, 2}, histograms.Throttle.Counts)
+	suite.Equal(uint64(1), histograms.Brake.Counts[5])
+	suite.Equal(uint64(1), histograms.Steering.Counts[15])
+	suite.Equal(uint64(1), histograms.Steering.Counts[0])
+	suite.InDelta(0.5, histograms.Throttle.Fractions()[9], 0.001)
+}
+
+func (suite *HistogramCollectorTestSuite) TestEngineMapAndGearUsage() {
+	// Arrange
+	suite.transformer.SetTransmissionGear(3, 4)
+	suite.transformer.RawTelemetry.EngineRpm = 6250
+	suite.frame(255, 0, 0)
+	suite.frame(255, 0, 0)
+	suite.transformer.SetTransmissionGear(4, 5)
+	suite.transformer.RawTelemetry.EngineRpm = 15000
+	suite.frame(128, 0, 0)
+	suite.transformer.SetFlags(true, false, false, false, false, false, false, false, false, false, false, false)
+	suite.frame(0, 0, 0)
+
+	// Act
+	histograms := suite.collector.Session()
+
+	// Assert
+	suite.Equal(uint64(2), histograms.EngineMap.Counts[12][9])
+	suite.Equal(uint64(1), histograms.EngineMap.Counts[23][5], "faster engine speeds are counted in the last bin")
+	suite.Equal([]int{3, 4}, histograms.Gears())
+	suite.InDelta(50, histograms.GearPercent(3), 0.001)
+	suite.Equal(2*time.Second/gttelemetry.FrameRate, histograms.GearTime(3))
+	suite.Zero(histograms.GearTime(5))
+}
+
+func (suite *HistogramCollectorTestSuite) TestLapHistograms() {
+	// Arrange
+	suite.frame(255, 0, 0)
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.frame(0, 255, 0)
+	suite.frame(0, 255, 0)
+
+	// Act
+	lap, ok := suite.collector.Lap(2)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal([]int16{1, 2}, suite.collector.Laps())
+	suite.Equal(uint64(2), lap.Frames)
+	suite.InDelta(100, lap.BrakingPercent(), 0.001)
+	suite.Equal(uint64(3), suite.collector.Session().Frames)
+}
+
+func (suite *HistogramCollectorTestSuite) TestPausedAndRepeatedFramesAreIgnored() {
+	// Arrange
+	suite.frame(255, 0, 0)
+	suite.collector.Update(suite.transformer)
+	suite.transformer.SetFlags(true, true, false, true, false, false, false, false, false, false, false, false)
+
+	// Act
+	suite.frame(0, 255, 0)
+
+	// Assert
+	suite.Equal(uint64(1), suite.collector.Session().Frames)
+}
+
+func (suite *HistogramCollectorTestSuite) TestReturnedHistogramsAreCopies() {
+	// Arrange
+	suite.frame(255, 0, 0)
+	histograms := suite.collector.Session()
+
+	// Act
+	histograms.Throttle.Counts[9] = 100
+
+	// Assert
+	suite.Equal(uint64(1), suite.collector.Session().Throttle.Counts[9])
+}
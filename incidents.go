@@ -0,0 +1,138 @@
+package gttelemetry
+
+import (
+	"time"
+)
+
+const (
+	// penaltyThrottleInputPercent is the minimum throttle input considered to be a request for full throttle.
+	penaltyThrottleInputPercent = 95
+	// penaltyThrottleRatio is the maximum ratio of throttle output to input while a penalty is being served.
+	penaltyThrottleRatio = 0.6
+	// penaltyMinimumSpeed is the ground speed in metres per second above which a throttle cut is considered.
+	penaltyMinimumSpeed = 5
+	// penaltyDetectionTime is how long a throttle cut must persist before it is reported as a penalty.
+	penaltyDetectionTime = 500 * time.Millisecond
+	// damageVmaxReduction is the fractional drop in calculated top speed reported as damage.
+	damageVmaxReduction = 0.02
+)
+
+// IncidentMonitor tracks penalties and damage across consecutive frames.
+//
+// None of the known telemetry formats (up to and including Addendum3) carry penalty or damage data, so the monitor
+// uses heuristics. A penalty is assumed while the throttle output is held well below a full throttle input with
+// traction control and the rev limiter inactive, which is how the game limits the speed of a penalised car. Damage
+// is assumed when the calculated top speed of the vehicle drops below the highest value seen since arriving on the
+// circuit, as mechanical damage reduces the available power.
+type IncidentMonitor struct {
+	vehicleID     uint32
+	lastSequence  uint32
+	peakVmax      uint16
+	damaged       bool
+	throttleCut   bool
+	throttleCutAt uint32
+}
+
+// NewIncidentMonitor creates a new incident monitor.
+func NewIncidentMonitor() *IncidentMonitor {
+	return &IncidentMonitor{}
+}
+
+// Update evaluates the latest telemetry frame.
+func (m *IncidentMonitor) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID == m.lastSequence {
+		return
+	}
+
+	m.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() {
+		m.reset()
+
+		return
+	}
+
+	// Damage carries over a pause, while the time a throttle cut was held for restarts as a pause is no time served
+	if t.Flags().GamePaused {
+		m.throttleCut = false
+
+		return
+	}
+
+	m.updateDamage(t)
+	m.updatePenalty(t, sequenceID)
+}
+
+// ActivePenaltySeconds returns how long the current penalty has been served for, in seconds, or zero when no
+// penalty is active. The remaining penalty time is not available from telemetry.
+func (m *IncidentMonitor) ActivePenaltySeconds() float64 {
+	elapsed := m.penaltyElapsed()
+	if elapsed < penaltyDetectionTime {
+		return 0
+	}
+
+	return elapsed.Seconds()
+}
+
+// HasDamage returns true if the vehicle is believed to have sustained mechanical damage since arriving on the
+// circuit.
+func (m *IncidentMonitor) HasDamage() bool {
+	return m.damaged
+}
+
+func (m *IncidentMonitor) updateDamage(t *Transformer) {
+	vmax := t.CalculatedVmax().Speed
+
+	if t.VehicleID() != m.vehicleID {
+		m.vehicleID = t.VehicleID()
+		m.peakVmax = vmax
+		m.damaged = false
+
+		return
+	}
+
+	if vmax > m.peakVmax {
+		m.peakVmax = vmax
+
+		return
+	}
+
+	if float64(vmax) < float64(m.peakVmax)*(1-damageVmaxReduction) {
+		m.damaged = true
+	}
+}
+
+func (m *IncidentMonitor) updatePenalty(t *Transformer, sequenceID uint32) {
+	flags := t.Flags()
+	input := t.ThrottleInputPercent()
+	output := t.ThrottleOutputPercent()
+
+	throttleCut := flags.Live &&
+		!flags.TCSActive &&
+		!flags.RevLimiterAlert &&
+		t.GroundSpeedMetresPerSecond() > penaltyMinimumSpeed &&
+		input >= penaltyThrottleInputPercent &&
+		output < input*penaltyThrottleRatio
+
+	if throttleCut && !m.throttleCut {
+		m.throttleCutAt = sequenceID
+	}
+
+	m.throttleCut = throttleCut
+}
+
+func (m *IncidentMonitor) penaltyElapsed() time.Duration {
+	if !m.throttleCut {
+		return 0
+	}
+
+	return framesElapsed(m.throttleCutAt, m.lastSequence)
+}
+
+func (m *IncidentMonitor) reset() {
+	m.vehicleID = 0
+	m.peakVmax = 0
+	m.damaged = false
+	m.throttleCut = false
+}
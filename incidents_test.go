@@ -0,0 +1,173 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type IncidentMonitorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	monitor     *gttelemetry.IncidentMonitor
+}
+
+func TestIncidentMonitorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(IncidentMonitorTestSuite))
+}
+
+func (suite *IncidentMonitorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.RaceLaps = 5
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.RawTelemetry.GroundSpeed = 40
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 300
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+
+	suite.monitor = gttelemetry.NewIncidentMonitor()
+}
+
+// advance feeds the given number of frames to the monitor.
+func (suite *IncidentMonitorTestSuite) advance(frames int) {
+	for range frames {
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.monitor.Update(suite.transformer)
+	}
+}
+
+func (suite *IncidentMonitorTestSuite) TestNoPenaltyAtFullThrottle() {
+	// Arrange
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.transformer.RawTelemetry.ThrottleOutput = 255
+
+	// Act
+	suite.advance(120)
+
+	// Assert
+	suite.Zero(suite.monitor.ActivePenaltySeconds())
+}
+
+func (suite *IncidentMonitorTestSuite) TestPenaltyReportedWhenThrottleIsCut() {
+	// Arrange
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.transformer.RawTelemetry.ThrottleOutput = 80
+
+	// Act
+	suite.advance(121)
+
+	// Assert
+	suite.InDelta(2.0, suite.monitor.ActivePenaltySeconds(), 0.001)
+}
+
+func (suite *IncidentMonitorTestSuite) TestPenaltyReportedAtDetectionTime() {
+	// Arrange
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.transformer.RawTelemetry.ThrottleOutput = 80
+
+	// Act
+	suite.advance(31)
+
+	// Assert
+	suite.InDelta(0.5, suite.monitor.ActivePenaltySeconds(), 0.001, "30 frames are 500ms, not 30 rounded frame intervals")
+}
+
+func (suite *IncidentMonitorTestSuite) TestShortThrottleCutIsIgnored() {
+	// Arrange
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.transformer.RawTelemetry.ThrottleOutput = 80
+
+	// Act
+	suite.advance(10)
+
+	// Assert
+	suite.Zero(suite.monitor.ActivePenaltySeconds())
+}
+
+func (suite *IncidentMonitorTestSuite) TestThrottleCutByTractionControlIsNotAPenalty() {
+	// Arrange
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.transformer.RawTelemetry.ThrottleOutput = 80
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, true)
+
+	// Act
+	suite.advance(120)
+
+	// Assert
+	suite.Zero(suite.monitor.ActivePenaltySeconds())
+}
+
+func (suite *IncidentMonitorTestSuite) TestPenaltyClearsWhenThrottleRestored() {
+	// Arrange
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.transformer.RawTelemetry.ThrottleOutput = 80
+	suite.advance(120)
+
+	// Act
+	suite.transformer.RawTelemetry.ThrottleOutput = 255
+	suite.advance(1)
+
+	// Assert
+	suite.Zero(suite.monitor.ActivePenaltySeconds())
+}
+
+func (suite *IncidentMonitorTestSuite) TestDamageReportedWhenTopSpeedDrops() {
+	// Arrange
+	suite.advance(10)
+
+	// Act
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 280
+	suite.advance(1)
+
+	// Assert
+	suite.True(suite.monitor.HasDamage())
+}
+
+func (suite *IncidentMonitorTestSuite) TestDamageNotReportedForSmallFluctuations() {
+	// Arrange
+	suite.advance(10)
+
+	// Act
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 299
+	suite.advance(1)
+
+	// Assert
+	suite.False(suite.monitor.HasDamage())
+}
+
+func (suite *IncidentMonitorTestSuite) TestDamageClearedWhenLeavingCircuit() {
+	// Arrange
+	suite.advance(10)
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 250
+	suite.advance(1)
+
+	// Act
+	suite.transformer.RawTelemetry.RaceEntrants = -1
+	suite.advance(1)
+
+	// Assert
+	suite.False(suite.monitor.HasDamage())
+}
+
+func (suite *IncidentMonitorTestSuite) TestDamageKeptWhilePaused() {
+	// Arrange
+	suite.advance(10)
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 250
+	suite.advance(1)
+
+	// Act
+	suite.transformer.SetFlags(true, true, false, true, false, false, false, false, false, false, false, false)
+	suite.advance(60)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.advance(1)
+
+	// Assert
+	suite.True(suite.monitor.HasDamage())
+}
@@ -0,0 +1,348 @@
+// Package apimanifest lists the exported API of the packages of a module, one declaration per line in the style of
+// the api/*.txt files of the Go distribution, so that changes to the public API show up in review as a diff of the
+// manifest.
+package apimanifest
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrNoPackages indicates that no importable packages were found to list.
+var ErrNoPackages = errors.New("no packages found")
+
+// Generate lists the exported API of the packages in the module rooted at dir. The package in dir and the packages
+// below each of the subdirectories are listed, skipping commands and test files. Files are selected with the build
+// constraints of linux/amd64 so that the manifest is the same whichever platform it is generated on.
+func Generate(dir, modulePath, version string, subdirs ...string) ([]byte, error) {
+	dirs := []string{dir}
+
+	for _, subdir := range subdirs {
+		err := filepath.WalkDir(filepath.Join(dir, subdir), func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() && path != filepath.Join(dir, subdir) && (entry.Name() == "testdata" || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+
+			if entry.IsDir() {
+				dirs = append(dirs, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing packages: %w", err)
+		}
+	}
+
+	lines := []string{}
+
+	for _, pkgDir := range dirs {
+		rel, err := filepath.Rel(dir, pkgDir)
+		if err != nil {
+			return nil, fmt.Errorf("listing packages: %w", err)
+		}
+
+		pkgLines, err := packageAPI(pkgDir, path.Join(modulePath, filepath.ToSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, pkgLines...)
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoPackages, dir)
+	}
+
+	slices.Sort(lines)
+
+	var manifest strings.Builder
+
+	fmt.Fprintf(&manifest, "# API manifest of %s %s, generated by tools/api_manifest\n", modulePath, version)
+
+	for _, line := range slices.Compact(lines) {
+		manifest.WriteString(line)
+		manifest.WriteByte('\n')
+	}
+
+	return []byte(manifest.String()), nil
+}
+
+// packageAPI lists the exported declarations of the package in a directory, or nothing when the directory has no Go
+// files or holds a command.
+func packageAPI(dir, importPath string) ([]string, error) {
+	ctxt := build.Default
+	ctxt.GOOS = "linux"
+	ctxt.GOARCH = "amd64"
+	ctxt.CgoEnabled = true
+
+	pkg, err := ctxt.ImportDir(dir, 0)
+	if err != nil {
+		var noGo *build.NoGoError
+		if errors.As(err, &noGo) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading package %s: %w", importPath, err)
+	}
+
+	if pkg.IsCommand() || strings.Contains(importPath, "/internal/") || strings.HasSuffix(importPath, "/internal") {
+		return nil, nil
+	}
+
+	fset := token.NewFileSet()
+	prefix := "pkg " + importPath + ", "
+	lines := []string{}
+
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+
+		for _, decl := range file.Decls {
+			for _, line := range declAPI(decl) {
+				lines = append(lines, prefix+line)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+// declAPI lists the exported parts of a top level declaration.
+func declAPI(decl ast.Decl) []string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		return funcAPI(decl)
+	case *ast.GenDecl:
+		return genDeclAPI(decl)
+	}
+
+	return nil
+}
+
+// funcAPI lists an exported function, or a method of an exported type.
+func funcAPI(decl *ast.FuncDecl) []string {
+	if !decl.Name.IsExported() {
+		return nil
+	}
+
+	if decl.Recv == nil {
+		return []string{"func " + decl.Name.Name + typeParams(decl.Type.TypeParams) + signature(decl.Type)}
+	}
+
+	recv := decl.Recv.List[0].Type
+	if !ast.IsExported(baseTypeName(recv)) {
+		return nil
+	}
+
+	return []string{"method (" + types.ExprString(recv) + ") " + decl.Name.Name + signature(decl.Type)}
+}
+
+// genDeclAPI lists the exported constants, variables and types of a declaration.
+func genDeclAPI(decl *ast.GenDecl) []string {
+	lines := []string{}
+
+	// Constants without a type or value repeat those of the previous constant of the group, as with iota
+	var lastType ast.Expr
+
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.ValueSpec:
+			if decl.Tok == token.CONST && (spec.Type != nil || len(spec.Values) > 0) {
+				lastType = spec.Type
+			}
+
+			for i, name := range spec.Names {
+				if name.IsExported() {
+					lines = append(lines, valueAPI(decl.Tok, name.Name, spec, i, lastType))
+				}
+			}
+		case *ast.TypeSpec:
+			if spec.Name.IsExported() {
+				lines = append(lines, typeAPI(spec)...)
+			}
+		}
+	}
+
+	return lines
+}
+
+// valueAPI describes an exported constant or variable by its type, or for untyped constants by their value.
+func valueAPI(tok token.Token, name string, spec *ast.ValueSpec, index int, lastType ast.Expr) string {
+	keyword := tok.String() + " " + name
+
+	switch {
+	case spec.Type != nil:
+		return keyword + " " + types.ExprString(spec.Type)
+	case tok == token.CONST && len(spec.Values) > index:
+		return keyword + " = " + types.ExprString(spec.Values[index])
+	case tok == token.CONST && lastType != nil:
+		return keyword + " " + types.ExprString(lastType)
+	case tok == token.VAR && len(spec.Values) > index && isErrorConstructor(spec.Values[index]):
+		return keyword + " error"
+	}
+
+	return keyword
+}
+
+// isErrorConstructor reports whether an expression creates an error with errors.New or fmt.Errorf, so that the text
+// of an error can change without changing the manifest.
+func isErrorConstructor(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	name := types.ExprString(call.Fun)
+
+	return name == "errors.New" || name == "fmt.Errorf"
+}
+
+// typeAPI lists an exported type, with the exported fields of a struct and the methods of an interface on lines of
+// their own.
+func typeAPI(spec *ast.TypeSpec) []string {
+	name := "type " + spec.Name.Name + typeParams(spec.TypeParams)
+
+	if spec.Assign.IsValid() {
+		return []string{name + " = " + types.ExprString(spec.Type)}
+	}
+
+	switch typ := spec.Type.(type) {
+	case *ast.StructType:
+		lines := []string{name + " struct"}
+
+		for _, field := range typ.Fields.List {
+			if len(field.Names) == 0 {
+				if ast.IsExported(baseTypeName(field.Type)) {
+					lines = append(lines, name+" struct, embedded "+types.ExprString(field.Type))
+				}
+
+				continue
+			}
+
+			for _, fieldName := range field.Names {
+				if fieldName.IsExported() {
+					lines = append(lines, name+" struct, "+fieldName.Name+" "+types.ExprString(field.Type))
+				}
+			}
+		}
+
+		return lines
+	case *ast.InterfaceType:
+		lines := []string{name + " interface"}
+
+		for _, method := range typ.Methods.List {
+			if len(method.Names) == 0 {
+				lines = append(lines, name+" interface, embedded "+types.ExprString(method.Type))
+
+				continue
+			}
+
+			funcType, ok := method.Type.(*ast.FuncType)
+			if !ok {
+				continue
+			}
+
+			for _, methodName := range method.Names {
+				if methodName.IsExported() {
+					lines = append(lines, name+" interface, "+methodName.Name+signature(funcType))
+				} else {
+					lines = append(lines, name+" interface, unexported methods")
+				}
+			}
+		}
+
+		return lines
+	}
+
+	return []string{name + " " + types.ExprString(spec.Type)}
+}
+
+// signature formats the parameters and results of a function without their names.
+func signature(funcType *ast.FuncType) string {
+	params := "(" + fieldTypes(funcType.Params) + ")"
+
+	if funcType.Results == nil || len(funcType.Results.List) == 0 {
+		return params
+	}
+
+	results := fieldTypes(funcType.Results)
+	if len(funcType.Results.List) == 1 && len(funcType.Results.List[0].Names) <= 1 {
+		return params + " " + results
+	}
+
+	return params + " (" + results + ")"
+}
+
+// typeParams formats the type parameters of a generic function or type.
+func typeParams(list *ast.FieldList) string {
+	if list == nil || len(list.List) == 0 {
+		return ""
+	}
+
+	params := []string{}
+
+	for _, field := range list.List {
+		names := []string{}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+
+		params = append(params, strings.Join(names, ", ")+" "+types.ExprString(field.Type))
+	}
+
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// fieldTypes formats the types of a parameter or result list, repeating the type of grouped names.
+func fieldTypes(list *ast.FieldList) string {
+	if list == nil {
+		return ""
+	}
+
+	fields := []string{}
+
+	for _, field := range list.List {
+		typ := types.ExprString(field.Type)
+
+		for range max(len(field.Names), 1) {
+			fields = append(fields, typ)
+		}
+	}
+
+	return strings.Join(fields, ", ")
+}
+
+// baseTypeName returns the name of a type without pointers, type arguments or package qualifier.
+func baseTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return baseTypeName(expr.X)
+	case *ast.IndexExpr:
+		return baseTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	case *ast.Ident:
+		return expr.Name
+	}
+
+	return ""
+}
@@ -0,0 +1,39 @@
+package apimanifest_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/internal/apimanifest"
+)
+
+type APIManifestTestSuite struct {
+	suite.Suite
+}
+
+func TestAPIManifestTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(APIManifestTestSuite))
+}
+
+func (suite *APIManifestTestSuite) TestGenerateListsExportedAPI() {
+	// Arrange
+	wantManifest, err := os.ReadFile("testdata/example.txt")
+	suite.Require().NoError(err)
+
+	// Act
+	gotManifest, err := apimanifest.Generate("testdata/example", "example.com/m", "1.2.3", "sub", "internal", "cmd")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(string(wantManifest), string(gotManifest))
+}
+
+func (suite *APIManifestTestSuite) TestGenerateWithoutPackagesFails() {
+	// Act
+	_, err := apimanifest.Generate("testdata/example/cmd", "example.com/m/cmd", "1.2.3")
+
+	// Assert
+	suite.ErrorIs(err, apimanifest.ErrNoPackages)
+}
@@ -0,0 +1,64 @@
+package example
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	Untyped = 5
+	hidden  = 1
+)
+
+type Mode int
+
+const (
+	ModeOff Mode = iota
+	ModeOn
+)
+
+var ErrExample = errors.New("example")
+
+var Started time.Time
+
+type Options struct {
+	Name    string
+	Retries int
+	secret  string
+	*Embedded
+}
+
+type Embedded struct{}
+
+type Source interface {
+	Channel(name string) (float32, bool)
+	Close()
+}
+
+type Pair[T any] struct {
+	First, Second T
+}
+
+type Alias = Options
+
+func New(name string, retries, timeout int) (*Options, error) {
+	return &Options{Name: name, Retries: retries}, nil
+}
+
+func Map[T any](values []T, fn func(T) T) []T {
+	return values
+}
+
+func (o *Options) Describe() string {
+	return o.Name + o.secret
+}
+
+func (o Options) unexported() {}
+
+func (p Pair[T]) Swap() Pair[T] {
+	return Pair[T]{First: p.Second, Second: p.First}
+}
+
+type private struct{}
+
+func (private) Exported() {}
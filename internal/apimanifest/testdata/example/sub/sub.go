@@ -0,0 +1,6 @@
+// Package sub is listed after the root package.
+package sub
+
+func Hello() string {
+	return "hello"
+}
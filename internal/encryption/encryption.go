@@ -0,0 +1,292 @@
+// Package encryption implements the password based encryption of recordings.
+//
+// An encrypted stream starts with a header of the magic bytes, the number of PBKDF2 iterations and a random salt,
+// from which a 256 bit AES key is derived from the password. The data follows in chunks sealed with AES-GCM, each
+// preceded by a flag marking the final chunk and the length of the sealed chunk. The nonce of each chunk is its
+// position in the stream and the final flag, and the header is authenticated with every chunk, so chunks that are
+// reordered, removed or cut off after the final chunk, or a header that is changed, fail to decrypt.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// MagicSize is the number of bytes at the start of a stream needed by IsEncrypted.
+	MagicSize = 6
+
+	// iterations is the number of PBKDF2 iterations used to derive the key of new streams, following the OWASP
+	// recommendation for PBKDF2-HMAC-SHA256. Streams record the count they were written with.
+	iterations = 600000
+	// maxIterations bounds the count read from a header, so that a crafted header cannot stall the reader.
+	maxIterations = 10000000
+	saltSize      = 16
+	keySize       = 32
+	headerSize    = MagicSize + 4 + saltSize
+	// chunkSize is the largest number of plaintext bytes sealed in a chunk.
+	chunkSize = 64 << 10
+	// chunkHeaderSize is the size of the final flag and sealed length that precede each chunk.
+	chunkHeaderSize = 5
+)
+
+var (
+	// ErrNoPassword indicates an attempt to encrypt without a password.
+	ErrNoPassword = errors.New("no password")
+	// ErrNotEncrypted indicates a stream that does not start with the header of an encrypted stream.
+	ErrNotEncrypted = errors.New("not encrypted")
+	// ErrDecryptionFailed indicates a chunk that could not be decrypted, because the password is wrong or the data
+	// has been corrupted or tampered with.
+	ErrDecryptionFailed = errors.New("decryption failed, wrong password or corrupt data")
+	// ErrTruncated indicates a stream that ends before its final chunk.
+	ErrTruncated = errors.New("encrypted data truncated")
+)
+
+// magic returns the bytes every encrypted stream starts with, "GTENC" and the format version.
+func magic() []byte {
+	return []byte{'G', 'T', 'E', 'N', 'C', 1}
+}
+
+// IsEncrypted returns true when the start of a stream is the header of an encrypted stream.
+func IsEncrypted(prefix []byte) bool {
+	return bytes.HasPrefix(prefix, magic())
+}
+
+// Writer encrypts the data written to it. Close must be called to write the final chunk, without which the stream
+// cannot be read.
+type Writer struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	buffer  []byte
+	counter uint64
+	closed  bool
+}
+
+// NewWriter writes the header of a new encrypted stream to w and returns a writer encrypting with a key derived from
+// the password.
+func NewWriter(w io.Writer, password string) (*Writer, error) {
+	if password == "" {
+		return nil, ErrNoPassword
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, magic())
+	binary.BigEndian.PutUint32(header[MagicSize:], iterations)
+
+	_, err := rand.Read(header[MagicSize+4:])
+	if err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	aead, err := newAEAD(password, header)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(header)
+	if err != nil {
+		return nil, fmt.Errorf("write header: %w", err)
+	}
+
+	return &Writer{w: w, aead: aead, header: header, buffer: make([]byte, 0, chunkSize)}, nil
+}
+
+// Write encrypts p, writing each chunk as it fills.
+func (e *Writer) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	written := 0
+
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data arrives, as the last chunk is held back until Close so that it
+		// can be marked final
+		if len(e.buffer) == chunkSize {
+			err := e.seal(false)
+			if err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(e.buffer[len(e.buffer):chunkSize], p)
+		e.buffer = e.buffer[:len(e.buffer)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close writes the final chunk. It does not close the underlying writer.
+func (e *Writer) Close() error {
+	if e.closed {
+		return nil
+	}
+
+	e.closed = true
+
+	return e.seal(true)
+}
+
+func (e *Writer) seal(final bool) error {
+	chunk := make([]byte, chunkHeaderSize, chunkHeaderSize+len(e.buffer)+e.aead.Overhead())
+	if final {
+		chunk[0] = 1
+	}
+
+	chunk = e.aead.Seal(chunk, nonce(e.counter, final), e.buffer, e.header)
+	binary.BigEndian.PutUint32(chunk[1:chunkHeaderSize], uint32(len(chunk)-chunkHeaderSize)) //nolint:gosec // chunks are at most 64 KiB
+
+	e.counter++
+	e.buffer = e.buffer[:0]
+
+	_, err := e.w.Write(chunk)
+	if err != nil {
+		return fmt.Errorf("write chunk: %w", err)
+	}
+
+	return nil
+}
+
+// Reader decrypts an encrypted stream.
+type Reader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	header  []byte
+	plain   []byte
+	counter uint64
+	done    bool
+}
+
+// NewReader reads the header of an encrypted stream from r and returns a reader decrypting it with a key derived
+// from the password. The first chunk is decrypted straight away, so a wrong password returns ErrDecryptionFailed
+// here rather than on the first read.
+func NewReader(r io.Reader, password string) (*Reader, error) {
+	header := make([]byte, headerSize)
+
+	_, err := io.ReadFull(r, header)
+	if err != nil || !IsEncrypted(header) {
+		return nil, ErrNotEncrypted
+	}
+
+	count := binary.BigEndian.Uint32(header[MagicSize:])
+	if count == 0 || count > maxIterations {
+		return nil, fmt.Errorf("%w: invalid iteration count", ErrDecryptionFailed)
+	}
+
+	aead, err := newAEAD(password, header)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &Reader{r: r, aead: aead, header: header}
+
+	err = reader.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// Read decrypts the next bytes of the stream into p.
+func (d *Reader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+
+		err := d.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+
+	return n, nil
+}
+
+// open reads and decrypts the next chunk.
+func (d *Reader) open() error {
+	chunkHeader := make([]byte, chunkHeaderSize)
+
+	_, err := io.ReadFull(d.r, chunkHeader)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncated
+	} else if err != nil {
+		return fmt.Errorf("read chunk: %w", err)
+	}
+
+	final := chunkHeader[0] == 1
+
+	sealedSize := binary.BigEndian.Uint32(chunkHeader[1:])
+	if sealedSize > uint32(chunkSize+d.aead.Overhead()) { //nolint:gosec // the overhead is 16 bytes
+		return ErrDecryptionFailed
+	}
+
+	sealed := make([]byte, sealedSize)
+
+	_, err = io.ReadFull(d.r, sealed)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncated
+	} else if err != nil {
+		return fmt.Errorf("read chunk: %w", err)
+	}
+
+	d.plain, err = d.aead.Open(sealed[:0], nonce(d.counter, final), sealed, d.header)
+	if err != nil {
+		return ErrDecryptionFailed
+	}
+
+	d.counter++
+	d.done = final
+
+	return nil
+}
+
+// newAEAD derives the key from the password and the salt and iterations in the header.
+func newAEAD(password string, header []byte) (cipher.AEAD, error) {
+	salt := header[MagicSize+4:]
+	count := int(binary.BigEndian.Uint32(header[MagicSize:]))
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, count, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create GCM: %w", err)
+	}
+
+	return aead, nil
+}
+
+// nonce returns the nonce of a chunk, its position in the stream followed by the final flag.
+func nonce(counter uint64, final bool) []byte {
+	n := make([]byte, 12)
+	binary.BigEndian.PutUint64(n[3:11], counter)
+
+	if final {
+		n[11] = 1
+	}
+
+	return n
+}
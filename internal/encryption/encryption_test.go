@@ -0,0 +1,133 @@
+package encryption_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/internal/encryption"
+)
+
+const password = "correct horse battery staple"
+
+type EncryptionTestSuite struct {
+	suite.Suite
+}
+
+func TestEncryptionTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(EncryptionTestSuite))
+}
+
+// encrypt returns data encrypted with the password, written in the given number of parts.
+func (suite *EncryptionTestSuite) encrypt(data []byte, parts int) []byte {
+	var buffer bytes.Buffer
+
+	writer, err := encryption.NewWriter(&buffer, password)
+	suite.Require().NoError(err)
+
+	size := len(data)/parts + 1
+	for len(data) > 0 {
+		n := min(size, len(data))
+		_, err = writer.Write(data[:n])
+		suite.Require().NoError(err)
+
+		data = data[n:]
+	}
+
+	suite.Require().NoError(writer.Close())
+
+	return buffer.Bytes()
+}
+
+func (suite *EncryptionTestSuite) TestRoundTrip() {
+	// Arrange
+	tests := map[string]struct {
+		size  int
+		parts int
+	}{
+		"empty":                 {size: 0, parts: 1},
+		"smaller than a chunk":  {size: 1000, parts: 3},
+		"exactly one chunk":     {size: 64 << 10, parts: 1},
+		"across several chunks": {size: 200000, parts: 7},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			data := make([]byte, test.size)
+			for i := range data {
+				data[i] = byte(i * 7)
+			}
+
+			encrypted := suite.encrypt(data, test.parts)
+
+			// Act
+			reader, err := encryption.NewReader(bytes.NewReader(encrypted), password)
+			suite.Require().NoError(err)
+
+			got, err := io.ReadAll(reader)
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.True(encryption.IsEncrypted(encrypted))
+			suite.False(bytes.Contains(encrypted, []byte("correct horse")))
+			suite.Equal(data, got)
+		})
+	}
+}
+
+func (suite *EncryptionTestSuite) TestWrongPasswordFailsToDecrypt() {
+	// Arrange
+	encrypted := suite.encrypt([]byte("telemetry"), 1)
+
+	// Act
+	_, err := encryption.NewReader(bytes.NewReader(encrypted), "wrong")
+
+	// Assert
+	suite.ErrorIs(err, encryption.ErrDecryptionFailed)
+}
+
+func (suite *EncryptionTestSuite) TestTamperedDataFailsToDecrypt() {
+	// Arrange
+	encrypted := suite.encrypt([]byte("telemetry"), 1)
+	encrypted[len(encrypted)-1] ^= 1
+
+	// Act
+	_, err := encryption.NewReader(bytes.NewReader(encrypted), password)
+
+	// Assert
+	suite.ErrorIs(err, encryption.ErrDecryptionFailed)
+}
+
+func (suite *EncryptionTestSuite) TestTruncatedStreamReturnsError() {
+	// Arrange
+	encrypted := suite.encrypt(make([]byte, 200000), 1)
+
+	reader, err := encryption.NewReader(bytes.NewReader(encrypted[:len(encrypted)/2]), password)
+	suite.Require().NoError(err)
+
+	// Act
+	_, err = io.ReadAll(reader)
+
+	// Assert
+	suite.ErrorIs(err, encryption.ErrTruncated)
+}
+
+func (suite *EncryptionTestSuite) TestUnencryptedStreamIsRejected() {
+	// Act
+	_, err := encryption.NewReader(bytes.NewReader([]byte("plain gzip data here")), password)
+
+	// Assert
+	suite.ErrorIs(err, encryption.ErrNotEncrypted)
+	suite.False(encryption.IsEncrypted([]byte{0x1f, 0x8b}))
+}
+
+func (suite *EncryptionTestSuite) TestWriterRequiresPassword() {
+	// Act
+	_, err := encryption.NewWriter(io.Discard, "")
+
+	// Assert
+	suite.ErrorIs(err, encryption.ErrNoPassword)
+}
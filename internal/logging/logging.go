@@ -0,0 +1,45 @@
+//go:build !tinygo
+
+// Package logging selects the logger used by the client. Standard builds log with zerolog, while TinyGo builds use a
+// small writer based logger with the subset of the zerolog API the client calls, so zerolog is not compiled in.
+package logging
+
+import (
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// Logger is the logger passed to the client and its packages.
+type Logger = zerolog.Logger
+
+// Level is the severity of a log message.
+type Level = zerolog.Level
+
+// WarnLevel is the level used when a configured level cannot be parsed.
+const WarnLevel = zerolog.WarnLevel
+
+// New creates a logger writing timestamped messages to w.
+func New(w io.Writer) Logger {
+	return zerolog.New(w).With().Timestamp().Logger()
+}
+
+// Nop returns a logger that discards all messages.
+func Nop() Logger {
+	return zerolog.Nop()
+}
+
+// WithComponent returns a copy of the logger that adds the component name to every message.
+func WithComponent(logger Logger, component string) Logger {
+	return logger.With().Str("component", component).Logger()
+}
+
+// ParseLevel converts a level name such as "debug" or "warn" into a Level.
+func ParseLevel(level string) (Level, error) {
+	return zerolog.ParseLevel(level) //nolint:wrapcheck // the error names the level already
+}
+
+// SetGlobalLevel sets the minimum level logged by every logger.
+func SetGlobalLevel(level Level) {
+	zerolog.SetGlobalLevel(level)
+}
@@ -0,0 +1,191 @@
+//go:build tinygo
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Level is the severity of a log message.
+type Level int8
+
+// Levels in increasing severity, matching the zerolog level values.
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	Disabled Level = 7
+)
+
+var errUnknownLevel = errors.New("unknown log level")
+
+var levelNames = map[Level]string{ //nolint:gochecknoglobals // fixed lookup table
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+	Disabled:   "disabled",
+}
+
+// globalLevel is the minimum level logged by every logger, as with zerolog.SetGlobalLevel.
+var globalLevel atomic.Int32 //nolint:gochecknoglobals // mirrors the process wide zerolog level
+
+// Logger writes one line per message in the logfmt style. The zero value discards all messages.
+type Logger struct {
+	writer io.Writer
+	fields []byte
+}
+
+// New creates a logger writing timestamped messages to w.
+func New(w io.Writer) Logger {
+	return Logger{writer: w}
+}
+
+// Nop returns a logger that discards all messages.
+func Nop() Logger {
+	return Logger{}
+}
+
+// WithComponent returns a copy of the logger that adds the component name to every message.
+func WithComponent(logger Logger, component string) Logger {
+	fields := append([]byte(nil), logger.fields...)
+	fields = append(fields, " component="...)
+	fields = strconv.AppendQuote(fields, component)
+
+	return Logger{writer: logger.writer, fields: fields}
+}
+
+// ParseLevel converts a level name such as "debug" or "warn" into a Level.
+func ParseLevel(level string) (Level, error) {
+	for value, name := range levelNames {
+		if strings.EqualFold(name, level) {
+			return value, nil
+		}
+	}
+
+	return Disabled, fmt.Errorf("%w: %q", errUnknownLevel, level)
+}
+
+// SetGlobalLevel sets the minimum level logged by every logger.
+func SetGlobalLevel(level Level) {
+	globalLevel.Store(int32(level))
+}
+
+// Debug starts a message at the debug level.
+func (l *Logger) Debug() *Event {
+	return l.newEvent(DebugLevel)
+}
+
+// Info starts a message at the info level.
+func (l *Logger) Info() *Event {
+	return l.newEvent(InfoLevel)
+}
+
+// Warn starts a message at the warn level.
+func (l *Logger) Warn() *Event {
+	return l.newEvent(WarnLevel)
+}
+
+// Error starts a message at the error level.
+func (l *Logger) Error() *Event {
+	return l.newEvent(ErrorLevel)
+}
+
+// newEvent returns nil when the message would be discarded, which every Event method accepts.
+func (l *Logger) newEvent(level Level) *Event {
+	if l.writer == nil || level < Level(globalLevel.Load()) {
+		return nil
+	}
+
+	event := &Event{writer: l.writer}
+	event.buf = time.Now().AppendFormat(event.buf, time.RFC3339)
+	event.buf = append(event.buf, " level="...)
+	event.buf = append(event.buf, levelNames[level]...)
+	event.buf = append(event.buf, l.fields...)
+
+	return event
+}
+
+// Event is a message being built by a Logger. A nil Event discards everything added to it.
+type Event struct {
+	writer io.Writer
+	buf    []byte
+}
+
+// Str adds a string field to the message.
+func (e *Event) Str(key, value string) *Event {
+	if e == nil {
+		return nil
+	}
+
+	e.buf = append(e.buf, ' ')
+	e.buf = append(e.buf, key...)
+	e.buf = append(e.buf, '=')
+	e.buf = strconv.AppendQuote(e.buf, value)
+
+	return e
+}
+
+// Int adds an integer field to the message.
+func (e *Event) Int(key string, value int) *Event {
+	if e == nil {
+		return nil
+	}
+
+	e.buf = append(e.buf, ' ')
+	e.buf = append(e.buf, key...)
+	e.buf = append(e.buf, '=')
+	e.buf = strconv.AppendInt(e.buf, int64(value), 10)
+
+	return e
+}
+
+// Stringer adds the string form of value to the message.
+func (e *Event) Stringer(key string, value fmt.Stringer) *Event {
+	if e == nil {
+		return nil
+	}
+
+	if value == nil {
+		return e.Str(key, "")
+	}
+
+	return e.Str(key, value.String())
+}
+
+// Err adds the error to the message under the error key, nil errors are left out.
+func (e *Event) Err(err error) *Event {
+	if e == nil || err == nil {
+		return e
+	}
+
+	return e.Str("error", err.Error())
+}
+
+// Msg writes the message and finishes the event.
+func (e *Event) Msg(message string) {
+	if e == nil {
+		return
+	}
+
+	e.Str("message", message)
+	e.buf = append(e.buf, '\n')
+
+	_, _ = e.writer.Write(e.buf)
+}
+
+// Msgf formats and writes the message and finishes the event.
+func (e *Event) Msgf(format string, args ...any) {
+	if e == nil {
+		return
+	}
+
+	e.Msg(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,57 @@
+//go:build tinygo
+
+package logging_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
+)
+
+func TestLoggerWritesFieldsAndMessage(t *testing.T) {
+	logging.SetGlobalLevel(logging.DebugLevel)
+
+	var out bytes.Buffer
+
+	logger := logging.WithComponent(logging.New(&out), "circuit db")
+	logger.Warn().Err(errors.New("boom")).Int("count", 3).Msgf("dropped %d", 3)
+
+	line := out.String()
+	for _, want := range []string{` level=warn`, ` component="circuit db"`, ` error="boom"`, ` count=3`, ` message="dropped 3"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line %q does not contain %q", line, want)
+		}
+	}
+
+	if !strings.HasSuffix(line, "\n") {
+		t.Errorf("log line %q does not end with a newline", line)
+	}
+}
+
+func TestLoggerSkipsMessagesBelowGlobalLevel(t *testing.T) {
+	logging.SetGlobalLevel(logging.WarnLevel)
+	t.Cleanup(func() { logging.SetGlobalLevel(logging.DebugLevel) })
+
+	var out bytes.Buffer
+
+	logger := logging.New(&out)
+	logger.Info().Str("key", "value").Msg("hidden")
+
+	if out.Len() != 0 {
+		t.Errorf("expected no output below the global level, got %q", out.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := logging.ParseLevel("error")
+	if err != nil || level != logging.ErrorLevel {
+		t.Errorf("ParseLevel(error) = %v, %v", level, err)
+	}
+
+	if _, err := logging.ParseLevel("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,156 @@
+package reader
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
+)
+
+// fanOutQueueLength is the number of packets queued for each fan-out target, about one second of telemetry.
+const fanOutQueueLength = 64
+
+// FanOutStats holds the delivery counters of a fan-out target.
+type FanOutStats struct {
+	Address string `json:"address"`
+	Sent    int64  `json:"sent"`
+	Errors  int64  `json:"errors"`
+	Dropped int64  `json:"dropped"`
+}
+
+// FanOut forwards the packets received from the console, still encrypted, to other UDP consumers such as SimHub.
+//
+// Each target has its own queue so that a slow or unreachable target does not hold up the others or the reader. When
+// a queue is full the oldest packet is dropped to make room, as consumers want the freshest telemetry.
+type FanOut struct {
+	targets []*fanOutTarget
+	log     logging.Logger
+}
+
+type fanOutTarget struct {
+	addr    *net.UDPAddr
+	queue   chan []byte
+	sent    atomic.Int64
+	errors  atomic.Int64
+	dropped atomic.Int64
+}
+
+// NewFanOut creates a fan-out to the given host:port addresses. Packets are only delivered while Run is active.
+func NewFanOut(addresses []string, log logging.Logger) (*FanOut, error) {
+	fanOut := &FanOut{
+		targets: make([]*fanOutTarget, 0, len(addresses)),
+		log:     log,
+	}
+
+	for _, address := range addresses {
+		addr, err := net.ResolveUDPAddr("udp", address)
+		if err != nil {
+			return nil, fmt.Errorf("resolve fan-out address %q: %w", address, err)
+		}
+
+		fanOut.targets = append(fanOut.targets, &fanOutTarget{
+			addr:  addr,
+			queue: make(chan []byte, fanOutQueueLength),
+		})
+	}
+
+	return fanOut, nil
+}
+
+// Send queues a packet for delivery to every target without blocking.
+func (f *FanOut) Send(packet []byte) {
+	if len(f.targets) == 0 {
+		return
+	}
+
+	// The reader reuses its buffer, so the packet must be copied before it is queued
+	packet = append([]byte(nil), packet...)
+
+	for _, target := range f.targets {
+		target.enqueue(packet)
+	}
+}
+
+// Run delivers queued packets to the targets until the context is cancelled.
+func (f *FanOut) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, target := range f.targets {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			target.deliver(ctx, f.log)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Stats returns the delivery counters of every target, in the order the targets were configured.
+func (f *FanOut) Stats() []FanOutStats {
+	stats := make([]FanOutStats, 0, len(f.targets))
+
+	for _, target := range f.targets {
+		stats = append(stats, FanOutStats{
+			Address: target.addr.String(),
+			Sent:    target.sent.Load(),
+			Errors:  target.errors.Load(),
+			Dropped: target.dropped.Load(),
+		})
+	}
+
+	return stats
+}
+
+// enqueue adds a packet to the queue, dropping the oldest queued packet when the queue is full.
+func (t *fanOutTarget) enqueue(packet []byte) {
+	for {
+		select {
+		case t.queue <- packet:
+			return
+		default:
+		}
+
+		select {
+		case <-t.queue:
+			t.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// deliver writes queued packets to the target until the context is cancelled.
+func (t *fanOutTarget) deliver(ctx context.Context, log logging.Logger) {
+	conn, err := net.DialUDP("udp", nil, t.addr)
+	if err != nil {
+		t.errors.Add(1)
+		log.Error().Err(err).Str("target", t.addr.String()).Msg("failed to connect to fan-out target")
+
+		return
+	}
+
+	defer conn.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case packet := <-t.queue:
+			_, err := conn.Write(packet)
+			if err != nil {
+				// An unreachable target is reported on later writes, so only log at debug level to avoid flooding
+				t.errors.Add(1)
+				log.Debug().Err(err).Str("target", t.addr.String()).Msg("failed to send packet to fan-out target")
+
+				continue
+			}
+
+			t.sent.Add(1)
+		}
+	}
+}
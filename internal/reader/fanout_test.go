@@ -0,0 +1,137 @@
+package reader_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/internal/reader"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type FanOutTestSuite struct {
+	suite.Suite
+}
+
+func TestFanOutTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(FanOutTestSuite))
+}
+
+func (suite *FanOutTestSuite) TestReceivedPacketsAreForwardedEncrypted() {
+	// Arrange
+	target := newLoopbackListener(suite.T())
+
+	fanOut, err := reader.NewFanOut([]string{target.LocalAddr().String()}, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go fanOut.Run(ctx)
+
+	receivePort := freeLoopbackPort(suite.T())
+	udpReader, err := reader.NewUDPReader("127.0.0.1", receivePort-1, models.Addendum3, reader.UDPOptions{FanOut: fanOut}, zerolog.Nop())
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { _ = udpReader.Close() })
+
+	sender, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: receivePort})
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { _ = sender.Close() })
+
+	packet := encryptedPacket(1)
+
+	// Act
+	_, err = sender.Write(packet)
+	suite.Require().NoError(err)
+
+	_, _, err = udpReader.Read()
+	suite.Require().NoError(err)
+
+	forwarded := make([]byte, 4096)
+	suite.Require().NoError(target.SetReadDeadline(time.Now().Add(time.Second)))
+	bufLen, err := target.Read(forwarded)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(packet, forwarded[:bufLen])
+	suite.Eventually(func() bool {
+		return fanOut.Stats()[0].Sent == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (suite *FanOutTestSuite) TestFullQueueDropsOldestPackets() {
+	// Arrange
+	fanOut, err := reader.NewFanOut([]string{"127.0.0.1:9"}, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	// Act — nothing is delivered while the fan-out is not running
+	for range 100 {
+		fanOut.Send([]byte{0x01})
+	}
+
+	// Assert
+	stats := fanOut.Stats()
+	suite.Require().Len(stats, 1)
+	suite.Equal("127.0.0.1:9", stats[0].Address)
+	suite.Equal(int64(36), stats[0].Dropped)
+	suite.Zero(stats[0].Sent)
+}
+
+func (suite *FanOutTestSuite) TestUnreachableTargetCountsErrors() {
+	// Arrange
+	fanOut, err := reader.NewFanOut([]string{net.JoinHostPort("127.0.0.1", "1")}, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go fanOut.Run(ctx)
+
+	// Act & Assert — the target refusing a packet is reported on a later write
+	suite.Eventually(func() bool {
+		fanOut.Send([]byte{0x01})
+
+		return fanOut.Stats()[0].Errors > 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func (suite *FanOutTestSuite) TestInvalidAddressReturnsError() {
+	// Act
+	_, err := reader.NewFanOut([]string{"not an address"}, zerolog.Nop())
+
+	// Assert
+	suite.Error(err)
+}
+
+// newLoopbackListener listens for UDP packets on a free loopback port.
+func newLoopbackListener(tb testing.TB) *net.UDPConn {
+	tb.Helper()
+
+	listener, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() { _ = listener.Close() })
+
+	return listener
+}
+
+// freeLoopbackPort returns a loopback UDP port that is not in use.
+func freeLoopbackPort(tb testing.TB) int {
+	tb.Helper()
+
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	defer probe.Close()
+
+	return probe.LocalAddr().(*net.UDPAddr).Port //nolint:forcetypeassert // Always a UDP address
+}
@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync/atomic"
 	"time"
 
-	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/internal/encryption"
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
 )
 
 // PacketInterval is the time between packets at the standard 60 Hz telemetry rate.
@@ -19,17 +21,35 @@ const PacketInterval = (1000 / 60) * time.Millisecond
 var (
 	ErrFilenameTooShort         = errors.New("filename too short")
 	ErrUnsupportedFileExtension = errors.New("unsupported file extension")
+	// ErrRecordingEncrypted indicates an encrypted recording opened without a password.
+	ErrRecordingEncrypted = errors.New("recording is encrypted, a password is required")
 )
 
 // FileReader reads GT7 replay files packet by packet.
 type FileReader struct {
 	fileContent *bufio.Scanner
-	log         zerolog.Logger
+	log         logging.Logger
 	closer      func() error
+	file        *countingReader
+	size        int64
 }
 
-// NewFileReader creates a new FileReader for the specified GT7 replay file.
-func NewFileReader(file string, log zerolog.Logger) (*FileReader, error) {
+// countingReader counts the bytes read from a file, for reporting progress through it.
+type countingReader struct {
+	reader io.Reader
+	read   atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.read.Add(int64(n))
+
+	return n, err
+}
+
+// NewFileReader creates a new FileReader for the specified GT7 replay file. Encrypted recordings are decrypted with
+// the password in opts.
+func NewFileReader(file string, opts FileOptions, log logging.Logger) (*FileReader, error) {
 	err := validateFile(file)
 	if err != nil {
 		return nil, err
@@ -40,7 +60,16 @@ func NewFileReader(file string, log zerolog.Logger) (*FileReader, error) {
 		return nil, fmt.Errorf("open file: %w", err)
 	}
 
-	reader, err := getFileReader(file, fileHandle)
+	info, err := fileHandle.Stat()
+	if err != nil {
+		fileHandle.Close()
+
+		return nil, fmt.Errorf("stat file: %w", err)
+	}
+
+	counter := &countingReader{reader: fileHandle}
+
+	reader, err := getFileReader(file, counter, opts.Password)
 	if err != nil {
 		fileHandle.Close()
 
@@ -54,6 +83,8 @@ func NewFileReader(file string, log zerolog.Logger) (*FileReader, error) {
 		fileContent: scanner,
 		log:         log,
 		closer:      fileHandle.Close,
+		file:        counter,
+		size:        info.Size(),
 	}, nil
 }
 
@@ -73,13 +104,32 @@ func validateFile(file string) error {
 	return nil
 }
 
-// getFileReader returns the appropriate reader based on file extension.
-func getFileReader(file string, fileHandle *os.File) (io.Reader, error) {
+// getFileReader returns the appropriate reader based on file extension, decrypting compressed recordings that are
+// encrypted.
+func getFileReader(file string, fileHandle io.Reader, password string) (io.Reader, error) {
 	fileExt := file[len(file)-3:]
 
 	switch fileExt {
 	case "gtz":
-		reader, err := gzip.NewReader(fileHandle)
+		buffered := bufio.NewReader(fileHandle)
+
+		var compressed io.Reader = buffered
+
+		prefix, _ := buffered.Peek(encryption.MagicSize)
+		if encryption.IsEncrypted(prefix) {
+			if password == "" {
+				return nil, ErrRecordingEncrypted
+			}
+
+			decrypted, err := encryption.NewReader(compressed, password)
+			if err != nil {
+				return nil, fmt.Errorf("decrypt recording: %w", err)
+			}
+
+			compressed = decrypted
+		}
+
+		reader, err := gzip.NewReader(compressed)
 		if err != nil {
 			return nil, fmt.Errorf("create gzip reader: %w", err)
 		}
@@ -164,6 +214,12 @@ func (r *FileReader) Read() (int, []byte, error) {
 	return len(packet), packet, nil
 }
 
+// Position returns the number of bytes of the file read so far and the size of the file. Reads are buffered, so the
+// position runs slightly ahead of the packets returned by Read.
+func (r *FileReader) Position() (int64, int64) {
+	return r.file.read.Load(), r.size
+}
+
 // Close closes the underlying file reader.
 func (r *FileReader) Close() error {
 	return nil
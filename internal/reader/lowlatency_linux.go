@@ -0,0 +1,86 @@
+//go:build linux && !tinygo
+
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+const (
+	// soBusyPoll is the Linux SO_BUSY_POLL socket option, which is not defined by the syscall package.
+	soBusyPoll = 0x2e
+	// nonBlockingReceiveSupported is true as low latency reads can spin on non-blocking receives on this platform.
+	nonBlockingReceiveSupported = true
+)
+
+// setBusyPoll enables busy polling of the device queue for blocking reads on the socket.
+func setBusyPoll(conn *net.UDPConn, microseconds int) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return fmt.Errorf("get raw connection: %w", err)
+	}
+
+	var sockErr error
+
+	err = rawConn.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soBusyPoll, microseconds)
+	})
+	if err != nil {
+		return fmt.Errorf("control raw connection: %w", err)
+	}
+
+	if sockErr != nil {
+		return fmt.Errorf("set SO_BUSY_POLL: %w", sockErr)
+	}
+
+	return nil
+}
+
+// receiveNonBlocking attempts to receive a packet without blocking, returning false if no packet was waiting.
+func receiveNonBlocking(conn *net.UDPConn, buffer []byte) (int, netip.AddrPort, bool, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return 0, netip.AddrPort{}, false, fmt.Errorf("get raw connection: %w", err)
+	}
+
+	var (
+		bufLen  int
+		from    syscall.Sockaddr
+		recvErr error
+	)
+
+	err = rawConn.Read(func(fd uintptr) bool {
+		bufLen, from, recvErr = syscall.Recvfrom(int(fd), buffer, syscall.MSG_DONTWAIT)
+
+		return true
+	})
+	if err != nil {
+		return 0, netip.AddrPort{}, false, fmt.Errorf("read raw connection: %w", err)
+	}
+
+	if errors.Is(recvErr, syscall.EAGAIN) || errors.Is(recvErr, syscall.EWOULDBLOCK) {
+		return 0, netip.AddrPort{}, false, nil
+	}
+
+	if recvErr != nil {
+		return 0, netip.AddrPort{}, false, fmt.Errorf("receive: %w", recvErr)
+	}
+
+	return bufLen, sockaddrAddrPort(from), true, nil
+}
+
+// sockaddrAddrPort converts the address a packet was received from, the zero address when it is not an IP address.
+func sockaddrAddrPort(from syscall.Sockaddr) netip.AddrPort {
+	switch from := from.(type) {
+	case *syscall.SockaddrInet4:
+		return netip.AddrPortFrom(netip.AddrFrom4(from.Addr), uint16(from.Port)) //nolint:gosec // ports fit in 16 bits
+	case *syscall.SockaddrInet6:
+		return netip.AddrPortFrom(netip.AddrFrom16(from.Addr), uint16(from.Port)) //nolint:gosec // ports fit in 16 bits
+	}
+
+	return netip.AddrPort{}
+}
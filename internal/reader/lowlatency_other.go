@@ -0,0 +1,25 @@
+//go:build !linux || tinygo
+
+package reader
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+)
+
+// nonBlockingReceiveSupported is false as non-blocking receives are not supported on this platform, so low latency
+// reads use the default blocking read.
+const nonBlockingReceiveSupported = false
+
+var errBusyPollUnsupported = errors.New("busy polling is only supported on Linux")
+
+// setBusyPoll is not supported on this platform.
+func setBusyPoll(_ *net.UDPConn, _ int) error {
+	return errBusyPollUnsupported
+}
+
+// receiveNonBlocking is not supported on this platform and always reports that no packet was waiting.
+func receiveNonBlocking(_ *net.UDPConn, _ []byte) (int, netip.AddrPort, bool, error) {
+	return 0, netip.AddrPort{}, false, nil
+}
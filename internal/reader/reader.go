@@ -4,11 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"time"
 
-	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
 	"github.com/zetetos/gt-telemetry/v2/pkg/models"
 )
 
@@ -25,6 +26,42 @@ type Reader interface {
 	Close() error
 }
 
+// FormatRequester is implemented by readers that can change the telemetry format requested from the source while
+// reading.
+type FormatRequester interface {
+	RequestFormat(format models.Name) error
+}
+
+// SourceReporter is implemented by readers that know the network address each packet was sent from.
+type SourceReporter interface {
+	// Source returns the address the last packet read was sent from.
+	Source() netip.AddrPort
+}
+
+// UDPOptions holds tuning options for UDP sources.
+type UDPOptions struct {
+	// LowLatency enables busy polling of the socket to reduce receive latency at the cost of CPU usage.
+	LowLatency bool
+	// DetectFormat deciphers packets sent in a format other than the one requested, so that the format the console
+	// is actually sending can be detected.
+	DetectFormat bool
+	// ReceiveBufferSize sets the size of the socket receive buffer in bytes, leave zero for the operating system
+	// default. The default buffer on Windows is small enough for bursts of packets to be dropped.
+	ReceiveBufferSize int
+	// ReusePort allows other applications to listen on the same port at the same time.
+	ReusePort bool
+	// Interface is the name or IP address of the network interface to listen on, leave empty to listen on all.
+	Interface string
+	// FanOut forwards every packet received from the console to other UDP consumers.
+	FanOut *FanOut
+}
+
+// FileOptions holds options for file sources.
+type FileOptions struct {
+	// Password decrypts encrypted recordings, and is ignored for recordings that are not encrypted.
+	Password string
+}
+
 // Config holds a constructed Reader along with source metadata.
 type Config struct {
 	Reader      Reader
@@ -33,7 +70,7 @@ type Config struct {
 }
 
 // New constructs a Reader and associated source metadata from a parsed source URL.
-func New(sourceURL *url.URL, format models.Name, log zerolog.Logger) (Config, error) {
+func New(sourceURL *url.URL, format models.Name, udpOpts UDPOptions, fileOpts FileOptions, log logging.Logger) (Config, error) {
 	switch sourceURL.Scheme {
 	case SchemeUDP:
 		host, portStr, _ := net.SplitHostPort(sourceURL.Host)
@@ -43,14 +80,14 @@ func New(sourceURL *url.URL, format models.Name, log zerolog.Logger) (Config, er
 			return Config{Recoverable: true}, fmt.Errorf("parse URL port: %w", err)
 		}
 
-		r, err := NewUDPReader(host, port, format, log)
+		r, err := NewUDPReader(host, port, format, udpOpts, log)
 		if err != nil {
 			return Config{Recoverable: true}, fmt.Errorf("setup UDP reader: %w", err)
 		}
 
 		return Config{Reader: r, Recoverable: true, Throttle: 0}, nil
 	case SchemeFile:
-		r, err := NewFileReader(sourceURL.Host+sourceURL.Path, log)
+		r, err := NewFileReader(sourceURL.Host+sourceURL.Path, fileOpts, log)
 		if err != nil {
 			return Config{}, fmt.Errorf("setup file reader: %w", err)
 		}
@@ -0,0 +1,69 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+var ErrNoInterfaceAddress = errors.New("network interface has no IPv4 address")
+
+// listenUDP opens the socket that telemetry is received on, applying the socket options.
+func listenUDP(port int, opts UDPOptions) (*net.UDPConn, error) {
+	host, err := bindAddress(opts.Interface)
+	if err != nil {
+		return nil, err
+	}
+
+	listenConfig := net.ListenConfig{}
+	if opts.ReusePort {
+		listenConfig.Control = reuseControl
+	}
+
+	packetConn, err := listenConfig.ListenPacket(context.Background(), "udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	conn := packetConn.(*net.UDPConn) //nolint:forcetypeassert // Always a UDP connection for the udp network
+
+	if opts.ReceiveBufferSize > 0 {
+		err = conn.SetReadBuffer(opts.ReceiveBufferSize)
+		if err != nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("set receive buffer size: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// bindAddress returns the address to bind to for an interface, which may be given as either an IP address or an
+// interface name. An empty interface binds to all interfaces.
+func bindAddress(iface string) (string, error) {
+	if iface == "" || net.ParseIP(iface) != nil {
+		return iface, nil
+	}
+
+	netInterface, err := net.InterfaceByName(iface)
+	if err != nil {
+		return "", fmt.Errorf("find network interface %q: %w", iface, err)
+	}
+
+	addrs, err := netInterface.Addrs()
+	if err != nil {
+		return "", fmt.Errorf("get addresses of network interface %q: %w", iface, err)
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if ok && ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrNoInterfaceAddress, iface)
+}
@@ -0,0 +1,15 @@
+//go:build (!unix && !windows) || tinygo
+
+package reader
+
+import (
+	"errors"
+	"syscall"
+)
+
+var errReusePortUnsupported = errors.New("port reuse is not supported on this platform")
+
+// reuseControl is not supported on this platform.
+func reuseControl(_, _ string, _ syscall.RawConn) error {
+	return errReusePortUnsupported
+}
@@ -0,0 +1,35 @@
+//go:build unix && !tinygo
+
+package reader
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// reuseControl allows other sockets to bind the same address and port, so that several tools can listen for
+// telemetry at the same time.
+func reuseControl(_, _ string, rawConn syscall.RawConn) error {
+	var sockErr error
+
+	err := rawConn.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1)
+		if sockErr != nil {
+			sockErr = fmt.Errorf("set SO_REUSEADDR: %w", sockErr)
+
+			return
+		}
+
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+		if sockErr != nil {
+			sockErr = fmt.Errorf("set SO_REUSEPORT: %w", sockErr)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("control raw connection: %w", err)
+	}
+
+	return sockErr
+}
@@ -0,0 +1,27 @@
+//go:build windows && !tinygo
+
+package reader
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// reuseControl allows other sockets to bind the same address and port, so that several tools can listen for
+// telemetry at the same time. Windows has no SO_REUSEPORT, SO_REUSEADDR alone allows the port to be shared.
+func reuseControl(_, _ string, rawConn syscall.RawConn) error {
+	var sockErr error
+
+	err := rawConn.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(syscall.Handle(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+	})
+	if err != nil {
+		return fmt.Errorf("control raw connection: %w", err)
+	}
+
+	if sockErr != nil {
+		return fmt.Errorf("set SO_REUSEADDR: %w", sockErr)
+	}
+
+	return nil
+}
@@ -4,60 +4,80 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
+	"runtime"
 	"sync"
 	"time"
 
-	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
 	"github.com/zetetos/gt-telemetry/v2/internal/salsa20"
 	"github.com/zetetos/gt-telemetry/v2/pkg/models"
 )
 
 const (
 	HeartbeatInterval = 10 * time.Second
+
+	// busyPollMicroseconds is the SO_BUSY_POLL duration set on the socket in low latency mode.
+	busyPollMicroseconds = 50
+	// spinDuration is how long a low latency read spins on non-blocking receives before blocking.
+	spinDuration = 20 * time.Millisecond
+	// receiveBufferSize is the size of the buffer used to receive a single packet.
+	receiveBufferSize = 4096
 )
 
 var (
 	ErrFailedToReceiveTelemetry  = errors.New("failed to receive telemetry")
 	ErrNoDataReceived            = errors.New("no data received")
 	ErrFailedToDecipherTelemetry = errors.New("failed to decipher telemetry")
+	ErrUnsupportedFormat         = errors.New("unsupported telemetry format")
 )
 
 type UDPReader struct {
-	conn       *net.UDPConn
-	address    string
-	sendPort   int
-	format     models.Name
-	ivSeed     uint32
-	closeFunc  func() error
-	stopTicker chan struct{}
-	closeOnce  sync.Once
-	log        zerolog.Logger
+	conn         *net.UDPConn
+	address      string
+	sendPort     int
+	formatMu     sync.RWMutex
+	format       models.Name
+	ivSeed       uint32
+	closeFunc    func() error
+	stopTicker   chan struct{}
+	closeOnce    sync.Once
+	lowLatency   bool
+	detectFormat bool
+	fanOut       *FanOut
+	source       netip.AddrPort
+	log          logging.Logger
 }
 
-func NewUDPReader(host string, sendPort int, format models.Name, log zerolog.Logger) (*UDPReader, error) {
+func NewUDPReader(host string, sendPort int, format models.Name, opts UDPOptions, log logging.Logger) (*UDPReader, error) {
 	log.Debug().Msg("creating UDP reader")
 
 	receivePort := sendPort + 1
 
-	addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf(":%d", receivePort))
+	conn, err := listenUDP(receivePort, opts)
 	if err != nil {
-		return nil, fmt.Errorf("resolve UDP address: %w", err)
+		return nil, fmt.Errorf("setup UDP listener %d: %w", receivePort, err)
 	}
 
-	conn, err := net.ListenUDP("udp", addr)
-	if err != nil {
-		return nil, fmt.Errorf("setup UDP listener %d: %w", receivePort, err)
+	reader := &UDPReader{
+		conn:         conn,
+		address:      host,
+		sendPort:     sendPort,
+		format:       format,
+		ivSeed:       getIVSeedForFormat(format),
+		closeFunc:    conn.Close,
+		stopTicker:   make(chan struct{}),
+		lowLatency:   opts.LowLatency,
+		detectFormat: opts.DetectFormat,
+		fanOut:       opts.FanOut,
+		log:          log,
 	}
 
-	reader := UDPReader{
-		conn:       conn,
-		address:    host,
-		sendPort:   sendPort,
-		format:     format,
-		ivSeed:     getIVSeedForFormat(format),
-		closeFunc:  conn.Close,
-		stopTicker: make(chan struct{}),
-		log:        log,
+	if opts.LowLatency {
+		err = setBusyPoll(conn, busyPollMicroseconds)
+		if err != nil {
+			log.Warn().Err(err).Msg("busy polling unavailable, low latency mode will spin on non-blocking reads only")
+		}
 	}
 
 	ticker := time.NewTicker(HeartbeatInterval)
@@ -87,13 +107,24 @@ func NewUDPReader(host string, sendPort int, format models.Name, log zerolog.Log
 		}
 	}()
 
-	return &reader, nil
+	return reader, nil
 }
 
 func (r *UDPReader) Read() (int, []byte, error) {
-	buffer := make([]byte, 4096)
+	buffer := make([]byte, receiveBufferSize)
+
+	var (
+		bufLen int
+		source netip.AddrPort
+		err    error
+	)
+
+	if r.lowLatency && nonBlockingReceiveSupported {
+		bufLen, source, err = r.spinRead(buffer)
+	} else {
+		bufLen, source, err = r.conn.ReadFromUDPAddrPort(buffer)
+	}
 
-	bufLen, _, err := r.conn.ReadFromUDP(buffer)
 	if err != nil {
 		return 0, buffer, fmt.Errorf("%w: %s", ErrFailedToReceiveTelemetry, err.Error())
 	}
@@ -102,7 +133,21 @@ func (r *UDPReader) Read() (int, []byte, error) {
 		return 0, buffer, ErrNoDataReceived
 	}
 
-	decipheredPacket, err := salsa20.Decode(r.ivSeed, buffer[:bufLen])
+	r.source = netip.AddrPortFrom(source.Addr().Unmap(), source.Port())
+
+	if r.fanOut != nil {
+		r.fanOut.Send(buffer[:bufLen])
+	}
+
+	r.formatMu.RLock()
+	ivSeed := r.ivSeed
+	r.formatMu.RUnlock()
+
+	decipheredPacket, err := salsa20.Decode(ivSeed, buffer[:bufLen])
+	if err != nil && r.detectFormat {
+		decipheredPacket, err = decodeWithAnySeed(buffer[:bufLen])
+	}
+
 	if err != nil {
 		return 0, buffer, fmt.Errorf("%w: %s", ErrFailedToDecipherTelemetry, err.Error())
 	}
@@ -110,6 +155,66 @@ func (r *UDPReader) Read() (int, []byte, error) {
 	return bufLen, decipheredPacket, nil
 }
 
+// decodeWithAnySeed attempts to decipher a packet with the IV seed of each known format.
+func decodeWithAnySeed(packet []byte) ([]byte, error) {
+	var err error
+
+	for _, format := range []models.Name{models.Addendum3, models.Addendum2, models.Addendum1, models.Standard} {
+		var decipheredPacket []byte
+
+		decipheredPacket, err = salsa20.Decode(getIVSeedForFormat(format), packet)
+		if err == nil {
+			return decipheredPacket, nil
+		}
+	}
+
+	return nil, err
+}
+
+// spinRead repeatedly attempts non-blocking receives for a short time so that a packet is picked up as soon as it
+// arrives without waiting for the runtime network poller, then falls back to a blocking read.
+func (r *UDPReader) spinRead(buffer []byte) (int, netip.AddrPort, error) {
+	deadline := time.Now().Add(spinDuration)
+
+	for time.Now().Before(deadline) {
+		bufLen, source, received, err := receiveNonBlocking(r.conn, buffer)
+		if err != nil {
+			return 0, netip.AddrPort{}, err
+		}
+
+		if received {
+			return bufLen, source, nil
+		}
+
+		// Let other goroutines run between attempts, as the sender may share the processor
+		runtime.Gosched()
+	}
+
+	return r.conn.ReadFromUDPAddrPort(buffer)
+}
+
+// Source returns the address the last packet read was sent from, such as the console, for telling apart consoles
+// and spotting unexpected senders on the port.
+func (r *UDPReader) Source() netip.AddrPort {
+	return r.source
+}
+
+// RequestFormat switches the format requested from the console with each heartbeat. A heartbeat for the new format is
+// sent immediately, packets already in flight in the previous format may fail to decipher.
+func (r *UDPReader) RequestFormat(format models.Name) error {
+	ivSeed := getIVSeedForFormat(format)
+	if ivSeed == 0 {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	r.formatMu.Lock()
+	r.format = format
+	r.ivSeed = ivSeed
+	r.formatMu.Unlock()
+
+	return r.sendHeartbeat()
+}
+
 func (r *UDPReader) Close() error {
 	var closeErr error
 
@@ -129,9 +234,13 @@ func (r *UDPReader) Close() error {
 }
 
 func (r *UDPReader) sendHeartbeat() error {
-	r.log.Debug().Msgf("sending format %q heartbeat to %s:%d", r.format, r.address, r.sendPort)
+	r.formatMu.RLock()
+	format := r.format
+	r.formatMu.RUnlock()
+
+	r.log.Debug().Msgf("sending format %q heartbeat to %s:%d", format, r.address, r.sendPort)
 
-	_, err := r.conn.WriteToUDP([]byte(r.format), &net.UDPAddr{
+	_, err := r.conn.WriteToUDP([]byte(format), &net.UDPAddr{
 		IP:   net.ParseIP(r.address),
 		Port: r.sendPort,
 	})
@@ -0,0 +1,292 @@
+package reader_test
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/internal/reader"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"golang.org/x/crypto/salsa20"
+)
+
+const (
+	packetSize = 296
+	magic      = 0x47375330
+	ivSeed     = 0xDEADBEEF
+)
+
+type UDPReaderTestSuite struct {
+	suite.Suite
+}
+
+func TestUDPReaderTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(UDPReaderTestSuite))
+}
+
+func (suite *UDPReaderTestSuite) TestReadReturnsDecipheredPacket() {
+	for _, lowLatency := range []bool{false, true} {
+		// Arrange
+		udpReader, sender := newLoopbackReader(suite.T(), lowLatency)
+
+		// Act
+		_, err := sender.Write(encryptedPacket(1))
+		suite.Require().NoError(err)
+
+		bufLen, packet, err := udpReader.Read()
+
+		// Assert
+		suite.Require().NoError(err)
+		suite.Equal(packetSize, bufLen)
+		suite.Equal(uint32(magic), binary.LittleEndian.Uint32(packet[0:4]))
+		suite.Equal(uint32(1), binary.LittleEndian.Uint32(packet[0x70:0x74]))
+	}
+}
+
+func (suite *UDPReaderTestSuite) TestSourceReturnsAddressOfSender() {
+	for _, lowLatency := range []bool{false, true} {
+		// Arrange
+		udpReader, sender := newLoopbackReader(suite.T(), lowLatency)
+
+		// Act
+		_, err := sender.Write(encryptedPacket(1))
+		suite.Require().NoError(err)
+
+		_, _, err = udpReader.Read()
+		suite.Require().NoError(err)
+
+		// Assert
+		suite.Equal(sender.LocalAddr().(*net.UDPAddr).AddrPort(), udpReader.Source()) //nolint:forcetypeassert // Always a UDP address
+	}
+}
+
+func (suite *UDPReaderTestSuite) TestReadReturnsErrorAfterClose() {
+	for _, lowLatency := range []bool{false, true} {
+		// Arrange
+		udpReader, _ := newLoopbackReader(suite.T(), lowLatency)
+
+		// Act
+		suite.Require().NoError(udpReader.Close())
+		_, _, err := udpReader.Read()
+
+		// Assert
+		suite.ErrorIs(err, reader.ErrFailedToReceiveTelemetry)
+	}
+}
+
+func (suite *UDPReaderTestSuite) TestRequestFormatSendsHeartbeatForNewFormat() {
+	// Arrange
+	console, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+
+	defer console.Close()
+
+	consolePort := console.LocalAddr().(*net.UDPAddr).Port //nolint:forcetypeassert // Always a UDP address
+
+	udpReader, err := reader.NewUDPReader("127.0.0.1", consolePort, models.Standard, reader.UDPOptions{}, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	defer udpReader.Close()
+
+	heartbeat := make([]byte, 16)
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(time.Second)))
+
+	bufLen, _, err := console.ReadFromUDP(heartbeat)
+	suite.Require().NoError(err)
+	suite.Equal("A", string(heartbeat[:bufLen]))
+
+	// Act
+	err = udpReader.RequestFormat(models.Addendum2)
+	suite.Require().NoError(err)
+
+	bufLen, _, err = console.ReadFromUDP(heartbeat)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("~", string(heartbeat[:bufLen]))
+}
+
+func (suite *UDPReaderTestSuite) TestRequestFormatDeciphersPacketsWithNewFormat() {
+	// Arrange
+	udpReader, sender := newLoopbackReader(suite.T(), false)
+	suite.Require().NoError(udpReader.RequestFormat(models.Standard))
+
+	// Act
+	_, err := sender.Write(encryptedPacketWithSeed(1, 0xDEADBEAF))
+	suite.Require().NoError(err)
+
+	_, packet, err := udpReader.Read()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(uint32(magic), binary.LittleEndian.Uint32(packet[0:4]))
+}
+
+func (suite *UDPReaderTestSuite) TestRequestFormatReturnsErrorForUnknownFormat() {
+	// Arrange
+	udpReader, _ := newLoopbackReader(suite.T(), false)
+
+	// Act
+	err := udpReader.RequestFormat(models.Unknown)
+
+	// Assert
+	suite.ErrorIs(err, reader.ErrUnsupportedFormat)
+}
+
+func (suite *UDPReaderTestSuite) TestReusePortAllowsPortToBeShared() {
+	// Arrange
+	receivePort := freeLoopbackPort(suite.T())
+	opts := reader.UDPOptions{ReusePort: true, Interface: "127.0.0.1", ReceiveBufferSize: 1 << 20}
+
+	first, err := reader.NewUDPReader("127.0.0.1", receivePort-1, models.Addendum3, opts, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	defer first.Close()
+
+	// Act
+	second, err := reader.NewUDPReader("127.0.0.1", receivePort-1, models.Addendum3, opts, zerolog.Nop())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NoError(second.Close())
+}
+
+func (suite *UDPReaderTestSuite) TestPortCannotBeSharedWithoutReusePort() {
+	// Arrange
+	receivePort := freeLoopbackPort(suite.T())
+	opts := reader.UDPOptions{Interface: "127.0.0.1"}
+
+	first, err := reader.NewUDPReader("127.0.0.1", receivePort-1, models.Addendum3, opts, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	defer first.Close()
+
+	// Act
+	_, err = reader.NewUDPReader("127.0.0.1", receivePort-1, models.Addendum3, opts, zerolog.Nop())
+
+	// Assert
+	suite.Error(err)
+}
+
+func (suite *UDPReaderTestSuite) TestUnknownInterfaceReturnsError() {
+	// Act
+	_, err := reader.NewUDPReader("127.0.0.1", 33739, models.Addendum3, reader.UDPOptions{Interface: "no-such-interface"}, zerolog.Nop())
+
+	// Assert
+	suite.Error(err)
+}
+
+// BenchmarkUDPReaderLatency measures the time from a packet being sent on the loopback interface to it being returned
+// deciphered by Read, with and without low latency mode. Each packet is sent while the reader is already waiting so
+// that the wake up cost is included, as it would be for a live stream.
+func BenchmarkUDPReaderLatency(b *testing.B) {
+	for _, bench := range []struct {
+		name       string
+		lowLatency bool
+	}{
+		{name: "default", lowLatency: false},
+		{name: "lowLatency", lowLatency: true},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			udpReader, sender := newLoopbackReader(b, bench.lowLatency)
+			packet := encryptedPacket(1)
+
+			ready := make(chan struct{})
+			sent := make(chan time.Time, 1)
+
+			go func() {
+				for range ready {
+					time.Sleep(100 * time.Microsecond)
+
+					sent <- time.Now()
+
+					_, _ = sender.Write(packet)
+				}
+			}()
+
+			defer close(ready)
+
+			var latency time.Duration
+
+			for b.Loop() {
+				ready <- struct{}{}
+
+				_, _, err := udpReader.Read()
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				latency += time.Since(<-sent)
+			}
+
+			b.ReportMetric(float64(latency.Nanoseconds())/float64(b.N), "ns/packet")
+		})
+	}
+}
+
+// newLoopbackReader creates a UDP reader on a free loopback port along with a connection that sends to it.
+func newLoopbackReader(tb testing.TB, lowLatency bool) (*reader.UDPReader, *net.UDPConn) {
+	tb.Helper()
+
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	receivePort := probe.LocalAddr().(*net.UDPAddr).Port //nolint:forcetypeassert // Always a UDP address
+
+	err = probe.Close()
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	udpReader, err := reader.NewUDPReader("127.0.0.1", receivePort-1, models.Addendum3, reader.UDPOptions{LowLatency: lowLatency}, zerolog.Nop())
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() { _ = udpReader.Close() })
+
+	sender, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: receivePort})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() { _ = sender.Close() })
+
+	return udpReader, sender
+}
+
+// encryptedPacket builds a minimal packet with the given sequence ID, encrypted as the game would send it.
+func encryptedPacket(sequenceID uint32) []byte {
+	return encryptedPacketWithSeed(sequenceID, ivSeed)
+}
+
+// encryptedPacketWithSeed builds a minimal packet with the given sequence ID, encrypted with the IV seed of a format.
+func encryptedPacketWithSeed(sequenceID uint32, seed uint32) []byte {
+	plain := make([]byte, packetSize)
+	binary.LittleEndian.PutUint32(plain[0:4], magic)
+	binary.LittleEndian.PutUint32(plain[0x70:0x74], sequenceID)
+
+	iv := uint32(0x12345678)
+
+	key := [32]byte{}
+	copy(key[:], "Simulator Interface Packet GT7 ver 0.0")
+
+	nonce := make([]byte, 8)
+	binary.LittleEndian.PutUint32(nonce, iv^seed)
+	binary.LittleEndian.PutUint32(nonce[4:], iv)
+
+	encrypted := make([]byte, packetSize)
+	salsa20.XORKeyStream(encrypted, plain, nonce, &key)
+
+	// The IV is transmitted in the clear
+	binary.LittleEndian.PutUint32(encrypted[0x40:0x44], iv)
+
+	return encrypted
+}
@@ -0,0 +1,416 @@
+// Package schemagen generates the JSON Schemas of the JSON documents the module publishes from the Go types that
+// encode them, so that the schemas published for integrators cannot drift from the documents. Descriptions are
+// taken from the doc comments of the types and their fields.
+package schemagen
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/livetiming"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+const (
+	// draft is the JSON Schema draft of the generated schemas, the draft of the circuit capture schema.
+	draft = "https://json-schema.org/draft-07/schema#"
+	// idBase is the base of the $id of each schema, followed by the file name of the schema.
+	idBase = "https://github.com/zetetos/gt-telemetry/pkg/schemas/"
+)
+
+var (
+	// ErrUnsupportedType indicates a Go type that has no JSON Schema equivalent, or encodes itself.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrDuplicateDefinition indicates two types of the same name in different packages within a schema.
+	ErrDuplicateDefinition = errors.New("duplicate definition")
+)
+
+// Document is a JSON document with a published schema.
+type Document struct {
+	// Name is the file name of the schema without the .json extension, e.g. "frame-snapshot".
+	Name  string
+	Title string
+	// Value is a value of the Go type the document is encoded from.
+	Value any
+}
+
+// RacingLineDocument is a racing line as written by RacingLine.WriteJSON, which encodes the lap time in milliseconds.
+type RacingLineDocument struct {
+	gttelemetry.RacingLine
+
+	// LaptimeMilliseconds is the lap time in milliseconds.
+	LaptimeMilliseconds int64 `json:"laptimeMilliseconds"`
+}
+
+// OverlayKeyframesDocument is an overlay track as written by OverlayTrack.WriteJSON, with times in seconds.
+type OverlayKeyframesDocument struct {
+	// Interval is the minimum time between keyframes in seconds.
+	Interval  float64           `json:"interval"`
+	Keyframes []OverlayKeyframe `json:"keyframes"`
+}
+
+// OverlayKeyframe is the state of the vehicle shown by an overlay from a point in the video.
+type OverlayKeyframe struct {
+	// Time is the time of the keyframe from the start of the video in seconds.
+	Time           float64 `json:"time"`
+	Lap            int16   `json:"lap"`
+	LaptimeSeconds float64 `json:"laptimeSeconds"`
+	SpeedKPH       float32 `json:"speedKPH"`
+	Gear           string  `json:"gear"`
+}
+
+// Documents lists the JSON documents the schemas are published for, in the order they are listed. Documents that
+// are encoded from unexported types are described by the mirrors of those types above.
+func Documents() []Document {
+	return []Document{
+		{Name: "frame-snapshot", Title: "Frame snapshot", Value: models.FrameSnapshot{}},
+		{Name: "lap-comparison", Title: "Lap comparison", Value: gttelemetry.LapComparison{}},
+		{Name: "racing-line", Title: "Racing line", Value: RacingLineDocument{}},
+		{Name: "overlay-keyframes", Title: "Overlay keyframes", Value: OverlayKeyframesDocument{}},
+		{Name: "timeline", Title: "Session timeline", Value: []gttelemetry.TimelineEvent{}},
+		{Name: "packet-drops", Title: "Packet drop summary", Value: gttelemetry.DropSummary{}},
+		{Name: "live-timing-standings", Title: "Live timing standings", Value: []livetiming.Entry{}},
+		{Name: "circuit", Title: "Circuit inventory entry", Value: circuits.CircuitInfo{}},
+		{Name: "circuit-manifest", Title: "Circuit inventory manifest", Value: circuits.Manifest{}},
+		{Name: "vehicle", Title: "Vehicle inventory entry", Value: vehicles.Vehicle{}},
+		{Name: "vehicle-inventory", Title: "Vehicle inventory", Value: vehicles.VehicleInventory{}},
+		{Name: "vehicle-manifest", Title: "Vehicle inventory manifest", Value: vehicles.Manifest{}},
+	}
+}
+
+// Generate returns the schema of the document, with the doc comments read from the source of the module rooted at dir.
+func Generate(dir, modulePath string, document Document) ([]byte, error) {
+	g := &generator{
+		dir:         dir,
+		modulePath:  modulePath,
+		docs:        map[string]map[string]typeDoc{},
+		definitions: map[string]any{},
+		defined:     map[string]reflect.Type{},
+	}
+
+	root := reflect.TypeOf(document.Value)
+
+	schema, err := g.schema(root, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", document.Name, err)
+	}
+
+	schema["$schema"] = draft
+	schema["$id"] = idBase + document.Name + ".json"
+	schema["title"] = document.Title
+
+	if doc, err := g.typeDoc(root); err != nil {
+		return nil, fmt.Errorf("%s: %w", document.Name, err)
+	} else if doc.doc != "" {
+		schema["description"] = doc.doc
+	}
+
+	if len(g.definitions) > 0 {
+		schema["definitions"] = g.definitions
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("%s: encoding schema: %w", document.Name, err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// typeDoc is the doc comment of a type and of each of its fields.
+type typeDoc struct {
+	doc    string
+	fields map[string]string
+}
+
+type generator struct {
+	dir        string
+	modulePath string
+	// docs holds the doc comments of the types of each package read so far, keyed by package path and type name
+	docs        map[string]map[string]typeDoc
+	definitions map[string]any
+	defined     map[string]reflect.Type
+}
+
+// schema returns the schema of a type. Named structs other than the root are added to the definitions and
+// referenced, so that each is described once.
+func (g *generator) schema(t reflect.Type, root bool) (map[string]any, error) {
+	switch {
+	case t == reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case t == reflect.TypeFor[time.Duration]():
+		return map[string]any{"type": "integer", "description": "Duration in nanoseconds"}, nil
+	case encodesItself(t) || encodesItself(reflect.PointerTo(t)):
+		return nil, fmt.Errorf("%w: %s encodes itself", ErrUnsupportedType, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		bits := t.Bits() - 1
+
+		return map[string]any{"type": "integer", "minimum": -(int64(1) << bits), "maximum": int64(1)<<bits - 1}, nil
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer", "minimum": 0, "maximum": uint64(math.MaxUint64) >> (64 - t.Bits())}, nil
+	case reflect.Uint, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Pointer:
+		return g.schema(t.Elem(), false)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+		}
+
+		items, err := g.schema(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: %s has keys that are not strings", ErrUnsupportedType, t)
+		}
+
+		values, err := g.schema(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		if root || t.Name() == "" {
+			return g.object(t)
+		}
+
+		return g.reference(t)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+// encodesItself reports whether encoding/json leaves the encoding of a type to the type, so its schema cannot be
+// derived from its fields.
+func encodesItself(t reflect.Type) bool {
+	return t.Implements(reflect.TypeFor[json.Marshaler]()) || t.Implements(reflect.TypeFor[encoding.TextMarshaler]())
+}
+
+// reference adds a named struct to the definitions and returns a reference to it.
+func (g *generator) reference(t reflect.Type) (map[string]any, error) {
+	name := t.Name()
+	ref := map[string]any{"$ref": "#/definitions/" + name}
+
+	if defined, ok := g.defined[name]; ok {
+		if defined != t {
+			return nil, fmt.Errorf("%w: %s and %s", ErrDuplicateDefinition, defined, t)
+		}
+
+		return ref, nil
+	}
+
+	g.defined[name] = t
+
+	schema, err := g.object(t)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := g.typeDoc(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.doc != "" {
+		schema["description"] = doc.doc
+	}
+
+	g.definitions[name] = schema
+
+	return ref, nil
+}
+
+// object returns the schema of a struct, with the fields encoding/json writes as its properties. Fields without
+// omitempty or omitzero are always written, so are required.
+func (g *generator) object(t reflect.Type) (map[string]any, error) {
+	properties := map[string]any{}
+	required := []string{}
+
+	err := g.addFields(t, properties, &required)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+func (g *generator) addFields(t reflect.Type, properties map[string]any, required *[]string) error {
+	doc, err := g.typeDoc(t)
+	if err != nil {
+		return err
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			err = g.addFields(field.Type, properties, required)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema, err := g.schema(field.Type, false)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		if description := doc.fields[field.Name]; description != "" {
+			if _, ok := schema["$ref"]; ok {
+				// Keywords alongside $ref are ignored in draft 7, so the reference is wrapped to keep the description
+				schema = map[string]any{"allOf": []any{schema}}
+			}
+
+			schema["description"] = description
+		}
+
+		properties[name] = schema
+
+		if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+
+	return nil
+}
+
+// typeDoc returns the doc comments of a named type declared in the module, or none for other types.
+func (g *generator) typeDoc(t reflect.Type) (typeDoc, error) {
+	pkgPath := t.PkgPath()
+	if t.Name() == "" || (pkgPath != g.modulePath && !strings.HasPrefix(pkgPath, g.modulePath+"/")) {
+		return typeDoc{}, nil
+	}
+
+	docs, ok := g.docs[pkgPath]
+	if !ok {
+		var err error
+
+		docs, err = readDocs(filepath.Join(g.dir, filepath.FromSlash(strings.TrimPrefix(pkgPath, g.modulePath))))
+		if err != nil {
+			return typeDoc{}, err
+		}
+
+		g.docs[pkgPath] = docs
+	}
+
+	return docs[t.Name()], nil
+}
+
+// readDocs reads the doc comments of the types declared in the package in dir.
+func readDocs(dir string) (map[string]typeDoc, error) {
+	pkg, err := build.Default.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("reading package %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	docs := map[string]typeDoc{}
+
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				typeSpec, _ := spec.(*ast.TypeSpec)
+				docs[typeSpec.Name.Name] = specDoc(gen, typeSpec)
+			}
+		}
+	}
+
+	return docs, nil
+}
+
+// specDoc returns the doc comments of a type declaration and its fields.
+func specDoc(gen *ast.GenDecl, typeSpec *ast.TypeSpec) typeDoc {
+	doc := typeDoc{doc: commentText(typeSpec.Doc), fields: map[string]string{}}
+	if doc.doc == "" && len(gen.Specs) == 1 {
+		doc.doc = commentText(gen.Doc)
+	}
+
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return doc
+	}
+
+	for _, field := range structType.Fields.List {
+		text := commentText(field.Doc)
+		if text == "" {
+			text = commentText(field.Comment)
+		}
+
+		for _, name := range field.Names {
+			doc.fields[name.Name] = text
+		}
+	}
+
+	return doc
+}
+
+// commentText returns a comment as a single line of text, with the first letter capitalised.
+func commentText(group *ast.CommentGroup) string {
+	text := strings.Join(strings.Fields(group.Text()), " ")
+	if text == "" {
+		return ""
+	}
+
+	return strings.ToUpper(text[:1]) + text[1:]
+}
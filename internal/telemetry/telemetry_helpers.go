@@ -194,3 +194,20 @@ func (this *GranTurismoTelemetry) SetTransmissionGearRatio(gears []float32) { //
 		Gear: gears,
 	}
 }
+
+// ResolveInstances computes the instances of the frame that are otherwise computed and stored on first use, so that a
+// copy of the frame can be read from several goroutines without writing to it.
+func (this *GranTurismoTelemetry) ResolveInstances() { //nolint:revive,staticcheck // matches kaitai generated code style
+	if this.Header != nil {
+		_, _ = this.HeaderIsGt6()
+		_, _ = this.HeaderIsGt7()
+	}
+
+	if this._io != nil {
+		_, _ = this.PacketSize()
+		_, _ = this.StandardFormat()
+		_, _ = this.Addendum1Format()
+		_, _ = this.Addendum2Format()
+		_, _ = this.Addendum3Format()
+	}
+}
@@ -0,0 +1,268 @@
+package gttelemetry
+
+import (
+	"math"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// DefaultInterpolationRate is the output rate of an Interpolator, in frames per second, when none is configured.
+const DefaultInterpolationRate = 120
+
+// rotationPeriod is the range the rotation components wrap around in, as each runs from -1 to 1.
+const rotationPeriod = 2
+
+// maxInterpolationGap is the largest jump in sequence IDs, in packets, that is interpolated across. Larger jumps,
+// such as after pausing or seeking a replay, and jumps backwards restart the interpolation from the new frame.
+const maxInterpolationGap = 6
+
+// InterpolatedFrame is a frame produced by an Interpolator. Time is measured from the first frame pushed to the
+// interpolator, advancing by a single packet interval across a jump in sequence IDs, and Channels holds the
+// interpolated value of each configured channel.
+type InterpolatedFrame struct {
+	Time     time.Duration
+	Position models.Coordinate
+	Velocity models.Vector
+	Rotation models.RotationalEnvelope
+	Channels map[string]float32
+}
+
+// InterpolatorOptions configures an Interpolator.
+type InterpolatorOptions struct {
+	// Rate is the output rate in frames per second, defaults to 120.
+	Rate int
+	// Channels lists the names of the numeric channels to interpolate, see ChannelNames.
+	Channels []string
+	// Extrapolate projects the position forward from the latest frame using its velocity when sampling in real
+	// time, instead of interpolating between the two latest frames. This avoids the one frame delay of
+	// interpolation at the cost of overshoot on sudden changes, and suits motion systems. The rotation is projected
+	// forward at the rate it changed over the two latest frames, as the angular velocity is measured about the axes
+	// of the car rather than of the rotation components.
+	Extrapolate bool
+	// Now returns the current time when sampling in real time, defaults to time.Now.
+	Now func() time.Time
+}
+
+type interpolationSample struct {
+	frame      InterpolatedFrame
+	receivedAt time.Time
+}
+
+// Interpolator produces intermediate frames between the 60Hz telemetry packets for high refresh rate overlays and
+// motion systems. Position, velocity and rotation are always interpolated along with any configured channels.
+type Interpolator struct {
+	rate          int
+	channels      []string
+	extrapolate   bool
+	now           func() time.Time
+	firstSequence uint32
+	lastSequence  uint32
+	offsetFrames  uint64
+	previous      *interpolationSample
+	current       *interpolationSample
+}
+
+// NewInterpolator creates a new interpolator.
+func NewInterpolator(opts InterpolatorOptions) *Interpolator {
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultInterpolationRate
+	}
+
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &Interpolator{
+		rate:        opts.Rate,
+		channels:    opts.Channels,
+		extrapolate: opts.Extrapolate,
+		now:         opts.Now,
+	}
+}
+
+// Push adds the latest telemetry frame and returns the frames at the output rate covering the time since the
+// previous frame, ending with the new frame. Frame times are derived from packet sequence IDs, so the output is
+// the same when processing a replay file at any speed. When the sequence IDs jump forwards by more than a few
+// packets or go backwards, only the new frame is returned.
+func (i *Interpolator) Push(t *Transformer) []InterpolatedFrame {
+	sequenceID := t.SequenceID()
+	if i.current != nil && sequenceID == i.lastSequence {
+		return nil
+	}
+
+	previous := i.current
+
+	switch {
+	case i.current == nil:
+		i.firstSequence = sequenceID
+	case sequenceID-i.lastSequence > maxInterpolationGap:
+		// Jumps backwards wrap around to large gaps. Times carry on from the previous frame, offset by one packet.
+		i.offsetFrames += uint64(i.lastSequence-i.firstSequence) + 1
+		i.firstSequence = sequenceID
+		previous = nil
+	}
+
+	i.lastSequence = sequenceID
+	i.previous = previous
+	i.current = &interpolationSample{
+		frame:      i.capture(t, framesDuration(i.offsetFrames+uint64(sequenceID-i.firstSequence))),
+		receivedAt: i.now(),
+	}
+
+	if i.previous == nil {
+		return []InterpolatedFrame{i.current.frame}
+	}
+
+	start, end := i.previous.frame.Time, i.current.frame.Time
+	frames := []InterpolatedFrame{}
+
+	for index := outputIndexAfter(start, i.rate); ; index++ {
+		at := time.Duration(index) * time.Second / time.Duration(i.rate)
+		if at >= end {
+			break
+		}
+
+		frame := lerpFrame(i.previous.frame, i.current.frame, float32(at-start)/float32(end-start))
+		frame.Time = at
+		frames = append(frames, frame)
+	}
+
+	return append(frames, i.current.frame)
+}
+
+// Sample returns a frame for the current time, for rendering in real time at any refresh rate. Without
+// extrapolation the frame is interpolated between the two latest frames, delayed by one packet interval.
+func (i *Interpolator) Sample() InterpolatedFrame {
+	if i.current == nil {
+		return InterpolatedFrame{}
+	}
+
+	since := i.now().Sub(i.current.receivedAt)
+
+	if i.extrapolate || i.previous == nil {
+		frame := i.current.frame
+		frame.Time += since
+		frame.Position = models.Coordinate{
+			X: frame.Position.X + frame.Velocity.X*float32(since.Seconds()),
+			Y: frame.Position.Y + frame.Velocity.Y*float32(since.Seconds()),
+			Z: frame.Position.Z + frame.Velocity.Z*float32(since.Seconds()),
+		}
+
+		if i.previous != nil {
+			frame.Rotation = extrapolateRotation(i.previous.frame, i.current.frame, since)
+		}
+
+		return frame
+	}
+
+	interval := i.current.receivedAt.Sub(i.previous.receivedAt)
+	if interval <= 0 {
+		return i.current.frame
+	}
+
+	fraction := min(float32(since)/float32(interval), 1)
+
+	return lerpFrame(i.previous.frame, i.current.frame, fraction)
+}
+
+func (i *Interpolator) capture(t *Transformer, at time.Duration) InterpolatedFrame {
+	channelValues := make(map[string]float32, len(i.channels))
+
+	for _, name := range i.channels {
+		if value, ok := t.Channel(name); ok {
+			channelValues[name] = value
+		}
+	}
+
+	return InterpolatedFrame{
+		Time:     at,
+		Position: t.PositionalMapCoordinates(),
+		Velocity: t.VelocityVector(),
+		Rotation: t.RotationEnvelope(),
+		Channels: channelValues,
+	}
+}
+
+// outputIndexAfter returns the index of the first output frame after start for the given output rate.
+func outputIndexAfter(start time.Duration, rate int) int64 {
+	index := int64(start)*int64(rate)/int64(time.Second) + 1
+
+	// Frame times are rounded down to the nanosecond, so the output frame at the same instant as start may not be
+	// counted by the division, as with 1/30s at 240 frames per second.
+	if time.Duration(index)*time.Second/time.Duration(rate) <= start {
+		index++
+	}
+
+	return index
+}
+
+func lerpFrame(a, b InterpolatedFrame, fraction float32) InterpolatedFrame {
+	lerp := func(from, to float32) float32 {
+		return from + (to-from)*fraction
+	}
+
+	channelValues := make(map[string]float32, len(b.Channels))
+	for name, to := range b.Channels {
+		channelValues[name] = lerp(a.Channels[name], to)
+	}
+
+	return InterpolatedFrame{
+		Time:     a.Time + time.Duration(float32(b.Time-a.Time)*fraction),
+		Position: lerpCoordinate(a.Position, b.Position, fraction),
+		Velocity: models.Vector{
+			X: lerp(a.Velocity.X, b.Velocity.X),
+			Y: lerp(a.Velocity.Y, b.Velocity.Y),
+			Z: lerp(a.Velocity.Z, b.Velocity.Z),
+		},
+		Rotation: models.RotationalEnvelope{
+			Pitch: wrapRotation(a.Rotation.Pitch + rotationDelta(a.Rotation.Pitch, b.Rotation.Pitch)*fraction),
+			Yaw:   wrapRotation(a.Rotation.Yaw + rotationDelta(a.Rotation.Yaw, b.Rotation.Yaw)*fraction),
+			Roll:  wrapRotation(a.Rotation.Roll + rotationDelta(a.Rotation.Roll, b.Rotation.Roll)*fraction),
+		},
+		Channels: channelValues,
+	}
+}
+
+// extrapolateRotation projects the rotation of the latest frame forward by since at the rate it changed from the
+// frame before.
+func extrapolateRotation(previous, current InterpolatedFrame, since time.Duration) models.RotationalEnvelope {
+	interval := current.Time - previous.Time
+	if interval <= 0 {
+		return current.Rotation
+	}
+
+	scale := float32(since) / float32(interval)
+	from, to := previous.Rotation, current.Rotation
+
+	return models.RotationalEnvelope{
+		Pitch: wrapRotation(to.Pitch + rotationDelta(from.Pitch, to.Pitch)*scale),
+		Yaw:   wrapRotation(to.Yaw + rotationDelta(from.Yaw, to.Yaw)*scale),
+		Roll:  wrapRotation(to.Roll + rotationDelta(from.Roll, to.Roll)*scale),
+	}
+}
+
+// rotationDelta returns the change from one rotation component to another along the shortest arc, so that a
+// component crossing from 1 to -1 turns a little further rather than all the way back.
+func rotationDelta(from, to float32) float32 {
+	delta := to - from
+
+	switch {
+	case delta > rotationPeriod/2:
+		delta -= rotationPeriod
+	case delta < -rotationPeriod/2:
+		delta += rotationPeriod
+	}
+
+	return delta
+}
+
+// wrapRotation wraps a rotation component back into the range -1 to 1.
+func wrapRotation(value float32) float32 {
+	wrapped := math.Mod(float64(value)+rotationPeriod/2, rotationPeriod)
+	if wrapped < 0 {
+		wrapped += rotationPeriod
+	}
+
+	return float32(wrapped - rotationPeriod/2)
+}
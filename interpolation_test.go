@@ -0,0 +1,202 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type InterpolatorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	now         time.Time
+}
+
+func TestInterpolatorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(InterpolatorTestSuite))
+}
+
+func (suite *InterpolatorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *InterpolatorTestSuite) clock() time.Time {
+	return suite.now
+}
+
+func (suite *InterpolatorTestSuite) push(interpolator *gttelemetry.Interpolator, x, rpm float32) []gttelemetry.InterpolatedFrame {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.SetMapPositionCoordinates(x, 0, 0)
+	suite.transformer.SetVelocityVector(60, 0, 0)
+	suite.transformer.RawTelemetry.EngineRpm = rpm
+
+	return interpolator.Push(suite.transformer)
+}
+
+func (suite *InterpolatorTestSuite) TestPushUpsamplesToOutputRate() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{
+		Rate:     240,
+		Channels: []string{"engineRPM"},
+	})
+	first := suite.push(interpolator, 0, 1000)
+
+	// Act
+	frames := suite.push(interpolator, 1, 2000)
+
+	// Assert
+	suite.Require().Len(first, 1)
+	suite.Require().Len(frames, 4)
+	suite.Equal(time.Second/240, frames[0].Time)
+	suite.InDelta(0.25, frames[0].Position.X, 0.0001)
+	suite.InDelta(1250, frames[0].Channels["engineRPM"], 0.1)
+	suite.InDelta(0.75, frames[2].Position.X, 0.0001)
+	suite.Equal(time.Second/60, frames[3].Time)
+	suite.InDelta(1, frames[3].Position.X, 0)
+	suite.InDelta(2000, frames[3].Channels["engineRPM"], 0)
+}
+
+func (suite *InterpolatorTestSuite) TestPushIgnoresRepeatedFrame() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{})
+	suite.push(interpolator, 0, 0)
+
+	// Act
+	frames := interpolator.Push(suite.transformer)
+
+	// Assert
+	suite.Empty(frames)
+}
+
+func (suite *InterpolatorTestSuite) TestPushRestartsAfterSequenceJump() {
+	tests := map[string]uint32{
+		"forwards":  600,
+		"backwards": 1,
+	}
+
+	for name, sequenceID := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{Rate: 240})
+			suite.transformer.RawTelemetry.SequenceId = 100
+			suite.push(interpolator, 0, 0)
+			suite.push(interpolator, 1, 0)
+
+			suite.transformer.RawTelemetry.SequenceId = sequenceID - 1
+
+			// Act
+			jumped := suite.push(interpolator, 50, 0)
+			next := suite.push(interpolator, 51, 0)
+
+			// Assert
+			suite.Require().Len(jumped, 1)
+			suite.Equal(2*time.Second/60, jumped[0].Time)
+			suite.InDelta(50, jumped[0].Position.X, 0)
+			suite.Require().Len(next, 4)
+			suite.Equal(3*time.Second/60, next[3].Time)
+			suite.InDelta(50.25, next[0].Position.X, 0.0001)
+		})
+	}
+}
+
+func (suite *InterpolatorTestSuite) TestPushInterpolatesAcrossDroppedPackets() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{Rate: 60})
+	suite.push(interpolator, 0, 0)
+	suite.transformer.RawTelemetry.SequenceId += 2
+
+	// Act
+	frames := suite.push(interpolator, 3, 0)
+
+	// Assert
+	suite.Require().Len(frames, 3)
+	suite.InDelta(1, frames[0].Position.X, 0.0001)
+	suite.Equal(3*time.Second/60, frames[2].Time)
+}
+
+func (suite *InterpolatorTestSuite) TestSampleInterpolatesBetweenLatestFrames() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{Now: suite.clock})
+	suite.push(interpolator, 0, 0)
+	suite.now = suite.now.Add(20 * time.Millisecond)
+	suite.push(interpolator, 2, 0)
+
+	// Act
+	suite.now = suite.now.Add(5 * time.Millisecond)
+	frame := interpolator.Sample()
+
+	// Assert
+	suite.InDelta(0.5, frame.Position.X, 0.0001)
+}
+
+func (suite *InterpolatorTestSuite) TestSampleExtrapolatesFromVelocity() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{Now: suite.clock, Extrapolate: true})
+	suite.push(interpolator, 0, 0)
+	suite.push(interpolator, 1, 0)
+
+	// Act
+	suite.now = suite.now.Add(10 * time.Millisecond)
+	frame := interpolator.Sample()
+
+	// Assert
+	suite.InDelta(1.6, frame.Position.X, 0.0001)
+}
+
+func (suite *InterpolatorTestSuite) TestRotationIsInterpolatedOnTheShortestArc() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{Rate: 120})
+	suite.transformer.SetRotationalEnvelope(0, 0.95, 0)
+	suite.push(interpolator, 0, 0)
+
+	// Act
+	suite.transformer.SetRotationalEnvelope(0, -0.95, 0)
+	frames := suite.push(interpolator, 1, 0)
+
+	// Assert
+	suite.Require().Len(frames, 2)
+	suite.InDelta(1, abs(frames[0].Rotation.Yaw), 0.0001, "The yaw wraps past 1 instead of turning back through 0")
+}
+
+func (suite *InterpolatorTestSuite) TestSampleExtrapolatesRotation() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{Now: suite.clock, Extrapolate: true})
+	suite.transformer.SetRotationalEnvelope(0.1, 0.98, 0)
+	suite.push(interpolator, 0, 0)
+	suite.transformer.SetRotationalEnvelope(0.11, 0.99, 0)
+	suite.push(interpolator, 1, 0)
+
+	// Act
+	suite.now = suite.now.Add(time.Second / 30)
+	frame := interpolator.Sample()
+
+	// Assert
+	suite.InDelta(0.13, frame.Rotation.Pitch, 0.0001)
+	suite.InDelta(-0.99, frame.Rotation.Yaw, 0.0001)
+	suite.Zero(frame.Rotation.Roll)
+}
+
+// abs returns the magnitude of a value.
+func abs(value float32) float32 {
+	return max(value, -value)
+}
+
+func (suite *InterpolatorTestSuite) TestSampleWithoutFramesIsEmpty() {
+	// Arrange
+	interpolator := gttelemetry.NewInterpolator(gttelemetry.InterpolatorOptions{})
+
+	// Act
+	frame := interpolator.Sample()
+
+	// Assert
+	suite.Equal(gttelemetry.InterpolatedFrame{}, frame)
+}
@@ -0,0 +1,282 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultCompareStep is the distance between the points of a lap comparison in metres when no step is given.
+	DefaultCompareStep = 5
+	// cornerSpeedDrop is the drop in speed, in km/h, from the preceding straight that identifies a corner.
+	cornerSpeedDrop = 15
+)
+
+// ErrEmptyLapTrace indicates a lap trace without samples, which cannot be compared.
+var ErrEmptyLapTrace = errors.New("lap trace has no samples")
+
+// LapDeltaPoint compares two laps at the same point on the lap. Values prefixed with Reference are from the reference
+// lap, the others are from the lap compared with it.
+type LapDeltaPoint struct {
+	// Distance is the distance from the start of the reference lap in metres.
+	Distance                 float32 `json:"distance"`
+	ReferenceSpeedKPH        float32 `json:"referenceSpeedKPH"`
+	SpeedKPH                 float32 `json:"speedKPH"`
+	ReferenceThrottlePercent float32 `json:"referenceThrottlePercent"`
+	ThrottlePercent          float32 `json:"throttlePercent"`
+	ReferenceBrakePercent    float32 `json:"referenceBrakePercent"`
+	BrakePercent             float32 `json:"brakePercent"`
+	ReferenceGear            int     `json:"referenceGear"`
+	Gear                     int     `json:"gear"`
+	// TimeDelta is how far the compared lap is behind the reference lap at this point, negative when it is ahead.
+	TimeDelta time.Duration `json:"timeDelta"`
+}
+
+// CornerDelta is the time gained or lost through a corner, from the end of the preceding straight to the end of the
+// following straight.
+type CornerDelta struct {
+	// Corner is the number of the corner, counting from 1 at the start of the lap.
+	Corner                int     `json:"corner"`
+	StartDistance         float32 `json:"startDistance"`
+	ApexDistance          float32 `json:"apexDistance"`
+	EndDistance           float32 `json:"endDistance"`
+	ReferenceMinimumSpeed float32 `json:"referenceMinimumSpeed"`
+	MinimumSpeed          float32 `json:"minimumSpeed"`
+	ReferenceMinimumGear  int     `json:"referenceMinimumGear"`
+	MinimumGear           int     `json:"minimumGear"`
+	// TimeDelta is the time lost through the corner by the compared lap, negative when time was gained.
+	TimeDelta time.Duration `json:"timeDelta"`
+}
+
+// LapComparison aligns two laps by distance and breaks the difference in lap time down by corner.
+type LapComparison struct {
+	ReferenceLap int16           `json:"referenceLap"`
+	Lap          int16           `json:"lap"`
+	LaptimeDelta time.Duration   `json:"laptimeDelta"`
+	Points       []LapDeltaPoint `json:"points"`
+	Corners      []CornerDelta   `json:"corners"`
+}
+
+// CompareLaps compares a lap with a reference lap at points every step metres along the reference lap, defaulting to
+// DefaultCompareStep. The laps are aligned by the fraction of the lap completed, which compensates for the distance
+// drifting between laps. Corners are found where the reference speed drops by at least 15km/h between straights.
+func CompareLaps(reference, lap LapTrace, step float32) (LapComparison, error) {
+	if len(reference.Samples) == 0 || len(lap.Samples) == 0 {
+		return LapComparison{}, ErrEmptyLapTrace
+	}
+
+	if step <= 0 {
+		step = DefaultCompareStep
+	}
+
+	referenceLength := reference.Samples[len(reference.Samples)-1].Distance
+	lapLength := lap.Samples[len(lap.Samples)-1].Distance
+
+	comparison := LapComparison{
+		ReferenceLap: reference.Lap,
+		Lap:          lap.Lap,
+		LaptimeDelta: lap.Laptime - reference.Laptime,
+	}
+
+	for distance := float32(0); ; distance += step {
+		distance = min(distance, referenceLength)
+
+		lapDistance := distance
+		if referenceLength > 0 {
+			lapDistance = distance / referenceLength * lapLength
+		}
+
+		referenceSample := sampleAtDistance(reference.Samples, distance)
+		lapSample := sampleAtDistance(lap.Samples, lapDistance)
+
+		comparison.Points = append(comparison.Points, LapDeltaPoint{
+			Distance:                 distance,
+			ReferenceSpeedKPH:        referenceSample.SpeedKPH,
+			SpeedKPH:                 lapSample.SpeedKPH,
+			ReferenceThrottlePercent: referenceSample.ThrottlePercent,
+			ThrottlePercent:          lapSample.ThrottlePercent,
+			ReferenceBrakePercent:    referenceSample.BrakePercent,
+			BrakePercent:             lapSample.BrakePercent,
+			ReferenceGear:            referenceSample.Gear,
+			Gear:                     lapSample.Gear,
+			TimeDelta:                lapSample.Elapsed - referenceSample.Elapsed,
+		})
+
+		if distance >= referenceLength {
+			break
+		}
+	}
+
+	comparison.Corners = compareCorners(comparison.Points)
+
+	return comparison, nil
+}
+
+// sampleAtDistance interpolates the sample at a distance along the lap. The gear is taken from the earlier sample.
+func sampleAtDistance(samples []LapTraceSample, distance float32) LapTraceSample {
+	index := sort.Search(len(samples), func(i int) bool {
+		return samples[i].Distance >= distance
+	})
+
+	switch {
+	case index == 0:
+		return samples[0]
+	case index == len(samples):
+		return samples[len(samples)-1]
+	}
+
+	before, after := samples[index-1], samples[index]
+
+	span := after.Distance - before.Distance
+	if span <= 0 {
+		return after
+	}
+
+	fraction := (distance - before.Distance) / span
+
+	return LapTraceSample{
+		Distance:        distance,
+		Elapsed:         before.Elapsed + time.Duration(float32(after.Elapsed-before.Elapsed)*fraction),
+		SpeedKPH:        lerp(before.SpeedKPH, after.SpeedKPH, fraction),
+		ThrottlePercent: lerp(before.ThrottlePercent, after.ThrottlePercent, fraction),
+		BrakePercent:    lerp(before.BrakePercent, after.BrakePercent, fraction),
+		Gear:            before.Gear,
+		X:               lerp(before.X, after.X, fraction),
+		Z:               lerp(before.Z, after.Z, fraction),
+	}
+}
+
+func lerp(a, b, fraction float32) float32 {
+	return a + (b-a)*fraction
+}
+
+// compareCorners finds the corners in the reference speed and totals the time delta through each of them. A corner
+// runs from the fastest point before its apex to the fastest point after it, so consecutive corners share a boundary
+// and the corner deltas add up to the lap time delta, apart from the straights before the first and after the last.
+func compareCorners(points []LapDeltaPoint) []CornerDelta {
+	peaks, apexes := speedExtremes(points)
+
+	corners := make([]CornerDelta, 0, len(apexes))
+
+	for i, apex := range apexes {
+		start := peaks[i]
+
+		end := len(points) - 1
+		if i+1 < len(peaks) {
+			end = peaks[i+1]
+		}
+
+		corner := CornerDelta{
+			Corner:                i + 1,
+			StartDistance:         points[start].Distance,
+			ApexDistance:          points[apex].Distance,
+			EndDistance:           points[end].Distance,
+			ReferenceMinimumSpeed: points[apex].ReferenceSpeedKPH,
+			MinimumSpeed:          points[apex].SpeedKPH,
+			ReferenceMinimumGear:  points[apex].ReferenceGear,
+			MinimumGear:           points[apex].Gear,
+			TimeDelta:             points[end].TimeDelta - points[start].TimeDelta,
+		}
+
+		for _, point := range points[start : end+1] {
+			if point.SpeedKPH < corner.MinimumSpeed {
+				corner.MinimumSpeed = point.SpeedKPH
+			}
+
+			if point.Gear < corner.MinimumGear {
+				corner.MinimumGear = point.Gear
+			}
+		}
+
+		corners = append(corners, corner)
+	}
+
+	return corners
+}
+
+// speedExtremes returns the indices of the fastest point before each corner and of the slowest point of each corner
+// in the reference speed. Peaks has one entry for every apex, starting with the fastest point before the first
+// corner, and an extra entry when the speed rises again after the last corner.
+func speedExtremes(points []LapDeltaPoint) ([]int, []int) {
+	var (
+		peaks   []int
+		apexes  []int
+		extreme int
+	)
+
+	findingApex := false
+
+	for i, point := range points {
+		speed := point.ReferenceSpeedKPH
+		extremeSpeed := points[extreme].ReferenceSpeedKPH
+
+		if !findingApex {
+			if speed > extremeSpeed {
+				extreme = i
+			} else if extremeSpeed-speed >= cornerSpeedDrop {
+				peaks = append(peaks, extreme)
+				extreme = i
+				findingApex = true
+			}
+
+			continue
+		}
+
+		if speed < extremeSpeed {
+			extreme = i
+		} else if speed-extremeSpeed >= cornerSpeedDrop {
+			apexes = append(apexes, extreme)
+			extreme = i
+			findingApex = false
+		}
+	}
+
+	if findingApex {
+		// the lap ended before the speed rose again, e.g. braking for the final corner
+		apexes = append(apexes, extreme)
+	} else if len(apexes) > 0 {
+		peaks = append(peaks, extreme)
+	}
+
+	return peaks, apexes
+}
+
+// WriteCSV writes the aligned points to w as CSV with a header row. The time delta is written in seconds.
+func (c LapComparison) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{
+		"distanceMetres", "referenceSpeedKPH", "speedKPH", "referenceThrottlePercent", "throttlePercent",
+		"referenceBrakePercent", "brakePercent", "referenceGear", "gear", "timeDeltaSeconds",
+	})
+	if err != nil {
+		return fmt.Errorf("write lap comparison CSV header: %w", err)
+	}
+
+	for _, point := range c.Points {
+		err = writer.Write([]string{
+			formatDecimal(float64(point.Distance), 1),
+			formatDecimal(float64(point.ReferenceSpeedKPH), 2),
+			formatDecimal(float64(point.SpeedKPH), 2),
+			formatDecimal(float64(point.ReferenceThrottlePercent), 1),
+			formatDecimal(float64(point.ThrottlePercent), 1),
+			formatDecimal(float64(point.ReferenceBrakePercent), 1),
+			formatDecimal(float64(point.BrakePercent), 1),
+			strconv.Itoa(point.ReferenceGear),
+			strconv.Itoa(point.Gear),
+			formatDecimal(point.TimeDelta.Seconds(), 3),
+		})
+		if err != nil {
+			return fmt.Errorf("write lap comparison CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
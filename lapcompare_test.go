@@ -0,0 +1,129 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type LapCompareTestSuite struct {
+	suite.Suite
+}
+
+func TestLapCompareTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LapCompareTestSuite))
+}
+
+// syntheticLap builds a 1km lap with a straight at 200km/h and a single corner at 500m taken at the given speed. Each
+// sample is 10m apart and the elapsed time is integrated from the speed.
+func syntheticLap(lap int16, cornerSpeed float32) gttelemetry.LapTrace {
+	trace := gttelemetry.LapTrace{Lap: lap}
+	elapsed := time.Duration(0)
+
+	for i := range 101 {
+		distance := float32(i * 10)
+
+		speed := float32(200)
+		if offset := distance - 500; offset > -200 && offset < 200 {
+			fraction := max(offset, -offset) / 200
+			speed = cornerSpeed + (200-cornerSpeed)*fraction
+		}
+
+		if i > 0 {
+			elapsed += time.Duration(10 / (speed / 3.6) * float32(time.Second))
+		}
+
+		gear := 6
+		if speed < 150 {
+			gear = 3
+		}
+
+		trace.Samples = append(trace.Samples, gttelemetry.LapTraceSample{
+			Distance: distance,
+			Elapsed:  elapsed,
+			SpeedKPH: speed,
+			Gear:     gear,
+		})
+	}
+
+	trace.Laptime = elapsed
+
+	return trace
+}
+
+func (suite *LapCompareTestSuite) TestCompareLapsFindsTimeLostInCorner() {
+	// Arrange
+	reference := syntheticLap(3, 100)
+	slower := syntheticLap(5, 80)
+
+	// Act
+	comparison, err := gttelemetry.CompareLaps(reference, slower, 10)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(int16(3), comparison.ReferenceLap)
+	suite.Equal(int16(5), comparison.Lap)
+	suite.Len(comparison.Points, 101)
+	suite.Positive(comparison.LaptimeDelta)
+
+	suite.Require().Len(comparison.Corners, 1)
+	corner := comparison.Corners[0]
+	suite.InDelta(500, corner.ApexDistance, 0.01)
+	suite.InDelta(100, corner.ReferenceMinimumSpeed, 0.01)
+	suite.InDelta(80, corner.MinimumSpeed, 0.01)
+	suite.Equal(3, corner.MinimumGear)
+	suite.InDelta(comparison.LaptimeDelta.Seconds(), corner.TimeDelta.Seconds(), 0.001)
+}
+
+func (suite *LapCompareTestSuite) TestCompareLapsAlignsByLapFraction() {
+	// Arrange
+	reference := syntheticLap(1, 100)
+	longer := syntheticLap(2, 100)
+
+	for i := range longer.Samples {
+		longer.Samples[i].Distance *= 1.02
+	}
+
+	// Act
+	comparison, err := gttelemetry.CompareLaps(reference, longer, 0)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.InDelta(1000, comparison.Points[len(comparison.Points)-1].Distance, 0.01)
+
+	for _, point := range comparison.Points {
+		suite.InDelta(0, point.TimeDelta.Seconds(), 0.001)
+	}
+}
+
+func (suite *LapCompareTestSuite) TestCompareLapsRejectsEmptyTrace() {
+	// Act
+	_, err := gttelemetry.CompareLaps(gttelemetry.LapTrace{}, syntheticLap(1, 100), 5)
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrEmptyLapTrace)
+}
+
+func (suite *LapCompareTestSuite) TestWriteCSVWritesEveryPoint() {
+	// Arrange
+	comparison, err := gttelemetry.CompareLaps(syntheticLap(1, 100), syntheticLap(2, 90), 250)
+	suite.Require().NoError(err)
+
+	var buffer bytes.Buffer
+
+	// Act
+	err = comparison.WriteCSV(&buffer)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	suite.Len(lines, 6)
+	suite.True(strings.HasPrefix(lines[0], "distanceMetres,"))
+	suite.True(strings.HasPrefix(lines[3], "500.0,100.00,90.00,"))
+}
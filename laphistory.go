@@ -0,0 +1,178 @@
+package gttelemetry
+
+import (
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// lapHistoryWetTyreCooling is the drop in lap average tyre temperature, in degrees Celsius, compared to the warmest
+// lap of the session that indicates a wet track.
+const lapHistoryWetTyreCooling = 15
+
+// WeatherEstimate is an estimate of the track conditions during a lap.
+type WeatherEstimate string
+
+const (
+	WeatherUnknown WeatherEstimate = "unknown"
+	WeatherDry     WeatherEstimate = "dry"
+	WeatherWet     WeatherEstimate = "wet"
+)
+
+// LapSnapshot describes the state of the vehicle as it crosses the line at the end of a lap. Gaps holds the pauses and
+// packet losses during the lap, whose time is not part of the lap time reported by the game, so that laps driven
+// across a gap can be left out of comparisons.
+type LapSnapshot struct {
+	Lap             int16            `json:"lap"`
+	SequenceID      uint32           `json:"sequenceId"`
+	Laptime         time.Duration    `json:"laptime"`
+	FuelLevel       float32          `json:"fuelLevel"`
+	FuelUsed        float32          `json:"fuelUsed"`
+	TyreTemperature models.CornerSet `json:"tyreTemperature"`
+	TimeOfDay       time.Duration    `json:"timeOfDay"`
+	Weather         WeatherEstimate  `json:"weather"`
+	Setup           SetupFingerprint `json:"setup"`
+	Gaps            []Gap            `json:"gaps,omitempty"`
+}
+
+// LapHistory records a snapshot of the vehicle at every lap boundary, the raw material for strategy charts.
+//
+// Telemetry does not report the weather or race position. The weather is estimated from the average tyre temperature
+// during the lap, a lap that is much cooler than the warmest lap of the session is estimated to be wet. The first
+// laps of a session that starts wet are therefore reported as dry until the track dries.
+type LapHistory struct {
+	lastSequence  uint32
+	lastLap       int16
+	lapStartFuel  float32
+	lapSetup      SetupFingerprint
+	tyreTempSum   float64
+	tyreTempCount int
+	warmestLap    float32
+	gapDetector   *GapDetector
+	lapGaps       []Gap
+	snapshots     []LapSnapshot
+	maxLaps       int
+}
+
+// NewLapHistory creates an empty lap history.
+func NewLapHistory() *LapHistory {
+	return &LapHistory{gapDetector: &GapDetector{discard: true}}
+}
+
+// Update evaluates the latest telemetry frame and returns the snapshot when the vehicle has just completed a lap.
+func (h *LapHistory) Update(t *Transformer) (LapSnapshot, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == h.lastSequence {
+		return LapSnapshot{}, false
+	}
+
+	h.lastSequence = sequenceID
+
+	if gap, ended := h.gapDetector.Update(t); ended {
+		h.lapGaps = append(h.lapGaps, gap)
+	}
+
+	if !t.IsOnCircuit() {
+		h.lastLap = 0
+
+		return LapSnapshot{}, false
+	}
+
+	lap := t.CurrentLap()
+	lastLap := h.lastLap
+	h.lastLap = lap
+
+	if lap <= lastLap || lastLap < 1 {
+		if lap != lastLap {
+			h.startLap(t)
+		}
+
+		// The values are held while paused, so would weight the average towards the frame the pause started at
+		if !h.gapDetector.Paused() {
+			h.tyreTempSum += float64(averageTyreTemperature(t))
+			h.tyreTempCount++
+		}
+
+		return LapSnapshot{}, false
+	}
+
+	snapshot := LapSnapshot{
+		Lap:             lastLap,
+		SequenceID:      sequenceID,
+		Laptime:         t.LastLaptime(),
+		FuelLevel:       t.FuelLevel(),
+		FuelUsed:        h.lapStartFuel - t.FuelLevel(),
+		TyreTemperature: t.TyreTemperatureCelsius(),
+		TimeOfDay:       t.TimeOfDay(),
+		Weather:         h.estimateWeather(),
+		Setup:           h.lapSetup,
+		Gaps:            h.lapGaps,
+	}
+
+	h.snapshots = trimRetained(append(h.snapshots, snapshot), h.maxLaps)
+	h.startLap(t)
+
+	return snapshot, true
+}
+
+// SetMaxRetainedLaps keeps only the snapshots of the most recent laps, bounding the memory used by long sessions such
+// as 24 hour endurance races. Zero keeps the snapshot of every lap.
+func (h *LapHistory) SetMaxRetainedLaps(laps int) {
+	h.maxLaps = max(laps, 0)
+	h.snapshots = trimRetained(h.snapshots, h.maxLaps)
+}
+
+// Snapshots returns the snapshots of all completed laps in lap order, or of the most recent laps when the number of
+// retained laps is limited.
+func (h *LapHistory) Snapshots() []LapSnapshot {
+	return h.snapshots
+}
+
+// Snapshot returns the snapshot for the given lap.
+func (h *LapHistory) Snapshot(lap int16) (LapSnapshot, bool) {
+	for i := len(h.snapshots) - 1; i >= 0; i-- {
+		if h.snapshots[i].Lap == lap {
+			return h.snapshots[i], true
+		}
+	}
+
+	return LapSnapshot{}, false
+}
+
+// SnapshotsForSetup returns the snapshots of the completed laps driven with the given setup, so that laps are only
+// compared with laps driven with an identical setup.
+func (h *LapHistory) SnapshotsForSetup(setup SetupFingerprint) []LapSnapshot {
+	snapshots := make([]LapSnapshot, 0, len(h.snapshots))
+
+	for _, snapshot := range h.snapshots {
+		if snapshot.Setup == setup {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	return snapshots
+}
+
+func (h *LapHistory) startLap(t *Transformer) {
+	h.lapStartFuel = t.FuelLevel()
+	h.lapSetup = t.SetupFingerprint()
+	h.tyreTempSum = 0
+	h.tyreTempCount = 0
+	h.lapGaps = nil
+}
+
+// estimateWeather compares the average tyre temperature of the lap with the warmest lap of the session.
+func (h *LapHistory) estimateWeather() WeatherEstimate {
+	if h.tyreTempCount == 0 {
+		return WeatherUnknown
+	}
+
+	average := float32(h.tyreTempSum / float64(h.tyreTempCount))
+	h.warmestLap = max(h.warmestLap, average)
+
+	if h.warmestLap-average >= lapHistoryWetTyreCooling {
+		return WeatherWet
+	}
+
+	return WeatherDry
+}
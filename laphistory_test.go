@@ -0,0 +1,180 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type LapHistoryTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	history     *gttelemetry.LapHistory
+}
+
+func TestLapHistoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LapHistoryTestSuite))
+}
+
+func (suite *LapHistoryTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.FuelLevel = 50
+	suite.transformer.SetTyreTemperature(80, 80, 80, 80)
+
+	suite.history = gttelemetry.NewLapHistory()
+}
+
+func (suite *LapHistoryTestSuite) frame(lap int16) (gttelemetry.LapSnapshot, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.CurrentLap = lap
+
+	return suite.history.Update(suite.transformer)
+}
+
+// lap drives a lap of frames at the given tyre temperature, using the given amount of fuel.
+func (suite *LapHistoryTestSuite) lap(lap int16, tyreTemp, fuelUsed float32) {
+	suite.transformer.SetTyreTemperature(tyreTemp, tyreTemp, tyreTemp, tyreTemp)
+
+	for range 10 {
+		suite.frame(lap)
+		suite.transformer.RawTelemetry.FuelLevel -= fuelUsed / 10
+	}
+}
+
+func (suite *LapHistoryTestSuite) TestSnapshotIsTakenAtLapBoundary() {
+	// Arrange
+	suite.lap(1, 80, 2.5)
+	suite.transformer.RawTelemetry.LastLaptime = 83456
+	suite.transformer.RawTelemetry.TimeOfDay = 3600000
+
+	// Act
+	snapshot, ok := suite.frame(2)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(int16(1), snapshot.Lap)
+	suite.Equal(83456*time.Millisecond, snapshot.Laptime)
+	suite.InDelta(47.5, snapshot.FuelLevel, 0.01)
+	suite.InDelta(2.5, snapshot.FuelUsed, 0.01)
+	suite.InDelta(80, snapshot.TyreTemperature.FrontLeft, 0.01)
+	suite.Equal(time.Hour, snapshot.TimeOfDay)
+	suite.Equal(gttelemetry.WeatherDry, snapshot.Weather)
+}
+
+func (suite *LapHistoryTestSuite) TestHistoryIsQueryableByLap() {
+	// Arrange
+	suite.lap(1, 80, 2)
+	suite.lap(2, 80, 3)
+
+	// Act
+	suite.lap(3, 80, 2)
+
+	// Assert
+	suite.Len(suite.history.Snapshots(), 2)
+
+	snapshot, ok := suite.history.Snapshot(2)
+	suite.Require().True(ok)
+	suite.InDelta(3, snapshot.FuelUsed, 0.01)
+
+	_, ok = suite.history.Snapshot(3)
+	suite.False(ok, "Lap 3 has not been completed")
+}
+
+func (suite *LapHistoryTestSuite) TestMaxRetainedLapsKeepsRecentLaps() {
+	// Arrange
+	suite.history.SetMaxRetainedLaps(2)
+
+	// Act
+	for lap := range int16(5) {
+		suite.lap(lap+1, 80, 2)
+	}
+
+	suite.frame(6)
+
+	// Assert
+	snapshots := suite.history.Snapshots()
+	suite.Require().Len(snapshots, 2)
+	suite.Equal(int16(4), snapshots[0].Lap)
+	suite.Equal(int16(5), snapshots[1].Lap)
+
+	_, ok := suite.history.Snapshot(3)
+	suite.False(ok, "Lap 3 is no longer retained")
+}
+
+func (suite *LapHistoryTestSuite) TestCoolLapIsEstimatedWet() {
+	// Arrange
+	suite.lap(1, 85, 2)
+	suite.lap(2, 60, 2)
+
+	// Act
+	snapshot, ok := suite.frame(3)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(gttelemetry.WeatherWet, snapshot.Weather)
+}
+
+func (suite *LapHistoryTestSuite) TestJoiningMidLapDoesNotReportPartialLapFuel() {
+	// Arrange
+	suite.frame(4)
+	suite.transformer.RawTelemetry.FuelLevel = 40
+
+	// Act
+	snapshot, ok := suite.frame(5)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.InDelta(10, snapshot.FuelUsed, 0.01)
+	suite.Equal(int16(4), snapshot.Lap)
+}
+
+func (suite *LapHistoryTestSuite) TestSnapshotsAreFilteredBySetup() {
+	// Arrange
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 300
+	suite.lap(1, 80, 2)
+	setup := suite.transformer.SetupFingerprint()
+
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 290
+	suite.lap(2, 80, 2)
+
+	// Act
+	suite.lap(3, 80, 2)
+
+	// Assert
+	snapshots := suite.history.SnapshotsForSetup(setup)
+	suite.Require().Len(snapshots, 1)
+	suite.Equal(int16(1), snapshots[0].Lap)
+	suite.Equal(setup, snapshots[0].Setup)
+	suite.NotEqual(setup, suite.history.Snapshots()[1].Setup)
+}
+
+func (suite *LapHistoryTestSuite) TestGapsDuringLapAreRecorded() {
+	// Arrange
+	suite.lap(1, 80, 2.5)
+	suite.transformer.SetFlags(true, true, false, true, false, false, false, false, false, false, false, false)
+	suite.lap(1, 20, 0)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.lap(1, 80, 0)
+
+	// Act
+	snapshot, ok := suite.frame(2)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Require().Len(snapshot.Gaps, 1)
+	suite.Equal(gttelemetry.GapPaused, snapshot.Gaps[0].Reason)
+	suite.Equal(11*time.Second/gttelemetry.FrameRate, snapshot.Gaps[0].Duration)
+
+	suite.lap(2, 80, 2.5)
+	next, ok := suite.frame(3)
+	suite.Require().True(ok)
+	suite.Empty(next.Gaps, "gaps belong to the lap they occurred in")
+}
@@ -0,0 +1,285 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// lapTraceColumns is the header row of a lap trace CSV file.
+//
+//nolint:gochecknoglobals // read-only header
+var lapTraceColumns = []string{"lap", "distanceMetres", "elapsedSeconds", "speedKPH", "throttlePercent", "brakePercent", "gear", "x", "z"}
+
+// lapTracePositionColumns is the number of position columns at the end of a lap trace CSV file, which are missing
+// from files written before positions were traced.
+const lapTracePositionColumns = 2
+
+// lapTraceCapacity is the number of samples allocated up front for each lap, enough for a two minute lap.
+const lapTraceCapacity = FrameRate * 120
+
+// ErrInvalidLapTrace indicates a lap trace CSV file that cannot be read.
+var ErrInvalidLapTrace = errors.New("invalid lap trace")
+
+// LapTraceSample is the state of the vehicle at a point on a lap.
+type LapTraceSample struct {
+	// Distance is the distance travelled since the start of the lap in metres.
+	Distance float32 `json:"distance"`
+	// Elapsed is the time since the start of the lap.
+	Elapsed         time.Duration `json:"elapsed"`
+	SpeedKPH        float32       `json:"speedKPH"`
+	ThrottlePercent float32       `json:"throttlePercent"`
+	BrakePercent    float32       `json:"brakePercent"`
+	Gear            int           `json:"gear"`
+	// X and Z are the position on the circuit map in metres.
+	X float32 `json:"x"`
+	Z float32 `json:"z"`
+}
+
+// LapTrace is the speed, driver inputs and gear sampled at every frame of a lap.
+type LapTrace struct {
+	Lap     int16            `json:"lap"`
+	Laptime time.Duration    `json:"laptime"`
+	Samples []LapTraceSample `json:"samples"`
+}
+
+// LapTraceRecorder records a trace of every completed lap, for comparing laps with CompareLaps.
+//
+// The distance is integrated from the ground speed, so it drifts slightly between laps. Traces are therefore
+// aligned by the fraction of the lap completed when they are compared.
+type LapTraceRecorder struct {
+	lastSequence uint32
+	lastLap      int16
+	distance     float64
+	elapsed      time.Duration
+	samples      []LapTraceSample
+	traces       []LapTrace
+	maxLaps      int
+}
+
+// NewLapTraceRecorder creates a recorder without any traces.
+func NewLapTraceRecorder() *LapTraceRecorder {
+	return &LapTraceRecorder{}
+}
+
+// Update samples the latest telemetry frame and returns the trace when the vehicle has just completed a lap. Laps
+// that were not driven from the start, such as the lap a recording starts in, are not returned.
+func (r *LapTraceRecorder) Update(t *Transformer) (LapTrace, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == r.lastSequence {
+		return LapTrace{}, false
+	}
+
+	previousSequence := r.lastSequence
+	r.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() {
+		r.lastLap = 0
+		r.samples = nil
+
+		return LapTrace{}, false
+	}
+
+	lap := t.CurrentLap()
+	lastLap := r.lastLap
+	r.lastLap = lap
+
+	if lap > lastLap && lastLap >= 1 && r.samples != nil {
+		trace := LapTrace{
+			Lap:     lastLap,
+			Laptime: t.LastLaptime(),
+			Samples: r.samples,
+		}
+
+		r.traces = trimRetained(append(r.traces, trace), r.maxLaps)
+		r.startLap()
+		r.sample(t)
+
+		return trace, true
+	}
+
+	if lap != lastLap {
+		// the first lap of the recorder is only traced from its start
+		if lastLap != 0 || lap == 1 {
+			r.startLap()
+		} else {
+			r.samples = nil
+		}
+	} else if r.samples != nil && !t.Flags().GamePaused {
+		// Paused frames hold the values of the frame the pause started at, so are neither driving time nor samples
+		elapsed := t.CorrectedDuration(framesElapsed(previousSequence, sequenceID))
+		r.elapsed += elapsed
+		r.distance += float64(t.GroundSpeedMetresPerSecond()) * elapsed.Seconds()
+	}
+
+	if r.samples != nil && !t.Flags().GamePaused {
+		r.sample(t)
+	}
+
+	return LapTrace{}, false
+}
+
+// SetMaxRetainedLaps keeps only the traces of the most recent laps, bounding the memory used by long sessions such as
+// 24 hour endurance races. Zero keeps the trace of every lap. Trace and Fastest only consider the retained laps.
+func (r *LapTraceRecorder) SetMaxRetainedLaps(laps int) {
+	r.maxLaps = max(laps, 0)
+	r.traces = trimRetained(r.traces, r.maxLaps)
+}
+
+// Traces returns the traces of all completed laps in lap order, or of the most recent laps when the number of retained
+// laps is limited.
+func (r *LapTraceRecorder) Traces() []LapTrace {
+	return r.traces
+}
+
+// Trace returns the trace of the given lap.
+func (r *LapTraceRecorder) Trace(lap int16) (LapTrace, bool) {
+	for i := len(r.traces) - 1; i >= 0; i-- {
+		if r.traces[i].Lap == lap {
+			return r.traces[i], true
+		}
+	}
+
+	return LapTrace{}, false
+}
+
+// Fastest returns the trace of the fastest completed lap.
+func (r *LapTraceRecorder) Fastest() (LapTrace, bool) {
+	if len(r.traces) == 0 {
+		return LapTrace{}, false
+	}
+
+	fastest := r.traces[0]
+	for _, trace := range r.traces[1:] {
+		if trace.Laptime > 0 && (fastest.Laptime <= 0 || trace.Laptime < fastest.Laptime) {
+			fastest = trace
+		}
+	}
+
+	return fastest, true
+}
+
+func (r *LapTraceRecorder) startLap() {
+	r.distance = 0
+	r.elapsed = 0
+	r.samples = make([]LapTraceSample, 0, lapTraceCapacity)
+}
+
+func (r *LapTraceRecorder) sample(t *Transformer) {
+	position := t.PositionalMapCoordinates()
+
+	r.samples = append(r.samples, LapTraceSample{
+		Distance:        float32(r.distance),
+		Elapsed:         r.elapsed,
+		SpeedKPH:        t.GroundSpeedKPH(),
+		ThrottlePercent: t.ThrottleInputPercent(),
+		BrakePercent:    t.BrakeInputPercent(),
+		Gear:            t.CurrentGear(),
+		X:               position.X,
+		Z:               position.Z,
+	})
+}
+
+// WriteCSV writes the trace to w as CSV with a header row, one row per sample. Elapsed time is written in seconds.
+func (l LapTrace) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(lapTraceColumns)
+	if err != nil {
+		return fmt.Errorf("write lap trace CSV header: %w", err)
+	}
+
+	lap := strconv.Itoa(int(l.Lap))
+
+	for _, sample := range l.Samples {
+		err = writer.Write([]string{
+			lap,
+			formatDecimal(float64(sample.Distance), 2),
+			formatDecimal(sample.Elapsed.Seconds(), 3),
+			formatDecimal(float64(sample.SpeedKPH), 2),
+			formatDecimal(float64(sample.ThrottlePercent), 1),
+			formatDecimal(float64(sample.BrakePercent), 1),
+			strconv.Itoa(sample.Gear),
+			formatDecimal(float64(sample.X), 2),
+			formatDecimal(float64(sample.Z), 2),
+		})
+		if err != nil {
+			return fmt.Errorf("write lap trace CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// ReadLapTraceCSV reads a trace written by LapTrace.WriteCSV. The lap time is taken from the last sample, so it may
+// be up to a frame shorter than the lap time reported by the game. Files written without the position columns are
+// read with every position at the map origin.
+func ReadLapTraceCSV(r io.Reader) (LapTrace, error) {
+	reader := csv.NewReader(r)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return LapTrace{}, fmt.Errorf("%w: %w", ErrInvalidLapTrace, err)
+	}
+
+	if len(records) > 0 {
+		columns := len(records[0])
+		if columns != len(lapTraceColumns) && columns != len(lapTraceColumns)-lapTracePositionColumns {
+			return LapTrace{}, fmt.Errorf("%w: expected %d columns, found %d", ErrInvalidLapTrace, len(lapTraceColumns), columns)
+		}
+	}
+
+	if len(records) <= 1 {
+		return LapTrace{}, fmt.Errorf("%w: no samples", ErrInvalidLapTrace)
+	}
+
+	trace := LapTrace{Samples: make([]LapTraceSample, 0, len(records)-1)}
+
+	for row, record := range records[1:] {
+		sample, lap, err := parseLapTraceRecord(record)
+		if err != nil {
+			return LapTrace{}, fmt.Errorf("%w: row %d: %w", ErrInvalidLapTrace, row+2, err)
+		}
+
+		trace.Lap = lap
+		trace.Samples = append(trace.Samples, sample)
+	}
+
+	trace.Laptime = trace.Samples[len(trace.Samples)-1].Elapsed
+
+	return trace, nil
+}
+
+// parseLapTraceRecord parses a CSV row into a sample and its lap number.
+func parseLapTraceRecord(record []string) (LapTraceSample, int16, error) {
+	var values [8]float64
+
+	lap, err := strconv.ParseInt(record[0], 10, 16)
+	if err != nil {
+		return LapTraceSample{}, 0, fmt.Errorf("%s: %w", lapTraceColumns[0], err)
+	}
+
+	for i := range record[1:] {
+		values[i], err = strconv.ParseFloat(record[i+1], 64)
+		if err != nil {
+			return LapTraceSample{}, 0, fmt.Errorf("%s: %w", lapTraceColumns[i+1], err)
+		}
+	}
+
+	return LapTraceSample{
+		Distance:        float32(values[0]),
+		Elapsed:         time.Duration(math.Round(values[1] * float64(time.Second))),
+		SpeedKPH:        float32(values[2]),
+		ThrottlePercent: float32(values[3]),
+		BrakePercent:    float32(values[4]),
+		Gear:            int(values[5]),
+		X:               float32(values[6]),
+		Z:               float32(values[7]),
+	}, int16(lap), nil
+}
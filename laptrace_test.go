@@ -0,0 +1,178 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type LapTraceTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	recorder    *gttelemetry.LapTraceRecorder
+}
+
+func TestLapTraceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LapTraceTestSuite))
+}
+
+func (suite *LapTraceTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.recorder = gttelemetry.NewLapTraceRecorder()
+}
+
+func (suite *LapTraceTestSuite) frame(lap int16, speed float32) (gttelemetry.LapTrace, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.CurrentLap = lap
+	suite.transformer.RawTelemetry.GroundSpeed = speed
+
+	return suite.recorder.Update(suite.transformer)
+}
+
+// lap drives a lap of one second at a constant speed in metres per second.
+func (suite *LapTraceTestSuite) lap(lap int16, speed float32) {
+	for range gttelemetry.FrameRate {
+		suite.frame(lap, speed)
+	}
+}
+
+func (suite *LapTraceTestSuite) TestTraceIsReturnedAtLapBoundary() {
+	// Arrange
+	suite.lap(1, 36)
+	suite.transformer.RawTelemetry.LastLaptime = 1000
+
+	// Act
+	trace, ok := suite.frame(2, 36)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(int16(1), trace.Lap)
+	suite.Equal(time.Second, trace.Laptime)
+	suite.Len(trace.Samples, gttelemetry.FrameRate)
+	suite.InDelta(0, trace.Samples[0].Distance, 0.001)
+	suite.InDelta(35.4, trace.Samples[len(trace.Samples)-1].Distance, 0.01)
+	suite.InDelta(129.6, trace.Samples[0].SpeedKPH, 0.01)
+}
+
+func (suite *LapTraceTestSuite) TestPartialFirstLapIsNotTraced() {
+	// Arrange
+	suite.lap(3, 36)
+
+	// Act
+	_, ok := suite.frame(4, 36)
+
+	// Assert
+	suite.False(ok)
+	suite.Empty(suite.recorder.Traces())
+}
+
+func (suite *LapTraceTestSuite) TestFastestReturnsQuickestLap() {
+	// Arrange
+	suite.lap(1, 36)
+	suite.transformer.RawTelemetry.LastLaptime = 92000
+	suite.lap(2, 36)
+	suite.transformer.RawTelemetry.LastLaptime = 91000
+	suite.lap(3, 36)
+	suite.transformer.RawTelemetry.LastLaptime = 93000
+	suite.frame(4, 36)
+
+	// Act
+	fastest, ok := suite.recorder.Fastest()
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(int16(2), fastest.Lap)
+}
+
+func (suite *LapTraceTestSuite) TestMaxRetainedLapsKeepsRecentTraces() {
+	// Arrange
+	suite.recorder.SetMaxRetainedLaps(2)
+
+	// Act
+	for lap := range int16(4) {
+		suite.lap(lap+1, 36)
+	}
+
+	suite.frame(5, 36)
+
+	// Assert
+	traces := suite.recorder.Traces()
+	suite.Require().Len(traces, 2)
+	suite.Equal(int16(3), traces[0].Lap)
+	suite.Equal(int16(4), traces[1].Lap)
+}
+
+func (suite *LapTraceTestSuite) TestCSVRoundTrip() {
+	// Arrange
+	trace := gttelemetry.LapTrace{
+		Lap: 4,
+		Samples: []gttelemetry.LapTraceSample{
+			{Distance: 0, Elapsed: 0, SpeedKPH: 180.5, ThrottlePercent: 100, Gear: 5},
+			{Distance: 50.25, Elapsed: 1100 * time.Millisecond, SpeedKPH: 120, BrakePercent: 80.5, Gear: 3, X: -12.5, Z: 340.25},
+		},
+	}
+
+	var buffer bytes.Buffer
+
+	suite.Require().NoError(trace.WriteCSV(&buffer))
+
+	// Act
+	got, err := gttelemetry.ReadLapTraceCSV(&buffer)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	trace.Laptime = 1100 * time.Millisecond
+	suite.Equal(trace, got)
+}
+
+func (suite *LapTraceTestSuite) TestReadLapTraceCSVWithoutPositions() {
+	// Arrange
+	csv := "lap,distanceMetres,elapsedSeconds,speedKPH,throttlePercent,brakePercent,gear\n2,10.5,0.250,150,100,0,4\n"
+
+	// Act
+	trace, err := gttelemetry.ReadLapTraceCSV(strings.NewReader(csv))
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(trace.Samples, 1)
+	suite.Equal(4, trace.Samples[0].Gear)
+	suite.Zero(trace.Samples[0].X)
+	suite.Zero(trace.Samples[0].Z)
+}
+
+func (suite *LapTraceTestSuite) TestReadLapTraceCSVRejectsInvalidFile() {
+	tests := map[string]struct {
+		csv string
+	}{
+		"no samples": {
+			csv: "lap,distanceMetres,elapsedSeconds,speedKPH,throttlePercent,brakePercent,gear\n",
+		},
+		"invalid number": {
+			csv: "lap,distanceMetres,elapsedSeconds,speedKPH,throttlePercent,brakePercent,gear\n1,fast,0,0,0,0,0\n",
+		},
+		"missing column": {
+			csv: "lap,distanceMetres\n1,0\n",
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			_, err := gttelemetry.ReadLapTraceCSV(strings.NewReader(test.csv))
+
+			// Assert
+			suite.ErrorIs(err, gttelemetry.ErrInvalidLapTrace)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package gttelemetry
+
+import "github.com/zetetos/gt-telemetry/v2/pkg/models"
+
+// Aliases using the US spelling of metre, kept so that code written against the v1 method names continues to build.
+// tools/rename_meters.sh rewrites uses of these names to the British spelling used by the rest of the API.
+
+// DefaultTrackWidthMeters is the assumed width of the circuit when no width is configured.
+//
+// Deprecated: Use DefaultTrackWidthMetres.
+const DefaultTrackWidthMeters = DefaultTrackWidthMetres
+
+// DynamicWheelbaseLeftMeters is an alias of DynamicWheelbaseLeftMetres.
+//
+// Deprecated: Use DynamicWheelbaseLeftMetres.
+func (t *Transformer) DynamicWheelbaseLeftMeters() float32 {
+	return t.DynamicWheelbaseLeftMetres()
+}
+
+// DynamicWheelbaseLeftMillimeters is an alias of DynamicWheelbaseLeftMillimetres.
+//
+// Deprecated: Use DynamicWheelbaseLeftMillimetres.
+func (t *Transformer) DynamicWheelbaseLeftMillimeters() float32 {
+	return t.DynamicWheelbaseLeftMillimetres()
+}
+
+// GroundSpeedMetersPerSecond is an alias of GroundSpeedMetresPerSecond.
+//
+// Deprecated: Use GroundSpeedMetresPerSecond.
+func (t *Transformer) GroundSpeedMetersPerSecond() float32 {
+	return t.GroundSpeedMetresPerSecond()
+}
+
+// RideHeightMeters is an alias of RideHeightMetres.
+//
+// Deprecated: Use RideHeightMetres.
+func (t *Transformer) RideHeightMeters() float32 {
+	return t.RideHeightMetres()
+}
+
+// RideHeightMillimeters is an alias of RideHeightMillimetres.
+//
+// Deprecated: Use RideHeightMillimetres.
+func (t *Transformer) RideHeightMillimeters() float32 {
+	return t.RideHeightMillimetres()
+}
+
+// SuspensionHeightMeters is an alias of SuspensionHeightMetres.
+//
+// Deprecated: Use SuspensionHeightMetres.
+func (t *Transformer) SuspensionHeightMeters() models.CornerSet {
+	return t.SuspensionHeightMetres()
+}
+
+// SuspensionHeightMillimeters is an alias of SuspensionHeightMillimetres.
+//
+// Deprecated: Use SuspensionHeightMillimetres.
+func (t *Transformer) SuspensionHeightMillimeters() models.CornerSet {
+	return t.SuspensionHeightMillimetres()
+}
+
+// TyreDiameterMeters is an alias of TyreDiameterMetres.
+//
+// Deprecated: Use TyreDiameterMetres.
+func (t *Transformer) TyreDiameterMeters() models.CornerSet {
+	return t.TyreDiameterMetres()
+}
+
+// TyreDiameterMillimeters is an alias of TyreDiameterMillimetres.
+//
+// Deprecated: Use TyreDiameterMillimetres.
+func (t *Transformer) TyreDiameterMillimeters() models.CornerSet {
+	return t.TyreDiameterMillimetres()
+}
+
+// TyreRadiusMeters is an alias of TyreRadiusMetres.
+//
+// Deprecated: Use TyreRadiusMetres.
+func (t *Transformer) TyreRadiusMeters() models.CornerSet {
+	return t.TyreRadiusMetres()
+}
+
+// TyreRadiusMillimeters is an alias of TyreRadiusMillimetres.
+//
+// Deprecated: Use TyreRadiusMillimetres.
+func (t *Transformer) TyreRadiusMillimeters() models.CornerSet {
+	return t.TyreRadiusMillimetres()
+}
+
+// VehicleHeightMillimeters is an alias of VehicleHeightMillimetres.
+//
+// Deprecated: Use VehicleHeightMillimetres.
+func (t *Transformer) VehicleHeightMillimeters() int {
+	return t.VehicleHeightMillimetres()
+}
+
+// VehicleLengthMillimeters is an alias of VehicleLengthMillimetres.
+//
+// Deprecated: Use VehicleLengthMillimetres.
+func (t *Transformer) VehicleLengthMillimeters() int {
+	return t.VehicleLengthMillimetres()
+}
+
+// VehicleTrackFrontMillimeters is an alias of VehicleTrackFrontMillimetres.
+//
+// Deprecated: Use VehicleTrackFrontMillimetres.
+func (t *Transformer) VehicleTrackFrontMillimeters() int {
+	return t.VehicleTrackFrontMillimetres()
+}
+
+// VehicleTrackRearMillimeters is an alias of VehicleTrackRearMillimetres.
+//
+// Deprecated: Use VehicleTrackRearMillimetres.
+func (t *Transformer) VehicleTrackRearMillimeters() int {
+	return t.VehicleTrackRearMillimetres()
+}
+
+// VehicleWheelbaseMillimeters is an alias of VehicleWheelbaseMillimetres.
+//
+// Deprecated: Use VehicleWheelbaseMillimetres.
+func (t *Transformer) VehicleWheelbaseMillimeters() int {
+	return t.VehicleWheelbaseMillimetres()
+}
+
+// VehicleWidthMillimeters is an alias of VehicleWidthMillimetres.
+//
+// Deprecated: Use VehicleWidthMillimetres.
+func (t *Transformer) VehicleWidthMillimeters() int {
+	return t.VehicleWidthMillimetres()
+}
+
+// WheelSpeedMetersPerSecond is an alias of WheelSpeedMetresPerSecond.
+//
+// Deprecated: Use WheelSpeedMetresPerSecond.
+func (t *Transformer) WheelSpeedMetersPerSecond() models.CornerSet {
+	return t.WheelSpeedMetresPerSecond()
+}
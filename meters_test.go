@@ -0,0 +1,47 @@
+package gttelemetry_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type MetersTestSuite struct {
+	suite.Suite
+}
+
+func TestMetersTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(MetersTestSuite))
+}
+
+func (suite *MetersTestSuite) TestEveryMetresMethodHasAlias() {
+	// Arrange
+	transformer := gttelemetry.NewTransformer(nil)
+	transformer.RawTelemetry.RideHeight = 0.08
+	transformer.RawTelemetry.GroundSpeed = 50
+	transformer.RawTelemetry.SequenceId = 1
+	transformer.SetVehicle(vehicles.Vehicle{Length: 4500, Wheelbase: 2700})
+	value := reflect.ValueOf(transformer)
+	transformerType := value.Type()
+
+	for i := range transformerType.NumMethod() {
+		name := transformerType.Method(i).Name
+		if !strings.Contains(name, "etres") {
+			continue
+		}
+
+		suite.Run(name, func() {
+			// Act
+			alias := value.MethodByName(strings.ReplaceAll(name, "etres", "eters"))
+
+			// Assert
+			suite.Require().True(alias.IsValid(), "missing alias")
+			suite.Equal(value.Method(i).Call(nil)[0].Interface(), alias.Call(nil)[0].Interface())
+		})
+	}
+}
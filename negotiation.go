@@ -0,0 +1,146 @@
+package gttelemetry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/reader"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// FormatNegotiationStep is how long the client waits for packets in a requested format before stepping down to the
+// next richest format while negotiating.
+const FormatNegotiationStep = 3 * time.Second
+
+// negotiationOrder lists the telemetry formats from richest to poorest.
+var negotiationOrder = []models.Name{ //nolint:gochecknoglobals // Static lookup table
+	models.Addendum3,
+	models.Addendum2,
+	models.Addendum1,
+	models.Standard,
+}
+
+// NegotiatedFormat returns the richest telemetry format that the console was found to send, or models.Unknown while
+// negotiation is still in progress. Negotiation is only used when no format is set in the options, otherwise the
+// configured or most recently requested format is returned.
+func (c *Client) NegotiatedFormat() models.Name {
+	c.formatMutex.Lock()
+	defer c.formatMutex.Unlock()
+
+	if !c.negotiating {
+		return c.format
+	}
+
+	return c.negotiatedFormat
+}
+
+// observeFormat records the format of a successfully decoded packet. While negotiating, a packet in the requested
+// format completes negotiation, while a packet in a poorer format means the console does not support the requested
+// format, so the poorer format is requested instead. Sources that cannot be asked for a format, such as recordings,
+// are negotiated to the format of their first packet.
+func (c *Client) observeFormat(detected models.Name) {
+	c.formatMutex.Lock()
+	defer c.formatMutex.Unlock()
+
+	c.lastFormatPacketAt = time.Now()
+
+	if !c.negotiating || c.negotiatedFormat != models.Unknown {
+		return
+	}
+
+	if _, ok := c.activeReader.(reader.FormatRequester); c.activeReader != nil && !ok {
+		c.format = detected
+		c.negotiatedFormat = detected
+		c.log.Info().Msgf("negotiated telemetry format %q from a source that cannot request formats", detected)
+
+		return
+	}
+
+	requestedRank := slices.Index(negotiationOrder, c.format)
+	detectedRank := slices.Index(negotiationOrder, detected)
+
+	// Packets richer than requested are left over from an earlier request
+	if detectedRank < 0 || detectedRank < requestedRank {
+		return
+	}
+
+	if detected != c.format {
+		err := c.requestFormatLocked(detected)
+		if err != nil {
+			c.log.Warn().Err(err).Msgf("failed to request negotiated format %q", detected)
+
+			return
+		}
+	}
+
+	c.negotiatedFormat = detected
+	c.log.Info().Msgf("negotiated telemetry format %q", detected)
+}
+
+// negotiateFormat steps down through the formats from richest to poorest while no packets are received in the
+// requested format, starting again from the richest after the poorest. It returns once negotiation completes or the
+// context is done.
+func (c *Client) negotiateFormat(ctx context.Context) {
+	ticker := time.NewTicker(FormatNegotiationStep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !c.stepDownFormat() {
+				return
+			}
+		}
+	}
+}
+
+// stepDownFormat requests the next poorer format if nothing has been received since the current format was requested.
+// It returns false once negotiation is no longer in progress.
+func (c *Client) stepDownFormat() bool {
+	c.formatMutex.Lock()
+	defer c.formatMutex.Unlock()
+
+	if !c.negotiating || c.negotiatedFormat != models.Unknown || c.activeReader == nil {
+		return false
+	}
+
+	if c.lastFormatPacketAt.After(c.formatRequestedAt) || time.Since(c.formatRequestedAt) < FormatNegotiationStep {
+		return true
+	}
+
+	next := negotiationOrder[(slices.Index(negotiationOrder, c.format)+1)%len(negotiationOrder)]
+
+	c.log.Debug().Msgf("no telemetry received in format %q, requesting format %q", c.format, next)
+
+	err := c.requestFormatLocked(next)
+	if err != nil {
+		c.log.Warn().Err(err).Msgf("failed to request format %q", next)
+	}
+
+	return true
+}
+
+// requestFormatLocked sends a request for the format to the active reader, if any, and records it as the requested
+// format. The format mutex must be held.
+func (c *Client) requestFormatLocked(format models.Name) error {
+	if c.activeReader != nil {
+		requester, ok := c.activeReader.(reader.FormatRequester)
+		if !ok {
+			return ErrFormatRequestNotSupported
+		}
+
+		err := requester.RequestFormat(format)
+		if err != nil {
+			return fmt.Errorf("request format: %w", err)
+		}
+	}
+
+	c.format = format
+	c.formatRequestedAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,321 @@
+package gttelemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultOverlayInterval is the time between the samples of an overlay track when no interval is given.
+const DefaultOverlayInterval = 100 * time.Millisecond
+
+// ErrUnknownOverlayFormat indicates an overlay stream format other than srt or ass.
+var ErrUnknownOverlayFormat = errors.New("unknown overlay format")
+
+// OverlaySample is the state of the vehicle shown by an overlay at a point in the video.
+type OverlaySample struct {
+	// Timestamp is the time of the sample from the start of the video.
+	Timestamp time.Duration `json:"timestamp"`
+	Lap       int16         `json:"lap"`
+	Laptime   time.Duration `json:"laptime"`
+	SpeedKPH  float32       `json:"speedKPH"`
+	Gear      string        `json:"gear"`
+}
+
+// OverlayOptions configures the sampling and synchronisation of an overlay track.
+type OverlayOptions struct {
+	// Interval is the minimum time between samples, defaulting to DefaultOverlayInterval. Set it to FrameInterval to
+	// keep every frame.
+	Interval time.Duration
+	// Offset is added to the time of each frame to line the track up with the video. Use a positive offset when the
+	// recording started after the video, and a negative offset when it started before. Samples that fall before the
+	// start of the video are dropped.
+	Offset time.Duration
+}
+
+// OverlayTrack records the speed, gear and lap time of a recording against the time since its first frame, for
+// overlaying telemetry onto captured gameplay video. The track can be written as SRT or ASS subtitles, or as JSON
+// keyframes for video editors that accept scripted overlays.
+//
+// Time is counted in frames rather than with the wall clock, so the track is the same when a recording is replayed
+// at any speed.
+type OverlayTrack struct {
+	interval     time.Duration
+	offset       time.Duration
+	lastSequence uint32
+	started      bool
+	frames       uint64
+	nextSample   time.Duration
+	samples      []OverlaySample
+}
+
+// NewOverlayTrack creates an empty overlay track.
+func NewOverlayTrack(opts OverlayOptions) *OverlayTrack {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultOverlayInterval
+	}
+
+	return &OverlayTrack{
+		interval: opts.Interval,
+		offset:   opts.Offset,
+	}
+}
+
+// Update adds the latest telemetry frame to the track. Repeated frames are ignored.
+func (o *OverlayTrack) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if o.started && sequenceID == o.lastSequence {
+		return
+	}
+
+	if o.started && sequenceID > o.lastSequence {
+		o.frames += uint64(sequenceID - o.lastSequence)
+	}
+
+	o.started = true
+	o.lastSequence = sequenceID
+
+	timestamp := t.CorrectedDuration(framesDuration(o.frames)) + o.offset
+	if timestamp < 0 || timestamp < o.nextSample {
+		return
+	}
+
+	o.samples = append(o.samples, OverlaySample{
+		Timestamp: timestamp,
+		Lap:       t.CurrentLap(),
+		Laptime:   t.CurrentLaptime(),
+		SpeedKPH:  t.GroundSpeedKPH(),
+		Gear:      t.CurrentGearString(),
+	})
+
+	o.nextSample = timestamp + o.interval
+}
+
+// Samples returns the samples of the track in time order.
+func (o *OverlayTrack) Samples() []OverlaySample {
+	return o.samples
+}
+
+// Text returns the line of text shown for a sample in the subtitle formats.
+func (s OverlaySample) Text() string {
+	return fmt.Sprintf("%.0f km/h  Gear %s  Lap %d  %s", s.SpeedKPH, s.Gear, s.Lap, formatOverlayLaptime(s.Laptime))
+}
+
+// WriteSRT writes the track to w as SubRip subtitles, with one cue per sample shown until the next sample.
+func (o *OverlayTrack) WriteSRT(w io.Writer) error {
+	for i, sample := range o.samples {
+		start, end := o.cueTimes(i)
+
+		err := writeSRTCue(w, i+1, start, end, sample)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assHeader is the script header of an ASS subtitle file, with a style placing the overlay in the bottom left corner.
+const assHeader = `[Script Info]
+ScriptType: v4.00+
+PlayResX: 1920
+PlayResY: 1080
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Telemetry,Consolas,48,&H00FFFFFF,&H00FFFFFF,&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,2,0,1,40,40,40,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+// WriteASS writes the track to w as Advanced SubStation Alpha subtitles, which can be restyled in a subtitle editor
+// or burnt into the video, e.g. with ffmpeg's subtitles filter.
+func (o *OverlayTrack) WriteASS(w io.Writer) error {
+	_, err := io.WriteString(w, assHeader)
+	if err != nil {
+		return fmt.Errorf("write ASS header: %w", err)
+	}
+
+	for i, sample := range o.samples {
+		start, end := o.cueTimes(i)
+
+		err = writeASSEvent(w, start, end, sample)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSRTCue writes a sample as the numbered SubRip cue shown from start until end.
+func writeSRTCue(w io.Writer, number int, start, end time.Duration, sample OverlaySample) error {
+	_, err := fmt.Fprintf(w, "%d\n%s --> %s\n%s\n\n", number, formatSRTTime(start), formatSRTTime(end), sample.Text())
+	if err != nil {
+		return fmt.Errorf("write SRT cue: %w", err)
+	}
+
+	return nil
+}
+
+// writeASSEvent writes a sample as the ASS dialogue event shown from start until end.
+func writeASSEvent(w io.Writer, start, end time.Duration, sample OverlaySample) error {
+	_, err := fmt.Fprintf(w, "Dialogue: 0,%s,%s,Telemetry,,0,0,0,,%s\n",
+		formatASSTime(start), formatASSTime(end), sample.Text())
+	if err != nil {
+		return fmt.Errorf("write ASS event: %w", err)
+	}
+
+	return nil
+}
+
+// OverlayStream writes an overlay track as subtitles while the recording is read, holding only the sample waiting for
+// the next one to end its cue, so that the memory used does not grow with the length of the session.
+type OverlayStream struct {
+	track *OverlayTrack
+	w     io.Writer
+	ass   bool
+	cues  int
+}
+
+// NewOverlayStream creates a stream writing SRT or ASS subtitles to w, the formats written by WriteSRT and WriteASS.
+// JSON keyframes are written as a single document, so are only available from an OverlayTrack.
+func NewOverlayStream(w io.Writer, format string, opts OverlayOptions) (*OverlayStream, error) {
+	stream := &OverlayStream{track: NewOverlayTrack(opts), w: w}
+
+	switch format {
+	case "srt":
+	case "ass":
+		stream.ass = true
+
+		_, err := io.WriteString(w, assHeader)
+		if err != nil {
+			return nil, fmt.Errorf("write ASS header: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOverlayFormat, format)
+	}
+
+	return stream, nil
+}
+
+// Update adds the latest telemetry frame to the track, writing the cue of the previous sample once a new sample is
+// taken.
+func (s *OverlayStream) Update(t *Transformer) error {
+	s.track.Update(t)
+
+	if len(s.track.samples) < 2 {
+		return nil
+	}
+
+	err := s.writeCue(s.track.samples[0], s.track.samples[1].Timestamp)
+
+	s.track.samples = append(s.track.samples[:0], s.track.samples[1])
+
+	return err
+}
+
+// Close writes the cue of the last sample, shown for one interval. It does not close w.
+func (s *OverlayStream) Close() error {
+	if len(s.track.samples) == 0 {
+		return nil
+	}
+
+	last := s.track.samples[0]
+	s.track.samples = s.track.samples[:0]
+
+	return s.writeCue(last, last.Timestamp+s.track.interval)
+}
+
+func (s *OverlayStream) writeCue(sample OverlaySample, end time.Duration) error {
+	s.cues++
+
+	if s.ass {
+		return writeASSEvent(s.w, sample.Timestamp, end, sample)
+	}
+
+	return writeSRTCue(s.w, s.cues, sample.Timestamp, end, sample)
+}
+
+// overlayKeyframe is a sample of the JSON keyframe track, with times in seconds for scripting in video editors.
+type overlayKeyframe struct {
+	Time           float64 `json:"time"`
+	Lap            int16   `json:"lap"`
+	LaptimeSeconds float64 `json:"laptimeSeconds"`
+	SpeedKPH       float32 `json:"speedKPH"`
+	Gear           string  `json:"gear"`
+}
+
+// WriteJSON writes the track to w as a JSON object holding an array of keyframes, with times in seconds.
+func (o *OverlayTrack) WriteJSON(w io.Writer) error {
+	keyframes := make([]overlayKeyframe, 0, len(o.samples))
+
+	for _, sample := range o.samples {
+		keyframes = append(keyframes, overlayKeyframe{
+			Time:           sample.Timestamp.Seconds(),
+			Lap:            sample.Lap,
+			LaptimeSeconds: sample.Laptime.Seconds(),
+			SpeedKPH:       sample.SpeedKPH,
+			Gear:           sample.Gear,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(struct {
+		Interval  float64           `json:"interval"`
+		Keyframes []overlayKeyframe `json:"keyframes"`
+	}{
+		Interval:  o.interval.Seconds(),
+		Keyframes: keyframes,
+	})
+	if err != nil {
+		return fmt.Errorf("write JSON keyframes: %w", err)
+	}
+
+	return nil
+}
+
+// cueTimes returns the times a sample is shown from and until, which is the time of the next sample or one interval
+// for the last sample.
+func (o *OverlayTrack) cueTimes(i int) (time.Duration, time.Duration) {
+	start := o.samples[i].Timestamp
+	end := start + o.interval
+
+	if i+1 < len(o.samples) {
+		end = o.samples[i+1].Timestamp
+	}
+
+	return start, end
+}
+
+// formatSRTTime formats a timestamp as hh:mm:ss,mmm.
+func formatSRTTime(d time.Duration) string {
+	hours, minutes, seconds := splitDuration(d)
+
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, (d%time.Second)/time.Millisecond)
+}
+
+// formatASSTime formats a timestamp as h:mm:ss.cc, the centisecond resolution of ASS subtitles.
+func formatASSTime(d time.Duration) string {
+	hours, minutes, seconds := splitDuration(d)
+
+	return fmt.Sprintf("%d:%02d:%02d.%02d", hours, minutes, seconds, (d%time.Second)/(10*time.Millisecond))
+}
+
+func splitDuration(d time.Duration) (time.Duration, time.Duration, time.Duration) {
+	return d / time.Hour, (d % time.Hour) / time.Minute, (d % time.Minute) / time.Second
+}
+
+// formatOverlayLaptime formats a lap time as m:ss.SSS, or dashes before the lap has started.
+func formatOverlayLaptime(laptime time.Duration) string {
+	if laptime <= 0 {
+		return "-:--.---"
+	}
+
+	return fmt.Sprintf("%d:%06.3f", laptime/time.Minute, float64(laptime%time.Minute)/float64(time.Second))
+}
@@ -0,0 +1,222 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type OverlayTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestOverlayTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(OverlayTestSuite))
+}
+
+func (suite *OverlayTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.transformer.SetTransmissionGear(3, 15)
+	suite.transformer.RawTelemetry.GroundSpeed = 25
+}
+
+// drive updates the track with the given number of consecutive frames, advancing the lap time with each frame.
+func (suite *OverlayTestSuite) drive(track *gttelemetry.OverlayTrack, frames int) {
+	for range frames {
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.transformer.RawTelemetry.CurrentLaptime += int32(gttelemetry.FrameInterval / time.Millisecond)
+		track.Update(suite.transformer)
+	}
+}
+
+func (suite *OverlayTestSuite) TestSamplesAreTakenAtInterval() {
+	// Arrange
+	track := gttelemetry.NewOverlayTrack(gttelemetry.OverlayOptions{Interval: 500 * time.Millisecond})
+
+	// Act
+	suite.drive(track, gttelemetry.FrameRate*2)
+
+	// Assert
+	samples := track.Samples()
+	suite.Require().Len(samples, 4)
+	suite.Equal(time.Duration(0), samples[0].Timestamp)
+	suite.Equal(1500*time.Millisecond, samples[3].Timestamp)
+	suite.Equal(int16(2), samples[3].Lap)
+	suite.Equal("3", samples[3].Gear)
+	suite.InDelta(90, samples[3].SpeedKPH, 0.01)
+}
+
+func (suite *OverlayTestSuite) TestRepeatedFramesAreIgnored() {
+	// Arrange
+	track := gttelemetry.NewOverlayTrack(gttelemetry.OverlayOptions{Interval: gttelemetry.FrameInterval})
+	suite.drive(track, 1)
+
+	// Act
+	track.Update(suite.transformer)
+
+	// Assert
+	suite.Len(track.Samples(), 1)
+}
+
+func (suite *OverlayTestSuite) TestOffsetShiftsTimestamps() {
+	tests := map[string]struct {
+		offset    time.Duration
+		wantFirst time.Duration
+		wantCount int
+	}{
+		"recording started after video": {
+			offset:    10 * time.Second,
+			wantFirst: 10 * time.Second,
+			wantCount: 2,
+		},
+		"recording started before video": {
+			offset:    -time.Second,
+			wantFirst: 0,
+			wantCount: 1,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+			track := gttelemetry.NewOverlayTrack(gttelemetry.OverlayOptions{Interval: time.Second, Offset: test.offset})
+
+			// Act
+			suite.drive(track, gttelemetry.FrameRate*2)
+
+			// Assert
+			suite.Require().Len(track.Samples(), test.wantCount)
+			suite.Equal(test.wantFirst, track.Samples()[0].Timestamp)
+		})
+	}
+}
+
+func (suite *OverlayTestSuite) TestWriteSRT() {
+	// Arrange
+	track := gttelemetry.NewOverlayTrack(gttelemetry.OverlayOptions{Interval: time.Second, Offset: time.Hour})
+	suite.drive(track, gttelemetry.FrameRate+1)
+
+	var buffer bytes.Buffer
+
+	// Act
+	err := track.WriteSRT(&buffer)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("1\n01:00:00,000 --> 01:00:01,000\n90 km/h  Gear 3  Lap 2  0:00.016\n\n"+
+		"2\n01:00:01,000 --> 01:00:02,000\n90 km/h  Gear 3  Lap 2  0:00.976\n\n", buffer.String())
+}
+
+func (suite *OverlayTestSuite) TestWriteASS() {
+	// Arrange
+	track := gttelemetry.NewOverlayTrack(gttelemetry.OverlayOptions{Interval: 250 * time.Millisecond})
+	suite.transformer.SetTransmissionGear(0, 15)
+	suite.drive(track, 1)
+
+	var buffer bytes.Buffer
+
+	// Act
+	err := track.WriteASS(&buffer)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Contains(buffer.String(), "[Events]\n")
+	suite.Contains(buffer.String(), "Dialogue: 0,0:00:00.00,0:00:00.25,Telemetry,,0,0,0,,90 km/h  Gear R  Lap 2  0:00.016\n")
+}
+
+func (suite *OverlayTestSuite) TestStreamMatchesTrack() {
+	tests := map[string]struct {
+		format string
+		write  func(track *gttelemetry.OverlayTrack, buffer *bytes.Buffer) error
+	}{
+		"srt": {
+			format: "srt",
+			write:  func(track *gttelemetry.OverlayTrack, buffer *bytes.Buffer) error { return track.WriteSRT(buffer) },
+		},
+		"ass": {
+			format: "ass",
+			write:  func(track *gttelemetry.OverlayTrack, buffer *bytes.Buffer) error { return track.WriteASS(buffer) },
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+
+			opts := gttelemetry.OverlayOptions{Interval: 250 * time.Millisecond, Offset: -100 * time.Millisecond}
+			track := gttelemetry.NewOverlayTrack(opts)
+
+			var want, got bytes.Buffer
+
+			stream, err := gttelemetry.NewOverlayStream(&got, test.format, opts)
+			suite.Require().NoError(err)
+
+			// Act
+			for range gttelemetry.FrameRate * 3 {
+				suite.transformer.RawTelemetry.SequenceId++
+				suite.transformer.RawTelemetry.CurrentLaptime += int32(gttelemetry.FrameInterval / time.Millisecond)
+				track.Update(suite.transformer)
+				suite.Require().NoError(stream.Update(suite.transformer))
+			}
+
+			suite.Require().NoError(stream.Close())
+			suite.Require().NoError(test.write(track, &want))
+
+			// Assert
+			suite.NotEmpty(want.String())
+			suite.Equal(want.String(), got.String())
+		})
+	}
+}
+
+func (suite *OverlayTestSuite) TestStreamRejectsJSON() {
+	// Act
+	_, err := gttelemetry.NewOverlayStream(&bytes.Buffer{}, "json", gttelemetry.OverlayOptions{})
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrUnknownOverlayFormat)
+}
+
+func (suite *OverlayTestSuite) TestWriteJSON() {
+	// Arrange
+	track := gttelemetry.NewOverlayTrack(gttelemetry.OverlayOptions{Interval: time.Second})
+	suite.drive(track, gttelemetry.FrameRate+1)
+
+	var buffer bytes.Buffer
+
+	// Act
+	err := track.WriteJSON(&buffer)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	var got struct {
+		Interval  float64 `json:"interval"`
+		Keyframes []struct {
+			Time           float64 `json:"time"`
+			LaptimeSeconds float64 `json:"laptimeSeconds"`
+			Gear           string  `json:"gear"`
+		} `json:"keyframes"`
+	}
+
+	suite.Require().NoError(json.Unmarshal(buffer.Bytes(), &got))
+	suite.InDelta(1, got.Interval, 0.001)
+	suite.Require().Len(got.Keyframes, 2)
+	suite.InDelta(1, got.Keyframes[1].Time, 0.001)
+	suite.InDelta(0.976, got.Keyframes[1].LaptimeSeconds, 0.001)
+	suite.Equal("3", got.Keyframes[1].Gear)
+}
@@ -0,0 +1,182 @@
+package gttelemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
+	"github.com/zetetos/gt-telemetry/v2/internal/reader"
+	"github.com/zetetos/gt-telemetry/v2/internal/telemetry"
+)
+
+// scanBatchSize is the number of packets parsed together by a worker of ScanParallel, large enough that handing
+// batches between goroutines costs little next to parsing them.
+const scanBatchSize = 256
+
+// scanBatch is a run of consecutive packets of a recording, parsed by one worker of ScanParallel.
+type scanBatch struct {
+	packets   [][]byte
+	frames    []telemetry.GranTurismoTelemetry
+	parseErrs []error
+	decodedAt []time.Time
+	// readErr is the error that ended reading the recording after the packets of the batch
+	readErr error
+	parsed  chan struct{}
+}
+
+// ScanParallel is Scan with the packets of the recording parsed by a pool of workers, so that exports and reports
+// of large recordings use every core. The frames are yielded in the order they were recorded, with the same sequence
+// IDs and telemetry as Scan, because the transformer, filters, statistics and recording are still updated one frame
+// at a time in that order. A workers count of zero or less uses one worker for each CPU available to the process.
+func (c *Client) ScanParallel(ctx context.Context, workers int) iter.Seq2[*Transformer, error] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers == 1 {
+		return c.Scan(ctx)
+	}
+
+	return func(yield func(*Transformer, error) bool) {
+		telemetryReader, err := c.openFileReader()
+		if err != nil {
+			yield(nil, err)
+
+			return
+		}
+
+		c.scanReader.Store(telemetryReader)
+
+		scanCtx, cancel := context.WithCancel(ctx)
+
+		var wg sync.WaitGroup
+
+		// The reader and workers are stopped before the recording is closed
+		defer func() {
+			cancel()
+			wg.Wait()
+			c.scanReader.Store(nil)
+
+			closeErr := telemetryReader.Close()
+			if closeErr != nil {
+				c.log.Error().Err(closeErr).Msg("failed to close telemetry reader")
+			}
+		}()
+
+		receivedAt, err := c.openRecordingTimes()
+		if err != nil {
+			yield(nil, err)
+
+			return
+		}
+
+		batches := make(chan *scanBatch, workers*2)
+		jobs := make(chan *scanBatch, workers*2)
+
+		wg.Go(func() {
+			readBatches(scanCtx, telemetryReader, batches, jobs)
+		})
+
+		for range workers {
+			wg.Go(func() {
+				for batch := range jobs {
+					batch.parse()
+				}
+			})
+		}
+
+		for batch := range batches {
+			select {
+			case <-batch.parsed:
+			case <-ctx.Done():
+				return
+			}
+
+			for i, packet := range batch.packets {
+				if ctx.Err() != nil {
+					return
+				}
+
+				c.DecipheredPacket = packet
+
+				if batch.parseErrs[i] != nil {
+					c.invalidTelemetry(batch.parseErrs[i])
+				} else {
+					c.applyTelemetry(&batch.frames[i], batch.decodedAt[i], receivedAt)
+				}
+
+				if !yield(c.Telemetry, nil) {
+					return
+				}
+			}
+
+			if errors.Is(batch.readErr, io.EOF) {
+				c.finish()
+
+				return
+			}
+
+			if batch.readErr != nil {
+				yield(nil, batch.readErr)
+
+				return
+			}
+		}
+	}
+}
+
+// readBatches reads the packets of a recording into batches, queuing each for parsing and, in the same order, for
+// yielding. The last batch holds the error that ended reading, io.EOF at the end of the recording.
+func readBatches(ctx context.Context, r reader.Reader, batches, jobs chan<- *scanBatch) {
+	defer close(batches)
+	defer close(jobs)
+
+	for {
+		batch := &scanBatch{packets: make([][]byte, 0, scanBatchSize), parsed: make(chan struct{})}
+
+		for len(batch.packets) < scanBatchSize && batch.readErr == nil {
+			bufLen, buffer, err := r.Read()
+			if err != nil {
+				batch.readErr = err
+
+				break
+			}
+
+			if bufLen > 0 {
+				batch.packets = append(batch.packets, bytes.Clone(buffer[:bufLen]))
+			}
+		}
+
+		for _, queue := range []chan<- *scanBatch{jobs, batches} {
+			select {
+			case queue <- batch:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if batch.readErr != nil {
+			return
+		}
+	}
+}
+
+// parse parses every packet of the batch, then signals that it has been parsed.
+func (b *scanBatch) parse() {
+	defer close(b.parsed)
+
+	b.frames = make([]telemetry.GranTurismoTelemetry, len(b.packets))
+	b.parseErrs = make([]error, len(b.packets))
+	b.decodedAt = make([]time.Time, len(b.packets))
+
+	for i, packet := range b.packets {
+		b.decodedAt[i] = time.Now()
+		b.parseErrs[i] = b.frames[i].Read(kaitai.NewStream(bytes.NewReader(packet)), nil, nil)
+	}
+}
@@ -0,0 +1,179 @@
+package gttelemetry_test
+
+import (
+	"context"
+	"iter"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type ParallelScanTestSuite struct {
+	suite.Suite
+}
+
+func TestParallelScanTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ParallelScanTestSuite))
+}
+
+// scannedFrame is the part of a frame compared between scans.
+type scannedFrame struct {
+	sequenceID     uint32
+	engineRPM      float32
+	groundSpeedKPH float32
+	lap            int16
+}
+
+// scanDemo reads every frame of the demo replay with the scan returned by scan.
+func (suite *ParallelScanTestSuite) scanDemo(
+	scan func(client *gttelemetry.Client) iter.Seq2[*gttelemetry.Transformer, error],
+) ([]scannedFrame, *gttelemetry.Client) {
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	var frames []scannedFrame
+
+	for transformer, err := range scan(client) {
+		suite.Require().NoError(err)
+
+		frames = append(frames, scannedFrame{
+			sequenceID:     transformer.SequenceID(),
+			engineRPM:      transformer.EngineRPM(),
+			groundSpeedKPH: transformer.GroundSpeedKPH(),
+			lap:            transformer.CurrentLap(),
+		})
+	}
+
+	return frames, client
+}
+
+func (suite *ParallelScanTestSuite) TestFramesMatchScan() {
+	tests := map[string]struct {
+		workers int
+	}{
+		"one worker":     {workers: 1},
+		"two workers":    {workers: 2},
+		"eight workers":  {workers: 8},
+		"every CPU core": {workers: 0},
+	}
+
+	// Arrange
+	wantFrames, wantClient := suite.scanDemo(func(client *gttelemetry.Client) iter.Seq2[*gttelemetry.Transformer, error] {
+		return client.Scan(context.Background())
+	})
+	suite.Require().NotEmpty(wantFrames)
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Act
+			gotFrames, gotClient := suite.scanDemo(func(client *gttelemetry.Client) iter.Seq2[*gttelemetry.Transformer, error] {
+				return client.ScanParallel(context.Background(), tc.workers)
+			})
+
+			// Assert
+			suite.Equal(wantFrames, gotFrames)
+			suite.True(gotClient.Finished)
+			suite.Equal(wantClient.Statistics.PacketsInvalid, gotClient.Statistics.PacketsInvalid)
+		})
+	}
+}
+
+func (suite *ParallelScanTestSuite) TestStopsWhenBreakingEarly() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	frames := 0
+
+	// Act
+	for _, err := range client.ScanParallel(context.Background(), 4) {
+		suite.Require().NoError(err)
+
+		frames++
+		if frames == 1000 {
+			break
+		}
+	}
+
+	read, size := client.ScanPosition()
+
+	// Assert
+	suite.Equal(1000, frames)
+	suite.False(client.Finished)
+	suite.Zero(read)
+	suite.Zero(size)
+}
+
+func (suite *ParallelScanTestSuite) TestStopsWhenCancelled() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frames := 0
+
+	// Act
+	for _, err := range client.ScanParallel(ctx, 4) {
+		suite.Require().NoError(err)
+
+		frames++
+		if frames == 10 {
+			cancel()
+		}
+	}
+
+	// Assert
+	suite.Equal(10, frames)
+	suite.False(client.Finished)
+}
+
+func (suite *ParallelScanTestSuite) TestFailsForNonFileSource() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "udp://127.0.0.1:33739", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	// Act
+	var gotErr error
+	for _, err := range client.ScanParallel(context.Background(), 4) {
+		gotErr = err
+	}
+
+	// Assert
+	suite.ErrorIs(gotErr, gttelemetry.ErrNotAFileSource)
+}
+
+// BenchmarkScan compares reading every frame of the demo replay with Scan and with ScanParallel using an increasing
+// number of workers. The frames per second are reported alongside the time per pass.
+func BenchmarkScan(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run("workers="+strconv.Itoa(workers), func(b *testing.B) {
+			frames := 0
+
+			for b.Loop() {
+				client, err := gttelemetry.New(gttelemetry.Options{
+					Source:             "file://data/replays/demo.gtz",
+					LogLevel:           "error",
+					DisableInventories: true,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				for _, err := range client.ScanParallel(context.Background(), workers) {
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					frames++
+				}
+			}
+
+			b.ReportMetric(float64(frames)/b.Elapsed().Seconds(), "frames/s")
+		})
+	}
+}
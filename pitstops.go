@@ -0,0 +1,178 @@
+package gttelemetry
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// pitStopStationarySpeed is the ground speed in metres per second below which the vehicle is stationary.
+	pitStopStationarySpeed = 0.5
+	// pitStopMinimumFuel is the increase in fuel level, in litres, that indicates refuelling.
+	pitStopMinimumFuel = 0.5
+	// pitStopTyreCooling is the drop in average tyre temperature, in degrees Celsius, that indicates new tyres.
+	pitStopTyreCooling = 10
+	// pitLossWindow is the number of laps either side of the lap of a pit stop that are affected by the stop. The pit
+	// lane usually crosses the timing line, so the time lost is split between the in lap and the out lap.
+	pitLossWindow = 1
+)
+
+// PitStop describes a completed pit stop.
+type PitStop struct {
+	Lap           int16         `json:"lap"`
+	SequenceID    uint32        `json:"sequenceId"`
+	Duration      time.Duration `json:"duration"`
+	FuelAdded     float32       `json:"fuelAdded"`
+	TyresChanged  bool          `json:"tyresChanged"`
+	startSequence uint32
+}
+
+// PitStopDetector detects pit stops from consecutive frames.
+//
+// Telemetry does not report when the vehicle is in the pit lane, so a pit stop is inferred when the vehicle comes
+// to a stop on the circuit and is then refuelled or fitted with new tyres, identified by an increase in fuel level
+// or a sudden drop in tyre temperature while stationary. Stops without service are not reported.
+type PitStopDetector struct {
+	stationary   bool
+	current      PitStop
+	fuelAtStop   float32
+	tyreTempStop float32
+	lastSequence uint32
+	stops        []PitStop
+}
+
+// NewPitStopDetector creates a new pit stop detector.
+func NewPitStopDetector() *PitStopDetector {
+	return &PitStopDetector{}
+}
+
+// Update evaluates the latest telemetry frame and returns the pit stop when the vehicle has just left the pit box.
+func (d *PitStopDetector) Update(t *Transformer) (PitStop, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == d.lastSequence {
+		return PitStop{}, false
+	}
+
+	d.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() || !t.Flags().Live {
+		d.stationary = false
+
+		return PitStop{}, false
+	}
+
+	stationary := t.GroundSpeedMetresPerSecond() < pitStopStationarySpeed
+
+	switch {
+	case stationary && !d.stationary:
+		d.current = PitStop{Lap: t.CurrentLap(), startSequence: sequenceID}
+		d.fuelAtStop = t.FuelLevel()
+		d.tyreTempStop = averageTyreTemperature(t)
+	case stationary:
+		if fuelAdded := t.FuelLevel() - d.fuelAtStop; fuelAdded > d.current.FuelAdded {
+			d.current.FuelAdded = fuelAdded
+		}
+
+		if d.tyreTempStop-averageTyreTemperature(t) >= pitStopTyreCooling {
+			d.current.TyresChanged = true
+		}
+	case d.stationary:
+		d.stationary = false
+
+		if d.current.FuelAdded < pitStopMinimumFuel && !d.current.TyresChanged {
+			return PitStop{}, false
+		}
+
+		d.current.SequenceID = sequenceID
+		d.current.Duration = framesElapsed(d.current.startSequence, sequenceID)
+		d.stops = append(d.stops, d.current)
+
+		return d.current, true
+	}
+
+	d.stationary = stationary
+
+	return PitStop{}, false
+}
+
+// Stops returns all pit stops detected so far.
+func (d *PitStopDetector) Stops() []PitStop {
+	return d.stops
+}
+
+func averageTyreTemperature(t *Transformer) float32 {
+	temps := t.TyreTemperatureCelsius()
+
+	return (temps.FrontLeft + temps.FrontRight + temps.RearLeft + temps.RearRight) / 4
+}
+
+// PitLossComparison compares the time lost to a pit stop with the time expected to be lost, so that strategy
+// decisions can use measured rather than estimated pit stop costs.
+type PitLossComparison struct {
+	Lap int16 `json:"lap"`
+	// Total is the time lost to the stop, from the laps around the stop compared with the median lap of the session.
+	Total time.Duration `json:"total"`
+	// Stationary is the time spent stopped in the pit box.
+	Stationary time.Duration `json:"stationary"`
+	// PitLane is the time lost driving through the pit lane, the total excluding the time stationary.
+	PitLane time.Duration `json:"pitLane"`
+	// Expected is the expected pit lane loss for the circuit, zero when unknown.
+	Expected time.Duration `json:"expected"`
+	// Delta is how much more time was lost in the pit lane than expected, zero when the expected loss is unknown.
+	Delta time.Duration `json:"delta"`
+}
+
+// ComparePitLoss measures the time lost to a pit stop from the lap times of a lap history and compares it with the
+// expected pit lane loss of the circuit, as returned by circuits.CircuitDB.PitLossSeconds. Laps either side of the
+// stop lap are included, as the pit lane usually crosses the timing line. It returns false until the lap after the
+// stop has been completed, or when there are no other laps to compare with.
+func ComparePitLoss(stop PitStop, snapshots []LapSnapshot, expected time.Duration) (PitLossComparison, bool) {
+	var (
+		affected      time.Duration
+		affectedCount int
+		afterStop     bool
+		reference     = make([]time.Duration, 0, len(snapshots))
+	)
+
+	for _, snapshot := range snapshots {
+		if snapshot.Laptime <= 0 {
+			continue
+		}
+
+		distance := snapshot.Lap - stop.Lap
+		if distance < -pitLossWindow || distance > pitLossWindow {
+			reference = append(reference, snapshot.Laptime)
+
+			continue
+		}
+
+		affected += snapshot.Laptime
+		affectedCount++
+
+		if distance > 0 {
+			afterStop = true
+		}
+	}
+
+	if !afterStop || len(reference) == 0 {
+		return PitLossComparison{}, false
+	}
+
+	slices.Sort(reference)
+	median := reference[len(reference)/2]
+
+	comparison := PitLossComparison{
+		Lap:        stop.Lap,
+		Total:      affected - time.Duration(affectedCount)*median,
+		Stationary: stop.Duration,
+		Expected:   expected,
+	}
+
+	comparison.PitLane = comparison.Total - comparison.Stationary
+
+	if expected > 0 {
+		comparison.Delta = comparison.PitLane - expected
+	}
+
+	return comparison, true
+}
@@ -0,0 +1,156 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type PitStopDetectorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	detector    *gttelemetry.PitStopDetector
+}
+
+func TestPitStopDetectorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(PitStopDetectorTestSuite))
+}
+
+func (suite *PitStopDetectorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 4
+	suite.transformer.RawTelemetry.FuelLevel = 10
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.transformer.SetTyreTemperature(80, 80, 80, 80)
+
+	suite.detector = gttelemetry.NewPitStopDetector()
+}
+
+func (suite *PitStopDetectorTestSuite) frame(speed float32) (gttelemetry.PitStop, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.GroundSpeed = speed
+
+	return suite.detector.Update(suite.transformer)
+}
+
+func (suite *PitStopDetectorTestSuite) TestRefuellingStopIsDetected() {
+	// Arrange
+	suite.frame(20)
+	suite.frame(0)
+
+	for range 299 {
+		suite.transformer.RawTelemetry.FuelLevel += 0.1
+		suite.frame(0)
+	}
+
+	// Act
+	stop, ok := suite.frame(5)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(int16(4), stop.Lap)
+	suite.Equal(5*time.Second, stop.Duration)
+	suite.InDelta(29.9, stop.FuelAdded, 0.01)
+	suite.False(stop.TyresChanged)
+	suite.Len(suite.detector.Stops(), 1)
+}
+
+func (suite *PitStopDetectorTestSuite) TestTyreChangeIsDetected() {
+	// Arrange
+	suite.frame(20)
+	suite.frame(0)
+	suite.transformer.SetTyreTemperature(40, 40, 40, 40)
+	suite.frame(0)
+
+	// Act
+	stop, ok := suite.frame(5)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.True(stop.TyresChanged)
+}
+
+func (suite *PitStopDetectorTestSuite) TestStopWithoutServiceIsIgnored() {
+	// Arrange
+	suite.frame(20)
+
+	for range 60 {
+		suite.frame(0)
+	}
+
+	// Act
+	_, ok := suite.frame(5)
+
+	// Assert
+	suite.False(ok)
+	suite.Empty(suite.detector.Stops())
+}
+
+func (suite *PitStopDetectorTestSuite) TestComparePitLoss() {
+	second := time.Second
+	stop := gttelemetry.PitStop{Lap: 3, Duration: 8 * second}
+	laps := func(laptimes ...time.Duration) []gttelemetry.LapSnapshot {
+		snapshots := make([]gttelemetry.LapSnapshot, 0, len(laptimes))
+		for i, laptime := range laptimes {
+			snapshots = append(snapshots, gttelemetry.LapSnapshot{Lap: int16(i + 1), Laptime: laptime})
+		}
+
+		return snapshots
+	}
+
+	tests := map[string]struct {
+		snapshots []gttelemetry.LapSnapshot
+		expected  time.Duration
+		want      gttelemetry.PitLossComparison
+		wantOK    bool
+	}{
+		"loss split between in and out laps": {
+			snapshots: laps(90*second, 91*second, 110*second, 102*second, 90*second, 89*second),
+			expected:  20 * second,
+			want: gttelemetry.PitLossComparison{
+				Lap:        3,
+				Total:      33 * second,
+				Stationary: 8 * second,
+				PitLane:    25 * second,
+				Expected:   20 * second,
+				Delta:      5 * second,
+			},
+			wantOK: true,
+		},
+		"expected loss unknown": {
+			snapshots: laps(90*second, 90*second, 120*second, 90*second, 90*second),
+			want: gttelemetry.PitLossComparison{
+				Lap:        3,
+				Total:      30 * second,
+				Stationary: 8 * second,
+				PitLane:    22 * second,
+			},
+			wantOK: true,
+		},
+		"out lap not completed": {
+			snapshots: laps(90*second, 90*second, 120*second),
+		},
+		"no laps to compare with": {
+			snapshots: laps(0, 95*second, 120*second, 100*second),
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got, ok := gttelemetry.ComparePitLoss(stop, test.snapshots, test.expected)
+
+			// Assert
+			suite.Equal(test.wantOK, ok)
+			suite.Equal(test.want, got)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package circuits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// CapturedCoordinates holds the full precision coordinates recorded during a circuit capture.
+type CapturedCoordinates struct {
+	Circuit      []models.Coordinate `json:"circuit"`
+	StartingLine models.Coordinate   `json:"startingLine"`
+}
+
+// CapturedCircuit is a circuit capture file as written by the circuit_capture tool. Unlike the inventory, captures
+// keep every coordinate of the lap in driving order, so they can be used as a path along the circuit.
+type CapturedCircuit struct {
+	Schema        string              `json:"$schema"`
+	Name          string              `json:"name"`
+	VariationName string              `json:"variationName"`
+	Default       bool                `json:"default"`
+	CountryCode   string              `json:"country"`
+	LengthMetres  int                 `json:"lengthMetres"`
+	LastModified  string              `json:"lastModified"`
+	Coordinates   CapturedCoordinates `json:"coordinates"`
+}
+
+// LoadCapturedCircuit reads a circuit capture file.
+func LoadCapturedCircuit(path string) (*CapturedCircuit, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read captured circuit %s: %w", path, err)
+	}
+
+	var captured CapturedCircuit
+
+	err = json.Unmarshal(data, &captured)
+	if err != nil {
+		return nil, fmt.Errorf("parse captured circuit %s: %w", path, err)
+	}
+
+	return &captured, nil
+}
@@ -0,0 +1,65 @@
+package circuits_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type CaptureTestSuite struct {
+	suite.Suite
+}
+
+func TestCaptureTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CaptureTestSuite))
+}
+
+func (suite *CaptureTestSuite) TestLoadCapturedCircuitReadsCoordinates() {
+	// Arrange
+	path := filepath.Join(suite.T().TempDir(), "capture.json")
+	content := `{
+		"name": "Test Circuit",
+		"variationName": "Test Circuit Full",
+		"default": true,
+		"country": "gb",
+		"lengthMetres": 1000,
+		"lastModified": "2025-01-01T00:00:00Z",
+		"coordinates": {
+			"circuit": [{"x": 1.5, "y": 2, "z": -3}, {"x": 2.5, "y": 2, "z": -4}],
+			"startingLine": {"x": 1, "y": 2, "z": 3}
+		}
+	}`
+	suite.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+
+	// Act
+	captured, err := circuits.LoadCapturedCircuit(path)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("Test Circuit Full", captured.VariationName)
+	suite.Equal("gb", captured.CountryCode)
+	suite.Equal([]models.Coordinate{{X: 1.5, Y: 2, Z: -3}, {X: 2.5, Y: 2, Z: -4}}, captured.Coordinates.Circuit)
+	suite.Equal(models.Coordinate{X: 1, Y: 2, Z: 3}, captured.Coordinates.StartingLine)
+}
+
+func (suite *CaptureTestSuite) TestLoadCapturedCircuitReturnsErrorForMissingFile() {
+	// Act
+	_, err := circuits.LoadCapturedCircuit(filepath.Join(suite.T().TempDir(), "missing.json"))
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *CaptureTestSuite) TestLoadCapturedCircuitReadsRepositoryCapture() {
+	// Act
+	captured, err := circuits.LoadCapturedCircuit("../../data/circuits/alsace_village.json")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(captured.Coordinates.Circuit)
+}
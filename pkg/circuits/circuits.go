@@ -7,10 +7,12 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
-	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
 	"github.com/zetetos/gt-telemetry/v2/pkg/models"
 )
 
@@ -24,6 +26,34 @@ const (
 	startLineCoorindateResolutionZ = 16
 )
 
+// Resolution is the size in metres along each axis of the grid that coordinates are normalised to for location
+// matching.
+type Resolution struct {
+	X int16 `json:"x"`
+	Y int16 `json:"y"`
+	Z int16 `json:"z"`
+}
+
+// DefaultCircuitResolution is the resolution of circuit coordinates when a circuit does not set one.
+func DefaultCircuitResolution() Resolution {
+	return Resolution{X: circuitCoorindateResolutionX, Y: circuitCoorindateResolutionY, Z: circuitCoorindateResolutionZ}
+}
+
+// DefaultStartLineResolution is the resolution of the start line when a circuit does not set one.
+func DefaultStartLineResolution() Resolution {
+	return Resolution{X: startLineCoorindateResolutionX, Y: startLineCoorindateResolutionY, Z: startLineCoorindateResolutionZ}
+}
+
+// Normalise normalises a coordinate to the grid of the resolution.
+func (r Resolution) Normalise(coordinate models.Coordinate) models.CoordinateNorm {
+	return coordinate.Normalise(r.X, r.Y, r.Z)
+}
+
+// String returns a string representation of the Resolution of the form "x:<X>,y:<Y>,z:<Z>".
+func (r Resolution) String() string {
+	return fmt.Sprintf("x:%d,y:%d,z:%d", r.X, r.Y, r.Z)
+}
+
 // CircuitInfo represents information about a specific race circuit.
 type CircuitInfo struct {
 	ID                    string                  `json:"id"`
@@ -33,44 +63,83 @@ type CircuitInfo struct {
 	Default               bool                    `json:"default"`
 	Length                int                     `json:"length"`
 	StartLine             models.CoordinateNorm   `json:"startLine"`
+	Reverse               bool                    `json:"reverse,omitempty"`              // driven the opposite way around the forward layout
+	ForwardID             string                  `json:"forwardId,omitempty"`            // ID of the forward layout of a reverse layout
+	CoordinateResolution  *Resolution             `json:"coordinateResolution,omitempty"` // grid of Coordinates, nil for the default
+	StartLineResolution   *Resolution             `json:"startLineResolution,omitempty"`  // grid of StartLine, nil for the default
+	PitLoss               float64                 `json:"pitLossSeconds,omitempty"`       // pit lane time loss, zero when not measured
+	Anchor                *geo.Anchor             `json:"anchor,omitempty"`               // real-world location, nil for fictional circuits
 	LastModified          time.Time               `json:"lastModified"`
 	Coordinates           []models.CoordinateNorm `json:"coordinates"`
 	UniqueCoordinateCount int                     `json:"-"`
 }
 
+// CircuitResolution returns the resolution of the circuit coordinates.
+func (c CircuitInfo) CircuitResolution() Resolution {
+	if c.CoordinateResolution == nil {
+		return DefaultCircuitResolution()
+	}
+
+	return *c.CoordinateResolution
+}
+
+// StartResolution returns the resolution of the start line.
+func (c CircuitInfo) StartResolution() Resolution {
+	if c.StartLineResolution == nil {
+		return DefaultStartLineResolution()
+	}
+
+	return *c.StartLineResolution
+}
+
 // circuitInventory holds the lookup maps and circuit metadata built at load time.
 type circuitInventory struct {
-	coordinates map[string]string      // normalised coord string → circuitID (unique coords only)
-	startLines  map[string][]string    // start coord string → []circuitID
-	circuits    map[string]CircuitInfo // circuitID → metadata (coordinates nil after map building)
+	coordinates map[string]string          // grid key → circuitID (unique coords only)
+	startLines  map[string][]string        // start line grid key → []circuitID
+	circuits    map[string]CircuitInfo     // circuitID → metadata (coordinates nil after map building)
+	directions  map[string][]cellDirection // shared grid key → direction each circuit is driven through it
+	maps        map[string]TrackMap        // circuitID → outline of the circuit for drawing its map
+	// coordinateResolutions and startLineResolutions list the distinct resolutions used by the circuits, default first
+	coordinateResolutions []Resolution
+	startLineResolutions  []Resolution
 }
 
 // CircuitDB provides thread-safe access to circuit information loaded from embedded and cached data.
 type CircuitDB struct {
 	mu             sync.RWMutex
 	inventory      *circuitInventory
+	custom         map[string]CircuitInfo
 	latestModified time.Time
 	fetcher        Fetcher
 	cacheDir       string
 	updateBaseURL  string
 	cancel         context.CancelFunc
-	log            *zerolog.Logger
+	log            *logging.Logger
 }
 
 // CircuitDBOptions configures optional behaviour for CircuitDB.
 type CircuitDBOptions struct {
 	CacheDir      string
+	CustomDir     string // directory of user circuit files layered over the embedded and cached inventory
 	UpdateBaseURL string
-	Logger        *zerolog.Logger
+	Logger        *logging.Logger // a *zerolog.Logger, except in TinyGo builds
+	// SkipEmbedded leaves out the embedded inventory, so only cached and custom circuits are known.
+	SkipEmbedded bool
 }
 
 // NewDB creates a new CircuitDB by loading circuits from the embedded inventory files,
 // overlaid by any cached circuit files found in opts.CacheDir. If opts.CacheDir is empty,
 // only embedded circuits are loaded. The returned CircuitDB is immediately usable.
 func NewDB(opts CircuitDBOptions) (*CircuitDB, error) {
-	circuits, err := loadFromFS(embeddedInventoryFS, "inventory")
-	if err != nil {
-		return nil, err
+	circuits := map[string]CircuitInfo{}
+
+	if !opts.SkipEmbedded {
+		var err error
+
+		circuits, err = loadFromFS(embeddedInventoryFS, "inventory")
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	inventory := buildLookupMaps(circuits)
@@ -88,11 +157,11 @@ func NewDB(opts CircuitDBOptions) (*CircuitDB, error) {
 		}
 	}
 
-	var logger zerolog.Logger
+	var logger logging.Logger
 	if opts.Logger == nil {
-		logger = zerolog.Nop().With().Str("component", "circuit db").Logger()
+		logger = logging.Nop()
 	} else {
-		logger = opts.Logger.With().Str("component", "circuit db").Logger()
+		logger = logging.WithComponent(*opts.Logger, "circuit db")
 	}
 
 	circuitDB := &CircuitDB{
@@ -105,6 +174,13 @@ func NewDB(opts CircuitDBOptions) (*CircuitDB, error) {
 	circuitDB.loadCacheDir()
 	circuitDB.updateLatestModified()
 
+	if opts.CustomDir != "" {
+		err := circuitDB.loadCustomDir(opts.CustomDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return circuitDB, nil
 }
 
@@ -144,48 +220,115 @@ func (db *CircuitDB) LatestModified() time.Time {
 	return db.latestModified
 }
 
-// GetCircuitAtCoordinate returns the circuit at a given coordinate.
+// GetCircuitAtCoordinate returns the circuit at a given coordinate. IdentifyStartLine and Identify return the
+// alternatives and the reason a coordinate is not found.
 func (db *CircuitDB) GetCircuitAtCoordinate(coordinate models.Coordinate, coordType models.CoordinateType) (circuitID string, found bool) {
+	var (
+		ident Identification
+		err   error
+	)
+
+	if coordType == models.CoordinateTypeStartLine {
+		ident, err = db.IdentifyStartLine(coordinate)
+	} else {
+		ident, err = db.Identify(coordinate, models.Vector{})
+	}
+
+	return ident.CircuitID, err == nil
+}
+
+// GetCircuitByID retrieves a CircuitInfo by its ID.
+func (db *CircuitDB) GetCircuitByID(circuitID string) (circuit CircuitInfo, found bool) {
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 
 	if db.inventory == nil {
-		return "", false
+		return CircuitInfo{}, false
 	}
 
-	if coordType == models.CoordinateTypeStartLine {
-		normalisedPos := NormaliseStartLineCoordinate(coordinate)
-		key := normalisedPos.String()
+	circuit, found = db.inventory.circuits[circuitID]
+	circuit.ID = circuitID
 
-		circuitIDs, ok := db.inventory.startLines[key]
-		if ok && len(circuitIDs) == 1 {
-			return circuitIDs[0], true
-		}
+	return circuit, found
+}
 
-		return "", false
+// GetForwardLayout returns the forward layout of a reverse layout. It is not found when the circuit is not a reverse
+// layout or its forward layout is not in the inventory.
+func (db *CircuitDB) GetForwardLayout(circuitID string) (circuit CircuitInfo, found bool) {
+	reverse, found := db.GetCircuitByID(circuitID)
+	if !found || !reverse.Reverse {
+		return CircuitInfo{}, false
 	}
 
-	normalisedPos := NormaliseCircuitCoordinate(coordinate)
-	key := normalisedPos.String()
-
-	circuitID, found = db.inventory.coordinates[key]
+	circuit, found = db.GetCircuitByID(reverse.ForwardID)
+	if !found {
+		return CircuitInfo{}, false
+	}
 
-	return circuitID, found
+	return circuit, true
 }
 
-// GetCircuitByID retrieves a CircuitInfo by its ID.
-func (db *CircuitDB) GetCircuitByID(circuitID string) (circuit CircuitInfo, found bool) {
+// GetReverseLayoutIDs returns the IDs of the reverse layouts of a forward layout.
+func (db *CircuitDB) GetReverseLayoutIDs(circuitID string) (circuitIDs []string) {
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 
 	if db.inventory == nil {
-		return CircuitInfo{}, false
+		return nil
 	}
 
-	circuit, found = db.inventory.circuits[circuitID]
-	circuit.ID = circuitID
+	for id, circuit := range db.inventory.circuits {
+		if circuit.Reverse && circuit.ForwardID == circuitID {
+			circuitIDs = append(circuitIDs, id)
+		}
+	}
 
-	return circuit, found
+	slices.Sort(circuitIDs)
+
+	return circuitIDs
+}
+
+// DisplayVariation returns the variation of a circuit for display, which for a reverse layout is the variation of
+// its forward layout followed by "(Reversed)", e.g. "Tokyo Expressway - Central Clockwise (Reversed)". It is empty
+// when the circuit is not found.
+func (db *CircuitDB) DisplayVariation(circuitID string) string {
+	circuit, found := db.GetCircuitByID(circuitID)
+	if !found {
+		return ""
+	}
+
+	if forward, found := db.GetForwardLayout(circuitID); found {
+		return forward.Variation + " (Reversed)"
+	}
+
+	return circuit.Variation
+}
+
+// GetAnchorAtCoordinate returns the real-world location of the circuit at a map coordinate, for synthesising GPS
+// positions. It is not found when the coordinate does not identify a circuit or the circuit is fictional.
+func (db *CircuitDB) GetAnchorAtCoordinate(coordinate models.Coordinate) (anchor geo.Anchor, found bool) {
+	circuitID, found := db.GetCircuitAtCoordinate(coordinate, models.CoordinateTypeCircuit)
+	if !found {
+		return geo.Anchor{}, false
+	}
+
+	circuit, found := db.GetCircuitByID(circuitID)
+	if !found || circuit.Anchor == nil {
+		return geo.Anchor{}, false
+	}
+
+	return *circuit.Anchor, true
+}
+
+// PitLossSeconds returns the time lost by driving through the pit lane of a circuit instead of staying on track,
+// excluding the time stationary in the pit box. It is not found when the loss has not been measured.
+func (db *CircuitDB) PitLossSeconds(circuitID string) (seconds float64, found bool) {
+	circuit, found := db.GetCircuitByID(circuitID)
+	if !found || circuit.PitLoss <= 0 {
+		return 0, false
+	}
+
+	return circuit.PitLoss, true
 }
 
 // GetAllCircuitIDs returns all available circuit IDs.
@@ -218,27 +361,31 @@ func (db *CircuitDB) updateLatestModified() {
 	db.latestModified = latest
 }
 
-// NormaliseStartLineCoordinate normalises a start line coordinate to reduce precision for location matching.
+// NormaliseStartLineCoordinate normalises a start line coordinate to the default start line grid to reduce precision
+// for location matching.
 func NormaliseStartLineCoordinate(coordinate models.Coordinate) (normalised models.CoordinateNorm) {
 	// The FIA rules state that the starting grid has a min width of 15 metres.
 	// 32m resolution should provide sufficient accuracy for most tracks.
-	return coordinate.Normalise(
-		startLineCoorindateResolutionX,
-		startLineCoorindateResolutionY,
-		startLineCoorindateResolutionZ,
-	)
+	return DefaultStartLineResolution().Normalise(coordinate)
 }
 
-// NormaliseCircuitCoordinate normalises a circuit coordinate to reduce precision for location matching.
+// NormaliseCircuitCoordinate normalises a circuit coordinate to the default circuit grid to reduce precision for
+// location matching.
 func NormaliseCircuitCoordinate(coordinate models.Coordinate) (normalised models.CoordinateNorm) {
 	// Track map resultion is lower to reduce file size.
 	// 64m resolution should be sufficient for most tracks.
 	// Y (vertical) resolution is higher since elevation changes are much smaller than X/Z.
-	return coordinate.Normalise(
-		circuitCoorindateResolutionX,
-		circuitCoorindateResolutionY,
-		circuitCoorindateResolutionZ,
-	)
+	return DefaultCircuitResolution().Normalise(coordinate)
+}
+
+// gridKey returns the lookup key of a normalised coordinate, qualified by its resolution when that is not the default
+// so that the same values on different grids are kept apart.
+func gridKey(coordinate models.CoordinateNorm, resolution, defaultResolution Resolution) string {
+	if resolution == defaultResolution {
+		return coordinate.String()
+	}
+
+	return coordinate.String() + "@" + resolution.String()
 }
 
 // loadCacheDir scans the cache directory and merges any cached circuit JSON files into the inventory.
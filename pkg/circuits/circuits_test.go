@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
 	"github.com/zetetos/gt-telemetry/v2/pkg/models"
 )
 
@@ -120,6 +121,210 @@ func (suite *CircuitsTestSuite) TestGetCircuitAtStartLineWithValidCoordinateRetu
 	suite.Equal("TestCircuit", got)
 }
 
+func (suite *CircuitsTestSuite) TestGetCircuitAtStartLineUsesCircuitResolution() {
+	// Arrange
+	// Both start lines normalise to {X: 240, Y: -2, Z: 608} on the default grid, but not on an 8m grid
+	fine := circuits.Resolution{X: 8, Y: 2, Z: 8}
+	testDB := circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"Clockwise": {
+			StartLine:           models.CoordinateNorm{X: 240, Y: -2, Z: 608},
+			StartLineResolution: &fine,
+		},
+		"Counterclockwise": {
+			StartLine:           models.CoordinateNorm{X: 248, Y: -2, Z: 616},
+			StartLineResolution: &fine,
+		},
+	})
+
+	tests := map[string]struct {
+		coordinate models.Coordinate
+		want       string
+	}{
+		"first layout":  {coordinate: models.Coordinate{X: 243, Y: -2.4, Z: 610}, want: "Clockwise"},
+		"second layout": {coordinate: models.Coordinate{X: 251, Y: -2.4, Z: 619}, want: "Counterclockwise"},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got, found := testDB.GetCircuitAtCoordinate(test.coordinate, models.CoordinateTypeStartLine)
+
+			// Assert
+			suite.True(found)
+			suite.Equal(test.want, got)
+		})
+	}
+}
+
+func (suite *CircuitsTestSuite) TestGetCircuitAtCoordinateSharedAcrossResolutionsReturnsNotFound() {
+	// Arrange
+	fine := circuits.Resolution{X: 8, Y: 2, Z: 8}
+	testDB := circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"Coarse": {Coordinates: []models.CoordinateNorm{{X: 128, Y: 8, Z: 192}}},
+		"Fine": {
+			CoordinateResolution: &fine,
+			Coordinates:          []models.CoordinateNorm{{X: 136, Y: 8, Z: 192}},
+		},
+	})
+
+	// Act
+	_, found := testDB.GetCircuitAtCoordinate(models.Coordinate{X: 138, Y: 8, Z: 193}, models.CoordinateTypeCircuit)
+
+	// Assert
+	suite.False(found, "Coordinate is claimed by both circuits")
+}
+
+func (suite *CircuitsTestSuite) TestEmbeddedTokyoSouthLayoutsHaveSeparateStartLines() {
+	// Arrange
+	testDB, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	tests := map[string]models.Coordinate{
+		"TokyoExpresswaySouthClockwise":        {X: -303.71, Y: 0.02, Z: 23.56},
+		"TokyoExpresswaySouthCounterclockwise": {X: -289.35, Y: 0.01, Z: 18.81},
+	}
+
+	for want, coordinate := range tests {
+		suite.Run(want, func() {
+			// Act
+			got, found := testDB.GetCircuitAtCoordinate(coordinate, models.CoordinateTypeStartLine)
+
+			// Assert
+			suite.True(found)
+			suite.Equal(want, got)
+		})
+	}
+}
+
+func (suite *CircuitsTestSuite) TestGetAnchorAtCoordinate() {
+	// Arrange
+	anchor := geo.Anchor{Latitude: 34.8431, Longitude: 136.5407, X: 544, Z: -144}
+	testDB := circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"RealCircuit": {
+			Anchor:      &anchor,
+			Coordinates: []models.CoordinateNorm{{X: 128, Y: 8, Z: 192}},
+		},
+		"FictionalCircuit": {
+			Coordinates: []models.CoordinateNorm{{X: 512, Y: 8, Z: 512}},
+		},
+	})
+
+	tests := map[string]struct {
+		coordinate models.Coordinate
+		wantFound  bool
+	}{
+		"real circuit": {
+			coordinate: models.Coordinate{X: 130, Y: 8.5, Z: 200},
+			wantFound:  true,
+		},
+		"fictional circuit": {
+			coordinate: models.Coordinate{X: 520, Y: 8.5, Z: 520},
+		},
+		"unknown coordinate": {
+			coordinate: models.Coordinate{X: -4000, Y: 0, Z: -4000},
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got, found := testDB.GetAnchorAtCoordinate(test.coordinate)
+
+			// Assert
+			suite.Equal(test.wantFound, found)
+
+			if test.wantFound {
+				suite.Equal(anchor, got)
+			}
+		})
+	}
+}
+
+func (suite *CircuitsTestSuite) TestEmbeddedInventoryHasAnchorsForRealCircuits() {
+	// Arrange
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	// Act
+	suzuka, _ := db.GetCircuitByID("SuzukaCircuit")
+	deepForest, _ := db.GetCircuitByID("DeepForestRaceway")
+
+	// Assert
+	suite.Require().NotNil(suzuka.Anchor)
+	suite.InDelta(34.84, suzuka.Anchor.Latitude, 0.01)
+	suite.InDelta(float64(suzuka.StartLine.X), suzuka.Anchor.X, 0.001)
+	suite.Nil(deepForest.Anchor)
+}
+
+func (suite *CircuitsTestSuite) TestPitLossSeconds() {
+	// Arrange
+	testDB := circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"Measured":   {PitLoss: 21.5},
+		"Unmeasured": {},
+	})
+
+	// Act
+	measured, measuredFound := testDB.PitLossSeconds("Measured")
+	_, unmeasuredFound := testDB.PitLossSeconds("Unmeasured")
+	_, unknownFound := testDB.PitLossSeconds("Unknown")
+
+	// Assert
+	suite.True(measuredFound)
+	suite.InDelta(21.5, measured, 1e-9)
+	suite.False(unmeasuredFound)
+	suite.False(unknownFound)
+}
+
+func (suite *CircuitsTestSuite) TestReverseLayouts() {
+	// Arrange
+	testDB := circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"Forward":  {ID: "Forward", Variation: "Forward Layout"},
+		"Reverse":  {ID: "Reverse", Variation: "Reverse Layout", Reverse: true, ForwardID: "Forward"},
+		"Orphaned": {ID: "Orphaned", Variation: "Orphaned Layout", Reverse: true, ForwardID: "Missing"},
+	})
+
+	tests := map[string]struct {
+		circuitID        string
+		wantForward      string
+		wantReverses     []string
+		wantDisplayed    string
+		wantForwardFound bool
+	}{
+		"forward layout":              {circuitID: "Forward", wantReverses: []string{"Reverse"}, wantDisplayed: "Forward Layout"},
+		"reverse layout":              {circuitID: "Reverse", wantForward: "Forward", wantForwardFound: true, wantDisplayed: "Forward Layout (Reversed)"},
+		"reverse of a missing layout": {circuitID: "Orphaned", wantDisplayed: "Orphaned Layout"},
+		"unknown circuit":             {circuitID: "Unknown"},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			forward, found := testDB.GetForwardLayout(test.circuitID)
+
+			// Assert
+			suite.Equal(test.wantForwardFound, found)
+			suite.Equal(test.wantForward, forward.ID)
+			suite.Equal(test.wantReverses, testDB.GetReverseLayoutIDs(test.circuitID))
+			suite.Equal(test.wantDisplayed, testDB.DisplayVariation(test.circuitID))
+		})
+	}
+}
+
+func (suite *CircuitsTestSuite) TestEmbeddedInventoryLinksTokyoReverseLayouts() {
+	// Arrange
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	// Act
+	forward, found := db.GetForwardLayout("TokyoExpresswayCentralCounterclockwise")
+
+	// Assert
+	suite.True(found)
+	suite.Equal("TokyoExpresswayCentralClockwise", forward.ID)
+	suite.Equal([]string{"TokyoExpresswaySouthCounterclockwise"}, db.GetReverseLayoutIDs("TokyoExpresswaySouthClockwise"))
+	suite.Equal("Tokyo Expressway - East Clockwise (Reversed)", db.DisplayVariation("TokyoExpresswayEastCounterclockwise"))
+}
+
 func (suite *CircuitsTestSuite) TestGetAllCircuitIDsReturnsAllIDs() {
 	// Arrange
 	testDB := circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
@@ -0,0 +1,78 @@
+package circuits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	ErrCircuitIDRequired         = errors.New("circuit ID is required")
+	ErrCircuitCoordinatesMissing = errors.New("circuit has no coordinates")
+)
+
+// Merge layers a user circuit over the inventory, replacing any circuit with the same ID. The circuit JSON uses the
+// inventory file format, as written by the circuit_inventory tool. Custom circuits take precedence over embedded,
+// cached and downloaded circuits, and are kept when the inventory is updated.
+func (db *CircuitDB) Merge(circuitJSON []byte) error {
+	var circuit CircuitInfo
+
+	err := json.Unmarshal(circuitJSON, &circuit)
+	if err != nil {
+		return fmt.Errorf("parsing custom circuit: %w", err)
+	}
+
+	if circuit.ID == "" {
+		return ErrCircuitIDRequired
+	}
+
+	err = db.mergeCircuits(map[string]CircuitInfo{circuit.ID: circuit})
+	if err != nil {
+		return err
+	}
+
+	db.log.Info().Str("circuit_id", circuit.ID).Msg("merged custom circuit")
+
+	return nil
+}
+
+// loadCustomDir merges the circuit files in the directory over the inventory.
+func (db *CircuitDB) loadCustomDir(customDir string) error {
+	circuits, err := loadFromFS(os.DirFS(customDir), ".")
+	if err != nil {
+		return fmt.Errorf("loading custom circuits: %w", err)
+	}
+
+	err = db.mergeCircuits(circuits)
+	if err != nil {
+		return err
+	}
+
+	db.log.Info().Int("count", len(circuits)).Str("path", customDir).Msg("loaded custom circuits")
+
+	return nil
+}
+
+// mergeCircuits validates the circuits and adds them to the custom circuits before rebuilding the inventory.
+func (db *CircuitDB) mergeCircuits(circuits map[string]CircuitInfo) error {
+	for id, circuit := range circuits {
+		if len(circuit.Coordinates) == 0 {
+			return fmt.Errorf("%w: %s", ErrCircuitCoordinatesMissing, id)
+		}
+	}
+
+	db.mu.Lock()
+
+	if db.custom == nil {
+		db.custom = make(map[string]CircuitInfo, len(circuits))
+	}
+
+	for id, circuit := range circuits {
+		db.custom[id] = circuit
+	}
+
+	db.mu.Unlock()
+
+	return db.rebuildInventory()
+}
@@ -0,0 +1,173 @@
+package circuits_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type CustomCircuitsTestSuite struct {
+	suite.Suite
+}
+
+func TestCustomCircuitsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CustomCircuitsTestSuite))
+}
+
+// customCircuit returns a circuit far from any circuit in the embedded inventory.
+func customCircuit(id string) circuits.CircuitInfo {
+	return circuits.CircuitInfo{
+		ID:           id,
+		Name:         "Custom Circuit",
+		Variation:    "Custom Circuit",
+		Country:      "au",
+		Length:       1000,
+		StartLine:    models.CoordinateNorm{X: 30000, Y: 0, Z: 30000},
+		LastModified: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Coordinates: []models.CoordinateNorm{
+			{X: 30000, Y: 0, Z: 30000},
+			{X: 30016, Y: 0, Z: 30000},
+			{X: 30032, Y: 0, Z: 30000},
+		},
+	}
+}
+
+func (suite *CustomCircuitsTestSuite) TestMergeAddsCircuitToLookups() {
+	// Arrange
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	data, err := json.Marshal(customCircuit("CustomCircuit"))
+	suite.Require().NoError(err)
+
+	// Act
+	err = db.Merge(data)
+	suite.Require().NoError(err)
+
+	// Assert
+	got, found := db.GetCircuitByID("CustomCircuit")
+	suite.True(found)
+	suite.Equal("Custom Circuit", got.Name)
+
+	circuitID, found := db.GetCircuitAtCoordinate(models.Coordinate{X: 30020, Y: 0, Z: 30004}, models.CoordinateTypeCircuit)
+	suite.True(found)
+	suite.Equal("CustomCircuit", circuitID)
+
+	circuitID, found = db.GetCircuitAtCoordinate(models.Coordinate{X: 30001, Y: 0, Z: 30001}, models.CoordinateTypeStartLine)
+	suite.True(found)
+	suite.Equal("CustomCircuit", circuitID)
+}
+
+func (suite *CustomCircuitsTestSuite) TestMergeReplacesEmbeddedCircuit() {
+	// Arrange
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	want, err := circuits.EmbeddedInventoryCount()
+	suite.Require().NoError(err)
+
+	data, err := json.Marshal(customCircuit("HighSpeedRing"))
+	suite.Require().NoError(err)
+
+	// Act
+	err = db.Merge(data)
+	suite.Require().NoError(err)
+
+	// Assert
+	got, found := db.GetCircuitByID("HighSpeedRing")
+	suite.True(found)
+	suite.Equal("Custom Circuit", got.Name)
+	suite.Len(db.GetAllCircuitIDs(), want)
+}
+
+func (suite *CustomCircuitsTestSuite) TestMergeRejectsInvalidCircuits() {
+	noCoordinates := customCircuit("NoCoordinates")
+	noCoordinates.Coordinates = nil
+
+	noCoordinatesJSON, err := json.Marshal(noCoordinates)
+	suite.Require().NoError(err)
+
+	noIDJSON, err := json.Marshal(customCircuit(""))
+	suite.Require().NoError(err)
+
+	tests := map[string]struct {
+		circuitJSON []byte
+		wantErr     error
+	}{
+		"malformed JSON":    {circuitJSON: []byte(`{not json`)},
+		"missing ID":        {circuitJSON: noIDJSON, wantErr: circuits.ErrCircuitIDRequired},
+		"missing positions": {circuitJSON: noCoordinatesJSON, wantErr: circuits.ErrCircuitCoordinatesMissing},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+			suite.Require().NoError(err)
+
+			// Act
+			err = db.Merge(test.circuitJSON)
+
+			// Assert
+			suite.Require().Error(err)
+
+			if test.wantErr != nil {
+				suite.Require().ErrorIs(err, test.wantErr)
+			}
+		})
+	}
+}
+
+func (suite *CustomCircuitsTestSuite) TestCustomDirIsLoaded() {
+	// Arrange
+	customDir := suite.T().TempDir()
+
+	data, err := json.Marshal(customCircuit("CustomCircuit"))
+	suite.Require().NoError(err)
+
+	err = os.WriteFile(filepath.Join(customDir, "CustomCircuit.json"), data, 0o600)
+	suite.Require().NoError(err)
+
+	// Act
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{CustomDir: customDir})
+	suite.Require().NoError(err)
+
+	// Assert
+	_, found := db.GetCircuitByID("CustomCircuit")
+	suite.True(found)
+}
+
+func (suite *CustomCircuitsTestSuite) TestMissingCustomDirReturnsError() {
+	// Act
+	_, err := circuits.NewDB(circuits.CircuitDBOptions{CustomDir: "/nonexistent/path"})
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *CustomCircuitsTestSuite) TestCustomCircuitsSurviveInventoryRebuild() {
+	// Arrange
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	data, err := json.Marshal(customCircuit("CustomCircuit"))
+	suite.Require().NoError(err)
+
+	err = db.Merge(data)
+	suite.Require().NoError(err)
+
+	// Act
+	err = db.RebuildInventory()
+	suite.Require().NoError(err)
+
+	// Assert
+	_, found := db.GetCircuitByID("CustomCircuit")
+	suite.True(found)
+}
@@ -0,0 +1,31 @@
+//go:build tinygo
+
+package circuits
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUpdatesUnsupported indicates that circuit updates cannot be downloaded in TinyGo builds, which leave out net/http.
+var ErrUpdatesUnsupported = errors.New("circuit updates are not supported in TinyGo builds")
+
+// HTTPFetcher is not supported in TinyGo builds, every fetch returns ErrUpdatesUnsupported.
+type HTTPFetcher struct {
+	baseURL string
+}
+
+// NewHTTPFetcher creates a new HTTPFetcher pointed at the given base URL.
+func NewHTTPFetcher(baseURL string) *HTTPFetcher {
+	return &HTTPFetcher{baseURL: baseURL}
+}
+
+// FetchManifest returns ErrUpdatesUnsupported.
+func (f *HTTPFetcher) FetchManifest(_ context.Context) (*Manifest, error) {
+	return nil, ErrUpdatesUnsupported
+}
+
+// FetchCircuit returns ErrUpdatesUnsupported.
+func (f *HTTPFetcher) FetchCircuit(_ context.Context, _ string) (*CircuitInfo, error) {
+	return nil, ErrUpdatesUnsupported
+}
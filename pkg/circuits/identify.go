@@ -0,0 +1,231 @@
+package circuits
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// pathMatcherEvidence is the number of path positions, about 200 metres of driving, that PathMatcher.Identify weighs
+// the candidate circuits over.
+const pathMatcherEvidence = 50
+
+var (
+	// ErrNoInventory indicates an identification against a database without circuits.
+	ErrNoInventory = errors.New("no circuits loaded")
+	// ErrCircuitNotFound indicates a position that is not on any known circuit.
+	ErrCircuitNotFound = errors.New("no circuit at position")
+	// ErrCircuitAmbiguous indicates a position shared by several circuits, which are returned as the alternatives.
+	ErrCircuitAmbiguous = errors.New("position shared by several circuits")
+)
+
+// Method is the way a circuit was identified.
+type Method string
+
+const (
+	// MethodStartLine identifies the circuit from the position of the vehicle on the start line.
+	MethodStartLine Method = "startLine"
+	// MethodCoordinate identifies the circuit from a position only driven on one circuit.
+	MethodCoordinate Method = "coordinate"
+	// MethodDirection identifies the circuit from the direction of travel through a position shared by several
+	// circuits, such as a layout and its reverse.
+	MethodDirection Method = "direction"
+	// MethodPath identifies the circuit from the evidence of the recent path of the vehicle.
+	MethodPath Method = "path"
+)
+
+// Identification is the result of identifying the circuit being driven.
+type Identification struct {
+	// CircuitID is the circuit identified, empty while it cannot be told apart from the alternatives.
+	CircuitID string `json:"circuitId,omitempty"`
+	// Confidence is how sure the identification is, from 0 to 1. Positions identify a circuit with a confidence of
+	// 1, while the confidence of PathMatcher.Identify grows as the vehicle drives, for showing progress such as
+	// "Identifying… 63%".
+	Confidence float64 `json:"confidence"`
+	// Alternatives are the other circuits that may be being driven, most likely first.
+	Alternatives []string `json:"alternatives,omitempty"`
+	// Method is the way the circuit was identified.
+	Method Method `json:"method,omitempty"`
+}
+
+// Identified returns whether a circuit was identified.
+func (i Identification) Identified() bool {
+	return i.CircuitID != ""
+}
+
+// identified returns the identification of a single circuit, or ErrCircuitNotFound or ErrCircuitAmbiguous with the
+// candidates as the alternatives.
+func identified(candidates []string, method Method) (Identification, error) {
+	switch len(candidates) {
+	case 0:
+		return Identification{}, ErrCircuitNotFound
+	case 1:
+		return Identification{CircuitID: candidates[0], Confidence: 1, Method: method}, nil
+	default:
+		slices.Sort(candidates)
+
+		return Identification{Alternatives: candidates, Method: method}, ErrCircuitAmbiguous
+	}
+}
+
+// IdentifyStartLine identifies the circuit from a position on the start line. Start lines shared by several circuits
+// return ErrCircuitAmbiguous with the circuits as alternatives.
+func (db *CircuitDB) IdentifyStartLine(coordinate models.Coordinate) (Identification, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.inventory == nil {
+		return Identification{}, ErrNoInventory
+	}
+
+	var circuitIDs []string
+
+	for _, resolution := range db.inventory.startLineResolutions {
+		for _, id := range db.inventory.startLines[gridKey(resolution.Normalise(coordinate), resolution, DefaultStartLineResolution())] {
+			if !slices.Contains(circuitIDs, id) {
+				circuitIDs = append(circuitIDs, id)
+			}
+		}
+	}
+
+	return identified(circuitIDs, MethodStartLine)
+}
+
+// Identify identifies the circuit at a position, using the direction of travel to choose between circuits that
+// share the position, such as a layout and its reverse. Only the X and Z of the direction are used, it need not be
+// normalised, and a zero direction only identifies positions driven on a single circuit. Positions shared by circuits
+// driven in the same direction return ErrCircuitAmbiguous with the circuits as alternatives.
+func (db *CircuitDB) Identify(coordinate models.Coordinate, direction models.Vector) (Identification, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.inventory == nil {
+		return Identification{}, ErrNoInventory
+	}
+
+	var unique, shared, driven []string
+
+	length := math.Hypot(float64(direction.X), float64(direction.Z))
+
+	for _, resolution := range db.inventory.coordinateResolutions {
+		key := gridKey(resolution.Normalise(coordinate), resolution, DefaultCircuitResolution())
+
+		if id, ok := db.inventory.coordinates[key]; ok && !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+
+		for _, cell := range db.inventory.directions[key] {
+			if !slices.Contains(shared, cell.circuitID) {
+				shared = append(shared, cell.circuitID)
+			}
+
+			if length == 0 || slices.Contains(driven, cell.circuitID) {
+				continue
+			}
+
+			cosine := (cell.x*float64(direction.X) + cell.z*float64(direction.Z)) / length
+			if cosine >= pathMatchMinCosine {
+				driven = append(driven, cell.circuitID)
+			}
+		}
+	}
+
+	// Grids of different resolutions overlap, so a position on two circuits on different grids is shared
+	if len(unique) == 1 {
+		return identified(unique, MethodCoordinate)
+	}
+
+	if len(driven) == 1 {
+		return identified(driven, MethodDirection)
+	}
+
+	candidates := driven
+	if len(candidates) == 0 {
+		candidates = shared
+	}
+
+	for _, id := range unique {
+		if !slices.Contains(candidates, id) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	if length == 0 {
+		return identified(candidates, MethodCoordinate)
+	}
+
+	return identified(candidates, MethodDirection)
+}
+
+// Identify adds the position of the vehicle to the path and identifies the circuit from the evidence of the recent
+// path. Each position of the path is a vote for the circuits it may be on, and the circuit with the most votes is
+// identified once it has more than any other. The confidence grows with the lead of the circuit over its closest
+// alternative, reaching 1 after about 200 metres without doubt.
+func (m *PathMatcher) Identify(coordinate models.Coordinate) Identification {
+	direction, moved := m.advance(coordinate)
+	if !moved {
+		return m.tally()
+	}
+
+	ident, err := m.db.Identify(coordinate, direction)
+
+	var candidates []string
+
+	switch {
+	case err == nil:
+		candidates = []string{ident.CircuitID}
+	case errors.Is(err, ErrCircuitAmbiguous):
+		candidates = ident.Alternatives
+	}
+
+	if len(m.evidence) == pathMatcherEvidence {
+		m.evidence = slices.Delete(m.evidence, 0, 1)
+	}
+
+	m.evidence = append(m.evidence, candidates)
+
+	return m.tally()
+}
+
+// tally counts the votes of the evidence for each circuit.
+func (m *PathMatcher) tally() Identification {
+	votes := make(map[string]int)
+
+	for _, candidates := range m.evidence {
+		for _, id := range candidates {
+			votes[id]++
+		}
+	}
+
+	if len(votes) == 0 {
+		return Identification{}
+	}
+
+	ranked := make([]string, 0, len(votes))
+	for id := range votes {
+		ranked = append(ranked, id)
+	}
+
+	slices.SortFunc(ranked, func(a, b string) int {
+		return cmp.Or(cmp.Compare(votes[b], votes[a]), cmp.Compare(a, b))
+	})
+
+	lead := votes[ranked[0]]
+	if len(ranked) > 1 {
+		lead -= votes[ranked[1]]
+	}
+
+	if lead == 0 {
+		return Identification{Alternatives: ranked, Method: MethodPath}
+	}
+
+	ident := Identification{CircuitID: ranked[0], Confidence: float64(lead) / pathMatcherEvidence, Method: MethodPath}
+	if len(ranked) > 1 {
+		ident.Alternatives = ranked[1:]
+	}
+
+	return ident
+}
@@ -0,0 +1,195 @@
+package circuits_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type IdentifyTestSuite struct {
+	suite.Suite
+
+	db *circuits.CircuitDB
+}
+
+func TestIdentifyTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(IdentifyTestSuite))
+}
+
+func (suite *IdentifyTestSuite) SetupTest() {
+	// A square loop driven anticlockwise on the map, the same loop reversed with an extra unique coordinate, and a
+	// layout sharing the start line of the reversed loop
+	loop := []models.CoordinateNorm{
+		{X: 0, Z: 0}, {X: 16, Z: 0}, {X: 32, Z: 0}, {X: 32, Z: 16}, {X: 32, Z: 32}, {X: 16, Z: 32}, {X: 0, Z: 32}, {X: 0, Z: 16},
+	}
+
+	reversed := []models.CoordinateNorm{{X: -16, Z: 0}}
+	for i := len(loop) - 1; i >= 0; i-- {
+		reversed = append(reversed, loop[i])
+	}
+
+	suite.db = circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"Forward": {Coordinates: loop, StartLine: models.CoordinateNorm{X: 0, Z: 0}},
+		"Reverse": {Coordinates: reversed, StartLine: models.CoordinateNorm{X: 2048, Z: 0}},
+		"Short":   {Coordinates: []models.CoordinateNorm{{X: 512, Z: 512}}, StartLine: models.CoordinateNorm{X: 2048, Z: 0}},
+	})
+}
+
+// lap returns the positions of a lap of the forward loop, a metre apart.
+func lap() []models.Coordinate {
+	corners := []models.Coordinate{{X: 8, Z: 8}, {X: 40, Z: 8}, {X: 40, Z: 40}, {X: 8, Z: 40}}
+
+	var positions []models.Coordinate
+
+	for i, from := range corners {
+		to := corners[(i+1)%len(corners)]
+		for step := range 32 {
+			fraction := float32(step) / 32
+			positions = append(positions, models.Coordinate{
+				X: from.X + (to.X-from.X)*fraction,
+				Z: from.Z + (to.Z-from.Z)*fraction,
+			})
+		}
+	}
+
+	return positions
+}
+
+func (suite *IdentifyTestSuite) TestIdentify() {
+	// Arrange
+	tests := map[string]struct {
+		coordinate models.Coordinate
+		direction  models.Vector
+		want       circuits.Identification
+		wantErr    error
+	}{
+		"unique coordinate": {
+			coordinate: models.Coordinate{X: -20, Z: 4},
+			want:       circuits.Identification{CircuitID: "Reverse", Confidence: 1, Method: circuits.MethodCoordinate},
+		},
+		"direction of travel": {
+			coordinate: models.Coordinate{X: 20, Z: 4},
+			direction:  models.Vector{X: 1},
+			want:       circuits.Identification{CircuitID: "Forward", Confidence: 1, Method: circuits.MethodDirection},
+		},
+		"shared without direction": {
+			coordinate: models.Coordinate{X: 20, Z: 4},
+			want:       circuits.Identification{Alternatives: []string{"Forward", "Reverse"}, Method: circuits.MethodCoordinate},
+			wantErr:    circuits.ErrCircuitAmbiguous,
+		},
+		"across the circuit": {
+			coordinate: models.Coordinate{X: 20, Z: 4},
+			direction:  models.Vector{Z: 1},
+			want:       circuits.Identification{Alternatives: []string{"Forward", "Reverse"}, Method: circuits.MethodDirection},
+			wantErr:    circuits.ErrCircuitAmbiguous,
+		},
+		"off circuit": {
+			coordinate: models.Coordinate{X: 5000, Z: 5000},
+			direction:  models.Vector{X: 1},
+			wantErr:    circuits.ErrCircuitNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got, err := suite.db.Identify(test.coordinate, test.direction)
+
+			// Assert
+			suite.Require().ErrorIs(err, test.wantErr)
+			suite.Equal(test.want, got)
+		})
+	}
+}
+
+func (suite *IdentifyTestSuite) TestIdentifyStartLine() {
+	// Arrange
+	tests := map[string]struct {
+		coordinate models.Coordinate
+		want       circuits.Identification
+		wantErr    error
+	}{
+		"unique start line": {
+			coordinate: models.Coordinate{X: 1, Z: 1},
+			want:       circuits.Identification{CircuitID: "Forward", Confidence: 1, Method: circuits.MethodStartLine},
+		},
+		"shared start line": {
+			coordinate: models.Coordinate{X: 2049, Z: 1},
+			want:       circuits.Identification{Alternatives: []string{"Reverse", "Short"}, Method: circuits.MethodStartLine},
+			wantErr:    circuits.ErrCircuitAmbiguous,
+		},
+		"off start line": {
+			coordinate: models.Coordinate{X: 5000, Z: 5000},
+			wantErr:    circuits.ErrCircuitNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got, err := suite.db.IdentifyStartLine(test.coordinate)
+
+			// Assert
+			suite.Require().ErrorIs(err, test.wantErr)
+			suite.Equal(test.want, got)
+		})
+	}
+}
+
+func (suite *IdentifyTestSuite) TestIdentifyWithoutInventory() {
+	// Arrange
+	db := new(circuits.CircuitDB)
+
+	// Act
+	_, err := db.Identify(models.Coordinate{}, models.Vector{X: 1})
+	_, startErr := db.IdentifyStartLine(models.Coordinate{})
+
+	// Assert
+	suite.Require().ErrorIs(err, circuits.ErrNoInventory)
+	suite.Require().ErrorIs(startErr, circuits.ErrNoInventory)
+}
+
+func (suite *IdentifyTestSuite) TestPathMatcherConfidenceGrowsWhileDriving() {
+	// Arrange
+	matcher := circuits.NewPathMatcher(suite.db)
+
+	var confidences []float64
+
+	// Act
+	var got circuits.Identification
+
+	for range 2 {
+		for _, position := range lap() {
+			got = matcher.Identify(position)
+			confidences = append(confidences, got.Confidence)
+		}
+	}
+
+	// Assert
+	suite.Equal(circuits.Identification{CircuitID: "Forward", Confidence: 1, Method: circuits.MethodPath}, got)
+	suite.InDelta(0, confidences[0], 0, "Direction is unknown until the vehicle has moved")
+	suite.Greater(confidences[40], 0.0)
+	suite.Less(confidences[40], 1.0)
+	suite.IsNonDecreasing(confidences)
+}
+
+func (suite *IdentifyTestSuite) TestPathMatcherEvidenceStartsAgainAfterJump() {
+	// Arrange
+	matcher := circuits.NewPathMatcher(suite.db)
+	for _, position := range lap() {
+		matcher.Identify(position)
+	}
+
+	// Act
+	jumped := matcher.Identify(models.Coordinate{X: 5000, Z: 5000})
+
+	matcher.Reset()
+	reset := matcher.Identify(models.Coordinate{X: 20, Z: 4})
+
+	// Assert
+	suite.Equal(circuits.Identification{}, jumped)
+	suite.Equal(circuits.Identification{Alternatives: []string{"Forward", "Reverse"}, Method: circuits.MethodPath}, reset)
+}
@@ -9,6 +9,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
@@ -75,14 +76,22 @@ func loadCacheDir(cacheDir string) (map[string]CircuitInfo, error) {
 
 // buildLookupMaps constructs the coordinate and start line lookup maps from a
 // set of loaded circuits. Coordinates on each CircuitInfo are set to nil after
-// building the maps to free memory.
+// building the maps to free memory, keeping only the outline of each track map.
 func buildLookupMaps(circuits map[string]CircuitInfo) *circuitInventory {
+	coordinateResolutions := []Resolution{DefaultCircuitResolution()}
+	startLineResolutions := []Resolution{DefaultStartLineResolution()}
+
 	// Count how many circuits claim each coordinate
 	coordCounts := make(map[string][]string)
 
 	for circuitID, info := range circuits {
+		resolution := info.CircuitResolution()
+		if !slices.Contains(coordinateResolutions, resolution) {
+			coordinateResolutions = append(coordinateResolutions, resolution)
+		}
+
 		for _, coord := range info.Coordinates {
-			key := coord.String()
+			key := gridKey(coord, resolution, DefaultCircuitResolution())
 			coordCounts[key] = append(coordCounts[key], circuitID)
 		}
 	}
@@ -102,10 +111,25 @@ func buildLookupMaps(circuits map[string]CircuitInfo) *circuitInventory {
 	startLines := make(map[string][]string)
 
 	for circuitID, info := range circuits {
-		key := info.StartLine.String()
+		resolution := info.StartResolution()
+		if !slices.Contains(startLineResolutions, resolution) {
+			startLineResolutions = append(startLineResolutions, resolution)
+		}
+
+		key := gridKey(info.StartLine, resolution, DefaultStartLineResolution())
 		startLines[key] = append(startLines[key], circuitID)
 	}
 
+	directions := buildDirections(circuits, coordCounts)
+
+	trackMaps := make(map[string]TrackMap)
+
+	for circuitID, info := range circuits {
+		if trackMap, ok := newTrackMap(circuitID, info.Coordinates); ok {
+			trackMaps[circuitID] = trackMap
+		}
+	}
+
 	// Nil out coordinate slices to free memory and set unique coordinate counts
 	for id, info := range circuits {
 		info.Coordinates = nil
@@ -114,9 +138,13 @@ func buildLookupMaps(circuits map[string]CircuitInfo) *circuitInventory {
 	}
 
 	return &circuitInventory{
-		coordinates: coordinates,
-		startLines:  startLines,
-		circuits:    circuits,
+		coordinates:           coordinates,
+		startLines:            startLines,
+		circuits:              circuits,
+		directions:            directions,
+		maps:                  trackMaps,
+		coordinateResolutions: coordinateResolutions,
+		startLineResolutions:  startLineResolutions,
 	}
 }
 
@@ -173,8 +201,8 @@ func (db *CircuitDB) downloadUpdatedCircuits(ctx context.Context) (didUpdate boo
 	return didUpdate, nil
 }
 
-// rebuildInventory reloads the inventory from embedded and cached circuit files
-// and atomically swaps the in-memory inventory.
+// rebuildInventory reloads the inventory from embedded and cached circuit files,
+// layers any custom circuits on top and atomically swaps the in-memory inventory.
 func (db *CircuitDB) rebuildInventory() error {
 	circuits, err := loadFromFS(embeddedInventoryFS, "inventory")
 	if err != nil {
@@ -188,6 +216,10 @@ func (db *CircuitDB) rebuildInventory() error {
 		}
 	}
 
+	db.mu.RLock()
+	maps.Copy(circuits, db.custom)
+	db.mu.RUnlock()
+
 	inv := buildLookupMaps(circuits)
 
 	db.mu.Lock()
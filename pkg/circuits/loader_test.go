@@ -279,6 +279,27 @@ func (suite *LoaderTestSuite) TestBuildLookupMapsGroupsSharedStartLines() {
 	suite.Contains(ids, "TrackB")
 }
 
+func (suite *LoaderTestSuite) TestBuildLookupMapsKeepsResolutionsApart() {
+	// Arrange
+	start := models.CoordinateNorm{X: 96, Y: 0, Z: 200}
+	fine := circuits.Resolution{X: 8, Y: 2, Z: 8}
+
+	trackB := newTestCircuit("TrackB", "Track B", "uk", start, nil)
+	trackB.StartLineResolution = &fine
+
+	circuitMap := map[string]circuits.CircuitInfo{
+		"TrackA": newTestCircuit("TrackA", "Track A", "jp", start, nil),
+		"TrackB": trackB,
+	}
+
+	// Act
+	got := circuits.BuildLookupMapsForTest(circuitMap)
+
+	// Assert
+	suite.Equal([]string{"TrackA"}, got.StartLines["x:96,y:0,z:200"])
+	suite.Equal([]string{"TrackB"}, got.StartLines["x:96,y:0,z:200@x:8,y:2,z:8"])
+}
+
 func (suite *LoaderTestSuite) TestBuildLookupMapsNilsOutCoordinatesAfterBuilding() {
 	// Arrange
 	circuitMap := map[string]circuits.CircuitInfo{
@@ -0,0 +1,129 @@
+package circuits
+
+import (
+	"math"
+	"slices"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+const (
+	// pathMatchMinCosine is the smallest cosine of the angle between the direction of travel and the direction a
+	// layout is driven through a coordinate for the layout to match, i.e. within 60 degrees.
+	pathMatchMinCosine = 0.5
+	// pathMatcherSpacing is the distance in metres the vehicle moves before its position is added to the path.
+	pathMatcherSpacing = 4
+	// pathMatcherPoints is the number of positions kept in the path, enough to measure the direction of travel over
+	// about the length of a grid cell without smoothing it through corners.
+	pathMatcherPoints = 6
+	// pathMatcherMaxJump is the furthest in metres the vehicle can move between updates before the path is started
+	// again, such as when it is returned to the pits.
+	pathMatcherMaxJump = 50
+)
+
+// cellDirection is the direction a layout is driven through a coordinate of the grid, as a unit vector on the map.
+type cellDirection struct {
+	circuitID string
+	x, z      float64
+}
+
+// GetCircuitInDirection returns the circuit at a coordinate, using the direction of travel to choose between layouts
+// that share the coordinate, such as a layout and its reverse. Only the X and Z of the direction are used, and it
+// need not be normalised. Coordinates shared by layouts driven in the same direction are not found. Identify returns
+// the alternatives and the reason a coordinate is not found.
+func (db *CircuitDB) GetCircuitInDirection(coordinate models.Coordinate, direction models.Vector) (circuitID string, found bool) {
+	ident, err := db.Identify(coordinate, direction)
+
+	return ident.CircuitID, err == nil
+}
+
+// buildDirections records the direction each circuit is driven through the coordinates it shares with other
+// circuits, from the order of its coordinates around the lap.
+func buildDirections(circuits map[string]CircuitInfo, coordCounts map[string][]string) map[string][]cellDirection {
+	directions := make(map[string][]cellDirection)
+
+	for circuitID, info := range circuits {
+		resolution := info.CircuitResolution()
+		count := len(info.Coordinates)
+
+		for i, coord := range info.Coordinates {
+			key := gridKey(coord, resolution, DefaultCircuitResolution())
+			if len(coordCounts[key]) < 2 {
+				continue
+			}
+
+			// The lap is a loop, so the neighbours of the first and last coordinates wrap around
+			previous := info.Coordinates[(i+count-1)%count]
+			next := info.Coordinates[(i+1)%count]
+			x, z := float64(next.X-previous.X), float64(next.Z-previous.Z)
+
+			length := math.Hypot(x, z)
+			if length == 0 {
+				continue
+			}
+
+			directions[key] = append(directions[key], cellDirection{circuitID: circuitID, x: x / length, z: z / length})
+		}
+	}
+
+	return directions
+}
+
+// PathMatcher identifies the circuit from the recent path of the vehicle. Where layouts share coordinates, such as a
+// layout and its reverse, the coordinate map cannot tell them apart, so the direction of the recent path is compared
+// with the direction each layout is driven through the coordinate.
+type PathMatcher struct {
+	db   *CircuitDB
+	path []models.Coordinate
+	// evidence holds the candidate circuits of the most recent positions added to the path
+	evidence [][]string
+}
+
+// NewPathMatcher creates a path matcher identifying circuits in the database.
+func NewPathMatcher(db *CircuitDB) *PathMatcher {
+	return &PathMatcher{db: db}
+}
+
+// Update adds the position of the vehicle to the path and returns the circuit identified at it.
+func (m *PathMatcher) Update(coordinate models.Coordinate) (circuitID string, found bool) {
+	direction, _ := m.advance(coordinate)
+
+	return m.db.GetCircuitInDirection(coordinate, direction)
+}
+
+// advance adds the position of the vehicle to the path, returning the direction of the recent path and whether the
+// position was added. The path and its evidence start again when the vehicle jumps.
+func (m *PathMatcher) advance(coordinate models.Coordinate) (models.Vector, bool) {
+	moved := true
+
+	switch {
+	case len(m.path) == 0 || m.distanceFromLast(coordinate) > pathMatcherMaxJump:
+		m.path = append(m.path[:0], coordinate)
+		m.evidence = m.evidence[:0]
+	case m.distanceFromLast(coordinate) >= pathMatcherSpacing:
+		if len(m.path) == pathMatcherPoints {
+			m.path = slices.Delete(m.path, 0, 1)
+		}
+
+		m.path = append(m.path, coordinate)
+	default:
+		moved = false
+	}
+
+	first := m.path[0]
+
+	return models.Vector{X: coordinate.X - first.X, Z: coordinate.Z - first.Z}, moved
+}
+
+// Reset forgets the path, such as at the start of a new session.
+func (m *PathMatcher) Reset() {
+	m.path = m.path[:0]
+	m.evidence = m.evidence[:0]
+}
+
+// distanceFromLast returns the horizontal distance in metres from the last position in the path.
+func (m *PathMatcher) distanceFromLast(coordinate models.Coordinate) float64 {
+	last := m.path[len(m.path)-1]
+
+	return math.Hypot(float64(coordinate.X-last.X), float64(coordinate.Z-last.Z))
+}
@@ -0,0 +1,143 @@
+package circuits_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type PathMatchTestSuite struct {
+	suite.Suite
+
+	db *circuits.CircuitDB
+}
+
+func TestPathMatchTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(PathMatchTestSuite))
+}
+
+func (suite *PathMatchTestSuite) SetupTest() {
+	// A square loop driven anticlockwise on the map, and the same loop reversed with an extra unique coordinate
+	loop := []models.CoordinateNorm{
+		{X: 0, Z: 0}, {X: 16, Z: 0}, {X: 32, Z: 0}, {X: 32, Z: 16}, {X: 32, Z: 32}, {X: 16, Z: 32}, {X: 0, Z: 32}, {X: 0, Z: 16},
+	}
+
+	reversed := []models.CoordinateNorm{{X: -16, Z: 0}}
+	for i := len(loop) - 1; i >= 0; i-- {
+		reversed = append(reversed, loop[i])
+	}
+
+	suite.db = circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"Forward": {Coordinates: loop},
+		"Reverse": {Coordinates: reversed},
+	})
+}
+
+func (suite *PathMatchTestSuite) TestGetCircuitInDirection() {
+	// Arrange
+	tests := map[string]struct {
+		coordinate models.Coordinate
+		direction  models.Vector
+		want       string
+		wantFound  bool
+	}{
+		"forward direction":    {coordinate: models.Coordinate{X: 20, Z: 4}, direction: models.Vector{X: 1}, want: "Forward", wantFound: true},
+		"reverse direction":    {coordinate: models.Coordinate{X: 20, Z: 4}, direction: models.Vector{X: -1}, want: "Reverse", wantFound: true},
+		"across the circuit":   {coordinate: models.Coordinate{X: 20, Z: 4}, direction: models.Vector{Z: 1}},
+		"no direction":         {coordinate: models.Coordinate{X: 20, Z: 4}},
+		"unique coordinate":    {coordinate: models.Coordinate{X: -20, Z: 4}, want: "Reverse", wantFound: true},
+		"off circuit":          {coordinate: models.Coordinate{X: 500, Z: 500}, direction: models.Vector{X: 1}},
+		"unnormalised forward": {coordinate: models.Coordinate{X: 36, Z: 20}, direction: models.Vector{X: 0.5, Z: 30}, want: "Forward", wantFound: true},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got, found := suite.db.GetCircuitInDirection(test.coordinate, test.direction)
+
+			// Assert
+			suite.Equal(test.wantFound, found)
+			suite.Equal(test.want, got)
+		})
+	}
+}
+
+func (suite *PathMatchTestSuite) TestPathMatcherFollowsDirectionOfTravel() {
+	// Arrange
+	matcher := circuits.NewPathMatcher(suite.db)
+
+	var (
+		got   string
+		found bool
+	)
+
+	// Act
+	for x := float32(32); x >= 1; x-- {
+		got, found = matcher.Update(models.Coordinate{X: x, Z: 33})
+	}
+
+	// Assert
+	suite.True(found)
+	suite.Equal("Forward", got)
+}
+
+func (suite *PathMatchTestSuite) TestPathMatcherStartsAgainAfterJump() {
+	// Arrange
+	matcher := circuits.NewPathMatcher(suite.db)
+	for x := float32(1); x <= 31; x++ {
+		matcher.Update(models.Coordinate{X: x, Z: 1})
+	}
+
+	// Act
+	_, found := matcher.Update(models.Coordinate{X: 20, Z: 33})
+
+	// Assert
+	suite.False(found, "Direction is unknown until the vehicle has moved")
+}
+
+func (suite *PathMatchTestSuite) TestPathMatcherTellsApartEmbeddedReverseLayouts() {
+	// Arrange
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	tests := map[string]struct {
+		capture string
+		reverse string
+	}{
+		"TokyoExpresswaySouthClockwise": {
+			capture: "../../data/circuits/tokyo_expressway_south_clockwise.json",
+			reverse: "TokyoExpresswaySouthCounterclockwise",
+		},
+		"TokyoExpresswaySouthCounterclockwise": {
+			capture: "../../data/circuits/tokyo_expressway_south_counterclockwise.json",
+			reverse: "TokyoExpresswaySouthClockwise",
+		},
+	}
+
+	for want, test := range tests {
+		suite.Run(want, func() {
+			captured, err := circuits.LoadCapturedCircuit(test.capture)
+			suite.Require().NoError(err)
+
+			matcher := circuits.NewPathMatcher(db)
+			matches := map[string]int{}
+
+			// Act
+			for _, coordinate := range captured.Coordinates.Circuit {
+				if got, found := matcher.Update(coordinate); found {
+					matches[got]++
+				}
+			}
+
+			// Assert
+			suite.Zero(matches[test.reverse], "The reverse layout should never be matched")
+
+			for circuitID, count := range matches {
+				suite.LessOrEqual(count, matches[want], "%s should not be matched more than the layout driven", circuitID)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package circuits
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// trackMapMargin is the space in metres left around the outline of a circuit on its map, so that a vehicle running
+// wide stays on the map.
+const trackMapMargin = 50
+
+// TrackMap is the outline of a circuit seen from above, for drawing the circuit and the position of the vehicle on
+// it. The map runs along the X axis of the map coordinates from left to right and along the Z axis from top to bottom.
+type TrackMap struct {
+	CircuitID string `json:"circuitId"`
+	// MinX and MinZ are the map coordinates of the top left corner of the map, and Width and Height its size in
+	// metres, including a margin around the outline.
+	MinX   float32 `json:"minX"`
+	MinZ   float32 `json:"minZ"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+	// Outline is the grid coordinates of the circuit in the order they are driven around the lap.
+	Outline []models.CoordinateNorm `json:"outline"`
+}
+
+// newTrackMap creates the map of a circuit from its coordinates, not found for circuits with too few coordinates to
+// draw.
+func newTrackMap(circuitID string, coordinates []models.CoordinateNorm) (TrackMap, bool) {
+	if len(coordinates) < 2 {
+		return TrackMap{}, false
+	}
+
+	minX, minZ := int16(math.MaxInt16), int16(math.MaxInt16)
+	maxX, maxZ := int16(math.MinInt16), int16(math.MinInt16)
+
+	for _, coordinate := range coordinates {
+		minX, maxX = min(minX, coordinate.X), max(maxX, coordinate.X)
+		minZ, maxZ = min(minZ, coordinate.Z), max(maxZ, coordinate.Z)
+	}
+
+	return TrackMap{
+		CircuitID: circuitID,
+		MinX:      float32(minX) - trackMapMargin,
+		MinZ:      float32(minZ) - trackMapMargin,
+		Width:     float32(maxX) - float32(minX) + 2*trackMapMargin,
+		Height:    float32(maxZ) - float32(minZ) + 2*trackMapMargin,
+		Outline:   slices.Clone(coordinates),
+	}, true
+}
+
+// TrackMap returns the map of a circuit, not found for unknown circuits and circuits without coordinates.
+func (db *CircuitDB) TrackMap(circuitID string) (trackMap TrackMap, found bool) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.inventory == nil {
+		return TrackMap{}, false
+	}
+
+	trackMap, found = db.inventory.maps[circuitID]
+
+	return trackMap, found
+}
+
+// Position returns the position of a coordinate on the map, from 0 at the left and top edges to 1 at the right and
+// bottom edges, and whether it is on the map.
+func (m TrackMap) Position(coordinate models.Coordinate) (x, y float64, onMap bool) {
+	if m.Width <= 0 || m.Height <= 0 {
+		return 0, 0, false
+	}
+
+	x = float64((coordinate.X - m.MinX) / m.Width)
+	y = float64((coordinate.Z - m.MinZ) / m.Height)
+
+	return x, y, x >= 0 && x <= 1 && y >= 0 && y <= 1
+}
+
+// SVG returns the map as an SVG image, the outline drawn as a closed path in the current colour of the page. The view
+// box is the size of the map in metres, so a position returned by Position is drawn at its X and Y multiplied by
+// the width and height of the view box.
+func (m TrackMap) SVG() []byte {
+	var path strings.Builder
+
+	for i, coordinate := range m.Outline {
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+
+		fmt.Fprintf(&path, "%s%g %g ", command, float32(coordinate.X)-m.MinX, float32(coordinate.Z)-m.MinZ)
+	}
+
+	path.WriteString("Z")
+
+	return fmt.Appendf(nil, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %g %g">`+
+		`<path class="track" d="%s" fill="none" stroke="currentColor" stroke-width="8" stroke-linejoin="round"/></svg>`+"\n",
+		m.Width, m.Height, path.String())
+}
@@ -0,0 +1,114 @@
+package circuits_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type TrackMapTestSuite struct {
+	suite.Suite
+
+	db *circuits.CircuitDB
+}
+
+func TestTrackMapTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TrackMapTestSuite))
+}
+
+func (suite *TrackMapTestSuite) SetupTest() {
+	suite.db = circuits.NewDBFromCircuits(map[string]circuits.CircuitInfo{
+		"Square": {Coordinates: []models.CoordinateNorm{
+			{X: 0, Y: 10, Z: 0}, {X: 100, Y: 10, Z: 0}, {X: 100, Y: 10, Z: 200}, {X: 0, Y: 10, Z: 200},
+		}},
+		"Point": {Coordinates: []models.CoordinateNorm{{X: 500, Z: 500}}},
+	})
+}
+
+func (suite *TrackMapTestSuite) TestTrackMapBoundsIncludeMargin() {
+	// Act
+	trackMap, found := suite.db.TrackMap("Square")
+
+	// Assert
+	suite.Require().True(found)
+	suite.Equal("Square", trackMap.CircuitID)
+	suite.InDelta(-50, trackMap.MinX, 0)
+	suite.InDelta(-50, trackMap.MinZ, 0)
+	suite.InDelta(200, trackMap.Width, 0)
+	suite.InDelta(300, trackMap.Height, 0)
+	suite.Len(trackMap.Outline, 4)
+}
+
+func (suite *TrackMapTestSuite) TestTrackMapNotFound() {
+	// Act
+	_, pointFound := suite.db.TrackMap("Point")
+	_, unknownFound := suite.db.TrackMap("Unknown")
+
+	// Assert
+	suite.False(pointFound, "a single coordinate cannot be drawn")
+	suite.False(unknownFound)
+}
+
+func (suite *TrackMapTestSuite) TestPosition() {
+	// Arrange
+	trackMap, found := suite.db.TrackMap("Square")
+	suite.Require().True(found)
+
+	tests := map[string]struct {
+		coordinate models.Coordinate
+		wantX      float64
+		wantY      float64
+		wantOnMap  bool
+	}{
+		"corner of the outline": {coordinate: models.Coordinate{X: 0, Z: 0}, wantX: 0.25, wantY: 1.0 / 6, wantOnMap: true},
+		"centre of the map":     {coordinate: models.Coordinate{X: 50, Y: 99, Z: 100}, wantX: 0.5, wantY: 0.5, wantOnMap: true},
+		"off the map":           {coordinate: models.Coordinate{X: 400, Z: 100}, wantX: 2.25, wantY: 0.5},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Act
+			x, y, onMap := trackMap.Position(tc.coordinate)
+
+			// Assert
+			suite.InDelta(tc.wantX, x, 1e-6)
+			suite.InDelta(tc.wantY, y, 1e-6)
+			suite.Equal(tc.wantOnMap, onMap)
+		})
+	}
+}
+
+func (suite *TrackMapTestSuite) TestSVG() {
+	// Arrange
+	trackMap, found := suite.db.TrackMap("Square")
+	suite.Require().True(found)
+
+	// Act
+	svg := string(trackMap.SVG())
+
+	// Assert
+	suite.Contains(svg, `viewBox="0 0 200 300"`)
+	suite.Contains(svg, `d="M50 50 L150 50 L150 250 L50 250 Z"`)
+}
+
+func (suite *TrackMapTestSuite) TestEmbeddedCircuitsHaveMaps() {
+	// Arrange
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{})
+	suite.Require().NoError(err)
+
+	// Act
+	circuitIDs := db.GetAllCircuitIDs()
+
+	// Assert
+	suite.Require().NotEmpty(circuitIDs)
+
+	for _, circuitID := range circuitIDs {
+		trackMap, found := db.TrackMap(circuitID)
+		suite.True(found, circuitID)
+		suite.Positive(trackMap.Width, circuitID)
+		suite.Positive(trackMap.Height, circuitID)
+	}
+}
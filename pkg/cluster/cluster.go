@@ -0,0 +1,69 @@
+// Package cluster aggregates the telemetry of several remote clients into one feed, as the backend for league live
+// timing.
+//
+// Each driver runs "gt daemon" with a Forwarder, which posts a summarised Frame of their telemetry to a central
+// Collector at a fixed interval. The collector keeps the latest frame of every driver and serves the combined live
+// timing standings along with the channels of each driver.
+package cluster
+
+import (
+	"errors"
+	"net/netip"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/livetiming"
+)
+
+const (
+	// DefaultInterval is the interval at which frames are forwarded when no interval is configured.
+	DefaultInterval = 250 * time.Millisecond
+	// DefaultStaleAfter is how long after the last frame a driver is reported off circuit when no time is configured.
+	DefaultStaleAfter = 5 * time.Second
+	// DefaultMaxDrivers is the number of drivers a collector accepts when no limit is configured.
+	DefaultMaxDrivers = 64
+
+	// FramesPath is the path of the collector endpoint that frames are posted to.
+	FramesPath = "/frames"
+
+	// maxFrameSize is the largest frame body accepted by the collector.
+	maxFrameSize = 64 << 10
+)
+
+var (
+	// ErrNoDriver indicates a forwarder configured without a driver name or a frame received without one.
+	ErrNoDriver = errors.New("no driver name")
+	// ErrTooManyDrivers indicates a frame from a new driver received by a collector that has reached its driver limit.
+	ErrTooManyDrivers = errors.New("too many drivers")
+	// ErrUnknownChannel indicates a channel that is not provided by the telemetry source.
+	ErrUnknownChannel = errors.New("unknown channel")
+	// ErrInvalidCollector indicates a collector URL that cannot be used.
+	ErrInvalidCollector = errors.New("invalid collector URL")
+)
+
+// Frame is the summary of the telemetry of a driver sent from a forwarder to the collector.
+type Frame struct {
+	Driver           string `json:"driver"`
+	SequenceID       uint32 `json:"sequenceId"`
+	OnCircuit        bool   `json:"onCircuit"`
+	CurrentLap       int16  `json:"currentLap"`
+	CurrentLaptimeMs int64  `json:"currentLaptimeMs"`
+	LastLaptimeMs    int64  `json:"lastLaptimeMs"`
+	// Console is the address of the console the driver's telemetry is received from, e.g. "192.168.1.20:33739",
+	// telling apart drivers on the same network. Omitted when the source does not know it.
+	Console string `json:"console,omitempty"`
+	// Channels holds the values of the channels selected on the forwarder.
+	Channels map[string]float32 `json:"channels,omitempty"`
+}
+
+// Source provides the telemetry summarised by a forwarder, as implemented by gttelemetry.Transformer.
+type Source interface {
+	livetiming.TimingSource
+	SequenceID() uint32
+	Channel(name string) (float32, bool)
+}
+
+// sourceAddresser is implemented by sources that know the network address of the console, such as
+// gttelemetry.Transformer.
+type sourceAddresser interface {
+	SourceAddress() netip.AddrPort
+}
@@ -0,0 +1,350 @@
+package cluster_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/cluster"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type ClusterTestSuite struct {
+	suite.Suite
+
+	collector *cluster.Collector
+	server    *httptest.Server
+	alice     *gttelemetry.Transformer
+	bob       *gttelemetry.Transformer
+}
+
+func TestClusterTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClusterTestSuite))
+}
+
+func (suite *ClusterTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.alice = gttelemetry.NewTransformer(inventory)
+	suite.bob = gttelemetry.NewTransformer(inventory)
+
+	suite.collector = cluster.NewCollector(cluster.CollectorOptions{})
+	suite.server = httptest.NewServer(suite.collector.Handler())
+}
+
+func (suite *ClusterTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// countingSource is a timing source that counts how often it is sampled.
+type countingSource struct {
+	*gttelemetry.Transformer
+
+	samples atomic.Int64
+}
+
+func (c *countingSource) CurrentLap() int16 {
+	c.samples.Add(1)
+
+	return c.Transformer.CurrentLap()
+}
+
+func (suite *ClusterTestSuite) forwarder(name string, telemetry *gttelemetry.Transformer) *cluster.Forwarder {
+	forwarder, err := cluster.NewForwarder(telemetry, cluster.ForwarderOptions{
+		CollectorURL: suite.server.URL,
+		Driver:       name,
+		Channels:     []string{"groundSpeedKPH"},
+	})
+	suite.Require().NoError(err)
+
+	forwarder.Observe(telemetry)
+
+	return forwarder
+}
+
+func (suite *ClusterTestSuite) completeLap(forwarder *cluster.Forwarder, telemetry *gttelemetry.Transformer, laptimeMs int32) {
+	telemetry.RawTelemetry.CurrentLap++
+	telemetry.RawTelemetry.LastLaptime = laptimeMs
+	forwarder.Observe(telemetry)
+	suite.Require().NoError(forwarder.Send(context.Background()))
+	suite.collector.Board().Update()
+}
+
+func (suite *ClusterTestSuite) TestCollectorCombinesStandingsOfForwarders() {
+	// Arrange
+	alice := suite.forwarder("Alice", suite.alice)
+	bob := suite.forwarder("Bob", suite.bob)
+
+	suite.completeLap(alice, suite.alice, 0)
+	suite.completeLap(bob, suite.bob, 0)
+
+	// Act
+	suite.completeLap(alice, suite.alice, 91000)
+	suite.completeLap(bob, suite.bob, 89500)
+
+	standings := suite.collector.Board().Standings()
+
+	// Assert
+	suite.Require().Len(standings, 2)
+	suite.Equal("Bob", standings[0].Name)
+	suite.Equal(1, standings[0].LapsCompleted)
+	suite.Equal("Alice", standings[1].Name)
+	suite.Equal(int64(1500), standings[1].GapMs)
+	suite.True(standings[0].OnCircuit)
+}
+
+func (suite *ClusterTestSuite) TestCollectorServesDriverChannels() {
+	// Arrange
+	suite.alice.RawTelemetry.GroundSpeed = 25
+	suite.alice.RawTelemetry.SequenceId = 42
+	suite.Require().NoError(suite.forwarder("Alice", suite.alice).Send(context.Background()))
+
+	// Act
+	resp, err := http.Get(suite.server.URL + "/drivers/Alice") //nolint:noctx // test request
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	var state cluster.DriverState
+
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&state))
+
+	// Assert
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("Alice", state.Driver)
+	suite.Equal(uint32(42), state.SequenceID)
+	suite.InDelta(90, state.Channels["groundSpeedKPH"], 0.01)
+	suite.False(state.Stale)
+	suite.Len(suite.collector.Drivers(), 1)
+}
+
+func (suite *ClusterTestSuite) TestForwarderFrameIncludesConsoleAddress() {
+	// Arrange
+	suite.alice.SetSourceAddress(netip.MustParseAddrPort("192.168.1.20:33739"))
+
+	// Act
+	aliceFrame := suite.forwarder("Alice", suite.alice).Frame()
+	bobFrame := suite.forwarder("Bob", suite.bob).Frame()
+
+	// Assert
+	suite.Equal("192.168.1.20:33739", aliceFrame.Console)
+	suite.Empty(bobFrame.Console, "The console is unknown for telemetry not received from the network")
+}
+
+func (suite *ClusterTestSuite) TestCollectorReportsUnknownDriver() {
+	// Act
+	resp, err := http.Get(suite.server.URL + "/drivers/Nobody") //nolint:noctx // test request
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	// Assert
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *ClusterTestSuite) TestCollectorRejectsInvalidFrames() {
+	tests := map[string]string{
+		"malformed JSON": `{"driver":`,
+		"missing driver": `{"currentLap":1}`,
+	}
+
+	for name, body := range tests {
+		suite.Run(name, func() {
+			// Act
+			resp, err := http.Post(suite.server.URL+cluster.FramesPath, "application/json", strings.NewReader(body)) //nolint:noctx // test request
+			suite.Require().NoError(err)
+
+			defer resp.Body.Close()
+
+			// Assert
+			suite.Equal(http.StatusBadRequest, resp.StatusCode)
+			suite.Empty(suite.collector.Drivers())
+		})
+	}
+}
+
+func (suite *ClusterTestSuite) TestCollectorRequiresToken() {
+	// Arrange
+	collector := cluster.NewCollector(cluster.CollectorOptions{Token: "secret"})
+	server := httptest.NewServer(collector.Handler())
+
+	defer server.Close()
+
+	forwarder := func(token string) *cluster.Forwarder {
+		forwarder, err := cluster.NewForwarder(suite.alice, cluster.ForwarderOptions{
+			CollectorURL: server.URL,
+			Driver:       "Alice",
+			Token:        token,
+		})
+		suite.Require().NoError(err)
+
+		return forwarder
+	}
+
+	// Act
+	rejected := forwarder("guess").Send(context.Background())
+	accepted := forwarder("secret").Send(context.Background())
+
+	// Assert
+	suite.Require().Error(rejected)
+	suite.Contains(rejected.Error(), "401")
+	suite.Require().NoError(accepted)
+	suite.Len(collector.Drivers(), 1)
+}
+
+func (suite *ClusterTestSuite) TestCollectorRejectsDriversOverLimit() {
+	// Arrange
+	collector := cluster.NewCollector(cluster.CollectorOptions{MaxDrivers: 2})
+	server := httptest.NewServer(collector.Handler())
+
+	defer server.Close()
+
+	post := func(driver string) int {
+		body := fmt.Sprintf(`{"driver":%q}`, driver)
+
+		resp, err := http.Post(server.URL+cluster.FramesPath, "application/json", strings.NewReader(body)) //nolint:noctx // test request
+		suite.Require().NoError(err)
+
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	// Act
+	alice := post("Alice")
+	bob := post("Bob")
+	carol := post("Carol")
+	aliceAgain := post("Alice")
+
+	// Assert
+	suite.Equal(http.StatusNoContent, alice)
+	suite.Equal(http.StatusNoContent, bob)
+	suite.Equal(http.StatusForbidden, carol)
+	suite.Equal(http.StatusNoContent, aliceAgain, "known drivers keep sending frames at the limit")
+	suite.Len(collector.Drivers(), 2)
+	suite.ErrorIs(collector.Receive(cluster.Frame{Driver: "Carol"}), cluster.ErrTooManyDrivers)
+}
+
+func (suite *ClusterTestSuite) TestCollectorRunStopsUpdatingWhenServerFails() {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	defer listener.Close()
+
+	collector := cluster.NewCollector(cluster.CollectorOptions{
+		Address:        listener.Addr().String(),
+		UpdateInterval: time.Millisecond,
+	})
+
+	source := &countingSource{Transformer: suite.alice}
+	collector.Board().AddSource("Carol", source)
+
+	// Act
+	err = collector.Run(context.Background())
+	samples := source.samples.Load()
+
+	time.Sleep(20 * time.Millisecond)
+
+	// Assert
+	suite.Require().ErrorContains(err, "address already in use")
+	suite.Equal(samples, source.samples.Load(), "the standings are not updated after Run returns")
+}
+
+func (suite *ClusterTestSuite) TestNewForwarderValidatesOptions() {
+	tests := map[string]struct {
+		opts cluster.ForwarderOptions
+		err  error
+	}{
+		"missing driver": {
+			opts: cluster.ForwarderOptions{CollectorURL: "http://localhost:8080"},
+			err:  cluster.ErrNoDriver,
+		},
+		"invalid collector": {
+			opts: cluster.ForwarderOptions{CollectorURL: "localhost:8080", Driver: "Alice"},
+			err:  cluster.ErrInvalidCollector,
+		},
+		"unknown channel": {
+			opts: cluster.ForwarderOptions{CollectorURL: "http://localhost:8080", Driver: "Alice", Channels: []string{"warpFactor"}},
+			err:  cluster.ErrUnknownChannel,
+		},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Act
+			_, err := cluster.NewForwarder(suite.alice, tc.opts)
+
+			// Assert
+			suite.ErrorIs(err, tc.err)
+		})
+	}
+}
+
+func (suite *ClusterTestSuite) TestForwarderSendsObservedFrame() {
+	// Arrange
+	suite.alice.RawTelemetry.CurrentLap = 3
+	suite.alice.RawTelemetry.SequenceId = 42
+	forwarder := suite.forwarder("Alice", suite.alice)
+
+	suite.alice.RawTelemetry.CurrentLap = 4
+	suite.alice.RawTelemetry.SequenceId = 43
+
+	// Act
+	frame := forwarder.Frame()
+
+	// Assert
+	suite.Equal(int16(3), frame.CurrentLap, "the frame is not read from the source after it was observed")
+	suite.Equal(uint32(42), frame.SequenceID)
+}
+
+func (suite *ClusterTestSuite) TestForwarderCanObserveWhileRunning() {
+	// Arrange
+	forwarder, err := cluster.NewForwarder(suite.alice, cluster.ForwarderOptions{
+		CollectorURL: suite.server.URL,
+		Driver:       "Alice",
+		Channels:     []string{"groundSpeedKPH"},
+		Interval:     time.Millisecond,
+	})
+	suite.Require().NoError(err)
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://../../data/replays/demo.gtz",
+		LogLevel: "error",
+		OnFrame:  func(transformer *gttelemetry.Transformer) { forwarder.Observe(transformer) },
+	})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := make(chan error, 1)
+
+	go func() { running <- forwarder.Run(ctx) }()
+
+	// Act
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	suite.Eventually(func() bool {
+		state, ok := suite.collector.Driver("Alice")
+
+		return ok && state.SequenceID == client.Telemetry.SequenceID()
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	// Assert
+	suite.Require().NoError(<-running)
+}
@@ -0,0 +1,327 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/pkg/httpauth"
+	"github.com/zetetos/gt-telemetry/v2/pkg/livetiming"
+)
+
+const (
+	shutdownTimeout   = 5 * time.Second
+	readHeaderTimeout = 5 * time.Second
+)
+
+// CollectorOptions configures a Collector.
+type CollectorOptions struct {
+	// Address is the TCP address to listen on, defaults to ":8080".
+	Address string
+	// UpdateInterval is how often the standings are updated from the latest frames, defaults to 250ms.
+	UpdateInterval time.Duration
+	// StaleAfter is how long after their last frame a driver is reported off circuit, defaults to 5s.
+	StaleAfter time.Duration
+	// MaxDrivers is the number of drivers accepted, frames from further drivers are rejected, defaults to 64.
+	MaxDrivers int
+	// Token is required by every request when set, both from forwarders and spectators, see httpauth.RequireToken.
+	Token string
+	// TLS serves the collector over HTTPS when a certificate is set.
+	TLS httpauth.TLS
+	// Logger is used for collector log messages, defaults to a disabled logger.
+	Logger *zerolog.Logger
+}
+
+// DriverState is the latest frame received from a driver.
+type DriverState struct {
+	Frame
+
+	ReceivedAt time.Time `json:"receivedAt"`
+	// Stale is true when no frame has been received from the driver for longer than the stale time.
+	Stale bool `json:"stale"`
+}
+
+// Collector receives frames from forwarders and serves the combined standings and the channels of every driver.
+type Collector struct {
+	board      *livetiming.Board
+	timing     *livetiming.Server
+	address    string
+	interval   time.Duration
+	staleAfter time.Duration
+	maxDrivers int
+	token      string
+	tls        httpauth.TLS
+	log        zerolog.Logger
+
+	mu      sync.RWMutex
+	drivers map[string]*driver
+	order   []*driver
+}
+
+// driver is the timing source of a remote driver, answering from their latest frame.
+type driver struct {
+	staleAfter time.Duration
+
+	mu         sync.RWMutex
+	frame      Frame
+	receivedAt time.Time
+}
+
+// NewCollector creates a collector without any drivers. Drivers are added when their first frame is received.
+func NewCollector(opts CollectorOptions) *Collector {
+	if opts.Address == "" {
+		opts.Address = livetiming.DefaultAddress
+	}
+
+	if opts.UpdateInterval <= 0 {
+		opts.UpdateInterval = livetiming.DefaultUpdateInterval
+	}
+
+	if opts.StaleAfter <= 0 {
+		opts.StaleAfter = DefaultStaleAfter
+	}
+
+	if opts.MaxDrivers <= 0 {
+		opts.MaxDrivers = DefaultMaxDrivers
+	}
+
+	log := zerolog.Nop()
+	if opts.Logger != nil {
+		log = *opts.Logger
+	}
+
+	board := livetiming.NewBoard()
+
+	return &Collector{
+		board:      board,
+		timing:     livetiming.NewServer(board, livetiming.Options{Logger: &log}),
+		address:    opts.Address,
+		interval:   opts.UpdateInterval,
+		staleAfter: opts.StaleAfter,
+		maxDrivers: opts.MaxDrivers,
+		token:      opts.Token,
+		tls:        opts.TLS,
+		log:        log,
+		drivers:    make(map[string]*driver),
+	}
+}
+
+// Board returns the live timing board holding the standings of every driver.
+func (c *Collector) Board() *livetiming.Board {
+	return c.board
+}
+
+// Receive stores a frame as the latest frame of its driver, adding the driver to the standings when it is their
+// first frame. Frames from new drivers are rejected with ErrTooManyDrivers once the driver limit is reached.
+func (c *Collector) Receive(frame Frame) error {
+	if frame.Driver == "" {
+		return ErrNoDriver
+	}
+
+	c.mu.Lock()
+
+	d, ok := c.drivers[frame.Driver]
+	if !ok {
+		if len(c.order) >= c.maxDrivers {
+			c.mu.Unlock()
+
+			return ErrTooManyDrivers
+		}
+
+		d = &driver{staleAfter: c.staleAfter}
+		c.drivers[frame.Driver] = d
+		c.order = append(c.order, d)
+	}
+
+	c.mu.Unlock()
+
+	d.mu.Lock()
+	d.frame = frame
+	d.receivedAt = time.Now()
+	d.mu.Unlock()
+
+	if !ok {
+		c.board.AddSource(frame.Driver, d)
+		c.log.Info().Str("driver", frame.Driver).Msg("driver joined")
+	}
+
+	return nil
+}
+
+// Drivers returns the latest frame of every driver in the order they joined.
+func (c *Collector) Drivers() []DriverState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	states := make([]DriverState, 0, len(c.order))
+	for _, d := range c.order {
+		states = append(states, d.state())
+	}
+
+	return states
+}
+
+// Driver returns the latest frame of the named driver.
+func (c *Collector) Driver(name string) (DriverState, bool) {
+	c.mu.RLock()
+	d, ok := c.drivers[name]
+	c.mu.RUnlock()
+
+	if !ok {
+		return DriverState{}, false
+	}
+
+	return d.state(), true
+}
+
+// Handler returns the HTTP handler receiving frames at "/frames" and serving the timing page at "/", the standings
+// at "/standings.json", every driver at "/drivers.json" and a single driver at "/drivers/{name}".
+func (c *Collector) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", c.timing.Handler())
+
+	mux.HandleFunc("POST "+FramesPath, func(w http.ResponseWriter, r *http.Request) {
+		var frame Frame
+
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFrameSize)).Decode(&frame)
+		if err != nil {
+			http.Error(w, "invalid frame: "+err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		err = c.Receive(frame)
+		if errors.Is(err, ErrTooManyDrivers) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+
+			return
+		}
+
+		if err != nil {
+			http.Error(w, "invalid frame: "+err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /drivers.json", func(w http.ResponseWriter, _ *http.Request) {
+		c.writeJSON(w, c.Drivers())
+	})
+
+	mux.HandleFunc("GET /drivers/{name}", func(w http.ResponseWriter, r *http.Request) {
+		state, ok := c.Driver(r.PathValue("name"))
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		c.writeJSON(w, state)
+	})
+
+	return httpauth.RequireToken(c.token, mux)
+}
+
+// Run updates the standings and serves HTTP requests until the context is cancelled. The standings stop updating
+// before Run returns, including when the server fails to start.
+func (c *Collector) Run(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              c.address,
+		Handler:           c.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	wg.Go(func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+				defer cancel()
+
+				_ = httpServer.Shutdown(shutdownCtx)
+
+				return
+			case <-ticker.C:
+				c.board.Update()
+			}
+		}
+	})
+
+	c.log.Info().Str("address", c.address).Msg("collecting frames")
+
+	err := httpauth.ListenAndServe(httpServer, c.tls)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve collector: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Collector) writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	err := json.NewEncoder(w).Encode(value)
+	if err != nil {
+		c.log.Error().Err(err).Msg("failed to encode response")
+	}
+}
+
+func (d *driver) state() DriverState {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return DriverState{Frame: d.frame, ReceivedAt: d.receivedAt, Stale: d.stale()}
+}
+
+func (d *driver) stale() bool {
+	return time.Since(d.receivedAt) > d.staleAfter
+}
+
+func (d *driver) CurrentLap() int16 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.frame.CurrentLap
+}
+
+func (d *driver) CurrentLaptime() time.Duration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return time.Duration(d.frame.CurrentLaptimeMs) * time.Millisecond
+}
+
+func (d *driver) LastLaptime() time.Duration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return time.Duration(d.frame.LastLaptimeMs) * time.Millisecond
+}
+
+// IsOnCircuit reports the driver off circuit when their frames have stopped arriving.
+func (d *driver) IsOnCircuit() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.frame.OnCircuit && !d.stale()
+}
@@ -0,0 +1,208 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/pkg/httpauth"
+)
+
+// sendTimeout is how long a frame may take to be accepted by the collector.
+const sendTimeout = 2 * time.Second
+
+// ForwarderOptions configures a Forwarder.
+type ForwarderOptions struct {
+	// CollectorURL is the base URL of the collector, e.g. http://timing.example.com:8080.
+	CollectorURL string
+	// Driver is the name the driver is shown under in the standings.
+	Driver string
+	// Token is sent with every frame when set, matching the token of the collector.
+	Token string
+	// Channels lists the names of the telemetry channels forwarded with each frame, in addition to the lap timing.
+	Channels []string
+	// Interval is how often frames are forwarded, defaults to 250ms.
+	Interval time.Duration
+	// Client is used to send frames, defaults to a client with a short timeout.
+	Client *http.Client
+	// Logger is used for forwarder log messages, defaults to a disabled logger.
+	Logger *zerolog.Logger
+}
+
+// Forwarder sends summarised frames of the telemetry of a driver to a collector. Frames are sent on the goroutine
+// running the forwarder, so the telemetry is summarised by Observe, typically from gttelemetry.Options.OnFrame on the
+// decoding goroutine, rather than read from a source that changes while it is sent.
+type Forwarder struct {
+	endpoint string
+	driver   string
+	token    string
+	channels []string
+	interval time.Duration
+	client   *http.Client
+	log      zerolog.Logger
+
+	mu    sync.Mutex
+	frame Frame
+}
+
+// NewForwarder creates a forwarder for the driver. The source is only read to check that the channels exist and is not
+// retained, the telemetry to forward is passed to Observe.
+func NewForwarder(source Source, opts ForwarderOptions) (*Forwarder, error) {
+	if opts.Driver == "" {
+		return nil, ErrNoDriver
+	}
+
+	collector, err := url.Parse(opts.CollectorURL)
+	if err != nil || (collector.Scheme != "http" && collector.Scheme != "https") || collector.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCollector, opts.CollectorURL)
+	}
+
+	for _, channel := range opts.Channels {
+		if _, ok := source.Channel(channel); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+		}
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: sendTimeout}
+	}
+
+	log := zerolog.Nop()
+	if opts.Logger != nil {
+		log = *opts.Logger
+	}
+
+	return &Forwarder{
+		endpoint: strings.TrimSuffix(collector.String(), "/") + FramesPath,
+		driver:   opts.Driver,
+		token:    opts.Token,
+		channels: opts.Channels,
+		interval: opts.Interval,
+		client:   opts.Client,
+		log:      log,
+		frame:    Frame{Driver: opts.Driver},
+	}, nil
+}
+
+// Observe summarises the telemetry of the source as the frame to forward. The source is read only during the call.
+func (f *Forwarder) Observe(source Source) {
+	frame := Frame{
+		Driver:           f.driver,
+		SequenceID:       source.SequenceID(),
+		OnCircuit:        source.IsOnCircuit(),
+		CurrentLap:       source.CurrentLap(),
+		CurrentLaptimeMs: source.CurrentLaptime().Milliseconds(),
+		LastLaptimeMs:    source.LastLaptime().Milliseconds(),
+	}
+
+	if addresser, ok := source.(sourceAddresser); ok && addresser.SourceAddress().IsValid() {
+		frame.Console = addresser.SourceAddress().String()
+	}
+
+	if len(f.channels) > 0 {
+		frame.Channels = make(map[string]float32, len(f.channels))
+		for _, channel := range f.channels {
+			frame.Channels[channel], _ = source.Channel(channel)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.frame = frame
+}
+
+// Frame returns the summary of the telemetry last observed.
+func (f *Forwarder) Frame() Frame {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.frame
+}
+
+// Send posts the summary of the telemetry last observed to the collector.
+func (f *Forwarder) Send(ctx context.Context) error {
+	body, err := json.Marshal(f.Frame())
+	if err != nil {
+		return fmt.Errorf("encode frame: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create frame request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	httpauth.SetToken(req, f.token)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send frame: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("send frame: collector responded %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Run forwards a frame at every interval in which new telemetry was observed until the context is cancelled. The
+// collector may be unreachable for a while, so failed frames are logged and forwarding continues.
+func (f *Forwarder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	f.log.Info().Str("collector", f.endpoint).Str("driver", f.driver).Msg("forwarding frames to collector")
+
+	lastSequence := uint32(0)
+	failing := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			sequenceID := f.Frame().SequenceID
+			if sequenceID == lastSequence {
+				continue
+			}
+
+			err := f.Send(ctx)
+
+			switch {
+			case err != nil && ctx.Err() != nil:
+				return nil
+			case err != nil:
+				if !failing {
+					f.log.Warn().Err(err).Msg("failed to forward frame to collector")
+				}
+
+				failing = true
+			default:
+				if failing {
+					f.log.Info().Msg("forwarding frames to collector resumed")
+				}
+
+				failing = false
+				lastSequence = sequenceID
+			}
+		}
+	}
+}
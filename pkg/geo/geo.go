@@ -0,0 +1,49 @@
+// Package geo converts circuit map coordinates into geographic coordinates, so that tools built for GPS data loggers
+// can read Gran Turismo telemetry.
+package geo
+
+import "math"
+
+// earthRadiusMetres is the mean radius of the Earth.
+const earthRadiusMetres = 6371008.8
+
+// DefaultAnchor is used for circuits without a real-world location. It places the circuit origin at the Royal
+// Observatory, Greenwich, so that tools rejecting positions near latitude and longitude zero still accept the data.
+//
+//nolint:gochecknoglobals // read-only default
+var DefaultAnchor = Anchor{Latitude: 51.4769, Longitude: -0.0005}
+
+// Anchor places a circuit map on the Earth by pinning a point on the map to a geographic position.
+type Anchor struct {
+	// Latitude and Longitude of the pinned point in degrees.
+	Latitude  float64 `json:"latitude"  yaml:"latitude"`
+	Longitude float64 `json:"longitude" yaml:"longitude"`
+	// Rotation of the map clockwise from true north in degrees, for aligning the map with the real circuit.
+	Rotation float64 `json:"rotation" yaml:"rotation"`
+	// X and Z are the map coordinates of the pinned point in metres, the map origin by default.
+	X float64 `json:"x,omitempty" yaml:"x,omitempty"`
+	Z float64 `json:"z,omitempty" yaml:"z,omitempty"`
+}
+
+// Position is a geographic position in degrees.
+type Position struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Project converts a position on the circuit map in metres to a geographic position. The map X axis is taken as east
+// and the negative Z axis as north before the anchor rotation is applied. Circuits are small enough for an
+// equirectangular projection around the anchor to be accurate to well under a metre.
+func (a Anchor) Project(x, z float64) Position {
+	east, north := x-a.X, a.Z-z
+
+	if a.Rotation != 0 {
+		sin, cos := math.Sincos(a.Rotation * math.Pi / 180)
+		east, north = east*cos+north*sin, north*cos-east*sin
+	}
+
+	latitude := a.Latitude + north/earthRadiusMetres*180/math.Pi
+	longitude := a.Longitude + east/(earthRadiusMetres*math.Cos(a.Latitude*math.Pi/180))*180/math.Pi
+
+	return Position{Latitude: latitude, Longitude: longitude}
+}
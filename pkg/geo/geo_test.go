@@ -0,0 +1,129 @@
+package geo_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
+)
+
+type GeoTestSuite struct {
+	suite.Suite
+}
+
+func TestGeoTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(GeoTestSuite))
+}
+
+func (suite *GeoTestSuite) TestProject() {
+	// one degree of latitude is about 111.2km
+	const metresPerDegree = 111195.08
+
+	tests := map[string]struct {
+		anchor        geo.Anchor
+		x, z          float64
+		wantLatitude  float64
+		wantLongitude float64
+	}{
+		"origin": {
+			anchor:        geo.Anchor{Latitude: 50, Longitude: 6},
+			wantLatitude:  50,
+			wantLongitude: 6,
+		},
+		"north is negative z": {
+			anchor:        geo.Anchor{Latitude: 0, Longitude: 0},
+			z:             -metresPerDegree,
+			wantLatitude:  1,
+			wantLongitude: 0,
+		},
+		"east is x and narrows with latitude": {
+			anchor:        geo.Anchor{Latitude: 60, Longitude: 0},
+			x:             metresPerDegree,
+			wantLatitude:  60,
+			wantLongitude: 2,
+		},
+		"pinned point": {
+			anchor:        geo.Anchor{Latitude: 10, Longitude: 20, X: 500, Z: -metresPerDegree},
+			x:             500,
+			z:             -metresPerDegree,
+			wantLatitude:  10,
+			wantLongitude: 20,
+		},
+		"rotated clockwise": {
+			anchor:        geo.Anchor{Latitude: 0, Longitude: 0, Rotation: 90},
+			z:             -metresPerDegree,
+			wantLatitude:  0,
+			wantLongitude: 1,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			position := test.anchor.Project(test.x, test.z)
+
+			// Assert
+			suite.InDelta(test.wantLatitude, position.Latitude, 1e-6)
+			suite.InDelta(test.wantLongitude, position.Longitude, 1e-6)
+		})
+	}
+}
+
+func (suite *GeoTestSuite) TestWriteNMEA() {
+	// Arrange
+	fix := geo.Fix{
+		Time:     time.Date(2026, 3, 14, 12, 30, 45, 250*int(time.Millisecond), time.UTC),
+		Position: geo.Position{Latitude: 50.3356, Longitude: -6.9475},
+		Altitude: 612.3,
+		Speed:    50,
+		Course:   271.5,
+	}
+
+	var buffer bytes.Buffer
+
+	// Act
+	err := geo.WriteNMEA(&buffer, fix)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("$GPGGA,123045.25,5020.1360,N,00656.8500,W,1,08,1.0,612.3,M,0.0,M,,*4C\r\n"+
+		"$GPRMC,123045.25,A,5020.1360,N,00656.8500,W,97.19,271.5,140326,,,A*44\r\n", buffer.String())
+}
+
+func (suite *GeoTestSuite) TestGPXWriterWritesSegments() {
+	// Arrange
+	var buffer bytes.Buffer
+
+	writer := geo.NewGPXWriter(&buffer, "Laps & more")
+	fix := geo.Fix{Time: time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC), Position: geo.Position{Latitude: 1, Longitude: 2}}
+
+	// Act
+	suite.Require().NoError(writer.Write(fix))
+	suite.Require().NoError(writer.NewSegment())
+	suite.Require().NoError(writer.Write(fix))
+	suite.Require().NoError(writer.Write(fix))
+	suite.Require().NoError(writer.Close())
+
+	// Assert
+	var gpx struct {
+		Name     string `xml:"trk>name"`
+		Segments []struct {
+			Points []struct {
+				Latitude float64 `xml:"lat,attr"`
+				Time     string  `xml:"time"`
+			} `xml:"trkpt"`
+		} `xml:"trk>trkseg"`
+	}
+
+	suite.Require().NoError(xml.Unmarshal(buffer.Bytes(), &gpx))
+	suite.Equal("Laps & more", gpx.Name)
+	suite.Require().Len(gpx.Segments, 2)
+	suite.Len(gpx.Segments[1].Points, 2)
+	suite.InDelta(1, gpx.Segments[0].Points[0].Latitude, 1e-9)
+	suite.Equal("2026-03-14T12:00:00Z", gpx.Segments[0].Points[0].Time)
+	suite.ErrorIs(writer.Write(fix), geo.ErrGPXClosed)
+}
@@ -0,0 +1,127 @@
+package geo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrGPXClosed indicates a write to a GPX writer that has already been closed.
+var ErrGPXClosed = errors.New("GPX writer is closed")
+
+// GPXWriter streams fixes to a GPX 1.0 file as a single track. Version 1.0 is used as, unlike 1.1, it records the
+// speed and course of each point.
+type GPXWriter struct {
+	w           io.Writer
+	name        string
+	started     bool
+	segmentOpen bool
+	closed      bool
+}
+
+// NewGPXWriter creates a writer for a track with the given name. Nothing is written until the first fix.
+func NewGPXWriter(w io.Writer, name string) *GPXWriter {
+	return &GPXWriter{w: w, name: name}
+}
+
+// Write adds a fix to the current track segment, starting the file or segment when needed.
+func (g *GPXWriter) Write(fix Fix) error {
+	if g.closed {
+		return ErrGPXClosed
+	}
+
+	err := g.start()
+	if err != nil {
+		return err
+	}
+
+	if !g.segmentOpen {
+		g.segmentOpen = true
+
+		_, err = io.WriteString(g.w, "    <trkseg>\n")
+		if err != nil {
+			return fmt.Errorf("write GPX segment: %w", err)
+		}
+	}
+
+	_, err = fmt.Fprintf(g.w,
+		"      <trkpt lat=\"%.8f\" lon=\"%.8f\"><ele>%.2f</ele><time>%s</time><course>%.1f</course><speed>%.2f</speed></trkpt>\n",
+		fix.Position.Latitude, fix.Position.Longitude, fix.Altitude, fix.Time.UTC().Format(time.RFC3339Nano), fix.Course,
+		fix.Speed)
+	if err != nil {
+		return fmt.Errorf("write GPX point: %w", err)
+	}
+
+	return nil
+}
+
+// NewSegment ends the current track segment, so that the next fix starts a new one. Tools treat segments as separate
+// runs, so a segment per lap keeps laps apart.
+func (g *GPXWriter) NewSegment() error {
+	if !g.segmentOpen {
+		return nil
+	}
+
+	g.segmentOpen = false
+
+	_, err := io.WriteString(g.w, "    </trkseg>\n")
+	if err != nil {
+		return fmt.Errorf("write GPX segment: %w", err)
+	}
+
+	return nil
+}
+
+// Close ends the track and the file. It does not close the underlying writer.
+func (g *GPXWriter) Close() error {
+	if g.closed {
+		return nil
+	}
+
+	err := g.start()
+	if err != nil {
+		return err
+	}
+
+	err = g.NewSegment()
+	if err != nil {
+		return err
+	}
+
+	g.closed = true
+
+	_, err = io.WriteString(g.w, "  </trk>\n</gpx>\n")
+	if err != nil {
+		return fmt.Errorf("write GPX footer: %w", err)
+	}
+
+	return nil
+}
+
+func (g *GPXWriter) start() error {
+	if g.started {
+		return nil
+	}
+
+	g.started = true
+
+	var name strings.Builder
+
+	err := xml.EscapeText(&name, []byte(g.name))
+	if err != nil {
+		return fmt.Errorf("escape GPX track name: %w", err)
+	}
+
+	_, err = fmt.Fprintf(g.w, `%s<gpx version="1.0" creator="gt-telemetry" xmlns="http://www.topografix.com/GPX/1/0">
+  <trk>
+    <name>%s</name>
+`, xml.Header, name.String())
+	if err != nil {
+		return fmt.Errorf("write GPX header: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package geo
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// knotsPerMetrePerSecond converts speeds for NMEA sentences, which give speed over ground in knots.
+const knotsPerMetrePerSecond = 3600.0 / 1852
+
+// Fix is a single reading of a GPS receiver.
+type Fix struct {
+	Time     time.Time
+	Position Position
+	// Altitude above sea level in metres.
+	Altitude float64
+	// Speed over ground in metres per second.
+	Speed float64
+	// Course over ground in degrees clockwise from true north.
+	Course float64
+}
+
+// WriteNMEA writes a fix to w as a GGA and an RMC sentence, the pair most tools need for position, altitude, speed and
+// course.
+func WriteNMEA(w io.Writer, fix Fix) error {
+	utc := fix.Time.UTC()
+	clock := fmt.Sprintf("%02d%02d%02d.%02d", utc.Hour(), utc.Minute(), utc.Second(), utc.Nanosecond()/int(10*time.Millisecond))
+	latitude, north := nmeaAngle(fix.Position.Latitude, 2, "N", "S")
+	longitude, east := nmeaAngle(fix.Position.Longitude, 3, "E", "W")
+
+	gga := fmt.Sprintf("GPGGA,%s,%s,%s,%s,%s,1,08,1.0,%.1f,M,0.0,M,,", clock, latitude, north, longitude, east, fix.Altitude)
+	rmc := fmt.Sprintf("GPRMC,%s,A,%s,%s,%s,%s,%.2f,%.1f,%s,,,A", clock, latitude, north, longitude, east,
+		fix.Speed*knotsPerMetrePerSecond, fix.Course, utc.Format("020106"))
+
+	_, err := fmt.Fprintf(w, "$%s*%02X\r\n$%s*%02X\r\n", gga, nmeaChecksum(gga), rmc, nmeaChecksum(rmc))
+	if err != nil {
+		return fmt.Errorf("write NMEA sentences: %w", err)
+	}
+
+	return nil
+}
+
+// nmeaAngle formats an angle as degrees and decimal minutes, with the degrees padded to the given width, and returns
+// it with its hemisphere.
+func nmeaAngle(angle float64, width int, positive, negative string) (string, string) {
+	hemisphere := positive
+	if angle < 0 {
+		hemisphere = negative
+		angle = -angle
+	}
+
+	degrees := math.Floor(angle)
+	minutes := (angle - degrees) * 60
+
+	return fmt.Sprintf("%0*d%07.4f", width, int(degrees), minutes), hemisphere
+}
+
+// nmeaChecksum is the exclusive or of every byte of the sentence between the $ and the *.
+func nmeaChecksum(sentence string) byte {
+	var checksum byte
+
+	for i := range len(sentence) {
+		checksum ^= sentence[i]
+	}
+
+	return checksum
+}
@@ -0,0 +1,229 @@
+// Package homeassistant builds Home Assistant MQTT discovery and state payloads for telemetry sensors.
+//
+// The package does not include an MQTT client. The messages it returns are intended to be published with the MQTT
+// client of the host application: discovery messages once on connection (they are marked as retained) and a state
+// message for each telemetry update that should be reflected in Home Assistant.
+package homeassistant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+const (
+	// DefaultDiscoveryPrefix is the discovery prefix used by Home Assistant unless configured otherwise.
+	DefaultDiscoveryPrefix = "homeassistant"
+	// DefaultNodeID identifies the telemetry device when no node ID is configured.
+	DefaultNodeID = "gt_telemetry"
+	// DefaultDeviceName is the device name shown in Home Assistant when no name is configured.
+	DefaultDeviceName = "Gran Turismo"
+)
+
+var (
+	// ErrNoSensors indicates that discovery was requested without any sensors.
+	ErrNoSensors = errors.New("no sensors configured")
+	// ErrUnknownChannel indicates a sensor whose channel cannot be read from the state source.
+	ErrUnknownChannel = errors.New("unknown channel")
+)
+
+// StateSource provides the telemetry values published in the state message, as implemented by
+// gttelemetry.Transformer.
+type StateSource interface {
+	GroundSpeedKPH() float32
+	EngineRPM() float32
+	CurrentLap() int16
+	GameState() models.GameState
+}
+
+// ChannelSource provides the values of telemetry channels by name, as implemented by gttelemetry.Transformer. A state
+// source that also implements ChannelSource can publish sensors of any channel, not only those of DefaultSensors.
+type ChannelSource interface {
+	Channel(name string) (float32, bool)
+}
+
+// Sensor describes a single Home Assistant sensor entity. Key is the field of the state payload holding the value, and
+// Channel the telemetry channel it is read from, empty for the game state.
+type Sensor struct {
+	Key         string
+	Channel     string
+	Name        string
+	Unit        string
+	DeviceClass string
+	StateClass  string
+	Icon        string
+}
+
+// Options configures the topics and device information used in the payloads.
+type Options struct {
+	// DiscoveryPrefix is the Home Assistant discovery prefix, defaults to "homeassistant".
+	DiscoveryPrefix string
+	// NodeID uniquely identifies the telemetry device, defaults to "gt_telemetry".
+	NodeID string
+	// DeviceName is the name of the device in Home Assistant, defaults to "Gran Turismo".
+	DeviceName string
+	// StateTopic is the topic that state messages are published to, defaults to "<NodeID>/state".
+	StateTopic string
+	// Sensors lists the sensors published in the state message, defaults to DefaultSensors. Pass the same sensors to
+	// DiscoveryMessages.
+	Sensors []Sensor
+	// Exclude lists the keys of sensors left out of the state payload, such as sensors whose channel is redacted.
+	Exclude []string
+}
+
+// Message is an MQTT message to be published by the host application.
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+type device struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+type discoveryPayload struct {
+	Name              string `json:"name"`
+	UniqueID          string `json:"unique_id"`                     //nolint:tagliatelle // Home Assistant field name
+	StateTopic        string `json:"state_topic"`                   //nolint:tagliatelle // Home Assistant field name
+	ValueTemplate     string `json:"value_template"`                //nolint:tagliatelle // Home Assistant field name
+	UnitOfMeasurement string `json:"unit_of_measurement,omitempty"` //nolint:tagliatelle // Home Assistant field name
+	DeviceClass       string `json:"device_class,omitempty"`        //nolint:tagliatelle // Home Assistant field name
+	StateClass        string `json:"state_class,omitempty"`         //nolint:tagliatelle // Home Assistant field name
+	Icon              string `json:"icon,omitempty"`
+	Device            device `json:"device"`
+}
+
+// DefaultSensors returns the speed, RPM, lap and game state sensors.
+func DefaultSensors() []Sensor {
+	return []Sensor{
+		{Key: "speed", Channel: "groundSpeedKPH", Name: "Speed", Unit: "km/h", DeviceClass: "speed", StateClass: "measurement"},
+		{Key: "rpm", Channel: "engineRPM", Name: "Engine RPM", Unit: "rpm", StateClass: "measurement", Icon: "mdi:engine"},
+		{Key: "lap", Channel: "currentLap", Name: "Lap", StateClass: "measurement", Icon: "mdi:flag-checkered"},
+		{Key: "state", Name: "Game state", Icon: "mdi:gamepad-variant"},
+	}
+}
+
+// DiscoveryMessages returns a retained discovery message for each sensor.
+func DiscoveryMessages(opts Options, sensors []Sensor) ([]Message, error) {
+	if len(sensors) == 0 {
+		return nil, ErrNoSensors
+	}
+
+	opts = withDefaults(opts)
+	messages := make([]Message, 0, len(sensors))
+
+	for _, sensor := range sensors {
+		payload, err := json.Marshal(discoveryPayload{
+			Name:              sensor.Name,
+			UniqueID:          opts.NodeID + "_" + sensor.Key,
+			StateTopic:        opts.StateTopic,
+			ValueTemplate:     fmt.Sprintf("{{ value_json.%s }}", sensor.Key),
+			UnitOfMeasurement: sensor.Unit,
+			DeviceClass:       sensor.DeviceClass,
+			StateClass:        sensor.StateClass,
+			Icon:              sensor.Icon,
+			Device: device{
+				Identifiers:  []string{opts.NodeID},
+				Name:         opts.DeviceName,
+				Manufacturer: "Polyphony Digital",
+				Model:        "Gran Turismo 7",
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshal discovery payload for %s: %w", sensor.Key, err)
+		}
+
+		messages = append(messages, Message{
+			Topic:   fmt.Sprintf("%s/sensor/%s/%s/config", opts.DiscoveryPrefix, opts.NodeID, sensor.Key),
+			Payload: payload,
+			Retain:  true,
+		})
+	}
+
+	return messages, nil
+}
+
+// StateMessage returns a message containing the current values of the sensors of the options. The values of sensors
+// other than those of DefaultSensors are read with the Channel method of the source, see ChannelSource.
+func StateMessage(opts Options, source StateSource) (Message, error) {
+	opts = withDefaults(opts)
+	fields := make(map[string]any, len(opts.Sensors))
+
+	for _, sensor := range opts.Sensors {
+		if slices.Contains(opts.Exclude, sensor.Key) {
+			continue
+		}
+
+		value, err := sensorValue(sensor, source)
+		if err != nil {
+			return Message{}, fmt.Errorf("read sensor %s: %w", sensor.Key, err)
+		}
+
+		fields[sensor.Key] = value
+	}
+
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return Message{}, fmt.Errorf("marshal state payload: %w", err)
+	}
+
+	return Message{
+		Topic:   opts.StateTopic,
+		Payload: payload,
+	}, nil
+}
+
+// sensorValue reads the value of a sensor from the source.
+func sensorValue(sensor Sensor, source StateSource) (any, error) {
+	switch sensor.Channel {
+	case "":
+		state := source.GameState()
+
+		return state.String(), nil
+	case "groundSpeedKPH":
+		return source.GroundSpeedKPH(), nil
+	case "engineRPM":
+		return source.EngineRPM(), nil
+	case "currentLap":
+		return source.CurrentLap(), nil
+	}
+
+	if channels, ok := source.(ChannelSource); ok {
+		if value, ok := channels.Channel(sensor.Channel); ok {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownChannel, sensor.Channel)
+}
+
+func withDefaults(opts Options) Options {
+	if opts.DiscoveryPrefix == "" {
+		opts.DiscoveryPrefix = DefaultDiscoveryPrefix
+	}
+
+	if opts.NodeID == "" {
+		opts.NodeID = DefaultNodeID
+	}
+
+	if opts.DeviceName == "" {
+		opts.DeviceName = DefaultDeviceName
+	}
+
+	if opts.StateTopic == "" {
+		opts.StateTopic = opts.NodeID + "/state"
+	}
+
+	if len(opts.Sensors) == 0 {
+		opts.Sensors = DefaultSensors()
+	}
+
+	return opts
+}
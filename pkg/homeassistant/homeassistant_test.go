@@ -0,0 +1,136 @@
+package homeassistant_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/homeassistant"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type fakeState struct{}
+
+func (fakeState) GroundSpeedKPH() float32     { return 123.5 }
+func (fakeState) EngineRPM() float32          { return 6500 }
+func (fakeState) CurrentLap() int16           { return 3 }
+func (fakeState) GameState() models.GameState { return models.GameStateLive }
+
+// fakeChannels is a state source that also provides channels by name.
+type fakeChannels struct {
+	fakeState
+}
+
+func (fakeChannels) Channel(name string) (float32, bool) {
+	if name == "fuelLevelPercent" {
+		return 42.5, true
+	}
+
+	return 0, false
+}
+
+type HomeAssistantTestSuite struct {
+	suite.Suite
+}
+
+func TestHomeAssistantTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(HomeAssistantTestSuite))
+}
+
+func (suite *HomeAssistantTestSuite) TestDiscoveryMessagesUseDefaultTopics() {
+	// Act
+	messages, err := homeassistant.DiscoveryMessages(homeassistant.Options{}, homeassistant.DefaultSensors())
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(messages, 4)
+	suite.Equal("homeassistant/sensor/gt_telemetry/speed/config", messages[0].Topic)
+	suite.True(messages[0].Retain)
+
+	var payload map[string]any
+
+	suite.Require().NoError(json.Unmarshal(messages[0].Payload, &payload))
+	suite.Equal("gt_telemetry/state", payload["state_topic"])
+	suite.Equal("{{ value_json.speed }}", payload["value_template"])
+	suite.Equal("km/h", payload["unit_of_measurement"])
+	suite.Equal("gt_telemetry_speed", payload["unique_id"])
+}
+
+func (suite *HomeAssistantTestSuite) TestDiscoveryMessagesUseConfiguredTopics() {
+	// Arrange
+	opts := homeassistant.Options{DiscoveryPrefix: "ha", NodeID: "rig1", StateTopic: "rigs/1"}
+
+	// Act
+	messages, err := homeassistant.DiscoveryMessages(opts, []homeassistant.Sensor{{Key: "lap", Name: "Lap"}})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Len(messages, 1)
+	suite.Equal("ha/sensor/rig1/lap/config", messages[0].Topic)
+	suite.Contains(string(messages[0].Payload), `"state_topic":"rigs/1"`)
+	suite.NotContains(string(messages[0].Payload), "unit_of_measurement")
+}
+
+func (suite *HomeAssistantTestSuite) TestDiscoveryMessagesRequireSensors() {
+	// Act
+	_, err := homeassistant.DiscoveryMessages(homeassistant.Options{}, nil)
+
+	// Assert
+	suite.Require().ErrorIs(err, homeassistant.ErrNoSensors)
+}
+
+func (suite *HomeAssistantTestSuite) TestStateMessageContainsSensorValues() {
+	// Act
+	message, err := homeassistant.StateMessage(homeassistant.Options{}, fakeState{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("gt_telemetry/state", message.Topic)
+	suite.False(message.Retain)
+	suite.JSONEq(`{"speed":123.5,"rpm":6500,"lap":3,"state":"live"}`, string(message.Payload))
+}
+
+func (suite *HomeAssistantTestSuite) TestStateMessageLeavesOutExcludedSensors() {
+	// Act
+	message, err := homeassistant.StateMessage(homeassistant.Options{Exclude: []string{"rpm", "lap"}}, fakeState{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.JSONEq(`{"speed":123.5,"state":"live"}`, string(message.Payload))
+}
+
+func (suite *HomeAssistantTestSuite) TestStateMessageContainsCustomSensors() {
+	// Arrange
+	sensors := append(homeassistant.DefaultSensors(), homeassistant.Sensor{Key: "fuel", Channel: "fuelLevelPercent", Name: "Fuel"})
+
+	// Act
+	message, err := homeassistant.StateMessage(homeassistant.Options{Sensors: sensors}, fakeChannels{})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.JSONEq(`{"speed":123.5,"rpm":6500,"lap":3,"state":"live","fuel":42.5}`, string(message.Payload))
+}
+
+func (suite *HomeAssistantTestSuite) TestStateMessageRejectsUnknownChannels() {
+	tests := map[string]struct {
+		source  homeassistant.StateSource
+		channel string
+	}{
+		"source without channels": {source: fakeState{}, channel: "fuelLevelPercent"},
+		"unknown channel":         {source: fakeChannels{}, channel: "turboBoostBar"},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			sensors := []homeassistant.Sensor{{Key: "custom", Channel: tc.channel, Name: "Custom"}}
+
+			// Act
+			_, err := homeassistant.StateMessage(homeassistant.Options{Sensors: sensors}, tc.source)
+
+			// Assert
+			suite.Require().ErrorIs(err, homeassistant.ErrUnknownChannel)
+		})
+	}
+}
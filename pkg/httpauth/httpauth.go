@@ -0,0 +1,89 @@
+// Package httpauth secures the HTTP servers of the telemetry exporters with an access token and TLS, so that they can
+// be exposed beyond the local machine, e.g. to an overlay running in the cloud.
+package httpauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// TokenParameter is the query parameter the token can be passed in by clients that cannot set request headers, such
+// as browsers opening a WebSocket or loading the live timing page.
+const TokenParameter = "token"
+
+// ErrIncompleteTLS indicates a TLS configuration with a certificate but no key, or a key but no certificate.
+var ErrIncompleteTLS = errors.New("TLS requires both a certificate and a key file")
+
+// TLS holds the PEM encoded certificate and private key files a server is served with.
+type TLS struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Enabled reports whether a certificate is configured.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// Validate checks that the certificate and key are either both set or both unset.
+func (t TLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return ErrIncompleteTLS
+	}
+
+	return nil
+}
+
+// ListenAndServe serves the server over TLS when a certificate is configured and over plain HTTP otherwise.
+func ListenAndServe(server *http.Server, tls TLS) error {
+	if !tls.Enabled() {
+		return server.ListenAndServe()
+	}
+
+	err := tls.Validate()
+	if err != nil {
+		return err
+	}
+
+	return server.ListenAndServeTLS(tls.CertFile, tls.KeyFile)
+}
+
+// RequireToken wraps a handler so that requests are only served when they carry the token, either as a bearer token
+// in the Authorization header or in the token query parameter. Requests for the public paths, such as health
+// endpoints polled by an orchestrator, are served without a token. An empty token disables the check.
+func RequireToken(token string, next http.Handler, publicPaths ...string) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(publicPaths, r.URL.Path) || validToken(r, token) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gt-telemetry"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
+}
+
+// SetToken adds the token to a request as a bearer token.
+func SetToken(r *http.Request, token string) {
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+func validToken(r *http.Request, token string) bool {
+	given := r.URL.Query().Get(TokenParameter)
+
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		given = bearer
+	}
+
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
@@ -0,0 +1,113 @@
+package httpauth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/httpauth"
+)
+
+type HTTPAuthTestSuite struct {
+	suite.Suite
+}
+
+func TestHTTPAuthTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(HTTPAuthTestSuite))
+}
+
+func (suite *HTTPAuthTestSuite) TestRequireToken() {
+	tests := map[string]struct {
+		token      string
+		target     string
+		header     string
+		wantStatus int
+	}{
+		"no token configured": {
+			target:     "/standings.json",
+			wantStatus: http.StatusOK,
+		},
+		"missing token": {
+			token:      "secret",
+			target:     "/standings.json",
+			wantStatus: http.StatusUnauthorized,
+		},
+		"bearer token": {
+			token:      "secret",
+			target:     "/standings.json",
+			header:     "Bearer secret",
+			wantStatus: http.StatusOK,
+		},
+		"wrong bearer token": {
+			token:      "secret",
+			target:     "/standings.json?token=secret",
+			header:     "Bearer guess",
+			wantStatus: http.StatusUnauthorized,
+		},
+		"query token": {
+			token:      "secret",
+			target:     "/ws?token=secret",
+			wantStatus: http.StatusOK,
+		},
+		"public path": {
+			token:      "secret",
+			target:     "/healthz",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			handler := httpauth.RequireToken(test.token, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}), "/healthz")
+
+			req := httptest.NewRequest(http.MethodGet, test.target, nil)
+			if test.header != "" {
+				req.Header.Set("Authorization", test.header)
+			}
+
+			recorder := httptest.NewRecorder()
+
+			// Act
+			handler.ServeHTTP(recorder, req)
+
+			// Assert
+			suite.Equal(test.wantStatus, recorder.Code)
+		})
+	}
+}
+
+func (suite *HTTPAuthTestSuite) TestSetTokenIsAccepted() {
+	// Arrange
+	handler := httpauth.RequireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/frames", nil)
+	httpauth.SetToken(req, "secret")
+
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.ServeHTTP(recorder, req)
+
+	// Assert
+	suite.Equal(http.StatusNoContent, recorder.Code)
+}
+
+func (suite *HTTPAuthTestSuite) TestIncompleteTLSIsRejected() {
+	// Arrange
+	server := &http.Server{Addr: "127.0.0.1:0"} //nolint:gosec // never served
+
+	// Act
+	err := httpauth.ListenAndServe(server, httpauth.TLS{CertFile: "cert.pem"})
+
+	// Assert
+	suite.Require().ErrorIs(err, httpauth.ErrIncompleteTLS)
+	suite.True(httpauth.TLS{KeyFile: "key.pem"}.Enabled())
+	suite.False(httpauth.TLS{}.Enabled())
+}
@@ -0,0 +1,283 @@
+// Package livetiming aggregates lap timing from one or more telemetry sources into live standings and serves them
+// as a timing page for spectators on the local network.
+package livetiming
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// TimingSource provides the lap timing of a driver, as implemented by gttelemetry.Transformer.
+type TimingSource interface {
+	CurrentLap() int16
+	CurrentLaptime() time.Duration
+	LastLaptime() time.Duration
+	IsOnCircuit() bool
+}
+
+// Entry is a single row of the live standings.
+type Entry struct {
+	Position         int    `json:"position"`
+	Name             string `json:"name"`
+	CurrentLap       int16  `json:"currentLap"`
+	LapsCompleted    int    `json:"lapsCompleted"`
+	CurrentLaptimeMs int64  `json:"currentLaptimeMs"`
+	LastLaptimeMs    int64  `json:"lastLaptimeMs"`
+	BestLaptimeMs    int64  `json:"bestLaptimeMs"`
+	TotalTimeMs      int64  `json:"totalTimeMs"`
+	GapMs            int64  `json:"gapMs"`
+	LapsDown         int    `json:"lapsDown"`
+	// GapAheadMs and GapBehindMs are the intervals to the drivers ahead and behind, zero when there is no driver
+	// ahead or behind or they have not completed a lap in common. See Board.GapAhead.
+	GapAheadMs  int64 `json:"gapAheadMs"`
+	GapBehindMs int64 `json:"gapBehindMs"`
+	OnCircuit   bool  `json:"onCircuit"`
+}
+
+// Feed holds a copy of the lap timing of a driver for a board. Sources are sampled by Board.Update on the goroutine
+// running the board, so a gttelemetry.Transformer owned by a client must not be added to a board directly, as it is
+// updated on the decoding goroutine. Instead, copy its timing into a Feed from gttelemetry.Options.OnFrame and add
+// the feed to the board. The zero value is ready to use.
+type Feed struct {
+	mu     sync.RWMutex
+	timing timing
+}
+
+// timing is a consistent reading of the lap timing of a source.
+type timing struct {
+	currentLap     int16
+	currentLaptime time.Duration
+	lastLaptime    time.Duration
+	onCircuit      bool
+}
+
+// Observe copies the lap timing of the source into the feed.
+func (f *Feed) Observe(source TimingSource) {
+	observed := sample(source)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.timing = observed
+}
+
+// CurrentLap returns the lap counter at the last observation.
+func (f *Feed) CurrentLap() int16 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.timing.currentLap
+}
+
+// CurrentLaptime returns the time on the current lap at the last observation.
+func (f *Feed) CurrentLaptime() time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.timing.currentLaptime
+}
+
+// LastLaptime returns the time of the last completed lap at the last observation.
+func (f *Feed) LastLaptime() time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.timing.lastLaptime
+}
+
+// IsOnCircuit reports whether the driver was on the circuit at the last observation.
+func (f *Feed) IsOnCircuit() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.timing.onCircuit
+}
+
+// sample reads the lap timing of a source.
+func sample(source TimingSource) timing {
+	return timing{
+		currentLap:     source.CurrentLap(),
+		currentLaptime: source.CurrentLaptime(),
+		lastLaptime:    source.LastLaptime(),
+		onCircuit:      source.IsOnCircuit(),
+	}
+}
+
+type source struct {
+	name      string
+	telemetry TimingSource
+	// timing holds the lap timing read by the last update, so that the standings never read the source
+	timing        timing
+	lastLap       int16
+	lapsCompleted int
+	totalTime     time.Duration
+	bestLaptime   time.Duration
+	// splits holds the accumulated race time at the end of each completed lap
+	splits []time.Duration
+}
+
+// Board keeps track of completed laps and accumulated race time for each source.
+type Board struct {
+	mu      sync.RWMutex
+	sources []*source
+}
+
+// NewBoard creates an empty timing board.
+func NewBoard() *Board {
+	return &Board{}
+}
+
+// AddSource adds a named timing source to the board. The source is read only by Update, which may run on another
+// goroutine than the one updating the source, so it must be safe for concurrent use, such as a Feed.
+func (b *Board) AddSource(name string, telemetry TimingSource) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sources = append(b.sources, &source{name: name, telemetry: telemetry})
+}
+
+// Update samples every source and records any laps completed since the previous update. The standings reflect the
+// sources as of the last update.
+func (b *Board) Update() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, src := range b.sources {
+		src.update()
+	}
+}
+
+// Standings returns the current standings ordered by laps completed and then accumulated race time.
+func (b *Board) Standings() []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ranked := b.ranked()
+	entries := make([]Entry, 0, len(ranked))
+
+	for i, src := range ranked {
+		entry := Entry{
+			Position:         i + 1,
+			Name:             src.name,
+			CurrentLap:       src.timing.currentLap,
+			LapsCompleted:    src.lapsCompleted,
+			CurrentLaptimeMs: src.timing.currentLaptime.Milliseconds(),
+			LastLaptimeMs:    src.timing.lastLaptime.Milliseconds(),
+			BestLaptimeMs:    src.bestLaptime.Milliseconds(),
+			TotalTimeMs:      src.totalTime.Milliseconds(),
+			OnCircuit:        src.timing.onCircuit,
+		}
+
+		if i > 0 {
+			entry.LapsDown = ranked[0].lapsCompleted - src.lapsCompleted
+			if entry.LapsDown == 0 {
+				entry.GapMs = (src.totalTime - ranked[0].totalTime).Milliseconds()
+			}
+
+			entry.GapAheadMs = gap(ranked[i-1], src).Milliseconds()
+			entries[i-1].GapBehindMs = entry.GapAheadMs
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// GapAhead returns the interval from the named driver to the driver ahead of them in the standings, and false when
+// the driver is not on the board, is leading or has not completed a lap. The interval is the difference in race time
+// at the end of the last lap both drivers completed, as the packets from the game carry no timing of other cars, so a
+// lapped driver is compared with the driver ahead where they crossed the line on the same lap.
+func (b *Board) GapAhead(name string) (time.Duration, bool) {
+	return b.interval(name, -1)
+}
+
+// GapBehind returns the interval from the named driver to the driver behind them in the standings, measured in the
+// same way as GapAhead.
+func (b *Board) GapBehind(name string) (time.Duration, bool) {
+	return b.interval(name, 1)
+}
+
+// interval returns the gap between the named driver and the driver offset places from them in the standings.
+func (b *Board) interval(name string, offset int) (time.Duration, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	ranked := b.ranked()
+
+	for i, src := range ranked {
+		if src.name != name {
+			continue
+		}
+
+		other := i + offset
+		if other < 0 || other >= len(ranked) {
+			return 0, false
+		}
+
+		ahead, behind := ranked[min(i, other)], ranked[max(i, other)]
+		if behind.lapsCompleted == 0 {
+			return 0, false
+		}
+
+		return gap(ahead, behind), true
+	}
+
+	return 0, false
+}
+
+// ranked returns the sources ordered by laps completed and then accumulated race time.
+func (b *Board) ranked() []*source {
+	ranked := slices.Clone(b.sources)
+
+	slices.SortStableFunc(ranked, func(a, b *source) int {
+		if a.lapsCompleted != b.lapsCompleted {
+			return cmp.Compare(b.lapsCompleted, a.lapsCompleted)
+		}
+
+		return cmp.Compare(a.totalTime, b.totalTime)
+	})
+
+	return ranked
+}
+
+// gap returns how far behind the first source the second source crossed the line at the end of the last lap both
+// completed, or zero when they have no lap in common.
+func gap(ahead, behind *source) time.Duration {
+	lap := min(len(ahead.splits), len(behind.splits))
+	if lap == 0 {
+		return 0
+	}
+
+	return behind.splits[lap-1] - ahead.splits[lap-1]
+}
+
+// update records a completed lap when the lap counter of the source advances and resets the source when the lap
+// counter goes backwards, such as when a race is restarted.
+func (s *source) update() {
+	s.timing = sample(s.telemetry)
+	lap := s.timing.currentLap
+
+	switch {
+	case lap < s.lastLap:
+		s.lapsCompleted = 0
+		s.totalTime = 0
+		s.bestLaptime = 0
+		s.splits = nil
+	case lap > s.lastLap && s.lastLap > 0:
+		laptime := s.timing.lastLaptime
+		if laptime > 0 {
+			s.lapsCompleted++
+			s.totalTime += laptime
+			s.splits = append(s.splits, s.totalTime)
+
+			if s.bestLaptime == 0 || laptime < s.bestLaptime {
+				s.bestLaptime = laptime
+			}
+		}
+	}
+
+	s.lastLap = lap
+}
@@ -0,0 +1,328 @@
+package livetiming_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/livetiming"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type LiveTimingTestSuite struct {
+	suite.Suite
+
+	board *livetiming.Board
+	alice *gttelemetry.Transformer
+	bob   *gttelemetry.Transformer
+}
+
+func TestLiveTimingTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LiveTimingTestSuite))
+}
+
+func (suite *LiveTimingTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.alice = gttelemetry.NewTransformer(inventory)
+	suite.bob = gttelemetry.NewTransformer(inventory)
+
+	suite.board = livetiming.NewBoard()
+	suite.board.AddSource("Alice", suite.alice)
+	suite.board.AddSource("Bob", suite.bob)
+}
+
+// countingSource is a timing source that counts how often it is sampled.
+type countingSource struct {
+	*gttelemetry.Transformer
+
+	samples atomic.Int64
+}
+
+func (c *countingSource) CurrentLap() int16 {
+	c.samples.Add(1)
+
+	return c.Transformer.CurrentLap()
+}
+
+func (suite *LiveTimingTestSuite) completeLap(transformer *gttelemetry.Transformer, laptimeMs int32) {
+	transformer.RawTelemetry.CurrentLap++
+	transformer.RawTelemetry.LastLaptime = laptimeMs
+	suite.board.Update()
+}
+
+func (suite *LiveTimingTestSuite) startRace() {
+	suite.alice.RawTelemetry.CurrentLap = 1
+	suite.bob.RawTelemetry.CurrentLap = 1
+	suite.board.Update()
+}
+
+func (suite *LiveTimingTestSuite) TestStandingsOrderByLapsThenTotalTime() {
+	// Arrange
+	suite.startRace()
+	suite.completeLap(suite.alice, 90000)
+	suite.completeLap(suite.bob, 88000)
+	suite.completeLap(suite.alice, 91000)
+	suite.completeLap(suite.bob, 92500)
+
+	// Act
+	standings := suite.board.Standings()
+
+	// Assert
+	suite.Require().Len(standings, 2)
+	suite.Equal("Bob", standings[0].Name)
+	suite.Equal(1, standings[0].Position)
+	suite.Equal(int64(180500), standings[0].TotalTimeMs)
+	suite.Equal(int64(88000), standings[0].BestLaptimeMs)
+	suite.Equal("Alice", standings[1].Name)
+	suite.Equal(2, standings[1].Position)
+	suite.Equal(int64(500), standings[1].GapMs)
+	suite.Equal(int64(90000), standings[1].BestLaptimeMs)
+}
+
+func (suite *LiveTimingTestSuite) TestStandingsReportLapsDown() {
+	// Arrange
+	suite.startRace()
+	suite.completeLap(suite.alice, 90000)
+	suite.completeLap(suite.alice, 90000)
+	suite.completeLap(suite.bob, 95000)
+
+	// Act
+	standings := suite.board.Standings()
+
+	// Assert
+	suite.Equal("Alice", standings[0].Name)
+	suite.Equal(1, standings[1].LapsDown)
+	suite.Equal(int64(0), standings[1].GapMs)
+}
+
+func (suite *LiveTimingTestSuite) TestStandingsReportIntervals() {
+	// Arrange
+	suite.startRace()
+	suite.completeLap(suite.alice, 90000)
+	suite.completeLap(suite.bob, 91500)
+
+	// Act
+	standings := suite.board.Standings()
+
+	// Assert
+	suite.Equal(int64(0), standings[0].GapAheadMs)
+	suite.Equal(int64(1500), standings[0].GapBehindMs)
+	suite.Equal(int64(1500), standings[1].GapAheadMs)
+	suite.Equal(int64(0), standings[1].GapBehindMs)
+}
+
+func (suite *LiveTimingTestSuite) TestGapAheadComparesLappedDriverOnCommonLap() {
+	// Arrange
+	suite.startRace()
+	suite.completeLap(suite.alice, 90000)
+	suite.completeLap(suite.alice, 90000)
+	suite.completeLap(suite.bob, 95000)
+
+	// Act
+	ahead, aheadOK := suite.board.GapAhead("Bob")
+	behind, behindOK := suite.board.GapBehind("Alice")
+
+	// Assert
+	suite.True(aheadOK)
+	suite.Equal(5*time.Second, ahead)
+	suite.True(behindOK)
+	suite.Equal(5*time.Second, behind)
+}
+
+func (suite *LiveTimingTestSuite) TestGapsUnavailable() {
+	// Arrange
+	suite.startRace()
+	suite.completeLap(suite.alice, 90000)
+
+	tests := map[string]func() (time.Duration, bool){
+		"leader has no driver ahead":            func() (time.Duration, bool) { return suite.board.GapAhead("Alice") },
+		"last driver has no driver behind":      func() (time.Duration, bool) { return suite.board.GapBehind("Bob") },
+		"driver behind has not completed a lap": func() (time.Duration, bool) { return suite.board.GapBehind("Alice") },
+		"unknown driver":                        func() (time.Duration, bool) { return suite.board.GapAhead("Carol") },
+	}
+
+	for name, gap := range tests {
+		suite.Run(name, func() {
+			// Act
+			_, ok := gap()
+
+			// Assert
+			suite.False(ok)
+		})
+	}
+}
+
+func (suite *LiveTimingTestSuite) TestStandingsResetWhenRaceRestarts() {
+	// Arrange
+	suite.startRace()
+	suite.completeLap(suite.alice, 90000)
+	suite.alice.RawTelemetry.CurrentLap = 0
+	suite.board.Update()
+
+	// Act
+	standings := suite.board.Standings()
+
+	// Assert
+	for _, entry := range standings {
+		suite.Equal(0, entry.LapsCompleted)
+		suite.Equal(int64(0), entry.TotalTimeMs)
+	}
+}
+
+func (suite *LiveTimingTestSuite) TestHandlerServesStandings() {
+	// Arrange
+	suite.startRace()
+	suite.completeLap(suite.bob, 88000)
+
+	server := httptest.NewServer(livetiming.NewServer(suite.board, livetiming.Options{}).Handler())
+	defer server.Close()
+
+	// Act
+	resp, err := http.Get(server.URL + "/standings.json") //nolint:noctx // test request
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	var standings []livetiming.Entry
+
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&standings))
+
+	// Assert
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Require().Len(standings, 2)
+	suite.Equal("Bob", standings[0].Name)
+}
+
+func (suite *LiveTimingTestSuite) TestHandlerServesTimingPage() {
+	// Arrange
+	server := httptest.NewServer(livetiming.NewServer(suite.board, livetiming.Options{}).Handler())
+	defer server.Close()
+
+	// Act
+	resp, err := http.Get(server.URL + "/") //nolint:noctx // test request
+	suite.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	// Assert
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Contains(resp.Header.Get("Content-Type"), "text/html")
+}
+
+func (suite *LiveTimingTestSuite) TestRunStopsSamplingWhenServerFails() {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	defer listener.Close()
+
+	source := &countingSource{Transformer: suite.alice}
+	suite.board.AddSource("Carol", source)
+
+	server := livetiming.NewServer(suite.board, livetiming.Options{
+		Address:        listener.Addr().String(),
+		UpdateInterval: time.Millisecond,
+	})
+
+	// Act
+	err = server.Run(context.Background())
+	samples := source.samples.Load()
+
+	time.Sleep(20 * time.Millisecond)
+
+	// Assert
+	suite.Require().ErrorContains(err, "address already in use")
+	suite.Equal(samples, source.samples.Load(), "the board is not sampled after Run returns")
+}
+
+func (suite *LiveTimingTestSuite) TestStandingsReflectLastUpdate() {
+	// Arrange
+	source := &countingSource{Transformer: suite.alice}
+	suite.board.AddSource("Carol", source)
+	suite.startRace()
+
+	samples := source.samples.Load()
+	suite.alice.RawTelemetry.CurrentLap = 2
+
+	// Act
+	standings := suite.board.Standings()
+
+	// Assert
+	suite.Equal(samples, source.samples.Load(), "the standings do not read the sources")
+
+	for _, entry := range standings {
+		suite.Equal(int16(1), entry.CurrentLap)
+	}
+}
+
+func (suite *LiveTimingTestSuite) TestFeedCopiesTiming() {
+	// Arrange
+	feed := &livetiming.Feed{}
+	suite.alice.RawTelemetry.CurrentLap = 3
+	suite.alice.RawTelemetry.LastLaptime = 90000
+
+	// Act
+	feed.Observe(suite.alice)
+	suite.alice.RawTelemetry.CurrentLap = 4
+
+	// Assert
+	suite.Equal(int16(3), feed.CurrentLap())
+	suite.Equal(90*time.Second, feed.LastLaptime())
+	suite.Equal(suite.alice.IsOnCircuit(), feed.IsOnCircuit())
+}
+
+func (suite *LiveTimingTestSuite) TestFeedCanBeObservedWhileBoardUpdates() {
+	// Arrange
+	feed := &livetiming.Feed{}
+	board := livetiming.NewBoard()
+	board.AddSource("Driver", feed)
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://../../data/replays/demo.gtz",
+		LogLevel: "error",
+		OnFrame:  func(transformer *gttelemetry.Transformer) { feed.Observe(transformer) },
+	})
+	suite.Require().NoError(err)
+
+	done := make(chan error, 1)
+
+	// Act
+	go func() {
+		for _, err := range client.Scan(context.Background()) {
+			if err != nil {
+				done <- err
+
+				return
+			}
+		}
+
+		done <- nil
+	}()
+
+	for {
+		board.Update()
+		_ = board.Standings()
+
+		select {
+		case err := <-done:
+			// Assert
+			suite.Require().NoError(err)
+
+			board.Update()
+			suite.Equal(client.Telemetry.CurrentLap(), board.Standings()[0].CurrentLap)
+
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package livetiming
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/pkg/httpauth"
+)
+
+const (
+	// DefaultAddress is the listen address used when no address is configured.
+	DefaultAddress = ":8080"
+	// DefaultUpdateInterval is the interval at which sources are sampled when no interval is configured.
+	DefaultUpdateInterval = 250 * time.Millisecond
+
+	shutdownTimeout   = 5 * time.Second
+	readHeaderTimeout = 5 * time.Second
+)
+
+//go:embed timing.html
+var timingPage []byte
+
+// Options configures a Server.
+type Options struct {
+	// Address is the TCP address to listen on, defaults to ":8080".
+	Address string
+	// UpdateInterval is how often the sources are sampled, defaults to 250ms.
+	UpdateInterval time.Duration
+	// Token is required by every request when set, see httpauth.RequireToken.
+	Token string
+	// TLS serves the page over HTTPS when a certificate is set.
+	TLS httpauth.TLS
+	// Logger is used for server log messages, defaults to a disabled logger.
+	Logger *zerolog.Logger
+}
+
+// Server serves the live timing page and standings of a Board over HTTP.
+type Server struct {
+	board    *Board
+	address  string
+	interval time.Duration
+	token    string
+	tls      httpauth.TLS
+	log      zerolog.Logger
+}
+
+// NewServer creates a server for the given board.
+func NewServer(board *Board, opts Options) *Server {
+	if opts.Address == "" {
+		opts.Address = DefaultAddress
+	}
+
+	if opts.UpdateInterval <= 0 {
+		opts.UpdateInterval = DefaultUpdateInterval
+	}
+
+	log := zerolog.Nop()
+	if opts.Logger != nil {
+		log = *opts.Logger
+	}
+
+	return &Server{
+		board:    board,
+		address:  opts.Address,
+		interval: opts.UpdateInterval,
+		token:    opts.Token,
+		tls:      opts.TLS,
+		log:      log,
+	}
+}
+
+// Handler returns the HTTP handler serving the timing page at "/" and the standings at "/standings.json". When a
+// token is configured, the page is opened with the token in the query string, e.g. /?token=secret.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(timingPage)
+	})
+
+	mux.HandleFunc("GET /standings.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		err := json.NewEncoder(w).Encode(s.board.Standings())
+		if err != nil {
+			s.log.Error().Err(err).Msg("failed to encode standings")
+		}
+	})
+
+	return httpauth.RequireToken(s.token, mux)
+}
+
+// Run samples the board sources and serves HTTP requests until the context is cancelled. Sampling stops before Run
+// returns, including when the server fails to start.
+func (s *Server) Run(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              s.address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	wg.Go(func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+				defer cancel()
+
+				_ = httpServer.Shutdown(shutdownCtx)
+
+				return
+			case <-ticker.C:
+				s.board.Update()
+			}
+		}
+	})
+
+	s.log.Info().Str("address", s.address).Msg("serving live timing")
+
+	err := httpauth.ListenAndServe(httpServer, s.tls)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve live timing: %w", err)
+	}
+
+	return nil
+}
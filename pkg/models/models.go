@@ -24,6 +24,14 @@ const (
 	GameStateReplay
 )
 
+var gameStateName = map[GameState]string{ //nolint:gochecknoglobals // helper for string representation of GameState
+	GameStateUnknown:  "unknown",
+	GameStateMainMenu: "mainMenu",
+	GameStateRaceMenu: "raceMenu",
+	GameStateLive:     "live",
+	GameStateReplay:   "replay",
+}
+
 type RaceType int
 
 const (
@@ -31,6 +39,9 @@ const (
 	RaceTypeSprint
 	RaceTypeEndurance
 	RaceTypeTimeTrial
+	RaceTypeQualifying
+	RaceTypeLicenceTest
+	RaceTypeMission
 )
 
 type CoordinateType int
@@ -141,6 +152,15 @@ func (c *CoordinateNorm) String() string {
 	return fmt.Sprintf("x:%d,y:%d,z:%d", c.X, c.Y, c.Z)
 }
 
+// String returns a string representation of the GameState.
+func (g *GameState) String() string {
+	if name, ok := gameStateName[*g]; ok {
+		return name
+	}
+
+	return gameStateName[GameStateUnknown]
+}
+
 // String returns a string representation of the SurfaceType.
 func (s *SurfaceType) String() string {
 	if name, ok := surfaceTypeName[*s]; ok {
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// SnapshotSchemaVersion is the semantic version of the FrameSnapshot schema, which is versioned independently of the
+// module. Within a major version fields and channels are only ever added, never renamed, removed or changed in type
+// or meaning, so integrators can rely on any field they read today being present in later minor versions. The minor
+// version is incremented when fields are added, and the patch version for corrections to the documentation.
+const SnapshotSchemaVersion = "1.1.0"
+
+// FrameSnapshot is the canonical form of a single frame of telemetry, shared by the exporters, servers and tools that
+// hand frames to other applications. Durations are in milliseconds, distances in metres and speeds in metres per
+// second unless the name of a channel says otherwise.
+type FrameSnapshot struct {
+	// SchemaVersion is the SnapshotSchemaVersion the snapshot was produced with.
+	SchemaVersion string `json:"schemaVersion"`
+	SequenceID    uint32 `json:"sequenceId"`
+	// ReceivedAt is the time the frame was received, omitted when it is not known.
+	ReceivedAt time.Time `json:"receivedAt,omitzero"`
+	// Source is the IP address and port of the console the frame was sent from, e.g. "192.168.1.20:33739", omitted
+	// for frames read from a file. Added in 1.1.0.
+	Source string `json:"source,omitempty"`
+	// Format is the telemetry format of the packet the frame was decoded from.
+	Format Name `json:"format"`
+	// GameState is the name of the state of the game, e.g. "live" or "raceMenu".
+	GameState           string        `json:"gameState"`
+	Flags               SnapshotFlags `json:"flags"`
+	VehicleID           uint32        `json:"vehicleId"`
+	VehicleManufacturer string        `json:"vehicleManufacturer"`
+	VehicleModel        string        `json:"vehicleModel"`
+	CurrentLap          int16         `json:"currentLap"`
+	RaceLaps            int16         `json:"raceLaps"`
+	CurrentLaptimeMs    int64         `json:"currentLaptimeMs"`
+	LastLaptimeMs       int64         `json:"lastLaptimeMs"`
+	BestLaptimeMs       int64         `json:"bestLaptimeMs"`
+	// Position is the position of the vehicle on the circuit map.
+	Position Coordinate         `json:"position"`
+	Velocity Vector             `json:"velocity"`
+	Rotation RotationalEnvelope `json:"rotation"`
+	// Channels holds the value of every numeric channel, keyed by channel name, e.g. "engineRPM". Channels without a
+	// value in the frame are left out.
+	Channels map[string]float32 `json:"channels"`
+}
+
+// SnapshotFlags are the state flags of a FrameSnapshot.
+type SnapshotFlags struct {
+	Live             bool `json:"live"`
+	GamePaused       bool `json:"gamePaused"`
+	Loading          bool `json:"loading"`
+	InGear           bool `json:"inGear"`
+	HasTurbo         bool `json:"hasTurbo"`
+	RevLimiterAlert  bool `json:"revLimiterAlert"`
+	HandbrakeActive  bool `json:"handbrakeActive"`
+	HeadlightsActive bool `json:"headlightsActive"`
+	HighBeamActive   bool `json:"highBeamActive"`
+	LowBeamActive    bool `json:"lowBeamActive"`
+	ASMActive        bool `json:"asmActive"`
+	TCSActive        bool `json:"tcsActive"`
+}
@@ -0,0 +1,90 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Options configures an Exporter.
+type Options struct {
+	// Address is the host and port of the OSC receiver, e.g. "127.0.0.1:9000".
+	Address string
+	// Prefix is prepended to each channel name to form the OSC address, defaults to "/gt".
+	Prefix string
+	// Channels lists the names of the telemetry channels to send.
+	Channels []string
+}
+
+// Exporter sends selected telemetry channels to an OSC receiver over UDP.
+type Exporter struct {
+	conn      net.Conn
+	channels  []string
+	addresses []string
+}
+
+// NewExporter creates an exporter that sends the configured channels to the OSC receiver at the given address.
+func NewExporter(opts Options) (*Exporter, error) {
+	if len(opts.Channels) == 0 {
+		return nil, ErrNoChannels
+	}
+
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	if !strings.HasPrefix(prefix, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, prefix)
+	}
+
+	conn, err := net.Dial("udp", opts.Address)
+	if err != nil {
+		return nil, fmt.Errorf("dial OSC receiver: %w", err)
+	}
+
+	addresses := make([]string, len(opts.Channels))
+	for i, channel := range opts.Channels {
+		addresses[i] = strings.TrimSuffix(prefix, "/") + "/" + channel
+	}
+
+	return &Exporter{
+		conn:      conn,
+		channels:  opts.Channels,
+		addresses: addresses,
+	}, nil
+}
+
+// Send reads the configured channels from the source and sends them to the receiver as a single OSC bundle.
+func (e *Exporter) Send(source ChannelSource) error {
+	messages := make([]Message, 0, len(e.channels))
+
+	for i, channel := range e.channels {
+		value, ok := source.Channel(channel)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+		}
+
+		messages = append(messages, Message{
+			Address: e.addresses[i],
+			Args:    []any{value},
+		})
+	}
+
+	packet, err := Bundle(messages)
+	if err != nil {
+		return fmt.Errorf("encode OSC bundle: %w", err)
+	}
+
+	_, err = e.conn.Write(packet)
+	if err != nil {
+		return fmt.Errorf("send OSC bundle: %w", err)
+	}
+
+	return nil
+}
+
+// Close releases the network connection used by the exporter.
+func (e *Exporter) Close() error {
+	return e.conn.Close()
+}
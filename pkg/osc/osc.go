@@ -0,0 +1,111 @@
+// Package osc encodes telemetry channels as Open Sound Control messages and sends them to OSC receivers such as
+// TouchDesigner or Max over UDP.
+package osc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// DefaultPrefix is the address prefix used when no prefix is configured.
+	DefaultPrefix = "/gt"
+
+	bundleTag = "#bundle"
+
+	// timetagImmediately is the OSC time tag indicating that a bundle should be processed on receipt.
+	timetagImmediately = 1
+)
+
+var (
+	// ErrInvalidAddress indicates an OSC address that does not begin with a forward slash.
+	ErrInvalidAddress = errors.New("OSC address must begin with '/'")
+	// ErrUnsupportedArgument indicates a message argument with a type that cannot be encoded.
+	ErrUnsupportedArgument = errors.New("unsupported OSC argument type")
+	// ErrUnknownChannel indicates a channel that is not provided by the channel source.
+	ErrUnknownChannel = errors.New("unknown channel")
+	// ErrNoChannels indicates that an exporter was configured without any channels.
+	ErrNoChannels = errors.New("no channels configured")
+)
+
+// ChannelSource provides the current value of named telemetry channels, as implemented by gttelemetry.Transformer.
+type ChannelSource interface {
+	Channel(name string) (float32, bool)
+}
+
+// Message is a single OSC message consisting of an address pattern and a list of arguments. Supported argument
+// types are int32, float32, string and bool.
+type Message struct {
+	Address string
+	Args    []any
+}
+
+// MarshalBinary encodes the message in the OSC 1.0 binary format.
+func (m Message) MarshalBinary() ([]byte, error) {
+	if !strings.HasPrefix(m.Address, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, m.Address)
+	}
+
+	typeTags := []byte{','}
+	args := &bytes.Buffer{}
+
+	for _, arg := range m.Args {
+		switch value := arg.(type) {
+		case int32:
+			typeTags = append(typeTags, 'i')
+			_ = binary.Write(args, binary.BigEndian, value)
+		case float32:
+			typeTags = append(typeTags, 'f')
+			_ = binary.Write(args, binary.BigEndian, math.Float32bits(value))
+		case string:
+			typeTags = append(typeTags, 's')
+			writePaddedString(args, value)
+		case bool:
+			if value {
+				typeTags = append(typeTags, 'T')
+			} else {
+				typeTags = append(typeTags, 'F')
+			}
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedArgument, arg)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	writePaddedString(buf, m.Address)
+	writePaddedString(buf, string(typeTags))
+	buf.Write(args.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// Bundle encodes a set of messages as a single OSC bundle that is processed immediately by the receiver.
+func Bundle(messages []Message) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writePaddedString(buf, bundleTag)
+	_ = binary.Write(buf, binary.BigEndian, uint64(timetagImmediately))
+
+	for _, message := range messages {
+		element, err := message.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		_ = binary.Write(buf, binary.BigEndian, int32(len(element))) //nolint:gosec // Element size is bounded by UDP
+		buf.Write(element)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writePaddedString writes a null terminated string padded to a multiple of four bytes.
+func writePaddedString(buf *bytes.Buffer, value string) {
+	buf.WriteString(value)
+
+	padding := 4 - len(value)%4
+	buf.Write(make([]byte, padding))
+}
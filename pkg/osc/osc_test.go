@@ -0,0 +1,154 @@
+package osc_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/osc"
+)
+
+type fakeSource map[string]float32
+
+func (f fakeSource) Channel(name string) (float32, bool) {
+	value, ok := f[name]
+
+	return value, ok
+}
+
+type OSCTestSuite struct {
+	suite.Suite
+}
+
+func TestOSCTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(OSCTestSuite))
+}
+
+func (suite *OSCTestSuite) TestMessageMarshalBinaryEncodesFloat() {
+	// Arrange
+	message := osc.Message{Address: "/gt/rpm", Args: []any{float32(1)}}
+	want := []byte{
+		'/', 'g', 't', '/', 'r', 'p', 'm', 0,
+		',', 'f', 0, 0,
+		0x3f, 0x80, 0x00, 0x00,
+	}
+
+	// Act
+	got, err := message.MarshalBinary()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(want, got)
+}
+
+func (suite *OSCTestSuite) TestMessageMarshalBinaryEncodesMixedArguments() {
+	// Arrange
+	message := osc.Message{Address: "/a", Args: []any{int32(2), "gear", true}}
+	want := []byte{
+		'/', 'a', 0, 0,
+		',', 'i', 's', 'T', 0, 0, 0, 0,
+		0, 0, 0, 2,
+		'g', 'e', 'a', 'r', 0, 0, 0, 0,
+	}
+
+	// Act
+	got, err := message.MarshalBinary()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(want, got)
+}
+
+func (suite *OSCTestSuite) TestMessageMarshalBinaryRejectsInvalidAddress() {
+	// Arrange
+	message := osc.Message{Address: "gt/rpm"}
+
+	// Act
+	_, err := message.MarshalBinary()
+
+	// Assert
+	suite.Require().ErrorIs(err, osc.ErrInvalidAddress)
+}
+
+func (suite *OSCTestSuite) TestMessageMarshalBinaryRejectsUnsupportedArgument() {
+	// Arrange
+	message := osc.Message{Address: "/gt/rpm", Args: []any{float64(1)}}
+
+	// Act
+	_, err := message.MarshalBinary()
+
+	// Assert
+	suite.Require().ErrorIs(err, osc.ErrUnsupportedArgument)
+}
+
+func (suite *OSCTestSuite) TestBundleWrapsMessages() {
+	// Arrange
+	messages := []osc.Message{{Address: "/a", Args: []any{int32(1)}}}
+
+	// Act
+	got, err := osc.Bundle(messages)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal([]byte("#bundle\x00"), got[:8])
+	suite.Equal([]byte{0, 0, 0, 0, 0, 0, 0, 1}, got[8:16])
+	suite.Equal([]byte{0, 0, 0, 12}, got[16:20])
+	suite.Len(got, 32)
+}
+
+func (suite *OSCTestSuite) TestNewExporterRequiresChannels() {
+	// Act
+	_, err := osc.NewExporter(osc.Options{Address: "127.0.0.1:9000"})
+
+	// Assert
+	suite.Require().ErrorIs(err, osc.ErrNoChannels)
+}
+
+func (suite *OSCTestSuite) TestExporterSendDeliversBundle() {
+	// Arrange
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	defer listener.Close()
+
+	exporter, err := osc.NewExporter(osc.Options{
+		Address:  listener.LocalAddr().String(),
+		Prefix:   "/rig/",
+		Channels: []string{"engineRPM"},
+	})
+	suite.Require().NoError(err)
+
+	defer exporter.Close()
+
+	want, err := osc.Bundle([]osc.Message{{Address: "/rig/engineRPM", Args: []any{float32(7500)}}})
+	suite.Require().NoError(err)
+
+	// Act
+	err = exporter.Send(fakeSource{"engineRPM": 7500})
+
+	// Assert
+	suite.Require().NoError(err)
+
+	buf := make([]byte, 1024)
+
+	suite.Require().NoError(listener.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := listener.ReadFrom(buf)
+	suite.Require().NoError(err)
+	suite.Equal(want, buf[:n])
+}
+
+func (suite *OSCTestSuite) TestExporterSendRejectsUnknownChannel() {
+	// Arrange
+	exporter, err := osc.NewExporter(osc.Options{Address: "127.0.0.1:9000", Channels: []string{"missing"}})
+	suite.Require().NoError(err)
+
+	defer exporter.Close()
+
+	// Act
+	err = exporter.Send(fakeSource{})
+
+	// Assert
+	suite.Require().ErrorIs(err, osc.ErrUnknownChannel)
+}
@@ -0,0 +1,212 @@
+// Package process streams a recording through visitors in a single pass, so that offline tools such as lap splitters,
+// exporters and statistics collectors share one read loop instead of each reading the recording again.
+//
+// Every frame is passed to each visitor in the order they are given. Visitors that also implement Finisher are called
+// once more after the last frame, such as to flush an export.
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
+)
+
+// DefaultProgressInterval is the time between progress reports when no interval is configured.
+const DefaultProgressInterval = 250 * time.Millisecond
+
+// ErrStop can be returned by a visitor to stop processing early, such as once a lap has been found. It is not
+// reported as an error and the visitors are still finished.
+var ErrStop = errors.New("stop processing")
+
+// Visitor is passed every frame of a recording. The Transformer is reused for every frame, so copy any values that are
+// needed after Visit returns.
+type Visitor interface {
+	Visit(t *gttelemetry.Transformer) error
+}
+
+// VisitorFunc adapts a function, such as VideoCSVWriter.Write or Timeline.Update, to a Visitor.
+type VisitorFunc func(t *gttelemetry.Transformer) error
+
+// Finisher is implemented by visitors that complete their work after the last frame, such as flushing an export.
+type Finisher interface {
+	Finish() error
+}
+
+// Progress reports how far through a recording processing has got.
+type Progress struct {
+	// Frames is the number of frames visited.
+	Frames int
+	// BytesRead is the number of bytes of the recording file read.
+	BytesRead int64
+	// TotalBytes is the size of the recording file.
+	TotalBytes int64
+	// Elapsed is the time since processing started.
+	Elapsed time.Duration
+}
+
+// Summary describes a completed pass through a recording.
+type Summary struct {
+	// Frames is the number of frames visited.
+	Frames int
+	// Stopped is whether a visitor stopped processing before the end of the recording.
+	Stopped bool
+	// Elapsed is the time processing took.
+	Elapsed time.Duration
+}
+
+// Options configures a pass through a recording.
+type Options struct {
+	// Password decrypts encrypted recordings.
+	Password string
+	// Progress is called at most once every ProgressInterval while processing, and once more at the end.
+	Progress func(Progress)
+	// ProgressInterval is the minimum time between progress reports, defaults to DefaultProgressInterval.
+	ProgressInterval time.Duration
+	// Workers is the number of goroutines parsing the packets of the recording, see Client.ScanParallel. Zero parses
+	// them on the reading goroutine like Client.Scan, less than zero uses one worker for each CPU.
+	Workers int
+	// Logger receives the log messages of the client reading the recording, which are discarded when nil.
+	Logger *logging.Logger
+}
+
+// Visit calls the function.
+func (f VisitorFunc) Visit(t *gttelemetry.Transformer) error {
+	return f(t)
+}
+
+// Fraction returns the fraction of the recording processed, from 0 to 1.
+func (p Progress) Fraction() float64 {
+	if p.TotalBytes <= 0 {
+		return 0
+	}
+
+	return min(float64(p.BytesRead)/float64(p.TotalBytes), 1)
+}
+
+// File passes every frame of the recording at path to the visitors, then finishes them.
+func File(path string, visitors ...Visitor) (Summary, error) {
+	return FileWithOptions(context.Background(), path, Options{}, visitors...)
+}
+
+// FileWithOptions passes every frame of the recording at path to the visitors, then finishes them, reporting progress
+// and stopping when the context is cancelled. When a visitor returns an error other than ErrStop, processing stops
+// and the visitors are not finished, so that partial output is not mistaken for a complete one.
+func FileWithOptions(ctx context.Context, path string, opts Options, visitors ...Visitor) (Summary, error) {
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = DefaultProgressInterval
+	}
+
+	if opts.Logger == nil {
+		nop := logging.Nop()
+		opts.Logger = &nop
+	}
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:            "file://" + path,
+		Logger:            opts.Logger,
+		RecordingPassword: opts.Password,
+	})
+	if err != nil {
+		return Summary{}, fmt.Errorf("open recording: %w", err)
+	}
+
+	started := time.Now()
+	lastReport := started
+	summary := Summary{}
+	progress := Progress{}
+
+	report := func() {
+		if opts.Progress != nil {
+			progress.Frames = summary.Frames
+			progress.Elapsed = time.Since(started)
+			opts.Progress(progress)
+		}
+	}
+
+	frames := client.Scan(ctx)
+	if opts.Workers != 0 {
+		frames = client.ScanParallel(ctx, opts.Workers)
+	}
+
+	for transformer, err := range frames {
+		if err != nil {
+			return summary, fmt.Errorf("read recording: %w", err)
+		}
+
+		summary.Frames++
+		progress.BytesRead, progress.TotalBytes = client.ScanPosition()
+
+		err = visit(transformer, visitors)
+		if errors.Is(err, ErrStop) {
+			summary.Stopped = true
+
+			break
+		}
+
+		if err != nil {
+			return summary, err
+		}
+
+		if opts.Progress != nil && time.Since(lastReport) >= opts.ProgressInterval {
+			report()
+
+			lastReport = time.Now()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return summary, fmt.Errorf("process recording: %w", ctx.Err())
+	}
+
+	if !summary.Stopped {
+		progress.BytesRead = progress.TotalBytes
+	}
+
+	report()
+
+	for _, visitor := range visitors {
+		if finisher, ok := visitor.(Finisher); ok {
+			err = finisher.Finish()
+			if err != nil {
+				return summary, fmt.Errorf("finish: %w", err)
+			}
+		}
+	}
+
+	summary.Elapsed = time.Since(started)
+
+	return summary, nil
+}
+
+// visit passes a frame to each visitor, stopping at the first error.
+func visit(transformer *gttelemetry.Transformer, visitors []Visitor) error {
+	for _, visitor := range visitors {
+		err := visitor.Visit(transformer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LapSplitter returns a visitor that passes the trace of each lap completed in the recording to fn, as recorded by
+// gttelemetry.LapTraceRecorder. Laps that were not driven from the start are skipped. Only the lap being traced is held
+// in memory, so recordings of any length can be split.
+func LapSplitter(fn func(trace gttelemetry.LapTrace) error) Visitor {
+	recorder := gttelemetry.NewLapTraceRecorder()
+	recorder.SetMaxRetainedLaps(1)
+
+	return VisitorFunc(func(t *gttelemetry.Transformer) error {
+		trace, completed := recorder.Update(t)
+		if !completed {
+			return nil
+		}
+
+		return fn(trace)
+	})
+}
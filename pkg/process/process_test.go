@@ -0,0 +1,200 @@
+package process_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/process"
+)
+
+const demoRecording = "../../data/replays/demo.gtz"
+
+var errVisitor = errors.New("visitor failed")
+
+type ProcessTestSuite struct {
+	suite.Suite
+}
+
+func TestProcessTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ProcessTestSuite))
+}
+
+// countingVisitor counts the frames it visits and whether it was finished.
+type countingVisitor struct {
+	frames   int
+	finished bool
+}
+
+func (v *countingVisitor) Visit(_ *gttelemetry.Transformer) error {
+	v.frames++
+
+	return nil
+}
+
+func (v *countingVisitor) Finish() error {
+	v.finished = true
+
+	return nil
+}
+
+func (suite *ProcessTestSuite) TestFileVisitsEveryFrameInOnePass() {
+	// Arrange
+	first := &countingVisitor{}
+	second := &countingVisitor{}
+
+	var sequenceIDs []uint32
+
+	ordered := process.VisitorFunc(func(t *gttelemetry.Transformer) error {
+		sequenceIDs = append(sequenceIDs, t.SequenceID())
+
+		return nil
+	})
+
+	// Act
+	summary, err := process.File(demoRecording, first, ordered, second)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Positive(summary.Frames)
+	suite.False(summary.Stopped)
+	suite.Equal(summary.Frames, first.frames)
+	suite.Equal(summary.Frames, second.frames)
+	suite.Len(sequenceIDs, summary.Frames)
+	suite.True(first.finished)
+	suite.True(second.finished)
+}
+
+func (suite *ProcessTestSuite) TestWorkersVisitTheSameFrames() {
+	// Arrange
+	sequenceIDs := func(workers int) []uint32 {
+		var ids []uint32
+
+		_, err := process.FileWithOptions(context.Background(), demoRecording, process.Options{Workers: workers},
+			process.VisitorFunc(func(t *gttelemetry.Transformer) error {
+				ids = append(ids, t.SequenceID())
+
+				return nil
+			}))
+		suite.Require().NoError(err)
+
+		return ids
+	}
+
+	// Act
+	sequential := sequenceIDs(0)
+	parallel := sequenceIDs(-1)
+
+	// Assert
+	suite.NotEmpty(sequential)
+	suite.Equal(sequential, parallel)
+}
+
+func (suite *ProcessTestSuite) TestVisitorCanStopEarly() {
+	// Arrange
+	counter := &countingVisitor{}
+	stopAfterTen := process.VisitorFunc(func(_ *gttelemetry.Transformer) error {
+		if counter.frames == 10 {
+			return process.ErrStop
+		}
+
+		return nil
+	})
+
+	// Act
+	summary, err := process.File(demoRecording, counter, stopAfterTen)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.True(summary.Stopped)
+	suite.Equal(10, summary.Frames)
+	suite.True(counter.finished)
+}
+
+func (suite *ProcessTestSuite) TestVisitorErrorSkipsFinish() {
+	// Arrange
+	counter := &countingVisitor{}
+	failing := process.VisitorFunc(func(_ *gttelemetry.Transformer) error { return errVisitor })
+
+	// Act
+	_, err := process.File(demoRecording, counter, failing)
+
+	// Assert
+	suite.Require().ErrorIs(err, errVisitor)
+	suite.Equal(1, counter.frames)
+	suite.False(counter.finished)
+}
+
+func (suite *ProcessTestSuite) TestProgressIsReported() {
+	// Arrange
+	var reports []process.Progress
+
+	opts := process.Options{
+		Progress:         func(progress process.Progress) { reports = append(reports, progress) },
+		ProgressInterval: 1,
+	}
+
+	// Act
+	summary, err := process.FileWithOptions(context.Background(), demoRecording, opts)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Greater(len(reports), 1)
+
+	last := reports[len(reports)-1]
+	suite.Equal(summary.Frames, last.Frames)
+	suite.Positive(last.TotalBytes)
+	suite.InDelta(1.0, last.Fraction(), 0)
+
+	for i := 1; i < len(reports); i++ {
+		suite.GreaterOrEqual(reports[i].BytesRead, reports[i-1].BytesRead)
+	}
+}
+
+func (suite *ProcessTestSuite) TestCancelledContextStopsProcessing() {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling := process.VisitorFunc(func(_ *gttelemetry.Transformer) error {
+		cancel()
+
+		return nil
+	})
+
+	// Act
+	_, err := process.FileWithOptions(ctx, demoRecording, process.Options{}, cancelling)
+
+	// Assert
+	suite.Require().ErrorIs(err, context.Canceled)
+}
+
+func (suite *ProcessTestSuite) TestMissingFileFails() {
+	// Act
+	_, err := process.File("missing.gtz")
+
+	// Assert
+	suite.Error(err)
+}
+
+func (suite *ProcessTestSuite) TestLapSplitterPassesCompletedLaps() {
+	// Arrange
+	var laps []int16
+
+	splitter := process.LapSplitter(func(trace gttelemetry.LapTrace) error {
+		laps = append(laps, trace.Lap)
+
+		return nil
+	})
+
+	// Act
+	_, err := process.File(demoRecording, splitter)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	for i := 1; i < len(laps); i++ {
+		suite.Greater(laps[i], laps[i-1])
+	}
+}
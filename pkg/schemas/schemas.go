@@ -0,0 +1,98 @@
+// Package schemas holds the JSON Schemas of the JSON documents the module publishes, such as frame snapshots, racing
+// lines and the circuit and vehicle inventories, so that integrators can validate the documents passing through their
+// pipelines. The schemas are generated from the Go types of the documents with make schemas.
+package schemas
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrUnknownSchema indicates a schema name that is not one of Names.
+var ErrUnknownSchema = errors.New("unknown schema")
+
+// FS holds the schemas as <name>.json files.
+//
+//go:embed *.json
+var FS embed.FS //nolint:gochecknoglobals // Embedded files
+
+// Names returns the sorted names of the schemas, e.g. "frame-snapshot".
+func Names() []string {
+	entries, _ := fs.ReadDir(FS, ".")
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Schema returns the schema with the name.
+func Schema(name string) ([]byte, error) {
+	schema, err := FS.ReadFile(name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, name)
+	}
+
+	return schema, nil
+}
+
+// Validate checks a JSON document against the schema with the name, returning an error describing each violation.
+func Validate(name string, document []byte) error {
+	schema, err := compile(name)
+	if err != nil {
+		return err
+	}
+
+	// Numbers are decoded exactly, so that integers beyond the precision of a float64 are checked against their limits
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value any
+
+	err = decoder.Decode(&value)
+	if err != nil {
+		return fmt.Errorf("parse document: %w", err)
+	}
+
+	err = schema.Validate(value)
+	if err != nil {
+		return fmt.Errorf("validate %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// compile compiles the schema with the name.
+func compile(name string) (*jsonschema.Schema, error) {
+	data, err := Schema(name)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft7
+
+	err = compiler.AddResource(name+".json", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("add schema %s: %w", name, err)
+	}
+
+	schema, err := compiler.Compile(name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("compile schema %s: %w", name, err)
+	}
+
+	return schema, nil
+}
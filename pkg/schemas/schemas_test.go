@@ -0,0 +1,222 @@
+package schemas_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/internal/schemagen"
+	"github.com/zetetos/gt-telemetry/v2/pkg/schemas"
+)
+
+type SchemasTestSuite struct {
+	suite.Suite
+}
+
+func TestSchemasTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SchemasTestSuite))
+}
+
+// TestSchemasAreCurrent fails when the Go types of the documents no longer match the embedded schemas, so that every
+// change to a document is reviewed as a change to its schema. Regenerate them with make schemas.
+func (suite *SchemasTestSuite) TestSchemasAreCurrent() {
+	names := []string{}
+
+	for _, document := range schemagen.Documents() {
+		names = append(names, document.Name)
+
+		suite.Run(document.Name, func() {
+			// Arrange
+			want, err := schemas.Schema(document.Name)
+			suite.Require().NoError(err)
+
+			// Act
+			got, err := schemagen.Generate("../..", "github.com/zetetos/gt-telemetry/v2", document)
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.Equal(string(want), string(got), "the schema has changed, run make schemas")
+		})
+	}
+
+	suite.ElementsMatch(names, schemas.Names(), "every embedded schema is generated")
+}
+
+func (suite *SchemasTestSuite) TestInventoriesAreValid() {
+	tests := map[string]struct {
+		schema string
+		glob   string
+	}{
+		"circuits": {schema: "circuit", glob: "../circuits/inventory/*.json"},
+		"vehicles": {schema: "vehicle", glob: "../vehicles/inventory/*.json"},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			paths, err := filepath.Glob(tc.glob)
+			suite.Require().NoError(err)
+			suite.Require().NotEmpty(paths)
+
+			for _, path := range paths {
+				document, err := os.ReadFile(path)
+				suite.Require().NoError(err)
+
+				// Act
+				err = schemas.Validate(tc.schema, document)
+
+				// Assert
+				suite.NoError(err, path)
+			}
+		})
+	}
+}
+
+func (suite *SchemasTestSuite) TestFrameSnapshotsAreValid() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://../../data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	frames := 0
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		if frames++; frames > 100 {
+			break
+		}
+
+		document, err := json.Marshal(transformer.Snapshot())
+		suite.Require().NoError(err)
+
+		// Act
+		err = schemas.Validate("frame-snapshot", document)
+
+		// Assert
+		suite.Require().NoError(err)
+	}
+}
+
+// TestMirroredDocumentsAreValid checks the documents described by mirror types in schemagen against their schemas,
+// and that they have no fields missing from the mirrors, so that the mirrors cannot drift from the documents.
+func (suite *SchemasTestSuite) TestMirroredDocumentsAreValid() {
+	tests := map[string]struct {
+		schema   string
+		mirror   any
+		document func() ([]byte, error)
+	}{
+		"racing line": {schema: "racing-line", mirror: &schemagen.RacingLineDocument{}, document: racingLineJSON},
+		"overlay keyframes": {
+			schema:   "overlay-keyframes",
+			mirror:   &schemagen.OverlayKeyframesDocument{},
+			document: overlayKeyframesJSON,
+		},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			document, err := tc.document()
+			suite.Require().NoError(err)
+
+			decoder := json.NewDecoder(bytes.NewReader(document))
+			decoder.DisallowUnknownFields()
+
+			// Act
+			err = schemas.Validate(tc.schema, document)
+
+			// Assert
+			suite.Require().NoError(err)
+			suite.Require().NoError(decoder.Decode(tc.mirror), "the document has fields missing from the mirror")
+		})
+	}
+}
+
+// racingLineJSON returns the racing line of a lap around a grid of points as JSON.
+func racingLineJSON() ([]byte, error) {
+	trace := gttelemetry.LapTrace{Lap: 2, Laptime: 90 * time.Second}
+
+	for i := range 900 {
+		trace.Samples = append(trace.Samples, gttelemetry.LapTraceSample{
+			Distance: float32(i) * 5,
+			Elapsed:  time.Duration(i) * 100 * time.Millisecond,
+			SpeedKPH: 180,
+			Gear:     4,
+			X:        float32(i % 100),
+			Z:        float32(i / 100),
+		})
+	}
+
+	line, err := gttelemetry.NewRacingLine(trace, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &bytes.Buffer{}
+	err = line.WriteJSON(document)
+
+	return document.Bytes(), err
+}
+
+// overlayKeyframesJSON returns the overlay track of the first frames of the demo recording as JSON keyframes.
+func overlayKeyframesJSON() ([]byte, error) {
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://../../data/replays/demo.gtz", LogLevel: "error"})
+	if err != nil {
+		return nil, err
+	}
+
+	track := gttelemetry.NewOverlayTrack(gttelemetry.OverlayOptions{})
+	frames := 0
+
+	for transformer, err := range client.Scan(context.Background()) {
+		if err != nil {
+			return nil, err
+		}
+
+		if frames++; frames > 100 {
+			break
+		}
+
+		track.Update(transformer)
+	}
+
+	document := &bytes.Buffer{}
+	err = track.WriteJSON(document)
+
+	return document.Bytes(), err
+}
+
+func (suite *SchemasTestSuite) TestInvalidDocumentIsRejected() {
+	// Arrange
+	data, err := os.ReadFile("../vehicles/inventory/102.json")
+	suite.Require().NoError(err)
+
+	var vehicle map[string]any
+	suite.Require().NoError(json.Unmarshal(data, &vehicle))
+
+	vehicle["carId"] = "102"
+
+	document, err := json.Marshal(vehicle)
+	suite.Require().NoError(err)
+
+	// Act
+	err = schemas.Validate("vehicle", document)
+
+	// Assert
+	suite.ErrorContains(err, "carId")
+}
+
+func (suite *SchemasTestSuite) TestUnknownSchema() {
+	// Act
+	_, err := schemas.Schema("session")
+
+	// Assert
+	suite.ErrorIs(err, schemas.ErrUnknownSchema)
+}
@@ -0,0 +1,26 @@
+//go:build darwin && !tinygo
+
+package serialdash
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioctlGetTermios = unix.TIOCGETA
+	ioctlSetTermios = unix.TIOCSETA
+)
+
+// setSpeed sets the baud rate, which macOS takes as a plain number.
+func setSpeed(termios *unix.Termios, baud int) error {
+	if baud <= 0 {
+		return fmt.Errorf("%w: %d", ErrUnsupportedBaud, baud)
+	}
+
+	termios.Ispeed = uint64(baud)
+	termios.Ospeed = uint64(baud)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build linux && !tinygo
+
+package serialdash
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
+
+// linuxSpeeds maps baud rates to the speed constants of the Linux terminal interface.
+var linuxSpeeds = map[int]uint32{ //nolint:gochecknoglobals // Static lookup table
+	9600:   unix.B9600,
+	19200:  unix.B19200,
+	38400:  unix.B38400,
+	57600:  unix.B57600,
+	115200: unix.B115200,
+	230400: unix.B230400,
+	460800: unix.B460800,
+	500000: unix.B500000,
+	921600: unix.B921600,
+}
+
+func setSpeed(termios *unix.Termios, baud int) error {
+	speed, ok := linuxSpeeds[baud]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrUnsupportedBaud, baud)
+	}
+
+	termios.Cflag &^= unix.CBAUD
+	termios.Cflag |= speed
+	termios.Ispeed = speed
+	termios.Ospeed = speed
+
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build (!linux && !darwin && !windows) || tinygo
+
+package serialdash
+
+import (
+	"os"
+)
+
+// OpenPort is not supported on this platform, use Options.Open to provide the port instead.
+func OpenPort(_ string, _ int) (*os.File, error) {
+	return nil, ErrUnsupportedPlatform
+}
@@ -0,0 +1,58 @@
+//go:build (linux || darwin) && !tinygo
+
+package serialdash
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// OpenPort opens a serial port in raw mode at the given baud rate, with 8 data bits, no parity and one stop bit.
+func OpenPort(port string, baud int) (*os.File, error) {
+	fd, err := unix.Open(port, unix.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open serial port %s: %w", port, err)
+	}
+
+	err = configurePort(fd, baud)
+	if err == nil {
+		// The port was opened non-blocking so that opening does not wait for the carrier detect line
+		err = unix.SetNonblock(fd, false)
+	}
+
+	if err != nil {
+		unix.Close(fd)
+
+		return nil, fmt.Errorf("configure serial port %s: %w", port, err)
+	}
+
+	return os.NewFile(uintptr(fd), port), nil
+}
+
+// configurePort sets the port to raw 8N1 mode at the given baud rate.
+func configurePort(fd int, baud int) error {
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return fmt.Errorf("get terminal attributes: %w", err)
+	}
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB | unix.CSTOPB
+	termios.Cflag |= unix.CS8 | unix.CREAD | unix.CLOCAL
+
+	err = setSpeed(termios, baud)
+	if err != nil {
+		return err
+	}
+
+	err = unix.IoctlSetTermios(fd, ioctlSetTermios, termios)
+	if err != nil {
+		return fmt.Errorf("set terminal attributes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+//go:build windows && !tinygo
+
+package serialdash
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// dcbBinary is the fBinary flag of the DCB structure, which must always be set.
+const dcbBinary = 0x1
+
+// OpenPort opens a serial port at the given baud rate, with 8 data bits, no parity and one stop bit.
+func OpenPort(port string, baud int) (*os.File, error) {
+	if !strings.HasPrefix(port, `\\.\`) {
+		// Ports above COM9 can only be opened with the device namespace prefix
+		port = `\\.\` + port
+	}
+
+	path, err := windows.UTF16PtrFromString(port)
+	if err != nil {
+		return nil, fmt.Errorf("open serial port %s: %w", port, err)
+	}
+
+	handle, err := windows.CreateFile(path, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open serial port %s: %w", port, err)
+	}
+
+	dcb := windows.DCB{}
+	dcb.DCBlength = uint32(unsafe.Sizeof(dcb))
+
+	err = windows.GetCommState(handle, &dcb)
+	if err == nil {
+		dcb.BaudRate = uint32(baud) //nolint:gosec // baud rates are small positive numbers
+		dcb.Flags = dcbBinary
+		dcb.ByteSize = 8
+		dcb.Parity = windows.NOPARITY
+		dcb.StopBits = windows.ONESTOPBIT
+
+		err = windows.SetCommState(handle, &dcb)
+	}
+
+	if err != nil {
+		windows.CloseHandle(handle)
+
+		return nil, fmt.Errorf("configure serial port %s: %w", port, err)
+	}
+
+	return os.NewFile(uintptr(handle), port), nil
+}
@@ -0,0 +1,210 @@
+// Package serialdash writes compact telemetry frames to DIY dashboards and shift lights, such as Arduino and ESP32
+// boards, over a serial port.
+//
+// Each frame is rendered from a text/template payload template. The template reads channels with the ch function,
+// for example {{ch "engineRPM" | printf "%.0f"}}. The port is reopened when it disappears, so a board can be unplugged
+// and plugged back in while the dashboard is running.
+package serialdash
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
+)
+
+const (
+	// DefaultBaud is the baud rate used when no baud rate is configured.
+	DefaultBaud = 115200
+	// DefaultInterval is the interval at which frames are written when no interval is configured.
+	DefaultInterval = 50 * time.Millisecond
+	// DefaultTemplate writes the engine RPM, gear and speed in km/h as a comma separated line.
+	DefaultTemplate = `{{ch "engineRPM" | printf "%.0f"}},{{ch "currentGear" | printf "%.0f"}},{{ch "groundSpeedKPH" | printf "%.0f"}}` + "\n"
+
+	// reopenInterval is how long to wait between attempts to reopen a port that has disappeared.
+	reopenInterval = time.Second
+)
+
+var (
+	// ErrNoPort indicates that a dashboard was configured without a port.
+	ErrNoPort = errors.New("no serial port configured")
+	// ErrUnknownChannel indicates a template that reads a channel not provided by the channel source.
+	ErrUnknownChannel = errors.New("unknown channel")
+	// ErrUnsupportedBaud indicates a baud rate that the platform cannot set.
+	ErrUnsupportedBaud = errors.New("unsupported baud rate")
+	// ErrUnsupportedPlatform indicates that serial ports cannot be opened on this platform.
+	ErrUnsupportedPlatform = errors.New("serial ports are not supported on this platform")
+)
+
+// ChannelSource provides the current value of named telemetry channels, as implemented by gttelemetry.Transformer.
+type ChannelSource interface {
+	Channel(name string) (float32, bool)
+}
+
+// Options configures a Dashboard.
+type Options struct {
+	// Port is the serial device, e.g. "/dev/ttyUSB0" or "COM3".
+	Port string
+	// Baud is the baud rate of the port, defaults to 115200. The port is always configured for 8 data bits, no parity
+	// and one stop bit.
+	Baud int
+	// Template is the text/template used to render each frame, defaults to DefaultTemplate.
+	Template string
+	// Interval is how often a frame is written, defaults to 50ms.
+	Interval time.Duration
+	// Open overrides how the port is opened, for example to write to a network serial bridge instead. Port and Baud
+	// are ignored when set.
+	Open func() (io.WriteCloser, error)
+	// Logger is used for log messages, defaults to a disabled logger.
+	Logger *logging.Logger
+}
+
+// Dashboard writes a frame rendered from the channel source to a serial port at every interval.
+type Dashboard struct {
+	source   ChannelSource
+	template *template.Template
+	interval time.Duration
+	open     func() (io.WriteCloser, error)
+	log      logging.Logger
+}
+
+// NewDashboard creates a dashboard, checking that the template renders with the channel source.
+func NewDashboard(source ChannelSource, opts Options) (*Dashboard, error) {
+	if opts.Open == nil {
+		if opts.Port == "" {
+			return nil, ErrNoPort
+		}
+
+		if opts.Baud <= 0 {
+			opts.Baud = DefaultBaud
+		}
+
+		port, baud := opts.Port, opts.Baud
+		opts.Open = func() (io.WriteCloser, error) {
+			file, err := OpenPort(port, baud)
+			if err != nil {
+				return nil, err
+			}
+
+			return file, nil
+		}
+	}
+
+	if opts.Template == "" {
+		opts.Template = DefaultTemplate
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	log := logging.Nop()
+	if opts.Logger != nil {
+		log = *opts.Logger
+	}
+
+	dashboard := &Dashboard{
+		source:   source,
+		interval: opts.Interval,
+		open:     opts.Open,
+		log:      log,
+	}
+
+	tmpl, err := template.New("frame").Funcs(template.FuncMap{"ch": dashboard.channel}).Parse(opts.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	dashboard.template = tmpl
+
+	_, err = dashboard.Frame()
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboard, nil
+}
+
+// Frame renders a frame from the current channel values.
+func (d *Dashboard) Frame() ([]byte, error) {
+	var frame bytes.Buffer
+
+	err := d.template.Execute(&frame, nil)
+	if err != nil {
+		return nil, fmt.Errorf("render frame: %w", err)
+	}
+
+	return frame.Bytes(), nil
+}
+
+// Run writes frames until the context is cancelled. The port is reopened when it cannot be opened or a write fails.
+func (d *Dashboard) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	var (
+		port       io.WriteCloser
+		nextReopen time.Time
+	)
+
+	defer func() {
+		if port != nil {
+			port.Close()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if port == nil {
+				if now.Before(nextReopen) {
+					continue
+				}
+
+				opened, err := d.open()
+				if err != nil {
+					d.log.Debug().Err(err).Msg("failed to open dashboard port, retrying")
+
+					nextReopen = now.Add(reopenInterval)
+
+					continue
+				}
+
+				port = opened
+
+				d.log.Info().Msg("dashboard port opened")
+			}
+
+			frame, err := d.Frame()
+			if err != nil {
+				return err
+			}
+
+			_, err = port.Write(frame)
+			if err != nil {
+				d.log.Warn().Err(err).Msg("dashboard port disconnected")
+
+				port.Close()
+				port = nil
+				nextReopen = now.Add(reopenInterval)
+			}
+		}
+	}
+}
+
+// channel is the template function that reads a channel.
+func (d *Dashboard) channel(name string) (float32, error) {
+	value, ok := d.source.Channel(name)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownChannel, name)
+	}
+
+	return value, nil
+}
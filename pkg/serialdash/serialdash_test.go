@@ -0,0 +1,167 @@
+package serialdash_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/serialdash"
+)
+
+var errUnplugged = errors.New("device unplugged")
+
+type fakeSource map[string]float32
+
+func (f fakeSource) Channel(name string) (float32, bool) {
+	value, ok := f[name]
+
+	return value, ok
+}
+
+// fakePort records the frames written to it and fails writes once unplugged.
+type fakePort struct {
+	mu        sync.Mutex
+	written   bytes.Buffer
+	unplugged bool
+}
+
+func (p *fakePort) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.unplugged {
+		return 0, errUnplugged
+	}
+
+	return p.written.Write(b)
+}
+
+func (p *fakePort) Close() error {
+	return nil
+}
+
+func (p *fakePort) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.written.String()
+}
+
+type SerialDashTestSuite struct {
+	suite.Suite
+
+	source fakeSource
+}
+
+func TestSerialDashTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SerialDashTestSuite))
+}
+
+func (suite *SerialDashTestSuite) SetupTest() {
+	suite.source = fakeSource{"engineRPM": 6543.4, "currentGear": 4, "groundSpeedKPH": 187.6}
+}
+
+func (suite *SerialDashTestSuite) TestDefaultTemplateRendersCSVLine() {
+	// Arrange
+	dashboard, err := serialdash.NewDashboard(suite.source, serialdash.Options{
+		Open: func() (io.WriteCloser, error) { return &fakePort{}, nil },
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	frame, err := dashboard.Frame()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("6543,4,188\n", string(frame))
+}
+
+func (suite *SerialDashTestSuite) TestCustomTemplate() {
+	// Arrange
+	dashboard, err := serialdash.NewDashboard(suite.source, serialdash.Options{
+		Template: `<{{ch "currentGear" | printf "%.0f"}}>`,
+		Open:     func() (io.WriteCloser, error) { return &fakePort{}, nil },
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	frame, err := dashboard.Frame()
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("<4>", string(frame))
+}
+
+func (suite *SerialDashTestSuite) TestUnknownChannelIsRejected() {
+	// Act
+	_, err := serialdash.NewDashboard(suite.source, serialdash.Options{
+		Template: `{{ch "warpSpeed"}}`,
+		Open:     func() (io.WriteCloser, error) { return &fakePort{}, nil },
+	})
+
+	// Assert
+	suite.ErrorIs(err, serialdash.ErrUnknownChannel)
+}
+
+func (suite *SerialDashTestSuite) TestPortIsRequired() {
+	// Act
+	_, err := serialdash.NewDashboard(suite.source, serialdash.Options{})
+
+	// Assert
+	suite.ErrorIs(err, serialdash.ErrNoPort)
+}
+
+func (suite *SerialDashTestSuite) TestPortIsReopenedAfterUnplug() {
+	// Arrange
+	var (
+		mu    sync.Mutex
+		ports []*fakePort
+	)
+
+	dashboard, err := serialdash.NewDashboard(suite.source, serialdash.Options{
+		Interval: time.Millisecond,
+		Open: func() (io.WriteCloser, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			port := &fakePort{}
+			ports = append(ports, port)
+
+			return port, nil
+		},
+	})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() { _ = dashboard.Run(ctx) }()
+
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(ports) == 1 && ports[0].String() != ""
+	}, time.Second, time.Millisecond)
+
+	// Act
+	mu.Lock()
+	ports[0].mu.Lock()
+	ports[0].unplugged = true
+	ports[0].mu.Unlock()
+	mu.Unlock()
+
+	// Assert
+	suite.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(ports) == 2 && ports[1].String() != ""
+	}, 3*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,76 @@
+package shiftlights
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	// opcSetPixelColours is the Open Pixel Control command that sets the colour of every pixel on a channel.
+	opcSetPixelColours = 0
+
+	opcHeaderSize = 4
+	opcMaxData    = 0xffff
+)
+
+// ErrFrameTooLarge indicates a frame with more pixels than fit in an Open Pixel Control message.
+var ErrFrameTooLarge = errors.New("frame too large for an Open Pixel Control message")
+
+// OPCMessage encodes a frame as an Open Pixel Control "set pixel colours" message for the given channel. Channel 0
+// broadcasts to every channel of the server.
+func OPCMessage(channel uint8, frame []Colour) ([]byte, error) {
+	size := len(frame) * 3
+	if size > opcMaxData {
+		return nil, fmt.Errorf("%w: %d pixels", ErrFrameTooLarge, len(frame))
+	}
+
+	message := make([]byte, opcHeaderSize, opcHeaderSize+size)
+	message[0] = channel
+	message[1] = opcSetPixelColours
+	binary.BigEndian.PutUint16(message[2:], uint16(size))
+
+	for _, colour := range frame {
+		message = append(message, colour.R, colour.G, colour.B)
+	}
+
+	return message, nil
+}
+
+// OPCClient sends frames to an Open Pixel Control server over TCP.
+type OPCClient struct {
+	conn    net.Conn
+	channel uint8
+}
+
+// DialOPC connects to the Open Pixel Control server at the address, e.g. "127.0.0.1:7890", sending frames to the
+// given channel.
+func DialOPC(address string, channel uint8) (*OPCClient, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("dial OPC server: %w", err)
+	}
+
+	return &OPCClient{conn: conn, channel: channel}, nil
+}
+
+// Send writes a frame to the server.
+func (c *OPCClient) Send(frame []Colour) error {
+	message, err := OPCMessage(c.channel, frame)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.conn.Write(message)
+	if err != nil {
+		return fmt.Errorf("send OPC message: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the connection to the server.
+func (c *OPCClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,184 @@
+// Package shiftlights maps engine RPM to the pixels of an LED strip, such as a WS2812 shift light, with configurable
+// colour zones and flashing when the rev limiter is hit. Frames can be sent to an Open Pixel Control server, e.g. a
+// Fadecandy or an ESP32 running an OPC firmware, or encoded in GRB order for strips driven directly.
+package shiftlights
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultFlashInterval is how long the strip stays on and off while flashing when no interval is configured.
+const DefaultFlashInterval = 100 * time.Millisecond
+
+var (
+	// ErrNoLEDs indicates a strip configured without any LEDs.
+	ErrNoLEDs = errors.New("strip must have at least one LED")
+	// ErrInvalidZones indicates colour zones that do not start at zero or are not in ascending order.
+	ErrInvalidZones = errors.New("colour zones must start at 0 and be in ascending order below 1")
+)
+
+// Colour is the colour of a single LED.
+type Colour struct {
+	R uint8
+	G uint8
+	B uint8
+}
+
+// Colours used by the default zones.
+//
+//nolint:gochecknoglobals // read-only colour constants
+var (
+	Off    = Colour{}
+	Green  = Colour{G: 255}
+	Yellow = Colour{R: 255, G: 191}
+	Red    = Colour{R: 255}
+	Blue   = Colour{B: 255}
+)
+
+// Zone colours the LEDs from Start, a fraction of the strip length between 0 and 1, up to the start of the next zone.
+type Zone struct {
+	Start  float64
+	Colour Colour
+}
+
+// DefaultZones lights the first half of the strip green, the next 30% yellow and the remainder red.
+func DefaultZones() []Zone {
+	return []Zone{
+		{Start: 0, Colour: Green},
+		{Start: 0.5, Colour: Yellow},
+		{Start: 0.8, Colour: Red},
+	}
+}
+
+// Options configures a Strip.
+type Options struct {
+	// LEDs is the number of LEDs on the strip.
+	LEDs int
+	// Zones are the colour zones along the strip, defaults to DefaultZones.
+	Zones []Zone
+	// FlashColour is the colour of every LED while flashing on the rev limiter, defaults to blue.
+	FlashColour Colour
+	// FlashInterval is how long the strip stays on and off while flashing, defaults to 100ms.
+	FlashInterval time.Duration
+	// DisableFlash lights the strip steadily instead of flashing when the rev limiter is hit.
+	DisableFlash bool
+}
+
+// State is the engine state that a frame is rendered from. Min and Max are the rev light range reported by the game,
+// see Transformer.EngineRPMLight, and Limiter is the rev limiter alert flag.
+type State struct {
+	RPM     float32
+	Min     uint16
+	Max     uint16
+	Limiter bool
+}
+
+// Strip renders shift light frames.
+type Strip struct {
+	leds          int
+	colours       []Colour
+	flashColour   Colour
+	flashInterval time.Duration
+	flash         bool
+}
+
+// NewStrip creates a strip, checking that the colour zones are valid.
+func NewStrip(opts Options) (*Strip, error) {
+	if opts.LEDs <= 0 {
+		return nil, ErrNoLEDs
+	}
+
+	zones := opts.Zones
+	if len(zones) == 0 {
+		zones = DefaultZones()
+	}
+
+	if zones[0].Start != 0 {
+		return nil, fmt.Errorf("%w: first zone starts at %g", ErrInvalidZones, zones[0].Start)
+	}
+
+	for i := 1; i < len(zones); i++ {
+		if zones[i].Start <= zones[i-1].Start || zones[i].Start >= 1 {
+			return nil, fmt.Errorf("%w: zone %d starts at %g", ErrInvalidZones, i, zones[i].Start)
+		}
+	}
+
+	// the colour of each LED is fixed by its position, so it is resolved once
+	colours := make([]Colour, opts.LEDs)
+	zone := 0
+
+	for i := range colours {
+		position := float64(i) / float64(opts.LEDs)
+		for zone+1 < len(zones) && zones[zone+1].Start <= position {
+			zone++
+		}
+
+		colours[i] = zones[zone].Colour
+	}
+
+	flashColour := opts.FlashColour
+	if flashColour == Off {
+		flashColour = Blue
+	}
+
+	flashInterval := opts.FlashInterval
+	if flashInterval <= 0 {
+		flashInterval = DefaultFlashInterval
+	}
+
+	return &Strip{
+		leds:          opts.LEDs,
+		colours:       colours,
+		flashColour:   flashColour,
+		flashInterval: flashInterval,
+		flash:         !opts.DisableFlash,
+	}, nil
+}
+
+// Frame returns the colour of every LED for the engine state. The number of lit LEDs grows linearly from none at the
+// minimum rev light RPM to all of them at the maximum. When the limiter is hit, the whole strip alternates between
+// the flash colour and off, with the phase taken from now so that consecutive frames flash at a steady rate.
+func (s *Strip) Frame(state State, now time.Time) []Colour {
+	frame := make([]Colour, s.leds)
+
+	if state.Limiter || (state.Max > 0 && state.RPM >= float32(state.Max)) {
+		if s.flash && (now.UnixNano()/int64(s.flashInterval))%2 == 1 {
+			return frame
+		}
+
+		for i := range frame {
+			frame[i] = s.flashColour
+		}
+
+		return frame
+	}
+
+	lit := s.lit(state)
+	copy(frame[:lit], s.colours)
+
+	return frame
+}
+
+// lit returns the number of LEDs lit at the engine state.
+func (s *Strip) lit(state State) int {
+	if state.Max <= state.Min || state.RPM <= float32(state.Min) {
+		return 0
+	}
+
+	fraction := float64(state.RPM-float32(state.Min)) / float64(state.Max-state.Min)
+
+	return min(int(math.Ceil(fraction*float64(s.leds))), s.leds)
+}
+
+// GRB encodes a frame in the green, red, blue byte order expected by WS2812 LEDs.
+func GRB(frame []Colour) []byte {
+	data := make([]byte, 0, len(frame)*3)
+	for _, colour := range frame {
+		data = append(data, colour.G, colour.R, colour.B)
+	}
+
+	return data
+}
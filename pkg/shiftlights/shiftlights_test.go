@@ -0,0 +1,158 @@
+package shiftlights_test
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/shiftlights"
+)
+
+type ShiftLightsTestSuite struct {
+	suite.Suite
+}
+
+func TestShiftLightsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ShiftLightsTestSuite))
+}
+
+func (suite *ShiftLightsTestSuite) TestFrameLightsZonesProgressively() {
+	strip, err := shiftlights.NewStrip(shiftlights.Options{LEDs: 10})
+	suite.Require().NoError(err)
+
+	g, y, r, o := shiftlights.Green, shiftlights.Yellow, shiftlights.Red, shiftlights.Off
+
+	tests := map[string]struct {
+		rpm  float32
+		want []shiftlights.Colour
+	}{
+		"below range": {
+			rpm:  4000,
+			want: []shiftlights.Colour{o, o, o, o, o, o, o, o, o, o},
+		},
+		"just above minimum": {
+			rpm:  5010,
+			want: []shiftlights.Colour{g, o, o, o, o, o, o, o, o, o},
+		},
+		"half way": {
+			rpm:  6000,
+			want: []shiftlights.Colour{g, g, g, g, g, o, o, o, o, o},
+		},
+		"near maximum": {
+			rpm:  6900,
+			want: []shiftlights.Colour{g, g, g, g, g, y, y, y, r, r},
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			frame := strip.Frame(shiftlights.State{RPM: test.rpm, Min: 5000, Max: 7000}, time.Unix(0, 0))
+
+			// Assert
+			suite.Equal(test.want, frame)
+		})
+	}
+}
+
+func (suite *ShiftLightsTestSuite) TestFrameFlashesOnLimiter() {
+	// Arrange
+	strip, err := shiftlights.NewStrip(shiftlights.Options{LEDs: 3, FlashInterval: 100 * time.Millisecond})
+	suite.Require().NoError(err)
+
+	state := shiftlights.State{RPM: 6500, Min: 5000, Max: 7000, Limiter: true}
+	b, o := shiftlights.Blue, shiftlights.Off
+
+	// Act
+	on := strip.Frame(state, time.Unix(0, 0))
+	off := strip.Frame(state, time.Unix(0, int64(150*time.Millisecond)))
+
+	// Assert
+	suite.Equal([]shiftlights.Colour{b, b, b}, on)
+	suite.Equal([]shiftlights.Colour{o, o, o}, off)
+}
+
+func (suite *ShiftLightsTestSuite) TestFrameIsSteadyWhenFlashDisabled() {
+	// Arrange
+	strip, err := shiftlights.NewStrip(shiftlights.Options{LEDs: 2, DisableFlash: true, FlashColour: shiftlights.Red})
+	suite.Require().NoError(err)
+
+	// Act
+	frame := strip.Frame(shiftlights.State{RPM: 7000, Min: 5000, Max: 7000}, time.Unix(0, int64(150*time.Millisecond)))
+
+	// Assert
+	suite.Equal([]shiftlights.Colour{shiftlights.Red, shiftlights.Red}, frame)
+}
+
+func (suite *ShiftLightsTestSuite) TestNewStripRejectsInvalidOptions() {
+	tests := map[string]struct {
+		opts    shiftlights.Options
+		wantErr error
+	}{
+		"no LEDs": {
+			opts:    shiftlights.Options{},
+			wantErr: shiftlights.ErrNoLEDs,
+		},
+		"zones not starting at zero": {
+			opts:    shiftlights.Options{LEDs: 8, Zones: []shiftlights.Zone{{Start: 0.2}}},
+			wantErr: shiftlights.ErrInvalidZones,
+		},
+		"zones out of order": {
+			opts:    shiftlights.Options{LEDs: 8, Zones: []shiftlights.Zone{{Start: 0}, {Start: 0.6}, {Start: 0.4}}},
+			wantErr: shiftlights.ErrInvalidZones,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			_, err := shiftlights.NewStrip(test.opts)
+
+			// Assert
+			suite.ErrorIs(err, test.wantErr)
+		})
+	}
+}
+
+func (suite *ShiftLightsTestSuite) TestGRBSwapsRedAndGreen() {
+	// Act
+	data := shiftlights.GRB([]shiftlights.Colour{{R: 1, G: 2, B: 3}})
+
+	// Assert
+	suite.Equal([]byte{2, 1, 3}, data)
+}
+
+func (suite *ShiftLightsTestSuite) TestOPCClientSendsSetPixelColours() {
+	// Arrange
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		message := make([]byte, 10)
+		_, _ = io.ReadFull(conn, message)
+		received <- message
+	}()
+
+	client, err := shiftlights.DialOPC(listener.Addr().String(), 1)
+	suite.Require().NoError(err)
+	defer client.Close()
+
+	// Act
+	err = client.Send([]shiftlights.Colour{{R: 255}, {B: 10}})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal([]byte{1, 0, 0, 6, 255, 0, 0, 0, 0, 10}, <-received)
+}
@@ -0,0 +1,459 @@
+// Package upload uploads recordings to S3 compatible object storage, such as Amazon S3, Cloudflare R2 or MinIO, for
+// teams that keep the session data of their drivers in one place.
+//
+// Recordings are sent as multipart uploads. The progress of an upload is saved alongside the recording in a state
+// file, see StateSuffix, so that an upload interrupted by a lost connection or a restart continues from the last part
+// sent rather than starting again. A recording can be uploaded once it is complete with Upload, or while it is still
+// being written with UploadLive, which sends each part as soon as the recording has grown by the part size.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// StateSuffix is appended to the path of a recording to name the file holding the progress of its upload, e.g.
+	// session.gtz.upload. The file is removed once the upload completes.
+	StateSuffix = ".upload"
+
+	// MinPartSize is the smallest part S3 accepts, other than the last part of an upload.
+	MinPartSize = 5 << 20
+	// DefaultPartSize is the part size used when none is configured.
+	DefaultPartSize = 8 << 20
+	// DefaultPollInterval is how often a live recording is checked for growth when no interval is configured.
+	DefaultPollInterval = 5 * time.Second
+
+	// requestTimeout is how long a single request, such as sending a part, may take.
+	requestTimeout = 5 * time.Minute
+	// maxParts is the most parts S3 accepts in an upload.
+	maxParts = 10000
+)
+
+var (
+	// ErrInvalidEndpoint indicates an endpoint that is not an http or https URL.
+	ErrInvalidEndpoint = errors.New("invalid endpoint URL")
+	// ErrNoBucket indicates an uploader configured without a bucket.
+	ErrNoBucket = errors.New("no bucket")
+	// ErrNoCredentials indicates an uploader configured without an access key.
+	ErrNoCredentials = errors.New("no access key ID or secret access key")
+	// ErrPartSizeTooSmall indicates a part size below MinPartSize.
+	ErrPartSizeTooSmall = errors.New("part size below 5 MiB")
+	// ErrTooManyParts indicates a recording that needs more parts than S3 allows, use a larger part size.
+	ErrTooManyParts = errors.New("recording needs more than 10000 parts")
+	// ErrRecordingChanged indicates a recording that no longer matches the parts already uploaded, such as when it was
+	// replaced while the upload was interrupted.
+	ErrRecordingChanged = errors.New("recording changed since the upload started")
+)
+
+// Options configures an Uploader.
+type Options struct {
+	// Endpoint is the base URL of the storage service, e.g. https://s3.eu-west-2.amazonaws.com or
+	// http://localhost:9000. Objects are addressed by path, with the bucket as the first segment.
+	Endpoint string
+	// Region is the region the bucket is in, defaults to us-east-1. Services without regions accept any region.
+	Region string
+	// Bucket is the bucket recordings are uploaded to.
+	Bucket string
+	// Prefix is prepended to the file name of each recording to form its key, e.g. "team/alice/".
+	Prefix string
+	// AccessKeyID and SecretAccessKey are the credentials requests are signed with.
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is sent with every request when using temporary credentials.
+	SessionToken string
+	// PartSize is the size of each part in bytes, at least MinPartSize, defaults to DefaultPartSize.
+	PartSize int64
+	// Metadata is stored with every object as user metadata, e.g. {"team": "Blue"}. Values must be ASCII.
+	Metadata map[string]string
+	// Tags are set on every object as object tags, for lifecycle rules and access policies, e.g. {"season": "2026"}.
+	Tags map[string]string
+	// PollInterval is how often a live recording is checked for growth, defaults to DefaultPollInterval.
+	PollInterval time.Duration
+	// Client is used to send requests, defaults to a client with a generous timeout for sending parts.
+	Client *http.Client
+	// Logger is used for uploader log messages, defaults to a disabled logger.
+	Logger *zerolog.Logger
+}
+
+// Uploader uploads recordings to a bucket.
+type Uploader struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	partSize        int64
+	metadata        map[string]string
+	tags            map[string]string
+	pollInterval    time.Duration
+	client          *http.Client
+	log             zerolog.Logger
+}
+
+// Part is a part of a recording that has been uploaded.
+type Part struct {
+	Number int    `json:"number"`
+	ETag   string `json:"etag"`
+	Size   int64  `json:"size"`
+}
+
+// State is the progress of an upload, saved alongside the recording while the upload is in progress.
+type State struct {
+	Key      string `json:"key"`
+	UploadID string `json:"uploadId"`
+	Parts    []Part `json:"parts"`
+}
+
+// uploaded returns the number of bytes of the recording already uploaded.
+func (s State) uploaded() int64 {
+	var size int64
+	for _, part := range s.Parts {
+		size += part.Size
+	}
+
+	return size
+}
+
+// New creates an uploader for the bucket.
+func New(opts Options) (*Uploader, error) {
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidEndpoint, opts.Endpoint)
+	}
+
+	if opts.Bucket == "" {
+		return nil, ErrNoBucket
+	}
+
+	if opts.AccessKeyID == "" || opts.SecretAccessKey == "" {
+		return nil, ErrNoCredentials
+	}
+
+	if opts.PartSize == 0 {
+		opts.PartSize = DefaultPartSize
+	} else if opts.PartSize < MinPartSize {
+		return nil, ErrPartSizeTooSmall
+	}
+
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: requestTimeout}
+	}
+
+	log := zerolog.Nop()
+	if opts.Logger != nil {
+		log = *opts.Logger
+	}
+
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+
+	return &Uploader{
+		endpoint:        endpoint,
+		region:          opts.Region,
+		bucket:          opts.Bucket,
+		prefix:          opts.Prefix,
+		accessKeyID:     opts.AccessKeyID,
+		secretAccessKey: opts.SecretAccessKey,
+		sessionToken:    opts.SessionToken,
+		partSize:        opts.PartSize,
+		metadata:        opts.Metadata,
+		tags:            opts.Tags,
+		pollInterval:    opts.PollInterval,
+		client:          opts.Client,
+		log:             log,
+	}, nil
+}
+
+// Key returns the key a recording is uploaded to, the prefix followed by the file name of the recording.
+func (u *Uploader) Key(path string) string {
+	return u.prefix + filepath.Base(path)
+}
+
+// Upload uploads a complete recording, continuing the upload of an earlier attempt when its state file is found.
+func (u *Uploader) Upload(ctx context.Context, path string) error {
+	finished := make(chan struct{})
+	close(finished)
+
+	return u.UploadLive(ctx, path, finished)
+}
+
+// UploadLive uploads a recording while it is being written, sending each part as soon as the recording has grown by
+// the part size. The upload is completed with the rest of the recording once finished is closed. When the context is
+// cancelled the state file is kept, so that the upload can be continued later with Upload.
+func (u *Uploader) UploadLive(ctx context.Context, path string, finished <-chan struct{}) error {
+	state, err := u.resume(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open recording: %w", err)
+	}
+	defer file.Close()
+
+	ticker := time.NewTicker(u.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Read whether the recording was finished before its size, so that nothing written before it finished is missed
+		var done bool
+		select {
+		case <-finished:
+			done = true
+		default:
+		}
+
+		info, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("stat recording: %w", err)
+		}
+
+		if info.Size() < state.uploaded() {
+			return fmt.Errorf("%w: %s", ErrRecordingChanged, path)
+		}
+
+		// The last part may be smaller than the part size, so it is only sent once the recording is finished
+		for info.Size()-state.uploaded() >= u.partSize || (done && (info.Size() > state.uploaded() || len(state.Parts) == 0)) {
+			size := min(info.Size()-state.uploaded(), u.partSize)
+
+			err = u.uploadPart(ctx, path, file, &state, size)
+			if err != nil {
+				return err
+			}
+		}
+
+		if done {
+			return u.complete(ctx, path, state)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-finished:
+		case <-ticker.C:
+		}
+	}
+}
+
+// resume reads the state of an earlier upload of the recording, or starts a new upload when there is none.
+func (u *Uploader) resume(ctx context.Context, path string) (State, error) {
+	data, err := os.ReadFile(path + StateSuffix)
+
+	switch {
+	case err == nil:
+		var state State
+
+		err = json.Unmarshal(data, &state)
+		if err != nil {
+			return State{}, fmt.Errorf("read upload state: %w", err)
+		}
+
+		u.log.Info().Str("file", path).Int("parts", len(state.Parts)).Msg("continuing upload of recording")
+
+		return state, nil
+	case !errors.Is(err, os.ErrNotExist):
+		return State{}, fmt.Errorf("read upload state: %w", err)
+	}
+
+	state := State{Key: u.Key(path)}
+
+	state.UploadID, err = u.createUpload(ctx, state.Key)
+	if err != nil {
+		return State{}, err
+	}
+
+	u.log.Info().Str("file", path).Str("key", state.Key).Msg("started upload of recording")
+
+	return state, saveState(path, state)
+}
+
+// uploadPart sends the next size bytes of the recording as a part and saves the state.
+func (u *Uploader) uploadPart(ctx context.Context, path string, file *os.File, state *State, size int64) error {
+	number := len(state.Parts) + 1
+	if number > maxParts {
+		return ErrTooManyParts
+	}
+
+	data := make([]byte, size)
+
+	_, err := file.ReadAt(data, state.uploaded())
+	if err != nil {
+		return fmt.Errorf("read recording: %w", err)
+	}
+
+	query := map[string]string{"partNumber": strconv.Itoa(number), "uploadId": state.UploadID}
+
+	resp, err := u.do(ctx, http.MethodPut, state.Key, query, nil, data)
+	if err != nil {
+		return fmt.Errorf("upload part %d: %w", number, err)
+	}
+
+	state.Parts = append(state.Parts, Part{Number: number, ETag: resp.header.Get("ETag"), Size: size})
+
+	return saveState(path, *state)
+}
+
+// createUpload starts a multipart upload, returning its ID.
+func (u *Uploader) createUpload(ctx context.Context, key string) (string, error) {
+	header := http.Header{}
+	for name, value := range u.metadata {
+		header.Set("X-Amz-Meta-"+name, value)
+	}
+
+	if len(u.tags) > 0 {
+		tags := url.Values{}
+		for name, value := range u.tags {
+			tags.Set(name, value)
+		}
+
+		header.Set("X-Amz-Tagging", tags.Encode())
+	}
+
+	resp, err := u.do(ctx, http.MethodPost, key, map[string]string{"uploads": ""}, header, nil)
+	if err != nil {
+		return "", fmt.Errorf("create upload: %w", err)
+	}
+
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+
+	err = xml.Unmarshal(resp.body, &result)
+	if err != nil || result.UploadID == "" {
+		return "", fmt.Errorf("create upload: invalid response: %s", resp.body)
+	}
+
+	return result.UploadID, nil
+}
+
+// complete completes the upload from its parts and removes the state file.
+func (u *Uploader) complete(ctx context.Context, path string, state State) error {
+	type completePart struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+
+	request := struct {
+		XMLName xml.Name       `xml:"CompleteMultipartUpload"`
+		Parts   []completePart `xml:"Part"`
+	}{}
+
+	for _, part := range state.Parts {
+		request.Parts = append(request.Parts, completePart{PartNumber: part.Number, ETag: part.ETag})
+	}
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("complete upload: %w", err)
+	}
+
+	_, err = u.do(ctx, http.MethodPost, state.Key, map[string]string{"uploadId": state.UploadID}, nil, body)
+	if err != nil {
+		return fmt.Errorf("complete upload: %w", err)
+	}
+
+	u.log.Info().Str("file", path).Str("key", state.Key).Int("parts", len(state.Parts)).Msg("uploaded recording")
+
+	err = os.Remove(path + StateSuffix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove upload state: %w", err)
+	}
+
+	return nil
+}
+
+type response struct {
+	header http.Header
+	body   []byte
+}
+
+// do sends a signed request for the object, returning an error for any response other than success.
+func (u *Uploader) do(ctx context.Context, method, key string, query map[string]string, header http.Header, body []byte) (response, error) {
+	target := *u.endpoint
+	target.Path = target.Path + "/" + u.bucket + "/" + key
+	target.RawPath = uriEncode(target.Path, true)
+	target.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return response{}, fmt.Errorf("create request: %w", err)
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	u.sign(req, hashHex(body), time.Now())
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return response{}, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return response{}, fmt.Errorf("read response: %w", err)
+	}
+
+	// Completing an upload can fail after the response has started, in which case the error is in the body
+	if resp.StatusCode != http.StatusOK || bytes.Contains(data, []byte("<Error>")) {
+		var serviceErr struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+
+		_ = xml.Unmarshal(data, &serviceErr)
+
+		return response{}, fmt.Errorf("storage responded %s: %s %s", resp.Status, serviceErr.Code, serviceErr.Message)
+	}
+
+	return response{header: resp.Header, body: data}, nil
+}
+
+// saveState writes the state of the upload of a recording, replacing the file so that it is never left half written.
+func saveState(path string, state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode upload state: %w", err)
+	}
+
+	temp := path + StateSuffix + ".tmp"
+
+	err = os.WriteFile(temp, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("write upload state: %w", err)
+	}
+
+	err = os.Rename(temp, path+StateSuffix)
+	if err != nil {
+		return fmt.Errorf("write upload state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package upload_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/upload"
+)
+
+// fakeStorage is a minimal S3 compatible service supporting multipart uploads.
+type fakeStorage struct {
+	mu        sync.Mutex
+	uploads   int
+	headers   http.Header
+	parts     map[int][]byte
+	objects   map[string][]byte
+	failPart  int
+	authority []string
+}
+
+func (s *fakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.authority = append(s.authority, r.Header.Get("Authorization"))
+	query := r.URL.Query()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.uploads++
+		s.headers = r.Header.Clone()
+		s.parts = map[int][]byte{}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>upload-%d</UploadId></InitiateMultipartUploadResult>", s.uploads)
+	case r.Method == http.MethodPut && query.Has("partNumber"):
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		if number == s.failPart {
+			s.failPart = 0
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, "<Error><Code>InternalError</Code><Message>try again</Message></Error>")
+
+			return
+		}
+
+		s.parts[number], _ = io.ReadAll(r.Body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, number))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var request struct {
+			Parts []struct {
+				PartNumber int `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		_ = xml.Unmarshal(body, &request)
+
+		var object []byte
+		for _, part := range request.Parts {
+			object = append(object, s.parts[part.PartNumber]...)
+		}
+
+		s.objects[key] = object
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+type UploadTestSuite struct {
+	suite.Suite
+
+	storage *fakeStorage
+	server  *httptest.Server
+	dir     string
+}
+
+func TestUploadTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(UploadTestSuite))
+}
+
+func (suite *UploadTestSuite) SetupTest() {
+	suite.storage = &fakeStorage{objects: map[string][]byte{}}
+	suite.server = httptest.NewServer(suite.storage)
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *UploadTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *UploadTestSuite) uploader() *upload.Uploader {
+	uploader, err := upload.New(upload.Options{
+		Endpoint:        suite.server.URL,
+		Region:          "eu-west-2",
+		Bucket:          "sessions",
+		Prefix:          "team/alice/",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		PartSize:        upload.MinPartSize,
+		Metadata:        map[string]string{"team": "Blue"},
+		Tags:            map[string]string{"season": "2026"},
+		PollInterval:    10 * time.Millisecond,
+	})
+	suite.Require().NoError(err)
+
+	return uploader
+}
+
+// recording writes a recording of the given size to the test directory.
+func (suite *UploadTestSuite) recording(size int) (string, []byte) {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	path := filepath.Join(suite.dir, "session.gtz")
+	suite.Require().NoError(os.WriteFile(path, data, 0o600))
+
+	return path, data
+}
+
+func (suite *UploadTestSuite) TestUploadSendsRecordingInParts() {
+	// Arrange
+	path, data := suite.recording(upload.MinPartSize*2 + 1000)
+
+	// Act
+	err := suite.uploader().Upload(context.Background(), path)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(data, suite.storage.objects["sessions/team/alice/session.gtz"])
+	suite.Len(suite.storage.parts, 3)
+	suite.Equal("Blue", suite.storage.headers.Get("X-Amz-Meta-Team"))
+	suite.Equal("season=2026", suite.storage.headers.Get("X-Amz-Tagging"))
+	suite.Contains(suite.storage.authority[0], "Credential=AKIDEXAMPLE/")
+	suite.Contains(suite.storage.authority[0], "/eu-west-2/s3/aws4_request")
+	suite.NoFileExists(path + upload.StateSuffix)
+}
+
+func (suite *UploadTestSuite) TestUploadContinuesAfterFailure() {
+	// Arrange
+	path, data := suite.recording(upload.MinPartSize*2 + 1000)
+	suite.storage.failPart = 2
+
+	uploader := suite.uploader()
+	suite.Require().Error(uploader.Upload(context.Background(), path))
+	suite.FileExists(path + upload.StateSuffix)
+
+	// Act
+	err := uploader.Upload(context.Background(), path)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(1, suite.storage.uploads, "The upload should be continued rather than started again")
+	suite.Equal(data, suite.storage.objects["sessions/team/alice/session.gtz"])
+	suite.NoFileExists(path + upload.StateSuffix)
+}
+
+func (suite *UploadTestSuite) TestUploadLiveSendsPartsWhileRecording() {
+	// Arrange
+	path, data := suite.recording(0)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	suite.Require().NoError(err)
+
+	defer file.Close()
+
+	finished := make(chan struct{})
+	result := make(chan error, 1)
+
+	go func() { result <- suite.uploader().UploadLive(context.Background(), path, finished) }()
+
+	// Act
+	for range 3 {
+		chunk := bytes.Repeat([]byte{byte(len(data))}, upload.MinPartSize/2+100)
+		_, err = file.Write(chunk)
+		suite.Require().NoError(err)
+
+		data = append(data, chunk...)
+	}
+
+	suite.Eventually(func() bool {
+		suite.storage.mu.Lock()
+		defer suite.storage.mu.Unlock()
+
+		return len(suite.storage.parts) == 1
+	}, 5*time.Second, 10*time.Millisecond, "A full part should be sent while recording")
+
+	close(finished)
+
+	// Assert
+	suite.Require().NoError(<-result)
+	suite.Len(suite.storage.parts, 2)
+	suite.Equal(data, suite.storage.objects["sessions/team/alice/session.gtz"])
+}
+
+func (suite *UploadTestSuite) TestNewValidatesOptions() {
+	// Arrange
+	valid := upload.Options{Endpoint: "https://s3.example.com", Bucket: "sessions", AccessKeyID: "id", SecretAccessKey: "key"}
+
+	tests := map[string]struct {
+		modify func(opts *upload.Options)
+		want   error
+	}{
+		"invalid endpoint":    {modify: func(opts *upload.Options) { opts.Endpoint = "s3.example.com" }, want: upload.ErrInvalidEndpoint},
+		"no bucket":           {modify: func(opts *upload.Options) { opts.Bucket = "" }, want: upload.ErrNoBucket},
+		"no credentials":      {modify: func(opts *upload.Options) { opts.SecretAccessKey = "" }, want: upload.ErrNoCredentials},
+		"part size too small": {modify: func(opts *upload.Options) { opts.PartSize = 1 << 20 }, want: upload.ErrPartSizeTooSmall},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			opts := valid
+			test.modify(&opts)
+
+			// Act
+			_, err := upload.New(opts)
+
+			// Assert
+			suite.ErrorIs(err, test.want)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+//go:build tinygo
+
+package vehicles
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUpdatesUnsupported indicates that vehicle updates cannot be downloaded in TinyGo builds, which leave out net/http.
+var ErrUpdatesUnsupported = errors.New("vehicle updates are not supported in TinyGo builds")
+
+// HTTPFetcher is not supported in TinyGo builds, every fetch returns ErrUpdatesUnsupported.
+type HTTPFetcher struct {
+	baseURL string
+}
+
+// NewHTTPFetcher creates a new HTTPFetcher pointed at the given base URL.
+func NewHTTPFetcher(baseURL string) *HTTPFetcher {
+	return &HTTPFetcher{baseURL: baseURL}
+}
+
+// FetchManifest returns ErrUpdatesUnsupported.
+func (f *HTTPFetcher) FetchManifest(_ context.Context) (*Manifest, error) {
+	return nil, ErrUpdatesUnsupported
+}
+
+// FetchVehicle returns ErrUpdatesUnsupported.
+func (f *HTTPFetcher) FetchVehicle(_ context.Context, _ int) (Vehicle, error) {
+	return Vehicle{}, ErrUpdatesUnsupported
+}
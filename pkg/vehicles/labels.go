@@ -0,0 +1,95 @@
+package vehicles
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// Labels holds the human-readable labels of the short codes used in the inventory, keyed by code, so that
+// applications can show "Front-engine, Rear-wheel drive" for "FR" without their own mapping tables. Codes without a
+// label are shown as they are.
+type Labels struct {
+	Aspiration map[string]string `json:"aspiration"`
+	Drivetrain map[string]string `json:"drivetrain"`
+	Category   map[string]string `json:"category"`
+}
+
+//nolint:gochecknoglobals // read-only label tables, only handed out as copies
+var (
+	englishAspirations = map[string]string{
+		"EV":    "Electric Vehicle",
+		"NA":    "Naturally Aspirated",
+		"TC":    "Turbocharged",
+		"SC":    "Supercharged",
+		"TC+SC": "Compound Charged",
+	}
+	englishDrivetrains = map[string]string{
+		"FF":  "Front-engine, Front-wheel drive",
+		"FR":  "Front-engine, Rear-wheel drive",
+		"MR":  "Mid-engine, Rear-wheel drive",
+		"RR":  "Rear-engine, Rear-wheel drive",
+		"4WD": "Four-wheel drive",
+	}
+	englishCategories = map[string]string{
+		"Gr.1": "Group 1",
+		"Gr.2": "Group 2",
+		"Gr.3": "Group 3",
+		"Gr.4": "Group 4",
+		"Gr.B": "Group B",
+		"Gr.N": "Road Car",
+		"Gr.X": "Special",
+	}
+)
+
+// EnglishLabels returns the British English labels, which are used by the Expanded methods of Vehicle.
+func EnglishLabels() Labels {
+	return Labels{
+		Aspiration: maps.Clone(englishAspirations),
+		Drivetrain: maps.Clone(englishDrivetrains),
+		Category:   maps.Clone(englishCategories),
+	}
+}
+
+// ParseLabels reads labels in another language from JSON with aspiration, drivetrain and category objects, such as
+// {"drivetrain": {"FR": "Moteur avant, propulsion"}}. Codes missing from the JSON keep their English label, so a
+// partial translation can be used.
+func ParseLabels(data []byte) (Labels, error) {
+	var translated Labels
+
+	err := json.Unmarshal(data, &translated)
+	if err != nil {
+		return Labels{}, fmt.Errorf("parsing labels: %w", err)
+	}
+
+	labels := EnglishLabels()
+	maps.Copy(labels.Aspiration, translated.Aspiration)
+	maps.Copy(labels.Drivetrain, translated.Drivetrain)
+	maps.Copy(labels.Category, translated.Category)
+
+	return labels, nil
+}
+
+// ExpandedAspiration returns the label of an aspiration code, or the code when it has no label.
+func (l Labels) ExpandedAspiration(code string) string {
+	return label(l.Aspiration, code)
+}
+
+// ExpandedDrivetrain returns the label of a drivetrain code, or the code when it has no label.
+func (l Labels) ExpandedDrivetrain(code string) string {
+	return label(l.Drivetrain, code)
+}
+
+// ExpandedCategory returns the label of a category code, or the code when it has no label.
+func (l Labels) ExpandedCategory(code string) string {
+	return label(l.Category, code)
+}
+
+// label looks up the label of a code, falling back to the code.
+func label(labels map[string]string, code string) string {
+	if expanded, ok := labels[code]; ok {
+		return expanded
+	}
+
+	return code
+}
@@ -0,0 +1,59 @@
+package vehicles_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type LabelsTestSuite struct {
+	suite.Suite
+}
+
+func TestLabelsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LabelsTestSuite))
+}
+
+func (suite *LabelsTestSuite) TestParseLabelsFallsBackToEnglish() {
+	// Arrange
+	data := []byte(`{
+		"drivetrain": {"FR": "Moteur avant, propulsion"},
+		"category": {"Gr.N": "Voiture de série"}
+	}`)
+
+	// Act
+	labels, err := vehicles.ParseLabels(data)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("Moteur avant, propulsion", labels.ExpandedDrivetrain("FR"))
+	suite.Equal("Mid-engine, Rear-wheel drive", labels.ExpandedDrivetrain("MR"))
+	suite.Equal("Voiture de série", labels.ExpandedCategory("Gr.N"))
+	suite.Equal("Turbocharged", labels.ExpandedAspiration("TC"))
+	suite.Equal("LP", labels.ExpandedDrivetrain("LP"))
+}
+
+func (suite *LabelsTestSuite) TestParseLabelsRejectsInvalidJSON() {
+	// Act
+	_, err := vehicles.ParseLabels([]byte(`{"drivetrain": ["FR"]}`))
+
+	// Assert
+	suite.Error(err)
+}
+
+func (suite *LabelsTestSuite) TestEnglishLabelsAreCopies() {
+	// Arrange
+	labels := vehicles.EnglishLabels()
+	labels.Drivetrain["FR"] = "changed"
+
+	vehicle := vehicles.Vehicle{Drivetrain: "FR"}
+
+	// Act
+	gotValue := vehicle.ExpandedDrivetrain()
+
+	// Assert
+	suite.Equal("Front-engine, Rear-wheel drive", gotValue)
+	suite.Equal("Front-engine, Rear-wheel drive", vehicles.EnglishLabels().ExpandedDrivetrain("FR"))
+}
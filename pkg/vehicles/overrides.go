@@ -0,0 +1,75 @@
+package vehicles
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// LoadOverrides layers user overrides over the inventory. The overrides JSON uses the same structure as the inventory,
+// a map of car ID to vehicle, but only the fields present in each entry are replaced, so a single incorrect value can
+// be corrected without copying the whole vehicle. Entries for vehicles missing from the inventory are added. The
+// overrides are also applied to vehicles downloaded later from the update server.
+func (db *VehicleDB) LoadOverrides(overridesJSON []byte) error {
+	overrides := map[string]json.RawMessage{}
+
+	err := json.Unmarshal(overridesJSON, &overrides)
+	if err != nil {
+		return fmt.Errorf("unmarshall vehicle overrides JSON: %w", err)
+	}
+
+	// Check every entry before any are applied so that invalid overrides leave the inventory unchanged
+	for key, override := range overrides {
+		_, err = strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("invalid car ID %q in vehicle overrides: %w", key, err)
+		}
+
+		var vehicle Vehicle
+
+		err = json.Unmarshal(override, &vehicle)
+		if err != nil {
+			return fmt.Errorf("unmarshall vehicle override %s: %w", key, err)
+		}
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.overrides == nil {
+		db.overrides = make(map[string]json.RawMessage, len(overrides))
+	}
+
+	for key, override := range overrides {
+		db.overrides[key] = override
+		db.inventory[key] = db.applyOverride(key, db.inventory[key])
+	}
+
+	db.updateLatestModified()
+
+	return nil
+}
+
+// applyOverride merges the override for the car ID, if any, over the vehicle. The caller must hold the write lock.
+func (db *VehicleDB) applyOverride(key string, vehicle Vehicle) Vehicle {
+	override, ok := db.overrides[key]
+	if !ok {
+		return vehicle
+	}
+
+	// Unmarshalling over the existing vehicle replaces only the fields present in the override
+	err := json.Unmarshal(override, &vehicle)
+	if err != nil {
+		db.log.Warn().Err(err).Str("car_id", key).Msg("failed to apply vehicle override")
+
+		return vehicle
+	}
+
+	if vehicle.CarID == 0 {
+		vehicle.CarID, _ = strconv.Atoi(key)
+	}
+
+	vehicle.source = SourceOverride
+
+	return vehicle
+}
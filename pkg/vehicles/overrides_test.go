@@ -0,0 +1,183 @@
+package vehicles_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type OverridesTestSuite struct {
+	suite.Suite
+}
+
+func TestOverridesTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(OverridesTestSuite))
+}
+
+const overridesInventoryJSON = `{
+	"12345": {"carId": 12345, "manufacturer": "OriginalCo", "model": "OriginalCar", "wheelbase": 0, "drivetrain": "FR"}
+}`
+
+func (suite *OverridesTestSuite) TestOverrideReplacesOnlyFieldsPresent() {
+	// Arrange
+	db, err := VehicleDBWithFetcherDisabled([]byte(overridesInventoryJSON))
+	suite.Require().NoError(err)
+
+	// Act
+	err = db.LoadOverrides([]byte(`{"12345": {"wheelbase": 2650}}`))
+	suite.Require().NoError(err)
+
+	got, err := db.GetVehicleByID(12345)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(2650, got.Wheelbase)
+	suite.Equal("OriginalCo", got.Manufacturer)
+	suite.Equal("FR", got.Drivetrain)
+	suite.Equal(vehicles.SourceOverride, got.Source())
+}
+
+func (suite *OverridesTestSuite) TestOverrideAddsVehicleMissingFromInventory() {
+	// Arrange
+	db, err := VehicleDBWithFetcherDisabled([]byte(overridesInventoryJSON))
+	suite.Require().NoError(err)
+
+	// Act
+	err = db.LoadOverrides([]byte(`{"999": {"manufacturer": "CustomCo", "model": "CustomCar"}}`))
+	suite.Require().NoError(err)
+
+	got, err := db.GetVehicleByID(999)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(999, got.CarID, "Car ID should be taken from the override key")
+	suite.Equal("CustomCo", got.Manufacturer)
+}
+
+func (suite *OverridesTestSuite) TestInvalidOverridesLeaveInventoryUnchanged() {
+	tests := map[string]string{
+		"malformed JSON": `{"12345": `,
+		"invalid car ID": `{"12345": {"wheelbase": 2650}, "abc": {"model": "Car"}}`,
+		"invalid field":  `{"12345": {"wheelbase": "long"}}`,
+	}
+
+	for name, overridesJSON := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			db, err := VehicleDBWithFetcherDisabled([]byte(overridesInventoryJSON))
+			suite.Require().NoError(err)
+
+			// Act
+			err = db.LoadOverrides([]byte(overridesJSON))
+
+			// Assert
+			suite.Require().Error(err)
+
+			got, err := db.GetVehicleByID(12345)
+			suite.Require().NoError(err)
+			suite.Equal(0, got.Wheelbase)
+			suite.Equal(vehicles.SourceInventory, got.Source())
+		})
+	}
+}
+
+func (suite *OverridesTestSuite) TestOverridesFileOptionIsLoaded() {
+	// Arrange
+	overridesFile := filepath.Join(suite.T().TempDir(), "overrides.json")
+
+	err := os.WriteFile(overridesFile, []byte(`{"12345": {"model": "CorrectedCar"}}`), 0o600)
+	suite.Require().NoError(err)
+
+	// Act
+	db, err := vehicles.NewDB([]byte(overridesInventoryJSON), vehicles.DBOptions{OverridesFile: overridesFile})
+	suite.Require().NoError(err)
+
+	got, err := db.GetVehicleByID(12345)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal("CorrectedCar", got.Model)
+}
+
+func (suite *OverridesTestSuite) TestMissingOverridesFileReturnsError() {
+	// Arrange
+	overridesFile := filepath.Join(suite.T().TempDir(), "missing.json")
+
+	// Act
+	_, err := vehicles.NewDB([]byte(overridesInventoryJSON), vehicles.DBOptions{OverridesFile: overridesFile})
+
+	// Assert
+	suite.Require().Error(err)
+}
+
+func (suite *OverridesTestSuite) TestOverrideIsAppliedToRemoteUpdates() {
+	// Arrange
+	fetcher := &mockFetcher{
+		vehicle: vehicles.Vehicle{
+			CarID:        12345,
+			Manufacturer: "UpdatedCo",
+			Model:        "UpdatedCar",
+			LastModified: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		onFetchManifest: func(_ context.Context) (*vehicles.Manifest, error) {
+			return &vehicles.Manifest{
+				Vehicles: map[string]vehicles.ManifestEntry{
+					"12345": {LastModified: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			}, nil
+		},
+	}
+
+	db, err := vehicles.NewDB([]byte(overridesInventoryJSON), vehicles.DBOptions{Fetcher: fetcher})
+	suite.Require().NoError(err)
+
+	err = db.LoadOverrides([]byte(`{"12345": {"wheelbase": 2650}}`))
+	suite.Require().NoError(err)
+
+	// Act
+	count, err := db.DownloadUpdatedVehicles(context.Background())
+	suite.Require().NoError(err)
+
+	got, err := db.GetVehicleByID(12345)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(1, count)
+	suite.Equal("UpdatedCo", got.Manufacturer)
+	suite.Equal(2650, got.Wheelbase)
+	suite.Equal(vehicles.SourceOverride, got.Source())
+}
+
+func (suite *OverridesTestSuite) TestSourceReportsCacheAndRemote() {
+	// Arrange
+	tmpDir := suite.T().TempDir()
+
+	err := os.WriteFile(filepath.Join(tmpDir, "12345.json"),
+		[]byte(`{"carId": 12345, "manufacturer": "CachedCo", "lastModified": "2026-01-01T00:00:00Z"}`), 0o600)
+	suite.Require().NoError(err)
+
+	fetcher := &mockFetcher{vehicle: vehicles.Vehicle{CarID: 777, Manufacturer: "RemoteCo"}}
+
+	db, err := vehicles.NewDB([]byte(overridesInventoryJSON), vehicles.DBOptions{Fetcher: fetcher, CacheDir: tmpDir})
+	suite.Require().NoError(err)
+
+	// Act
+	cached, err := db.GetVehicleByID(12345)
+	suite.Require().NoError(err)
+
+	_, _ = db.GetVehicleByID(777)
+
+	// Assert
+	suite.Equal(vehicles.SourceCache, cached.Source())
+	suite.Eventually(func() bool {
+		remote, _ := db.GetVehicleByID(777)
+
+		return remote.Source() == vehicles.SourceRemote
+	}, 2*time.Second, 10*time.Millisecond)
+}
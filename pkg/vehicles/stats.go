@@ -0,0 +1,89 @@
+package vehicles
+
+import (
+	"sort"
+)
+
+// InventoryStats summarises the coverage of the vehicle inventory.
+type InventoryStats struct {
+	Total          int                 `json:"total"`
+	ByManufacturer map[string]int      `json:"byManufacturer"`
+	ByCategory     map[string]int      `json:"byCategory"`
+	ByDrivetrain   map[string]int      `json:"byDrivetrain"`
+	Incomplete     []IncompleteVehicle `json:"incomplete"`
+}
+
+// IncompleteVehicle describes an inventory entry that is missing dimension or engine data.
+type IncompleteVehicle struct {
+	CarID         int      `json:"carId"`
+	Manufacturer  string   `json:"manufacturer"`
+	Model         string   `json:"model"`
+	MissingFields []string `json:"missingFields"`
+}
+
+// Stats returns counts of the vehicles in the inventory by manufacturer, category and drivetrain, along with the
+// entries that are missing dimension or engine data, ordered by CarID.
+func (db *VehicleDB) Stats() InventoryStats {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	stats := InventoryStats{
+		Total:          len(db.inventory),
+		ByManufacturer: make(map[string]int),
+		ByCategory:     make(map[string]int),
+		ByDrivetrain:   make(map[string]int),
+		Incomplete:     []IncompleteVehicle{},
+	}
+
+	for _, vehicle := range db.inventory {
+		stats.ByManufacturer[vehicle.Manufacturer]++
+		stats.ByCategory[vehicle.Category]++
+		stats.ByDrivetrain[vehicle.Drivetrain]++
+
+		missing := vehicle.MissingFields()
+		if len(missing) > 0 {
+			stats.Incomplete = append(stats.Incomplete, IncompleteVehicle{
+				CarID:         vehicle.CarID,
+				Manufacturer:  vehicle.Manufacturer,
+				Model:         vehicle.Model,
+				MissingFields: missing,
+			})
+		}
+	}
+
+	sort.Slice(stats.Incomplete, func(i, j int) bool {
+		return stats.Incomplete[i].CarID < stats.Incomplete[j].CarID
+	})
+
+	return stats
+}
+
+// MissingFields returns the JSON names of the dimension and engine fields that have no data. Electric vehicles have
+// no engine, so the engine layout is not required for them.
+func (v *Vehicle) MissingFields() []string {
+	dimensions := []struct {
+		name  string
+		value int
+	}{
+		{"length", v.Length},
+		{"width", v.Width},
+		{"height", v.Height},
+		{"wheelbase", v.Wheelbase},
+		{"trackFront", v.TrackFront},
+		{"trackRear", v.TrackRear},
+	}
+
+	missing := []string{}
+
+	for _, dimension := range dimensions {
+		if dimension.value == 0 {
+			missing = append(missing, dimension.name)
+		}
+	}
+
+	if v.EngineLayout == "" && v.Aspiration != "EV" {
+		missing = append(missing, "engineLayout")
+	}
+
+	return missing
+}
@@ -0,0 +1,84 @@
+package vehicles_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StatsTestSuite))
+}
+
+func (suite *StatsTestSuite) TestStatsCountsVehiclesByGroup() {
+	// Arrange
+	inventoryJSON := []byte(`{
+		"1": {"carId": 1, "manufacturer": "Mazda", "category": "Gr.3", "drivetrain": "FR"},
+		"2": {"carId": 2, "manufacturer": "Mazda", "category": "Gr.4", "drivetrain": "FR"},
+		"3": {"carId": 3, "manufacturer": "Porsche", "category": "Gr.3", "drivetrain": "MR"}
+	}`)
+
+	db, err := VehicleDBWithFetcherDisabled(inventoryJSON)
+	suite.Require().NoError(err)
+
+	// Act
+	stats := db.Stats()
+
+	// Assert
+	suite.Equal(3, stats.Total)
+	suite.Equal(map[string]int{"Mazda": 2, "Porsche": 1}, stats.ByManufacturer)
+	suite.Equal(map[string]int{"Gr.3": 2, "Gr.4": 1}, stats.ByCategory)
+	suite.Equal(map[string]int{"FR": 2, "MR": 1}, stats.ByDrivetrain)
+}
+
+func (suite *StatsTestSuite) TestStatsListsIncompleteVehiclesInCarIDOrder() {
+	// Arrange
+	inventoryJSON := []byte(`{
+		"1": {"carId": 1, "manufacturer": "Complete", "length": 4000, "width": 1800, "height": 1200,
+			"wheelbase": 2500, "trackFront": 1600, "trackRear": 1600, "engineLayout": "V6"},
+		"3": {"carId": 3, "manufacturer": "Electric", "aspiration": "EV", "length": 4000, "width": 1800,
+			"height": 1200, "wheelbase": 2500, "trackFront": 1600, "trackRear": 0},
+		"2": {"carId": 2, "manufacturer": "NoEngine", "length": 4000, "width": 1800, "height": 1200,
+			"wheelbase": 2500, "trackFront": 1600, "trackRear": 1600}
+	}`)
+
+	db, err := VehicleDBWithFetcherDisabled(inventoryJSON)
+	suite.Require().NoError(err)
+
+	// Act
+	stats := db.Stats()
+
+	// Assert
+	suite.Require().Len(stats.Incomplete, 2)
+	suite.Equal(2, stats.Incomplete[0].CarID)
+	suite.Equal([]string{"engineLayout"}, stats.Incomplete[0].MissingFields)
+	suite.Equal(3, stats.Incomplete[1].CarID)
+	suite.Equal([]string{"trackRear"}, stats.Incomplete[1].MissingFields, "Electric vehicles should not require an engine layout")
+}
+
+func (suite *StatsTestSuite) TestStatsCoversEmbeddedInventory() {
+	// Arrange
+	wantCount, err := vehicles.EmbeddedInventoryCount()
+	suite.Require().NoError(err)
+
+	db, err := VehicleDBWithFetcherDisabled(nil)
+	suite.Require().NoError(err)
+
+	// Act
+	stats := db.Stats()
+
+	// Assert
+	total := 0
+	for _, count := range stats.ByManufacturer {
+		total += count
+	}
+
+	suite.Equal(wantCount, stats.Total)
+	suite.Equal(wantCount, total)
+}
@@ -13,7 +13,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -29,6 +29,8 @@ var ErrVehicleNotFound = errors.New("no vehicle found with id")
 type Vehicle struct {
 	CarID                 int       `csv:"CarId"                 json:"carId"`
 	Manufacturer          string    `csv:"Manufacturer"          json:"manufacturer"`
+	ManufacturerID        string    `csv:"ManufacturerId"        json:"manufacturerId,omitempty"`
+	ManufacturerCountry   string    `csv:"ManufacturerCountry"   json:"manufacturerCountry,omitempty"`
 	Model                 string    `csv:"Model"                 json:"model"`
 	Year                  int       `csv:"Year"                  json:"year"`
 	OpenCockpit           bool      `csv:"OpenCockpit"           json:"openCockpit"`
@@ -45,9 +47,21 @@ type Vehicle struct {
 	EngineLayout          string    `csv:"EngineLayout"          json:"engineLayout"`
 	EngineBankAngle       float32   `csv:"EngineBankAngle"       json:"engineBankAngle"`
 	EngineCrankPlaneAngle float32   `csv:"EngineCrankPlaneAngle" json:"engineCrankPlaneAngle"`
+	EngineRedline         int       `csv:"EngineRedline"         json:"engineRedline,omitempty"`
 	LastModified          time.Time `csv:"-"                     json:"lastModified,omitzero"`
+	source                Source
 }
 
+// Source identifies where the data for a vehicle was loaded from.
+type Source string
+
+const (
+	SourceInventory Source = "inventory" // embedded or supplied inventory JSON
+	SourceCache     Source = "cache"     // cache directory
+	SourceRemote    Source = "remote"    // downloaded from the update server
+	SourceOverride  Source = "override"  // user overrides, layered over any of the above
+)
+
 // VehicleInventory represents the complete JSON structure from the embedded vehicle inventory data.
 type VehicleInventory map[string]Vehicle
 
@@ -60,14 +74,16 @@ type backoffState struct {
 type DBOptions struct {
 	CacheDir      string
 	UpdateBaseURL string
+	OverridesFile string // JSON file of vehicle fields overriding the inventory, keyed by car ID
 	Fetcher       Fetcher
-	Logger        *zerolog.Logger
+	Logger        *logging.Logger // a *zerolog.Logger, except in TinyGo builds
 }
 
 // VehicleDB provides an object and methods to access vehicle information from the embedded inventory.
 type VehicleDB struct {
 	mu             sync.RWMutex
 	inventory      VehicleInventory
+	overrides      map[string]json.RawMessage
 	latestModified time.Time
 	fetcher        Fetcher
 	group          singleflight.Group
@@ -76,7 +92,7 @@ type VehicleDB struct {
 	cacheDir       string
 	updateBaseURL  string
 	cancel         context.CancelFunc
-	log            *zerolog.Logger
+	log            *logging.Logger
 }
 
 //go:embed inventory
@@ -149,11 +165,11 @@ func NewDB(inventoryJSON []byte, opts DBOptions) (*VehicleDB, error) {
 		}
 	}
 
-	var logger zerolog.Logger
+	var logger logging.Logger
 	if opts.Logger == nil {
-		logger = zerolog.Nop().With().Str("component", "vehicle db").Logger()
+		logger = logging.Nop()
 	} else {
-		logger = opts.Logger.With().Str("component", "vehicle db").Logger()
+		logger = logging.WithComponent(*opts.Logger, "vehicle db")
 	}
 
 	vehicleDB := &VehicleDB{
@@ -168,6 +184,18 @@ func NewDB(inventoryJSON []byte, opts DBOptions) (*VehicleDB, error) {
 	vehicleDB.loadCacheDir()
 	vehicleDB.updateLatestModified()
 
+	if opts.OverridesFile != "" {
+		overridesJSON, err := os.ReadFile(opts.OverridesFile)
+		if err != nil {
+			return nil, fmt.Errorf("read vehicle overrides: %w", err)
+		}
+
+		err = vehicleDB.LoadOverrides(overridesJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return vehicleDB, nil
 }
 
@@ -294,7 +322,8 @@ func (db *VehicleDB) downloadUpdatedVehicles(ctx context.Context) (int, error) {
 
 		db.mu.Lock()
 
-		db.inventory[carIDStr] = vehicle
+		vehicle.source = SourceRemote
+		db.inventory[carIDStr] = db.applyOverride(carIDStr, vehicle)
 		if vehicle.LastModified.After(db.latestModified) {
 			db.latestModified = vehicle.LastModified
 		}
@@ -358,6 +387,7 @@ func (db *VehicleDB) loadCacheFile(name string) {
 
 	existing, exists := db.inventory[key]
 	if !exists || vehicle.LastModified.After(existing.LastModified) {
+		vehicle.source = SourceCache
 		db.inventory[key] = vehicle
 	}
 }
@@ -382,7 +412,8 @@ func (db *VehicleDB) fetchAndStore(vehicleID int) {
 
 		db.mu.Lock()
 
-		db.inventory[key] = vehicle
+		vehicle.source = SourceRemote
+		db.inventory[key] = db.applyOverride(key, vehicle)
 		if vehicle.LastModified.After(db.latestModified) {
 			db.latestModified = vehicle.LastModified
 		}
@@ -476,20 +507,26 @@ func (db *VehicleDB) resetBackoff(id int) {
 	delete(db.backoff, id)
 }
 
+// Source returns where the data for the vehicle was loaded from.
+func (v *Vehicle) Source() Source {
+	if v.source == "" {
+		return SourceInventory
+	}
+
+	return v.source
+}
+
 // ExpandedAspiration provides a human-readable description of the vehicle's aspiration type.
 func (v *Vehicle) ExpandedAspiration() string {
-	switch v.Aspiration {
-	case "EV":
-		return "Electric Vehicle"
-	case "NA":
-		return "Naturally Aspirated"
-	case "TC":
-		return "Turbocharged"
-	case "SC":
-		return "Supercharged"
-	case "TC+SC":
-		return "Compound Charged"
-	default:
-		return v.Aspiration
-	}
+	return label(englishAspirations, v.Aspiration)
+}
+
+// ExpandedDrivetrain provides a human-readable description of the vehicle's drivetrain layout.
+func (v *Vehicle) ExpandedDrivetrain() string {
+	return label(englishDrivetrains, v.Drivetrain)
+}
+
+// ExpandedCategory provides a human-readable description of the vehicle's racing category.
+func (v *Vehicle) ExpandedCategory() string {
+	return label(englishCategories, v.Category)
 }
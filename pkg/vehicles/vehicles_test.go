@@ -185,6 +185,59 @@ func (suite *VehiclesTestSuite) TestShortAspirationExpandsToLongName() {
 	}
 }
 
+func (suite *VehiclesTestSuite) TestShortDrivetrainExpandsToLongName() {
+	tests := map[string]string{
+		"FF":      "Front-engine, Front-wheel drive",
+		"FR":      "Front-engine, Rear-wheel drive",
+		"MR":      "Mid-engine, Rear-wheel drive",
+		"RR":      "Rear-engine, Rear-wheel drive",
+		"4WD":     "Four-wheel drive",
+		"INVALID": "INVALID",
+	}
+
+	for shortName, wantValue := range tests {
+		suite.Run(shortName, func() {
+			// Arrange
+			vehicle := vehicles.Vehicle{
+				Drivetrain: shortName,
+			}
+
+			// Act
+			gotValue := vehicle.ExpandedDrivetrain()
+
+			// Assert
+			suite.Equal(wantValue, gotValue)
+		})
+	}
+}
+
+func (suite *VehiclesTestSuite) TestShortCategoryExpandsToLongName() {
+	tests := map[string]string{
+		"Gr.1":    "Group 1",
+		"Gr.3":    "Group 3",
+		"Gr.B":    "Group B",
+		"Gr.N":    "Road Car",
+		"Gr.X":    "Special",
+		"":        "",
+		"INVALID": "INVALID",
+	}
+
+	for shortName, wantValue := range tests {
+		suite.Run(shortName, func() {
+			// Arrange
+			vehicle := vehicles.Vehicle{
+				Category: shortName,
+			}
+
+			// Act
+			gotValue := vehicle.ExpandedCategory()
+
+			// Assert
+			suite.Equal(wantValue, gotValue)
+		})
+	}
+}
+
 // --- Mock Fetcher ---
 
 type mockFetcher struct {
@@ -0,0 +1,243 @@
+// Package webhook delivers events as JSON to HTTP webhooks, so that services such as league bots and databases can
+// react to a session without holding a connection open.
+//
+// Events are queued by Emit and delivered in order by Run, with each failed delivery retried with a growing delay.
+// When a secret is configured every request carries an HMAC-SHA256 signature of its body, see SignatureHeader, so that
+// receivers can check the event was sent by the daemon.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// SignatureHeader holds the hex encoded HMAC-SHA256 of the request body, prefixed with "sha256=", when a secret is
+	// configured.
+	SignatureHeader = "X-GT-Signature"
+
+	// DefaultRetries is the number of times a failed delivery is retried when no count is configured.
+	DefaultRetries = 3
+	// DefaultRetryDelay is the delay before the first retry when none is configured, doubled for each further retry.
+	DefaultRetryDelay = time.Second
+	// DefaultQueueSize is the number of events held for delivery when no size is configured.
+	DefaultQueueSize = 100
+
+	// deliveryTimeout is how long a webhook may take to accept an event.
+	deliveryTimeout = 10 * time.Second
+)
+
+var (
+	// ErrNoURLs indicates a sink configured without any webhook URLs.
+	ErrNoURLs = errors.New("no webhook URLs")
+	// ErrInvalidURL indicates a webhook URL that is not an http or https URL.
+	ErrInvalidURL = errors.New("invalid webhook URL")
+)
+
+// Options configures a Sink.
+type Options struct {
+	// URLs are the webhooks every event is delivered to.
+	URLs []string
+	// Headers are sent with every request, e.g. {"Authorization": "Bearer secret"}.
+	Headers map[string]string
+	// Secret signs the body of every request in the SignatureHeader when set.
+	Secret string
+	// Retries is the number of times a failed delivery is retried, defaults to DefaultRetries. Set it negative to
+	// disable retries.
+	Retries int
+	// RetryDelay is the delay before the first retry, defaults to DefaultRetryDelay.
+	RetryDelay time.Duration
+	// QueueSize is the number of events held for delivery, defaults to DefaultQueueSize. Events emitted while the queue
+	// is full are dropped.
+	QueueSize int
+	// Client is used to deliver events, defaults to a client with a short timeout.
+	Client *http.Client
+	// Logger is used for sink log messages, defaults to a disabled logger.
+	Logger *zerolog.Logger
+}
+
+// Sink delivers events to webhooks.
+type Sink struct {
+	urls       []string
+	headers    map[string]string
+	secret     []byte
+	retries    int
+	retryDelay time.Duration
+	queue      chan []byte
+	client     *http.Client
+	log        zerolog.Logger
+}
+
+// New creates a sink delivering to the webhooks.
+func New(opts Options) (*Sink, error) {
+	if len(opts.URLs) == 0 {
+		return nil, ErrNoURLs
+	}
+
+	for _, webhook := range opts.URLs {
+		target, err := url.Parse(webhook)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidURL, webhook)
+		}
+	}
+
+	if opts.Retries == 0 {
+		opts.Retries = DefaultRetries
+	}
+
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = DefaultRetryDelay
+	}
+
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: deliveryTimeout}
+	}
+
+	log := zerolog.Nop()
+	if opts.Logger != nil {
+		log = *opts.Logger
+	}
+
+	return &Sink{
+		urls:       opts.URLs,
+		headers:    opts.Headers,
+		secret:     []byte(opts.Secret),
+		retries:    max(opts.Retries, 0),
+		retryDelay: opts.RetryDelay,
+		queue:      make(chan []byte, opts.QueueSize),
+		client:     opts.Client,
+		log:        log,
+	}, nil
+}
+
+// Emit queues an event for delivery by Run, returning false when the event could not be encoded or the queue is full.
+// It never blocks, so it can be called from the telemetry loop.
+func (s *Sink) Emit(event any) bool {
+	body, err := json.Marshal(event)
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to encode webhook event")
+
+		return false
+	}
+
+	select {
+	case s.queue <- body:
+		return true
+	default:
+		s.log.Warn().Msg("webhook queue full, event dropped")
+
+		return false
+	}
+}
+
+// Run delivers queued events until the context is cancelled. Events that still fail after every retry are logged and
+// dropped, so that a webhook that is down does not hold up the events behind them.
+func (s *Sink) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case body := <-s.queue:
+			for _, webhook := range s.urls {
+				err := s.deliver(ctx, webhook, body)
+				if err != nil && ctx.Err() == nil {
+					s.log.Warn().Err(err).Str("url", webhook).Msg("failed to deliver webhook event")
+				}
+			}
+		}
+	}
+}
+
+// Deliver sends an event to every webhook straight away, retrying failed deliveries, and returns the errors of the
+// webhooks it could not be delivered to.
+func (s *Sink) Deliver(ctx context.Context, event any) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	var errs []error
+
+	for _, webhook := range s.urls {
+		err = s.deliver(ctx, webhook, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", webhook, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deliver posts the body to a webhook, retrying after network errors, rate limiting and server errors.
+func (s *Sink) deliver(ctx context.Context, webhook string, body []byte) error {
+	delay := s.retryDelay
+
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(ctx, webhook, body)
+		if err == nil || !retry || attempt == s.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// post sends a single request, returning whether a failure is worth retrying.
+func (s *Sink) post(ctx context.Context, webhook string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	if len(s.secret) > 0 {
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("send event: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("send event: webhook responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("send event: webhook responded %s", resp.Status)
+	}
+}
@@ -0,0 +1,200 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/webhook"
+)
+
+// receiver records the requests made to a webhook, responding with the queued statuses before succeeding.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(req.Body)
+	r.bodies = append(r.bodies, string(body))
+	r.headers = append(r.headers, req.Header.Clone())
+
+	if len(r.statuses) > 0 {
+		w.WriteHeader(r.statuses[0])
+		r.statuses = r.statuses[1:]
+	}
+}
+
+func (r *receiver) requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.bodies)
+}
+
+type WebhookTestSuite struct {
+	suite.Suite
+
+	receiver *receiver
+	server   *httptest.Server
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.receiver = &receiver{}
+	suite.server = httptest.NewServer(suite.receiver)
+}
+
+func (suite *WebhookTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *WebhookTestSuite) sink(modify func(opts *webhook.Options)) *webhook.Sink {
+	opts := webhook.Options{
+		URLs:       []string{suite.server.URL},
+		RetryDelay: time.Millisecond,
+	}
+
+	if modify != nil {
+		modify(&opts)
+	}
+
+	sink, err := webhook.New(opts)
+	suite.Require().NoError(err)
+
+	return sink
+}
+
+func (suite *WebhookTestSuite) TestDeliverSendsSignedJSON() {
+	// Arrange
+	sink := suite.sink(func(opts *webhook.Options) {
+		opts.Secret = "secret"
+		opts.Headers = map[string]string{"Authorization": "Bearer token"}
+	})
+
+	// Act
+	err := sink.Deliver(context.Background(), map[string]string{"type": "sessionStart"})
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, suite.receiver.requests())
+	suite.JSONEq(`{"type":"sessionStart"}`, suite.receiver.bodies[0])
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(suite.receiver.bodies[0]))
+
+	headers := suite.receiver.headers[0]
+	suite.Equal("sha256="+hex.EncodeToString(mac.Sum(nil)), headers.Get(webhook.SignatureHeader))
+	suite.Equal("application/json", headers.Get("Content-Type"))
+	suite.Equal("Bearer token", headers.Get("Authorization"))
+}
+
+func (suite *WebhookTestSuite) TestDeliverRetries() {
+	tests := map[string]struct {
+		statuses     []int
+		wantRequests int
+		wantErr      bool
+	}{
+		"server error is retried":      {statuses: []int{http.StatusInternalServerError}, wantRequests: 2},
+		"rate limiting is retried":     {statuses: []int{http.StatusTooManyRequests}, wantRequests: 2},
+		"client error is not retried":  {statuses: []int{http.StatusBadRequest}, wantRequests: 1, wantErr: true},
+		"retries are exhausted":        {statuses: []int{500, 500, 500, 500}, wantRequests: 4, wantErr: true},
+		"success is not retried":       {statuses: nil, wantRequests: 1},
+		"redirects count as a failure": {statuses: []int{http.StatusNotModified}, wantRequests: 1, wantErr: true},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.receiver.bodies = nil
+			suite.receiver.statuses = test.statuses
+			sink := suite.sink(nil)
+
+			// Act
+			err := sink.Deliver(context.Background(), "event")
+
+			// Assert
+			suite.Equal(test.wantRequests, suite.receiver.requests())
+
+			if test.wantErr {
+				suite.Error(err)
+			} else {
+				suite.NoError(err)
+			}
+		})
+	}
+}
+
+func (suite *WebhookTestSuite) TestRunDeliversEmittedEventsInOrder() {
+	// Arrange
+	sink := suite.sink(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() { done <- sink.Run(ctx) }()
+
+	// Act
+	suite.True(sink.Emit(1))
+	suite.True(sink.Emit(2))
+
+	// Assert
+	suite.Eventually(func() bool { return suite.receiver.requests() == 2 }, 5*time.Second, time.Millisecond)
+	suite.Equal([]string{"1", "2"}, suite.receiver.bodies)
+
+	cancel()
+	suite.NoError(<-done)
+}
+
+func (suite *WebhookTestSuite) TestEmitDropsEventsWhenQueueFull() {
+	// Arrange
+	sink := suite.sink(func(opts *webhook.Options) { opts.QueueSize = 1 })
+
+	// Act
+	first := sink.Emit(1)
+	second := sink.Emit(2)
+
+	// Assert
+	suite.True(first)
+	suite.False(second)
+}
+
+func (suite *WebhookTestSuite) TestNewValidatesURLs() {
+	tests := map[string]struct {
+		urls []string
+		want error
+	}{
+		"no URLs":        {urls: nil, want: webhook.ErrNoURLs},
+		"missing scheme": {urls: []string{"example.com/hook"}, want: webhook.ErrInvalidURL},
+		"unsupported":    {urls: []string{"ftp://example.com/hook"}, want: webhook.ErrInvalidURL},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			_, err := webhook.New(webhook.Options{URLs: test.urls})
+
+			// Assert
+			suite.ErrorIs(err, test.want)
+		})
+	}
+}
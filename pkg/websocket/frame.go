@@ -0,0 +1,123 @@
+package websocket
+
+import (
+	"crypto/sha1" //nolint:gosec // Required by the WebSocket handshake, not used for security
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// acceptGUID is appended to the client key to form the accept key, as defined by RFC 6455.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxControlPayload is the largest payload allowed in a control frame.
+const maxControlPayload = 125
+
+const (
+	opText  byte = 0x1
+	opClose byte = 0x8
+	opPing  byte = 0x9
+	opPong  byte = 0xA
+)
+
+var (
+	// ErrFrameTooLarge indicates a frame from a client that is larger than the server accepts.
+	ErrFrameTooLarge = errors.New("frame too large")
+	// ErrUnmaskedFrame indicates a frame from a client that was not masked.
+	ErrUnmaskedFrame = errors.New("client frame not masked")
+)
+
+// acceptKey returns the Sec-WebSocket-Accept header value for a client key.
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID)) //nolint:gosec // Required by the WebSocket handshake
+
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// writeFrame writes a single unfragmented, unmasked frame as sent by a server.
+func writeFrame(w io.Writer, opcode byte, payload []byte) error {
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+
+	length := len(payload)
+
+	switch {
+	case length <= maxControlPayload:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	_, err := w.Write(append(header, payload...))
+	if err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+
+	return nil
+}
+
+// readFrame reads a single masked frame as sent by a client and returns its opcode and unmasked payload. Payloads
+// larger than maxPayload are rejected, the server only expects small control frames from clients.
+func readFrame(r io.Reader, maxPayload int) (byte, []byte, error) {
+	header := make([]byte, 2)
+
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read frame header: %w", err)
+	}
+
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+
+		_, err = io.ReadFull(r, extended)
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+
+		_, err = io.ReadFull(r, extended)
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	if err != nil {
+		return 0, nil, fmt.Errorf("read frame length: %w", err)
+	}
+
+	if !masked {
+		return 0, nil, ErrUnmaskedFrame
+	}
+
+	if length > uint64(maxPayload) { //nolint:gosec // maxPayload is a small positive constant
+		return 0, nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, length)
+	}
+
+	mask := make([]byte, 4)
+
+	_, err = io.ReadFull(r, mask)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read frame mask: %w", err)
+	}
+
+	payload := make([]byte, length)
+
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read frame payload: %w", err)
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return opcode, payload, nil
+}
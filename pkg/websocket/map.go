@@ -0,0 +1,62 @@
+package websocket
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultMapInterval is the interval at which the map channel is sent when no interval is configured, often
+	// enough for a smoothly moving dot with a CSS transition.
+	DefaultMapInterval = 250 * time.Millisecond
+
+	// mapPositionScale rounds map positions to four decimal places, under a metre on the largest circuits.
+	mapPositionScale = 10000
+)
+
+// MapPosition is the position of the vehicle on the map of the circuit being driven.
+type MapPosition struct {
+	// CircuitID is the circuit identified, whose map is drawn with the position.
+	CircuitID string `json:"circuitId"`
+	// X and Y are the position across and down the map, from 0 at the left and top edges to 1 at the right and bottom
+	// edges.
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	// Confidence is how sure the identification of the circuit is, from 0 to 1.
+	Confidence float64 `json:"confidence"`
+}
+
+// MapSource provides the position of the vehicle on the map of the circuit being driven, not found while no circuit
+// is identified.
+type MapSource interface {
+	MapPosition() (MapPosition, bool)
+}
+
+// MapMessage is the JSON message sent to clients of the map channel at every interval. Map is null while no circuit
+// is identified.
+type MapMessage struct {
+	Map *MapPosition `json:"map"`
+}
+
+// NewMapServer creates a server that sends the map channel, the position of the vehicle on the map of the circuit
+// being driven, read from the source. Only the interval and logger of the options are used, and the interval
+// defaults to 250ms.
+func NewMapServer(source MapSource, opts Options) *Server {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultMapInterval
+	}
+
+	message := func() any {
+		position, found := source.MapPosition()
+		if !found {
+			return MapMessage{}
+		}
+
+		position.X = math.Round(position.X*mapPositionScale) / mapPositionScale
+		position.Y = math.Round(position.Y*mapPositionScale) / mapPositionScale
+
+		return MapMessage{Map: &position}
+	}
+
+	return newServer(message, opts.Interval, opts.Logger)
+}
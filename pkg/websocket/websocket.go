@@ -0,0 +1,311 @@
+// Package websocket streams telemetry channels, and the position of the vehicle on the map of the circuit, to
+// browsers and other WebSocket clients as JSON messages.
+//
+// Only the parts of RFC 6455 needed by a server that pushes messages are implemented. Messages from clients other
+// than ping and close frames are ignored.
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultInterval is the interval at which channels are sent when no interval is configured.
+	DefaultInterval = 50 * time.Millisecond
+
+	// clientQueueLength is the number of messages queued for each client before messages are dropped.
+	clientQueueLength = 16
+	// writeTimeout is how long a write to a client may take before the client is disconnected.
+	writeTimeout = 5 * time.Second
+)
+
+var (
+	// ErrNoChannels indicates that a server was configured without any channels.
+	ErrNoChannels = errors.New("no channels configured")
+	// ErrUnknownChannel indicates a channel that is not provided by the channel source.
+	ErrUnknownChannel = errors.New("unknown channel")
+)
+
+// ChannelSource provides the current value of named telemetry channels, as implemented by gttelemetry.Transformer.
+type ChannelSource interface {
+	Channel(name string) (float32, bool)
+}
+
+// Options configures a Server.
+type Options struct {
+	// Channels lists the names of the telemetry channels to send.
+	Channels []string
+	// Interval is how often the channels are sent, defaults to 50ms.
+	Interval time.Duration
+	// Logger is used for server log messages, defaults to a disabled logger.
+	Logger *zerolog.Logger
+}
+
+// Message is the JSON message sent to clients at every interval.
+type Message struct {
+	Channels map[string]float32 `json:"channels"`
+}
+
+// Server sends selected telemetry channels, or the map channel, to every connected WebSocket client.
+type Server struct {
+	// message returns the message sent at every interval
+	message  func() any
+	interval time.Duration
+	log      zerolog.Logger
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+}
+
+type frame struct {
+	opcode  byte
+	payload []byte
+}
+
+type client struct {
+	conn      net.Conn
+	send      chan frame
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewServer creates a server that sends the configured channels read from the source.
+func NewServer(source ChannelSource, opts Options) (*Server, error) {
+	if len(opts.Channels) == 0 {
+		return nil, ErrNoChannels
+	}
+
+	for _, channel := range opts.Channels {
+		if _, ok := source.Channel(channel); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+		}
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	message := func() any {
+		message := Message{Channels: make(map[string]float32, len(opts.Channels))}
+		for _, channel := range opts.Channels {
+			message.Channels[channel], _ = source.Channel(channel)
+		}
+
+		return message
+	}
+
+	return newServer(message, opts.Interval, opts.Logger), nil
+}
+
+// newServer creates a server sending the message at every interval.
+func newServer(message func() any, interval time.Duration, logger *zerolog.Logger) *Server {
+	log := zerolog.Nop()
+	if logger != nil {
+		log = *logger
+	}
+
+	return &Server{
+		message:  message,
+		interval: interval,
+		log:      log,
+		clients:  make(map[*client]struct{}),
+	}
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and sends messages to it until it is closed.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "WebSocket upgrade required", http.StatusUpgradeRequired)
+
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection cannot be upgraded", http.StatusInternalServerError)
+
+		return
+	}
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		s.log.Error().Err(err).Msg("failed to hijack connection")
+
+		return
+	}
+
+	_, err = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err == nil {
+		err = buf.Flush()
+	}
+
+	if err != nil {
+		s.log.Debug().Err(err).Msg("failed to complete WebSocket handshake")
+
+		conn.Close()
+
+		return
+	}
+
+	c := &client{
+		conn: conn,
+		send: make(chan frame, clientQueueLength),
+		done: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	s.log.Debug().Str("remote", conn.RemoteAddr().String()).Msg("WebSocket client connected")
+
+	go s.writeLoop(c)
+
+	s.readLoop(c, buf)
+}
+
+// Broadcast sends a message, encoded as JSON, to every connected client. Clients that are not keeping up miss the
+// message rather than holding up the other clients.
+func (s *Server) Broadcast(message any) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		select {
+		case c.send <- frame{opcode: opText, payload: payload}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Clients returns the number of connected clients.
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients)
+}
+
+// Run sends the channels, or the map channel, to the connected clients at every interval until the context is
+// cancelled, then closes every client connection.
+func (s *Server) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.closeAll()
+
+			return
+		case <-ticker.C:
+			if s.Clients() == 0 {
+				continue
+			}
+
+			err := s.Broadcast(s.message())
+			if err != nil {
+				s.log.Error().Err(err).Msg("failed to broadcast message")
+			}
+		}
+	}
+}
+
+// readLoop handles control frames from the client until the connection is closed.
+func (s *Server) readLoop(c *client, r io.Reader) {
+	defer s.disconnect(c)
+
+	for {
+		opcode, payload, err := readFrame(r, maxControlPayload)
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case opClose:
+			select {
+			case c.send <- frame{opcode: opClose, payload: payload}:
+				// The write loop disconnects once the close frame has been echoed
+				<-c.done
+			default:
+			}
+
+			return
+		case opPing:
+			select {
+			case c.send <- frame{opcode: opPong, payload: payload}:
+			default:
+			}
+		}
+	}
+}
+
+// writeLoop writes queued frames to the client until it is disconnected.
+func (s *Server) writeLoop(c *client) {
+	defer s.disconnect(c)
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case f := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+			err := writeFrame(c.conn, f.opcode, f.payload)
+			if err != nil || f.opcode == opClose {
+				return
+			}
+		}
+	}
+}
+
+// disconnect removes the client and closes its connection.
+func (s *Server) disconnect(c *client) {
+	c.closeOnce.Do(func() {
+		s.mu.Lock()
+		delete(s.clients, c)
+		s.mu.Unlock()
+
+		close(c.done)
+		c.conn.Close()
+
+		s.log.Debug().Str("remote", c.conn.RemoteAddr().String()).Msg("WebSocket client disconnected")
+	})
+}
+
+// closeAll disconnects every client.
+func (s *Server) closeAll() {
+	s.mu.Lock()
+	clients := make([]*client, 0, len(s.clients))
+
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range clients {
+		s.disconnect(c)
+	}
+}
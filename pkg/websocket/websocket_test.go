@@ -0,0 +1,241 @@
+package websocket_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/zetetos/gt-telemetry/v2/pkg/websocket"
+)
+
+// sampleKey and sampleAccept are the handshake example from RFC 6455.
+const (
+	sampleKey    = "dGhlIHNhbXBsZSBub25jZQ=="
+	sampleAccept = "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+)
+
+type fakeSource map[string]float32
+
+func (f fakeSource) Channel(name string) (float32, bool) {
+	value, ok := f[name]
+
+	return value, ok
+}
+
+type fakeMapSource struct {
+	position websocket.MapPosition
+	found    bool
+}
+
+func (f fakeMapSource) MapPosition() (websocket.MapPosition, bool) {
+	return f.position, f.found
+}
+
+type WebSocketTestSuite struct {
+	suite.Suite
+
+	server *websocket.Server
+	http   *httptest.Server
+}
+
+func TestWebSocketTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(WebSocketTestSuite))
+}
+
+func (suite *WebSocketTestSuite) SetupTest() {
+	server, err := websocket.NewServer(fakeSource{"engineRPM": 6500, "currentGear": 3}, websocket.Options{
+		Channels: []string{"engineRPM", "currentGear"},
+		Interval: 10 * time.Millisecond,
+	})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.T().Cleanup(cancel)
+
+	go server.Run(ctx)
+
+	suite.server = server
+	suite.http = httptest.NewServer(server)
+	suite.T().Cleanup(suite.http.Close)
+}
+
+// connect performs the WebSocket handshake and returns the connection and a reader positioned after the response.
+func (suite *WebSocketTestSuite) connect() (net.Conn, *bufio.Reader) {
+	return suite.connectTo(suite.http.URL)
+}
+
+// connectTo performs the WebSocket handshake with the server at the URL.
+func (suite *WebSocketTestSuite) connectTo(url string) (net.Conn, *bufio.Reader) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { _ = conn.Close() })
+	suite.Require().NoError(conn.SetDeadline(time.Now().Add(2 * time.Second)))
+
+	_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: "+sampleKey+"\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	suite.Require().NoError(err)
+
+	reader := bufio.NewReader(conn)
+
+	response, err := http.ReadResponse(reader, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusSwitchingProtocols, response.StatusCode)
+	suite.Require().Equal(sampleAccept, response.Header.Get("Sec-WebSocket-Accept"))
+
+	return conn, reader
+}
+
+// readFrame reads a single unmasked server frame.
+func (suite *WebSocketTestSuite) readFrame(reader *bufio.Reader) (byte, []byte) {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(reader, header)
+	suite.Require().NoError(err)
+
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		extended := make([]byte, 2)
+		_, err = io.ReadFull(reader, extended)
+		suite.Require().NoError(err)
+
+		length = int(binary.BigEndian.Uint16(extended))
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	suite.Require().NoError(err)
+
+	return header[0] & 0x0F, payload
+}
+
+// writeFrame writes a single masked client frame.
+func (suite *WebSocketTestSuite) writeFrame(conn net.Conn, opcode byte, payload []byte) {
+	mask := []byte{1, 2, 3, 4}
+	frame := append([]byte{0x80 | opcode, 0x80 | byte(len(payload))}, mask...)
+
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := conn.Write(frame)
+	suite.Require().NoError(err)
+}
+
+func (suite *WebSocketTestSuite) TestClientReceivesChannels() {
+	// Arrange
+	_, reader := suite.connect()
+
+	// Act
+	opcode, payload := suite.readFrame(reader)
+
+	// Assert
+	suite.Equal(byte(0x1), opcode)
+
+	var message websocket.Message
+	suite.Require().NoError(json.Unmarshal(payload, &message))
+	suite.InDelta(6500, message.Channels["engineRPM"], 0.001)
+	suite.InDelta(3, message.Channels["currentGear"], 0.001)
+}
+
+func (suite *WebSocketTestSuite) TestPingIsAnsweredWithPong() {
+	// Arrange
+	conn, reader := suite.connect()
+
+	// Act
+	suite.writeFrame(conn, 0x9, []byte("hello"))
+
+	// Assert
+	for {
+		opcode, payload := suite.readFrame(reader)
+		if opcode == 0xA {
+			suite.Equal("hello", string(payload))
+
+			return
+		}
+	}
+}
+
+func (suite *WebSocketTestSuite) TestCloseDisconnectsClient() {
+	// Arrange
+	conn, _ := suite.connect()
+	suite.Eventually(func() bool { return suite.server.Clients() == 1 }, time.Second, 5*time.Millisecond)
+
+	// Act
+	suite.writeFrame(conn, 0x8, nil)
+
+	// Assert
+	suite.Eventually(func() bool { return suite.server.Clients() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func (suite *WebSocketTestSuite) TestPlainRequestIsRejected() {
+	// Act
+	response, err := http.Get(suite.http.URL) //nolint:noctx // test request
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusUpgradeRequired, response.StatusCode)
+	suite.NoError(response.Body.Close())
+}
+
+func (suite *WebSocketTestSuite) TestNewServerValidatesChannels() {
+	// Act
+	_, noChannelsErr := websocket.NewServer(fakeSource{}, websocket.Options{})
+	_, unknownErr := websocket.NewServer(fakeSource{}, websocket.Options{Channels: []string{"nope"}})
+
+	// Assert
+	suite.ErrorIs(noChannelsErr, websocket.ErrNoChannels)
+	suite.ErrorIs(unknownErr, websocket.ErrUnknownChannel)
+}
+
+func (suite *WebSocketTestSuite) TestMapChannel() {
+	tests := map[string]struct {
+		source fakeMapSource
+		want   websocket.MapMessage
+	}{
+		"circuit identified": {
+			source: fakeMapSource{
+				position: websocket.MapPosition{CircuitID: "Suzuka", X: 0.123456, Y: 0.987654, Confidence: 0.5},
+				found:    true,
+			},
+			want: websocket.MapMessage{Map: &websocket.MapPosition{CircuitID: "Suzuka", X: 0.1235, Y: 0.9877, Confidence: 0.5}},
+		},
+		"no circuit identified": {
+			source: fakeMapSource{},
+			want:   websocket.MapMessage{},
+		},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			server := websocket.NewMapServer(tc.source, websocket.Options{Interval: 10 * time.Millisecond})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			go server.Run(ctx)
+
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			_, reader := suite.connectTo(httpServer.URL)
+
+			// Act
+			_, payload := suite.readFrame(reader)
+
+			// Assert
+			var message websocket.MapMessage
+			suite.Require().NoError(json.Unmarshal(payload, &message))
+			suite.Equal(tc.want, message)
+		})
+	}
+}
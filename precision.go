@@ -0,0 +1,121 @@
+package gttelemetry
+
+import (
+	"maps"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+const (
+	// DefaultChannelPrecision is the number of decimal places of channels without a precision of their own.
+	DefaultChannelPrecision = 3
+	// MaxChannelPrecision is the largest number of decimal places a channel can be written with, beyond which the
+	// digits of a float32 are noise.
+	MaxChannelPrecision = 9
+)
+
+// ChannelPrecision sets the number of decimal places channels are exported with, keyed by channel name, such as 0 for
+// engineRPM and 1 for groundSpeedKPH. Channels that are not listed are written with DefaultChannelPrecision.
+//
+// Values are always written with a "." decimal separator, never in exponent form and without a sign for zero, so
+// exports are the same on every platform and import into spreadsheets whatever the locale.
+type ChannelPrecision map[string]int
+
+// RoundedChannels reads channels from a source, rounding each value to the precision of its channel.
+type RoundedChannels struct {
+	source    ChannelSource
+	precision ChannelPrecision
+}
+
+// Places returns the number of decimal places a channel is written with.
+func (p ChannelPrecision) Places(name string) int {
+	return p.places(name, DefaultChannelPrecision)
+}
+
+// Round rounds the value of a channel to its number of decimal places, so that it is encoded in JSON with no more
+// digits than that.
+func (p ChannelPrecision) Round(name string, value float32) float32 {
+	return roundDecimal(value, p.Places(name))
+}
+
+// Format formats the value of a channel with its number of decimal places.
+func (p ChannelPrecision) Format(name string, value float32) string {
+	return formatDecimal(float64(value), p.Places(name))
+}
+
+// Channels wraps a channel source so that the values read from it are rounded to the precision of their channel.
+func (p ChannelPrecision) Channels(source ChannelSource) RoundedChannels {
+	return RoundedChannels{source: source, precision: p}
+}
+
+// RoundSnapshot rounds the channels of a frame snapshot to their precision.
+func (p ChannelPrecision) RoundSnapshot(snapshot models.FrameSnapshot) models.FrameSnapshot {
+	channels := maps.Clone(snapshot.Channels)
+	for name, value := range channels {
+		channels[name] = p.Round(name, value)
+	}
+
+	snapshot.Channels = channels
+
+	return snapshot
+}
+
+// places returns the number of decimal places of a channel, or the fallback when the channel has none set.
+func (p ChannelPrecision) places(name string, fallback int) int {
+	if places, ok := p[name]; ok {
+		return min(max(places, 0), MaxChannelPrecision)
+	}
+
+	return fallback
+}
+
+// format formats the value of a channel, with the fallback number of decimal places when the channel has none set.
+func (p ChannelPrecision) format(name string, value float32, fallback int) string {
+	return formatDecimal(float64(value), p.places(name, fallback))
+}
+
+// Channel returns the value of a channel rounded to its precision, or false when the source does not provide it.
+func (r RoundedChannels) Channel(name string) (float32, bool) {
+	value, ok := r.source.Channel(name)
+	if !ok {
+		return 0, false
+	}
+
+	return r.precision.Round(name, value), true
+}
+
+// formatDecimal formats a value with a fixed number of decimal places, independent of the locale and never in
+// exponent form. Negative values that round to zero are written without a sign, and values that are not finite as an
+// empty string, so that each is read as a number or a blank cell by spreadsheets.
+func formatDecimal(value float64, places int) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return ""
+	}
+
+	formatted := strconv.FormatFloat(value, 'f', places, 64)
+	if strings.Trim(formatted, "-0.") == "" {
+		return strings.TrimPrefix(formatted, "-")
+	}
+
+	return formatted
+}
+
+// roundDecimal rounds a value to a number of decimal places, half away from zero. Values that round to zero are
+// returned as positive zero.
+func roundDecimal(value float32, places int) float32 {
+	if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+		return value
+	}
+
+	scale := math.Pow10(places)
+
+	rounded := float32(math.Round(float64(value)*scale) / scale)
+	if rounded == 0 {
+		return 0
+	}
+
+	return rounded
+}
@@ -0,0 +1,89 @@
+package gttelemetry_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type PrecisionTestSuite struct {
+	suite.Suite
+}
+
+func TestPrecisionTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(PrecisionTestSuite))
+}
+
+func (suite *PrecisionTestSuite) TestFormat() {
+	precision := gttelemetry.ChannelPrecision{"engineRPM": 0, "groundSpeedKPH": 1, "heading": 20}
+
+	tests := map[string]struct {
+		channel   string
+		value     float32
+		wantValue string
+	}{
+		"rounded to the channel precision": {channel: "groundSpeedKPH", value: 123.46, wantValue: "123.5"},
+		"no decimal places":                {channel: "engineRPM", value: 6999.6, wantValue: "7000"},
+		"default precision":                {channel: "fuelLevel", value: 12.3456, wantValue: "12.346"},
+		"tiny values without exponent":     {channel: "fuelLevel", value: 1.5e-7, wantValue: "0.000"},
+		"large values without exponent":    {channel: "engineRPM", value: 1.5e9, wantValue: "1500000000"},
+		"negative zero without sign":       {channel: "groundSpeedKPH", value: -0.04, wantValue: "0.0"},
+		"negative values keep their sign":  {channel: "groundSpeedKPH", value: -0.06, wantValue: "-0.1"},
+		"not a number as a blank cell":     {channel: "fuelLevel", value: float32(math.NaN()), wantValue: ""},
+		"precision limited to the maximum": {channel: "heading", value: 0.5, wantValue: "0.500000000"},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Act
+			gotValue := precision.Format(tc.channel, tc.value)
+
+			// Assert
+			suite.Equal(tc.wantValue, gotValue)
+		})
+	}
+}
+
+func (suite *PrecisionTestSuite) TestRoundedValuesEncodeWithoutExtraDigits() {
+	// Arrange
+	precision := gttelemetry.ChannelPrecision{"groundSpeedKPH": 2}
+	snapshot := models.FrameSnapshot{Channels: map[string]float32{
+		"groundSpeedKPH": 123.456789,
+		"velocityX":      -1e-7,
+	}}
+
+	// Act
+	rounded := precision.RoundSnapshot(snapshot)
+	payload, err := json.Marshal(rounded.Channels)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(`{"groundSpeedKPH":123.46,"velocityX":0}`, string(payload))
+	suite.InDelta(123.456789, snapshot.Channels["groundSpeedKPH"], 1e-4)
+}
+
+func (suite *PrecisionTestSuite) TestChannelsAreRounded() {
+	// Arrange
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	transformer := gttelemetry.NewTransformer(inventory)
+	transformer.RawTelemetry.EngineRpm = 4321.7
+
+	source := gttelemetry.ChannelPrecision{"engineRPM": 0}.Channels(transformer)
+
+	// Act
+	gotValue, ok := source.Channel("engineRPM")
+	_, unknown := source.Channel("warpSpeed")
+
+	// Assert
+	suite.True(ok)
+	suite.InDelta(4322, gotValue, 0)
+	suite.False(unknown)
+}
@@ -0,0 +1,161 @@
+package gttelemetry
+
+import (
+	"time"
+)
+
+const (
+	// raceStartStationarySpeed is the ground speed in metres per second below which the vehicle is stationary on
+	// the grid.
+	raceStartStationarySpeed = 0.5
+	// raceStartThrottlePercent is the throttle input that counts as the driver applying the throttle.
+	raceStartThrottlePercent = 10
+	// raceStartRollingSpeed is the ground speed in metres per second at lights out above which the race has a
+	// rolling start.
+	raceStartRollingSpeed = 5
+)
+
+// StartType identifies how a race was started.
+type StartType string
+
+const (
+	StartTypeUnknown  StartType = "unknown"
+	StartTypeStanding StartType = "standing"
+	StartTypeRolling  StartType = "rolling"
+)
+
+// RaceStart describes the start of a race, from lights out to the driver applying the throttle.
+type RaceStart struct {
+	SequenceID       uint32        `json:"sequenceId"`
+	GridPosition     int16         `json:"gridPosition"`
+	SpeedAtLightsOut float32       `json:"speedAtLightsOut"`
+	StartType        StartType     `json:"startType"`
+	ReactionTime     time.Duration `json:"reactionTime"`
+	ThrottleHeld     bool          `json:"throttleHeld"`
+}
+
+// RaceStartDetector detects the pre-race countdown and the start of a race from consecutive frames.
+//
+// Telemetry does not report the start lights, so lights out is inferred from the lap counter, which remains at zero
+// while the vehicle waits on the grid and changes to the first lap when the race starts. The reaction time is
+// measured from lights out to the first frame with the throttle applied. When the throttle is already applied at
+// lights out the reaction time is zero and the start is reported with ThrottleHeld set.
+//
+// A start is classified as a standing start when the vehicle waited stationary on the grid and was still stationary
+// at lights out, and as a rolling start when the vehicle was moving on the grid or crossed the line at speed.
+type RaceStartDetector struct {
+	lastSequence uint32
+	lastLap      int16
+	countdown    bool
+	waiting      bool
+	startType    StartType
+	current      RaceStart
+	starts       []RaceStart
+}
+
+// NewRaceStartDetector creates a new race start detector.
+func NewRaceStartDetector() *RaceStartDetector {
+	return &RaceStartDetector{lastLap: -1}
+}
+
+// Update evaluates the latest telemetry frame and returns the race start once the driver reacts to lights out.
+func (d *RaceStartDetector) Update(t *Transformer) (RaceStart, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == d.lastSequence {
+		return RaceStart{}, false
+	}
+
+	d.lastSequence = sequenceID
+
+	if t.Flags().GamePaused {
+		return RaceStart{}, false
+	}
+
+	if !t.IsOnCircuit() {
+		d.reset()
+
+		return RaceStart{}, false
+	}
+
+	lap := t.CurrentLap()
+	lastLap := d.lastLap
+	d.lastLap = lap
+
+	throttleApplied := t.ThrottleInputPercent() >= raceStartThrottlePercent
+
+	switch {
+	case lap == 0:
+		d.countdown = t.GroundSpeedMetresPerSecond() < raceStartStationarySpeed
+		d.waiting = false
+		d.startType = StartTypeUnknown
+	case lastLap == 0:
+		speed := t.GroundSpeedMetresPerSecond()
+		d.current = RaceStart{
+			SequenceID:       sequenceID,
+			GridPosition:     t.GridPosition(),
+			SpeedAtLightsOut: speed,
+			StartType:        classifyStart(d.countdown, speed),
+		}
+		d.countdown = false
+		d.startType = d.current.StartType
+
+		if throttleApplied {
+			d.current.ThrottleHeld = true
+
+			return d.complete(), true
+		}
+
+		d.waiting = true
+	case d.waiting && throttleApplied:
+		d.current.ReactionTime = framesElapsed(d.current.SequenceID, sequenceID)
+
+		return d.complete(), true
+	}
+
+	return RaceStart{}, false
+}
+
+// InCountdown reports whether the vehicle is stationary on the grid waiting for the race to start.
+func (d *RaceStartDetector) InCountdown() bool {
+	return d.countdown
+}
+
+// StartType returns how the current race was started, or StartTypeUnknown if no start has been detected.
+func (d *RaceStartDetector) StartType() StartType {
+	if d.startType == "" {
+		return StartTypeUnknown
+	}
+
+	return d.startType
+}
+
+// Starts returns all race starts detected so far.
+func (d *RaceStartDetector) Starts() []RaceStart {
+	return d.starts
+}
+
+// classifyStart classifies a start from whether the vehicle was stationary on the grid and its speed at lights out.
+func classifyStart(stationaryOnGrid bool, speed float32) StartType {
+	switch {
+	case speed >= raceStartRollingSpeed, !stationaryOnGrid:
+		return StartTypeRolling
+	case speed < raceStartStationarySpeed:
+		return StartTypeStanding
+	default:
+		return StartTypeUnknown
+	}
+}
+
+func (d *RaceStartDetector) complete() RaceStart {
+	d.waiting = false
+	d.starts = append(d.starts, d.current)
+
+	return d.current
+}
+
+func (d *RaceStartDetector) reset() {
+	d.lastLap = -1
+	d.countdown = false
+	d.waiting = false
+	d.startType = StartTypeUnknown
+}
@@ -0,0 +1,144 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type RaceStartDetectorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	detector    *gttelemetry.RaceStartDetector
+}
+
+func TestRaceStartDetectorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RaceStartDetectorTestSuite))
+}
+
+func (suite *RaceStartDetectorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.RaceLaps = 5
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+	suite.transformer.RawTelemetry.GridPosition = 7
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+
+	suite.detector = gttelemetry.NewRaceStartDetector()
+}
+
+func (suite *RaceStartDetectorTestSuite) frame(lap int16, speed float32, throttle uint8) (gttelemetry.RaceStart, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.CurrentLap = lap
+	suite.transformer.RawTelemetry.GroundSpeed = speed
+	suite.transformer.RawTelemetry.ThrottleInput = throttle
+
+	return suite.detector.Update(suite.transformer)
+}
+
+func (suite *RaceStartDetectorTestSuite) TestCountdownIsDetectedOnGrid() {
+	// Act
+	suite.frame(0, 0, 0)
+
+	// Assert
+	suite.True(suite.detector.InCountdown())
+}
+
+func (suite *RaceStartDetectorTestSuite) TestReactionTimeIsMeasuredFromLightsOut() {
+	// Arrange
+	suite.frame(0, 0, 0)
+	suite.frame(1, 0, 0)
+
+	for range 17 {
+		suite.frame(1, 0, 0)
+	}
+
+	// Act
+	start, ok := suite.frame(1, 0, 255)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.False(suite.detector.InCountdown())
+	suite.Equal(int16(7), start.GridPosition)
+	suite.Equal(300*time.Millisecond, start.ReactionTime)
+	suite.Equal(gttelemetry.StartTypeStanding, start.StartType)
+	suite.False(start.ThrottleHeld)
+	suite.Len(suite.detector.Starts(), 1)
+}
+
+func (suite *RaceStartDetectorTestSuite) TestThrottleHeldAtLightsOutIsReported() {
+	// Arrange
+	suite.frame(0, 0, 200)
+
+	// Act
+	start, ok := suite.frame(1, 0, 200)
+
+	// Assert
+	suite.Require().True(ok)
+	suite.True(start.ThrottleHeld)
+	suite.Zero(start.ReactionTime)
+}
+
+func (suite *RaceStartDetectorTestSuite) TestStartTypeIsClassified() {
+	tests := map[string]struct {
+		gridSpeed      float32
+		lightsOutSpeed float32
+		want           gttelemetry.StartType
+	}{
+		"stationary on grid":      {gridSpeed: 0, lightsOutSpeed: 0, want: gttelemetry.StartTypeStanding},
+		"formation lap":           {gridSpeed: 20, lightsOutSpeed: 30, want: gttelemetry.StartTypeRolling},
+		"moving on grid":          {gridSpeed: 2, lightsOutSpeed: 2, want: gttelemetry.StartTypeRolling},
+		"creeping at lights out":  {gridSpeed: 0, lightsOutSpeed: 2, want: gttelemetry.StartTypeUnknown},
+		"at speed from grid stop": {gridSpeed: 0, lightsOutSpeed: 10, want: gttelemetry.StartTypeRolling},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+			suite.frame(0, test.gridSpeed, 0)
+			suite.Equal(gttelemetry.StartTypeUnknown, suite.detector.StartType())
+
+			// Act
+			start, ok := suite.frame(1, test.lightsOutSpeed, 255)
+
+			// Assert
+			suite.Require().True(ok)
+			suite.Equal(test.want, start.StartType)
+			suite.Equal(test.want, suite.detector.StartType())
+		})
+	}
+}
+
+func (suite *RaceStartDetectorTestSuite) TestJoiningMidRaceDoesNotReportStart() {
+	// Act
+	_, first := suite.frame(3, 50, 255)
+	_, second := suite.frame(4, 50, 255)
+
+	// Assert
+	suite.False(first)
+	suite.False(second)
+	suite.Empty(suite.detector.Starts())
+}
+
+func (suite *RaceStartDetectorTestSuite) TestLeavingCircuitCancelsStart() {
+	// Arrange
+	suite.frame(0, 0, 0)
+	suite.frame(1, 0, 0)
+	suite.transformer.RawTelemetry.RaceEntrants = -1
+	suite.frame(1, 0, 0)
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+
+	// Act
+	_, ok := suite.frame(1, 0, 255)
+
+	// Assert
+	suite.False(ok)
+}
@@ -0,0 +1,128 @@
+package gttelemetry
+
+import (
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// raceTypeSmallField is the largest field, including the player, of time trials, licence tests and missions against a
+// couple of opponents.
+const raceTypeSmallField = 3
+
+// RaceTypeSession is a session on circuit and the race type it was classified as.
+type RaceTypeSession struct {
+	RaceType        models.RaceType `json:"raceType"`
+	StartSequenceID uint32          `json:"startSequenceId"`
+	EndSequenceID   uint32          `json:"endSequenceId"`
+	// Entrants and Laps are the field and race distance of the session, zero laps for sessions without a lap count.
+	Entrants int16 `json:"entrants"`
+	Laps     int16 `json:"laps"`
+	// Completed reports whether the race distance was completed before the session ended.
+	Completed bool `json:"completed"`
+}
+
+// RaceTypeDetector classifies each session on circuit, refining Transformer.RaceType with the transitions between
+// the menus and the circuit that a single frame cannot show. A session driven alone without a lap count, which
+// Transformer.RaceType takes for a time trial, is qualifying when it is followed by a race with a larger field
+// without returning to the main menu, as in championships and lobbies. Sessions over laps with a small field, which
+// Transformer.RaceType leaves unknown, are licence tests when driven alone and missions against a couple of opponents.
+type RaceTypeDetector struct {
+	lastSequence uint32
+	onCircuit    bool
+	current      RaceTypeSession
+	sessions     []RaceTypeSession
+	// qualifying is the index of the last session while it may be the qualifying of the next session
+	qualifying int
+}
+
+// NewRaceTypeDetector creates a detector without any sessions.
+func NewRaceTypeDetector() *RaceTypeDetector {
+	return &RaceTypeDetector{qualifying: -1}
+}
+
+// Update evaluates the latest telemetry frame and returns the race type of the session in progress, unknown in the
+// menus.
+func (d *RaceTypeDetector) Update(t *Transformer) models.RaceType {
+	sequenceID := t.SequenceID()
+	if sequenceID == d.lastSequence {
+		return d.raceType()
+	}
+
+	d.lastSequence = sequenceID
+
+	// Loading screens and pauses do not end a session, the vehicle is only off circuit in the menus
+	gameState := t.GameState()
+	onCircuit := gameState == models.GameStateLive || gameState == models.GameStateReplay
+
+	switch {
+	case onCircuit && !d.onCircuit:
+		d.onCircuit = true
+		d.current = RaceTypeSession{StartSequenceID: sequenceID}
+	case !onCircuit && d.onCircuit && (gameState == models.GameStateMainMenu || gameState == models.GameStateRaceMenu):
+		d.onCircuit = false
+		d.endSession(gameState)
+
+		return models.RaceTypeUnknown
+	case gameState == models.GameStateMainMenu:
+		d.qualifying = -1
+	}
+
+	if !d.onCircuit {
+		return models.RaceTypeUnknown
+	}
+
+	if raceType := sessionRaceType(t); raceType != models.RaceTypeUnknown {
+		d.current.RaceType = raceType
+		d.current.Entrants = t.RaceEntrants()
+		d.current.Laps = t.RaceLaps()
+	}
+
+	d.current.EndSequenceID = sequenceID
+	d.current.Completed = d.current.Completed || t.RaceComplete()
+
+	if d.qualifying >= 0 && (d.current.RaceType == models.RaceTypeSprint || d.current.RaceType == models.RaceTypeEndurance) {
+		d.sessions[d.qualifying].RaceType = models.RaceTypeQualifying
+		d.qualifying = -1
+	}
+
+	return d.current.RaceType
+}
+
+// Sessions returns the sessions that have ended, in order. A time trial may still become qualifying until the next
+// session has started.
+func (d *RaceTypeDetector) Sessions() []RaceTypeSession {
+	return d.sessions
+}
+
+// sessionRaceType returns the race type of the frame, telling licence tests and missions apart from other sessions
+// over laps with a small field.
+func sessionRaceType(t *Transformer) models.RaceType {
+	raceType := t.RaceType()
+	if raceType != models.RaceTypeUnknown || !t.IsOnCircuit() || t.RaceLaps() <= 0 {
+		return raceType
+	}
+
+	if t.RaceEntrants() <= 1 {
+		return models.RaceTypeLicenceTest
+	}
+
+	return models.RaceTypeMission
+}
+
+// raceType returns the race type of the session in progress.
+func (d *RaceTypeDetector) raceType() models.RaceType {
+	if !d.onCircuit {
+		return models.RaceTypeUnknown
+	}
+
+	return d.current.RaceType
+}
+
+// endSession adds the session in progress to the sessions once the vehicle has returned to a menu.
+func (d *RaceTypeDetector) endSession(gameState models.GameState) {
+	d.sessions = append(d.sessions, d.current)
+	d.qualifying = -1
+
+	if d.current.RaceType == models.RaceTypeTimeTrial && gameState == models.GameStateRaceMenu {
+		d.qualifying = len(d.sessions) - 1
+	}
+}
@@ -0,0 +1,181 @@
+package gttelemetry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type RaceTypeDetectorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	detector    *gttelemetry.RaceTypeDetector
+}
+
+func TestRaceTypeDetectorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RaceTypeDetectorTestSuite))
+}
+
+func (suite *RaceTypeDetectorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.detector = gttelemetry.NewRaceTypeDetector()
+}
+
+// session drives a few frames of a session with the field and race distance, completing the race when asked.
+func (suite *RaceTypeDetectorTestSuite) session(entrants, laps int16, complete bool) models.RaceType {
+	var raceType models.RaceType
+
+	suite.transformer.RawTelemetry.RaceEntrants = entrants
+	suite.transformer.RawTelemetry.RaceLaps = laps
+	suite.transformer.RawTelemetry.CurrentLap = 1
+
+	for frame := range 10 {
+		if complete && frame == 9 {
+			suite.transformer.RawTelemetry.CurrentLap = laps + 1
+		}
+
+		suite.transformer.RawTelemetry.SequenceId++
+		raceType = suite.detector.Update(suite.transformer)
+	}
+
+	return raceType
+}
+
+// menu returns to the race menu, or the main menu.
+func (suite *RaceTypeDetectorTestSuite) menu(main bool) {
+	suite.transformer.RawTelemetry.RaceEntrants = -1
+	suite.transformer.RawTelemetry.RaceLaps = 0
+
+	if main {
+		suite.transformer.RawTelemetry.RaceLaps = -1
+	}
+
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.detector.Update(suite.transformer)
+}
+
+// raceTypes returns the race type of each session that has ended.
+func (suite *RaceTypeDetectorTestSuite) raceTypes() []models.RaceType {
+	var raceTypes []models.RaceType
+
+	for _, session := range suite.detector.Sessions() {
+		raceTypes = append(raceTypes, session.RaceType)
+	}
+
+	return raceTypes
+}
+
+func (suite *RaceTypeDetectorTestSuite) TestTimeTrialBeforeRaceIsQualifying() {
+	// Arrange
+	suite.session(1, 0, false)
+	suite.menu(false)
+
+	// Act
+	raceType := suite.session(16, 5, true)
+	suite.menu(false)
+
+	// Assert
+	suite.Equal(models.RaceTypeSprint, raceType)
+	suite.Equal([]models.RaceType{models.RaceTypeQualifying, models.RaceTypeSprint}, suite.raceTypes())
+
+	sessions := suite.detector.Sessions()
+	suite.False(sessions[0].Completed)
+	suite.True(sessions[1].Completed)
+	suite.Equal(int16(16), sessions[1].Entrants)
+	suite.Equal(int16(5), sessions[1].Laps)
+}
+
+func (suite *RaceTypeDetectorTestSuite) TestSessionSequences() {
+	tests := map[string]struct {
+		sessions func()
+		want     []models.RaceType
+	}{
+		"time trial retried": {
+			sessions: func() {
+				suite.session(1, 0, false)
+				suite.menu(false)
+				suite.session(1, 0, false)
+				suite.menu(false)
+			},
+			want: []models.RaceType{models.RaceTypeTimeTrial, models.RaceTypeTimeTrial},
+		},
+		"time trial then race from the main menu": {
+			sessions: func() {
+				suite.session(1, 0, false)
+				suite.menu(false)
+				suite.menu(true)
+				suite.session(16, 5, true)
+				suite.menu(false)
+			},
+			want: []models.RaceType{models.RaceTypeTimeTrial, models.RaceTypeSprint},
+		},
+		"licence test then mission": {
+			sessions: func() {
+				suite.session(1, 1, false)
+				suite.menu(false)
+				suite.session(2, 2, true)
+				suite.menu(false)
+			},
+			want: []models.RaceType{models.RaceTypeLicenceTest, models.RaceTypeMission},
+		},
+		"qualifying for an endurance race": {
+			sessions: func() {
+				suite.session(1, 0, false)
+				suite.menu(false)
+				suite.session(20, 0, false)
+				suite.menu(false)
+			},
+			want: []models.RaceType{models.RaceTypeQualifying, models.RaceTypeEndurance},
+		},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.SetupTest()
+
+			// Act
+			tc.sessions()
+
+			// Assert
+			suite.Equal(tc.want, suite.raceTypes())
+		})
+	}
+}
+
+func (suite *RaceTypeDetectorTestSuite) TestRaceTypeIsUnknownInMenus() {
+	// Arrange
+	suite.session(16, 5, false)
+
+	// Act
+	suite.menu(false)
+	raceType := suite.detector.Update(suite.transformer)
+
+	// Assert
+	suite.Equal(models.RaceTypeUnknown, raceType)
+}
+
+func (suite *RaceTypeDetectorTestSuite) TestDemoRecordingIsInRaceMenu() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	// Act
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+		suite.Equal(models.RaceTypeUnknown, suite.detector.Update(transformer))
+	}
+
+	// Assert
+	suite.Empty(suite.detector.Sessions(), "The demo recording reports no field, as in the race menu")
+}
@@ -0,0 +1,130 @@
+package gttelemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// DefaultRacingLineStep is the distance between the points of a racing line in metres when no step is given.
+	DefaultRacingLineStep = 2
+	// RacingLineFormatVersion is the version of the racing line JSON format written by RacingLine.WriteJSON.
+	RacingLineFormatVersion = 1
+)
+
+// ErrInvalidRacingLine indicates a racing line JSON file that cannot be read.
+var ErrInvalidRacingLine = errors.New("invalid racing line")
+
+// RacingLinePoint is the position, speed and gear at a point on a racing line.
+type RacingLinePoint struct {
+	// Distance is the distance from the start of the lap in metres.
+	Distance float32 `json:"distance"`
+	// X and Z are the position on the circuit map in metres.
+	X        float32 `json:"x"`
+	Z        float32 `json:"z"`
+	SpeedKPH float32 `json:"speedKPH"`
+	Gear     int     `json:"gear"`
+}
+
+// RacingLine is the line driven on a lap, sampled at fixed distance steps, for drawing on a track map and comparing
+// with the line of other laps.
+type RacingLine struct {
+	// Version is the version of the JSON format, RacingLineFormatVersion.
+	Version int `json:"version"`
+	// CircuitID is the circuit the line was driven on, empty when unknown.
+	CircuitID string `json:"circuitId,omitempty"`
+	Lap       int16  `json:"lap"`
+	// Laptime is the lap time in milliseconds in JSON.
+	Laptime time.Duration `json:"-"`
+	// Step is the distance between points in metres.
+	Step float32 `json:"step"`
+	// Length is the distance travelled over the lap in metres.
+	Length float32           `json:"length"`
+	Points []RacingLinePoint `json:"points"`
+}
+
+// racingLineJSON is the JSON form of a racing line, with the lap time in milliseconds.
+type racingLineJSON struct {
+	RacingLine
+
+	LaptimeMilliseconds int64 `json:"laptimeMilliseconds"`
+}
+
+// NewRacingLine samples the line driven on a lap every step metres, defaulting to DefaultRacingLineStep, such as the
+// fastest lap returned by LapTraceRecorder.Fastest. The last point is at the end of the lap.
+func NewRacingLine(trace LapTrace, step float32) (RacingLine, error) {
+	if len(trace.Samples) == 0 {
+		return RacingLine{}, ErrEmptyLapTrace
+	}
+
+	if step <= 0 {
+		step = DefaultRacingLineStep
+	}
+
+	length := trace.Samples[len(trace.Samples)-1].Distance
+
+	line := RacingLine{
+		Version: RacingLineFormatVersion,
+		Lap:     trace.Lap,
+		Laptime: trace.Laptime,
+		Step:    step,
+		Length:  length,
+		Points:  make([]RacingLinePoint, 0, int(length/step)+2),
+	}
+
+	for distance := float32(0); ; distance += step {
+		distance = min(distance, length)
+		sample := sampleAtDistance(trace.Samples, distance)
+
+		line.Points = append(line.Points, RacingLinePoint{
+			Distance: distance,
+			X:        sample.X,
+			Z:        sample.Z,
+			SpeedKPH: sample.SpeedKPH,
+			Gear:     sample.Gear,
+		})
+
+		if distance >= length {
+			break
+		}
+	}
+
+	return line, nil
+}
+
+// WriteJSON writes the racing line in the JSON format documented in the README.
+func (l RacingLine) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(racingLineJSON{
+		RacingLine:          l,
+		LaptimeMilliseconds: l.Laptime.Milliseconds(),
+	})
+	if err != nil {
+		return fmt.Errorf("encode racing line: %w", err)
+	}
+
+	return nil
+}
+
+// ReadRacingLineJSON reads a racing line written by RacingLine.WriteJSON.
+func ReadRacingLineJSON(r io.Reader) (RacingLine, error) {
+	var line racingLineJSON
+
+	err := json.NewDecoder(r).Decode(&line)
+	if err != nil {
+		return RacingLine{}, fmt.Errorf("%w: %w", ErrInvalidRacingLine, err)
+	}
+
+	if line.Version != RacingLineFormatVersion {
+		return RacingLine{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidRacingLine, line.Version)
+	}
+
+	line.Laptime = time.Duration(line.LaptimeMilliseconds) * time.Millisecond
+
+	return line.RacingLine, nil
+}
@@ -0,0 +1,88 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type RacingLineTestSuite struct {
+	suite.Suite
+
+	trace gttelemetry.LapTrace
+}
+
+func TestRacingLineTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RacingLineTestSuite))
+}
+
+func (suite *RacingLineTestSuite) SetupTest() {
+	suite.trace = gttelemetry.LapTrace{
+		Lap:     3,
+		Laptime: 90500 * time.Millisecond,
+		Samples: []gttelemetry.LapTraceSample{
+			{Distance: 0, SpeedKPH: 100, Gear: 3, X: 0, Z: 0},
+			{Distance: 10, SpeedKPH: 120, Gear: 4, X: 10, Z: 0},
+			{Distance: 15, SpeedKPH: 110, Gear: 4, X: 10, Z: 5},
+		},
+	}
+}
+
+func (suite *RacingLineTestSuite) TestPointsAreSampledAtSteps() {
+	// Act
+	line, err := gttelemetry.NewRacingLine(suite.trace, 4)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(gttelemetry.RacingLineFormatVersion, line.Version)
+	suite.InDelta(15, line.Length, 0.001)
+	suite.Require().Len(line.Points, 5)
+	suite.InDelta(4, line.Points[1].X, 0.001)
+	suite.InDelta(108, line.Points[1].SpeedKPH, 0.001)
+	suite.Equal(3, line.Points[1].Gear)
+	suite.InDelta(10, line.Points[3].X, 0.001)
+	suite.InDelta(2, line.Points[3].Z, 0.001)
+	suite.InDelta(15, line.Points[4].Distance, 0.001)
+	suite.InDelta(5, line.Points[4].Z, 0.001)
+}
+
+func (suite *RacingLineTestSuite) TestEmptyTraceIsRejected() {
+	// Act
+	_, err := gttelemetry.NewRacingLine(gttelemetry.LapTrace{}, 0)
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrEmptyLapTrace)
+}
+
+func (suite *RacingLineTestSuite) TestJSONRoundTrip() {
+	// Arrange
+	line, err := gttelemetry.NewRacingLine(suite.trace, 0)
+	suite.Require().NoError(err)
+
+	line.CircuitID = "spa"
+
+	var buffer bytes.Buffer
+
+	suite.Require().NoError(line.WriteJSON(&buffer))
+	suite.Contains(buffer.String(), `"laptimeMilliseconds": 90500`)
+
+	// Act
+	got, err := gttelemetry.ReadRacingLineJSON(&buffer)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(line, got)
+}
+
+func (suite *RacingLineTestSuite) TestReadRejectsUnsupportedVersion() {
+	// Act
+	_, err := gttelemetry.ReadRacingLineJSON(strings.NewReader(`{"version": 2, "points": []}`))
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrInvalidRacingLine)
+}
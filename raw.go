@@ -0,0 +1,49 @@
+package gttelemetry
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/telemetry"
+)
+
+// Raw returns a copy of the decoded frame, for values that have no accessor method. The nested values are copied
+// too, so changes to the returned frame do not affect the transformer.
+func (t *Transformer) Raw() telemetry.GranTurismoTelemetry {
+	raw := t.RawTelemetry
+
+	raw.Header = clonePointer(raw.Header)
+	raw.MapPositionCoordinates = clonePointer(raw.MapPositionCoordinates)
+	raw.VelocityVector = clonePointer(raw.VelocityVector)
+	raw.RotationalEnvelope = clonePointer(raw.RotationalEnvelope)
+	raw.AngularVelocityVector = clonePointer(raw.AngularVelocityVector)
+	raw.TyreTemperature = clonePointer(raw.TyreTemperature)
+	raw.Flags = clonePointer(raw.Flags)
+	raw.TransmissionGear = clonePointer(raw.TransmissionGear)
+	raw.Ignore1 = bytes.Clone(raw.Ignore1)
+	raw.RoadPlaneVector = clonePointer(raw.RoadPlaneVector)
+	raw.WheelRadiansPerSecond = clonePointer(raw.WheelRadiansPerSecond)
+	raw.TyreRadius = clonePointer(raw.TyreRadius)
+	raw.SuspensionHeight = clonePointer(raw.SuspensionHeight)
+	raw.Reserved = bytes.Clone(raw.Reserved)
+	raw.TranslationalEnvelope = clonePointer(raw.TranslationalEnvelope)
+	raw.SurfaceType = clonePointer(raw.SurfaceType)
+
+	raw.TransmissionGearRatio = clonePointer(raw.TransmissionGearRatio)
+	if raw.TransmissionGearRatio != nil {
+		raw.TransmissionGearRatio.Gear = slices.Clone(raw.TransmissionGearRatio.Gear)
+	}
+
+	return raw
+}
+
+// clonePointer returns a pointer to a shallow copy of the value, or nil when the pointer is nil.
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	clone := *value
+
+	return &clone
+}
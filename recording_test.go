@@ -1,8 +1,10 @@
 package gttelemetry_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -168,3 +170,330 @@ func (suite *RecordingTestSuite) TestFileCreation() {
 	_, err = os.Stat(gtrPath)
 	suite.False(os.IsNotExist(err), "GTR file was not created")
 }
+
+func (suite *RecordingTestSuite) TestRecordingTimesRoundTrip() {
+	// Arrange
+	demo, err := os.ReadFile("data/replays/demo.gtz")
+	suite.Require().NoError(err)
+
+	sourcePath := filepath.Join(suite.tmpDir, "source.gtz")
+	suite.Require().NoError(os.WriteFile(sourcePath, demo, 0o600))
+
+	source, err := gttelemetry.New(gttelemetry.Options{Source: "file://" + sourcePath, LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	var firstSequence uint32
+
+	for transformer, err := range source.Scan(context.Background()) {
+		suite.Require().NoError(err)
+		suite.True(transformer.ReceivedAt().IsZero(), "frames of a recording without times are not tagged")
+
+		if firstSequence == 0 {
+			firstSequence = transformer.SequenceID()
+		}
+	}
+
+	start := time.Date(2026, 5, 17, 10, 30, 0, 0, time.UTC)
+	times := "sequenceId,receivedAt\n" + strconv.FormatUint(uint64(firstSequence), 10) + "," + start.Format(time.RFC3339Nano) + "\n"
+	suite.Require().NoError(os.WriteFile(sourcePath+gttelemetry.RecordingTimesSuffix, []byte(times), 0o600))
+
+	recordingDir := filepath.Join(suite.tmpDir, "recordings")
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:       "file://" + sourcePath,
+		LogLevel:     "error",
+		TimeSource:   &fakeClock{},
+		RecordingDir: recordingDir,
+	})
+	suite.Require().NoError(err)
+
+	want := map[uint32]time.Time{}
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		want[transformer.SequenceID()] = transformer.ReceivedAt()
+	}
+
+	suite.Require().NoError(client.StopRecording())
+
+	recordings, err := filepath.Glob(filepath.Join(recordingDir, "*.gtz"))
+	suite.Require().NoError(err)
+	suite.Require().Len(recordings, 1)
+	suite.FileExists(recordings[0] + gttelemetry.RecordingTimesSuffix)
+
+	copied, err := gttelemetry.New(gttelemetry.Options{Source: "file://" + recordings[0], LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	// Act
+	got := map[uint32]time.Time{}
+
+	for transformer, err := range copied.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		got[transformer.SequenceID()] = transformer.ReceivedAt()
+	}
+
+	// Assert
+	suite.Require().NotEmpty(got)
+	suite.Equal(start, want[firstSequence])
+	suite.Equal(start.Add(time.Second), want[firstSequence+gttelemetry.FrameRate])
+
+	for sequenceID, receivedAt := range got {
+		suite.True(want[sequenceID].Equal(receivedAt), "frame %d", sequenceID)
+	}
+}
+
+// recordedSequenceIDs returns the sequence IDs of the frames in a recording.
+func (suite *RecordingTestSuite) recordedSequenceIDs(path string) []uint32 {
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://" + path, LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	var sequenceIDs []uint32
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		sequenceIDs = append(sequenceIDs, transformer.SequenceID())
+	}
+
+	return sequenceIDs
+}
+
+func (suite *RecordingTestSuite) TestRecordingSplitInOnFrameStartsWithTheFrame() {
+	// Arrange
+	firstPath := filepath.Join(suite.tmpDir, "first.gtz")
+	secondPath := filepath.Join(suite.tmpDir, "second.gtz")
+	frames := 0
+
+	var (
+		client     *gttelemetry.Client
+		splitFrame uint32
+	)
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://data/replays/demo.gtz",
+		LogLevel: "error",
+		OnFrame: func(transformer *gttelemetry.Transformer) {
+			switch frames {
+			case 0:
+				suite.Require().NoError(client.StartRecording(firstPath))
+			case 100:
+				splitFrame = transformer.SequenceID()
+
+				suite.Require().NoError(client.StopRecording())
+				suite.Require().NoError(client.StartRecording(secondPath))
+			}
+
+			frames++
+		},
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	suite.Require().NoError(client.StopRecording())
+
+	// Assert
+	first := suite.recordedSequenceIDs(firstPath)
+	second := suite.recordedSequenceIDs(secondPath)
+
+	suite.Require().NotEmpty(first)
+	suite.Require().NotEmpty(second)
+	suite.NotContains(first, splitFrame)
+	suite.Equal(splitFrame, second[0], "the frame the recording was split on starts the new recording")
+	suite.Less(first[len(first)-1], splitFrame)
+}
+
+func (suite *RecordingTestSuite) TestEncryptedRecordingRoundTrip() {
+	// Arrange
+	recordingDir := filepath.Join(suite.tmpDir, "recordings")
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:            "file://data/replays/demo.gtz",
+		LogLevel:          "error",
+		RecordingDir:      recordingDir,
+		RecordingPassword: "league secret",
+	})
+	suite.Require().NoError(err)
+
+	var want []uint32
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		want = append(want, transformer.SequenceID())
+	}
+
+	suite.Require().NoError(client.StopRecording())
+
+	recordings, err := filepath.Glob(filepath.Join(recordingDir, "*.gtz"))
+	suite.Require().NoError(err)
+	suite.Require().Len(recordings, 1)
+
+	scan := func(password string) ([]uint32, error) {
+		reader, err := gttelemetry.New(gttelemetry.Options{
+			Source:            "file://" + recordings[0],
+			LogLevel:          "error",
+			RecordingPassword: password,
+		})
+		suite.Require().NoError(err)
+
+		var got []uint32
+
+		for transformer, err := range reader.Scan(context.Background()) {
+			if err != nil {
+				return got, err
+			}
+
+			got = append(got, transformer.SequenceID())
+		}
+
+		return got, nil
+	}
+
+	// Act
+	got, err := scan("league secret")
+	_, noPasswordErr := scan("")
+	_, wrongPasswordErr := scan("wrong")
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.NotEmpty(got)
+	suite.Equal(want, got)
+	suite.ErrorIs(noPasswordErr, gttelemetry.ErrRecordingEncrypted)
+	suite.ErrorIs(wrongPasswordErr, gttelemetry.ErrRecordingDecryption)
+}
+
+func (suite *RecordingTestSuite) TestPlainRecordingCannotBeEncrypted() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:            "file://data/replays/demo.gtz",
+		LogLevel:          "error",
+		RecordingPassword: "league secret",
+	})
+	suite.Require().NoError(err)
+
+	path := filepath.Join(suite.tmpDir, "plain.gtr")
+
+	// Act
+	err = client.StartRecording(path)
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrRecordingNotEncryptable)
+	suite.NoFileExists(path)
+}
+
+func (suite *RecordingTestSuite) TestMarkersAddedWhileRecordingAppearInReplay() {
+	// Arrange
+	recordingDir := filepath.Join(suite.tmpDir, "recordings")
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:       "file://data/replays/demo.gtz",
+		LogLevel:     "error",
+		RecordingDir: recordingDir,
+	})
+	suite.Require().NoError(err)
+
+	_, err = client.AddMarker("before recording")
+	suite.Require().ErrorIs(err, gttelemetry.ErrNoRecordingInProgress)
+
+	frames := 0
+
+	var marked uint32
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		frames++
+		if frames == 100 {
+			marker, err := client.AddMarker("  spin at T1 ")
+			suite.Require().NoError(err)
+
+			marked = transformer.SequenceID()
+			suite.Equal("spin at T1", marker.Label)
+		}
+	}
+
+	suite.Require().NoError(client.StopRecording())
+
+	recordings, err := filepath.Glob(filepath.Join(recordingDir, "*.gtz"))
+	suite.Require().NoError(err)
+	suite.Require().Len(recordings, 1)
+
+	suite.Require().NoError(gttelemetry.AddRecordingMarker(recordings[0], gttelemetry.Marker{SequenceID: 1, Label: "missing frame"}))
+
+	recorded, err := gttelemetry.New(gttelemetry.Options{Source: "file://" + recordings[0], LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	// Act
+	replay, err := recorded.OpenReplay()
+	suite.Require().NoError(err)
+
+	markers := replay.Markers()
+
+	// Assert
+	suite.Require().Len(markers, 2)
+	suite.Equal("spin at T1", markers[0].Label)
+	suite.Equal(marked, markers[0].SequenceID)
+	suite.GreaterOrEqual(markers[0].Frame, 0)
+	suite.Equal("missing frame", markers[1].Label)
+	suite.Equal(-1, markers[1].Frame)
+
+	transformer, err := replay.Seek(markers[0].Frame)
+	suite.Require().NoError(err)
+	suite.Equal(marked, transformer.SequenceID())
+}
+
+func (suite *RecordingTestSuite) TestInvalidMarkers() {
+	// Arrange
+	path := filepath.Join(suite.tmpDir, "session.gtz")
+	suite.Require().NoError(os.WriteFile(path+gttelemetry.RecordingMarkersSuffix, []byte("sequenceId,label\nabc,spin\n"), 0o600))
+
+	// Act
+	_, readErr := gttelemetry.ReadRecordingMarkers(path)
+	addErr := gttelemetry.AddRecordingMarker(path, gttelemetry.Marker{SequenceID: 1, Label: "  "})
+
+	// Assert
+	suite.Require().ErrorIs(readErr, gttelemetry.ErrInvalidRecordingMarkers)
+	suite.Require().ErrorIs(addErr, gttelemetry.ErrEmptyMarkerLabel)
+}
+
+func (suite *RecordingTestSuite) TestRecordingGapsRoundTrip() {
+	// Arrange
+	path := filepath.Join(suite.tmpDir, "session.gtz")
+	want := []gttelemetry.Gap{
+		{SequenceID: 100, EndSequenceID: 700, Reason: gttelemetry.GapPaused, Duration: 10 * time.Second},
+		{SequenceID: 900, EndSequenceID: 960, Reason: gttelemetry.GapPacketLoss, Duration: time.Second},
+	}
+
+	none, err := gttelemetry.ReadRecordingGaps(path)
+	suite.Require().NoError(err)
+	suite.Nil(none)
+
+	// Act
+	for _, gap := range want {
+		suite.Require().NoError(gttelemetry.AddRecordingGap(path, gap))
+	}
+
+	got, err := gttelemetry.ReadRecordingGaps(path)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.Equal(want, got)
+}
+
+func (suite *RecordingTestSuite) TestInvalidGaps() {
+	// Arrange
+	path := filepath.Join(suite.tmpDir, "session.gtz")
+	suite.Require().NoError(os.WriteFile(path+gttelemetry.RecordingGapsSuffix, []byte("sequenceId,endSequenceId,reason\n1,abc,paused\n"), 0o600))
+
+	// Act
+	_, err := gttelemetry.ReadRecordingGaps(path)
+
+	// Assert
+	suite.Require().ErrorIs(err, gttelemetry.ErrInvalidRecordingGaps)
+}
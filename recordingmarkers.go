@@ -0,0 +1,116 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RecordingMarkersSuffix is appended to the path of a recording to name the file holding its markers, e.g.
+// session.gtz.markers. The file is plain CSV so that markers can be shared and reviewed alongside the recording.
+const RecordingMarkersSuffix = ".markers"
+
+var (
+	// ErrInvalidRecordingMarkers indicates a recording markers file that cannot be read.
+	ErrInvalidRecordingMarkers = errors.New("invalid recording markers")
+	// ErrEmptyMarkerLabel indicates a marker without a label.
+	ErrEmptyMarkerLabel = errors.New("marker label is empty")
+)
+
+// Marker is a named frame of a recording, such as "spin at T1" or "weird flag value", for finding it again later.
+type Marker struct {
+	SequenceID uint32 `json:"sequenceId"`
+	Label      string `json:"label"`
+}
+
+// ReadRecordingMarkers reads the markers of a recording in the order they were added, returning nil when the
+// recording has none.
+func ReadRecordingMarkers(recordingPath string) ([]Marker, error) {
+	file, err := os.Open(recordingPath + RecordingMarkersSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("open recording markers: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRecordingMarkers, err)
+	}
+
+	markers := make([]Marker, 0, max(len(records)-1, 0))
+
+	for row, record := range records[min(1, len(records)):] {
+		sequenceID, err := strconv.ParseUint(record[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %w", ErrInvalidRecordingMarkers, row+2, err)
+		}
+
+		markers = append(markers, Marker{SequenceID: uint32(sequenceID), Label: record[1]})
+	}
+
+	return markers, nil
+}
+
+// AddRecordingMarker appends a marker to the markers file of a recording, creating the file if needed.
+func AddRecordingMarker(recordingPath string, marker Marker) error {
+	marker.Label = strings.TrimSpace(marker.Label)
+	if marker.Label == "" {
+		return ErrEmptyMarkerLabel
+	}
+
+	path := recordingPath + RecordingMarkersSuffix
+
+	_, err := os.Stat(path)
+	newFile := errors.Is(err, os.ErrNotExist)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open recording markers: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+
+	if newFile {
+		_ = writer.Write([]string{"sequenceId", "label"})
+	}
+
+	_ = writer.Write([]string{strconv.FormatUint(uint64(marker.SequenceID), 10), marker.Label})
+	writer.Flush()
+
+	err = writer.Error()
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("write recording markers: %w", err)
+	}
+
+	return file.Close()
+}
+
+// AddMarker marks the current frame of the recording in progress with a label, e.g. from a hotkey pressed when
+// something unexpected happens on track.
+func (c *Client) AddMarker(label string) (Marker, error) {
+	c.recordingMutex.RLock()
+	defer c.recordingMutex.RUnlock()
+
+	if !c.isRecording {
+		return Marker{}, ErrNoRecordingInProgress
+	}
+
+	marker := Marker{SequenceID: c.Telemetry.SequenceID(), Label: strings.TrimSpace(label)}
+
+	err := AddRecordingMarker(c.recordingPath, marker)
+	if err != nil {
+		return Marker{}, err
+	}
+
+	return marker, nil
+}
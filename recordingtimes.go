@@ -0,0 +1,146 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RecordingTimesSuffix is appended to the path of a recording to name the file holding the times its frames were
+// received, e.g. session.gtz.times. The file is written alongside recordings when the client has a TimeSource.
+const RecordingTimesSuffix = ".times"
+
+// recordingTimesInterval is the number of frames between rows of a recording times file. The time of the frames in
+// between is counted on from the previous row.
+const recordingTimesInterval = FrameRate
+
+// ErrInvalidRecordingTimes indicates a recording times file that cannot be read.
+var ErrInvalidRecordingTimes = errors.New("invalid recording times")
+
+// recordingTime is the time a frame of a recording was received.
+type recordingTime struct {
+	sequenceID uint32
+	receivedAt time.Time
+}
+
+// recordingTimesWriter writes the times frames were received as CSV, with a row for the first frame, every
+// recordingTimesInterval frames and whenever the sequence ID jumps backwards.
+type recordingTimesWriter struct {
+	file         io.WriteCloser
+	writer       *csv.Writer
+	started      bool
+	lastSequence uint32
+}
+
+func newRecordingTimesWriter(path string) (*recordingTimesWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create recording times file: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+
+	err = writer.Write([]string{"sequenceId", "receivedAt"})
+	if err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf("write recording times header: %w", err)
+	}
+
+	return &recordingTimesWriter{file: file, writer: writer}, nil
+}
+
+func (w *recordingTimesWriter) write(sequenceID uint32, receivedAt time.Time) error {
+	if w.started && sequenceID >= w.lastSequence && sequenceID-w.lastSequence < recordingTimesInterval {
+		return nil
+	}
+
+	w.started = true
+	w.lastSequence = sequenceID
+
+	err := w.writer.Write([]string{
+		strconv.FormatUint(uint64(sequenceID), 10),
+		receivedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("write recording times: %w", err)
+	}
+
+	return nil
+}
+
+func (w *recordingTimesWriter) Close() error {
+	w.writer.Flush()
+
+	err := w.writer.Error()
+	if err != nil {
+		w.file.Close()
+
+		return fmt.Errorf("flush recording times: %w", err)
+	}
+
+	return w.file.Close()
+}
+
+// recordingTimes replays the times read from a recording times file while the recording is scanned.
+type recordingTimes struct {
+	rows    []recordingTime
+	next    int
+	current *recordingTime
+}
+
+// readRecordingTimes reads the times file of a recording, returning nil when the recording has none.
+func readRecordingTimes(recordingPath string) (*recordingTimes, error) {
+	file, err := os.Open(recordingPath + RecordingTimesSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("open recording times: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRecordingTimes, err)
+	}
+
+	times := &recordingTimes{rows: make([]recordingTime, 0, max(len(records)-1, 0))}
+
+	for row, record := range records[min(1, len(records)):] {
+		sequenceID, err := strconv.ParseUint(record[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %w", ErrInvalidRecordingTimes, row+2, err)
+		}
+
+		receivedAt, err := time.Parse(time.RFC3339Nano, record[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %w", ErrInvalidRecordingTimes, row+2, err)
+		}
+
+		times.rows = append(times.rows, recordingTime{sequenceID: uint32(sequenceID), receivedAt: receivedAt})
+	}
+
+	return times, nil
+}
+
+// at returns the time the frame with the sequence ID was received. Frames must be passed in the order they were
+// recorded.
+func (r *recordingTimes) at(sequenceID uint32) time.Time {
+	if r.next < len(r.rows) && r.rows[r.next].sequenceID == sequenceID {
+		r.current = &r.rows[r.next]
+		r.next++
+	}
+
+	if r.current == nil {
+		return time.Time{}
+	}
+
+	return r.current.receivedAt.Add(framesElapsed(r.current.sequenceID, sequenceID))
+}
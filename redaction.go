@@ -0,0 +1,70 @@
+package gttelemetry
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// ChannelPolicy selects the channels an exporter may publish, such as to keep the fuel strategy off a public stream
+// overlay. Channels in Block are always hidden, and when Allow is set only the channels it lists are published.
+type ChannelPolicy struct {
+	Allow []string `yaml:"allow"`
+	Block []string `yaml:"block"`
+}
+
+// ChannelSource provides the current value of named telemetry channels, as implemented by Transformer and
+// FilteredChannels.
+type ChannelSource interface {
+	Channel(name string) (float32, bool)
+}
+
+// RedactedChannels reads channels from a source, reporting the channels hidden by a policy as unknown.
+type RedactedChannels struct {
+	source ChannelSource
+	policy ChannelPolicy
+}
+
+// Allowed reports whether the policy allows a channel to be published.
+func (p ChannelPolicy) Allowed(name string) bool {
+	if slices.Contains(p.Block, name) {
+		return false
+	}
+
+	return len(p.Allow) == 0 || slices.Contains(p.Allow, name)
+}
+
+// Filter returns the channels of a list that the policy allows, in the same order.
+func (p ChannelPolicy) Filter(names []string) []string {
+	return slices.DeleteFunc(slices.Clone(names), func(name string) bool { return !p.Allowed(name) })
+}
+
+// Redacted returns the channels of a list that the policy hides, in the same order.
+func (p ChannelPolicy) Redacted(names []string) []string {
+	return slices.DeleteFunc(slices.Clone(names), p.Allowed)
+}
+
+// Channels wraps a channel source so that channels hidden by the policy cannot be read from it.
+func (p ChannelPolicy) Channels(source ChannelSource) RedactedChannels {
+	return RedactedChannels{source: source, policy: p}
+}
+
+// RedactSnapshot removes the channels hidden by the policy from a frame snapshot. The other fields of the snapshot,
+// such as the lap times and position, are kept.
+func (p ChannelPolicy) RedactSnapshot(snapshot models.FrameSnapshot) models.FrameSnapshot {
+	channels := maps.Clone(snapshot.Channels)
+	maps.DeleteFunc(channels, func(name string, _ float32) bool { return !p.Allowed(name) })
+	snapshot.Channels = channels
+
+	return snapshot
+}
+
+// Channel returns the value of a channel, or false when the channel is hidden or unknown.
+func (r RedactedChannels) Channel(name string) (float32, bool) {
+	if !r.policy.Allowed(name) {
+		return 0, false
+	}
+
+	return r.source.Channel(name)
+}
@@ -0,0 +1,85 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type RedactionTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestRedactionTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RedactionTestSuite))
+}
+
+func (suite *RedactionTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.FuelLevel = 42
+	suite.transformer.RawTelemetry.EngineRpm = 6500
+}
+
+func (suite *RedactionTestSuite) TestAllowed() {
+	tests := map[string]struct {
+		policy gttelemetry.ChannelPolicy
+		want   bool
+	}{
+		"empty policy":       {policy: gttelemetry.ChannelPolicy{}, want: true},
+		"blocked":            {policy: gttelemetry.ChannelPolicy{Block: []string{"fuelLevel"}}, want: false},
+		"allowed":            {policy: gttelemetry.ChannelPolicy{Allow: []string{"fuelLevel"}}, want: true},
+		"not allowed":        {policy: gttelemetry.ChannelPolicy{Allow: []string{"engineRPM"}}, want: false},
+		"block beats allow":  {policy: gttelemetry.ChannelPolicy{Allow: []string{"fuelLevel"}, Block: []string{"fuelLevel"}}, want: false},
+		"other channel held": {policy: gttelemetry.ChannelPolicy{Block: []string{"engineRPM"}}, want: true},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got := test.policy.Allowed("fuelLevel")
+
+			// Assert
+			suite.Equal(test.want, got)
+		})
+	}
+}
+
+func (suite *RedactionTestSuite) TestChannelsHidesBlockedChannels() {
+	// Arrange
+	policy := gttelemetry.ChannelPolicy{Block: []string{"fuelLevel"}}
+
+	// Act
+	source := policy.Channels(suite.transformer.FilteredChannels())
+	fuel, fuelFound := source.Channel("fuelLevel")
+	rpm, rpmFound := source.Channel("engineRPM")
+
+	// Assert
+	suite.False(fuelFound)
+	suite.Zero(fuel)
+	suite.True(rpmFound)
+	suite.InDelta(6500, rpm, 1e-3)
+}
+
+func (suite *RedactionTestSuite) TestRedactSnapshotKeepsOtherFields() {
+	// Arrange
+	policy := gttelemetry.ChannelPolicy{Block: []string{"fuelLevel", "fuelLevelPercent"}}
+	snapshot := suite.transformer.Snapshot()
+
+	// Act
+	redacted := policy.RedactSnapshot(snapshot)
+
+	// Assert
+	suite.NotContains(redacted.Channels, "fuelLevel")
+	suite.NotContains(redacted.Channels, "fuelLevelPercent")
+	suite.Contains(redacted.Channels, "engineRPM")
+	suite.Contains(snapshot.Channels, "fuelLevel", "The original snapshot should be unchanged")
+	suite.Equal(snapshot.SequenceID, redacted.SequenceID)
+}
@@ -0,0 +1,240 @@
+package gttelemetry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
+	"github.com/zetetos/gt-telemetry/v2/internal/telemetry"
+)
+
+// ErrFrameOutOfRange indicates a seek to a frame that is not in the replay.
+var ErrFrameOutOfRange = errors.New("frame out of range")
+
+// ReplayMarker is a marker of a recording with the index of the frame it marks in the replay, or -1 when the
+// recording has no frame with the sequence ID of the marker.
+type ReplayMarker struct {
+	Marker
+
+	Frame int `json:"frame"`
+}
+
+// Replay holds every frame of a recording in memory so that it can be read in any order, such as when stepping back
+// and forth through a recording to investigate a parser issue in a specific frame.
+type Replay struct {
+	client      *Client
+	path        string
+	raw         *telemetry.GranTurismoTelemetry
+	packets     [][]byte
+	sequenceIDs []uint32
+	elapsed     []time.Duration
+	receivedAt  []time.Time
+	markers     []ReplayMarker
+	gaps        []Gap
+	position    int
+}
+
+// OpenReplay reads every frame of the file source of the client into memory, along with the markers and gaps of the
+// recording. Frames that cannot be parsed are skipped. The frames are decoded into the Telemetry field of the client
+// as they are seeked to, without recording them or updating the client statistics.
+func (c *Client) OpenReplay() (*Replay, error) {
+	telemetryReader, err := c.openFileReader()
+	if err != nil {
+		return nil, err
+	}
+
+	defer telemetryReader.Close()
+
+	sourceURL, err := url.Parse(c.source)
+	if err != nil {
+		return nil, fmt.Errorf("parse source URL: %w", err)
+	}
+
+	receivedAt, err := c.openRecordingTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	lastSequence := uint32(0)
+	elapsed := time.Duration(0)
+
+	replay := &Replay{
+		client:   c,
+		path:     sourceURL.Host + sourceURL.Path,
+		raw:      telemetry.NewGranTurismoTelemetry(),
+		position: -1,
+	}
+
+	for {
+		bufLen, buffer, err := telemetryReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("read replay: %w", err)
+		}
+
+		if bufLen == 0 {
+			continue
+		}
+
+		packet := bytes.Clone(buffer[:bufLen])
+
+		err = replay.raw.Read(kaitai.NewStream(bytes.NewReader(packet)), nil, nil)
+		if err != nil {
+			c.log.Debug().Err(err).Int("frame", len(replay.packets)).Msg("skipping frame that cannot be parsed")
+
+			continue
+		}
+
+		if len(replay.packets) > 0 {
+			elapsed += framesElapsed(lastSequence, replay.raw.SequenceId)
+		}
+
+		lastSequence = replay.raw.SequenceId
+
+		replay.packets = append(replay.packets, packet)
+		replay.sequenceIDs = append(replay.sequenceIDs, replay.raw.SequenceId)
+		replay.elapsed = append(replay.elapsed, elapsed)
+		replay.receivedAt = append(replay.receivedAt, receivedAt(replay.raw.SequenceId))
+	}
+
+	markers, err := ReadRecordingMarkers(replay.path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, marker := range markers {
+		replay.addMarker(marker)
+	}
+
+	replay.gaps, err = ReadRecordingGaps(replay.path)
+	if err != nil {
+		return nil, err
+	}
+
+	return replay, nil
+}
+
+// Len returns the number of frames in the replay.
+func (r *Replay) Len() int {
+	return len(r.packets)
+}
+
+// Position returns the index of the frame last seeked to, or -1 before the first seek.
+func (r *Replay) Position() int {
+	return r.position
+}
+
+// Seek decodes the frame at the index into the telemetry of the client.
+func (r *Replay) Seek(frame int) (*Transformer, error) {
+	if frame < 0 || frame >= len(r.packets) {
+		return nil, fmt.Errorf("%w: %d of %d", ErrFrameOutOfRange, frame, len(r.packets))
+	}
+
+	err := r.raw.Read(kaitai.NewStream(bytes.NewReader(r.packets[frame])), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse frame %d: %w", frame, err)
+	}
+
+	c := r.client
+	c.DecipheredPacket = r.packets[frame]
+	c.Telemetry.setFrame(r.raw)
+	c.Telemetry.receivedAt = r.receivedAt[frame]
+	c.Telemetry.decodedAt = time.Now()
+	c.Telemetry.UpdateVehicle()
+	c.Telemetry.UpdateFilters()
+	r.position = frame
+
+	return c.Telemetry, nil
+}
+
+// Step seeks forwards or backwards by a number of frames from the current position, stopping at the first and last
+// frames.
+func (r *Replay) Step(frames int) (*Transformer, error) {
+	if len(r.packets) == 0 {
+		return nil, fmt.Errorf("%w: empty replay", ErrFrameOutOfRange)
+	}
+
+	return r.Seek(min(max(r.position+frames, 0), len(r.packets)-1))
+}
+
+// Elapsed returns the time from the first frame of the replay to the frame at the index, counted from the sequence
+// IDs of the frames. Time does not advance where the sequence ID jumps backwards, such as when a replay restarts.
+func (r *Replay) Elapsed(frame int) time.Duration {
+	if frame < 0 || frame >= len(r.packets) {
+		return 0
+	}
+
+	return r.elapsed[frame]
+}
+
+// Duration returns the time from the first to the last frame of the replay.
+func (r *Replay) Duration() time.Duration {
+	return r.Elapsed(len(r.packets) - 1)
+}
+
+// Packet returns the deciphered packet of the frame at the index, which must not be modified.
+func (r *Replay) Packet(frame int) []byte {
+	if frame < 0 || frame >= len(r.packets) {
+		return nil
+	}
+
+	return r.packets[frame]
+}
+
+// Markers returns the markers of the recording in frame order, followed by any markers without a frame.
+func (r *Replay) Markers() []ReplayMarker {
+	return r.markers
+}
+
+// Gaps returns the pauses and packet losses recorded in the recording, in the order they occurred.
+func (r *Replay) Gaps() []Gap {
+	return r.gaps
+}
+
+// AddMarker marks the current frame with a label, saving the marker to the markers file of the recording.
+func (r *Replay) AddMarker(label string) (ReplayMarker, error) {
+	if r.position < 0 {
+		return ReplayMarker{}, fmt.Errorf("%w: no frame has been seeked to", ErrFrameOutOfRange)
+	}
+
+	marker := Marker{SequenceID: r.sequenceIDs[r.position], Label: label}
+
+	err := AddRecordingMarker(r.path, marker)
+	if err != nil {
+		return ReplayMarker{}, err
+	}
+
+	return r.addMarker(marker), nil
+}
+
+// addMarker finds the first frame with the sequence ID of the marker and adds the marker in frame order.
+func (r *Replay) addMarker(marker Marker) ReplayMarker {
+	replayMarker := ReplayMarker{Marker: marker, Frame: slices.Index(r.sequenceIDs, marker.SequenceID)}
+	replayMarker.Label = strings.TrimSpace(replayMarker.Label)
+
+	// markers without a frame go last, and markers on the same frame stay in the order they were added
+	order := func(frame int) int {
+		if frame < 0 {
+			return math.MaxInt
+		}
+
+		return frame
+	}
+
+	index := len(r.markers)
+	for index > 0 && order(r.markers[index-1].Frame) > order(replayMarker.Frame) {
+		index--
+	}
+
+	r.markers = slices.Insert(r.markers, index, replayMarker)
+
+	return replayMarker
+}
@@ -0,0 +1,192 @@
+package gttelemetry_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type ReplayTestSuite struct {
+	suite.Suite
+
+	replay *gttelemetry.Replay
+}
+
+func TestReplayTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ReplayTestSuite))
+}
+
+func (suite *ReplayTestSuite) SetupTest() {
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	suite.replay, err = client.OpenReplay()
+	suite.Require().NoError(err)
+}
+
+// scannedSequenceIDs returns the sequence ID of every frame of the demo replay read in order.
+func (suite *ReplayTestSuite) scannedSequenceIDs() []uint32 {
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "file://data/replays/demo.gtz", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	var sequenceIDs []uint32
+
+	for transformer, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+
+		sequenceIDs = append(sequenceIDs, transformer.SequenceID())
+	}
+
+	return sequenceIDs
+}
+
+func (suite *ReplayTestSuite) TestSeekMatchesScan() {
+	// Arrange
+	scanned := suite.scannedSequenceIDs()
+	suite.Require().Len(scanned, suite.replay.Len())
+
+	middle := suite.replay.Len() / 2
+
+	// Act
+	last, err := suite.replay.Seek(suite.replay.Len() - 1)
+	suite.Require().NoError(err)
+
+	lastSequence := last.SequenceID()
+
+	first, err := suite.replay.Seek(0)
+	suite.Require().NoError(err)
+
+	firstSequence := first.SequenceID()
+
+	seeked, err := suite.replay.Seek(middle)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(scanned[len(scanned)-1], lastSequence)
+	suite.Equal(scanned[0], firstSequence)
+	suite.Equal(scanned[middle], seeked.SequenceID())
+	suite.Equal(middle, suite.replay.Position())
+	suite.NotEmpty(suite.replay.Packet(middle))
+}
+
+func (suite *ReplayTestSuite) TestOnFrameIsCalledForEveryFrame() {
+	// Arrange
+	var sequenceIDs []uint32
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://data/replays/demo.gtz",
+		LogLevel: "error",
+		OnFrame: func(transformer *gttelemetry.Transformer) {
+			sequenceIDs = append(sequenceIDs, transformer.SequenceID())
+		},
+	})
+	suite.Require().NoError(err)
+
+	// Act
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	// Assert
+	suite.Equal(suite.scannedSequenceIDs(), sequenceIDs)
+}
+
+// TestClonesCanBeReadWhileStreaming reads clones of every frame on other goroutines while the client decodes the next
+// frames, which the race detector reports if a clone shares any state written by the client.
+func (suite *ReplayTestSuite) TestClonesCanBeReadWhileStreaming() {
+	// Arrange
+	var latest atomic.Pointer[gttelemetry.Transformer]
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:   "file://data/replays/demo.gtz",
+		LogLevel: "error",
+		Filters:  map[string]time.Duration{"engineRPM": 100 * time.Millisecond},
+		OnFrame: func(transformer *gttelemetry.Transformer) {
+			latest.Store(transformer.Clone())
+		},
+	})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+
+	reads := make([]int, 4)
+
+	for i := range reads {
+		wg.Go(func() {
+			for ctx.Err() == nil || reads[i] == 0 {
+				if frame := latest.Load(); frame != nil {
+					_ = frame.Snapshot()
+					_, _ = frame.Filtered("engineRPM")
+					_ = frame.FinalDriveEstimate()
+					_ = frame.CurrentVehicle()
+					reads[i]++
+				}
+			}
+		})
+	}
+
+	// Act
+	for _, err := range client.Scan(context.Background()) {
+		suite.Require().NoError(err)
+	}
+
+	cancel()
+	wg.Wait()
+
+	// Assert
+	suite.Equal(client.Telemetry.SequenceID(), latest.Load().SequenceID())
+
+	for _, count := range reads {
+		suite.Positive(count)
+	}
+}
+
+func (suite *ReplayTestSuite) TestStepStopsAtEnds() {
+	// Arrange
+	_, err := suite.replay.Seek(1)
+	suite.Require().NoError(err)
+
+	// Act
+	_, err = suite.replay.Step(-10)
+	suite.Require().NoError(err)
+
+	start := suite.replay.Position()
+
+	_, err = suite.replay.Step(suite.replay.Len() + 10)
+	suite.Require().NoError(err)
+
+	// Assert
+	suite.Equal(0, start)
+	suite.Equal(suite.replay.Len()-1, suite.replay.Position())
+	suite.Positive(suite.replay.Duration())
+	suite.Equal(suite.replay.Duration(), suite.replay.Elapsed(suite.replay.Position()))
+}
+
+func (suite *ReplayTestSuite) TestSeekOutOfRange() {
+	// Act
+	_, err := suite.replay.Seek(suite.replay.Len())
+
+	// Assert
+	suite.Require().ErrorIs(err, gttelemetry.ErrFrameOutOfRange)
+	suite.Equal(-1, suite.replay.Position())
+}
+
+func (suite *ReplayTestSuite) TestOpenReplayRequiresFileSource() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{Source: "udp://127.0.0.1:33739", LogLevel: "error"})
+	suite.Require().NoError(err)
+
+	// Act
+	_, err = client.OpenReplay()
+
+	// Assert
+	suite.Require().ErrorIs(err, gttelemetry.ErrNotAFileSource)
+}
@@ -0,0 +1,13 @@
+package gttelemetry
+
+import "slices"
+
+// trimRetained returns the most recent limit items, or every item when limit is zero. The items kept are copied to a
+// new array so that the dropped items can be collected, without changing slices already returned to callers.
+func trimRetained[T any](items []T, limit int) []T {
+	if limit <= 0 || len(items) <= limit {
+		return items
+	}
+
+	return slices.Clone(items[len(items)-limit:])
+}
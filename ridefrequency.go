@@ -0,0 +1,180 @@
+package gttelemetry
+
+import (
+	"math"
+)
+
+const (
+	// rideStrikeVelocity is the damper velocity in metres per second that marks a kerb strike or bump, from which the
+	// suspension oscillates at its ride frequency.
+	rideStrikeVelocity = 0.25
+	// rideWindowFrames is the number of frames of suspension travel followed after a strike, long enough for one
+	// cycle of the lowest ride frequency measured.
+	rideWindowFrames = FrameRate * 3 / 2
+	// rideMinimumSpeed is the ground speed in metres per second below which strikes are ignored, as the suspension is
+	// disturbed by the driver getting the car moving rather than by the road.
+	rideMinimumSpeed = 10
+	// rideZeroBand is the suspension travel in metres either side of the settled height that must be crossed for the
+	// oscillation to count as passing through it, so that noise around the settled height is not counted.
+	rideZeroBand = 0.001
+	// rideMinimumFrequency and rideMaximumFrequency bound the ride frequencies in hertz that are accepted, from soft
+	// road cars to stiff racing cars, leaving out oscillations that are not the suspension settling.
+	rideMinimumFrequency = 1
+	rideMaximumFrequency = 10
+)
+
+// RideFrequencies are the ride frequencies of the front and rear suspension in hertz, zero when an axle has not been
+// measured. Samples is the number of strikes each frequency is the average of.
+type RideFrequencies struct {
+	FrontHz      float64 `json:"frontHz"`
+	RearHz       float64 `json:"rearHz"`
+	FrontSamples int     `json:"frontSamples"`
+	RearSamples  int     `json:"rearSamples"`
+}
+
+// rideAxle follows the suspension travel of an axle, the average of the suspension height of its two wheels.
+type rideAxle struct {
+	lastHeight   float32
+	started      bool
+	samples      []float64
+	frequencySum float64
+	count        int
+}
+
+// RideFrequencyEstimator estimates the ride frequency of the front and rear suspension from the oscillation of the
+// suspension after kerb strikes and bumps, a setup insight the game does not show.
+//
+// A strike is a damper velocity, the rate of change of the suspension height, above rideStrikeVelocity. The
+// suspension travel of the following one and a half seconds is levelled by removing its trend and the frequency measured from the
+// times it passes back through the settled height. Strikes where the suspension is too heavily damped to complete an
+// oscillation, or that are followed by further bumps, give no or implausible frequencies and are left out.
+type RideFrequencyEstimator struct {
+	lastSequence uint32
+	front        rideAxle
+	rear         rideAxle
+}
+
+// NewRideFrequencyEstimator creates an estimator without any strikes measured.
+func NewRideFrequencyEstimator() *RideFrequencyEstimator {
+	return &RideFrequencyEstimator{}
+}
+
+// Update follows the suspension travel of the latest telemetry frame.
+func (e *RideFrequencyEstimator) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID == e.lastSequence {
+		return
+	}
+
+	previousSequence := e.lastSequence
+	e.lastSequence = sequenceID
+
+	// Strikes are measured over consecutive frames of driving, so start again after a pause or lost frames
+	if t.Flags().GamePaused || !t.IsOnCircuit() || t.GroundSpeedMetresPerSecond() < rideMinimumSpeed ||
+		sequenceID != previousSequence+1 {
+		e.front.reset()
+		e.rear.reset()
+
+		return
+	}
+
+	height := t.SuspensionHeightMetres()
+	e.front.update((height.FrontLeft + height.FrontRight) / 2)
+	e.rear.update((height.RearLeft + height.RearRight) / 2)
+}
+
+// Frequencies returns the ride frequencies estimated so far.
+func (e *RideFrequencyEstimator) Frequencies() RideFrequencies {
+	return RideFrequencies{
+		FrontHz:      e.front.frequency(),
+		RearHz:       e.rear.frequency(),
+		FrontSamples: e.front.count,
+		RearSamples:  e.rear.count,
+	}
+}
+
+func (a *rideAxle) reset() {
+	a.started = false
+	a.samples = a.samples[:0]
+}
+
+func (a *rideAxle) update(height float32) {
+	lastHeight := a.lastHeight
+	started := a.started
+	a.lastHeight = height
+	a.started = true
+
+	switch {
+	case len(a.samples) > 0:
+		a.samples = append(a.samples, float64(height))
+
+		if len(a.samples) == rideWindowFrames {
+			if frequency, ok := oscillationFrequency(a.samples); ok {
+				a.frequencySum += frequency
+				a.count++
+			}
+
+			a.samples = a.samples[:0]
+		}
+	case started && math.Abs(float64(height-lastHeight))*FrameRate >= rideStrikeVelocity:
+		a.samples = append(a.samples, float64(height))
+	}
+}
+
+func (a *rideAxle) frequency() float64 {
+	if a.count == 0 {
+		return 0
+	}
+
+	return a.frequencySum / float64(a.count)
+}
+
+// oscillationFrequency returns the frequency in hertz of the oscillation of samples taken every frame, from the
+// frames at which the levelled samples pass through zero. At least one full cycle is needed.
+func oscillationFrequency(samples []float64) (float64, bool) {
+	frames := make([]float64, len(samples))
+	for i := range frames {
+		frames[i] = float64(i)
+	}
+
+	slope, intercept := linearFit(frames, samples)
+
+	var (
+		crossings []int
+		side      int
+	)
+
+	for i, sample := range samples {
+		level := sample - (slope*float64(i) + intercept)
+
+		current := side
+
+		switch {
+		case level > rideZeroBand:
+			current = 1
+		case level < -rideZeroBand:
+			current = -1
+		}
+
+		if side != 0 && current != side {
+			crossings = append(crossings, i)
+		}
+
+		side = current
+	}
+
+	// Two half cycles are needed to span a full cycle
+	if len(crossings) < 3 {
+		return 0, false
+	}
+
+	first, last := crossings[0], crossings[len(crossings)-1]
+	halfCycles := float64(len(crossings) - 1)
+	frequency := halfCycles / 2 / (float64(last-first) / FrameRate)
+
+	if frequency < rideMinimumFrequency || frequency > rideMaximumFrequency {
+		return 0, false
+	}
+
+	return frequency, true
+}
@@ -0,0 +1,96 @@
+package gttelemetry_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type RideFrequencyTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	estimator   *gttelemetry.RideFrequencyEstimator
+}
+
+func TestRideFrequencyTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RideFrequencyTestSuite))
+}
+
+func (suite *RideFrequencyTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.GroundSpeed = 40
+	suite.estimator = gttelemetry.NewRideFrequencyEstimator()
+}
+
+// strike drives over a kerb, the front and rear suspension oscillating at the given frequencies with light damping
+// before settling.
+func (suite *RideFrequencyTestSuite) strike(frontHz, rearHz float64) {
+	height := func(frequency float64, frame int) float32 {
+		elapsed := float64(frame) / gttelemetry.FrameRate
+
+		return float32(0.05 + 0.03*math.Exp(-elapsed)*math.Sin(2*math.Pi*frequency*elapsed))
+	}
+
+	for range 10 {
+		suite.frame(0.05, 0.05)
+	}
+
+	for frame := range 2 * gttelemetry.FrameRate {
+		suite.frame(height(frontHz, frame), height(rearHz, frame))
+	}
+}
+
+func (suite *RideFrequencyTestSuite) frame(front, rear float32) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.SetSuspensionHeight(front, front, rear, rear)
+	suite.estimator.Update(suite.transformer)
+}
+
+func (suite *RideFrequencyTestSuite) TestFrequenciesAreMeasuredAfterStrike() {
+	// Arrange
+	suite.strike(2.5, 3)
+	suite.strike(2.5, 3)
+
+	// Act
+	frequencies := suite.estimator.Frequencies()
+
+	// Assert
+	suite.InDelta(2.5, frequencies.FrontHz, 0.1)
+	suite.InDelta(3, frequencies.RearHz, 0.1)
+	suite.Equal(2, frequencies.FrontSamples)
+	suite.Equal(2, frequencies.RearSamples)
+}
+
+func (suite *RideFrequencyTestSuite) TestSlowStrikesAreIgnored() {
+	// Arrange
+	suite.transformer.RawTelemetry.GroundSpeed = 2
+	suite.strike(2.5, 3)
+
+	// Act
+	frequencies := suite.estimator.Frequencies()
+
+	// Assert
+	suite.Zero(frequencies.FrontHz)
+	suite.Zero(frequencies.FrontSamples)
+}
+
+func (suite *RideFrequencyTestSuite) TestSmoothRoadIsNotAStrike() {
+	// Arrange
+	for range 5 * gttelemetry.FrameRate {
+		suite.frame(0.05, 0.05)
+	}
+
+	// Act
+	frequencies := suite.estimator.Frequencies()
+
+	// Assert
+	suite.Equal(gttelemetry.RideFrequencies{}, frequencies)
+}
@@ -0,0 +1,64 @@
+package gttelemetry
+
+// SchedulingPriority is the priority of the OS thread decoding telemetry.
+type SchedulingPriority string
+
+const (
+	// SchedulingNormal is the default priority of the operating system.
+	SchedulingNormal SchedulingPriority = "normal"
+	// SchedulingRaised is a raised priority that still shares the CPU with other threads, a negative nice value on
+	// Linux and THREAD_PRIORITY_HIGHEST on Windows.
+	SchedulingRaised SchedulingPriority = "raised"
+	// SchedulingRealtime is a realtime priority that runs ahead of every normal thread, SCHED_FIFO on Linux and
+	// THREAD_PRIORITY_TIME_CRITICAL on Windows.
+	SchedulingRealtime SchedulingPriority = "realtime"
+)
+
+// SchedulingState reports the scheduling achieved by Options.RealtimeScheduling for the current stream.
+type SchedulingState struct {
+	// Requested is whether RealtimeScheduling was set.
+	Requested bool `json:"requested"`
+	// ThreadLocked is whether the decoder is locked to its OS thread, which is only the case while streaming.
+	ThreadLocked bool `json:"threadLocked"`
+	// Priority is the priority of the decoder thread.
+	Priority SchedulingPriority `json:"priority"`
+	// Reason explains why the priority could not be raised to realtime, such as a missing permission.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Scheduling returns the scheduling of the decoder thread, as raised by Options.RealtimeScheduling.
+func (c *Client) Scheduling() SchedulingState {
+	c.schedulingMutex.Lock()
+	defer c.schedulingMutex.Unlock()
+
+	return c.scheduling
+}
+
+// raiseScheduling raises the priority of the OS thread the stream is locked to as far as permitted, returning a
+// function that restores the priority before the thread is unlocked.
+func (c *Client) raiseScheduling() func() {
+	priority, restore, reason := raiseThreadPriority()
+
+	state := SchedulingState{Requested: true, ThreadLocked: true, Priority: priority}
+
+	if reason != nil {
+		state.Reason = reason.Error()
+		c.log.Warn().Err(reason).Str("priority", string(priority)).Msg("could not raise decoder thread to realtime priority")
+	} else {
+		c.log.Info().Str("priority", string(priority)).Msg("raised decoder thread priority")
+	}
+
+	c.setScheduling(state)
+
+	return func() {
+		restore()
+		c.setScheduling(SchedulingState{Requested: true, Priority: SchedulingNormal})
+	}
+}
+
+func (c *Client) setScheduling(state SchedulingState) {
+	c.schedulingMutex.Lock()
+	defer c.schedulingMutex.Unlock()
+
+	c.scheduling = state
+}
@@ -0,0 +1,49 @@
+//go:build linux && !tinygo
+
+package gttelemetry
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// realtimePriority is the SCHED_FIFO priority of the decoder thread, low in the range of 1 to 99 so that the
+	// interrupt and kernel threads that deliver packets still run ahead of it.
+	realtimePriority = 10
+	// raisedNice is the nice value of the decoder thread when realtime scheduling is not permitted.
+	raisedNice = -10
+)
+
+// raiseThreadPriority gives the calling thread SCHED_FIFO scheduling, or failing that a negative nice value, both of
+// which need CAP_SYS_NICE or a raised RLIMIT_RTPRIO or RLIMIT_NICE. The error explains why realtime scheduling was not
+// achieved.
+func raiseThreadPriority() (SchedulingPriority, func(), error) {
+	previous, err := unix.SchedGetAttr(0, 0)
+	if err != nil {
+		return SchedulingNormal, func() {}, fmt.Errorf("get scheduling attributes: %w", err)
+	}
+
+	restore := func() {
+		_ = unix.SchedSetAttr(0, previous, 0)
+	}
+
+	realtime := *previous
+	realtime.Policy = unix.SCHED_FIFO
+	realtime.Priority = realtimePriority
+	realtime.Nice = 0
+
+	fifoErr := unix.SchedSetAttr(0, &realtime, 0)
+	if fifoErr == nil {
+		return SchedulingRealtime, restore, nil
+	}
+
+	// The thread ID selects just the calling thread, rather than the whole process
+	err = unix.Setpriority(unix.PRIO_PROCESS, unix.Gettid(), raisedNice)
+	if err == nil {
+		return SchedulingRaised, restore, fmt.Errorf("set SCHED_FIFO: %w", fifoErr)
+	}
+
+	return SchedulingNormal, func() {}, fmt.Errorf("set SCHED_FIFO: %w, set nice %d: %w", fifoErr, raisedNice, err)
+}
@@ -0,0 +1,14 @@
+//go:build (!linux && !windows) || tinygo
+
+package gttelemetry
+
+import (
+	"errors"
+)
+
+var errSchedulingUnsupported = errors.New("raising the thread priority is only supported on Linux and Windows")
+
+// raiseThreadPriority is not supported on this platform, the decoder is only locked to its OS thread.
+func raiseThreadPriority() (SchedulingPriority, func(), error) {
+	return SchedulingNormal, func() {}, errSchedulingUnsupported
+}
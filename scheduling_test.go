@@ -0,0 +1,90 @@
+package gttelemetry_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type SchedulingTestSuite struct {
+	suite.Suite
+}
+
+func TestSchedulingTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SchedulingTestSuite))
+}
+
+func (suite *SchedulingTestSuite) TestSchedulingIsNormalWhenNotRequested() {
+	// Arrange
+	client, err := gttelemetry.New(gttelemetry.Options{LogLevel: "error", DisableInventories: true})
+	suite.Require().NoError(err)
+
+	// Act
+	gotState := client.Scheduling()
+
+	// Assert
+	suite.Equal(gttelemetry.SchedulingState{Priority: gttelemetry.SchedulingNormal}, gotState)
+}
+
+func (suite *SchedulingTestSuite) TestStreamReportsAchievedScheduling() {
+	// Arrange
+	console, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	suite.Require().NoError(err)
+
+	defer console.Close()
+
+	consolePort := console.LocalAddr().(*net.UDPAddr).Port //nolint:forcetypeassert // Always a UDP address
+
+	client, err := gttelemetry.New(gttelemetry.Options{
+		Source:             "udp://127.0.0.1:" + strconv.Itoa(consolePort),
+		Format:             models.Standard,
+		LogLevel:           "error",
+		DisableInventories: true,
+		RealtimeScheduling: true,
+	})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = client.Stream(ctx)
+	}()
+
+	// Act
+	// The first heartbeat is sent once the stream is running on its locked thread
+	heartbeat := make([]byte, 16)
+	suite.Require().NoError(console.SetReadDeadline(time.Now().Add(5 * time.Second)))
+
+	_, _, err = console.ReadFromUDP(heartbeat)
+	suite.Require().NoError(err)
+
+	streaming := client.Scheduling()
+
+	cancel()
+	<-done
+
+	stopped := client.Scheduling()
+
+	// Assert
+	suite.True(streaming.Requested)
+	suite.True(streaming.ThreadLocked)
+	suite.Contains([]gttelemetry.SchedulingPriority{
+		gttelemetry.SchedulingNormal, gttelemetry.SchedulingRaised, gttelemetry.SchedulingRealtime,
+	}, streaming.Priority)
+
+	if streaming.Priority != gttelemetry.SchedulingRealtime {
+		suite.NotEmpty(streaming.Reason)
+	}
+
+	suite.Equal(gttelemetry.SchedulingState{Requested: true, Priority: gttelemetry.SchedulingNormal}, stopped)
+}
@@ -0,0 +1,53 @@
+//go:build windows && !tinygo
+
+package gttelemetry
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	threadPriorityHighest      = 2
+	threadPriorityTimeCritical = 15
+	threadPriorityErrorReturn  = 0x7fffffff
+)
+
+//nolint:gochecknoglobals // lazily loaded system DLL procedures
+var (
+	kernel32              = windows.NewLazySystemDLL("kernel32.dll")
+	procGetThreadPriority = kernel32.NewProc("GetThreadPriority")
+	procSetThreadPriority = kernel32.NewProc("SetThreadPriority")
+)
+
+// raiseThreadPriority sets the calling thread to THREAD_PRIORITY_TIME_CRITICAL, or failing that to
+// THREAD_PRIORITY_HIGHEST, within the priority class of the process. The error explains why realtime scheduling was
+// not achieved.
+func raiseThreadPriority() (SchedulingPriority, func(), error) {
+	thread, err := windows.GetCurrentThread()
+	if err != nil {
+		return SchedulingNormal, func() {}, fmt.Errorf("get current thread: %w", err)
+	}
+
+	previous, _, err := procGetThreadPriority.Call(uintptr(thread))
+	if previous == threadPriorityErrorReturn {
+		return SchedulingNormal, func() {}, fmt.Errorf("get thread priority: %w", err)
+	}
+
+	restore := func() {
+		_, _, _ = procSetThreadPriority.Call(uintptr(thread), previous)
+	}
+
+	ok, _, criticalErr := procSetThreadPriority.Call(uintptr(thread), threadPriorityTimeCritical)
+	if ok != 0 {
+		return SchedulingRealtime, restore, nil
+	}
+
+	ok, _, err = procSetThreadPriority.Call(uintptr(thread), threadPriorityHighest)
+	if ok != 0 {
+		return SchedulingRaised, restore, fmt.Errorf("set THREAD_PRIORITY_TIME_CRITICAL: %w", criticalErr)
+	}
+
+	return SchedulingNormal, func() {}, fmt.Errorf("set THREAD_PRIORITY_TIME_CRITICAL: %w, set THREAD_PRIORITY_HIGHEST: %w", criticalErr, err)
+}
@@ -0,0 +1,148 @@
+package gttelemetry
+
+import (
+	"strings"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// SessionEventType identifies the kind of session event. Besides the lifecycle events below, every TimelineEventType
+// is also a session event type, such as "lap", "pitStop" or "penalty".
+type SessionEventType string
+
+const (
+	// SessionEventStart is sent when the vehicle goes out on circuit, live or in a replay.
+	SessionEventStart SessionEventType = "sessionStart"
+	// SessionEventEnd is sent when the vehicle leaves the circuit for the menus.
+	SessionEventEnd SessionEventType = "sessionEnd"
+	// SessionEventRaceComplete is sent when the game reports the race as complete, with the best lap time as its value.
+	SessionEventRaceComplete SessionEventType = "raceComplete"
+)
+
+// sessionEventTypes lists every type of session event, for validating event filters.
+var sessionEventTypes = map[SessionEventType]bool{ //nolint:gochecknoglobals // Static lookup table
+	SessionEventStart:                          true,
+	SessionEventEnd:                            true,
+	SessionEventRaceComplete:                   true,
+	SessionEventType(TimelineEventLap):         true,
+	SessionEventType(TimelineEventRaceStart):   true,
+	SessionEventType(TimelineEventPitStop):     true,
+	SessionEventType(TimelineEventTrackLimits): true,
+	SessionEventType(TimelineEventPenalty):     true,
+	SessionEventType(TimelineEventDamage):      true,
+	SessionEventType(TimelineEventGap):         true,
+}
+
+// SessionEvent is a session lifecycle or incident event, in the form delivered to webhooks. SessionTime is measured
+// from the start of the session, and Detail and Value are set as for the TimelineEvent of the same type.
+type SessionEvent struct {
+	Type        SessionEventType `json:"type"`
+	SequenceID  uint32           `json:"sequenceId"`
+	ReceivedAt  time.Time        `json:"receivedAt,omitzero"`
+	SessionTime time.Duration    `json:"sessionTime"`
+	Lap         int16            `json:"lap"`
+	GameState   string           `json:"gameState"`
+	VehicleID   uint32           `json:"vehicleId"`
+	Vehicle     string           `json:"vehicle,omitempty"`
+	Detail      string           `json:"detail,omitempty"`
+	Value       float64          `json:"value,omitempty"`
+}
+
+// SessionEventDetector detects the start and end of sessions on circuit, the completion of races and the events of a
+// Timeline of each session, for services that react to a session as it happens rather than analysing it afterwards.
+type SessionEventDetector struct {
+	opts          TimelineOptions
+	timeline      *Timeline
+	emitted       int
+	lastSequence  uint32
+	startSequence uint32
+	onCircuit     bool
+	raceComplete  bool
+}
+
+// NewSessionEventDetector creates a detector, with the options used for the timeline of each session. Events are
+// returned as they occur, so the timeline only retains the lap in progress and the last lap completed, however long
+// the session.
+func NewSessionEventDetector(opts TimelineOptions) *SessionEventDetector {
+	opts.MaxRetainedLaps = 1
+
+	return &SessionEventDetector{opts: opts}
+}
+
+// Update evaluates the latest telemetry frame and returns the events that occurred in it, in order.
+func (d *SessionEventDetector) Update(t *Transformer) []SessionEvent {
+	sequenceID := t.SequenceID()
+	if sequenceID == d.lastSequence {
+		return nil
+	}
+
+	d.lastSequence = sequenceID
+
+	var events []SessionEvent
+
+	// Loading screens and pauses do not end a session, the vehicle is only off circuit in the menus
+	gameState := t.GameState()
+	onCircuit := gameState == models.GameStateLive || gameState == models.GameStateReplay
+
+	switch {
+	case onCircuit && !d.onCircuit:
+		d.onCircuit = true
+		d.raceComplete = t.RaceComplete()
+		d.startSequence = sequenceID
+		d.timeline = NewTimeline(d.opts)
+		d.emitted = 0
+
+		events = append(events, d.event(t, SessionEventStart))
+	case !onCircuit && d.onCircuit && (gameState == models.GameStateMainMenu || gameState == models.GameStateRaceMenu):
+		d.onCircuit = false
+
+		return append(events, d.event(t, SessionEventEnd))
+	case !d.onCircuit:
+		return nil
+	}
+
+	d.timeline.Update(t)
+
+	// Events already emitted may have been dropped when the timeline limits the laps it retains
+	for _, timelineEvent := range d.timeline.Events()[max(d.emitted-d.timeline.dropped, 0):] {
+		event := d.event(t, SessionEventType(timelineEvent.Type))
+		event.SequenceID = timelineEvent.SequenceID
+		event.Lap = timelineEvent.Lap
+		event.Detail = timelineEvent.Detail
+		event.Value = timelineEvent.Value
+		events = append(events, event)
+	}
+
+	d.emitted = d.timeline.dropped + len(d.timeline.Events())
+
+	if t.RaceComplete() && !d.raceComplete {
+		event := d.event(t, SessionEventRaceComplete)
+		if t.BestLaptime() > 0 {
+			event.Detail = "best lap " + formatLaptime(t.BestLaptime())
+			event.Value = t.BestLaptime().Seconds()
+		}
+
+		events = append(events, event)
+	}
+
+	d.raceComplete = t.RaceComplete()
+
+	return events
+}
+
+// event returns an event of the type at the current frame.
+func (d *SessionEventDetector) event(t *Transformer, eventType SessionEventType) SessionEvent {
+	gameState := t.GameState()
+
+	return SessionEvent{
+		Type:        eventType,
+		SequenceID:  t.SequenceID(),
+		ReceivedAt:  t.ReceivedAt(),
+		SessionTime: t.CorrectedDuration(framesElapsed(d.startSequence, t.SequenceID())),
+		Lap:         t.CurrentLap(),
+		GameState:   gameState.String(),
+		VehicleID:   t.VehicleID(),
+		Vehicle:     strings.TrimSpace(t.VehicleManufacturer() + " " + t.VehicleModel()),
+	}
+}
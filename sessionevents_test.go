@@ -0,0 +1,158 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type SessionEventDetectorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	detector    *gttelemetry.SessionEventDetector
+}
+
+func TestSessionEventDetectorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SessionEventDetectorTestSuite))
+}
+
+func (suite *SessionEventDetectorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.RaceLaps = -1
+	suite.transformer.RawTelemetry.RaceEntrants = -1
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+
+	suite.detector = gttelemetry.NewSessionEventDetector(gttelemetry.TimelineOptions{})
+}
+
+// frame advances the telemetry by a frame and returns the types of the events detected in it.
+func (suite *SessionEventDetectorTestSuite) frame() []gttelemetry.SessionEventType {
+	suite.transformer.RawTelemetry.SequenceId++
+
+	var types []gttelemetry.SessionEventType
+	for _, event := range suite.detector.Update(suite.transformer) {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+// goOnCircuit sets the telemetry to a live race of the given number of laps.
+func (suite *SessionEventDetectorTestSuite) goOnCircuit(laps int16) {
+	suite.transformer.RawTelemetry.RaceLaps = laps
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+	suite.transformer.RawTelemetry.CurrentLap = 1
+}
+
+func (suite *SessionEventDetectorTestSuite) TestDetectsSessionStartAndEnd() {
+	// Arrange
+	suite.Empty(suite.frame(), "No session should start in the menus")
+
+	// Act
+	suite.goOnCircuit(3)
+	start := suite.frame()
+	during := suite.frame()
+
+	suite.transformer.RawTelemetry.RaceEntrants = -1
+	end := suite.frame()
+	after := suite.frame()
+
+	// Assert
+	suite.Equal([]gttelemetry.SessionEventType{gttelemetry.SessionEventStart}, start)
+	suite.Empty(during)
+	suite.Equal([]gttelemetry.SessionEventType{gttelemetry.SessionEventEnd}, end)
+	suite.Empty(after)
+}
+
+func (suite *SessionEventDetectorTestSuite) TestForwardsTimelineEvents() {
+	// Arrange
+	suite.goOnCircuit(3)
+	suite.frame()
+
+	for range 59 {
+		suite.frame()
+	}
+
+	// Act
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.transformer.RawTelemetry.LastLaptime = 83456
+	suite.transformer.RawTelemetry.SequenceId++
+	events := suite.detector.Update(suite.transformer)
+
+	// Assert
+	suite.Require().Len(events, 1)
+	suite.Equal(gttelemetry.SessionEventType(gttelemetry.TimelineEventLap), events[0].Type)
+	suite.Equal(int16(1), events[0].Lap)
+	suite.Equal("1:23.456", events[0].Detail)
+	suite.Equal(time.Second, events[0].SessionTime)
+	suite.Equal("live", events[0].GameState)
+}
+
+func (suite *SessionEventDetectorTestSuite) TestForwardsEveryLapOfLongSessions() {
+	// Arrange
+	suite.goOnCircuit(0)
+	suite.frame()
+
+	var laps []int16
+
+	// Act
+	for lap := range int16(10) {
+		for range 10 {
+			suite.frame()
+		}
+
+		suite.transformer.RawTelemetry.CurrentLap = lap + 2
+		suite.transformer.RawTelemetry.LastLaptime = 60000
+		suite.transformer.RawTelemetry.SequenceId++
+
+		for _, event := range suite.detector.Update(suite.transformer) {
+			if event.Type == gttelemetry.SessionEventType(gttelemetry.TimelineEventLap) {
+				laps = append(laps, event.Lap)
+			}
+		}
+	}
+
+	// Assert
+	suite.Equal([]int16{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, laps)
+}
+
+func (suite *SessionEventDetectorTestSuite) TestDetectsRaceCompleteOnce() {
+	// Arrange
+	suite.goOnCircuit(1)
+	suite.frame()
+	suite.transformer.RawTelemetry.BestLaptime = 90500
+
+	// Act
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.transformer.RawTelemetry.SequenceId++
+	events := suite.detector.Update(suite.transformer)
+	again := suite.frame()
+
+	// Assert
+	suite.Require().NotEmpty(events)
+	complete := events[len(events)-1]
+	suite.Equal(gttelemetry.SessionEventRaceComplete, complete.Type)
+	suite.Equal("best lap 1:30.500", complete.Detail)
+	suite.InDelta(90.5, complete.Value, 1e-9)
+	suite.NotContains(again, gttelemetry.SessionEventRaceComplete)
+}
+
+func (suite *SessionEventDetectorTestSuite) TestIgnoresRepeatedFrames() {
+	// Arrange
+	suite.goOnCircuit(3)
+	suite.frame()
+
+	// Act
+	events := suite.detector.Update(suite.transformer)
+
+	// Assert
+	suite.Empty(events)
+}
@@ -0,0 +1,210 @@
+package gttelemetry
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+)
+
+// SetupComparisonSectors is the number of sectors of equal distance each lap is split into. The telemetry does not
+// report the timing sectors of a circuit, so the sectors of laps are only comparable with other laps of the same
+// circuit.
+const SetupComparisonSectors = 3
+
+// SetupResult is the performance of a vehicle and setup on a circuit, over the laps added to a SetupComparison.
+type SetupResult struct {
+	CircuitID string           `json:"circuitId"`
+	VehicleID uint32           `json:"vehicleId"`
+	Vehicle   string           `json:"vehicle"`
+	Setup     SetupFingerprint `json:"setup"`
+	Laps      int              `json:"laps"`
+	BestLap   time.Duration    `json:"bestLap"`
+	// Gap is the time the best lap is behind the best lap of the fastest vehicle and setup on the circuit.
+	Gap time.Duration `json:"gap"`
+	// BestLapSectors are the sector times of the best lap.
+	BestLapSectors []time.Duration `json:"bestLapSectors"`
+	// BestSectors are the best time of each sector over every lap, which add up to the best lap that could be driven by
+	// putting the best sectors together.
+	BestSectors []time.Duration `json:"bestSectors"`
+	TopSpeedKPH float32         `json:"topSpeedKPH"`
+}
+
+// OptimalLap returns the sum of the best sectors.
+func (r SetupResult) OptimalLap() time.Duration {
+	var optimal time.Duration
+
+	for _, sector := range r.BestSectors {
+		optimal += sector
+	}
+
+	return optimal
+}
+
+// setupKey identifies the results of a vehicle and setup on a circuit.
+type setupKey struct {
+	circuitID string
+	setup     SetupFingerprint
+}
+
+// SetupComparison compares the laps driven on each circuit by different vehicles and setups, by best lap, sector
+// times and top speed, for choosing the vehicle to use for a race. Laps are added from the frames of any number of
+// recordings, one after another, and the circuit of each lap is identified from the circuit database.
+//
+// Laps driven across a pause or packet loss, and laps on circuits that are not identified, are left out.
+type SetupComparison struct {
+	matcher      *circuits.PathMatcher
+	history      *LapHistory
+	traces       *LapTraceRecorder
+	lastSequence uint32
+	results      map[setupKey]*SetupResult
+}
+
+// NewSetupComparison creates a comparison without any laps, identifying circuits in the circuit database.
+func NewSetupComparison(db *circuits.CircuitDB) *SetupComparison {
+	comparison := &SetupComparison{
+		matcher: circuits.NewPathMatcher(db),
+		results: make(map[setupKey]*SetupResult),
+	}
+
+	comparison.startRecording()
+
+	return comparison
+}
+
+// Update evaluates the latest telemetry frame, adding the lap to the comparison when the vehicle has just completed
+// one. A sequence ID lower than that of the previous frame starts a new recording.
+func (c *SetupComparison) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID < c.lastSequence {
+		c.startRecording()
+	}
+
+	c.lastSequence = sequenceID
+
+	snapshot, snapshotOK := c.history.Update(t)
+	trace, traceOK := c.traces.Update(t)
+
+	if !t.IsOnCircuit() {
+		return
+	}
+
+	ident := c.matcher.Identify(t.PositionalMapCoordinates())
+
+	if !traceOK || !ident.Identified() || (snapshotOK && len(snapshot.Gaps) > 0) {
+		return
+	}
+
+	setup := t.SetupFingerprint()
+	if snapshotOK {
+		setup = snapshot.Setup
+	}
+
+	vehicle := strings.TrimSpace(t.VehicleManufacturer() + " " + t.VehicleModel())
+
+	c.AddLap(ident.CircuitID, t.VehicleID(), vehicle, setup, trace)
+}
+
+// AddLap adds the trace of a lap driven on a circuit by a vehicle and setup to the comparison. Laps without a lap
+// time are left out.
+func (c *SetupComparison) AddLap(circuitID string, vehicleID uint32, vehicle string, setup SetupFingerprint, trace LapTrace) {
+	if trace.Laptime <= 0 || len(trace.Samples) == 0 {
+		return
+	}
+
+	key := setupKey{circuitID: circuitID, setup: setup}
+
+	result, ok := c.results[key]
+	if !ok {
+		result = &SetupResult{CircuitID: circuitID, VehicleID: vehicleID, Vehicle: vehicle, Setup: setup}
+		c.results[key] = result
+	}
+
+	sectors := lapSectors(trace)
+
+	result.Laps++
+
+	if result.BestLap == 0 || trace.Laptime < result.BestLap {
+		result.BestLap = trace.Laptime
+		result.BestLapSectors = sectors
+	}
+
+	if result.BestSectors == nil {
+		result.BestSectors = slices.Clone(sectors)
+	}
+
+	for i, sector := range sectors {
+		result.BestSectors[i] = min(result.BestSectors[i], sector)
+	}
+
+	for _, sample := range trace.Samples {
+		result.TopSpeedKPH = max(result.TopSpeedKPH, sample.SpeedKPH)
+	}
+}
+
+// Circuits returns the IDs of the circuits with laps in the comparison, in order.
+func (c *SetupComparison) Circuits() []string {
+	var circuitIDs []string
+
+	for key := range c.results {
+		if !slices.Contains(circuitIDs, key.circuitID) {
+			circuitIDs = append(circuitIDs, key.circuitID)
+		}
+	}
+
+	slices.Sort(circuitIDs)
+
+	return circuitIDs
+}
+
+// Results returns the results of each vehicle and setup driven on a circuit, fastest first.
+func (c *SetupComparison) Results(circuitID string) []SetupResult {
+	var results []SetupResult
+
+	for key, result := range c.results {
+		if key.circuitID == circuitID {
+			results = append(results, *result)
+		}
+	}
+
+	slices.SortFunc(results, func(a, b SetupResult) int {
+		return cmp.Or(cmp.Compare(a.BestLap, b.BestLap), cmp.Compare(a.Setup, b.Setup))
+	})
+
+	for i := range results {
+		results[i].Gap = results[i].BestLap - results[0].BestLap
+	}
+
+	return results
+}
+
+// startRecording forgets the laps in progress, ready for the frames of another recording.
+func (c *SetupComparison) startRecording() {
+	c.matcher.Reset()
+	c.history = NewLapHistory()
+	c.history.SetMaxRetainedLaps(1)
+	c.traces = NewLapTraceRecorder()
+	c.traces.SetMaxRetainedLaps(1)
+	c.lastSequence = 0
+}
+
+// lapSectors splits a lap into sectors of equal distance and returns the time of each. The last sector ends at the
+// lap time reported by the game.
+func lapSectors(trace LapTrace) []time.Duration {
+	distance := trace.Samples[len(trace.Samples)-1].Distance
+	sectors := make([]time.Duration, SetupComparisonSectors)
+
+	var start time.Duration
+
+	for i := range SetupComparisonSectors - 1 {
+		end := sampleAtDistance(trace.Samples, distance*float32(i+1)/SetupComparisonSectors).Elapsed
+		sectors[i] = end - start
+		start = end
+	}
+
+	sectors[SetupComparisonSectors-1] = trace.Laptime - start
+
+	return sectors
+}
@@ -0,0 +1,160 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+// straightCircuit is a custom circuit well away from the circuits of the inventory.
+const straightCircuit = `{"id": "Straight", "coordinates": [
+	{"x": 20000, "y": 0, "z": 20000}, {"x": 20016, "y": 0, "z": 20000}, {"x": 20032, "y": 0, "z": 20000},
+	{"x": 20048, "y": 0, "z": 20000}, {"x": 20064, "y": 0, "z": 20000}, {"x": 20080, "y": 0, "z": 20000}
+]}`
+
+type SetupComparisonTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	comparison  *gttelemetry.SetupComparison
+	x           float32
+}
+
+func TestSetupComparisonTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SetupComparisonTestSuite))
+}
+
+func (suite *SetupComparisonTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	db, err := circuits.NewDB(circuits.CircuitDBOptions{SkipEmbedded: true})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.Merge([]byte(straightCircuit)))
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.comparison = gttelemetry.NewSetupComparison(db)
+	suite.x = 20002
+}
+
+// lap drives a lap of one second at 36 metres per second along the straight circuit.
+func (suite *SetupComparisonTestSuite) lap(lap int16) {
+	for range gttelemetry.FrameRate {
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.transformer.RawTelemetry.CurrentLap = lap
+		suite.transformer.RawTelemetry.GroundSpeed = 36
+		suite.transformer.SetMapPositionCoordinates(suite.x, 0, 20008)
+		suite.comparison.Update(suite.transformer)
+
+		suite.x += 0.6
+	}
+}
+
+// sectorTrace returns the trace of a lap of 300 metres, driven at the speed of each of its three 100 metre sectors.
+func sectorTrace(speeds ...float32) gttelemetry.LapTrace {
+	var (
+		lap     gttelemetry.LapTrace
+		elapsed time.Duration
+	)
+
+	for i, speed := range speeds {
+		for metre := range 100 {
+			lap.Samples = append(lap.Samples, gttelemetry.LapTraceSample{
+				Distance: float32(i*100 + metre),
+				Elapsed:  elapsed,
+				SpeedKPH: speed * 3.6,
+			})
+
+			elapsed += time.Duration(float64(time.Second) / float64(speed))
+		}
+	}
+
+	lap.Laptime = elapsed
+	lap.Samples = append(lap.Samples, gttelemetry.LapTraceSample{Distance: 300, Elapsed: elapsed})
+
+	return lap
+}
+
+func (suite *SetupComparisonTestSuite) TestResultsRankSetupsOnCircuit() {
+	// Arrange
+	suite.comparison.AddLap("Suzuka", 1, "Mazda RX-7", "soft", sectorTrace(50, 20, 50))
+	suite.comparison.AddLap("Suzuka", 1, "Mazda RX-7", "soft", sectorTrace(25, 50, 50))
+	suite.comparison.AddLap("Suzuka", 2, "Honda NSX", "stiff", sectorTrace(50, 50, 50))
+	suite.comparison.AddLap("Monza", 1, "Mazda RX-7", "soft", sectorTrace(10, 10, 10))
+
+	// Act
+	results := suite.comparison.Results("Suzuka")
+
+	// Assert
+	suite.Equal([]string{"Monza", "Suzuka"}, suite.comparison.Circuits())
+	suite.Require().Len(results, 2)
+
+	stiff, soft := results[0], results[1]
+	suite.Equal(gttelemetry.SetupFingerprint("stiff"), stiff.Setup)
+	suite.Equal("Honda NSX", stiff.Vehicle)
+	suite.Equal(1, stiff.Laps)
+	suite.Equal(6*time.Second, stiff.BestLap)
+	suite.Zero(stiff.Gap)
+	suite.InDelta(180, stiff.TopSpeedKPH, 0.001)
+
+	suite.Equal(2, soft.Laps)
+	suite.Equal(8*time.Second, soft.BestLap)
+	suite.Equal(2*time.Second, soft.Gap)
+	suite.InDeltaSlice([]time.Duration{4 * time.Second, 2 * time.Second, 2 * time.Second}, soft.BestLapSectors, float64(time.Millisecond))
+	suite.InDeltaSlice([]time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second}, soft.BestSectors, float64(time.Millisecond))
+	suite.InDelta(float64(6*time.Second), float64(soft.OptimalLap()), float64(time.Millisecond))
+}
+
+func (suite *SetupComparisonTestSuite) TestLapsAreAddedFromFrames() {
+	// Arrange
+	suite.lap(1)
+	suite.lap(2)
+	suite.transformer.RawTelemetry.LastLaptime = 1000
+
+	// Act
+	suite.lap(3)
+
+	// Assert
+	suite.Equal([]string{"Straight"}, suite.comparison.Circuits())
+
+	results := suite.comparison.Results("Straight")
+	suite.Require().Len(results, 1)
+	suite.Equal(1, results[0].Laps, "The lap the frames start in is not driven from the start")
+	suite.Equal(time.Second, results[0].BestLap)
+	suite.Equal(suite.transformer.SetupFingerprint(), results[0].Setup)
+	suite.InDelta(129.6, results[0].TopSpeedKPH, 0.01)
+}
+
+func (suite *SetupComparisonTestSuite) TestLapsOffKnownCircuitsAreLeftOut() {
+	// Arrange
+	suite.x = -20000
+
+	suite.lap(1)
+	suite.lap(2)
+	suite.transformer.RawTelemetry.LastLaptime = 1000
+
+	// Act
+	suite.lap(3)
+
+	// Assert
+	suite.Empty(suite.comparison.Circuits())
+}
+
+func (suite *SetupComparisonTestSuite) TestNewRecordingStartsAgain() {
+	// Arrange
+	suite.lap(1)
+	suite.lap(2)
+
+	// Act
+	suite.transformer.RawTelemetry.SequenceId = 0
+	suite.transformer.RawTelemetry.LastLaptime = 1000
+	suite.lap(3)
+
+	// Assert
+	suite.Empty(suite.comparison.Circuits(), "The lap in progress when the recording ended is not completed")
+}
@@ -0,0 +1,75 @@
+package gttelemetry
+
+import (
+	"math"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// Snapshot returns the current frame as a models.FrameSnapshot, with the value of every channel, for handing frames
+// to other applications in the stable schema described by models.SnapshotSchemaVersion. Channels that have no value
+// in the frame, such as the fuel level percentage of an electric car without a fuel tank, are left out.
+func (t *Transformer) Snapshot() models.FrameSnapshot {
+	gameState := t.GameState()
+	flags := t.Flags()
+
+	// The format is read from the packet, so is unknown until the first packet is received
+	format := models.Unknown
+	if t.TelemetryStarted() {
+		format = t.TelemetryFormat()
+	}
+
+	// Frames read from a file were not received from the network
+	var source string
+	if address := t.SourceAddress(); address.IsValid() {
+		source = address.String()
+	}
+
+	snapshot := models.FrameSnapshot{
+		SchemaVersion: models.SnapshotSchemaVersion,
+		SequenceID:    t.SequenceID(),
+		ReceivedAt:    t.ReceivedAt(),
+		Source:        source,
+		Format:        format,
+		GameState:     gameState.String(),
+		Flags: models.SnapshotFlags{
+			Live:             flags.Live,
+			GamePaused:       flags.GamePaused,
+			Loading:          flags.Loading,
+			InGear:           flags.InGear,
+			HasTurbo:         flags.HasTurbo,
+			RevLimiterAlert:  flags.RevLimiterAlert,
+			HandbrakeActive:  flags.HandbrakeActive,
+			HeadlightsActive: flags.HeadlightsActive,
+			HighBeamActive:   flags.HighBeamActive,
+			LowBeamActive:    flags.LowBeamActive,
+			ASMActive:        flags.ASMActive,
+			TCSActive:        flags.TCSActive,
+		},
+		VehicleID:           t.VehicleID(),
+		VehicleManufacturer: t.VehicleManufacturer(),
+		VehicleModel:        t.VehicleModel(),
+		CurrentLap:          t.CurrentLap(),
+		RaceLaps:            t.RaceLaps(),
+		CurrentLaptimeMs:    t.CurrentLaptime().Milliseconds(),
+		LastLaptimeMs:       t.LastLaptime().Milliseconds(),
+		BestLaptimeMs:       t.BestLaptime().Milliseconds(),
+		Position:            t.PositionalMapCoordinates(),
+		Velocity:            t.VelocityVector(),
+		Rotation:            t.RotationEnvelope(),
+		Channels:            make(map[string]float32, len(channels)),
+	}
+
+	for name, read := range channels {
+		value := read(t)
+
+		// JSON has no representation of NaN or infinity
+		if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+			continue
+		}
+
+		snapshot.Channels[name] = value
+	}
+
+	return snapshot
+}
@@ -0,0 +1,121 @@
+package gttelemetry_test
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type SnapshotTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestSnapshotTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SnapshotTestSuite))
+}
+
+func (suite *SnapshotTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshotHoldsFrame() {
+	// Arrange
+	suite.transformer.SetFormatAddendum2()
+	suite.transformer.RawTelemetry.SequenceId = 42
+	suite.transformer.RawTelemetry.CurrentLap = 3
+	suite.transformer.RawTelemetry.LastLaptime = 83456
+	suite.transformer.RawTelemetry.EngineRpm = 7000
+	suite.transformer.SetMapPositionCoordinates(1, 2, 3)
+	suite.transformer.RawTelemetry.FuelCapacity = 100
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, true)
+	suite.transformer.SetSourceAddress(netip.MustParseAddrPort("192.168.1.20:33739"))
+
+	// Act
+	snapshot := suite.transformer.Snapshot()
+
+	// Assert
+	suite.Equal(models.SnapshotSchemaVersion, snapshot.SchemaVersion)
+	suite.Equal(uint32(42), snapshot.SequenceID)
+	suite.Equal("192.168.1.20:33739", snapshot.Source)
+	suite.Equal(models.Addendum2, snapshot.Format)
+	suite.Equal(int16(3), snapshot.CurrentLap)
+	suite.Equal(int64(83456), snapshot.LastLaptimeMs)
+	suite.Equal(models.Coordinate{X: 1, Y: 2, Z: 3}, snapshot.Position)
+	suite.True(snapshot.Flags.Live)
+	suite.True(snapshot.Flags.InGear)
+	suite.True(snapshot.Flags.TCSActive)
+	suite.False(snapshot.Flags.GamePaused)
+	suite.InDelta(7000, snapshot.Channels["engineRPM"], 0.01)
+	suite.Len(snapshot.Channels, len(gttelemetry.ChannelNames()))
+}
+
+func (suite *SnapshotTestSuite) TestSnapshotBeforeFirstPacketHasUnknownFormat() {
+	// Act
+	snapshot := suite.transformer.Snapshot()
+
+	// Assert
+	suite.Equal(models.Unknown, snapshot.Format)
+	suite.Equal(uint32(0), snapshot.SequenceID)
+	suite.Empty(snapshot.Source)
+}
+
+func (suite *SnapshotTestSuite) TestSnapshotLeavesOutChannelsWithoutValue() {
+	// Arrange
+	suite.transformer.RawTelemetry.FuelCapacity = 0
+
+	// Act
+	snapshot := suite.transformer.Snapshot()
+
+	// Assert
+	suite.NotContains(snapshot.Channels, "fuelLevelPercent")
+	suite.Contains(snapshot.Channels, "fuelLevel")
+}
+
+// TestSnapshotSchemaIsAdditive checks that every field of version 1.0.0 of the schema is still present, as fields
+// must never be renamed or removed within a major version.
+func (suite *SnapshotTestSuite) TestSnapshotSchemaIsAdditive() {
+	// Arrange
+	tests := map[string][]string{
+		"snapshot": {
+			"schemaVersion", "sequenceId", "format", "gameState", "flags", "vehicleId", "vehicleManufacturer",
+			"vehicleModel", "currentLap", "raceLaps", "currentLaptimeMs", "lastLaptimeMs", "bestLaptimeMs", "position",
+			"velocity", "rotation", "channels",
+		},
+		"flags": {
+			"live", "gamePaused", "loading", "inGear", "hasTurbo", "revLimiterAlert", "handbrakeActive",
+			"headlightsActive", "highBeamActive", "lowBeamActive", "asmActive", "tcsActive",
+		},
+	}
+
+	// Act
+	data, err := json.Marshal(suite.transformer.Snapshot())
+	suite.Require().NoError(err)
+
+	var snapshot map[string]json.RawMessage
+	suite.Require().NoError(json.Unmarshal(data, &snapshot))
+
+	var flags map[string]json.RawMessage
+	suite.Require().NoError(json.Unmarshal(snapshot["flags"], &flags))
+
+	fields := map[string]map[string]json.RawMessage{"snapshot": snapshot, "flags": flags}
+
+	// Assert
+	for name, keys := range tests {
+		suite.Run(name, func() {
+			for _, key := range keys {
+				suite.Contains(fields[name], key)
+			}
+		})
+	}
+}
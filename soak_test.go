@@ -0,0 +1,126 @@
+package gttelemetry_test
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+const (
+	// soakDefaultDuration is the session time simulated by the soak test, set GT_SOAK_DURATION, e.g. to 24h, to soak
+	// for longer.
+	soakDefaultDuration = 2 * time.Hour
+	// soakLaptime is the time of each lap of the soak session, short so that many laps are completed.
+	soakLaptime = time.Minute
+	// soakRetainedLaps is the number of laps of history kept during the soak session.
+	soakRetainedLaps = 5
+	// soakMaxHeapGrowth is the most the heap may grow between the middle and end of the soak session.
+	soakMaxHeapGrowth = 4 << 20
+)
+
+// SoakTestSuite drives the lap and session histories through a long session, checking that memory stays bounded when
+// the laps retained are limited. The suite is not parallel so that the heap is only used by the soak session.
+type SoakTestSuite struct {
+	suite.Suite
+}
+
+func TestSoakTestSuite(t *testing.T) {
+	suite.Run(t, new(SoakTestSuite))
+}
+
+// soakDuration returns the session time to simulate.
+func (suite *SoakTestSuite) soakDuration() time.Duration {
+	value := os.Getenv("GT_SOAK_DURATION")
+	if value == "" {
+		return soakDefaultDuration
+	}
+
+	duration, err := time.ParseDuration(value)
+	suite.Require().NoError(err)
+
+	return duration
+}
+
+// heapInUse returns the bytes of live heap objects after a garbage collection.
+func heapInUse() uint64 {
+	runtime.GC()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapAlloc
+}
+
+func (suite *SoakTestSuite) TestHistoriesStayBoundedOverLongSession() {
+	// Arrange
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	transformer := gttelemetry.NewTransformer(inventory)
+	transformer.RawTelemetry.RaceLaps = 0
+	transformer.RawTelemetry.RaceEntrants = 16
+	transformer.RawTelemetry.CurrentLap = 1
+	transformer.RawTelemetry.GroundSpeed = 50
+	transformer.RawTelemetry.FuelLevel = 100
+	transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+
+	history := gttelemetry.NewLapHistory()
+	history.SetMaxRetainedLaps(soakRetainedLaps)
+
+	traces := gttelemetry.NewLapTraceRecorder()
+	traces.SetMaxRetainedLaps(soakRetainedLaps)
+
+	timeline := gttelemetry.NewTimeline(gttelemetry.TimelineOptions{MaxRetainedLaps: soakRetainedLaps})
+	events := gttelemetry.NewSessionEventDetector(gttelemetry.TimelineOptions{})
+
+	subtitles, err := gttelemetry.NewOverlayStream(io.Discard, "srt", gttelemetry.OverlayOptions{})
+	suite.Require().NoError(err)
+
+	frames := int(suite.soakDuration() / gttelemetry.FrameInterval)
+	framesPerLap := int(soakLaptime / gttelemetry.FrameInterval)
+	laps := 0
+
+	var middleHeap uint64
+
+	// Act
+	for frame := 1; frame <= frames; frame++ {
+		transformer.RawTelemetry.SequenceId++
+
+		if frame%framesPerLap == 0 {
+			transformer.RawTelemetry.CurrentLap++
+			transformer.RawTelemetry.LastLaptime = int32(soakLaptime / time.Millisecond)
+			transformer.RawTelemetry.FuelLevel = 100
+			laps++
+		}
+
+		transformer.RawTelemetry.FuelLevel -= 0.001
+
+		history.Update(transformer)
+		traces.Update(transformer)
+		timeline.Update(transformer)
+		events.Update(transformer)
+		suite.Require().NoError(subtitles.Update(transformer))
+
+		if frame == frames/2 {
+			middleHeap = heapInUse()
+		}
+	}
+
+	suite.Require().NoError(subtitles.Close())
+
+	endHeap := heapInUse()
+
+	// Assert
+	suite.Greater(laps, soakRetainedLaps*2)
+	suite.Len(history.Snapshots(), soakRetainedLaps)
+	suite.Len(traces.Traces(), soakRetainedLaps)
+	suite.LessOrEqual(len(timeline.Events()), soakRetainedLaps+1)
+	suite.Less(int64(endHeap)-int64(middleHeap), int64(soakMaxHeapGrowth),
+		"heap grew from %d to %d bytes over the second half of %d laps", middleHeap, endHeap, laps)
+}
@@ -0,0 +1,161 @@
+package gttelemetry
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// TrapLine is a named line across the circuit, defined by two map coordinates either side of the track. Only the
+// horizontal X and Z components of the coordinates are used.
+type TrapLine struct {
+	Name string            `json:"name"`
+	A    models.Coordinate `json:"a"`
+	B    models.Coordinate `json:"b"`
+}
+
+// LapSplits holds the speed recorded at each trap line during a single lap, keyed by trap name in metres per
+// second. Trap names are stable so splits can be compared between laps and sessions on the same circuit.
+type LapSplits struct {
+	Lap    int16              `json:"lap"`
+	Speeds map[string]float32 `json:"speeds"`
+}
+
+// TrapCrossing describes a single trap line crossing.
+type TrapCrossing struct {
+	Trap  string
+	Lap   int16
+	Speed float32
+}
+
+// SpeedTraps records the speed of the vehicle as it crosses each registered trap line.
+type SpeedTraps struct {
+	traps        []TrapLine
+	lastPosition models.Coordinate
+	lastSpeed    float32
+	lastSequence uint32
+	hasLast      bool
+	current      LapSplits
+	laps         []LapSplits
+}
+
+// NewSpeedTraps creates a set of speed traps for the given trap lines.
+func NewSpeedTraps(traps ...TrapLine) *SpeedTraps {
+	return &SpeedTraps{
+		traps:   traps,
+		current: LapSplits{Speeds: map[string]float32{}},
+	}
+}
+
+// TrapsFromPath generates evenly spaced trap lines along a reference path, such as the coordinates of a captured
+// lap, for use when no sector boundaries are known for the circuit. Each line is centred on the path, perpendicular
+// to it and width metres wide. Traps are named "T1", "T2" and so on in driving order.
+func TrapsFromPath(path []models.Coordinate, count int, width float32) []TrapLine {
+	if len(path) < 2 || count < 1 {
+		return nil
+	}
+
+	cumulative := make([]float64, len(path))
+	for i := 1; i < len(path); i++ {
+		cumulative[i] = cumulative[i-1] + planarDistance(path[i-1], path[i])
+	}
+
+	total := cumulative[len(cumulative)-1]
+	traps := make([]TrapLine, 0, count)
+	segment := 1
+
+	for trap := 1; trap <= count; trap++ {
+		distance := total * float64(trap) / float64(count+1)
+
+		for segment < len(path)-1 && cumulative[segment] < distance {
+			segment++
+		}
+
+		start, end := path[segment-1], path[segment]
+		length := cumulative[segment] - cumulative[segment-1]
+
+		fraction := float32(0)
+		if length > 0 {
+			fraction = float32((distance - cumulative[segment-1]) / length)
+		}
+
+		centre := lerpCoordinate(start, end, fraction)
+		normalX, normalZ := float64(-(end.Z - start.Z)), float64(end.X-start.X)
+		scale := float64(width) / 2 / math.Max(math.Hypot(normalX, normalZ), math.SmallestNonzeroFloat32)
+
+		traps = append(traps, TrapLine{
+			Name: "T" + strconv.Itoa(trap),
+			A:    models.Coordinate{X: centre.X + float32(normalX*scale), Y: centre.Y, Z: centre.Z + float32(normalZ*scale)},
+			B:    models.Coordinate{X: centre.X - float32(normalX*scale), Y: centre.Y, Z: centre.Z - float32(normalZ*scale)},
+		})
+	}
+
+	return traps
+}
+
+// Update checks whether the vehicle crossed any trap line since the previous frame and returns the crossings. The
+// speed at each crossing is interpolated between the two frames either side of the line.
+func (s *SpeedTraps) Update(t *Transformer) []TrapCrossing {
+	sequenceID := t.SequenceID()
+	if s.hasLast && sequenceID == s.lastSequence {
+		return nil
+	}
+
+	if lap := t.CurrentLap(); lap != s.current.Lap {
+		if len(s.current.Speeds) > 0 {
+			s.laps = append(s.laps, s.current)
+		}
+
+		s.current = LapSplits{Lap: lap, Speeds: map[string]float32{}}
+	}
+
+	position := t.PositionalMapCoordinates()
+	speed := t.GroundSpeedMetresPerSecond()
+
+	var crossings []TrapCrossing
+
+	if s.hasLast && t.IsOnCircuit() {
+		for _, trap := range s.traps {
+			fraction, ok := segmentIntersection(s.lastPosition, position, trap.A, trap.B)
+			if !ok {
+				continue
+			}
+
+			trapSpeed := s.lastSpeed + (speed-s.lastSpeed)*float32(fraction)
+			s.current.Speeds[trap.Name] = trapSpeed
+
+			crossings = append(crossings, TrapCrossing{Trap: trap.Name, Lap: s.current.Lap, Speed: trapSpeed})
+		}
+	}
+
+	s.lastPosition = position
+	s.lastSpeed = speed
+	s.lastSequence = sequenceID
+	s.hasLast = true
+
+	return crossings
+}
+
+// CurrentLap returns the splits recorded so far on the current lap.
+func (s *SpeedTraps) CurrentLap() LapSplits {
+	return s.current
+}
+
+// Laps returns the splits of all completed laps.
+func (s *SpeedTraps) Laps() []LapSplits {
+	return s.laps
+}
+
+// CompareSplits returns the difference in speed at each trap present in both laps, as b minus a.
+func CompareSplits(a, b LapSplits) map[string]float32 {
+	deltas := map[string]float32{}
+
+	for name, speedA := range a.Speeds {
+		if speedB, ok := b.Speeds[name]; ok {
+			deltas[name] = speedB - speedA
+		}
+	}
+
+	return deltas
+}
@@ -0,0 +1,124 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type SpeedTrapsTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	traps       *gttelemetry.SpeedTraps
+}
+
+func TestSpeedTrapsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SpeedTrapsTestSuite))
+}
+
+func (suite *SpeedTrapsTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+
+	suite.traps = gttelemetry.NewSpeedTraps(gttelemetry.TrapLine{
+		Name: "Finish straight",
+		A:    models.Coordinate{X: 100, Z: -10},
+		B:    models.Coordinate{X: 100, Z: 10},
+	})
+}
+
+// drive moves the vehicle to the given position and speed and feeds a new frame to the speed traps.
+func (suite *SpeedTrapsTestSuite) drive(x float32, speed float32) []gttelemetry.TrapCrossing {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.SetMapPositionCoordinates(x, 0, 0)
+	suite.transformer.RawTelemetry.GroundSpeed = speed
+
+	return suite.traps.Update(suite.transformer)
+}
+
+func (suite *SpeedTrapsTestSuite) TestCrossingRecordsInterpolatedSpeed() {
+	// Arrange
+	suite.drive(90, 50)
+
+	// Act
+	crossings := suite.drive(110, 60)
+
+	// Assert
+	suite.Require().Len(crossings, 1)
+	suite.Equal("Finish straight", crossings[0].Trap)
+	suite.Equal(int16(1), crossings[0].Lap)
+	suite.InDelta(55, crossings[0].Speed, 0.001)
+	suite.InDelta(55, suite.traps.CurrentLap().Speeds["Finish straight"], 0.001)
+}
+
+func (suite *SpeedTrapsTestSuite) TestNoCrossingAwayFromTrap() {
+	// Arrange
+	suite.drive(10, 50)
+
+	// Act
+	crossings := suite.drive(20, 50)
+
+	// Assert
+	suite.Empty(crossings)
+}
+
+func (suite *SpeedTrapsTestSuite) TestCompletedLapsAreRetained() {
+	// Arrange
+	suite.drive(90, 50)
+	suite.drive(110, 50)
+
+	// Act
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.drive(120, 50)
+
+	// Assert
+	suite.Require().Len(suite.traps.Laps(), 1)
+	suite.Equal(int16(1), suite.traps.Laps()[0].Lap)
+	suite.Equal(int16(2), suite.traps.CurrentLap().Lap)
+	suite.Empty(suite.traps.CurrentLap().Speeds)
+}
+
+func (suite *SpeedTrapsTestSuite) TestTrapsFromPathAreEvenlySpaced() {
+	// Arrange
+	path := []models.Coordinate{{X: 0}, {X: 100}, {X: 400}}
+
+	// Act
+	traps := gttelemetry.TrapsFromPath(path, 3, 20)
+
+	// Assert
+	suite.Require().Len(traps, 3)
+	suite.Equal("T1", traps[0].Name)
+	suite.InDelta(100, traps[0].A.X, 0.001)
+	suite.InDelta(200, traps[1].A.X, 0.001)
+	suite.InDelta(300, traps[2].A.X, 0.001)
+	suite.InDelta(10, traps[0].A.Z, 0.001)
+	suite.InDelta(-10, traps[0].B.Z, 0.001)
+}
+
+func (suite *SpeedTrapsTestSuite) TestTrapsFromPathRequiresPath() {
+	// Act
+	traps := gttelemetry.TrapsFromPath([]models.Coordinate{{}}, 3, 20)
+
+	// Assert
+	suite.Empty(traps)
+}
+
+func (suite *SpeedTrapsTestSuite) TestCompareSplitsReturnsCommonTraps() {
+	// Arrange
+	a := gttelemetry.LapSplits{Speeds: map[string]float32{"T1": 50, "T2": 60}}
+	b := gttelemetry.LapSplits{Speeds: map[string]float32{"T1": 52, "T3": 70}}
+
+	// Act
+	deltas := gttelemetry.CompareSplits(a, b)
+
+	// Assert
+	suite.Equal(map[string]float32{"T1": 2}, deltas)
+}
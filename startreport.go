@@ -0,0 +1,240 @@
+package gttelemetry
+
+import "time"
+
+const (
+	// StartAnalysisDuration is the time after lights out analysed by a StartAnalyser.
+	StartAnalysisDuration = 5 * time.Second
+	// startTargetSpeedKPH is the speed the time to reach is reported by a start report.
+	startTargetSpeedKPH = 100
+	// startSlipMinimumSpeed is the ground speed in metres per second used in place of slower speeds when calculating
+	// wheel slip, so that wheelspin off the line does not produce an infinite slip ratio.
+	startSlipMinimumSpeed = 1
+)
+
+// DrivenAxle identifies the axles driven by the engine.
+type DrivenAxle string
+
+const (
+	DrivenAxleUnknown DrivenAxle = "unknown"
+	DrivenAxleFront   DrivenAxle = "front"
+	DrivenAxleRear    DrivenAxle = "rear"
+	DrivenAxleBoth    DrivenAxle = "both"
+)
+
+// AxleSlip is the wheel slip of an axle, the speed of the wheels over the ground speed minus one. Zero is no slip
+// and one is the wheels turning at twice the ground speed.
+type AxleSlip struct {
+	Peak float32 `json:"peak"`
+	Mean float32 `json:"mean"`
+}
+
+// StartSample is the state of the vehicle at a frame of a standing start.
+type StartSample struct {
+	// Elapsed is the time since lights out.
+	Elapsed         time.Duration `json:"elapsed"`
+	SpeedKPH        float32       `json:"speedKPH"`
+	ThrottlePercent float32       `json:"throttlePercent"`
+	EngineRPM       float32       `json:"engineRPM"`
+	Gear            int           `json:"gear"`
+	FrontSlip       float32       `json:"frontSlip"`
+	RearSlip        float32       `json:"rearSlip"`
+}
+
+// StartReport analyses the first seconds of a standing start, for refining launches.
+type StartReport struct {
+	// SequenceID is the frame at lights out.
+	SequenceID uint32 `json:"sequenceId"`
+	// ReactionTime is the time from lights out to the throttle being applied, zero when it was held at lights out.
+	ReactionTime time.Duration `json:"reactionTime"`
+	// TimeTo100KPH is the time from lights out to reaching 100km/h, zero when it was not reached during the analysis.
+	TimeTo100KPH time.Duration `json:"timeTo100KPH"`
+	// SpeedKPH is the speed at the end of the analysis.
+	SpeedKPH   float32       `json:"speedKPH"`
+	DrivenAxle DrivenAxle    `json:"drivenAxle"`
+	FrontSlip  AxleSlip      `json:"frontSlip"`
+	RearSlip   AxleSlip      `json:"rearSlip"`
+	Samples    []StartSample `json:"samples"`
+}
+
+// DrivenSlip returns the slip of the driven axle, or of the axle with the most slip when both or neither are driven.
+func (r StartReport) DrivenSlip() AxleSlip {
+	switch r.DrivenAxle {
+	case DrivenAxleFront:
+		return r.FrontSlip
+	case DrivenAxleRear:
+		return r.RearSlip
+	}
+
+	if r.FrontSlip.Peak > r.RearSlip.Peak {
+		return r.FrontSlip
+	}
+
+	return r.RearSlip
+}
+
+// StartAnalyser records the first StartAnalysisDuration of every standing start and reports the wheel slip of each
+// axle, the throttle trace and the time to 100km/h.
+//
+// Lights out is inferred from the lap counter changing from zero to the first lap, in the same way as
+// RaceStartDetector, and only starts where the vehicle waited stationary on the grid are analysed.
+type StartAnalyser struct {
+	lastSequence uint32
+	lastLap      int16
+	countdown    bool
+	analysing    bool
+	throttled    bool
+	frames       uint64
+	slipFrames   int
+	current      StartReport
+	reports      []StartReport
+}
+
+// NewStartAnalyser creates a new start analyser.
+func NewStartAnalyser() *StartAnalyser {
+	return &StartAnalyser{lastLap: -1}
+}
+
+// Update evaluates the latest telemetry frame and returns the report when the analysis of a start completes.
+func (a *StartAnalyser) Update(t *Transformer) (StartReport, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == a.lastSequence {
+		return StartReport{}, false
+	}
+
+	previousSequence := a.lastSequence
+	a.lastSequence = sequenceID
+
+	if t.Flags().GamePaused {
+		return StartReport{}, false
+	}
+
+	if !t.IsOnCircuit() {
+		a.lastLap = -1
+		a.countdown = false
+		a.analysing = false
+
+		return StartReport{}, false
+	}
+
+	lap := t.CurrentLap()
+	lastLap := a.lastLap
+	a.lastLap = lap
+
+	switch {
+	case lap == 0:
+		a.countdown = t.GroundSpeedMetresPerSecond() < raceStartStationarySpeed
+		a.analysing = false
+	case lastLap == 0:
+		standing := classifyStart(a.countdown, t.GroundSpeedMetresPerSecond()) == StartTypeStanding
+		a.countdown = false
+
+		if standing {
+			a.begin(t)
+		}
+	case a.analysing:
+		if sequenceID > previousSequence {
+			a.frames += uint64(sequenceID - previousSequence)
+		}
+
+		if framesDuration(a.frames) > StartAnalysisDuration {
+			return a.complete(), true
+		}
+
+		a.sample(t)
+	}
+
+	return StartReport{}, false
+}
+
+// Reports returns the reports of all starts analysed so far.
+func (a *StartAnalyser) Reports() []StartReport {
+	return a.reports
+}
+
+func (a *StartAnalyser) begin(t *Transformer) {
+	a.analysing = true
+	a.throttled = false
+	a.frames = 0
+	a.slipFrames = 0
+	a.current = StartReport{
+		SequenceID: t.SequenceID(),
+		DrivenAxle: drivenAxle(t.VehicleDrivetrain()),
+		Samples:    make([]StartSample, 0, int(StartAnalysisDuration/FrameInterval)+1),
+	}
+
+	a.sample(t)
+}
+
+func (a *StartAnalyser) sample(t *Transformer) {
+	elapsed := framesDuration(a.frames)
+	frontSlip, rearSlip := axleSlip(t)
+	sample := StartSample{
+		Elapsed:         elapsed,
+		SpeedKPH:        t.GroundSpeedKPH(),
+		ThrottlePercent: t.ThrottleInputPercent(),
+		EngineRPM:       t.EngineRPM(),
+		Gear:            t.CurrentGear(),
+		FrontSlip:       frontSlip,
+		RearSlip:        rearSlip,
+	}
+
+	a.current.Samples = append(a.current.Samples, sample)
+	a.current.SpeedKPH = sample.SpeedKPH
+
+	if !a.throttled && sample.ThrottlePercent >= raceStartThrottlePercent {
+		a.throttled = true
+		a.current.ReactionTime = elapsed
+	}
+
+	if a.current.TimeTo100KPH == 0 && sample.SpeedKPH >= startTargetSpeedKPH {
+		a.current.TimeTo100KPH = elapsed
+	}
+
+	// slip is only meaningful once the driver is trying to accelerate
+	if a.throttled {
+		a.slipFrames++
+		a.current.FrontSlip = addSlip(a.current.FrontSlip, frontSlip, a.slipFrames)
+		a.current.RearSlip = addSlip(a.current.RearSlip, rearSlip, a.slipFrames)
+	}
+}
+
+func (a *StartAnalyser) complete() StartReport {
+	a.analysing = false
+	a.reports = append(a.reports, a.current)
+
+	return a.current
+}
+
+// addSlip adds the slip of a frame to the peak and running mean of an axle.
+func addSlip(slip AxleSlip, value float32, frames int) AxleSlip {
+	slip.Peak = max(slip.Peak, value)
+	slip.Mean += (value - slip.Mean) / float32(frames)
+
+	return slip
+}
+
+// axleSlip returns the mean slip of the front and rear wheels.
+func axleSlip(t *Transformer) (float32, float32) {
+	ground := max(t.GroundSpeedMetresPerSecond(), startSlipMinimumSpeed)
+	wheels := t.WheelSpeedMetresPerSecond()
+
+	front := (wheels.FrontLeft+wheels.FrontRight)/2/ground - 1
+	rear := (wheels.RearLeft+wheels.RearRight)/2/ground - 1
+
+	return max(front, 0), max(rear, 0)
+}
+
+// drivenAxle returns the driven axles of a drivetrain layout from the vehicle inventory.
+func drivenAxle(drivetrain string) DrivenAxle {
+	switch drivetrain {
+	case "FF":
+		return DrivenAxleFront
+	case "FR", "MR", "RR":
+		return DrivenAxleRear
+	case "4WD":
+		return DrivenAxleBoth
+	default:
+		return DrivenAxleUnknown
+	}
+}
@@ -0,0 +1,118 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type StartAnalyserTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	analyser    *gttelemetry.StartAnalyser
+}
+
+func TestStartAnalyserTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StartAnalyserTestSuite))
+}
+
+func (suite *StartAnalyserTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.RaceLaps = 5
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.transformer.SetTyreRadius(0.3, 0.3, 0.3, 0.3)
+
+	suite.analyser = gttelemetry.NewStartAnalyser()
+}
+
+// frame updates the analyser with a frame where the rear wheels turn at the given multiple of the ground speed.
+func (suite *StartAnalyserTestSuite) frame(lap int16, speed float32, throttle uint8, rearSlip float32) (gttelemetry.StartReport, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.CurrentLap = lap
+	suite.transformer.RawTelemetry.GroundSpeed = speed
+	suite.transformer.RawTelemetry.ThrottleInput = throttle
+	suite.transformer.SetWheelRadiansPerSecond(speed/0.3, speed/0.3, speed*rearSlip/0.3, speed*rearSlip/0.3)
+	suite.transformer.SetVehicle(vehicles.Vehicle{Drivetrain: "FR"})
+
+	return suite.analyser.Update(suite.transformer)
+}
+
+func (suite *StartAnalyserTestSuite) TestStandingStartIsAnalysed() {
+	// Arrange
+	suite.frame(0, 0, 0, 1)
+	suite.frame(1, 0, 0, 1)
+
+	var (
+		report gttelemetry.StartReport
+		ok     bool
+	)
+
+	// Act
+	for frame := 1; !ok && frame < gttelemetry.FrameRate*10; frame++ {
+		speed := float32(frame) / gttelemetry.FrameRate * 7
+		slip := float32(1)
+
+		if frame > 12 && frame <= 42 {
+			slip = 1.5
+		}
+
+		report, ok = suite.frame(1, speed, 255, slip)
+	}
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(gttelemetry.DrivenAxleRear, report.DrivenAxle)
+	suite.Equal(gttelemetry.FrameInterval, report.ReactionTime)
+	suite.InDelta(4, report.TimeTo100KPH.Seconds(), 0.02)
+	suite.InDelta(126, report.SpeedKPH, 0.1)
+	suite.InDelta(0.5, report.RearSlip.Peak, 0.001)
+	suite.InDelta(0.05, report.RearSlip.Mean, 0.0001)
+	suite.InDelta(0, report.FrontSlip.Peak, 0.001)
+	suite.Equal(report.RearSlip, report.DrivenSlip())
+	suite.Len(report.Samples, gttelemetry.FrameRate*5+1)
+	suite.Equal(5*time.Second, report.Samples[len(report.Samples)-1].Elapsed)
+	suite.Len(suite.analyser.Reports(), 1)
+}
+
+func (suite *StartAnalyserTestSuite) TestRollingStartIsIgnored() {
+	// Arrange
+	suite.frame(0, 30, 200, 1)
+	suite.frame(1, 35, 255, 1)
+
+	// Act
+	var ok bool
+	for range gttelemetry.FrameRate * 6 {
+		_, ok = suite.frame(1, 40, 255, 1)
+		if ok {
+			break
+		}
+	}
+
+	// Assert
+	suite.False(ok)
+	suite.Empty(suite.analyser.Reports())
+}
+
+func (suite *StartAnalyserTestSuite) TestDrivenSlipOfFourWheelDrive() {
+	// Arrange
+	report := gttelemetry.StartReport{
+		DrivenAxle: gttelemetry.DrivenAxleBoth,
+		FrontSlip:  gttelemetry.AxleSlip{Peak: 0.3},
+		RearSlip:   gttelemetry.AxleSlip{Peak: 0.2},
+	}
+
+	// Act
+	slip := report.DrivenSlip()
+
+	// Assert
+	suite.InDelta(0.3, slip.Peak, 0.001)
+}
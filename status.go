@@ -0,0 +1,130 @@
+package gttelemetry
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/units"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// DefaultStatusInterval is the minimum time between status lines when no interval is given.
+const DefaultStatusInterval = 5 * time.Second
+
+// StatusOptions configures a StatusFormatter.
+type StatusOptions struct {
+	// Interval is the minimum time between status lines, defaulting to DefaultStatusInterval.
+	Interval time.Duration
+	// Imperial shows the speed in mph rather than km/h.
+	Imperial bool
+}
+
+// StatusFormatter produces a compact single line status of the vehicle, such as
+// "Gear 4 | 187 km/h | Lap 3/10 | Last 1:31.204 (+0.412)", at a limited rate for chat bots and stream titles.
+//
+// Time is counted in frames rather than with the wall clock, as for OverlayTrack, so a recording replayed at any speed
+// produces the same lines.
+type StatusFormatter struct {
+	interval     time.Duration
+	imperial     bool
+	lastSequence uint32
+	started      bool
+	frames       uint64
+	nextLine     time.Duration
+	lastLine     string
+}
+
+// NewStatusFormatter creates a status formatter.
+func NewStatusFormatter(opts StatusOptions) *StatusFormatter {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultStatusInterval
+	}
+
+	return &StatusFormatter{
+		interval: opts.Interval,
+		imperial: opts.Imperial,
+	}
+}
+
+// Update evaluates the latest telemetry frame and returns a status line when at least one interval has passed since
+// the last line and the status has changed, so that unchanged lines are not posted again. Repeated frames are ignored.
+func (s *StatusFormatter) Update(t *Transformer) (string, bool) {
+	sequenceID := t.SequenceID()
+	if s.started && sequenceID == s.lastSequence {
+		return "", false
+	}
+
+	if s.started && sequenceID > s.lastSequence {
+		s.frames += uint64(sequenceID - s.lastSequence)
+	}
+
+	s.started = true
+	s.lastSequence = sequenceID
+
+	elapsed := framesDuration(s.frames)
+	if elapsed < s.nextLine {
+		return "", false
+	}
+
+	line := s.Format(t)
+	if line == s.lastLine {
+		return "", false
+	}
+
+	s.lastLine = line
+	s.nextLine = elapsed + s.interval
+
+	return line, true
+}
+
+// Format returns the status line of a frame straight away, without rate limiting. Off circuit the line names the
+// menu the game is in. The delta is the time of the last lap against the best lap of the session.
+func (s *StatusFormatter) Format(t *Transformer) string {
+	switch t.GameState() {
+	case models.GameStateMainMenu:
+		return "In the main menu"
+	case models.GameStateRaceMenu:
+		return "In the race menu"
+	case models.GameStateUnknown:
+		return "Waiting for telemetry"
+	}
+
+	speed := fmt.Sprintf("%.0f km/h", t.GroundSpeedKPH())
+	if s.imperial {
+		speed = fmt.Sprintf("%.0f mph", units.MetresPerSecondToMilesPerHour(t.GroundSpeedMetresPerSecond()))
+	}
+
+	parts := []string{"Gear " + t.CurrentGearString(), speed}
+
+	switch {
+	case t.CurrentLap() < 1:
+		parts = append(parts, "Out lap")
+	case t.RaceLaps() > 0:
+		parts = append(parts, fmt.Sprintf("Lap %d/%d", min(t.CurrentLap(), t.RaceLaps()), t.RaceLaps()))
+	default:
+		parts = append(parts, fmt.Sprintf("Lap %d", t.CurrentLap()))
+	}
+
+	if last := t.LastLaptime(); last > 0 {
+		parts = append(parts, "Last "+formatLaptime(last)+statusDelta(last, t.BestLaptime()))
+	}
+
+	if t.GameState() == models.GameStateReplay {
+		parts = append(parts, "Replay")
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+// statusDelta returns the delta of a lap to the best lap in brackets, or marks the lap as the best.
+func statusDelta(last, best time.Duration) string {
+	switch {
+	case best <= 0:
+		return ""
+	case last == best:
+		return " (best)"
+	default:
+		return fmt.Sprintf(" (%+.3f)", (last - best).Seconds())
+	}
+}
@@ -0,0 +1,143 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type StatusTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestStatusTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StatusTestSuite))
+}
+
+func (suite *StatusTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.transformer.SetTransmissionGear(4, 15)
+	suite.transformer.RawTelemetry.GroundSpeed = 52
+	suite.transformer.RawTelemetry.RaceLaps = 10
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+	suite.transformer.RawTelemetry.CurrentLap = 3
+	suite.transformer.RawTelemetry.LastLaptime = 91204
+	suite.transformer.RawTelemetry.BestLaptime = 90792
+}
+
+func (suite *StatusTestSuite) TestFormat() {
+	tests := map[string]struct {
+		modify   func(raw *gttelemetry.Transformer)
+		imperial bool
+		want     string
+	}{
+		"race": {
+			want: "Gear 4 | 187 km/h | Lap 3/10 | Last 1:31.204 (+0.412)",
+		},
+		"imperial": {
+			imperial: true,
+			want:     "Gear 4 | 116 mph | Lap 3/10 | Last 1:31.204 (+0.412)",
+		},
+		"best lap": {
+			modify: func(t *gttelemetry.Transformer) { t.RawTelemetry.BestLaptime = 91204 },
+			want:   "Gear 4 | 187 km/h | Lap 3/10 | Last 1:31.204 (best)",
+		},
+		"time trial": {
+			modify: func(t *gttelemetry.Transformer) { t.RawTelemetry.RaceLaps = 0 },
+			want:   "Gear 4 | 187 km/h | Lap 3 | Last 1:31.204 (+0.412)",
+		},
+		"out lap": {
+			modify: func(t *gttelemetry.Transformer) {
+				t.RawTelemetry.CurrentLap = 0
+				t.RawTelemetry.LastLaptime = -1
+			},
+			want: "Gear 4 | 187 km/h | Out lap",
+		},
+		"replay": {
+			modify: func(t *gttelemetry.Transformer) {
+				t.SetFlags(false, false, false, true, false, false, false, false, false, false, false, false)
+			},
+			want: "Gear 4 | 187 km/h | Lap 3/10 | Last 1:31.204 (+0.412) | Replay",
+		},
+		"main menu": {
+			modify: func(t *gttelemetry.Transformer) {
+				t.RawTelemetry.RaceLaps = -1
+				t.RawTelemetry.RaceEntrants = -1
+			},
+			want: "In the main menu",
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			suite.SetupTest()
+
+			if test.modify != nil {
+				test.modify(suite.transformer)
+			}
+
+			formatter := gttelemetry.NewStatusFormatter(gttelemetry.StatusOptions{Imperial: test.imperial})
+
+			// Act
+			line := formatter.Format(suite.transformer)
+
+			// Assert
+			suite.Equal(test.want, line)
+		})
+	}
+}
+
+func (suite *StatusTestSuite) TestUpdateLimitsRate() {
+	// Arrange
+	formatter := gttelemetry.NewStatusFormatter(gttelemetry.StatusOptions{Interval: time.Second})
+
+	var lines []string
+
+	// Act
+	for frame := range gttelemetry.FrameRate * 3 {
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.transformer.RawTelemetry.GroundSpeed = 50 + float32(frame)/10
+
+		line, ok := formatter.Update(suite.transformer)
+		if ok {
+			lines = append(lines, line)
+		}
+	}
+
+	// Assert
+	suite.Equal([]string{
+		"Gear 4 | 180 km/h | Lap 3/10 | Last 1:31.204 (+0.412)",
+		"Gear 4 | 202 km/h | Lap 3/10 | Last 1:31.204 (+0.412)",
+		"Gear 4 | 223 km/h | Lap 3/10 | Last 1:31.204 (+0.412)",
+	}, lines)
+}
+
+func (suite *StatusTestSuite) TestUpdateSkipsUnchangedLines() {
+	// Arrange
+	formatter := gttelemetry.NewStatusFormatter(gttelemetry.StatusOptions{Interval: time.Second})
+
+	var count int
+
+	// Act
+	for range gttelemetry.FrameRate * 3 {
+		suite.transformer.RawTelemetry.SequenceId++
+
+		_, ok := formatter.Update(suite.transformer)
+		if ok {
+			count++
+		}
+	}
+
+	// Assert
+	suite.Equal(1, count)
+}
@@ -0,0 +1,50 @@
+package gttelemetry
+
+import "math"
+
+const (
+	// steeringRatioMinSpeed is the slowest speed, in metres per second, at which the steering ratio is estimated.
+	// Below it the yaw rate is too small to resolve the wheel angle.
+	steeringRatioMinSpeed = 5
+	// steeringRatioMinWheelAngle is the smallest front wheel angle, in radians, at which the steering ratio is
+	// estimated, as the ratio of two angles close to zero is dominated by noise.
+	steeringRatioMinWheelAngle = 0.02
+)
+
+// SteeringRatioEstimate is the ratio of the steering wheel angle to the front wheel angle, estimated from the motion
+// of the car. Valid is false when the car is too slow or too close to straight for an estimate, or its wheelbase is
+// unknown, in which case the other fields are zero.
+type SteeringRatioEstimate struct {
+	// Ratio is the steering wheel angle divided by the front wheel angle, e.g. 12 for a 12:1 steering ratio.
+	Ratio float32 `json:"ratio"`
+	// FrontWheelAngleRadians is the front wheel angle calculated from the yaw rate and speed.
+	FrontWheelAngleRadians float32 `json:"frontWheelAngleRadians"`
+	Valid                  bool    `json:"valid"`
+}
+
+// SteeringRatioEstimate compares the steering wheel angle with the front wheel angle calculated from the yaw rate,
+// speed and wheelbase of the car, for checking that the rotation of a wheel matches the car. The wheel angle follows
+// the kinematic bicycle model, which ignores tyre slip, so the estimate is most accurate through slow, steady corners
+// and reads high when the car understeers. Only the magnitudes of the angles are compared.
+func (t *Transformer) SteeringRatioEstimate() SteeringRatioEstimate {
+	speed := float64(t.GroundSpeedMetresPerSecond())
+	wheelbase := float64(t.VehicleWheelbaseMillimetres()) / 1000
+	steering := math.Abs(float64(t.SteeringWheelAngleRadians()))
+
+	if speed < steeringRatioMinSpeed || wheelbase <= 0 || steering == 0 {
+		return SteeringRatioEstimate{}
+	}
+
+	yawRate := math.Abs(float64(t.AngularVelocityVector().Y))
+	wheelAngle := math.Atan(wheelbase * yawRate / speed)
+
+	if wheelAngle < steeringRatioMinWheelAngle {
+		return SteeringRatioEstimate{}
+	}
+
+	return SteeringRatioEstimate{
+		Ratio:                  float32(steering / wheelAngle),
+		FrontWheelAngleRadians: float32(wheelAngle),
+		Valid:                  true,
+	}
+}
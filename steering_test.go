@@ -0,0 +1,84 @@
+package gttelemetry_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type SteeringTestSuite struct {
+	suite.Suite
+}
+
+func TestSteeringTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SteeringTestSuite))
+}
+
+func (suite *SteeringTestSuite) TestSteeringRatioEstimate() {
+	// A 2.7m wheelbase turning at 0.5rad/s at 20m/s has a front wheel angle of atan(2.7 * 0.5 / 20)
+	wheelAngle := math.Atan(2.7 * 0.5 / 20)
+
+	tests := map[string]struct {
+		wheelbase int
+		speed     float32
+		yawRate   float32
+		steering  float32
+		want      gttelemetry.SteeringRatioEstimate
+	}{
+		"left turn": {
+			wheelbase: 2700,
+			speed:     20,
+			yawRate:   0.5,
+			steering:  float32(12 * wheelAngle),
+			want:      gttelemetry.SteeringRatioEstimate{Ratio: 12, FrontWheelAngleRadians: float32(wheelAngle), Valid: true},
+		},
+		"right turn": {
+			wheelbase: 2700,
+			speed:     20,
+			yawRate:   -0.5,
+			steering:  float32(-12 * wheelAngle),
+			want:      gttelemetry.SteeringRatioEstimate{Ratio: 12, FrontWheelAngleRadians: float32(wheelAngle), Valid: true},
+		},
+		"too slow": {
+			wheelbase: 2700,
+			speed:     2,
+			yawRate:   0.5,
+			steering:  1,
+		},
+		"straight": {
+			wheelbase: 2700,
+			speed:     20,
+			yawRate:   0.01,
+			steering:  0.05,
+		},
+		"unknown wheelbase": {
+			speed:    20,
+			yawRate:  0.5,
+			steering: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			transformer := gttelemetry.NewTransformer(nil)
+			transformer.RawTelemetry.SequenceId = 1
+			transformer.SetVehicle(vehicles.Vehicle{Wheelbase: tc.wheelbase})
+			transformer.RawTelemetry.GroundSpeed = tc.speed
+			transformer.RawTelemetry.SteeringWheelAngleRadians = tc.steering
+			transformer.SetAngularVelocityVector(0, tc.yawRate, 0)
+
+			// Act
+			got := transformer.SteeringRatioEstimate()
+
+			// Assert
+			suite.Equal(tc.want.Valid, got.Valid)
+			suite.InDelta(tc.want.Ratio, got.Ratio, 0.001)
+			suite.InDelta(tc.want.FrontWheelAngleRadians, got.FrontWheelAngleRadians, 0.0001)
+		})
+	}
+}
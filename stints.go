@@ -0,0 +1,178 @@
+package gttelemetry
+
+import (
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// Stint describes the driving between pit stops, from the start of the session or the end of a pit stop to the end of
+// the next pit stop or the stint in progress.
+type Stint struct {
+	Number          int    `json:"number"`
+	StartLap        int16  `json:"startLap"`
+	EndLap          int16  `json:"endLap"`
+	StartSequenceID uint32 `json:"startSequenceId"`
+	EndSequenceID   uint32 `json:"endSequenceId"`
+	// Laps is the number of laps completed during the stint. A lap belongs to the stint in which it was completed, so
+	// the lap of a pit stop that crosses the line after the stop belongs to the next stint.
+	Laps int `json:"laps"`
+	// Duration is the time driven during the stint, excluding pauses.
+	Duration time.Duration `json:"duration"`
+	// AveragePace is the mean lap time of the laps completed during the stint, leaving out laps driven across a pause
+	// or packet loss.
+	AveragePace time.Duration `json:"averagePace"`
+	FuelStart   float32       `json:"fuelStart"`
+	FuelUsed    float32       `json:"fuelUsed"`
+	// FuelPerLap is the mean fuel used on each lap completed during the stint.
+	FuelPerLap float32 `json:"fuelPerLap"`
+	// TyreTemperatureStart and TyreTemperatureEnd are the tyre temperatures as the first and last laps of the stint
+	// were completed.
+	TyreTemperatureStart models.CornerSet `json:"tyreTemperatureStart"`
+	TyreTemperatureEnd   models.CornerSet `json:"tyreTemperatureEnd"`
+	// TyreTemperatureTrend is the change in the average tyre temperature per lap over the stint, in degrees Celsius.
+	TyreTemperatureTrend float32 `json:"tyreTemperatureTrend"`
+	// PitStop is the stop that ended the stint, nil for the stint in progress.
+	PitStop *PitStop `json:"pitStop,omitempty"`
+}
+
+// StintTracker splits a session into stints at each serviced pit stop for endurance race planning, such as how many
+// laps a stint lasts on a tank of fuel and how the tyres fade over a stint.
+//
+// Pit stops are detected by a PitStopDetector, so stops without service do not end a stint. A new session, when the
+// lap counter goes back, clears the stints of the previous session.
+type StintTracker struct {
+	lastSequence uint32
+	pitStops     *PitStopDetector
+	history      *LapHistory
+	current      Stint
+	frames       uint64
+	paceSum      time.Duration
+	paceLaps     int
+	fuelLaps     float32
+	trendLaps    []float64
+	trendTemps   []float64
+	stints       []Stint
+}
+
+// NewStintTracker creates a tracker without any stints.
+func NewStintTracker() *StintTracker {
+	// Only the snapshot of the lap just completed is needed, so the history holds no more than one lap
+	history := NewLapHistory()
+	history.SetMaxRetainedLaps(1)
+
+	return &StintTracker{
+		pitStops: NewPitStopDetector(),
+		history:  history,
+	}
+}
+
+// Update evaluates the latest telemetry frame and returns the stint when a pit stop has just ended it.
+func (s *StintTracker) Update(t *Transformer) (Stint, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == s.lastSequence {
+		return Stint{}, false
+	}
+
+	s.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() {
+		s.history.Update(t)
+		s.pitStops.Update(t)
+
+		return Stint{}, false
+	}
+
+	lap := t.CurrentLap()
+	if s.current.Number == 0 || lap < s.current.StartLap {
+		s.stints = nil
+		s.startStint(t, 1)
+	}
+
+	if flags := t.Flags(); flags.Live && !flags.GamePaused {
+		s.frames++
+	}
+
+	s.current.EndLap = lap
+	s.current.EndSequenceID = sequenceID
+	s.current.Duration = t.CorrectedDuration(framesDuration(s.frames))
+	s.current.FuelUsed = s.current.FuelStart - t.FuelLevel()
+
+	if snapshot, completed := s.history.Update(t); completed {
+		s.addLap(snapshot)
+	}
+
+	stop, stopped := s.pitStops.Update(t)
+	if !stopped {
+		return Stint{}, false
+	}
+
+	// The fuel level already includes the fuel added during the stop
+	s.current.FuelUsed = s.current.FuelStart - (t.FuelLevel() - stop.FuelAdded)
+	s.current.PitStop = &stop
+
+	stint := s.current
+	s.stints = append(s.stints, stint)
+	s.startStint(t, stint.Number+1)
+
+	return stint, true
+}
+
+// CurrentStint returns the stint in progress, with a zero Number before the vehicle is on circuit.
+func (s *StintTracker) CurrentStint() Stint {
+	return s.current
+}
+
+// StintHistory returns the stints of the session that have ended at a pit stop, in order.
+func (s *StintTracker) StintHistory() []Stint {
+	return s.stints
+}
+
+func (s *StintTracker) startStint(t *Transformer, number int) {
+	s.current = Stint{
+		Number:          number,
+		StartLap:        t.CurrentLap(),
+		EndLap:          t.CurrentLap(),
+		StartSequenceID: t.SequenceID(),
+		EndSequenceID:   t.SequenceID(),
+		FuelStart:       t.FuelLevel(),
+	}
+	s.frames = 0
+	s.paceSum = 0
+	s.paceLaps = 0
+	s.fuelLaps = 0
+	s.trendLaps = s.trendLaps[:0]
+	s.trendTemps = s.trendTemps[:0]
+}
+
+// addLap adds a lap completed during the stint to its pace, fuel and tyre temperature trend.
+func (s *StintTracker) addLap(snapshot LapSnapshot) {
+	s.current.Laps++
+
+	if snapshot.Laptime > 0 && len(snapshot.Gaps) == 0 {
+		s.paceSum += snapshot.Laptime
+		s.paceLaps++
+		s.current.AveragePace = s.paceSum / time.Duration(s.paceLaps)
+	}
+
+	// Laps with a refuel report a negative fuel use
+	if snapshot.FuelUsed > 0 {
+		s.fuelLaps++
+		s.current.FuelPerLap += (snapshot.FuelUsed - s.current.FuelPerLap) / s.fuelLaps
+	}
+
+	if s.current.Laps == 1 {
+		s.current.TyreTemperatureStart = snapshot.TyreTemperature
+	}
+
+	s.current.TyreTemperatureEnd = snapshot.TyreTemperature
+
+	temps := snapshot.TyreTemperature
+	s.trendLaps = append(s.trendLaps, float64(snapshot.Lap))
+	s.trendTemps = append(s.trendTemps, float64(temps.FrontLeft+temps.FrontRight+temps.RearLeft+temps.RearRight)/4)
+
+	if len(s.trendLaps) > 1 {
+		slope, _ := linearFit(s.trendLaps, s.trendTemps)
+		s.current.TyreTemperatureTrend = float32(slope)
+	}
+}
@@ -0,0 +1,152 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type StintTrackerTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	tracker     *gttelemetry.StintTracker
+}
+
+func TestStintTrackerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StintTrackerTestSuite))
+}
+
+func (suite *StintTrackerTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.transformer.RawTelemetry.FuelLevel = 50
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+	suite.transformer.SetTyreTemperature(80, 80, 80, 80)
+
+	suite.tracker = gttelemetry.NewStintTracker()
+}
+
+func (suite *StintTrackerTestSuite) frame(speed float32) (gttelemetry.Stint, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.GroundSpeed = speed
+
+	return suite.tracker.Update(suite.transformer)
+}
+
+// lap drives a second of a lap using 3 litres of fuel and completes it in the lap time, two degrees warmer.
+func (suite *StintTrackerTestSuite) lap(laptime time.Duration) {
+	for range gttelemetry.FrameRate {
+		suite.frame(40)
+		suite.transformer.RawTelemetry.FuelLevel -= 0.05
+	}
+
+	lap := float32(suite.transformer.RawTelemetry.CurrentLap)
+	suite.transformer.RawTelemetry.CurrentLap++
+	suite.transformer.RawTelemetry.LastLaptime = int32(laptime.Milliseconds())
+	suite.transformer.SetTyreTemperature(80+2*lap, 80+2*lap, 80+2*lap, 80+2*lap)
+}
+
+// pitStop stops for a second, adding fuel and fitting new tyres when asked, and returns the stint ended as it leaves.
+func (suite *StintTrackerTestSuite) pitStop(fuel float32, tyres bool) (gttelemetry.Stint, bool) {
+	suite.frame(0)
+
+	if tyres {
+		suite.transformer.SetTyreTemperature(50, 50, 50, 50)
+	}
+
+	for range gttelemetry.FrameRate {
+		suite.transformer.RawTelemetry.FuelLevel += fuel / gttelemetry.FrameRate
+		suite.frame(0)
+	}
+
+	return suite.frame(5)
+}
+
+func (suite *StintTrackerTestSuite) TestPitStopEndsStint() {
+	// Arrange
+	suite.lap(90 * time.Second)
+	suite.lap(91 * time.Second)
+	suite.lap(92 * time.Second)
+
+	// Act
+	stint, ended := suite.pitStop(30, true)
+
+	// Assert
+	suite.Require().True(ended)
+	suite.Equal(1, stint.Number)
+	suite.Equal(int16(1), stint.StartLap)
+	suite.Equal(int16(4), stint.EndLap)
+	suite.Equal(3, stint.Laps)
+	suite.Equal(91*time.Second, stint.AveragePace)
+	suite.InDelta(50, stint.FuelStart, 0.001)
+	suite.InDelta(9, stint.FuelUsed, 0.01)
+	suite.InDelta(3, stint.FuelPerLap, 0.01)
+	suite.InDelta(82, stint.TyreTemperatureStart.FrontLeft, 0.001)
+	suite.InDelta(86, stint.TyreTemperatureEnd.FrontLeft, 0.001)
+	suite.InDelta(2, stint.TyreTemperatureTrend, 0.001)
+	suite.GreaterOrEqual(stint.Duration, 4*time.Second)
+	suite.Require().NotNil(stint.PitStop)
+	suite.True(stint.PitStop.TyresChanged)
+	suite.Equal([]gttelemetry.Stint{stint}, suite.tracker.StintHistory())
+
+	current := suite.tracker.CurrentStint()
+	suite.Equal(2, current.Number)
+	suite.Equal(int16(4), current.StartLap)
+	suite.InDelta(71, current.FuelStart, 0.01)
+	suite.Zero(current.Laps)
+	suite.Nil(current.PitStop)
+}
+
+func (suite *StintTrackerTestSuite) TestCurrentStintIsUpdatedEachLap() {
+	// Arrange
+	suite.lap(90 * time.Second)
+
+	// Act
+	suite.lap(92 * time.Second)
+	suite.frame(40)
+
+	// Assert
+	current := suite.tracker.CurrentStint()
+	suite.Equal(1, current.Number)
+	suite.Equal(2, current.Laps)
+	suite.Equal(int16(3), current.EndLap)
+	suite.Equal(91*time.Second, current.AveragePace)
+	suite.InDelta(6, current.FuelUsed, 0.01)
+	suite.Empty(suite.tracker.StintHistory())
+}
+
+func (suite *StintTrackerTestSuite) TestStopWithoutServiceDoesNotEndStint() {
+	// Arrange
+	suite.lap(90 * time.Second)
+
+	// Act
+	_, ended := suite.pitStop(0, false)
+
+	// Assert
+	suite.False(ended)
+	suite.Equal(1, suite.tracker.CurrentStint().Number)
+}
+
+func (suite *StintTrackerTestSuite) TestNewSessionClearsStints() {
+	// Arrange
+	suite.lap(90 * time.Second)
+	suite.lap(90 * time.Second)
+	suite.pitStop(30, false)
+
+	// Act
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.frame(40)
+
+	// Assert
+	suite.Empty(suite.tracker.StintHistory())
+	suite.Equal(1, suite.tracker.CurrentStint().Number)
+	suite.Equal(int16(1), suite.tracker.CurrentStint().StartLap)
+}
@@ -8,14 +8,19 @@ import (
 	"fmt"
 	"io"
 	"iter"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kaitai-io/kaitai_struct_go_runtime/kaitai"
-	"github.com/rs/zerolog"
+	"github.com/zetetos/gt-telemetry/v2/internal/encryption"
+	"github.com/zetetos/gt-telemetry/v2/internal/logging"
 	"github.com/zetetos/gt-telemetry/v2/internal/reader"
 	"github.com/zetetos/gt-telemetry/v2/internal/telemetry"
 	"github.com/zetetos/gt-telemetry/v2/pkg/circuits"
@@ -35,6 +40,7 @@ const (
 	recordingStateNone      recordingState = iota
 	recordingStateOnCircuit                // vehicle is live on circuit, not in any menu
 	recordingStateRaceMenu                 // vehicle is loaded but in the race menu
+	recordingStatePending                  // read from the next frame, on the goroutine decoding the stream
 )
 
 var (
@@ -43,13 +49,29 @@ var (
 	ErrRecordingAlreadyInProgress = errors.New("recording already in progress")
 	ErrUnsupportedFileExtension   = errors.New("unsupported file extension, use either .gtr or .gtz")
 	ErrNoRecordingInProgress      = errors.New("no recording in progress")
+	ErrUnsupportedFormat          = reader.ErrUnsupportedFormat
+	ErrFormatRequestNotSupported  = errors.New("source does not support format requests")
+	// ErrRecordingEncrypted indicates an encrypted recording read without Options.RecordingPassword.
+	ErrRecordingEncrypted = reader.ErrRecordingEncrypted
+	// ErrRecordingDecryption indicates an encrypted recording that could not be decrypted, because the password is
+	// wrong or the recording is corrupt.
+	ErrRecordingDecryption = encryption.ErrDecryptionFailed
+	// ErrRecordingNotEncryptable indicates a plain gtr recording started with a recording password, only compressed
+	// gtz recordings can be encrypted.
+	ErrRecordingNotEncryptable = errors.New("only gtz recordings can be encrypted")
 )
 
+// FanOutStats holds the delivery counters of a fan-out target.
+type FanOutStats = reader.FanOutStats
+
 type statistics struct {
+	// mu guards the counters against concurrent reads for expvar, the client updates them from a single goroutine
+	mu                sync.Mutex
 	enabled           bool
 	decodeTimeLast    time.Duration
 	packetRateLast    time.Time
 	packetIDLast      uint32
+	fanOut            *reader.FanOut
 	DecodeTimeAvg     time.Duration
 	DecodeTimeMax     time.Duration
 	PacketRateAvg     int
@@ -59,35 +81,134 @@ type statistics struct {
 	PacketsInvalid    int
 	PacketsTotal      int
 	PacketSize        int
+	// PacketsUnexpectedSource counts packets received from an address other than the console of a UDP source, such
+	// as another console broadcasting on the same network. The packets are still processed.
+	PacketsUnexpectedSource int
 }
 
 type Options struct {
 	Source        string
-	Format        models.Name
+	Format        models.Name // leave empty to negotiate the richest format sent by the console
 	LogLevel      string
-	Logger        *zerolog.Logger
+	Logger        *logging.Logger // a *zerolog.Logger, except in TinyGo builds where it is left unset
 	StatsEnabled  bool
 	CachePath     string
 	UpdateBaseURL string
 	VehicleDB     string // TODO: remove in future release, overrides can be added to cache
+	// VehicleOverrides is a JSON file of vehicle fields that override the inventory, keyed by car ID.
+	VehicleOverrides string
+	// CustomCircuitsDir is a directory of user circuit files, in the inventory format, layered over the inventory.
+	CustomCircuitsDir string
+	// LowLatency busy polls the UDP socket from a goroutine pinned to its OS thread, trading a CPU core for
+	// lower and more consistent receive latency. Intended for motion rigs and other latency sensitive consumers.
+	LowLatency bool
+	// RealtimeScheduling locks the goroutine decoding the stream to its OS thread and raises the priority of the
+	// thread as far as the operating system permits, reducing the jitter seen by motion and force feedback consumers.
+	// Realtime priority needs CAP_SYS_NICE or a raised RLIMIT_RTPRIO on Linux, the priority achieved is reported by
+	// Client.Scheduling.
+	RealtimeScheduling bool
+	// ReceiveBufferSize sets the size of the UDP receive buffer in bytes, leave zero for the operating system default.
+	// Increase it if bursts of packets are dropped, the default buffer on Windows is particularly small.
+	ReceiveBufferSize int
+	// ReusePort allows other applications to listen for telemetry on the same port at the same time.
+	ReusePort bool
+	// Interface is the name or IP address of the network interface to listen on, leave empty to listen on all.
+	Interface string
+	// FanOut is a list of host:port addresses that packets received from the console are forwarded to, still
+	// encrypted, so that other applications such as SimHub can receive telemetry at the same time. Only packets
+	// received from a UDP source are forwarded.
+	FanOut []string
+	// RecordingDir enables automatic recording, each session on circuit or replay is recorded to a new file in this
+	// directory.
+	RecordingDir string
+	// RecordingFormat is the file format of automatic recordings, either gtz for compressed or gtr for plain files.
+	// Defaults to gtz.
+	RecordingFormat string
+	// ExpvarName publishes the client statistics with expvar under this name, e.g. "gttelemetry", and enables the
+	// collection of statistics. A client created later with the same name replaces the published statistics.
+	ExpvarName string
+	// Filters smooths noisy channels, mapping channel names to the time constant of an exponential moving average.
+	// Smoothed values are read with Transformer.Filtered, raw values remain available from the other accessors.
+	Filters map[string]time.Duration
+	// FinalDrive configures the estimation of the final drive ratio returned by Transformer.FinalDriveEstimate.
+	FinalDrive FinalDriveOptions
+	// DisableInventories skips loading the embedded vehicle and circuit inventories and any cached updates, for
+	// minimal deployments such as small containers and embedded gateways where memory is scarce. Vehicle getters
+	// then return zero values and no circuits are identified. VehicleDB, VehicleOverrides, CustomCircuitsDir and
+	// UpdateBaseURL are ignored.
+	DisableInventories bool
+	// RecordingPassword encrypts gtz recordings with AES-256-GCM, using a key derived from the password, and decrypts
+	// encrypted recordings read from a file source, for sharing captures that should only be read by those given the
+	// password. Recordings that are not encrypted are read as normal. The times, markers and gaps files written
+	// alongside a recording are not encrypted.
+	RecordingPassword string
+	// TimeSource tags each frame with the time it was received, returned by Transformer.ReceivedAt, defaults to the
+	// local system clock. When set, the times are also written alongside recordings, see RecordingTimesSuffix, so
+	// that captures from several rigs synchronised to the same source can be aligned afterwards.
+	TimeSource TimeSource
+	// OnPacketDropped is called with the context of each burst of packets dropped from the stream, such as the packet
+	// rate before it and the time since the previous drop, to help tell Wi-Fi loss apart from the client stalling.
+	// It is called on the goroutine decoding the stream, so must return quickly. Client.PacketDrops summarises drops.
+	OnPacketDropped func(PacketDrop)
+	// OnFrame is called with the Transformer after each frame is decoded, on the goroutine decoding the stream, so
+	// must return quickly. Other goroutines should not read the Transformer while streaming, copy what they need here
+	// instead, e.g. with Transformer.Snapshot. It is called before the frame is recorded, so a recording started or
+	// stopped here begins or ends with the frame, e.g. to split a recording where the lap changes.
+	OnFrame func(*Transformer)
 }
 
 type Client struct {
-	log              zerolog.Logger
-	source           string
-	format           models.Name
-	DecipheredPacket []byte
-	Finished         bool
-	Statistics       *statistics
-	Telemetry        *Transformer
-	CircuitDB        *circuits.CircuitDB
+	log               logging.Logger
+	source            string
+	lowLatency        bool
+	udpOpts           reader.UDPOptions
+	realtime          bool
+	DecipheredPacket  []byte
+	Finished          bool
+	Statistics        *statistics
+	Telemetry         *Transformer
+	CircuitDB         *circuits.CircuitDB
+	timeSource        TimeSource
+	recordTimes       bool
+	recordingPassword string
+	drops             *dropMonitor
+	onFrame           func(*Transformer)
+
+	// Scheduling state
+	schedulingMutex sync.Mutex
+	scheduling      SchedulingState
+
+	// finished mirrors Finished for IsFinished, which is read from other goroutines while streaming
+	finished atomic.Bool
+
+	// scanReader is the reader of the recording being scanned, for reporting progress
+	scanReader atomic.Pointer[reader.FileReader]
+
+	// Source state
+	packetSource      netip.AddrPort
+	expectedSource    netip.Addr
+	unexpectedSources map[netip.Addr]struct{}
+
+	// Format state
+	formatMutex        sync.Mutex
+	format             models.Name
+	activeReader       reader.Reader
+	negotiating        bool
+	negotiatedFormat   models.Name
+	formatRequestedAt  time.Time
+	lastFormatPacketAt time.Time
 
 	// Recording state
 	recordingMutex     sync.RWMutex
 	recordingFile      io.WriteCloser
+	recordingPath      string
 	recordingBuffer    io.Writer
+	recordingTimes     *recordingTimesWriter
+	recordingGaps      *GapDetector
 	isRecording        bool
 	recordingInitState recordingState
+	recordingDir       string
+	recordingFormat    string
 }
 
 func New(opts Options) (*Client, error) {
@@ -97,32 +218,78 @@ func New(opts Options) (*Client, error) {
 		opts.Source = autoDiscoveryURL
 	}
 
-	if opts.Format == "" {
-		opts.Format = models.Addendum3
+	// Negotiate the richest format supported by the console, starting with the richest, when none is set
+	negotiating := opts.Format == ""
+	if negotiating {
+		opts.Format = negotiationOrder[0]
 	}
 
-	circuitDB, err := loadCircuitDB(opts.CachePath, opts.UpdateBaseURL, &logger)
+	circuitDB, vehicleDB, err := loadInventories(opts, &logger)
 	if err != nil {
 		return nil, err
 	}
 
-	vehicleDB, err := loadVehicleDB(opts.VehicleDB, opts.CachePath, opts.UpdateBaseURL, &logger)
+	if opts.RecordingFormat == "" {
+		opts.RecordingFormat = "gtz"
+	}
+
+	recordTimes := opts.TimeSource != nil
+	if !recordTimes {
+		opts.TimeSource = SystemClock{}
+	}
+
+	if opts.RecordingDir != "" {
+		err = os.MkdirAll(opts.RecordingDir, 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("creating recording directory: %w", err)
+		}
+	}
+
+	var fanOut *reader.FanOut
+	if len(opts.FanOut) > 0 {
+		fanOut, err = reader.NewFanOut(opts.FanOut, logger)
+		if err != nil {
+			return nil, fmt.Errorf("setting up fan-out: %w", err)
+		}
+	}
+
+	transformer := NewTransformer(vehicleDB)
+
+	err = transformer.SetFilters(opts.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("setting up filters: %w", err)
+	}
+
+	err = transformer.SetFinalDriveOptions(opts.FinalDrive)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("setting up final drive estimation: %w", err)
 	}
 
-	if opts.UpdateBaseURL != "" {
+	if opts.UpdateBaseURL != "" && !opts.DisableInventories {
 		go checkForUpdates(context.Background(), opts.UpdateBaseURL, vehicleDB, circuitDB, &logger)
 	}
 
-	return &Client{
+	client := &Client{
 		log:              logger,
 		source:           opts.Source,
 		format:           opts.Format,
+		negotiating:      negotiating,
+		negotiatedFormat: models.Unknown,
+		lowLatency:       opts.LowLatency,
+		realtime:         opts.RealtimeScheduling,
+		drops:            &dropMonitor{onDrop: opts.OnPacketDropped},
+		onFrame:          opts.OnFrame,
+		scheduling:       SchedulingState{Requested: opts.RealtimeScheduling, Priority: SchedulingNormal},
+		udpOpts: reader.UDPOptions{
+			ReceiveBufferSize: opts.ReceiveBufferSize,
+			ReusePort:         opts.ReusePort,
+			Interface:         opts.Interface,
+			FanOut:            fanOut,
+		},
 		DecipheredPacket: []byte{},
 		Finished:         false,
 		Statistics: &statistics{
-			enabled:           opts.StatsEnabled,
+			enabled:           opts.StatsEnabled || opts.ExpvarName != "",
 			decodeTimeLast:    time.Duration(0),
 			packetRateLast:    time.Now(),
 			DecodeTimeAvg:     time.Duration(0),
@@ -134,41 +301,90 @@ func New(opts Options) (*Client, error) {
 			PacketsDropped:    0,
 			PacketsInvalid:    0,
 			packetIDLast:      0,
+			fanOut:            fanOut,
 		},
-		Telemetry: NewTransformer(vehicleDB),
-		CircuitDB: circuitDB,
-	}, nil
+		Telemetry:         transformer,
+		CircuitDB:         circuitDB,
+		timeSource:        opts.TimeSource,
+		recordTimes:       recordTimes,
+		recordingPassword: opts.RecordingPassword,
+		recordingDir:      opts.RecordingDir,
+		recordingFormat:   opts.RecordingFormat,
+	}
+
+	if opts.ExpvarName != "" {
+		err = publishStatistics(opts.ExpvarName, client.Statistics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
 }
 
-// setupLogger initializes the zerolog.Logger based on options.
-func setupLogger(opts Options) zerolog.Logger {
+// setupLogger initializes the logging.Logger based on options.
+func setupLogger(opts Options) logging.Logger {
 	if opts.Logger != nil {
 		return *opts.Logger
 	}
 
-	log := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	log := logging.New(os.Stdout)
 
-	logLevel, err := zerolog.ParseLevel(opts.LogLevel)
+	logLevel, err := logging.ParseLevel(opts.LogLevel)
 	if err != nil {
-		logLevel = zerolog.WarnLevel
+		logLevel = logging.WarnLevel
 
 		log.Warn().Str("log_level", opts.LogLevel).Msg("unknown log level, setting level to warn")
 	}
 
-	zerolog.SetGlobalLevel(logLevel)
+	logging.SetGlobalLevel(logLevel)
 
 	return log
 }
 
+// loadInventories loads the circuit and vehicle databases, or creates empty databases when inventories are disabled.
+func loadInventories(opts Options, logger *logging.Logger) (*circuits.CircuitDB, *vehicles.VehicleDB, error) {
+	if opts.DisableInventories {
+		if opts.VehicleDB != "" || opts.VehicleOverrides != "" || opts.CustomCircuitsDir != "" {
+			logger.Warn().Msg("inventories are disabled, ignoring vehicle DB, vehicle overrides and custom circuits")
+		}
+
+		circuitDB, err := circuits.NewDB(circuits.CircuitDBOptions{SkipEmbedded: true, Logger: logger})
+		if err != nil {
+			return nil, nil, fmt.Errorf("setting up empty circuit database: %w", err)
+		}
+
+		vehicleDB, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{Logger: logger})
+		if err != nil {
+			return nil, nil, fmt.Errorf("setting up empty vehicle database: %w", err)
+		}
+
+		return circuitDB, vehicleDB, nil
+	}
+
+	circuitDB, err := loadCircuitDB(opts.CachePath, opts.CustomCircuitsDir, opts.UpdateBaseURL, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vehicleDB, err := loadVehicleDB(opts.VehicleDB, opts.VehicleOverrides, opts.CachePath, opts.UpdateBaseURL, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return circuitDB, vehicleDB, nil
+}
+
 // loadCircuitDB loads the circuit database from embedded inventory files,
-// overlaid by any cached circuit files found in cachePath.
-func loadCircuitDB(cachePath, updateBaseURL string, logger *zerolog.Logger) (*circuits.CircuitDB, error) {
+// overlaid by any cached circuit files found in cachePath and custom circuit files found in customDir.
+func loadCircuitDB(cachePath, customDir, updateBaseURL string, logger *logging.Logger) (*circuits.CircuitDB, error) {
 	if cachePath == "" {
 		cachePath = filepath.Join(defaultCachePath, "circuits")
 	}
 
 	circuitDB, err := circuits.NewDB(circuits.CircuitDBOptions{
 		CacheDir:      cachePath,
+		CustomDir:     customDir,
 		UpdateBaseURL: updateBaseURL,
 		Logger:        logger,
 	})
@@ -179,8 +395,8 @@ func loadCircuitDB(cachePath, updateBaseURL string, logger *zerolog.Logger) (*ci
 	return circuitDB, nil
 }
 
-// loadVehicleDB loads the vehicle database from file if provided.
-func loadVehicleDB(dbPath string, cachePath string, updateURL string, logger *zerolog.Logger) (*vehicles.VehicleDB, error) {
+// loadVehicleDB loads the vehicle database from file if provided, with any user overrides layered over it.
+func loadVehicleDB(dbPath string, overridesPath string, cachePath string, updateURL string, logger *logging.Logger) (*vehicles.VehicleDB, error) {
 	var vehiclesJSON []byte
 
 	var err error
@@ -199,6 +415,7 @@ func loadVehicleDB(dbPath string, cachePath string, updateURL string, logger *ze
 	DBOptions := vehicles.DBOptions{
 		CacheDir:      cachePath,
 		UpdateBaseURL: updateURL,
+		OverridesFile: overridesPath,
 		Logger:        logger,
 	}
 
@@ -212,7 +429,7 @@ func loadVehicleDB(dbPath string, cachePath string, updateURL string, logger *ze
 
 // checkForUpdates fetches the remote version.json and triggers vehicle/circuit
 // updates only when the remote data is newer than the local inventory.
-func checkForUpdates(ctx context.Context, updateBaseURL string, vehicleDB *vehicles.VehicleDB, circuitDB *circuits.CircuitDB, logger *zerolog.Logger) {
+func checkForUpdates(ctx context.Context, updateBaseURL string, vehicleDB *vehicles.VehicleDB, circuitDB *circuits.CircuitDB, logger *logging.Logger) {
 	version, err := fetchVersion(ctx, updateBaseURL)
 	if err != nil {
 		logger.Warn().Err(err).Msg("failed to fetch remote version")
@@ -247,17 +464,62 @@ func (c *Client) Stream(ctx context.Context) (recoverable bool, err error) {
 		return false, fmt.Errorf("parse source URL: %w", err)
 	}
 
-	readerCfg, err := reader.New(sourceURL, c.format, c.log)
+	if c.lowLatency || c.realtime {
+		// Keep the read loop on a single OS thread so that it is not migrated away while spinning
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+	}
+
+	if c.realtime {
+		// Restore the priority before the thread is unlocked and handed back to other goroutines
+		restore := c.raiseScheduling()
+		defer restore()
+	}
+
+	c.formatMutex.Lock()
+
+	udpOpts := c.udpOpts
+	udpOpts.LowLatency = c.lowLatency
+	udpOpts.DetectFormat = c.negotiating
+
+	readerCfg, err := reader.New(sourceURL, c.format, udpOpts, reader.FileOptions{Password: c.recordingPassword}, c.log)
 	if err != nil {
+		c.formatMutex.Unlock()
+
 		return readerCfg.Recoverable, err
 	}
 
+	c.expectSource(sourceURL)
+
 	recoverable = readerCfg.Recoverable
 	telemetryReader := readerCfg.Reader
 	throttle := readerCfg.Throttle
+	c.activeReader = telemetryReader
+	c.formatRequestedAt = time.Now()
+	_, canRequestFormat := telemetryReader.(reader.FormatRequester)
+	negotiate := c.negotiating && c.negotiatedFormat == models.Unknown && canRequestFormat
+	c.formatMutex.Unlock()
+
+	if udpOpts.FanOut != nil {
+		fanOutCtx, cancelFanOut := context.WithCancel(ctx)
+		defer cancelFanOut()
+
+		go udpOpts.FanOut.Run(fanOutCtx)
+	}
+
+	if negotiate {
+		negotiationCtx, cancelNegotiation := context.WithCancel(ctx)
+		defer cancelNegotiation()
+
+		go c.negotiateFormat(negotiationCtx)
+	}
 
 	// Ensure the reader is closed when Stream exits
 	defer func() {
+		c.formatMutex.Lock()
+		c.activeReader = nil
+		c.formatMutex.Unlock()
+
 		closeErr := telemetryReader.Close()
 		if closeErr != nil {
 			c.log.Error().Err(closeErr).Msg("failed to close telemetry reader")
@@ -290,6 +552,36 @@ func (c *Client) Stream(ctx context.Context) (recoverable bool, err error) {
 	}
 }
 
+// RequestFormat switches the telemetry format requested from the console. GT7 has no control packets other than the
+// heartbeat, which carries the requested format, so a heartbeat for the new format is sent straight away when
+// streaming from a UDP source. The format is also used for any later calls to Stream. Requesting a format stops any
+// format negotiation in progress.
+func (c *Client) RequestFormat(format models.Name) error {
+	if !slices.Contains(negotiationOrder, format) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	c.formatMutex.Lock()
+	defer c.formatMutex.Unlock()
+
+	err := c.requestFormatLocked(format)
+	if err != nil {
+		return err
+	}
+
+	c.negotiating = false
+
+	return nil
+}
+
+// Format returns the telemetry format currently requested from the console.
+func (c *Client) Format() models.Name {
+	c.formatMutex.Lock()
+	defer c.formatMutex.Unlock()
+
+	return c.format
+}
+
 // Run starts the telemetry client to read and process a live data stream.
 //
 // Deprecated: Use Stream instead.
@@ -311,17 +603,28 @@ func (c *Client) Scan(ctx context.Context) iter.Seq2[*Transformer, error] {
 			return
 		}
 
+		c.scanReader.Store(telemetryReader)
+
 		defer func() {
+			c.scanReader.Store(nil)
+
 			closeErr := telemetryReader.Close()
 			if closeErr != nil {
 				c.log.Error().Err(closeErr).Msg("failed to close telemetry reader")
 			}
 		}()
 
+		receivedAt, err := c.openRecordingTimes()
+		if err != nil {
+			yield(nil, err)
+
+			return
+		}
+
 		rawTelemetry := telemetry.NewGranTurismoTelemetry()
 
 		for ctx.Err() == nil {
-			done, readErr := c.scanNextPacket(telemetryReader, rawTelemetry)
+			done, readErr := c.scanNextPacket(telemetryReader, rawTelemetry, receivedAt)
 			if done {
 				if readErr != nil {
 					yield(nil, readErr)
@@ -339,6 +642,18 @@ func (c *Client) Scan(ctx context.Context) iter.Seq2[*Transformer, error] {
 	}
 }
 
+// ScanPosition returns the number of bytes of the recording read so far by Scan and the size of the recording file,
+// for reporting progress. Reads are buffered, so the position runs slightly ahead of the frames yielded. Both are zero
+// when no scan is in progress.
+func (c *Client) ScanPosition() (int64, int64) {
+	scanReader := c.scanReader.Load()
+	if scanReader == nil {
+		return 0, 0
+	}
+
+	return scanReader.Position()
+}
+
 // IsReplaySource checks if the telemetry source is a replay file.
 func (c *Client) IsReplaySource() (bool, error) {
 	sourceURL, err := url.Parse(c.source)
@@ -366,52 +681,89 @@ func (c *Client) StartRecording(filePath string) error {
 		return ErrRecordingAlreadyInProgress
 	}
 
+	fileExt := filePath[len(filePath)-3:]
+	if fileExt == "gtr" && c.recordingPassword != "" {
+		return ErrRecordingNotEncryptable
+	}
+
+	file, buffer, err := createRecording(filePath, c.recordingPassword)
+	if err != nil {
+		return err
+	}
+
+	c.recordingFile = file
+
+	if c.recordTimes {
+		c.recordingTimes, err = newRecordingTimesWriter(filePath + RecordingTimesSuffix)
+		if err != nil {
+			c.recordingFile.Close()
+			c.recordingFile = nil
+
+			return err
+		}
+	}
+
+	c.recordingBuffer = buffer
+	c.recordingPath = filePath
+	c.recordingGaps = &GapDetector{discard: true}
+	c.isRecording = true
+
+	// The game state is read from the next frame rather than here, as the Transformer is written by the goroutine
+	// decoding the stream while recordings are started from other goroutines
+	c.recordingInitState = recordingStatePending
+
+	c.log.Info().Str("file", filePath).Msg("started recording telemetry data")
+
+	return nil
+}
+
+// createRecording creates a recording file, returning the file to close once the recording is complete and the writer
+// that packets are written to. The format, plain (.gtr) or compressed (.gtz), is chosen by the file extension, and
+// compressed recordings are encrypted when a password is given.
+func createRecording(filePath string, password string) (io.WriteCloser, io.Writer, error) {
+	fileExt := filePath[len(filePath)-3:]
+
 	// Create the file
 	file, err := os.Create(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to create recording file: %w", err)
+		return nil, nil, fmt.Errorf("failed to create recording file: %w", err)
 	}
 
 	// Determine format based on file extension
-	var buffer io.Writer
-
-	fileExt := filePath[len(filePath)-3:]
 	switch fileExt {
 	case "gtz":
-		gzipWriter, err := gzip.NewWriterLevel(file, gzip.BestCompression)
+		wrapper := &gzipFileWrapper{file: file}
+
+		var compressed io.Writer = file
+
+		if password != "" {
+			wrapper.encryptWriter, err = encryption.NewWriter(file, password)
+			if err != nil {
+				file.Close()
+
+				return nil, nil, fmt.Errorf("failed to create encryption writer: %w", err)
+			}
+
+			compressed = wrapper.encryptWriter
+		}
+
+		wrapper.gzipWriter, err = gzip.NewWriterLevel(compressed, gzip.BestCompression)
 		if err != nil {
 			file.Close()
 
-			return fmt.Errorf("failed to create gzip writer: %w", err)
+			return nil, nil, fmt.Errorf("failed to create gzip writer: %w", err)
 		}
 
-		gzipWriter.Comment = "Gran Turismo Telemetry Recording"
-		buffer = gzipWriter
-		c.recordingFile = &gzipFileWrapper{file: file, gzipWriter: gzipWriter}
+		wrapper.gzipWriter.Comment = "Gran Turismo Telemetry Recording"
+
+		return wrapper, wrapper.gzipWriter, nil
 	case "gtr":
-		buffer = file
-		c.recordingFile = file
+		return file, file, nil
 	default:
 		file.Close()
 
-		return fmt.Errorf("%w: %q", ErrUnsupportedFileExtension, fileExt)
-	}
-
-	c.recordingBuffer = buffer
-	c.isRecording = true
-
-	switch {
-	case c.Telemetry.IsInRaceMenu():
-		c.recordingInitState = recordingStateRaceMenu
-	case !c.Telemetry.IsInMainMenu():
-		c.recordingInitState = recordingStateOnCircuit
-	default:
-		c.recordingInitState = recordingStateNone
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnsupportedFileExtension, fileExt)
 	}
-
-	c.log.Info().Str("file", filePath).Msg("started recording telemetry data")
-
-	return nil
 }
 
 // StopRecording stops the current recording and closes the file.
@@ -423,6 +775,15 @@ func (c *Client) StopRecording() error {
 		return ErrNoRecordingInProgress
 	}
 
+	if c.recordingTimes != nil {
+		err := c.recordingTimes.Close()
+		if err != nil {
+			c.log.Error().Err(err).Msg("error closing recording times file")
+		}
+
+		c.recordingTimes = nil
+	}
+
 	// Flush and close the file
 	err := c.recordingFile.Close()
 	if err != nil {
@@ -433,6 +794,8 @@ func (c *Client) StopRecording() error {
 
 	c.recordingFile = nil
 	c.recordingBuffer = nil
+	c.recordingPath = ""
+	c.recordingGaps = nil
 	c.isRecording = false
 	c.recordingInitState = recordingStateNone
 
@@ -449,6 +812,15 @@ func (c *Client) IsRecording() bool {
 	return c.isRecording
 }
 
+// RecordingPath returns the path of the recording in progress, or an empty string when not recording. The recording
+// file is complete once the path changes.
+func (c *Client) RecordingPath() string {
+	c.recordingMutex.RLock()
+	defer c.recordingMutex.RUnlock()
+
+	return c.recordingPath
+}
+
 // openFileReader parses the client source URL and opens a FileReader.
 // Returns ErrNotAFileSource if the source is not a file:// URL.
 func (c *Client) openFileReader() (*reader.FileReader, error) {
@@ -461,7 +833,7 @@ func (c *Client) openFileReader() (*reader.FileReader, error) {
 		return nil, ErrNotAFileSource
 	}
 
-	r, err := reader.NewFileReader(sourceURL.Host+sourceURL.Path, c.log)
+	r, err := reader.NewFileReader(sourceURL.Host+sourceURL.Path, reader.FileOptions{Password: c.recordingPassword}, c.log)
 	if err != nil {
 		return nil, fmt.Errorf("setup file reader: %w", err)
 	}
@@ -471,11 +843,11 @@ func (c *Client) openFileReader() (*reader.FileReader, error) {
 
 // scanNextPacket reads and processes one packet for the Scan iterator.
 // Returns done=true when scanning is complete. A non-nil error should be yielded to the caller.
-func (c *Client) scanNextPacket(r reader.Reader, raw *telemetry.GranTurismoTelemetry) (done bool, err error) {
+func (c *Client) scanNextPacket(r reader.Reader, raw *telemetry.GranTurismoTelemetry, receivedAt func(sequenceID uint32) time.Time) (done bool, err error) {
 	bufLen, buffer, readErr := r.Read()
 	if readErr != nil {
 		if errors.Is(readErr, io.EOF) {
-			c.Finished = true
+			c.finish()
 
 			return true, nil
 		}
@@ -491,7 +863,7 @@ func (c *Client) scanNextPacket(r reader.Reader, raw *telemetry.GranTurismoTelem
 
 	stream := kaitai.NewStream(bytes.NewReader(c.DecipheredPacket))
 
-	c.processTelemetry(raw, stream, time.Now())
+	c.processTelemetry(raw, stream, time.Now(), receivedAt)
 
 	return false, nil
 }
@@ -512,6 +884,8 @@ func (c *Client) readAndProcessPacket(telemetryReader reader.Reader, rawTelemetr
 		return false, nil
 	}
 
+	c.observeSource(telemetryReader)
+
 	c.DecipheredPacket = buffer[:bufLen]
 
 	decodeStart := time.Now()
@@ -519,7 +893,7 @@ func (c *Client) readAndProcessPacket(telemetryReader reader.Reader, rawTelemetr
 	reader := bytes.NewReader(c.DecipheredPacket)
 	stream := kaitai.NewStream(reader)
 
-	c.processTelemetry(rawTelemetry, stream, decodeStart)
+	c.processTelemetry(rawTelemetry, stream, decodeStart, c.receivedNow)
 
 	return false, nil
 }
@@ -532,7 +906,7 @@ func (c *Client) handleReadError(err error) (shouldContinue bool, finished bool)
 
 	if errors.Is(err, io.EOF) {
 		if !c.Finished {
-			c.Finished = true
+			c.finish()
 			c.log.Info().Msg("reached end of telemetry data")
 		}
 
@@ -540,9 +914,7 @@ func (c *Client) handleReadError(err error) (shouldContinue bool, finished bool)
 	}
 
 	if err.Error() == "bufio.Scanner: SplitFunc returns advance count beyond input" {
-		if !c.Finished {
-			c.Finished = true
-		}
+		c.finish()
 
 		return false, true
 	}
@@ -552,6 +924,18 @@ func (c *Client) handleReadError(err error) (shouldContinue bool, finished bool)
 	return false, true
 }
 
+// finish marks the end of a file source as reached.
+func (c *Client) finish() {
+	c.Finished = true
+	c.finished.Store(true)
+}
+
+// IsFinished reports whether the end of a file source has been reached. Unlike Finished, it can be called from other
+// goroutines while the client is streaming.
+func (c *Client) IsFinished() bool {
+	return c.finished.Load()
+}
+
 // handleEmptyBuffer checks if the buffer is empty and logs if so.
 func (c *Client) handleEmptyBuffer(buffer []byte, bufLen int) bool {
 	if len(buffer[:bufLen]) == 0 {
@@ -563,20 +947,122 @@ func (c *Client) handleEmptyBuffer(buffer []byte, bufLen int) bool {
 	return true
 }
 
-// processTelemetry parses and processes telemetry packets.
-func (c *Client) processTelemetry(rawTelemetry *telemetry.GranTurismoTelemetry, stream *kaitai.Stream, decodeStart time.Time) {
+// processTelemetry parses and processes telemetry packets, tagging each frame with the time returned by receivedAt.
+func (c *Client) processTelemetry(
+	rawTelemetry *telemetry.GranTurismoTelemetry, stream *kaitai.Stream, decodeStart time.Time, receivedAt func(sequenceID uint32) time.Time,
+) {
 	err := rawTelemetry.Read(stream, nil, nil)
 	if err != nil {
-		c.Statistics.PacketsInvalid++
-		c.log.Error().Err(err).Msg("failed to parse telemetry")
+		c.invalidTelemetry(err)
 
 		return
 	}
 
-	c.Telemetry.RawTelemetry = *rawTelemetry
+	c.applyTelemetry(rawTelemetry, decodeStart, receivedAt)
+}
+
+// invalidTelemetry counts and logs a packet that could not be parsed.
+func (c *Client) invalidTelemetry(err error) {
+	c.Statistics.mu.Lock()
+	c.Statistics.PacketsInvalid++
+	c.Statistics.mu.Unlock()
+	c.log.Error().Err(err).Msg("failed to parse telemetry")
+}
+
+// applyTelemetry updates the transformer with a parsed packet, then collects statistics, calls OnFrame and records it.
+func (c *Client) applyTelemetry(
+	rawTelemetry *telemetry.GranTurismoTelemetry, decodeStart time.Time, receivedAt func(sequenceID uint32) time.Time,
+) {
+	c.Telemetry.setFrame(rawTelemetry)
+	c.Telemetry.receivedAt = receivedAt(rawTelemetry.SequenceId)
+	c.Telemetry.decodedAt = decodeStart
+	c.Telemetry.sourceAddress = c.packetSource
+	c.Telemetry.UpdateVehicle()
+	c.Telemetry.UpdateFilters()
+	c.Telemetry.UpdateClock()
+	c.Telemetry.UpdateFuelFlow()
+	c.observeFormat(c.Telemetry.TelemetryFormat())
+	c.drops.observe(rawTelemetry.SequenceId, c.Telemetry.receivedAt)
 	c.Statistics.decodeTimeLast = time.Since(decodeStart)
 	c.collectStats()
+	c.autoRecord()
+
+	if c.onFrame != nil {
+		c.onFrame(c.Telemetry)
+	}
+
 	c.recordPacket()
+	c.drops.processed(time.Since(decodeStart))
+}
+
+// expectSource sets the console address packets of a UDP source are expected from, when the source names a single
+// console rather than a broadcast or multicast group.
+func (c *Client) expectSource(sourceURL *url.URL) {
+	c.packetSource = netip.AddrPort{}
+	c.expectedSource = netip.Addr{}
+	c.unexpectedSources = make(map[netip.Addr]struct{})
+
+	if sourceURL.Scheme != "udp" {
+		return
+	}
+
+	addr, err := netip.ParseAddr(sourceURL.Hostname())
+	if err != nil || addr.IsUnspecified() || addr.IsMulticast() || addr == netip.AddrFrom4([4]byte{255, 255, 255, 255}) {
+		return
+	}
+
+	c.expectedSource = addr.Unmap()
+}
+
+// observeSource records the address the packet was read from, warning once for each address that is not the console.
+func (c *Client) observeSource(r reader.Reader) {
+	reporter, ok := r.(reader.SourceReporter)
+	if !ok {
+		c.packetSource = netip.AddrPort{}
+
+		return
+	}
+
+	c.packetSource = reporter.Source()
+
+	if !c.expectedSource.IsValid() || c.packetSource.Addr() == c.expectedSource {
+		return
+	}
+
+	c.Statistics.mu.Lock()
+	c.Statistics.PacketsUnexpectedSource++
+	c.Statistics.mu.Unlock()
+
+	if _, seen := c.unexpectedSources[c.packetSource.Addr()]; !seen {
+		c.unexpectedSources[c.packetSource.Addr()] = struct{}{}
+		c.log.Warn().Stringer("source", c.packetSource).Stringer("console", c.expectedSource).
+			Msg("received telemetry from an unexpected source")
+	}
+}
+
+// receivedNow returns the time of the client's time source for frames received live.
+func (c *Client) receivedNow(uint32) time.Time {
+	return c.timeSource.Now()
+}
+
+// openRecordingTimes returns the times frames of the file source were received, from the recording times file, or
+// the zero time for every frame when the recording has none.
+func (c *Client) openRecordingTimes() (func(sequenceID uint32) time.Time, error) {
+	sourceURL, err := url.Parse(c.source)
+	if err != nil {
+		return nil, fmt.Errorf("parse source URL: %w", err)
+	}
+
+	times, err := readRecordingTimes(sourceURL.Host + sourceURL.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if times == nil {
+		return func(uint32) time.Time { return time.Time{} }, nil
+	}
+
+	return times.at, nil
 }
 
 // currentGameState returns the recording state that corresponds to the current game state.
@@ -591,17 +1077,60 @@ func (c *Client) currentGameState() recordingState {
 	}
 }
 
+// autoRecord starts recording to a new file in the recording directory when a vehicle is loaded, either on circuit or
+// in a replay, and no recording is in progress. The recording stops when the game state changes, so each session has
+// its own file.
+func (c *Client) autoRecord() {
+	if c.recordingDir == "" || c.IsRecording() || c.Telemetry.Flags().GamePaused {
+		return
+	}
+
+	if c.currentGameState() == recordingStateNone {
+		return
+	}
+
+	filePath := filepath.Join(c.recordingDir, time.Now().Format("20060102-150405.000")+"."+c.recordingFormat)
+
+	err := c.StartRecording(filePath)
+	if err != nil {
+		c.log.Error().Err(err).Msg("failed to start automatic recording")
+
+		// Disable automatic recording rather than failing on every packet
+		c.recordingDir = ""
+	}
+}
+
 // recordPacket writes the current packet to the recording file if recording is active.
 func (c *Client) recordPacket() {
 	c.recordingMutex.RLock()
 	active := c.isRecording && c.recordingBuffer != nil && len(c.DecipheredPacket) > 0
 	initState := c.recordingInitState
+	path := c.recordingPath
+	gaps := c.recordingGaps
 	c.recordingMutex.RUnlock()
 
 	if !active {
 		return
 	}
 
+	if initState == recordingStatePending {
+		initState = c.currentGameState()
+
+		c.recordingMutex.Lock()
+		if c.recordingInitState == recordingStatePending {
+			c.recordingInitState = initState
+		}
+		c.recordingMutex.Unlock()
+	}
+
+	// Paused frames are not recorded, so pauses are only known from the gaps file
+	if gap, ended := gaps.Update(c.Telemetry); ended {
+		err := AddRecordingGap(path, gap)
+		if err != nil {
+			c.log.Error().Err(err).Msg("failed to write recording gap")
+		}
+	}
+
 	if c.Telemetry.Flags().GamePaused {
 		return
 	}
@@ -628,17 +1157,28 @@ func (c *Client) recordPacket() {
 
 	c.recordingMutex.RLock()
 	_, err := c.recordingBuffer.Write(c.DecipheredPacket)
+
+	var timesErr error
+	if err == nil && c.recordingTimes != nil {
+		timesErr = c.recordingTimes.write(c.Telemetry.SequenceID(), c.Telemetry.ReceivedAt())
+	}
 	c.recordingMutex.RUnlock()
 
 	if err != nil {
 		c.log.Error().Err(err).Msg("failed to write packet to recording file")
 	}
+
+	if timesErr != nil {
+		c.log.Error().Err(timesErr).Msg("failed to write recording times")
+	}
 }
 
-// gzipFileWrapper wraps a gzip writer and file to handle proper closing.
+// gzipFileWrapper wraps a gzip writer, the encryption writer of encrypted recordings and file to handle proper
+// closing.
 type gzipFileWrapper struct {
-	file       *os.File
-	gzipWriter *gzip.Writer
+	file          *os.File
+	gzipWriter    *gzip.Writer
+	encryptWriter *encryption.Writer
 }
 
 // Write writes data to the gzip writer.
@@ -646,9 +1186,13 @@ func (g *gzipFileWrapper) Write(p []byte) (n int, err error) {
 	return g.gzipWriter.Write(p)
 }
 
-// Close closes the gzip writer and the underlying file.
+// Close closes the gzip writer, the encryption writer and the underlying file.
 func (g *gzipFileWrapper) Close() error {
 	err := g.gzipWriter.Close()
+	if err == nil && g.encryptWriter != nil {
+		err = g.encryptWriter.Close()
+	}
+
 	if err != nil {
 		g.file.Close()
 
@@ -658,12 +1202,26 @@ func (g *gzipFileWrapper) Close() error {
 	return g.file.Close()
 }
 
+// FanOut returns the delivery counters of every fan-out target, so that a consumer missing data can be diagnosed.
+// Sent packets were written to the network without error, errors are failed writes, which include packets rejected
+// by an unreachable target, and dropped packets were discarded because the target could not keep up.
+func (s *statistics) FanOut() []FanOutStats {
+	if s.fanOut == nil {
+		return nil
+	}
+
+	return s.fanOut.Stats()
+}
+
 // collectStats updates the telemetry statistics based on the latest packet.
 func (c *Client) collectStats() {
 	if !c.Statistics.enabled {
 		return
 	}
 
+	c.Statistics.mu.Lock()
+	defer c.Statistics.mu.Unlock()
+
 	c.Statistics.PacketsTotal++
 
 	if c.Statistics.packetIDLast == c.Telemetry.SequenceID() {
@@ -0,0 +1,268 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// TimelineEventType identifies the kind of event recorded in a session timeline.
+type TimelineEventType string
+
+const (
+	TimelineEventLap         TimelineEventType = "lap"
+	TimelineEventRaceStart   TimelineEventType = "raceStart"
+	TimelineEventPitStop     TimelineEventType = "pitStop"
+	TimelineEventTrackLimits TimelineEventType = "trackLimits"
+	TimelineEventPenalty     TimelineEventType = "penalty"
+	TimelineEventDamage      TimelineEventType = "damage"
+	TimelineEventGap         TimelineEventType = "gap"
+)
+
+// TimelineEvent is a single entry in a session timeline. SessionTime is measured from the first frame of the
+// session and Value holds the lap time, race start reaction time, pit stop duration, track limits excess or gap
+// duration, depending on the event type.
+type TimelineEvent struct {
+	SessionTime time.Duration     `json:"sessionTime"`
+	SequenceID  uint32            `json:"sequenceId"`
+	Lap         int16             `json:"lap"`
+	Type        TimelineEventType `json:"type"`
+	Detail      string            `json:"detail"`
+	Value       float64           `json:"value"`
+}
+
+// TimelineOptions configures a Timeline.
+type TimelineOptions struct {
+	// TrackLimits is an optional track limits monitor whose warnings are added to the timeline.
+	TrackLimits *TrackLimits
+	// MaxRetainedLaps keeps only the events of the most recent laps and the lap in progress, bounding the memory used
+	// by long sessions such as 24 hour endurance races. Zero keeps every event.
+	MaxRetainedLaps int
+}
+
+// Timeline combines lap times, race starts, pit stops, track limit warnings, incidents and gaps in the telemetry into a
+// chronological record of a session, for review by race stewards alongside race footage.
+type Timeline struct {
+	incidents     *IncidentMonitor
+	raceStarts    *RaceStartDetector
+	pitStops      *PitStopDetector
+	trackLimits   *TrackLimits
+	gaps          *GapDetector
+	firstSequence uint32
+	lastSequence  uint32
+	lastLap       int16
+	penalised     bool
+	damaged       bool
+	maxLaps       int
+	// dropped is the number of events no longer retained, see Dropped
+	dropped int
+	events  []TimelineEvent
+}
+
+// NewTimeline creates an empty session timeline.
+func NewTimeline(opts TimelineOptions) *Timeline {
+	return &Timeline{
+		incidents:   NewIncidentMonitor(),
+		raceStarts:  NewRaceStartDetector(),
+		pitStops:    NewPitStopDetector(),
+		trackLimits: opts.TrackLimits,
+		gaps:        &GapDetector{discard: true},
+		maxLaps:     max(opts.MaxRetainedLaps, 0),
+	}
+}
+
+// Update evaluates the latest telemetry frame and records any new events.
+func (tl *Timeline) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID == tl.lastSequence {
+		return
+	}
+
+	if tl.firstSequence == 0 {
+		tl.firstSequence = sequenceID
+	}
+
+	tl.lastSequence = sequenceID
+	lap := t.CurrentLap()
+
+	if gap, ok := tl.gaps.Update(t); ok {
+		tl.add(t, TimelineEvent{
+			Type:   TimelineEventGap,
+			Detail: fmt.Sprintf("%s for %.1fs from sequence %d", gap.Reason, gap.Duration.Seconds(), gap.SequenceID),
+			Value:  gap.Duration.Seconds(),
+		})
+	}
+
+	if lap > tl.lastLap && tl.lastLap > 0 && t.LastLaptime() > 0 {
+		tl.add(t, TimelineEvent{
+			Lap:    tl.lastLap,
+			Type:   TimelineEventLap,
+			Detail: formatLaptime(t.LastLaptime()),
+			Value:  t.LastLaptime().Seconds(),
+		})
+	}
+
+	tl.lastLap = lap
+
+	if start, ok := tl.raceStarts.Update(t); ok {
+		tl.add(t, TimelineEvent{
+			Type:   TimelineEventRaceStart,
+			Detail: fmt.Sprintf("%s start from grid position %d, reaction %.3fs", start.StartType, start.GridPosition, start.ReactionTime.Seconds()),
+			Value:  start.ReactionTime.Seconds(),
+		})
+	}
+
+	if stop, ok := tl.pitStops.Update(t); ok {
+		tl.add(t, TimelineEvent{
+			Type:   TimelineEventPitStop,
+			Detail: fmt.Sprintf("stationary %.1fs, fuel added %.1f, tyres changed %t", stop.Duration.Seconds(), stop.FuelAdded, stop.TyresChanged),
+			Value:  stop.Duration.Seconds(),
+		})
+	}
+
+	if tl.trackLimits != nil {
+		if warning, ok := tl.trackLimits.Update(t); ok {
+			tl.add(t, TimelineEvent{
+				Type:   TimelineEventTrackLimits,
+				Detail: fmt.Sprintf("all wheels %.1fm beyond track limits", warning.ExcessMetres),
+				Value:  float64(warning.ExcessMetres),
+			})
+		}
+	}
+
+	tl.updateIncidents(t)
+}
+
+// Events returns all recorded events in chronological order, or those of the most recent laps when the number of
+// retained laps is limited.
+func (tl *Timeline) Events() []TimelineEvent {
+	return tl.events
+}
+
+// Dropped returns the number of events no longer retained because of the limit on retained laps, such as to show that
+// earlier events of the session are not listed.
+func (tl *Timeline) Dropped() int {
+	return tl.dropped
+}
+
+// WriteJSON writes the timeline events to w as a JSON array.
+func (tl *Timeline) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	events := tl.events
+	if events == nil {
+		events = []TimelineEvent{}
+	}
+
+	err := encoder.Encode(events)
+	if err != nil {
+		return fmt.Errorf("encode timeline JSON: %w", err)
+	}
+
+	return nil
+}
+
+// WriteCSV writes the timeline events to w as CSV with a header row. Session time is written in seconds.
+func (tl *Timeline) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write([]string{"sessionTime", "sequenceId", "lap", "type", "detail", "value"})
+	if err != nil {
+		return fmt.Errorf("write timeline CSV header: %w", err)
+	}
+
+	for _, event := range tl.events {
+		err = writer.Write([]string{
+			formatDecimal(event.SessionTime.Seconds(), 3),
+			strconv.FormatUint(uint64(event.SequenceID), 10),
+			strconv.Itoa(int(event.Lap)),
+			string(event.Type),
+			event.Detail,
+			formatDecimal(event.Value, 3),
+		})
+		if err != nil {
+			return fmt.Errorf("write timeline CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func (tl *Timeline) updateIncidents(t *Transformer) {
+	tl.incidents.Update(t)
+
+	penalty := tl.incidents.ActivePenaltySeconds()
+	if penalty > 0 && !tl.penalised {
+		tl.add(t, TimelineEvent{
+			Type:   TimelineEventPenalty,
+			Detail: "throttle limited by penalty",
+			Value:  penalty,
+		})
+	}
+
+	tl.penalised = penalty > 0
+
+	damaged := tl.incidents.HasDamage()
+	if damaged && !tl.damaged {
+		tl.add(t, TimelineEvent{
+			Type:   TimelineEventDamage,
+			Detail: "calculated top speed reduced",
+		})
+	}
+
+	tl.damaged = damaged
+}
+
+// add records an event at the current frame, filling in the session time, sequence ID and lap if not set.
+func (tl *Timeline) add(t *Transformer, event TimelineEvent) {
+	event.SequenceID = tl.lastSequence
+	event.SessionTime = t.CorrectedDuration(framesElapsed(tl.firstSequence, tl.lastSequence))
+
+	if event.Lap == 0 {
+		event.Lap = t.CurrentLap()
+	}
+
+	tl.events = append(tl.events, event)
+
+	if event.Type == TimelineEventLap {
+		tl.trimLaps()
+	}
+}
+
+// trimLaps drops the events of the laps completed before the most recent retained laps.
+func (tl *Timeline) trimLaps() {
+	if tl.maxLaps == 0 {
+		return
+	}
+
+	laps := 0
+
+	for i := len(tl.events) - 1; i >= 0; i-- {
+		if tl.events[i].Type != TimelineEventLap {
+			continue
+		}
+
+		laps++
+		if laps > tl.maxLaps {
+			tl.dropped += i + 1
+			tl.events = slices.Clone(tl.events[i+1:])
+
+			return
+		}
+	}
+}
+
+// formatLaptime formats a lap time as m:ss.SSS.
+func formatLaptime(laptime time.Duration) string {
+	minutes := laptime / time.Minute
+	seconds := float64(laptime%time.Minute) / float64(time.Second)
+
+	return fmt.Sprintf("%d:%06.3f", minutes, seconds)
+}
@@ -0,0 +1,178 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type TimelineTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	timeline    *gttelemetry.Timeline
+}
+
+func TestTimelineTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TimelineTestSuite))
+}
+
+func (suite *TimelineTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.transformer.RawTelemetry.GroundSpeed = 30
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 300
+	suite.transformer.SetFlags(true, false, false, true, false, false, false, false, false, false, false, false)
+
+	suite.timeline = gttelemetry.NewTimeline(gttelemetry.TimelineOptions{
+		TrackLimits: gttelemetry.NewTrackLimits(gttelemetry.TrackLimitsOptions{
+			Path: []models.Coordinate{{X: -1000}, {X: 1000}},
+		}),
+	})
+}
+
+func (suite *TimelineTestSuite) frame(x, z float32) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.SetMapPositionCoordinates(x, 0, z)
+	suite.timeline.Update(suite.transformer)
+}
+
+func (suite *TimelineTestSuite) TestRecordsEventsInOrder() {
+	// Arrange
+	suite.frame(0, 0)
+	suite.frame(1, 0)
+
+	// Act
+	for x := range 60 {
+		suite.frame(float32(x+2), 0)
+	}
+
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.transformer.RawTelemetry.LastLaptime = 83456
+	suite.frame(70, 0)
+	suite.frame(71, 10)
+	suite.frame(72, 10)
+
+	// Assert
+	events := suite.timeline.Events()
+	suite.Require().Len(events, 2)
+	suite.Equal(gttelemetry.TimelineEventLap, events[0].Type)
+	suite.Equal(int16(1), events[0].Lap)
+	suite.Equal("1:23.456", events[0].Detail)
+	suite.Equal(62*time.Second/60, events[0].SessionTime)
+	suite.Equal(gttelemetry.TimelineEventTrackLimits, events[1].Type)
+	suite.Equal(int16(2), events[1].Lap)
+}
+
+func (suite *TimelineTestSuite) TestRecordsRaceStart() {
+	// Arrange
+	suite.transformer.RawTelemetry.CurrentLap = 0
+	suite.transformer.RawTelemetry.GroundSpeed = 0
+	suite.transformer.RawTelemetry.GridPosition = 3
+	suite.frame(0, 0)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.frame(0, 0)
+
+	// Act
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.frame(0, 0)
+
+	// Assert
+	events := suite.timeline.Events()
+	suite.Require().Len(events, 1)
+	suite.Equal(gttelemetry.TimelineEventRaceStart, events[0].Type)
+	suite.Equal("standing start from grid position 3, reaction 0.017s", events[0].Detail)
+}
+
+func (suite *TimelineTestSuite) TestRecordsPenaltyOnce() {
+	// Arrange
+	suite.transformer.RawTelemetry.ThrottleInput = 255
+	suite.transformer.RawTelemetry.ThrottleOutput = 50
+
+	// Act
+	for x := range 120 {
+		suite.frame(float32(x), 0)
+	}
+
+	// Assert
+	events := suite.timeline.Events()
+	suite.Require().Len(events, 1)
+	suite.Equal(gttelemetry.TimelineEventPenalty, events[0].Type)
+}
+
+func (suite *TimelineTestSuite) TestMaxRetainedLapsDropsOldLaps() {
+	// Arrange
+	timeline := gttelemetry.NewTimeline(gttelemetry.TimelineOptions{MaxRetainedLaps: 2})
+
+	// Act
+	for lap := range int16(5) {
+		suite.transformer.RawTelemetry.CurrentLap = lap + 1
+		suite.transformer.RawTelemetry.LastLaptime = 60000
+
+		for range 10 {
+			suite.transformer.RawTelemetry.SequenceId++
+			timeline.Update(suite.transformer)
+		}
+	}
+
+	// Assert
+	var laps []int16
+
+	for _, event := range timeline.Events() {
+		if event.Type == gttelemetry.TimelineEventLap {
+			laps = append(laps, event.Lap)
+		}
+	}
+
+	suite.Equal([]int16{3, 4}, laps)
+	suite.Equal(2, timeline.Dropped())
+}
+
+func (suite *TimelineTestSuite) TestWriteCSV() {
+	// Arrange
+	suite.frame(0, 0)
+	suite.transformer.RawTelemetry.CurrentLap = 2
+	suite.transformer.RawTelemetry.LastLaptime = 60000
+	suite.frame(1, 0)
+
+	buf := &bytes.Buffer{}
+
+	// Act
+	err := suite.timeline.WriteCSV(buf)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	suite.Require().Len(lines, 2)
+	suite.Equal("sessionTime,sequenceId,lap,type,detail,value", lines[0])
+	suite.Equal("0.017,2,1,lap,1:00.000,60.000", lines[1])
+}
+
+func (suite *TimelineTestSuite) TestWriteJSONWritesEmptyArray() {
+	// Arrange
+	buf := &bytes.Buffer{}
+
+	// Act
+	err := suite.timeline.WriteJSON(buf)
+
+	// Assert
+	suite.Require().NoError(err)
+
+	var events []gttelemetry.TimelineEvent
+
+	suite.Require().NoError(json.Unmarshal(buf.Bytes(), &events))
+	suite.NotNil(events)
+	suite.Empty(events)
+}
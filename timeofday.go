@@ -0,0 +1,140 @@
+package gttelemetry
+
+import (
+	"time"
+)
+
+const (
+	// DefaultDawn is the time of day at which night ends when no dawn is configured.
+	DefaultDawn = 6 * time.Hour
+	// DefaultDusk is the time of day at which night starts when no dusk is configured.
+	DefaultDusk = 19 * time.Hour
+
+	dayLength = 24 * time.Hour
+)
+
+// TimeOfDayOptions configures a TimeOfDayTracker.
+type TimeOfDayOptions struct {
+	Dawn time.Duration // time of day at which night ends, defaults to DefaultDawn
+	Dusk time.Duration // time of day at which night starts, defaults to DefaultDusk
+}
+
+// TimeOfDayTracker tracks the in-game time of day through a session.
+//
+// The in-game clock runs faster than real time in races with time progression, and wraps at midnight. Real time is
+// measured from the number of frames received, so the time acceleration factor is also correct for replays played
+// at normal speed.
+type TimeOfDayTracker struct {
+	dawn          time.Duration
+	dusk          time.Duration
+	started       bool
+	firstSequence uint32
+	lastSequence  uint32
+	lastTimeOfDay time.Duration
+	elapsed       time.Duration
+}
+
+// NewTimeOfDayTracker creates a time of day tracker.
+func NewTimeOfDayTracker(opts TimeOfDayOptions) *TimeOfDayTracker {
+	if opts.Dawn == 0 {
+		opts.Dawn = DefaultDawn
+	}
+
+	if opts.Dusk == 0 {
+		opts.Dusk = DefaultDusk
+	}
+
+	return &TimeOfDayTracker{
+		dawn: opts.Dawn,
+		dusk: opts.Dusk,
+	}
+}
+
+// Update evaluates the latest telemetry frame. The session restarts when the vehicle leaves the circuit.
+func (tod *TimeOfDayTracker) Update(t *Transformer) {
+	sequenceID := t.SequenceID()
+	if sequenceID == tod.lastSequence {
+		return
+	}
+
+	tod.lastSequence = sequenceID
+
+	if !t.IsOnCircuit() {
+		tod.started = false
+
+		return
+	}
+
+	timeOfDay := t.TimeOfDay()
+
+	if !tod.started {
+		tod.started = true
+		tod.firstSequence = sequenceID
+		tod.lastTimeOfDay = timeOfDay
+		tod.elapsed = 0
+
+		return
+	}
+
+	delta := timeOfDay - tod.lastTimeOfDay
+	if delta < 0 {
+		// The clock wrapped at midnight
+		delta += dayLength
+	}
+
+	tod.elapsed += delta
+	tod.lastTimeOfDay = timeOfDay
+}
+
+// Current returns the in-game time of day of the latest frame.
+func (tod *TimeOfDayTracker) Current() time.Duration {
+	return tod.lastTimeOfDay
+}
+
+// Elapsed returns the in-game time elapsed since the start of the session.
+func (tod *TimeOfDayTracker) Elapsed() time.Duration {
+	return tod.elapsed
+}
+
+// Start returns the in-game time of day at the start of the session, such as to look up the weather script of an
+// event with WeatherScripts.Lookup.
+func (tod *TimeOfDayTracker) Start() time.Duration {
+	return ((tod.lastTimeOfDay-tod.elapsed)%dayLength + dayLength) % dayLength
+}
+
+// RealElapsed returns the real time elapsed since the start of the session.
+func (tod *TimeOfDayTracker) RealElapsed() time.Duration {
+	if !tod.started {
+		return 0
+	}
+
+	return framesElapsed(tod.firstSequence, tod.lastSequence)
+}
+
+// Acceleration returns how many times faster the in-game clock runs than real time, or zero before any time has
+// elapsed.
+func (tod *TimeOfDayTracker) Acceleration() float64 {
+	realElapsed := tod.RealElapsed()
+	if realElapsed == 0 {
+		return 0
+	}
+
+	return float64(tod.elapsed) / float64(realElapsed)
+}
+
+// IsNight reports whether the in-game time of day is between dusk and dawn.
+func (tod *TimeOfDayTracker) IsNight() bool {
+	return tod.started && IsNight(tod.lastTimeOfDay, tod.dawn, tod.dusk)
+}
+
+// IsNight reports whether a time of day is between dusk and dawn.
+func IsNight(timeOfDay, dawn, dusk time.Duration) bool {
+	timeOfDay %= dayLength
+
+	if dusk > dawn {
+		return timeOfDay >= dusk || timeOfDay < dawn
+	}
+
+	// Night does not span midnight
+	return timeOfDay >= dusk && timeOfDay < dawn
+}
@@ -0,0 +1,122 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type TimeOfDayTrackerTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	tracker     *gttelemetry.TimeOfDayTracker
+}
+
+func TestTimeOfDayTrackerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TimeOfDayTrackerTestSuite))
+}
+
+func (suite *TimeOfDayTrackerTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.tracker = gttelemetry.NewTimeOfDayTracker(gttelemetry.TimeOfDayOptions{})
+}
+
+func (suite *TimeOfDayTrackerTestSuite) frame(timeOfDay time.Duration) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.TimeOfDay = uint32(timeOfDay.Milliseconds()) //nolint:gosec // test values fit
+	suite.tracker.Update(suite.transformer)
+}
+
+func (suite *TimeOfDayTrackerTestSuite) TestElapsedAndAccelerationAreMeasured() {
+	// Arrange
+	timeOfDay := 18 * time.Hour
+
+	// Act — one real second at 60 frames per second, the clock advancing ten seconds per second
+	for range 61 {
+		suite.frame(timeOfDay)
+		timeOfDay += 10 * time.Second / 60
+	}
+
+	// Assert
+	suite.Equal(time.Second, suite.tracker.RealElapsed())
+	suite.InDelta(float64(10*time.Second), float64(suite.tracker.Elapsed()), float64(time.Millisecond*10))
+	suite.InDelta(10, suite.tracker.Acceleration(), 0.01)
+}
+
+func (suite *TimeOfDayTrackerTestSuite) TestElapsedHandlesMidnight() {
+	// Arrange
+	suite.frame(23*time.Hour + 59*time.Minute)
+
+	// Act
+	suite.frame(2 * time.Minute)
+
+	// Assert
+	suite.Equal(3*time.Minute, suite.tracker.Elapsed())
+	suite.Equal(23*time.Hour+59*time.Minute, suite.tracker.Start())
+	suite.True(suite.tracker.IsNight())
+}
+
+func (suite *TimeOfDayTrackerTestSuite) TestLeavingCircuitRestartsSession() {
+	// Arrange
+	suite.frame(12 * time.Hour)
+	suite.frame(13 * time.Hour)
+	suite.transformer.RawTelemetry.RaceEntrants = -1
+	suite.frame(13 * time.Hour)
+	suite.transformer.RawTelemetry.RaceEntrants = 0
+
+	// Act
+	suite.frame(8 * time.Hour)
+
+	// Assert
+	suite.Zero(suite.tracker.Elapsed())
+	suite.Equal(8*time.Hour, suite.tracker.Current())
+}
+
+func (suite *TimeOfDayTrackerTestSuite) TestIsNightUsesConfiguredWindow() {
+	// Arrange
+	suite.tracker = gttelemetry.NewTimeOfDayTracker(gttelemetry.TimeOfDayOptions{
+		Dawn: 5 * time.Hour,
+		Dusk: 21 * time.Hour,
+	})
+
+	// Act
+	suite.frame(20 * time.Hour)
+	evening := suite.tracker.IsNight()
+
+	suite.frame(22 * time.Hour)
+	night := suite.tracker.IsNight()
+
+	// Assert
+	suite.False(evening)
+	suite.True(night)
+}
+
+func (suite *TimeOfDayTrackerTestSuite) TestIsNight() {
+	tests := map[string]struct {
+		timeOfDay time.Duration
+		dawn      time.Duration
+		dusk      time.Duration
+		want      bool
+	}{
+		"midday":             {timeOfDay: 12 * time.Hour, dawn: 6 * time.Hour, dusk: 19 * time.Hour, want: false},
+		"at dusk":            {timeOfDay: 19 * time.Hour, dawn: 6 * time.Hour, dusk: 19 * time.Hour, want: true},
+		"before dawn":        {timeOfDay: 5 * time.Hour, dawn: 6 * time.Hour, dusk: 19 * time.Hour, want: true},
+		"at dawn":            {timeOfDay: 6 * time.Hour, dawn: 6 * time.Hour, dusk: 19 * time.Hour, want: false},
+		"after midnight":     {timeOfDay: 1 * time.Hour, dawn: 4 * time.Hour, dusk: 1 * time.Hour, want: true},
+		"window not wrapped": {timeOfDay: 23 * time.Hour, dawn: 4 * time.Hour, dusk: 1 * time.Hour, want: false},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			suite.Equal(test.want, gttelemetry.IsNight(test.timeOfDay, test.dawn, test.dusk))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package gttelemetry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidTimecode indicates a timecode that cannot be parsed by ParseTimecode.
+var ErrInvalidTimecode = errors.New("invalid timecode")
+
+// TimeSource provides the time that frames are tagged with when they are received, returned by
+// Transformer.ReceivedAt. Setting a shared time source on every rig in a race, such as an NTP or PTP corrected clock
+// or a timecode generator, allows their captures to be aligned afterwards.
+type TimeSource interface {
+	Now() time.Time
+}
+
+// SystemClock is a TimeSource that reads the local system clock.
+type SystemClock struct{}
+
+// Now returns the current local time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// OffsetClock is a TimeSource that corrects the local system clock by an offset, such as the offset from an NTP
+// server or PTP grandmaster measured by the caller. The offset can be updated at any time while frames are received.
+type OffsetClock struct {
+	offset atomic.Int64
+}
+
+// NewOffsetClock creates a clock that adds the offset to the local system clock.
+func NewOffsetClock(offset time.Duration) *OffsetClock {
+	clock := &OffsetClock{}
+	clock.SetOffset(offset)
+
+	return clock
+}
+
+// SetOffset sets the offset added to the local system clock.
+func (c *OffsetClock) SetOffset(offset time.Duration) {
+	c.offset.Store(int64(offset))
+}
+
+// Offset returns the offset added to the local system clock.
+func (c *OffsetClock) Offset() time.Duration {
+	return time.Duration(c.offset.Load())
+}
+
+// Now returns the corrected time.
+func (c *OffsetClock) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Timecode is a SMPTE time of day timecode, as carried by linear timecode (LTC).
+type Timecode struct {
+	Hours   int `json:"hours"`
+	Minutes int `json:"minutes"`
+	Seconds int `json:"seconds"`
+	Frames  int `json:"frames"`
+	// Rate is the number of frames per second.
+	Rate int `json:"rate"`
+}
+
+// ParseTimecode parses a timecode in the form hh:mm:ss:ff at the given frame rate.
+func ParseTimecode(value string, rate int) (Timecode, error) {
+	timecode := Timecode{Rate: rate}
+
+	_, err := fmt.Sscanf(value, "%2d:%2d:%2d:%2d", &timecode.Hours, &timecode.Minutes, &timecode.Seconds, &timecode.Frames)
+	if err != nil {
+		return Timecode{}, fmt.Errorf("%w: %q: %w", ErrInvalidTimecode, value, err)
+	}
+
+	if rate <= 0 || timecode.Hours < 0 || timecode.Hours > 23 || timecode.Minutes < 0 || timecode.Minutes > 59 ||
+		timecode.Seconds < 0 || timecode.Seconds > 59 || timecode.Frames < 0 || timecode.Frames >= rate {
+		return Timecode{}, fmt.Errorf("%w: %q at %d fps", ErrInvalidTimecode, value, rate)
+	}
+
+	return timecode, nil
+}
+
+// String returns the timecode in the form hh:mm:ss:ff.
+func (t Timecode) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", t.Hours, t.Minutes, t.Seconds, t.Frames)
+}
+
+// Duration returns the time since midnight represented by the timecode.
+func (t Timecode) Duration() time.Duration {
+	duration := time.Duration(t.Hours)*time.Hour + time.Duration(t.Minutes)*time.Minute +
+		time.Duration(t.Seconds)*time.Second
+
+	if t.Rate > 0 {
+		duration += time.Duration(t.Frames) * time.Second / time.Duration(t.Rate)
+	}
+
+	return duration
+}
+
+// TimecodeClock is a TimeSource driven by an external timecode, such as LTC decoded from an audio input. The decoder
+// calls Sync with every timecode it reads, and the time is advanced from the local clock between timecodes. The
+// timecode is taken as the time of day in UTC on the date it was last synchronised.
+type TimecodeClock struct {
+	mu       sync.Mutex
+	base     time.Time
+	syncedAt time.Time
+	local    TimeSource
+}
+
+// NewTimecodeClock creates a clock that advances from the local time source between timecodes, SystemClock when nil,
+// and returns the local time until it is first synchronised.
+func NewTimecodeClock(local TimeSource) *TimecodeClock {
+	if local == nil {
+		local = SystemClock{}
+	}
+
+	return &TimecodeClock{local: local}
+}
+
+// Sync sets the clock to a timecode that has just been read.
+func (c *TimecodeClock) Sync(timecode Timecode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.syncedAt = c.local.Now()
+	midnight := c.syncedAt.UTC().Truncate(24 * time.Hour)
+	c.base = midnight.Add(timecode.Duration())
+}
+
+// Now returns the time of the last timecode advanced by the time since it was read.
+func (c *TimecodeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.local.Now()
+	if c.base.IsZero() {
+		return now
+	}
+
+	return c.base.Add(now.Sub(c.syncedAt))
+}
@@ -0,0 +1,99 @@
+package gttelemetry_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+// fakeClock is a TimeSource that returns a time set by the test.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+type TimeSourceTestSuite struct {
+	suite.Suite
+}
+
+func TestTimeSourceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TimeSourceTestSuite))
+}
+
+func (suite *TimeSourceTestSuite) TestParseTimecode() {
+	tests := map[string]struct {
+		value   string
+		rate    int
+		want    time.Duration
+		wantErr bool
+	}{
+		"valid": {
+			value: "10:30:15:12",
+			rate:  25,
+			want:  10*time.Hour + 30*time.Minute + 15*time.Second + 480*time.Millisecond,
+		},
+		"frame beyond rate": {
+			value:   "10:30:15:25",
+			rate:    25,
+			wantErr: true,
+		},
+		"not a timecode": {
+			value:   "half past ten",
+			rate:    25,
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			timecode, err := gttelemetry.ParseTimecode(test.value, test.rate)
+
+			// Assert
+			if test.wantErr {
+				suite.ErrorIs(err, gttelemetry.ErrInvalidTimecode)
+
+				return
+			}
+
+			suite.Require().NoError(err)
+			suite.Equal(test.want, timecode.Duration())
+			suite.Equal(test.value, timecode.String())
+		})
+	}
+}
+
+func (suite *TimeSourceTestSuite) TestTimecodeClockAdvancesFromSync() {
+	// Arrange
+	local := &fakeClock{now: time.Date(2026, 5, 17, 10, 29, 0, 0, time.UTC)}
+	clock := gttelemetry.NewTimecodeClock(local)
+	suite.Equal(local.now, clock.Now())
+
+	timecode, err := gttelemetry.ParseTimecode("10:30:00:00", 30)
+	suite.Require().NoError(err)
+
+	// Act
+	clock.Sync(timecode)
+	local.now = local.now.Add(1500 * time.Millisecond)
+
+	// Assert
+	suite.Equal(time.Date(2026, 5, 17, 10, 30, 1, 500_000_000, time.UTC), clock.Now())
+}
+
+func (suite *TimeSourceTestSuite) TestOffsetClock() {
+	// Arrange
+	clock := gttelemetry.NewOffsetClock(time.Hour)
+
+	// Act
+	clock.SetOffset(-time.Hour)
+
+	// Assert
+	suite.Equal(-time.Hour, clock.Offset())
+	suite.WithinDuration(time.Now().Add(-time.Hour), clock.Now(), time.Second)
+}
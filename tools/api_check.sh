@@ -0,0 +1,45 @@
+#!/bin/sh
+
+# Report changes to the public API that are incompatible with the last v2 release
+# tag, using apidiff. Packages under internal/ are not compared, as they cannot be
+# imported by other modules. Nothing is compared when there is no release tag yet,
+# such as in a shallow clone.
+#
+# Set APIDIFF to use an installed apidiff rather than the latest release.
+#
+# Usage: api_check.sh [tag]
+
+set -eu
+
+MODULE="github.com/zetetos/gt-telemetry/v2"
+APIDIFF=${APIDIFF:-"go run golang.org/x/exp/cmd/apidiff@latest"}
+
+if [ "$#" -gt 1 ]; then
+  echo "Usage: $0 [tag]" >&2
+  exit 1
+fi
+
+tag=${1:-$(git describe --tags --abbrev=0 --match 'v2.*' 2>/dev/null || true)}
+
+if [ -z "$tag" ]; then
+  echo "No v2 release tag to compare the API with, skipping"
+  exit 0
+fi
+
+tmp=$(mktemp -d)
+trap 'git worktree remove --force "$tmp/old" >/dev/null 2>&1 || true; rm -rf "$tmp"' EXIT
+
+git worktree add --detach "$tmp/old" "$tag" >/dev/null 2>&1
+
+(cd "$tmp/old" && $APIDIFF -m -w "$tmp/old.api" "$MODULE")
+$APIDIFF -m -w "$tmp/new.api" "$MODULE"
+
+report=$($APIDIFF -m -incompatible "$tmp/old.api" "$tmp/new.api")
+
+if [ -n "$report" ]; then
+  echo "Incompatible changes to the API since $tag:" >&2
+  echo "$report" >&2
+  exit 1
+fi
+
+echo "API is compatible with $tag"
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/internal/apimanifest"
+)
+
+const (
+	modulePath = "github.com/zetetos/gt-telemetry/v2"
+	usage      = `Usage:
+  %s [module_dir]
+
+Writes the API manifest of the root package and the packages under pkg/ to stdout, for example:
+  go run ./tools/api_manifest > api.txt
+`
+)
+
+func main() {
+	if len(os.Args) > 2 {
+		fmt.Fprintf(os.Stderr, usage, os.Args[0])
+		os.Exit(1)
+	}
+
+	dir := "."
+	if len(os.Args) == 2 {
+		dir = os.Args[1]
+	}
+
+	manifest, err := apimanifest.Generate(dir, modulePath, gttelemetry.APIVersion(), "pkg")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	_, err = os.Stdout.Write(manifest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: writing manifest: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -56,6 +56,9 @@ type Config struct {
 	VariationName string
 	Default       bool
 	CountryCode   string
+	ResumeFile    string
+	InventoryDir  string
+	Force         bool
 }
 
 // CircuitCapture handles the capture process state.
@@ -75,6 +78,8 @@ type CircuitCapture struct {
 	captureActive     bool
 	extentsInit       bool
 	ready             bool
+	resuming          bool
+	resumeFrom        gtmodels.Coordinate
 }
 
 func main() {
@@ -103,7 +108,8 @@ func main() {
 	for {
 		select {
 		case <-sigChan:
-			fmt.Println("\nRecording interrupted, file save aborted.")
+			fmt.Println("\nRecording interrupted.")
+			capture.savePartialCapture()
 
 			return
 		default:
@@ -119,6 +125,10 @@ func main() {
 					return
 				}
 
+				if errors.Is(err, ErrSessionExitedEarly) {
+					capture.savePartialCapture()
+				}
+
 				log.Fatalf("Error during capture: %v", err)
 			}
 		}
@@ -133,6 +143,9 @@ func parseFlags() *Config {
 	flag.StringVar(&config.VariationName, "v", "", "Circuit variation name (defaults to circuit name)")
 	flag.BoolVar(&config.Default, "default", false, "Set as default variation for the circuit")
 	flag.StringVar(&config.CountryCode, "c", "", "Circuit country code iso 3166-1 (required)")
+	flag.StringVar(&config.ResumeFile, "resume", "", "Partial capture file to resume, circuit details are taken from the file when not set")
+	flag.StringVar(&config.InventoryDir, "inventory", defaultInventoryDir, "Inventory directory the capture is validated against")
+	flag.BoolVar(&config.Force, "force", false, "Save the capture without prompting when it collides with an existing layout")
 	flag.Parse()
 
 	return config
@@ -140,7 +153,8 @@ func parseFlags() *Config {
 
 // validate checks if the config is valid.
 func (c *Config) validate() error {
-	if c.Name == "" {
+	// Circuit details are taken from the partial capture when resuming
+	if c.Name == "" && c.ResumeFile == "" {
 		return ErrCircuitNameRequired
 	}
 
@@ -148,7 +162,7 @@ func (c *Config) validate() error {
 		c.VariationName = c.Name
 	}
 
-	if c.CountryCode == "" {
+	if c.CountryCode == "" && c.ResumeFile == "" {
 		return ErrCircuitCountryCodeRequired
 	}
 
@@ -204,6 +218,13 @@ func NewCircuitCapture(config *Config) (*CircuitCapture, error) {
 	newCircuit.circuitData.Default = config.Default
 	newCircuit.circuitData.CountryCode = config.CountryCode
 
+	if config.ResumeFile != "" {
+		err = newCircuit.loadPartialCapture(config.ResumeFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return newCircuit, nil
 }
 
@@ -286,6 +307,12 @@ func (c *CircuitCapture) processCapture() error {
 
 	currentLap := c.gt.Telemetry.CurrentLap()
 
+	if c.resuming {
+		c.waitForResumePoint(c.gt.Telemetry.PositionalMapCoordinates(), currentLap)
+
+		return nil
+	}
+
 	// Lap start detection
 	if !c.gt.Telemetry.IsInMainMenu() && currentLap != c.lastLap {
 		if c.captureActive {
@@ -294,6 +321,12 @@ func (c *CircuitCapture) processCapture() error {
 			c.circuitData.LengthMetres = int(math.Round(c.distanceTravelled))
 			dropped := c.gt.Statistics.PacketsDropped - c.startDropped
 
+			if !c.checkInventory() {
+				fmt.Println("Capture discarded.")
+
+				return ErrCaptureComplete
+			}
+
 			err := c.saveCircuitData(dropped)
 			if err != nil {
 				return fmt.Errorf("failed to save circuit data: %w", err)
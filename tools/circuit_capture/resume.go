@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+
+	gtmodels "github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+// resumeRadiusMetres is how close the vehicle must be to the end of a partial capture for the capture to resume.
+const resumeRadiusMetres = 10
+
+var ErrPartialCaptureEmpty = errors.New("partial capture contains no coordinates")
+
+// partialFilename returns the path of the partial capture file for the circuit.
+func (c *CircuitCapture) partialFilename() string {
+	return path.Join(c.config.OutputDir, nameToID(c.circuitData.VariationName)+".partial.json")
+}
+
+// savePartialCapture saves the coordinates captured so far so that the capture can be resumed later. Nothing is saved
+// if the capture has not started.
+func (c *CircuitCapture) savePartialCapture() {
+	if !c.captureActive || len(c.circuitData.Coordinates.Circuit) == 0 {
+		return
+	}
+
+	c.circuitData.LengthMetres = int(math.Round(c.distanceTravelled))
+
+	filename := c.partialFilename()
+
+	data, err := json.MarshalIndent(c.circuitData, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filename, data, 0o600)
+	}
+
+	if err != nil {
+		fmt.Printf("Failed to save partial capture: %v\n", err)
+
+		return
+	}
+
+	fmt.Printf("Partial capture of %d points saved to %s\n", len(c.circuitData.Coordinates.Circuit), filename)
+	fmt.Printf("Resume the capture with: -resume %s\n", filename)
+}
+
+// loadPartialCapture loads a partial capture to be resumed. The circuit details are taken from the partial capture
+// unless they were set on the command line.
+func (c *CircuitCapture) loadPartialCapture(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("read partial capture: %w", err)
+	}
+
+	var partial CircuitData
+
+	err = json.Unmarshal(data, &partial)
+	if err != nil {
+		return fmt.Errorf("parse partial capture: %w", err)
+	}
+
+	if len(partial.Coordinates.Circuit) == 0 {
+		return fmt.Errorf("%w: %s", ErrPartialCaptureEmpty, filename)
+	}
+
+	if c.config.Name == "" {
+		c.circuitData.Name = partial.Name
+		c.circuitData.VariationName = partial.VariationName
+		c.circuitData.Default = partial.Default
+	}
+
+	if c.config.CountryCode == "" {
+		c.circuitData.CountryCode = partial.CountryCode
+	}
+
+	// Replaying the coordinates rebuilds the extents and distance travelled as well as the path
+	for _, coordinate := range partial.Coordinates.Circuit {
+		c.updateCoordinate(coordinate)
+	}
+
+	c.circuitData.Coordinates.StartingLine = partial.Coordinates.StartingLine
+	c.resumeFrom = c.lastCoordinate
+	c.resuming = true
+
+	fmt.Printf("Loaded partial capture of %d points from %s\n", len(partial.Coordinates.Circuit), filename)
+	fmt.Printf("Capture will resume at X = %.4f, Y = %.4f, Z = %.4f\n", c.resumeFrom.X, c.resumeFrom.Y, c.resumeFrom.Z)
+
+	return nil
+}
+
+// waitForResumePoint resumes the capture once the vehicle reaches the end of the partial capture. The remainder of
+// the lap is then captured up to the start line, where the partial capture began.
+func (c *CircuitCapture) waitForResumePoint(coordinate gtmodels.Coordinate, currentLap int16) {
+	// Lap changes before the resume point is reached are not the end of the captured lap
+	c.lastLap = currentLap
+
+	if c.gt.Telemetry.IsInMainMenu() {
+		return
+	}
+
+	dx := float64(coordinate.X - c.resumeFrom.X)
+	dy := float64(coordinate.Y - c.resumeFrom.Y)
+	dz := float64(coordinate.Z - c.resumeFrom.Z)
+
+	if math.Sqrt(dx*dx+dy*dy+dz*dz) > resumeRadiusMetres {
+		return
+	}
+
+	fmt.Println("Resume point reached, capture resumed.")
+
+	c.resuming = false
+	c.captureActive = true
+	c.startDropped = c.gt.Statistics.PacketsDropped
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	gtcircuits "github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	gtmodels "github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+const (
+	defaultInventoryDir = "./pkg/circuits/inventory/"
+
+	// minUniqueCoordinates is the number of coordinates a circuit must not share with any other circuit for it to be
+	// reliably identified while driving.
+	minUniqueCoordinates = 10
+)
+
+// circuitOverlap is the number of normalised coordinates a capture shares with an existing circuit.
+type circuitOverlap struct {
+	ID     string
+	Shared int
+	Unique int // unique coordinates left to the existing circuit once the capture is added
+}
+
+// captureValidation is the result of identifying a capture against the existing inventory.
+type captureValidation struct {
+	Coordinates       int
+	UniqueCoordinates int
+	IdentifiedAs      string
+	StartLineShared   []string
+	Overlaps          []circuitOverlap
+}
+
+// collides reports whether the capture cannot be told apart from an existing layout, or leaves an existing layout
+// that can no longer be told apart from the capture. A shared start line is common between layouts of a circuit, so
+// it is only reported.
+func (v *captureValidation) collides() bool {
+	if v.UniqueCoordinates < minUniqueCoordinates {
+		return true
+	}
+
+	for _, overlap := range v.Overlaps {
+		if overlap.Unique < minUniqueCoordinates {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadInventory reads the normalised coordinates of each circuit in the inventory directory.
+func loadInventory(inventoryDir string) (map[string]gtcircuits.CircuitInfo, error) {
+	entries, err := os.ReadDir(inventoryDir)
+	if err != nil {
+		return nil, fmt.Errorf("read inventory directory: %w", err)
+	}
+
+	inventory := make(map[string]gtcircuits.CircuitInfo)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || entry.Name() == "manifest.json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(inventoryDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read inventory file: %w", err)
+		}
+
+		var info gtcircuits.CircuitInfo
+
+		err = json.Unmarshal(data, &info)
+		if err != nil {
+			return nil, fmt.Errorf("parse inventory file %s: %w", entry.Name(), err)
+		}
+
+		if info.ID == "" {
+			info.ID = strings.TrimSuffix(entry.Name(), ".json")
+		}
+
+		inventory[info.ID] = info
+	}
+
+	return inventory, nil
+}
+
+// gridCoordinate is a normalised coordinate qualified by the grid it was normalised to, so that circuits with their own
+// coordinate resolution are compared on their grid, as the circuit database does when identifying circuits.
+type gridCoordinate struct {
+	resolution gtcircuits.Resolution
+	coordinate gtmodels.CoordinateNorm
+}
+
+// validateCapture identifies the captured circuit against the inventory. A previous capture of the same layout is
+// ignored, since saving the capture replaces it.
+func validateCapture(data *CircuitData, inventory map[string]gtcircuits.CircuitInfo) *captureValidation {
+	captureID := nameToID(data.VariationName)
+
+	// Count the circuits claiming each coordinate on their own grid, excluding the capture
+	resolutions := []gtcircuits.Resolution{gtcircuits.DefaultCircuitResolution()}
+	claims := make(map[gridCoordinate]int)
+
+	for id, info := range inventory {
+		if id == captureID {
+			continue
+		}
+
+		resolution := info.CircuitResolution()
+		if !slices.Contains(resolutions, resolution) {
+			resolutions = append(resolutions, resolution)
+		}
+
+		seen := make(map[gtmodels.CoordinateNorm]bool, len(info.Coordinates))
+
+		for _, coordinate := range info.Coordinates {
+			if !seen[coordinate] {
+				seen[coordinate] = true
+				claims[gridCoordinate{resolution, coordinate}]++
+			}
+		}
+	}
+
+	// The capture is saved on the default grid, and is normalised to the grid of every circuit to compare with them
+	captured := make(map[gridCoordinate]bool)
+	claimed := make(map[gtmodels.CoordinateNorm]bool)
+
+	for _, coordinate := range data.Coordinates.Circuit {
+		isClaimed := false
+
+		for _, resolution := range resolutions {
+			key := gridCoordinate{resolution, resolution.Normalise(coordinate)}
+			captured[key] = true
+			isClaimed = isClaimed || claims[key] > 0
+		}
+
+		normalised := gtcircuits.NormaliseCircuitCoordinate(coordinate)
+		claimed[normalised] = claimed[normalised] || isClaimed
+	}
+
+	validation := &captureValidation{Coordinates: len(claimed)}
+
+	for _, isClaimed := range claimed {
+		if !isClaimed {
+			validation.UniqueCoordinates++
+		}
+	}
+
+	for id, info := range inventory {
+		if id == captureID {
+			continue
+		}
+
+		// Start lines are compared on the grid of each circuit, which is finer for layouts starting close together
+		if info.StartResolution().Normalise(data.Coordinates.StartingLine) == info.StartLine {
+			validation.StartLineShared = append(validation.StartLineShared, id)
+		}
+
+		resolution := info.CircuitResolution()
+		overlap := circuitOverlap{ID: id}
+		seen := make(map[gtmodels.CoordinateNorm]bool, len(info.Coordinates))
+
+		for _, coordinate := range info.Coordinates {
+			if seen[coordinate] {
+				continue
+			}
+
+			seen[coordinate] = true
+			key := gridCoordinate{resolution, coordinate}
+
+			if captured[key] {
+				overlap.Shared++
+			} else if claims[key] == 1 {
+				overlap.Unique++
+			}
+		}
+
+		if overlap.Shared > 0 {
+			validation.Overlaps = append(validation.Overlaps, overlap)
+		}
+	}
+
+	sort.Strings(validation.StartLineShared)
+	sort.Slice(validation.Overlaps, func(i, j int) bool {
+		if validation.Overlaps[i].Shared != validation.Overlaps[j].Shared {
+			return validation.Overlaps[i].Shared > validation.Overlaps[j].Shared
+		}
+
+		return validation.Overlaps[i].ID < validation.Overlaps[j].ID
+	})
+
+	if len(validation.Overlaps) > 0 {
+		validation.IdentifiedAs = validation.Overlaps[0].ID
+	}
+
+	return validation
+}
+
+// printValidation prints the result of identifying the capture against the inventory.
+func printValidation(validation *captureValidation) {
+	fmt.Println("#### Inventory Validation ####")
+	fmt.Printf("Unique coordinates: %d of %d\n", validation.UniqueCoordinates, validation.Coordinates)
+
+	if validation.IdentifiedAs != "" {
+		fmt.Printf("Closest existing layout: %s\n", validation.IdentifiedAs)
+	}
+
+	if validation.UniqueCoordinates < minUniqueCoordinates {
+		fmt.Printf("⚠️  Capture has fewer than %d unique coordinates and may be identified as an existing layout.\n", minUniqueCoordinates)
+	}
+
+	if len(validation.StartLineShared) > 0 {
+		fmt.Printf("Start line is shared with: %s\n", strings.Join(validation.StartLineShared, ", "))
+	}
+
+	for _, overlap := range validation.Overlaps {
+		if overlap.Unique < minUniqueCoordinates {
+			fmt.Printf("⚠️  %s would be left with %d unique coordinates.\n", overlap.ID, overlap.Unique)
+		}
+	}
+}
+
+// confirmSave asks the user whether to save a capture that collides with an existing layout.
+func confirmSave() bool {
+	fmt.Print("Capture collides with an existing layout. Save anyway? [y/N] ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+// checkInventory validates the capture against the inventory, returning false if the capture should not be saved.
+// The capture is saved without validation if the inventory cannot be read.
+func (c *CircuitCapture) checkInventory() bool {
+	inventory, err := loadInventory(c.config.InventoryDir)
+	if err != nil {
+		fmt.Printf("Skipping inventory validation: %v\n", err)
+
+		return true
+	}
+
+	validation := validateCapture(&c.circuitData, inventory)
+	printValidation(validation)
+	fmt.Println()
+
+	if !validation.collides() || c.config.Force {
+		return true
+	}
+
+	return confirmSave()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gtcircuits "github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	gtmodels "github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+type ValidateTestSuite struct {
+	suite.Suite
+}
+
+func TestValidateTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ValidateTestSuite))
+}
+
+// straight returns coordinates every 10 metres along a straight from the x position.
+func straight(x float32, points int) []gtmodels.Coordinate {
+	coordinates := make([]gtmodels.Coordinate, 0, points)
+
+	for i := range points {
+		coordinates = append(coordinates, gtmodels.Coordinate{X: x + float32(i*10) + 5, Y: 1, Z: 5})
+	}
+
+	return coordinates
+}
+
+// inventoryCircuit returns a circuit with the coordinates normalised to the resolution.
+func inventoryCircuit(id string, resolution gtcircuits.Resolution, coordinates []gtmodels.Coordinate) gtcircuits.CircuitInfo {
+	info := gtcircuits.CircuitInfo{ID: id}
+	if resolution != gtcircuits.DefaultCircuitResolution() {
+		info.CoordinateResolution = &resolution
+	}
+
+	for _, coordinate := range coordinates {
+		info.Coordinates = append(info.Coordinates, resolution.Normalise(coordinate))
+	}
+
+	return info
+}
+
+func (suite *ValidateTestSuite) TestCaptureIsComparedOnGridOfEachCircuit() {
+	// Arrange
+	fine := gtcircuits.Resolution{X: 10, Y: 2, Z: 10}
+	layout := straight(0, 40)
+
+	inventory := map[string]gtcircuits.CircuitInfo{
+		"harbourFine":  inventoryCircuit("harbourFine", fine, layout),
+		"elsewhereDef": inventoryCircuit("elsewhereDef", gtcircuits.DefaultCircuitResolution(), straight(5000, 40)),
+	}
+
+	data := &CircuitData{VariationName: "Harbour Copy", Coordinates: CircuitCoordinates{Circuit: layout}}
+
+	// Act
+	validation := validateCapture(data, inventory)
+
+	// Assert
+	suite.Equal("harbourFine", validation.IdentifiedAs)
+	suite.Zero(validation.UniqueCoordinates, "every captured coordinate is claimed by the circuit on its own grid")
+	suite.Require().Len(validation.Overlaps, 1)
+	suite.Equal(40, validation.Overlaps[0].Shared)
+	suite.Zero(validation.Overlaps[0].Unique)
+	suite.True(validation.collides())
+}
+
+func (suite *ValidateTestSuite) TestCaptureOfNewLayoutIsUnique() {
+	// Arrange
+	inventory := map[string]gtcircuits.CircuitInfo{
+		"harbourFine": inventoryCircuit("harbourFine", gtcircuits.Resolution{X: 10, Y: 2, Z: 10}, straight(0, 40)),
+	}
+
+	data := &CircuitData{VariationName: "Harbour East", Coordinates: CircuitCoordinates{Circuit: straight(2000, 40)}}
+
+	// Act
+	validation := validateCapture(data, inventory)
+
+	// Assert
+	suite.Empty(validation.IdentifiedAs)
+	suite.Equal(validation.Coordinates, validation.UniqueCoordinates)
+	suite.Empty(validation.Overlaps)
+	suite.False(validation.collides())
+}
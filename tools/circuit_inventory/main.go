@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/santhosh-tekuri/jsonschema/v5"
 	gtcircuits "github.com/zetetos/gt-telemetry/v2/pkg/circuits"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
 	gtmodels "github.com/zetetos/gt-telemetry/v2/pkg/models"
 )
 
@@ -28,6 +30,9 @@ type CircuitData struct {
 	Default       bool               `json:"default"`
 	Country       string             `json:"country"`
 	LengthMetres  int                `json:"lengthMetres"`
+	ReverseOf     string             `json:"reverseOf,omitempty"`
+	PitLoss       float64            `json:"pitLossSeconds,omitempty"`
+	Anchor        *geo.Anchor        `json:"anchor,omitempty"`
 	LastModified  string             `json:"lastModified"`
 	Coordinates   CircuitCoordinates `json:"coordinates"`
 }
@@ -37,6 +42,8 @@ type CircuitProcessingResult struct {
 	CoordinateMap          map[string][]string
 	CircuitsMap            map[string]map[string]any
 	CircuitStartLines      map[string]gtmodels.CoordinateNorm
+	StartLineResolutions   map[string]gtcircuits.Resolution // Grid of each circuit's start line
+	RawStartLines          map[string]gtmodels.Coordinate   // Full precision start line of each circuit
 	CircuitCoordinatesNorm map[string][]gtmodels.CoordinateNorm
 	RawCoordinateCounts    map[string]int // Track raw coordinate counts per circuit
 	LastModified           time.Time      // Most recent modification time of any source circuit file
@@ -96,6 +103,12 @@ func runUpdate(circuitsDir, outputDir string) {
 		os.Exit(1)
 	}
 
+	// Tell apart start lines that only share a cell of the default grid
+	refineStartLines(processed)
+
+	// Check reverse layouts refer to a forward layout in the inventory
+	checkReverseLayouts(processed)
+
 	// Analyze circuit coordinates
 	stats := analyzeCircuitCoordinates(processed)
 
@@ -210,6 +223,8 @@ func processCircuitFiles(circuitsDir, outputFile string) (*CircuitProcessingResu
 		CoordinateMap:          make(map[string][]string),
 		CircuitsMap:            make(map[string]map[string]any),
 		CircuitStartLines:      make(map[string]gtmodels.CoordinateNorm),
+		StartLineResolutions:   make(map[string]gtcircuits.Resolution),
+		RawStartLines:          make(map[string]gtmodels.Coordinate),
 		CircuitCoordinatesNorm: make(map[string][]gtmodels.CoordinateNorm),
 		RawCoordinateCounts:    make(map[string]int),
 	}
@@ -287,18 +302,37 @@ func processSingleCircuitFile(path string, processed *CircuitProcessingResult, s
 	// Store starting line for analysis
 	startingLineNorm := gtcircuits.NormaliseStartLineCoordinate(circuitData.Coordinates.StartingLine)
 	processed.CircuitStartLines[circuitID] = startingLineNorm
+	processed.StartLineResolutions[circuitID] = gtcircuits.DefaultStartLineResolution()
+	processed.RawStartLines[circuitID] = circuitData.Coordinates.StartingLine
+
+	// Pin the anchor to the starting line unless another point is given
+	anchor := circuitData.Anchor
+	if anchor != nil && anchor.X == 0 && anchor.Z == 0 {
+		anchor.X = float64(startingLineNorm.X)
+		anchor.Z = float64(startingLineNorm.Z)
+	}
+
+	// Link a reverse layout to the layout it is the reverse of
+	forwardID := ""
+	if circuitData.ReverseOf != "" {
+		forwardID = nameToID(circuitData.ReverseOf)
+	}
 
 	// Store circuit info
 	processed.CircuitsMap[circuitID] = map[string]any{
-		"id":           circuitID,
-		"name":         circuitData.Name,
-		"variation":    circuitData.VariationName,
-		"default":      circuitData.Default,
-		"country":      circuitData.Country,
-		"length":       uint16(circuitData.LengthMetres), //nolint:gosec // Length will always be positive and less than max uint16
-		"startline":    startingLineNorm,
-		"lastModified": circuitLastModified,
-		"coordinates":  processed.CircuitCoordinatesNorm[circuitID],
+		"id":                  circuitID,
+		"name":                circuitData.Name,
+		"variation":           circuitData.VariationName,
+		"default":             circuitData.Default,
+		"country":             circuitData.Country,
+		"length":              uint16(circuitData.LengthMetres), //nolint:gosec // Length will always be positive and less than max uint16
+		"startline":           startingLineNorm,
+		"startLineResolution": (*gtcircuits.Resolution)(nil),
+		"forwardId":           forwardID,
+		"pitLoss":             circuitData.PitLoss,
+		"anchor":              anchor,
+		"lastModified":        circuitLastModified,
+		"coordinates":         processed.CircuitCoordinatesNorm[circuitID],
 	}
 
 	return nil
@@ -366,7 +400,7 @@ func analyzeCircuitCoordinates(processed *CircuitProcessingResult) (stats []Circ
 
 		// Check if starting line coordinate is unique
 		startLineCoord := processed.CircuitStartLines[circuitID]
-		startLineUnique := isStartLineUnique(circuitID, startLineCoord, processed.CircuitStartLines)
+		startLineUnique := isStartLineUnique(circuitID, startLineCoord, processed)
 
 		rawCoords := processed.RawCoordinateCounts[circuitID]
 
@@ -388,9 +422,11 @@ func analyzeCircuitCoordinates(processed *CircuitProcessingResult) (stats []Circ
 }
 
 // isStartLineUnique checks if a circuit's starting line coordinate is unique.
-func isStartLineUnique(circuitID string, startLineCoord gtmodels.CoordinateNorm, allStartLines map[string]gtmodels.CoordinateNorm) bool {
-	for otherCircuitID, otherStartLine := range allStartLines {
-		if otherCircuitID != circuitID && otherStartLine == startLineCoord {
+func isStartLineUnique(circuitID string, startLineCoord gtmodels.CoordinateNorm, processed *CircuitProcessingResult) bool {
+	resolution := processed.StartLineResolutions[circuitID]
+
+	for otherCircuitID, otherStartLine := range processed.CircuitStartLines {
+		if otherCircuitID != circuitID && otherStartLine == startLineCoord && processed.StartLineResolutions[otherCircuitID] == resolution {
 			return false
 		}
 	}
@@ -398,6 +434,66 @@ func isStartLineUnique(circuitID string, startLineCoord gtmodels.CoordinateNorm,
 	return true
 }
 
+// refineStartLines moves the start lines of circuits that share a cell of the default start line grid to the
+// coarsest of startLineRefinements that gives each of them a cell of its own. Start lines closer together than a cell
+// of the grid only fall into separate cells by chance, so layouts that start in the same place, such as a circuit and
+// its variant without a chicane, keep the default grid.
+func refineStartLines(processed *CircuitProcessingResult) {
+	groups := make(map[gtmodels.CoordinateNorm][]string)
+
+	for circuitID, startLine := range processed.CircuitStartLines {
+		groups[startLine] = append(groups[startLine], circuitID)
+	}
+
+	for _, circuitIDs := range groups {
+		if len(circuitIDs) < 2 {
+			continue
+		}
+
+		for _, resolution := range startLineRefinements {
+			if !startLinesApart(processed, circuitIDs, resolution) {
+				continue
+			}
+
+			cells := make(map[gtmodels.CoordinateNorm]bool, len(circuitIDs))
+			for _, circuitID := range circuitIDs {
+				cells[resolution.Normalise(processed.RawStartLines[circuitID])] = true
+			}
+
+			if len(cells) < len(circuitIDs) {
+				continue
+			}
+
+			for _, circuitID := range circuitIDs {
+				startLine := resolution.Normalise(processed.RawStartLines[circuitID])
+				processed.CircuitStartLines[circuitID] = startLine
+				processed.StartLineResolutions[circuitID] = resolution
+				processed.CircuitsMap[circuitID]["startline"] = startLine
+				processed.CircuitsMap[circuitID]["startLineResolution"] = &resolution
+			}
+
+			fmt.Printf("Start lines of %s refined to a %s grid\n", strings.Join(circuitIDs, ", "), resolution)
+
+			break
+		}
+	}
+}
+
+// checkReverseLayouts warns of reverse layouts whose forward layout is not in the inventory, which would be shown
+// with their own variation name.
+func checkReverseLayouts(processed *CircuitProcessingResult) {
+	for circuitID, circuitData := range processed.CircuitsMap {
+		forwardID, _ := circuitData["forwardId"].(string)
+		if forwardID == "" {
+			continue
+		}
+
+		if _, found := processed.CircuitsMap[forwardID]; !found {
+			fmt.Fprintf(os.Stderr, "⚠️  %s is the reverse of %s, which is not in the inventory\n", circuitID, forwardID)
+		}
+	}
+}
+
 // sortStatsByVariationName sorts circuit stats alphabetically by name.
 func sortStatsByVariationName(stats []CircuitStats) {
 	for i := range len(stats) - 1 {
@@ -516,15 +612,20 @@ func writeCircuitInventoryFiles(processed *CircuitProcessingResult, inventoryDir
 
 	for circuitID, circuitData := range processed.CircuitsMap {
 		file := gtcircuits.CircuitInfo{ //nolint:forcetypeassert // Safe due to controlled data source
-			ID:           circuitData["id"].(string),
-			Name:         circuitData["name"].(string),
-			Variation:    circuitData["variation"].(string),
-			Country:      circuitData["country"].(string),
-			Default:      circuitData["default"].(bool),
-			Length:       int(circuitData["length"].(uint16)),
-			StartLine:    circuitData["startline"].(gtmodels.CoordinateNorm),
-			LastModified: circuitData["lastModified"].(time.Time),
-			Coordinates:  circuitData["coordinates"].([]gtmodels.CoordinateNorm),
+			ID:                  circuitData["id"].(string),
+			Name:                circuitData["name"].(string),
+			Variation:           circuitData["variation"].(string),
+			Country:             circuitData["country"].(string),
+			Default:             circuitData["default"].(bool),
+			Length:              int(circuitData["length"].(uint16)),
+			StartLine:           circuitData["startline"].(gtmodels.CoordinateNorm),
+			StartLineResolution: circuitData["startLineResolution"].(*gtcircuits.Resolution),
+			Reverse:             circuitData["forwardId"].(string) != "",
+			ForwardID:           circuitData["forwardId"].(string),
+			PitLoss:             circuitData["pitLoss"].(float64),
+			Anchor:              circuitData["anchor"].(*geo.Anchor),
+			LastModified:        circuitData["lastModified"].(time.Time),
+			Coordinates:         circuitData["coordinates"].([]gtmodels.CoordinateNorm),
 		}
 
 		outData, err := marshalCircuitJSON(file)
@@ -545,10 +646,33 @@ func writeCircuitInventoryFiles(processed *CircuitProcessingResult, inventoryDir
 	return count, nil
 }
 
+// startLinesApart reports whether the start lines of the circuits are at least a cell of the grid apart horizontally.
+func startLinesApart(processed *CircuitProcessingResult, circuitIDs []string, resolution gtcircuits.Resolution) bool {
+	cell := float64(max(resolution.X, resolution.Z))
+
+	for i, circuitID := range circuitIDs {
+		for _, otherCircuitID := range circuitIDs[i+1:] {
+			a, b := processed.RawStartLines[circuitID], processed.RawStartLines[otherCircuitID]
+			if math.Hypot(float64(a.X-b.X), float64(a.Z-b.Z)) < cell {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// startLineRefinements are the finer start line grids tried, coarsest first, for circuits whose start lines share a
+// cell of the default grid.
+var startLineRefinements = []gtcircuits.Resolution{{X: 8, Y: 2, Z: 8}, {X: 4, Y: 1, Z: 4}}
+
 // coordObjectPattern matches a multi-line JSON object containing only x, y, z fields.
 var coordObjectPattern = regexp.MustCompile(`\{\s*\n\s*"x":\s*(-?\d+),\s*\n\s*"y":\s*(-?\d+),\s*\n\s*"z":\s*(-?\d+)\s*\n\s*\}`)
 
-// marshalCircuitJSON marshals a CircuitInfo to indented JSON with coordinate objects inlined.
+// anchorObjectPattern matches the multi-line JSON anchor object.
+var anchorObjectPattern = regexp.MustCompile(`"anchor": \{\s*\n((?:\s*"\w+":\s*-?[\d.e+-]+,?\s*\n)+)\s*\}`)
+
+// marshalCircuitJSON marshals a CircuitInfo to indented JSON with coordinate and anchor objects inlined.
 func marshalCircuitJSON(file gtcircuits.CircuitInfo) ([]byte, error) {
 	data, err := json.MarshalIndent(file, "", "    ")
 	if err != nil {
@@ -556,6 +680,9 @@ func marshalCircuitJSON(file gtcircuits.CircuitInfo) ([]byte, error) {
 	}
 
 	result := coordObjectPattern.ReplaceAllString(string(data), `{ "x": $1, "y": $2, "z": $3 }`)
+	result = anchorObjectPattern.ReplaceAllStringFunc(result, func(anchor string) string {
+		return `"anchor": { ` + strings.Join(strings.Fields(anchorObjectPattern.FindStringSubmatch(anchor)[1]), " ") + ` }`
+	})
 
 	return []byte(result), nil
 }
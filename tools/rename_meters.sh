@@ -0,0 +1,41 @@
+#!/bin/sh
+
+# Rewrites uses of the deprecated US spelling aliases (e.g. RideHeightMeters) to the British spelling used by the
+# gt-telemetry API (e.g. RideHeightMetres), in place. The files are also reformatted by gofmt.
+
+if [ "$#" -eq 0 ]; then
+  echo "Usage: $0 <path>..." >&2
+  exit 1
+fi
+
+for name in \
+  DefaultTrackWidth \
+  DynamicWheelbaseLeft \
+  DynamicWheelbaseLeftMilli \
+  GroundSpeed:PerSecond \
+  RideHeight \
+  RideHeightMilli \
+  SuspensionHeight \
+  SuspensionHeightMilli \
+  TyreDiameter \
+  TyreDiameterMilli \
+  TyreRadius \
+  TyreRadiusMilli \
+  VehicleHeightMilli \
+  VehicleLengthMilli \
+  VehicleTrackFrontMilli \
+  VehicleTrackRearMilli \
+  VehicleWheelbaseMilli \
+  VehicleWidthMilli \
+  WheelSpeed:PerSecond; do
+  prefix=${name%%:*}
+  suffix=${name#"$prefix"}
+  suffix=${suffix#:}
+
+  case $prefix in
+    *Milli) old="${prefix}meters${suffix}" new="${prefix}metres${suffix}" ;;
+    *) old="${prefix}Meters${suffix}" new="${prefix}Metres${suffix}" ;;
+  esac
+
+  gofmt -w -r "x.${old} -> x.${new}" "$@" || exit 1
+done
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/schemagen"
+)
+
+const (
+	modulePath = "github.com/zetetos/gt-telemetry/v2"
+	usage      = `Usage:
+  %s [module_dir]
+
+Writes the JSON Schema of each published JSON document to pkg/schemas, for example:
+  go run ./tools/schemas
+`
+)
+
+func main() {
+	if len(os.Args) > 2 {
+		fmt.Fprintf(os.Stderr, usage, os.Args[0])
+		os.Exit(1)
+	}
+
+	dir := "."
+	if len(os.Args) == 2 {
+		dir = os.Args[1]
+	}
+
+	for _, document := range schemagen.Documents() {
+		schema, err := schemagen.Generate(dir, modulePath, document)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		path := filepath.Join(dir, "pkg", "schemas", document.Name+".json")
+
+		err = os.WriteFile(path, schema, 0o644) //nolint:gosec // schemas are published to be read by anyone
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: writing schema: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
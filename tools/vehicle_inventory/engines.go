@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+const (
+	preferInventory = "inventory"
+	preferCommunity = "community"
+)
+
+// communityEngine is a row of the community engine data CSV. Numeric columns are read as strings so that an empty
+// cell can be told apart from a value of zero.
+type communityEngine struct {
+	CarID                 int    `csv:"CarId"`
+	Manufacturer          string `csv:"Manufacturer"`
+	Model                 string `csv:"Model"`
+	EngineLayout          string `csv:"EngineLayout"`
+	EngineBankAngle       string `csv:"EngineBankAngle"`
+	EngineCrankPlaneAngle string `csv:"EngineCrankPlaneAngle"`
+	EngineRedline         string `csv:"EngineRedline"`
+	Source                string `csv:"Source"`
+}
+
+// engineField is a field of the engine data that the community data would set on a vehicle.
+type engineField struct {
+	name      string
+	inventory string
+	community string
+	unset     bool
+	apply     func(vehicle *vehicles.Vehicle)
+}
+
+// conflictResolver decides between the inventory and community values of a field when both are set and differ,
+// either from the -prefer flag or by asking on the terminal.
+type conflictResolver struct {
+	prefer      string
+	interactive bool
+	input       *bufio.Reader
+	colors      *colorPrinter
+	unresolved  int
+}
+
+// importEngineData fills in and corrects the engine data of the inventory from a community CSV file.
+func importEngineData(csvFile, inventoryDir string, flags cliFlags) error {
+	if flags.prefer != "" && flags.prefer != preferInventory && flags.prefer != preferCommunity {
+		return fmt.Errorf("%w: -prefer must be %s or %s", ErrInvalidPreference, preferInventory, preferCommunity)
+	}
+
+	rows, err := loadCommunityEngines(csvFile)
+	if err != nil {
+		return err
+	}
+
+	vehicleMap, err := loadInventoryDir(inventoryDir)
+	if err != nil {
+		return fmt.Errorf("loading inventory: %w", err)
+	}
+
+	colors := newColorPrinter(flags.noColor)
+	resolver := &conflictResolver{
+		prefer:      flags.prefer,
+		interactive: flags.prefer == "" && isTerminal(os.Stdin),
+		input:       bufio.NewReader(os.Stdin),
+		colors:      colors,
+	}
+
+	allChanges := []changeRecord{}
+
+	for _, row := range rows {
+		carIDStr := strconv.Itoa(row.CarID)
+
+		vehicle, exists := vehicleMap[carIDStr]
+		if !exists {
+			fmt.Fprintf(os.Stderr, "Warning: skipping CarID %d (%s %s): %v\n", row.CarID, row.Manufacturer, row.Model, ErrVehicleNotFound)
+
+			continue
+		}
+
+		fields, err := engineFields(vehicle, row)
+		if err != nil {
+			return fmt.Errorf("CarID %d: %w", row.CarID, err)
+		}
+
+		changes := []string{}
+
+		for _, field := range fields {
+			take := field.unset
+
+			if !take {
+				take, err = resolver.resolve(vehicle, row, field)
+				if err != nil {
+					return err
+				}
+			}
+
+			if !take {
+				continue
+			}
+
+			if !field.unset {
+				changes = append(changes, fmt.Sprintf("  %s %s: %s", colors.Red("-"), field.name, colors.Red(field.inventory)))
+			}
+
+			changes = append(changes, fmt.Sprintf("  %s %s: %s", colors.Green("+"), field.name, colors.Green(field.community)))
+
+			field.apply(&vehicle)
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		// Touch the vehicle so that clients fetching updates through the manifest pick up the new engine data
+		vehicle.LastModified = time.Now().UTC().Truncate(time.Second)
+		vehicleMap[carIDStr] = vehicle
+
+		allChanges = append(allChanges, changeRecord{carID: row.CarID, changes: changes})
+	}
+
+	printChanges(allChanges, colors)
+
+	if resolver.unresolved > 0 {
+		fmt.Fprintf(os.Stderr, "\nKept the inventory value of %d conflicting fields, use -prefer or run interactively to review them\n", resolver.unresolved)
+	}
+
+	if flags.dryRun {
+		printDryRunSummary(inventoryDir, 0, len(allChanges))
+
+		return nil
+	}
+
+	if len(allChanges) == 0 {
+		fmt.Fprintln(os.Stderr, "No engine data to update")
+
+		return nil
+	}
+
+	return writeMergedInventory(inventoryDir, vehicleMap, 0, len(allChanges))
+}
+
+// loadCommunityEngines reads the community engine data CSV file.
+func loadCommunityEngines(csvFile string) ([]communityEngine, error) {
+	inputF, err := os.Open(csvFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening CSV file: %w", err)
+	}
+
+	defer func() {
+		closeErr := inputF.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: error closing input file: %v\n", closeErr)
+		}
+	}()
+
+	var rows []communityEngine
+
+	err = gocsv.Unmarshal(inputF, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("parsing CSV: %w", err)
+	}
+
+	return rows, nil
+}
+
+// engineFields returns the fields of a community row that are set and differ from the vehicle. A field is unset in
+// the inventory when it is empty, or for the angles when the vehicle has no engine layout yet, as an angle of zero is
+// valid for inline engines and flat plane cranks.
+func engineFields(vehicle vehicles.Vehicle, row communityEngine) ([]engineField, error) {
+	fields := []engineField{}
+	noEngineData := vehicle.EngineLayout == ""
+
+	if layout := strings.TrimSpace(row.EngineLayout); layout != "" && layout != vehicle.EngineLayout {
+		fields = append(fields, engineField{
+			name:      "EngineLayout",
+			inventory: "'" + vehicle.EngineLayout + "'",
+			community: "'" + layout + "'",
+			unset:     noEngineData,
+			apply:     func(vehicle *vehicles.Vehicle) { vehicle.EngineLayout = layout },
+		})
+	}
+
+	angles := []struct {
+		name    string
+		cell    string
+		current float32
+		set     func(vehicle *vehicles.Vehicle, angle float32)
+	}{
+		{
+			name:    "EngineBankAngle",
+			cell:    row.EngineBankAngle,
+			current: vehicle.EngineBankAngle,
+			set:     func(vehicle *vehicles.Vehicle, angle float32) { vehicle.EngineBankAngle = angle },
+		},
+		{
+			name:    "EngineCrankPlaneAngle",
+			cell:    row.EngineCrankPlaneAngle,
+			current: vehicle.EngineCrankPlaneAngle,
+			set:     func(vehicle *vehicles.Vehicle, angle float32) { vehicle.EngineCrankPlaneAngle = angle },
+		},
+	}
+
+	for _, angle := range angles {
+		cell := strings.TrimSpace(angle.cell)
+		if cell == "" {
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(cell, 32)
+		if err != nil || parsed < 0 || parsed >= 360 {
+			return nil, fmt.Errorf("%w: %s '%s'", ErrInvalidEngineData, angle.name, cell)
+		}
+
+		community := float32(parsed)
+		if community == angle.current {
+			continue
+		}
+
+		fields = append(fields, engineField{
+			name:      angle.name,
+			inventory: strconv.FormatFloat(float64(angle.current), 'f', -1, 32),
+			community: cell,
+			unset:     noEngineData,
+			apply:     func(vehicle *vehicles.Vehicle) { angle.set(vehicle, community) },
+		})
+	}
+
+	if cell := strings.TrimSpace(row.EngineRedline); cell != "" {
+		redline, err := strconv.Atoi(cell)
+		if err != nil || redline <= 0 {
+			return nil, fmt.Errorf("%w: EngineRedline '%s'", ErrInvalidEngineData, cell)
+		}
+
+		if redline != vehicle.EngineRedline {
+			fields = append(fields, engineField{
+				name:      "EngineRedline",
+				inventory: strconv.Itoa(vehicle.EngineRedline),
+				community: cell,
+				unset:     vehicle.EngineRedline == 0,
+				apply:     func(vehicle *vehicles.Vehicle) { vehicle.EngineRedline = redline },
+			})
+		}
+	}
+
+	return fields, nil
+}
+
+// resolve returns whether the community value of a conflicting field should replace the inventory value.
+func (r *conflictResolver) resolve(vehicle vehicles.Vehicle, row communityEngine, field engineField) (bool, error) {
+	switch {
+	case r.prefer == preferCommunity:
+		return true, nil
+	case r.prefer == preferInventory:
+		return false, nil
+	case !r.interactive:
+		r.unresolved++
+
+		return false, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "\n%s %s %s %s\n", r.colors.Yellow("[CONFLICT]"), r.colors.Cyan(fmt.Sprintf("CarID %d", vehicle.CarID)),
+		vehicle.Manufacturer, vehicle.Model)
+	fmt.Fprintf(os.Stderr, "  %s\n", field.name)
+	fmt.Fprintf(os.Stderr, "    inventory: %s\n", r.colors.Red(field.inventory))
+	fmt.Fprintf(os.Stderr, "    community: %s\n", r.colors.Green(field.community))
+
+	if row.Source != "" {
+		fmt.Fprintf(os.Stderr, "    source:    %s\n", row.Source)
+	}
+
+	for {
+		fmt.Fprint(os.Stderr, "[k]eep inventory, [t]ake community, [K]eep all, [T]ake all, [q]uit: ")
+
+		answer, err := r.input.ReadString('\n')
+		if errors.Is(err, io.EOF) && answer == "" {
+			// Nobody is answering, so keep the inventory value of this and any later conflicts
+			fmt.Fprintln(os.Stderr)
+
+			r.interactive = false
+			r.unresolved++
+
+			return false, nil
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("reading answer: %w", err)
+		}
+
+		switch strings.TrimSpace(answer) {
+		case "k":
+			return false, nil
+		case "t":
+			return true, nil
+		case "K":
+			r.prefer = preferInventory
+
+			return false, nil
+		case "T":
+			r.prefer = preferCommunity
+
+			return true, nil
+		case "q":
+			return false, ErrImportAborted
+		}
+	}
+}
+
+// isTerminal reports whether the file is an interactive terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -310,6 +310,8 @@ func convertGTToPD(gtCarsMap map[string]GTCar, gtTunersMap map[string]GTTuner) m
 			ID:              gtCar.ID,
 			NameShort:       gtCar.NameShort,
 			Manufacturer:    manufacturerName,
+			ManufacturerID:  gtCar.ManufacturerID,
+			Country:         getManufacturerCountry(gtCar.ManufacturerID, gtTunersMap),
 			Year:            year,
 			DriveTrain:      gtCar.DriveTrain,
 			AspirationShort: gtCar.AspirationShort,
@@ -348,6 +350,15 @@ func getManufacturerName(manufacturerID string, gtTunersMap map[string]GTTuner)
 	return ""
 }
 
+// getManufacturerCountry gets the upper case country or region code of the manufacturer from the tuners map.
+func getManufacturerCountry(manufacturerID string, gtTunersMap map[string]GTTuner) string {
+	if tuner, exists := gtTunersMap[manufacturerID]; exists {
+		return strings.ToUpper(strings.TrimSpace(tuner.Country))
+	}
+
+	return ""
+}
+
 // extractYearFromName extracts the year from a car name (e.g., "'22" -> 2022).
 func extractYearFromName(nameShort string) int {
 	yearPattern := regexp.MustCompile(`'(\d{2})$`)
@@ -16,6 +16,7 @@ Actions:
   convert  <file.csv> <dir>  Import CSV and write per-vehicle JSON files to dir
   manifest <dir>             Generate manifest JSON from inventory directory (stdout)
   update   <dir> [locale]    Fetch and merge car data from Gran Turismo website
+  engines  <file.csv> <dir>  Import engine data from a community CSV into the inventory
 
 Arguments:
   dir                      Path to a directory containing per-vehicle JSON files.
@@ -26,6 +27,8 @@ Flags:
   -help                    Show this help message
   -no-color                Disable colored output
   -dry-run                 Show changes without modifying files
+  -prefer <source>         Resolve engine data conflicts with the inventory or community value
+                           (default: ask on a terminal, otherwise keep the inventory value)
 
 Examples:
   # Export inventory directory to CSV
@@ -42,6 +45,9 @@ Examples:
 
   # Fetch and merge data for a specific locale
   inventory update pkg/vehicles/inventory us
+
+  # Fill in engine data from a community CSV, reviewing conflicts one by one
+  inventory engines engines.csv pkg/vehicles/inventory
 `
 
 // cliFlags holds all command-line flags.
@@ -49,6 +55,7 @@ type cliFlags struct {
 	help    bool
 	noColor bool
 	dryRun  bool
+	prefer  string
 }
 
 // parseCLI parses command-line arguments and returns flags and positional arguments.
@@ -58,6 +65,7 @@ func parseCLI() (cliFlags, []string) {
 	flag.BoolVar(&flags.help, "help", false, "Show help message")
 	flag.BoolVar(&flags.noColor, "no-color", false, "Disable colored output")
 	flag.BoolVar(&flags.dryRun, "dry-run", false, "Show changes without modifying files")
+	flag.StringVar(&flags.prefer, "prefer", "", "Resolve engine data conflicts with the inventory or community value")
 
 	flag.Parse()
 
@@ -92,8 +100,10 @@ func main() {
 		retCode = handleManifestAction(args)
 	case "update":
 		retCode = handleUpdateAction(args, flags)
+	case "engines":
+		retCode = handleEnginesAction(args, flags)
 	default:
-		fmt.Fprintf(os.Stderr, "Error: Unknown action '%s'. Supported actions: convert, manifest, update\n\n", action)
+		fmt.Fprintf(os.Stderr, "Error: Unknown action '%s'. Supported actions: convert, manifest, update, engines\n\n", action)
 		fmt.Print(usage)
 
 		retCode = 1
@@ -183,3 +193,22 @@ func handleUpdateAction(args []string, flags cliFlags) int {
 
 	return 0
 }
+
+// handleEnginesAction processes the engines action.
+func handleEnginesAction(args []string, flags cliFlags) int {
+	if len(args) < 3 {
+		fmt.Fprintf(os.Stderr, "Error: CSV file and inventory directory arguments are required for engines action\n\n")
+		fmt.Print(usage)
+
+		return 1
+	}
+
+	err := importEngineData(args[1], args[2], flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error importing engine data: %v\n", err)
+
+		return 1
+	}
+
+	return 0
+}
@@ -121,16 +121,18 @@ func performMerge(gtVehicleMap map[string]vehicles.Vehicle, pdVehicleMap map[str
 // createNewVehicle creates a new vehicle from PD data.
 func createNewVehicle(carID int, pdVehicle PDVehicle) vehicles.Vehicle {
 	return vehicles.Vehicle{
-		CarID:        carID,
-		Manufacturer: pdVehicle.Manufacturer,
-		Model:        pdVehicle.NameShort,
-		Year:         pdVehicle.Year,
-		Category:     pdVehicle.CarClass,
-		Drivetrain:   pdVehicle.DriveTrain,
-		Aspiration:   pdVehicle.AspirationShort,
-		Length:       pdVehicle.LengthV,
-		Width:        pdVehicle.WidthV,
-		Height:       pdVehicle.HeightV,
+		CarID:               carID,
+		Manufacturer:        pdVehicle.Manufacturer,
+		ManufacturerID:      pdVehicle.ManufacturerID,
+		ManufacturerCountry: pdVehicle.Country,
+		Model:               pdVehicle.NameShort,
+		Year:                pdVehicle.Year,
+		Category:            pdVehicle.CarClass,
+		Drivetrain:          pdVehicle.DriveTrain,
+		Aspiration:          pdVehicle.AspirationShort,
+		Length:              pdVehicle.LengthV,
+		Width:               pdVehicle.WidthV,
+		Height:              pdVehicle.HeightV,
 	}
 }
 
@@ -188,6 +190,7 @@ func getVehicleUpdateChanges(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle, co
 	changes := []string{}
 
 	updated = checkManufacturerUpdate(gtVehicle, pdVehicle, colors, &changes) || updated
+	updated = checkManufacturerMetadataUpdate(gtVehicle, pdVehicle, colors, &changes) || updated
 	updated = checkModelUpdate(gtVehicle, pdVehicle, colors, &changes) || updated
 	updated = checkYearUpdate(gtVehicle, pdVehicle, colors, &changes) || updated
 	updated = checkDrivetrainUpdate(gtVehicle, pdVehicle, colors, &changes) || updated
@@ -214,6 +217,35 @@ func checkManufacturerUpdate(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle, co
 	return true
 }
 
+// checkManufacturerMetadataUpdate checks and records manufacturer identifier and country field changes.
+func checkManufacturerMetadataUpdate(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle, colors *colorPrinter, changes *[]string) bool {
+	updated := false
+
+	fields := []struct {
+		name    string
+		current string
+		value   string
+	}{
+		{name: "ManufacturerId", current: gtVehicle.ManufacturerID, value: pdVehicle.ManufacturerID},
+		{name: "ManufacturerCountry", current: gtVehicle.ManufacturerCountry, value: pdVehicle.Country},
+	}
+
+	for _, field := range fields {
+		if field.value == "" || field.value == pdNullValue || field.current == field.value {
+			continue
+		}
+
+		if field.current != "" {
+			*changes = append(*changes, fmt.Sprintf("  %s %s: %s", colors.Red("-"), field.name, colors.Red("'"+field.current+"'")))
+		}
+
+		*changes = append(*changes, fmt.Sprintf("  %s %s: %s", colors.Green("+"), field.name, colors.Green("'"+field.value+"'")))
+		updated = true
+	}
+
+	return updated
+}
+
 // checkModelUpdate checks and records model field changes.
 func checkModelUpdate(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle, colors *colorPrinter, changes *[]string) bool {
 	if pdVehicle.NameShort == "" || pdVehicle.NameShort == pdNullValue || gtVehicle.Model == pdVehicle.NameShort {
@@ -319,6 +351,7 @@ func checkDimensionUpdates(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle, colo
 // applyVehicleUpdates applies updates from PDVehicle to GT Vehicle.
 func applyVehicleUpdates(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle) vehicles.Vehicle {
 	gtVehicle = updateManufacturer(gtVehicle, pdVehicle)
+	gtVehicle = updateManufacturerMetadata(gtVehicle, pdVehicle)
 	gtVehicle = updateModel(gtVehicle, pdVehicle)
 	gtVehicle = updateYear(gtVehicle, pdVehicle)
 	gtVehicle = updateDrivetrain(gtVehicle, pdVehicle)
@@ -338,6 +371,19 @@ func updateManufacturer(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle) vehicle
 	return gtVehicle
 }
 
+// updateManufacturerMetadata updates the manufacturer identifier and country fields if needed.
+func updateManufacturerMetadata(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle) vehicles.Vehicle {
+	if pdVehicle.ManufacturerID != "" && pdVehicle.ManufacturerID != pdNullValue {
+		gtVehicle.ManufacturerID = pdVehicle.ManufacturerID
+	}
+
+	if pdVehicle.Country != "" && pdVehicle.Country != pdNullValue {
+		gtVehicle.ManufacturerCountry = pdVehicle.Country
+	}
+
+	return gtVehicle
+}
+
 // updateModel updates the model field if needed.
 func updateModel(gtVehicle vehicles.Vehicle, pdVehicle PDVehicle) vehicles.Vehicle {
 	if pdVehicle.NameShort != "" && pdVehicle.NameShort != pdNullValue {
@@ -405,6 +451,7 @@ func getNewVehicleChanges(pdVehicle PDVehicle, colors *colorPrinter) []string {
 	var changes []string
 
 	changes = appendManufacturerChange(changes, pdVehicle, colors)
+	changes = appendManufacturerMetadataChanges(changes, pdVehicle, colors)
 	changes = appendModelChange(changes, pdVehicle, colors)
 	changes = appendYearChange(changes, pdVehicle, colors)
 	changes = appendCategoryChange(changes, pdVehicle, colors)
@@ -424,6 +471,19 @@ func appendManufacturerChange(changes []string, pdVehicle PDVehicle, colors *col
 	return changes
 }
 
+// appendManufacturerMetadataChanges appends manufacturer identifier and country changes if present.
+func appendManufacturerMetadataChanges(changes []string, pdVehicle PDVehicle, colors *colorPrinter) []string {
+	if pdVehicle.ManufacturerID != "" && pdVehicle.ManufacturerID != pdNullValue {
+		changes = append(changes, fmt.Sprintf("  %s ManufacturerId: %s", colors.Green("+"), colors.Green("'"+pdVehicle.ManufacturerID+"'")))
+	}
+
+	if pdVehicle.Country != "" && pdVehicle.Country != pdNullValue {
+		changes = append(changes, fmt.Sprintf("  %s ManufacturerCountry: %s", colors.Green("+"), colors.Green("'"+pdVehicle.Country+"'")))
+	}
+
+	return changes
+}
+
 // appendModelChange appends model change if present.
 func appendModelChange(changes []string, pdVehicle PDVehicle, colors *colorPrinter) []string {
 	if pdVehicle.NameShort != "" && pdVehicle.NameShort != pdNullValue {
@@ -15,6 +15,9 @@ var (
 	ErrTunersVariableNameNotFound = errors.New("could not find variable name in tuners JavaScript")
 	ErrCarsObjectNotFound         = errors.New("cars object not found in JavaScript")
 	ErrTunersObjectNotFound       = errors.New("tuners object not found in JavaScript")
+	ErrInvalidEngineData          = errors.New("invalid engine data")
+	ErrInvalidPreference          = errors.New("invalid conflict preference")
+	ErrImportAborted              = errors.New("import aborted")
 )
 
 const pdNullValue = "---"
@@ -24,6 +27,8 @@ type PDVehicle struct {
 	ID              string `json:"id"`
 	NameShort       string `json:"nameShort"`
 	Manufacturer    string `json:"manufacturer"`
+	ManufacturerID  string `json:"manufacturerId"`
+	Country         string `json:"country"`
 	Year            int    `json:"year"`
 	DriveTrain      string `json:"driveTrain"`
 	AspirationShort string `json:"aspirationShort"`
@@ -52,6 +57,7 @@ type GTTuner struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
 	NameShort string `json:"nameShort"`
+	Country   string `json:"country"`
 }
 
 // changeRecord tracks changes made to a vehicle during merge operations.
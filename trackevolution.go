@@ -0,0 +1,122 @@
+package gttelemetry
+
+const (
+	// trackEvolutionFuelSecondsPerLitre is the time each litre of fuel on board adds to a lap, a rule of thumb for
+	// the weight of fuel that is close enough to separate the fuel burn from the change in grip.
+	trackEvolutionFuelSecondsPerLitre = 0.03
+	// trackEvolutionMaxLapFactor is how much slower than the fastest corrected lap a lap may be and still be counted,
+	// excluding out laps, in laps and laps with an incident.
+	trackEvolutionMaxLapFactor = 1.07
+	// trackEvolutionMinLaps is the fewest laps that a trend is fitted to.
+	trackEvolutionMinLaps = 3
+)
+
+// TrackEvolution is the trend in lap times over a session once the weight of fuel burnt is taken into account.
+type TrackEvolution struct {
+	// Index is the percentage by which the fuel corrected lap time improved between the first and last laps counted
+	// according to the trend, positive as the track gains grip and negative as it loses grip.
+	Index float32 `json:"index"`
+	// SecondsPerLap is the change in the fuel corrected lap time from one lap to the next, negative as the track
+	// gets faster.
+	SecondsPerLap float32 `json:"secondsPerLap"`
+	// TyreTemperatureCelsiusPerLap is the trend in the average tyre temperature at the end of each lap. The telemetry
+	// does not report the air or track temperature, so it stands in for the change in ambient conditions.
+	TyreTemperatureCelsiusPerLap float32 `json:"tyreTemperatureCelsiusPerLap"`
+	// Laps is the number of laps the trend was fitted to.
+	Laps int `json:"laps"`
+}
+
+// TrackEvolution fits a trend to the lap times of the session, with the time taken to carry the fuel on board each
+// lap removed. Only laps driven with the setup and in the weather of the latest lap are counted, and laps much slower
+// than the fastest are left out. The trend also includes the wear of the tyres, so it is most meaningful over a stint
+// on one set of tyres. Returns false until enough laps have been completed.
+func (h *LapHistory) TrackEvolution() (TrackEvolution, bool) {
+	if len(h.snapshots) == 0 {
+		return TrackEvolution{}, false
+	}
+
+	latest := h.snapshots[len(h.snapshots)-1]
+	laps := make([]float64, 0, len(h.snapshots))
+	times := make([]float64, 0, len(h.snapshots))
+	temperatures := make([]float64, 0, len(h.snapshots))
+	fastest := 0.0
+
+	for _, snapshot := range h.SnapshotsForSetup(latest.Setup) {
+		if snapshot.Laptime <= 0 || snapshot.Weather != latest.Weather {
+			continue
+		}
+
+		// Fuel level is measured at the end of the lap, so the car carried half the fuel used on average
+		fuel := float64(snapshot.FuelLevel + snapshot.FuelUsed/2)
+		corrected := snapshot.Laptime.Seconds() - fuel*trackEvolutionFuelSecondsPerLitre
+
+		if fastest == 0 || corrected < fastest {
+			fastest = corrected
+		}
+
+		tyres := snapshot.TyreTemperature
+		laps = append(laps, float64(snapshot.Lap))
+		times = append(times, corrected)
+		temperatures = append(temperatures, float64(tyres.FrontLeft+tyres.FrontRight+tyres.RearLeft+tyres.RearRight)/4)
+	}
+
+	counted := 0
+
+	for i := range laps {
+		if times[i] > fastest*trackEvolutionMaxLapFactor {
+			continue
+		}
+
+		laps[counted], times[counted], temperatures[counted] = laps[i], times[i], temperatures[i]
+		counted++
+	}
+
+	laps, times, temperatures = laps[:counted], times[:counted], temperatures[:counted]
+
+	if counted < trackEvolutionMinLaps {
+		return TrackEvolution{}, false
+	}
+
+	slope, intercept := linearFit(laps, times)
+	first := slope*laps[0] + intercept
+	last := slope*laps[counted-1] + intercept
+	temperatureSlope, _ := linearFit(laps, temperatures)
+
+	return TrackEvolution{
+		Index:                        float32(100 * (first - last) / first),
+		SecondsPerLap:                float32(slope),
+		TyreTemperatureCelsiusPerLap: float32(temperatureSlope),
+		Laps:                         counted,
+	}, true
+}
+
+// TrackEvolutionIndex returns the Index of TrackEvolution, the percentage by which the track has got faster over the
+// session, for strategy tools that only need a single figure.
+func (h *LapHistory) TrackEvolutionIndex() (float32, bool) {
+	evolution, ok := h.TrackEvolution()
+
+	return evolution.Index, ok
+}
+
+// linearFit returns the slope and intercept of the least squares line through the points.
+func linearFit(xs, ys []float64) (float64, float64) {
+	var sumX, sumY, sumXX, sumXY float64
+
+	for i, x := range xs {
+		sumX += x
+		sumY += ys[i]
+		sumXX += x * x
+		sumXY += x * ys[i]
+	}
+
+	n := float64(len(xs))
+	denominator := n*sumXX - sumX*sumX
+
+	if denominator == 0 {
+		return 0, sumY / n
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+
+	return slope, (sumY - slope*sumX) / n
+}
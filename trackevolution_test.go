@@ -0,0 +1,131 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type TrackEvolutionTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	history     *gttelemetry.LapHistory
+	lapNumber   int16
+}
+
+func TestTrackEvolutionTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TrackEvolutionTestSuite))
+}
+
+func (suite *TrackEvolutionTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.FuelLevel = 100
+	suite.transformer.SetTyreTemperature(80, 80, 80, 80)
+
+	suite.history = gttelemetry.NewLapHistory()
+}
+
+// lap drives a lap of frames at the given tyre temperature, using the given amount of fuel, which is reported with
+// the given lap time as the car crosses the line.
+func (suite *TrackEvolutionTestSuite) lap(laptimeMs int32, fuelUsed, tyreTemp float32) {
+	suite.lapNumber++
+	suite.transformer.SetTyreTemperature(tyreTemp, tyreTemp, tyreTemp, tyreTemp)
+
+	for range 10 {
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.transformer.RawTelemetry.CurrentLap = suite.lapNumber
+		suite.history.Update(suite.transformer)
+		suite.transformer.RawTelemetry.FuelLevel -= fuelUsed / 10
+	}
+
+	suite.transformer.RawTelemetry.LastLaptime = laptimeMs
+}
+
+// drive completes a lap at each of the given lap times, using the given amount of fuel every lap.
+func (suite *TrackEvolutionTestSuite) drive(laptimesMs []int32, fuelPerLap float32) {
+	for _, laptime := range laptimesMs {
+		suite.lap(laptime, fuelPerLap, 80)
+	}
+
+	suite.crossLine()
+}
+
+func (suite *TrackEvolutionTestSuite) crossLine() {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.RawTelemetry.CurrentLap = suite.lapNumber + 1
+	suite.history.Update(suite.transformer)
+}
+
+func (suite *TrackEvolutionTestSuite) TestFasterLapsShowTrackGainingGrip() {
+	// Arrange
+	suite.drive([]int32{90000, 89500, 89000, 88500, 88000}, 0)
+
+	// Act
+	evolution, ok := suite.history.TrackEvolution()
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(5, evolution.Laps)
+	suite.InDelta(-0.5, evolution.SecondsPerLap, 0.001)
+	suite.InDelta(100*2.0/87, evolution.Index, 0.001, "100 litres of fuel should take 3 seconds off the first lap")
+}
+
+func (suite *TrackEvolutionTestSuite) TestLapTimesAreCorrectedForFuelBurnt() {
+	// Arrange
+	suite.drive([]int32{90000, 90000, 90000, 90000}, 3)
+
+	// Act
+	evolution, ok := suite.history.TrackEvolution()
+
+	// Assert
+	suite.Require().True(ok)
+	suite.InDelta(0.09, evolution.SecondsPerLap, 0.001, "Lighter car should have been faster")
+	suite.Negative(evolution.Index)
+}
+
+func (suite *TrackEvolutionTestSuite) TestSlowLapsAreNotCounted() {
+	// Arrange
+	suite.drive([]int32{90000, 89500, 120000, 89000, 88500}, 0)
+
+	// Act
+	evolution, ok := suite.history.TrackEvolution()
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Equal(4, evolution.Laps)
+	suite.InDelta(-0.4, evolution.SecondsPerLap, 0.1)
+}
+
+func (suite *TrackEvolutionTestSuite) TestTyreTemperatureTrendIsReported() {
+	// Arrange
+	for _, temperature := range []float32{70, 72, 74, 76} {
+		suite.lap(90000, 0, temperature)
+	}
+
+	suite.crossLine()
+
+	// Act
+	evolution, ok := suite.history.TrackEvolution()
+
+	// Assert
+	suite.Require().True(ok)
+	suite.Positive(evolution.TyreTemperatureCelsiusPerLap)
+}
+
+func (suite *TrackEvolutionTestSuite) TestIndexIsUnavailableUntilEnoughLaps() {
+	// Arrange
+	suite.drive([]int32{90000, 89000}, 0)
+
+	// Act
+	_, ok := suite.history.TrackEvolutionIndex()
+
+	// Assert
+	suite.False(ok)
+}
@@ -0,0 +1,207 @@
+package gttelemetry
+
+import (
+	"math"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+)
+
+const (
+	// DefaultTrackWidthMetres is the assumed width of the circuit when no width is configured.
+	DefaultTrackWidthMetres = 12
+
+	// defaultWheelbaseMetres and defaultTrackMetres are used when the vehicle dimensions are not in the inventory.
+	defaultWheelbaseMetres = 2.6
+	defaultTrackMetres     = 1.6
+
+	// trackLimitsSearchWindow is the number of path segments either side of the previous match that are searched
+	// before falling back to a search of the whole path.
+	trackLimitsSearchWindow = 50
+	// trackLimitsMinimumSpeed is the ground speed in metres per second below which track limits are not checked.
+	trackLimitsMinimumSpeed = 3
+	// minimumHeadingDistance is how far the vehicle must move in metres before its direction of travel is updated.
+	minimumHeadingDistance = 0.05
+)
+
+// TrackLimitsOptions configures a TrackLimits monitor.
+type TrackLimitsOptions struct {
+	// Path is the centreline of the circuit in driving order, such as the coordinates of a captured lap.
+	Path []models.Coordinate
+	// TrackWidthMetres is the assumed width of the circuit, defaults to 12 metres.
+	TrackWidthMetres float32
+}
+
+// TrackLimitWarning describes a single excursion beyond the track limits.
+type TrackLimitWarning struct {
+	Lap        int16             `json:"lap"`
+	SequenceID uint32            `json:"sequenceId"`
+	Position   models.Coordinate `json:"position"`
+	// ExcessMetres is how far the wheel closest to the circuit was beyond the edge of the track.
+	ExcessMetres float32 `json:"excessMetres"`
+}
+
+// TrackLimits monitors the wheel positions of the vehicle relative to a circuit path and reports a warning each
+// time all four wheels leave the track. The wheel positions are estimated from the position of the vehicle, its
+// direction of travel and its wheelbase and track dimensions.
+type TrackLimits struct {
+	path         []models.Coordinate
+	halfWidth    float64
+	lastIndex    int
+	lastPosition models.Coordinate
+	headingX     float64
+	headingZ     float64
+	hasHeading   bool
+	hasPosition  bool
+	lastSequence uint32
+	offTrack     bool
+	warnings     []TrackLimitWarning
+	lapCounts    map[int16]int
+}
+
+// NewTrackLimits creates a new track limits monitor.
+func NewTrackLimits(opts TrackLimitsOptions) *TrackLimits {
+	width := opts.TrackWidthMetres
+	if width <= 0 {
+		width = DefaultTrackWidthMetres
+	}
+
+	return &TrackLimits{
+		path:      opts.Path,
+		halfWidth: float64(width) / 2,
+		lapCounts: map[int16]int{},
+	}
+}
+
+// Update evaluates the latest telemetry frame and returns a warning when the vehicle has just left the track.
+func (m *TrackLimits) Update(t *Transformer) (TrackLimitWarning, bool) {
+	sequenceID := t.SequenceID()
+	if len(m.path) < 2 || (m.hasPosition && sequenceID == m.lastSequence) {
+		return TrackLimitWarning{}, false
+	}
+
+	m.lastSequence = sequenceID
+	position := t.PositionalMapCoordinates()
+	m.updateHeading(position)
+
+	if !t.IsOnCircuit() || !m.hasHeading || t.GroundSpeedMetresPerSecond() < trackLimitsMinimumSpeed {
+		m.offTrack = false
+
+		return TrackLimitWarning{}, false
+	}
+
+	excess := math.MaxFloat64
+	for _, wheel := range m.wheelPositions(t, position) {
+		excess = math.Min(excess, m.distanceFromPath(wheel)-m.halfWidth)
+	}
+
+	wasOffTrack := m.offTrack
+	m.offTrack = excess > 0
+
+	if !m.offTrack || wasOffTrack {
+		return TrackLimitWarning{}, false
+	}
+
+	warning := TrackLimitWarning{
+		Lap:          t.CurrentLap(),
+		SequenceID:   sequenceID,
+		Position:     position,
+		ExcessMetres: float32(excess),
+	}
+
+	m.warnings = append(m.warnings, warning)
+	m.lapCounts[warning.Lap]++
+
+	return warning, true
+}
+
+// Warnings returns all track limit warnings in the order they occurred.
+func (m *TrackLimits) Warnings() []TrackLimitWarning {
+	return m.warnings
+}
+
+// LapCount returns the number of track limit warnings on the given lap.
+func (m *TrackLimits) LapCount(lap int16) int {
+	return m.lapCounts[lap]
+}
+
+// updateHeading updates the direction of travel from the movement since the previous frame.
+func (m *TrackLimits) updateHeading(position models.Coordinate) {
+	if !m.hasPosition {
+		m.lastPosition = position
+		m.hasPosition = true
+
+		return
+	}
+
+	distance := planarDistance(m.lastPosition, position)
+	if distance < minimumHeadingDistance {
+		return
+	}
+
+	m.headingX = float64(position.X-m.lastPosition.X) / distance
+	m.headingZ = float64(position.Z-m.lastPosition.Z) / distance
+	m.hasHeading = true
+	m.lastPosition = position
+}
+
+// wheelPositions estimates the position of each wheel from the vehicle position and direction of travel.
+func (m *TrackLimits) wheelPositions(t *Transformer, position models.Coordinate) [4]models.Coordinate {
+	halfWheelbase := millimetresOrDefault(t.VehicleWheelbaseMillimetres(), defaultWheelbaseMetres) / 2
+	halfTrackFront := millimetresOrDefault(t.VehicleTrackFrontMillimetres(), defaultTrackMetres) / 2
+	halfTrackRear := millimetresOrDefault(t.VehicleTrackRearMillimetres(), defaultTrackMetres) / 2
+
+	offset := func(forward, right float64) models.Coordinate {
+		return models.Coordinate{
+			X: position.X + float32(m.headingX*forward-m.headingZ*right),
+			Y: position.Y,
+			Z: position.Z + float32(m.headingZ*forward+m.headingX*right),
+		}
+	}
+
+	return [4]models.Coordinate{
+		offset(halfWheelbase, -halfTrackFront),
+		offset(halfWheelbase, halfTrackFront),
+		offset(-halfWheelbase, -halfTrackRear),
+		offset(-halfWheelbase, halfTrackRear),
+	}
+}
+
+// distanceFromPath returns the horizontal distance from the point to the closest segment of the path, searching
+// near the previous match first.
+func (m *TrackLimits) distanceFromPath(point models.Coordinate) float64 {
+	best, bestIndex := m.searchPath(point, m.lastIndex-trackLimitsSearchWindow, m.lastIndex+trackLimitsSearchWindow)
+	if best > m.halfWidth*2 {
+		best, bestIndex = m.searchPath(point, 0, len(m.path))
+	}
+
+	m.lastIndex = bestIndex
+
+	return best
+}
+
+func (m *TrackLimits) searchPath(point models.Coordinate, from, to int) (float64, int) {
+	best := math.MaxFloat64
+	bestIndex := m.lastIndex
+	segments := len(m.path) - 1
+
+	for i := from; i < to; i++ {
+		index := ((i % segments) + segments) % segments
+
+		distance, _ := pointSegmentDistance(point, m.path[index], m.path[index+1])
+		if distance < best {
+			best = distance
+			bestIndex = index
+		}
+	}
+
+	return best, bestIndex
+}
+
+// millimetresOrDefault converts a dimension in millimetres to metres, using the default when it is unknown.
+func millimetresOrDefault(millimetres int, defaultMetres float64) float64 {
+	if millimetres <= 0 {
+		return defaultMetres
+	}
+
+	return float64(millimetres) / 1000
+}
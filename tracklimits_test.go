@@ -0,0 +1,112 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/models"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type TrackLimitsTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	limits      *gttelemetry.TrackLimits
+	x           float32
+}
+
+func TestTrackLimitsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TrackLimitsTestSuite))
+}
+
+func (suite *TrackLimitsTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.transformer.RawTelemetry.GroundSpeed = 30
+
+	suite.limits = gttelemetry.NewTrackLimits(gttelemetry.TrackLimitsOptions{
+		Path: []models.Coordinate{{X: 0}, {X: 500}, {X: 1000}},
+	})
+	suite.x = 10
+}
+
+// drive moves the vehicle half a metre along the straight at the given lateral offset and feeds a new frame.
+func (suite *TrackLimitsTestSuite) drive(z float32) (gttelemetry.TrackLimitWarning, bool) {
+	suite.x += 0.5
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.SetMapPositionCoordinates(suite.x, 0, z)
+
+	return suite.limits.Update(suite.transformer)
+}
+
+func (suite *TrackLimitsTestSuite) TestNoWarningOnTrack() {
+	// Act
+	for range 10 {
+		_, warned := suite.drive(4)
+		suite.False(warned)
+	}
+
+	// Assert
+	suite.Empty(suite.limits.Warnings())
+}
+
+func (suite *TrackLimitsTestSuite) TestNoWarningWithTwoWheelsOnTrack() {
+	// Arrange
+	suite.drive(0)
+
+	// Act
+	_, warned := suite.drive(6.5)
+
+	// Assert
+	suite.False(warned)
+}
+
+func (suite *TrackLimitsTestSuite) TestWarningWhenAllWheelsLeaveTrack() {
+	// Arrange
+	suite.drive(8)
+
+	// Act
+	warning, warned := suite.drive(8)
+
+	// Assert
+	suite.True(warned)
+	suite.Equal(int16(1), warning.Lap)
+	suite.InDelta(1.2, warning.ExcessMetres, 0.01)
+	suite.Equal(1, suite.limits.LapCount(1))
+}
+
+func (suite *TrackLimitsTestSuite) TestSingleWarningPerExcursion() {
+	// Arrange
+	suite.drive(0)
+
+	// Act
+	for range 10 {
+		suite.drive(9)
+	}
+
+	suite.drive(0)
+	suite.drive(-9)
+
+	// Assert
+	suite.Len(suite.limits.Warnings(), 2)
+	suite.Equal(2, suite.limits.LapCount(1))
+	suite.Equal(0, suite.limits.LapCount(2))
+}
+
+func (suite *TrackLimitsTestSuite) TestNoWarningAtLowSpeed() {
+	// Arrange
+	suite.transformer.RawTelemetry.GroundSpeed = 1
+	suite.drive(0)
+
+	// Act
+	_, warned := suite.drive(9)
+
+	// Assert
+	suite.False(warned)
+}
@@ -2,6 +2,8 @@ package gttelemetry
 
 import (
 	"math"
+	"net/netip"
+	"slices"
 	"time"
 
 	"github.com/zetetos/gt-telemetry/v2/internal/telemetry"
@@ -46,17 +48,68 @@ type Vmax struct {
 }
 
 type Transformer struct {
+	// RawTelemetry is the decoded frame that the accessor methods read from. Changes made to it corrupt the values
+	// returned by the transformer and by the detectors that read it.
+	//
+	// Deprecated: Use Raw for a copy of the frame, or the accessor methods. RawTelemetry will be unexported in v3.
 	RawTelemetry telemetry.GranTurismoTelemetry
-	Vehicle      vehicles.Vehicle
-	inventory    *vehicles.VehicleDB
-}
-
+	// Vehicle is the vehicle resolved for the most recent frame. It is written by UpdateVehicle as each frame is
+	// decoded, so reading it from another goroutine while the client streams is a data race, read it from a Clone.
+	//
+	// Deprecated: Use CurrentVehicle. Vehicle will be removed in v3.
+	Vehicle       vehicles.Vehicle
+	inventory     *vehicles.VehicleDB
+	vehicle       *vehicles.Vehicle
+	cache         *frameCache
+	generation    uint64
+	filters       *filterBank
+	receivedAt    time.Time
+	sourceAddress netip.AddrPort
+	decodedAt     time.Time
+	finalDrive    *finalDriveState
+	clock         *clockState
+	fuelFlow      *fuelFlowState
+}
+
+// NewTransformer creates a transformer reading vehicles from the inventory. The vehicle getters return the vehicle
+// resolved by UpdateVehicle, which the client calls for each frame, so call it after setting a frame by hand.
 func NewTransformer(inventory *vehicles.VehicleDB) *Transformer {
 	return &Transformer{
 		RawTelemetry: telemetry.GranTurismoTelemetry{},
 		Vehicle:      vehicles.Vehicle{},
 		inventory:    inventory,
+		cache:        &frameCache{},
+		filters:      &filterBank{},
+		finalDrive:   &finalDriveState{},
+		clock:        &clockState{},
+		fuelFlow:     &fuelFlowState{},
+	}
+}
+
+// Clone returns a copy of the transformer holding the current frame, its vehicle and the state of the filters and
+// measurements, which does not change as the client decodes later frames. A Transformer is written by the client on
+// the goroutine decoding the stream, so clone it there, e.g. in Options.OnFrame, and read the clone from other
+// goroutines. A clone is safe for concurrent use by any number of goroutines as long as none of them updates it.
+func (t *Transformer) Clone() *Transformer {
+	clone := &Transformer{
+		RawTelemetry:  t.Raw(),
+		Vehicle:       t.Vehicle,
+		inventory:     t.inventory,
+		vehicle:       t.vehicle,
+		cache:         &frameCache{},
+		generation:    t.generation,
+		filters:       t.filters.clone(),
+		receivedAt:    t.receivedAt,
+		sourceAddress: t.sourceAddress,
+		decodedAt:     t.decodedAt,
+		finalDrive:    t.finalDrive.clone(),
+		clock:         t.clock.clone(),
+		fuelFlow:      t.fuelFlow.clone(),
 	}
+
+	clone.RawTelemetry.ResolveInstances()
+
+	return clone
 }
 
 func (t *Transformer) AngularVelocityVector() models.Vector {
@@ -118,12 +171,14 @@ func (t *Transformer) CurrentGear() int {
 }
 
 func (t *Transformer) CurrentGearRatio() float32 {
-	gear := t.CurrentGear()
-	if gear > len(t.Transmission().GearRatios) {
-		return -1
-	}
+	return cached(t, cacheKeyCurrentGearRatio, func() float32 {
+		gear := t.CurrentGear()
+		if gear > len(t.Transmission().GearRatios) {
+			return -1
+		}
 
-	return t.Transmission().GearRatios[gear-1]
+		return t.Transmission().GearRatios[gear-1]
+	})
 }
 
 func (t *Transformer) CurrentLap() int16 {
@@ -134,31 +189,10 @@ func (t *Transformer) CurrentLaptime() time.Duration {
 	return time.Duration(t.RawTelemetry.CurrentLaptime) * time.Millisecond
 }
 
+// DifferentialRatio returns the final drive ratio of the vehicle, see FinalDriveEstimate for how it is estimated and
+// the confidence in it. It returns -1 when there is not enough data to estimate the ratio.
 func (t *Transformer) DifferentialRatio() float32 {
-	t.UpdateVehicle()
-
-	transmission := t.Transmission()
-	if transmission.Gears == 0 {
-		return -1
-	}
-
-	highestRatio := transmission.GearRatios[transmission.Gears-1]
-	vMax := t.CalculatedVmax()
-
-	var rollingDiameter float32
-
-	switch t.Vehicle.Drivetrain {
-	case "FF":
-		rollingDiameter = t.TyreDiameterMetres().FrontLeft
-	default:
-		rollingDiameter = t.TyreDiameterMetres().RearLeft
-	}
-
-	vMaxMetresPerMinute := float32(vMax.Speed) * 1000 / 60
-	wheelRpm := vMaxMetresPerMinute / (rollingDiameter * math.Pi)
-	diffRatio := (float32(vMax.RPM) / highestRatio) / wheelRpm
-
-	return diffRatio
+	return t.FinalDriveEstimate().Ratio
 }
 
 func (t *Transformer) DynamicWheelbaseLeftMetres() float32 {
@@ -344,26 +378,60 @@ func (t *Transformer) RaceLaps() int16 {
 	return t.RawTelemetry.RaceLaps
 }
 
+// RaceType estimates the type of the session from the field and race distance of the current frame. Sessions without
+// a lap count are time trials when driven alone or with a couple of cars and endurance races with a larger field, and
+// races over laps with a larger field are sprint races. A single frame cannot tell qualifying from a time trial, nor
+// what a session over laps with a small field is, so those are unknown, see RaceTypeDetector.
 func (t *Transformer) RaceType() models.RaceType {
 	if !t.IsOnCircuit() {
 		return models.RaceTypeUnknown
 	}
 
-	if t.RawTelemetry.RaceEntrants <= 3 && t.RawTelemetry.RaceLaps == 0 {
+	if t.RawTelemetry.RaceEntrants <= raceTypeSmallField && t.RawTelemetry.RaceLaps == 0 {
 		return models.RaceTypeTimeTrial
 	}
 
-	if t.RawTelemetry.RaceEntrants > 3 && t.RawTelemetry.RaceLaps == 0 {
+	if t.RawTelemetry.RaceEntrants > raceTypeSmallField && t.RawTelemetry.RaceLaps == 0 {
 		return models.RaceTypeEndurance
 	}
 
-	if t.RawTelemetry.RaceEntrants > 3 && t.RawTelemetry.RaceLaps > 0 {
+	if t.RawTelemetry.RaceEntrants > raceTypeSmallField && t.RawTelemetry.RaceLaps > 0 {
 		return models.RaceTypeSprint
 	}
 
 	return models.RaceTypeUnknown
 }
 
+// ReceivedAt returns the time the frame was received from the client's TimeSource. Frames read from a recording
+// return the time they were recorded when the recording has a times file, and the zero time otherwise.
+func (t *Transformer) ReceivedAt() time.Time {
+	return t.receivedAt
+}
+
+// SourceAddress returns the network address the frame was sent from, to tell apart consoles and spot unexpected
+// senders on the telemetry port. Frames read from a file or replay return the zero address.
+func (t *Transformer) SourceAddress() netip.AddrPort {
+	return t.sourceAddress
+}
+
+// FrameAge returns the time since the frame was decoded, measured by the local clock whatever the client's TimeSource,
+// or zero before the first frame. The age grows while the console stops sending, such as when the network drops or the
+// game is closed, so dashboards can tell that the values are no longer live.
+func (t *Transformer) FrameAge() time.Duration {
+	if t.decodedAt.IsZero() {
+		return 0
+	}
+
+	return time.Since(t.decodedAt)
+}
+
+// IsStale returns true when no frame has been decoded for longer than maxAge, or none has been decoded at all, for
+// greying out values instead of showing the last frame as if it were live. The game keeps sending frames while paused,
+// so use Flags().GamePaused to tell when the values are held by a pause.
+func (t *Transformer) IsStale(maxAge time.Duration) bool {
+	return t.decodedAt.IsZero() || time.Since(t.decodedAt) > maxAge
+}
+
 func (t *Transformer) RideHeightMetres() float32 {
 	return t.RawTelemetry.RideHeight
 }
@@ -385,6 +453,26 @@ func (t *Transformer) SequenceID() uint32 {
 	return t.RawTelemetry.SequenceId
 }
 
+// SetupFingerprint identifies the current combination of vehicle and setup, so that laps are only compared with laps
+// driven with an identical setup. Damage reduces the calculated top speed and so also changes the fingerprint.
+func (t *Transformer) SetupFingerprint() SetupFingerprint {
+	return cached(t, cacheKeySetupFingerprint, func() SetupFingerprint {
+		transmission := t.Transmission()
+		tyreRadius := t.TyreRadiusMetres()
+
+		values := append(slices.Clone(transmission.GearRatios),
+			t.TransmissionTopSpeedRatio(),
+			tyreRadius.FrontLeft,
+			tyreRadius.FrontRight,
+			tyreRadius.RearLeft,
+			tyreRadius.RearRight,
+			float32(t.RawTelemetry.CalculatedMaxSpeed),
+		)
+
+		return SetupFingerprint(fingerprint(t.VehicleID(), values...))
+	})
+}
+
 func (t *Transformer) SteeringWheelAngleRadians() float32 {
 	return t.RawTelemetry.SteeringWheelAngleRadians
 }
@@ -393,20 +481,52 @@ func (t *Transformer) SteeringWheelAngleRadiansPerSecond() float32 {
 	return t.RawTelemetry.SteeringWheelAngleRadiansPerSecond
 }
 
+// SteeringWheelForceFeedback returns the steering wheel angular velocity. The game does not send the force feedback
+// signal in telemetry, so its distribution and clipping cannot be measured from the packet.
+//
 // Deprecated: value is steering angular velocity, not force feedback.
 func (t *Transformer) SteeringWheelForceFeedback() float32 {
 	return t.RawTelemetry.SteeringWheelAngleRadiansPerSecond
 }
 
+// noSuggestedGear is the suggested gear sent by the game when it has no suggestion.
+const noSuggestedGear = 15
+
+// GearSuggestion is the gear suggested by the game, such as for the approach to a corner. Valid is false when the
+// game has no suggestion or the frame has no transmission data, in which case Gear is zero.
+type GearSuggestion struct {
+	Gear  int  `json:"gear"`
+	Valid bool `json:"valid"`
+}
+
+// SuggestedGear returns the raw suggested gear, which is 15 both when the game has no suggestion and when the frame
+// has no transmission data.
+//
+// Deprecated: Use GearSuggestion, which reports whether there is a suggestion.
 func (t *Transformer) SuggestedGear() uint64 {
 	gear := t.RawTelemetry.TransmissionGear
 	if gear == nil {
-		return 15
+		return noSuggestedGear
 	}
 
 	return gear.Suggested
 }
 
+// GearSuggestion returns the gear suggested by the game and whether there is a suggestion.
+func (t *Transformer) GearSuggestion() GearSuggestion {
+	gear := t.RawTelemetry.TransmissionGear
+	if gear == nil || gear.Suggested == noSuggestedGear {
+		return GearSuggestion{}
+	}
+
+	return GearSuggestion{Gear: int(gear.Suggested), Valid: true} //nolint:gosec // the suggested gear is four bits
+}
+
+// HasSuggestedGear reports whether the game is suggesting a gear.
+func (t *Transformer) HasSuggestedGear() bool {
+	return t.GearSuggestion().Valid
+}
+
 func (t *Transformer) SurfaceType() models.CornerSetGeneric[models.SurfaceType] {
 	surfaceType := t.RawTelemetry.SurfaceType
 	if surfaceType == nil {
@@ -436,27 +556,29 @@ func (t *Transformer) SuspensionHeightMetres() models.CornerSet {
 }
 
 func (t *Transformer) TelemetryFormat() models.Name {
-	isAddendum3Format, err := t.RawTelemetry.Addendum3Format()
-	if err == nil && isAddendum3Format {
-		return models.Addendum3
-	}
+	return cached(t, cacheKeyTelemetryFormat, func() models.Name {
+		isAddendum3Format, err := t.RawTelemetry.Addendum3Format()
+		if err == nil && isAddendum3Format {
+			return models.Addendum3
+		}
 
-	isAddendum2Format, err := t.RawTelemetry.Addendum2Format()
-	if err == nil && isAddendum2Format {
-		return models.Addendum2
-	}
+		isAddendum2Format, err := t.RawTelemetry.Addendum2Format()
+		if err == nil && isAddendum2Format {
+			return models.Addendum2
+		}
 
-	isAddendum1Format, err := t.RawTelemetry.Addendum1Format()
-	if err == nil && isAddendum1Format {
-		return models.Addendum1
-	}
+		isAddendum1Format, err := t.RawTelemetry.Addendum1Format()
+		if err == nil && isAddendum1Format {
+			return models.Addendum1
+		}
 
-	isStandardFormat, err := t.RawTelemetry.StandardFormat()
-	if err == nil && isStandardFormat {
-		return models.Standard
-	}
+		isStandardFormat, err := t.RawTelemetry.StandardFormat()
+		if err == nil && isStandardFormat {
+			return models.Standard
+		}
 
-	return "unknown"
+		return "unknown"
+	})
 }
 
 func (t *Transformer) TelemetryStarted() bool {
@@ -489,27 +611,29 @@ func (t *Transformer) TranslationEnvelope() models.TranslationalEnvelope {
 }
 
 func (t *Transformer) Transmission() Transmission {
-	ratios := t.RawTelemetry.TransmissionGearRatio
-	if ratios == nil {
-		return Transmission{
-			Gears:      0,
-			GearRatios: make([]float32, 8),
+	return cached(t, cacheKeyTransmission, func() Transmission {
+		ratios := t.RawTelemetry.TransmissionGearRatio
+		if ratios == nil {
+			return Transmission{
+				Gears:      0,
+				GearRatios: make([]float32, 8),
+			}
 		}
-	}
 
-	// TODO: figure out how to support vehicles with more than 8 gears (Lexus LC500)
-	gearCount := 0
+		// TODO: figure out how to support vehicles with more than 8 gears (Lexus LC500)
+		gearCount := 0
 
-	for _, ratio := range ratios.Gear {
-		if ratio > 0 {
-			gearCount++
+		for _, ratio := range ratios.Gear {
+			if ratio > 0 {
+				gearCount++
+			}
 		}
-	}
 
-	return Transmission{
-		Gears:      gearCount,
-		GearRatios: ratios.Gear,
-	}
+		return Transmission{
+			Gears:      gearCount,
+			GearRatios: ratios.Gear,
+		}
+	})
 }
 
 func (t *Transformer) TransmissionTopSpeedRatio() float32 {
@@ -612,117 +736,103 @@ func (t *Transformer) Unknown0x154() float32 {
 }
 
 func (t *Transformer) VehicleAspiration() string {
-	t.UpdateVehicle()
-
-	return t.Vehicle.Aspiration
+	return t.currentVehicle().Aspiration
 }
 
 func (t *Transformer) VehicleAspirationExpanded() string {
-	t.UpdateVehicle()
-
-	return t.Vehicle.ExpandedAspiration()
+	return t.currentVehicle().ExpandedAspiration()
 }
 
 func (t *Transformer) VehicleEngineLayout() string {
-	t.UpdateVehicle()
-
-	return t.Vehicle.EngineLayout
+	return t.currentVehicle().EngineLayout
 }
 
 func (t *Transformer) VehicleEngineBankAngle() float32 {
-	t.UpdateVehicle()
-
-	return t.Vehicle.EngineBankAngle
+	return t.currentVehicle().EngineBankAngle
 }
 
 func (t *Transformer) VehicleEngineCrankPlaneAngle() float32 {
-	t.UpdateVehicle()
+	return t.currentVehicle().EngineCrankPlaneAngle
+}
 
-	return t.Vehicle.EngineCrankPlaneAngle
+func (t *Transformer) VehicleEngineRedline() int {
+	return t.currentVehicle().EngineRedline
 }
 
 func (t *Transformer) VehicleCategory() string {
-	t.UpdateVehicle()
+	return t.currentVehicle().Category
+}
 
-	return t.Vehicle.Category
+func (t *Transformer) VehicleCategoryExpanded() string {
+	return t.currentVehicle().ExpandedCategory()
 }
 
 func (t *Transformer) VehicleDrivetrain() string {
-	t.UpdateVehicle()
+	return t.currentVehicle().Drivetrain
+}
 
-	return t.Vehicle.Drivetrain
+func (t *Transformer) VehicleDrivetrainExpanded() string {
+	return t.currentVehicle().ExpandedDrivetrain()
 }
 
 func (t *Transformer) VehicleHasOpenCockpit() bool {
-	t.UpdateVehicle()
-
-	return t.Vehicle.OpenCockpit
+	return t.currentVehicle().OpenCockpit
 }
 
 func (t *Transformer) VehicleID() uint32 {
-	t.UpdateVehicle()
-
-	return uint32(t.Vehicle.CarID) //nolint:gosec // TODO: might be an issue with the -10000 validation ID
+	return uint32(t.currentVehicle().CarID) //nolint:gosec // TODO: might be an issue with the -10000 validation ID
 }
 
 func (t *Transformer) VehicleManufacturer() string {
-	t.UpdateVehicle()
+	return t.currentVehicle().Manufacturer
+}
 
-	return t.Vehicle.Manufacturer
+// VehicleManufacturerID returns the identifier of the vehicle manufacturer on the Gran Turismo website, which also
+// names the manufacturer logo, or an empty string when unknown.
+func (t *Transformer) VehicleManufacturerID() string {
+	return t.currentVehicle().ManufacturerID
 }
 
-func (t *Transformer) VehicleModel() string {
-	t.UpdateVehicle()
+// VehicleManufacturerCountry returns the ISO 3166 country or region code of the vehicle manufacturer, such as "JP" or
+// "DE", or an empty string when unknown.
+func (t *Transformer) VehicleManufacturerCountry() string {
+	return t.currentVehicle().ManufacturerCountry
+}
 
-	return t.Vehicle.Model
+func (t *Transformer) VehicleModel() string {
+	return t.currentVehicle().Model
 }
 
 func (t *Transformer) VehicleType() string {
-	t.UpdateVehicle()
-
-	return t.Vehicle.CarType
+	return t.currentVehicle().CarType
 }
 
 func (t *Transformer) VehicleYear() int {
-	t.UpdateVehicle()
-
-	return t.Vehicle.Year
+	return t.currentVehicle().Year
 }
 
 func (t *Transformer) VehicleLengthMillimetres() int {
-	t.UpdateVehicle()
-
-	return t.Vehicle.Length
+	return t.currentVehicle().Length
 }
 
 func (t *Transformer) VehicleWidthMillimetres() int {
-	t.UpdateVehicle()
-
-	return t.Vehicle.Width
+	return t.currentVehicle().Width
 }
 
 func (t *Transformer) VehicleHeightMillimetres() int {
-	t.UpdateVehicle()
-
-	return t.Vehicle.Height
+	return t.currentVehicle().Height
 }
 
 func (t *Transformer) VehicleWheelbaseMillimetres() int {
-	t.UpdateVehicle()
-
-	return t.Vehicle.Wheelbase
+	return t.currentVehicle().Wheelbase
 }
 
 func (t *Transformer) VehicleTrackFrontMillimetres() int {
-	t.UpdateVehicle()
-
-	return t.Vehicle.TrackFront
+	return t.currentVehicle().TrackFront
 }
 
 func (t *Transformer) VehicleTrackRearMillimetres() int {
-	t.UpdateVehicle()
-
-	return t.Vehicle.TrackRear
+	return t.currentVehicle().TrackRear
 }
 
 func (t *Transformer) VelocityVector() models.Vector {
@@ -777,29 +887,51 @@ func (t *Transformer) WaterTemperatureCelsius() float32 {
 	return t.RawTelemetry.WaterTemperature
 }
 
-func (t *Transformer) UpdateVehicle() {
-	if t.IsInMainMenu() {
-		t.Vehicle = vehicles.Vehicle{}
-
-		return
-	}
-
-	vehicleID := int(t.RawTelemetry.VehicleId)
+// CurrentVehicle returns the vehicle resolved for the current frame by UpdateVehicle.
+func (t *Transformer) CurrentVehicle() vehicles.Vehicle {
+	return *t.currentVehicle()
+}
 
-	if t.Vehicle.CarID != vehicleID || t.Vehicle.Manufacturer == "" {
-		vehicle, err := t.inventory.GetVehicleByID(vehicleID)
-		if err != nil {
-			vehicle = vehicles.Vehicle{
-				CarID: vehicleID,
+// UpdateVehicle resolves the vehicle for the current frame from the inventory, reusing the previous vehicle when the
+// vehicle ID has not changed, and copies it to the deprecated Vehicle field. The client calls this once for each
+// decoded frame, on the goroutine decoding the stream, and the vehicle getters only read the resolved vehicle.
+func (t *Transformer) UpdateVehicle() {
+	var vehicle vehicles.Vehicle
+
+	if !t.IsInMainMenu() {
+		vehicleID := int(t.RawTelemetry.VehicleId)
+
+		// The previous vehicle is read from the field, so that a vehicle set on it is kept while the ID is unchanged
+		if t.Vehicle.CarID == vehicleID && t.Vehicle.Manufacturer != "" {
+			vehicle = t.Vehicle
+		} else {
+			var err error
+
+			vehicle, err = t.inventory.GetVehicleByID(vehicleID)
+			if err != nil {
+				vehicle = vehicles.Vehicle{
+					CarID: vehicleID,
+				}
 			}
 		}
 
-		t.Vehicle = vehicle
+		vehicleCategory := t.RawTelemetry.VehicleCategory
+
+		if vehicleCategory != "" && vehicle.Category != vehicleCategory {
+			vehicle.Category = vehicleCategory
+		}
 	}
 
-	vehicleCategory := t.RawTelemetry.VehicleCategory
+	// A new vehicle is stored rather than changing the previous one in place, as clones share it
+	t.Vehicle = vehicle
+	t.vehicle = &vehicle
+}
 
-	if vehicleCategory != "" && t.Vehicle.Category != vehicleCategory {
-		t.Vehicle.Category = vehicleCategory
+// currentVehicle returns the vehicle resolved by the last call to UpdateVehicle, which must not be modified.
+func (t *Transformer) currentVehicle() *vehicles.Vehicle {
+	if t.vehicle == nil {
+		return &vehicles.Vehicle{}
 	}
+
+	return t.vehicle
 }
@@ -1,5 +1,12 @@
 package gttelemetry
 
+import (
+	"net/netip"
+	"time"
+
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
 // SetFormatStandard sets the telemetry format to Standard (format "A") for testing purposes.
 // This allows tests to set the format without parsing a binary packet.
 func (t *Transformer) SetFormatStandard() {
@@ -30,6 +37,13 @@ func (t *Transformer) SetFormatInvalid() {
 	t.RawTelemetry.SetFormatInvalid()
 }
 
+// SetVehicle sets the vehicle resolved for the current frame for testing purposes.
+// This allows tests to set vehicle details without an inventory lookup.
+func (t *Transformer) SetVehicle(vehicle vehicles.Vehicle) {
+	t.Vehicle = vehicle
+	t.vehicle = &vehicle
+}
+
 // SetAngularVelocityVector sets the angular velocity vector for testing purposes.
 // This allows tests to set telemetry values without parsing a binary packet.
 func (t *Transformer) SetAngularVelocityVector(x, y, z float32) {
@@ -120,3 +134,18 @@ func (t *Transformer) SetSuspensionHeight(fl, fr, rl, rr float32) {
 func (t *Transformer) SetTransmissionGearRatio(gears []float32) {
 	t.RawTelemetry.SetTransmissionGearRatio(gears)
 }
+
+// SetSourceAddress sets the network address the frame was sent from for testing purposes.
+func (t *Transformer) SetSourceAddress(address netip.AddrPort) {
+	t.sourceAddress = address
+}
+
+// SetDecodedAt sets the time the frame was decoded for testing purposes.
+func (t *Transformer) SetDecodedAt(decodedAt time.Time) {
+	t.decodedAt = decodedAt
+}
+
+// SetReceivedAt sets the time the frame was received for testing purposes.
+func (t *Transformer) SetReceivedAt(receivedAt time.Time) {
+	t.receivedAt = receivedAt
+}
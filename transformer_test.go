@@ -29,6 +29,8 @@ func (suite *TransformerTestSuite) SetupTest() {
 		"1234": {
 			"model": "Dummy Model",
 			"manufacturer": "Dummy Manufacturer",
+			"manufacturerId": "dummy",
+			"manufacturerCountry": "GB",
 			"category": "Gr.1",
 			"drivetrain": "FR",
 			"aspiration": "NA",
@@ -796,6 +798,33 @@ func (suite *TransformerTestSuite) TestRaceLapsReturnsCorrectValue() {
 	suite.Equal(wantValue, gotValue)
 }
 
+func (suite *TransformerTestSuite) TestFrameAgeAndIsStale() {
+	// Arrange
+	tests := map[string]struct {
+		decodedAt time.Time
+		wantAge   time.Duration
+		wantStale bool
+	}{
+		"no frame decoded": {wantStale: true},
+		"recent frame":     {decodedAt: time.Now().Add(-100 * time.Millisecond), wantAge: 100 * time.Millisecond},
+		"old frame":        {decodedAt: time.Now().Add(-5 * time.Second), wantAge: 5 * time.Second, wantStale: true},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			suite.transformer.SetDecodedAt(test.decodedAt)
+
+			// Act
+			gotAge := suite.transformer.FrameAge()
+			gotStale := suite.transformer.IsStale(time.Second)
+
+			// Assert
+			suite.InDelta(test.wantAge.Seconds(), gotAge.Seconds(), 0.5)
+			suite.Equal(test.wantStale, gotStale)
+		})
+	}
+}
+
 func (suite *TransformerTestSuite) TestRideHeightMetresReturnsCorrectValue() {
 	// Arrange
 	wantValue := float32(0.12345)
@@ -837,6 +866,36 @@ func (suite *TransformerTestSuite) TestRotationEnvelopeReturnsCorrectValue() {
 	suite.InEpsilon(float32(0.3), gotValue.Roll, 1e-5)
 }
 
+func (suite *TransformerTestSuite) TestRawReturnsCopyOfFrame() {
+	// Arrange
+	suite.transformer.RawTelemetry.EngineRpm = 6500
+	suite.transformer.SetTransmissionGearRatio([]float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+	suite.transformer.SetTyreTemperature(80, 81, 82, 83)
+
+	// Act
+	raw := suite.transformer.Raw()
+	raw.EngineRpm = 0
+	raw.TransmissionGearRatio.Gear[0] = 0
+	raw.TyreTemperature.FrontLeft = 0
+
+	// Assert
+	suite.InDelta(6500, suite.transformer.EngineRPM(), 1e-6)
+	suite.InDelta(3.2, suite.transformer.Transmission().GearRatios[0], 1e-6)
+	suite.InDelta(80, suite.transformer.TyreTemperatureCelsius().FrontLeft, 1e-6)
+}
+
+func (suite *TransformerTestSuite) TestRawHandlesEmptyFrame() {
+	// Arrange
+	transformer := gttelemetry.NewTransformer(nil)
+
+	// Act
+	raw := transformer.Raw()
+
+	// Assert
+	suite.Nil(raw.Flags)
+	suite.Nil(raw.TransmissionGearRatio)
+}
+
 func (suite *TransformerTestSuite) TestSequenceIDReturnsCorrectValue() {
 	// Arrange
 	wantValue := uint32(123456789)
@@ -849,6 +908,68 @@ func (suite *TransformerTestSuite) TestSequenceIDReturnsCorrectValue() {
 	suite.Equal(wantValue, gotValue)
 }
 
+func (suite *TransformerTestSuite) TestSetupFingerprintIsStableForSameSetup() {
+	// Arrange
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.RawTelemetry.CalculatedMaxSpeed = 300
+	suite.transformer.RawTelemetry.TransmissionTopSpeedRatio = 3.5
+	suite.transformer.SetTransmissionGearRatio([]float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+	suite.transformer.SetTyreRadius(0.33, 0.33, 0.34, 0.34)
+	suite.transformer.RawTelemetry.SequenceId = 1
+
+	// Act
+	first := suite.transformer.SetupFingerprint()
+	suite.transformer.RawTelemetry.SequenceId = 2
+	second := suite.transformer.SetupFingerprint()
+
+	// Assert
+	suite.NotEmpty(first)
+	suite.Equal(first, second)
+}
+
+func (suite *TransformerTestSuite) TestSetupFingerprintChangesWithSetup() {
+	tests := map[string]struct {
+		change func(t *gttelemetry.Transformer)
+	}{
+		"vehicle": {
+			change: func(t *gttelemetry.Transformer) { t.RawTelemetry.VehicleId = 5678 },
+		},
+		"gear ratios": {
+			change: func(t *gttelemetry.Transformer) {
+				t.SetTransmissionGearRatio([]float32{3.0, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+			},
+		},
+		"tyre radius": {
+			change: func(t *gttelemetry.Transformer) { t.SetTyreRadius(0.34, 0.34, 0.35, 0.35) },
+		},
+		"calculated top speed": {
+			change: func(t *gttelemetry.Transformer) { t.RawTelemetry.CalculatedMaxSpeed = 290 },
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.transformer.RawTelemetry.VehicleId = 1234
+			suite.transformer.RawTelemetry.CalculatedMaxSpeed = 300
+			suite.transformer.SetTransmissionGearRatio([]float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+			suite.transformer.SetTyreRadius(0.33, 0.33, 0.34, 0.34)
+			suite.transformer.RawTelemetry.SequenceId = 1
+			suite.transformer.UpdateVehicle()
+			before := suite.transformer.SetupFingerprint()
+
+			// Act
+			test.change(suite.transformer)
+			suite.transformer.RawTelemetry.SequenceId = 2
+			suite.transformer.UpdateVehicle()
+			after := suite.transformer.SetupFingerprint()
+
+			// Assert
+			suite.NotEqual(before, after)
+		})
+	}
+}
+
 func (suite *TransformerTestSuite) TestSteeringWheelAngleRadiansReturnsCorrectValue() {
 	// Arrange
 	wantValue := float32(2.18334)
@@ -879,7 +1000,7 @@ func (suite *TransformerTestSuite) TestSuggestedGearReturnsNeutralWhenTelemetryI
 	suite.transformer.RawTelemetry.TransmissionGear = nil
 
 	// Act
-	gotValue := suite.transformer.SuggestedGear()
+	gotValue := suite.transformer.SuggestedGear() //nolint:staticcheck // covers the deprecated method
 
 	// Assert
 	suite.Equal(wantValue, gotValue)
@@ -897,7 +1018,7 @@ func (suite *TransformerTestSuite) TestSuggestedGearReturnsCorrectValue() {
 			}
 
 			// Act
-			gotValue := suite.transformer.SuggestedGear()
+			gotValue := suite.transformer.SuggestedGear() //nolint:staticcheck // covers the deprecated method
 
 			// Assert
 			suite.Equal(uint64(wantValue), gotValue) //nolint:gosec // not an issue as test is between 0 and 15
@@ -905,6 +1026,40 @@ func (suite *TransformerTestSuite) TestSuggestedGearReturnsCorrectValue() {
 	}
 }
 
+func (suite *TransformerTestSuite) TestGearSuggestion() {
+	tests := map[string]struct {
+		gear *telemetry.GranTurismoTelemetry_TransmissionGear
+		want gttelemetry.GearSuggestion
+	}{
+		"missing transmission data": {
+			gear: nil,
+			want: gttelemetry.GearSuggestion{},
+		},
+		"no suggestion": {
+			gear: &telemetry.GranTurismoTelemetry_TransmissionGear{Suggested: 15},
+			want: gttelemetry.GearSuggestion{},
+		},
+		"suggested gear": {
+			gear: &telemetry.GranTurismoTelemetry_TransmissionGear{Current: 4, Suggested: 3},
+			want: gttelemetry.GearSuggestion{Gear: 3, Valid: true},
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			suite.transformer.RawTelemetry.TransmissionGear = test.gear
+
+			// Act
+			got := suite.transformer.GearSuggestion()
+
+			// Assert
+			suite.Equal(test.want, got)
+			suite.Equal(test.want.Valid, suite.transformer.HasSuggestedGear())
+		})
+	}
+}
+
 func (suite *TransformerTestSuite) TestSurfaceTypeRetursCorrectValue() {
 	testCases := []struct {
 		name          string
@@ -1334,6 +1489,7 @@ func (suite *TransformerTestSuite) TestVehicleAspirationReturnsCorrectValueWhenT
 	wantValue := "NA"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleAspiration()
@@ -1347,6 +1503,7 @@ func (suite *TransformerTestSuite) TestVehicleAspirationExpandedReturnsCorrectVa
 	wantValue := "Naturally Aspirated"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleAspirationExpanded()
@@ -1360,6 +1517,7 @@ func (suite *TransformerTestSuite) TestVehicleEngineLayoutReturnsCorrectValueWhe
 	wantValue := "V6"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleEngineLayout()
@@ -1374,6 +1532,7 @@ func (suite *TransformerTestSuite) TestVehicleEngineBankAngleReturnsCorrectValue
 
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleEngineBankAngle()
@@ -1388,6 +1547,7 @@ func (suite *TransformerTestSuite) TestVehicleEngineCrankPlaneAngleReturnsCorrec
 
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleEngineCrankPlaneAngle()
@@ -1445,6 +1605,7 @@ func (suite *TransformerTestSuite) TestVehicleIDReturnsCorrectValueWhenTelemetry
 	wantValue := uint32(1234)
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = wantValue
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleID()
@@ -1458,6 +1619,7 @@ func (suite *TransformerTestSuite) TestVehicleCategoryReturnsCorrectValueWhenTel
 	wantValue := "Gr.1"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleCategory()
@@ -1466,11 +1628,26 @@ func (suite *TransformerTestSuite) TestVehicleCategoryReturnsCorrectValueWhenTel
 	suite.Equal(wantValue, gotValue)
 }
 
+func (suite *TransformerTestSuite) TestVehicleCategoryExpandedReturnsCorrectValueWhenTelemetryHasKnownID() {
+	// Arrange
+	wantValue := "Group 1"
+	suite.transformer.Vehicle = vehicles.Vehicle{}
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
+
+	// Act
+	gotValue := suite.transformer.VehicleCategoryExpanded()
+
+	// Assert
+	suite.Equal(wantValue, gotValue)
+}
+
 func (suite *TransformerTestSuite) TestVehicleDrivetrainReturnsCorrectValueWhenTelemetryHasKnownID() {
 	// Arrange
 	wantValue := "FR"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleDrivetrain()
@@ -1479,11 +1656,26 @@ func (suite *TransformerTestSuite) TestVehicleDrivetrainReturnsCorrectValueWhenT
 	suite.Equal(wantValue, gotValue)
 }
 
+func (suite *TransformerTestSuite) TestVehicleDrivetrainExpandedReturnsCorrectValueWhenTelemetryHasKnownID() {
+	// Arrange
+	wantValue := "Front-engine, Rear-wheel drive"
+	suite.transformer.Vehicle = vehicles.Vehicle{}
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
+
+	// Act
+	gotValue := suite.transformer.VehicleDrivetrainExpanded()
+
+	// Assert
+	suite.Equal(wantValue, gotValue)
+}
+
 func (suite *TransformerTestSuite) TestVehicleManufacturerReturnsCorrectValueWhenTelemetryHasKnownID() {
 	// Arrange
 	wantValue := "Dummy Manufacturer"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleManufacturer()
@@ -1492,11 +1684,40 @@ func (suite *TransformerTestSuite) TestVehicleManufacturerReturnsCorrectValueWhe
 	suite.Equal(wantValue, gotValue)
 }
 
+func (suite *TransformerTestSuite) TestVehicleManufacturerIDReturnsCorrectValueWhenTelemetryHasKnownID() {
+	// Arrange
+	wantValue := "dummy"
+	suite.transformer.Vehicle = vehicles.Vehicle{}
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
+
+	// Act
+	gotValue := suite.transformer.VehicleManufacturerID()
+
+	// Assert
+	suite.Equal(wantValue, gotValue)
+}
+
+func (suite *TransformerTestSuite) TestVehicleManufacturerCountryReturnsCorrectValueWhenTelemetryHasKnownID() {
+	// Arrange
+	wantValue := "GB"
+	suite.transformer.Vehicle = vehicles.Vehicle{}
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
+
+	// Act
+	gotValue := suite.transformer.VehicleManufacturerCountry()
+
+	// Assert
+	suite.Equal(wantValue, gotValue)
+}
+
 func (suite *TransformerTestSuite) TestVehicleModelReturnsCorrectValueWhenTelemetryHasKnownID() {
 	// Arrange
 	wantValue := "Dummy Model"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleModel()
@@ -1523,6 +1744,7 @@ func (suite *TransformerTestSuite) TestVehicleTypeReturnsCorrectValueWhenTelemet
 	wantValue := "race"
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleType()
@@ -1536,6 +1758,7 @@ func (suite *TransformerTestSuite) TestVehicleYearReturnsCorrectValueWhenTelemet
 	wantValue := 2025
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleYear()
@@ -1549,6 +1772,7 @@ func (suite *TransformerTestSuite) TestVehicleLengthMillimetresReturnsCorrectVal
 	wantValue := 4500
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleLengthMillimetres()
@@ -1562,6 +1786,7 @@ func (suite *TransformerTestSuite) TestVehicleWidthMillimetresReturnsCorrectValu
 	wantValue := 1800
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleWidthMillimetres()
@@ -1575,6 +1800,7 @@ func (suite *TransformerTestSuite) TestVehicleHeightMillimetresReturnsCorrectVal
 	wantValue := 1300
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleHeightMillimetres()
@@ -1588,6 +1814,7 @@ func (suite *TransformerTestSuite) TestVehicleWheelbaseMillimetresReturnsCorrect
 	wantValue := 2700
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleWheelbaseMillimetres()
@@ -1601,6 +1828,7 @@ func (suite *TransformerTestSuite) TestVehicleTrackFrontMillimetresReturnsCorrec
 	wantValue := 1550
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleTrackFrontMillimetres()
@@ -1614,6 +1842,7 @@ func (suite *TransformerTestSuite) TestVehicleTrackRearMillimetresReturnsCorrect
 	wantValue := 1600
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleTrackRearMillimetres()
@@ -1633,6 +1862,7 @@ func (suite *TransformerTestSuite) TestVehicleObjectIsEmptyWhenTelemetryHasUnkno
 
 	invalidVehicleID := uint32(0)
 	suite.transformer.RawTelemetry.VehicleId = invalidVehicleID
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	suite.transformer.VehicleID() // trigger vehicle object update
@@ -1929,6 +2159,18 @@ func (suite *TransformerTestSuite) TestRaceTypeReturnsUnknownForSmallFieldWithLa
 	suite.Equal(models.RaceTypeUnknown, gotValue)
 }
 
+func (suite *TransformerTestSuite) TestRaceTypeReturnsSprintForSingleLapWithLargeField() {
+	// Arrange
+	suite.transformer.RawTelemetry.RaceLaps = 1
+	suite.transformer.RawTelemetry.RaceEntrants = 16
+
+	// Act
+	gotValue := suite.transformer.RaceType()
+
+	// Assert
+	suite.Equal(models.RaceTypeSprint, gotValue)
+}
+
 func (suite *TransformerTestSuite) TestSurfaceTypeReturnsEmptyObjectWhenTelemetryIsNil() {
 	// Arrange
 	suite.transformer.RawTelemetry.SurfaceType = nil
@@ -2041,3 +2283,60 @@ func (suite *TransformerTestSuite) TestUpdateVehicleDoesNotReloadWhenSameVehicle
 	// Assert
 	suite.Equal("Custom Manufacturer", suite.transformer.Vehicle.Manufacturer)
 }
+
+func (suite *TransformerTestSuite) TestVehicleIsOnlyResolvedByUpdateVehicle() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 10
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
+
+	// Act
+	suite.transformer.RawTelemetry.SequenceId = 11
+	suite.transformer.RawTelemetry.VehicleId = 9999
+	beforeUpdate := suite.transformer.VehicleManufacturer()
+
+	suite.transformer.UpdateVehicle()
+	afterUpdate := suite.transformer.VehicleManufacturer()
+
+	// Assert
+	suite.Equal("Dummy Manufacturer", beforeUpdate, "getters read the vehicle resolved for the last frame")
+	suite.Empty(afterUpdate)
+}
+
+func (suite *TransformerTestSuite) TestCloneIsNotChangedByLaterFrames() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 10
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.RawTelemetry.EngineRpm = 6500
+	suite.transformer.SetTransmissionGearRatio([]float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+	suite.transformer.UpdateVehicle()
+
+	// Act
+	clone := suite.transformer.Clone()
+
+	suite.transformer.RawTelemetry.SequenceId = 11
+	suite.transformer.RawTelemetry.VehicleId = 9999
+	suite.transformer.RawTelemetry.EngineRpm = 7000
+	suite.transformer.RawTelemetry.TransmissionGearRatio.Gear[0] = 4
+	suite.transformer.UpdateVehicle()
+
+	// Assert
+	suite.Equal(uint32(10), clone.SequenceID())
+	suite.InDelta(6500, clone.EngineRPM(), 1e-6)
+	suite.InDelta(3.2, clone.Transmission().GearRatios[0], 1e-6)
+	suite.Equal("Dummy Manufacturer", clone.VehicleManufacturer())
+	suite.Equal("Dummy Manufacturer", clone.Vehicle.Manufacturer)
+	suite.Empty(suite.transformer.VehicleManufacturer())
+}
+
+func (suite *TransformerTestSuite) TestCopyDoesNotCopyLocks() {
+	// Arrange
+	suite.transformer.RawTelemetry.SequenceId = 10
+	suite.transformer.RawTelemetry.EngineRpm = 6500
+
+	// Act
+	snapshot := *suite.transformer
+
+	// Assert
+	suite.InDelta(6500, snapshot.EngineRPM(), 1e-6, "go vet reports a copied lock if Transformer holds one by value")
+}
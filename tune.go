@@ -0,0 +1,119 @@
+package gttelemetry
+
+import (
+	"slices"
+)
+
+// TuneFingerprint identifies a transmission tune, the gear ratio table and top speed ratio of a vehicle.
+type TuneFingerprint string
+
+// TuneRecord describes a transmission tune seen during a session.
+type TuneRecord struct {
+	VehicleID     uint32          `json:"vehicleId"`
+	Fingerprint   TuneFingerprint `json:"fingerprint"`
+	GearRatios    []float32       `json:"gearRatios"`
+	TopSpeedRatio float32         `json:"topSpeedRatio"`
+	SequenceID    uint32          `json:"sequenceId"`
+}
+
+// TuneChange describes a change to the transmission tune of a vehicle.
+type TuneChange struct {
+	Previous TuneRecord `json:"previous"`
+	Current  TuneRecord `json:"current"`
+}
+
+// ChangedGears returns the gears, numbered from 1, whose ratio differs between the previous and current tune,
+// including gears added or removed by a change to the number of gears.
+func (c TuneChange) ChangedGears() []int {
+	var gears []int
+
+	for i := range max(len(c.Previous.GearRatios), len(c.Current.GearRatios)) {
+		if i >= len(c.Previous.GearRatios) || i >= len(c.Current.GearRatios) || c.Previous.GearRatios[i] != c.Current.GearRatios[i] {
+			gears = append(gears, i+1)
+		}
+	}
+
+	return gears
+}
+
+// TopSpeedRatioChanged reports whether the transmission top speed ratio differs between the previous and current tune.
+func (c TuneChange) TopSpeedRatioChanged() bool {
+	return c.Previous.TopSpeedRatio != c.Current.TopSpeedRatio
+}
+
+// TuneMonitor detects changes to the transmission tune of a vehicle and keeps a history of the tunes seen, so that
+// laps driven with different setups can be told apart.
+//
+// Gear ratios can only be changed in the garage, so a change is reported when the vehicle is next driven with a
+// different gear ratio table or top speed ratio to the last time it was driven. Switching to another vehicle is not a
+// tune change.
+type TuneMonitor struct {
+	lastSequence uint32
+	current      map[uint32]TuneRecord
+	history      []TuneRecord
+}
+
+// NewTuneMonitor creates a new tune monitor.
+func NewTuneMonitor() *TuneMonitor {
+	return &TuneMonitor{
+		current: make(map[uint32]TuneRecord),
+	}
+}
+
+// Update evaluates the latest telemetry frame and returns the change when the tune of the vehicle has changed.
+func (m *TuneMonitor) Update(t *Transformer) (TuneChange, bool) {
+	sequenceID := t.SequenceID()
+	if sequenceID == m.lastSequence {
+		return TuneChange{}, false
+	}
+
+	m.lastSequence = sequenceID
+
+	transmission := t.Transmission()
+	if !t.IsOnCircuit() || transmission.Gears == 0 {
+		return TuneChange{}, false
+	}
+
+	vehicleID := t.VehicleID()
+	topSpeedRatio := t.TransmissionTopSpeedRatio()
+	fingerprint := tuneFingerprint(vehicleID, transmission.GearRatios, topSpeedRatio)
+
+	previous, seen := m.current[vehicleID]
+	if seen && previous.Fingerprint == fingerprint {
+		return TuneChange{}, false
+	}
+
+	record := TuneRecord{
+		VehicleID:     vehicleID,
+		Fingerprint:   fingerprint,
+		GearRatios:    slices.Clone(transmission.GearRatios),
+		TopSpeedRatio: topSpeedRatio,
+		SequenceID:    sequenceID,
+	}
+
+	m.current[vehicleID] = record
+	m.history = append(m.history, record)
+
+	if !seen {
+		return TuneChange{}, false
+	}
+
+	return TuneChange{Previous: previous, Current: record}, true
+}
+
+// Current returns the latest tune seen for the vehicle.
+func (m *TuneMonitor) Current(vehicleID uint32) (TuneRecord, bool) {
+	record, ok := m.current[vehicleID]
+
+	return record, ok
+}
+
+// History returns every tune seen, in the order they were first driven.
+func (m *TuneMonitor) History() []TuneRecord {
+	return m.history
+}
+
+// tuneFingerprint hashes the vehicle ID, gear ratios and top speed ratio.
+func tuneFingerprint(vehicleID uint32, gearRatios []float32, topSpeedRatio float32) TuneFingerprint {
+	return TuneFingerprint(fingerprint(vehicleID, append(slices.Clone(gearRatios), topSpeedRatio)...))
+}
@@ -0,0 +1,120 @@
+package gttelemetry_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type TuneMonitorTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+	monitor     *gttelemetry.TuneMonitor
+}
+
+func TestTuneMonitorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TuneMonitorTestSuite))
+}
+
+func (suite *TuneMonitorTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.RawTelemetry.TransmissionTopSpeedRatio = 3.5
+	suite.transformer.SetTransmissionGearRatio([]float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+
+	suite.monitor = gttelemetry.NewTuneMonitor()
+}
+
+func (suite *TuneMonitorTestSuite) frame() (gttelemetry.TuneChange, bool) {
+	suite.transformer.RawTelemetry.SequenceId++
+	suite.transformer.UpdateVehicle()
+
+	return suite.monitor.Update(suite.transformer)
+}
+
+func (suite *TuneMonitorTestSuite) TestFirstTuneIsRecordedWithoutChange() {
+	// Act
+	_, changed := suite.frame()
+	_, changedAgain := suite.frame()
+
+	// Assert
+	suite.False(changed)
+	suite.False(changedAgain)
+	suite.Require().Len(suite.monitor.History(), 1)
+	suite.Equal(uint32(1234), suite.monitor.History()[0].VehicleID)
+	suite.NotEmpty(suite.monitor.History()[0].Fingerprint)
+}
+
+func (suite *TuneMonitorTestSuite) TestGearRatioChangeIsReported() {
+	// Arrange
+	suite.frame()
+
+	// Act
+	suite.transformer.SetTransmissionGearRatio([]float32{3.0, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+	change, changed := suite.frame()
+
+	// Assert
+	suite.Require().True(changed)
+	suite.InDelta(3.2, change.Previous.GearRatios[0], 0.001)
+	suite.InDelta(3.0, change.Current.GearRatios[0], 0.001)
+	suite.NotEqual(change.Previous.Fingerprint, change.Current.Fingerprint)
+	suite.Len(suite.monitor.History(), 2)
+	suite.Equal([]int{1}, change.ChangedGears())
+	suite.False(change.TopSpeedRatioChanged())
+}
+
+func (suite *TuneMonitorTestSuite) TestTopSpeedRatioChangeIsReported() {
+	// Arrange
+	suite.frame()
+
+	// Act
+	suite.transformer.RawTelemetry.TransmissionTopSpeedRatio = 3.8
+	change, changed := suite.frame()
+
+	// Assert
+	suite.True(changed)
+	suite.Empty(change.ChangedGears())
+	suite.True(change.TopSpeedRatioChanged())
+}
+
+func (suite *TuneMonitorTestSuite) TestVehicleChangeIsNotTuneChange() {
+	// Arrange
+	suite.frame()
+
+	// Act
+	suite.transformer.RawTelemetry.VehicleId = 5678
+	suite.transformer.SetTransmissionGearRatio([]float32{2.8, 1.9, 1.4, 1.1, 0.9, 0, 0, 0})
+	_, changed := suite.frame()
+
+	// Assert
+	suite.False(changed)
+	suite.Len(suite.monitor.History(), 2)
+
+	current, ok := suite.monitor.Current(1234)
+	suite.Require().True(ok)
+	suite.InDelta(3.2, current.GearRatios[0], 0.001)
+}
+
+func (suite *TuneMonitorTestSuite) TestReturningToPreviousTuneIsReported() {
+	// Arrange
+	suite.frame()
+	first := suite.monitor.History()[0].Fingerprint
+
+	suite.transformer.SetTransmissionGearRatio([]float32{3.0, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+	suite.frame()
+
+	// Act
+	suite.transformer.SetTransmissionGearRatio([]float32{3.2, 2.1, 1.5, 1.2, 1.0, 0.8, 0, 0})
+	change, changed := suite.frame()
+
+	// Assert
+	suite.Require().True(changed)
+	suite.Equal(first, change.Current.Fingerprint, "Fingerprints should be stable for the same tune")
+}
@@ -474,6 +474,7 @@ func (suite *UnitAlternatesTestSuite) TestVehicleLengthInchesReturnsCorrectValue
 	// Arrange
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleLengthInches()
@@ -486,6 +487,7 @@ func (suite *UnitAlternatesTestSuite) TestVehicleWidthInchesReturnsCorrectValue(
 	// Arrange
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleWidthInches()
@@ -498,6 +500,7 @@ func (suite *UnitAlternatesTestSuite) TestVehicleHeightInchesReturnsCorrectValue
 	// Arrange
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleHeightInches()
@@ -510,6 +513,7 @@ func (suite *UnitAlternatesTestSuite) TestVehicleWheelbaseInchesReturnsCorrectVa
 	// Arrange
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleWheelbaseInches()
@@ -522,6 +526,7 @@ func (suite *UnitAlternatesTestSuite) TestVehicleTrackFrontInchesReturnsCorrectV
 	// Arrange
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleTrackFrontInches()
@@ -534,6 +539,7 @@ func (suite *UnitAlternatesTestSuite) TestVehicleTrackRearInchesReturnsCorrectVa
 	// Arrange
 	suite.transformer.Vehicle = vehicles.Vehicle{}
 	suite.transformer.RawTelemetry.VehicleId = 1234
+	suite.transformer.UpdateVehicle()
 
 	// Act
 	gotValue := suite.transformer.VehicleTrackRearInches()
@@ -0,0 +1,15 @@
+//go:build tinygo
+
+package gttelemetry
+
+import (
+	"context"
+	"errors"
+)
+
+var errUpdatesUnsupported = errors.New("inventory updates are not supported in TinyGo builds")
+
+// fetchVersion is not supported in TinyGo builds, which leave out net/http.
+func fetchVersion(_ context.Context, _ string) (*RemoteVersion, error) {
+	return nil, errUpdatesUnsupported
+}
@@ -0,0 +1,156 @@
+package gttelemetry
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/zetetos/gt-telemetry/v2/internal/units"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
+)
+
+// VideoCSVPreset selects the column layout of a VideoCSVWriter.
+type VideoCSVPreset int
+
+const (
+	// VideoCSVRaceRender is the generic data logger CSV layout read by RaceRender.
+	VideoCSVRaceRender VideoCSVPreset = iota
+	// VideoCSVTelemetryOverlay is the custom CSV layout read by Telemetry Overlay.
+	VideoCSVTelemetryOverlay
+)
+
+// ErrUnknownVideoCSVPreset indicates a preset name that is not recognised by ParseVideoCSVPreset.
+var ErrUnknownVideoCSVPreset = errors.New("unknown video CSV preset")
+
+//nolint:gochecknoglobals // read-only column layouts
+var videoCSVColumns = map[VideoCSVPreset][]string{
+	VideoCSVRaceRender: {
+		"Time", "Lap", "Latitude", "Longitude", "Altitude (m)", "Speed (KPH)", "Heading", "RPM", "Gear",
+		"Throttle Position (%)", "Brake (%)",
+	},
+	VideoCSVTelemetryOverlay: {
+		"cts (ms)", "lap", "lat (deg)", "lon (deg)", "alt (m)", "speed2d (km/h)", "heading (deg)", "rpm", "gear",
+		"throttle (%)", "brake (%)",
+	},
+}
+
+// ParseVideoCSVPreset returns the preset with the given name, either "racerender" or "telemetry-overlay".
+func ParseVideoCSVPreset(name string) (VideoCSVPreset, error) {
+	switch name {
+	case "racerender":
+		return VideoCSVRaceRender, nil
+	case "telemetry-overlay":
+		return VideoCSVTelemetryOverlay, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrUnknownVideoCSVPreset, name)
+}
+
+// VideoCSVWriter writes one CSV row per telemetry frame in the layout expected by a video overlay tool, so that
+// Gran Turismo sessions can be used in existing overlay pipelines. Latitude and longitude are synthesised from the
+// map coordinates by projecting them around a geographic anchor. Time is counted in frames from the first frame
+// written, so the output is the same when a recording is replayed at any speed.
+type VideoCSVWriter struct {
+	writer       *csv.Writer
+	preset       VideoCSVPreset
+	anchor       geo.Anchor
+	started      bool
+	lastSequence uint32
+	frames       uint64
+	precision    ChannelPrecision
+}
+
+// NewVideoCSVWriter creates a writer for the preset that projects map coordinates around the anchor. Use
+// geo.DefaultAnchor when the circuit has no real-world location.
+func NewVideoCSVWriter(w io.Writer, preset VideoCSVPreset, anchor geo.Anchor) *VideoCSVWriter {
+	return &VideoCSVWriter{
+		writer: csv.NewWriter(w),
+		preset: preset,
+		anchor: anchor,
+	}
+}
+
+// SetPrecision sets the number of decimal places of the columns read from channels: positionY for the altitude,
+// groundSpeedKPH, engineRPM, throttleInputPercent and brakeInputPercent. Columns of channels without a precision keep
+// the precision of the preset.
+func (v *VideoCSVWriter) SetPrecision(precision ChannelPrecision) {
+	v.precision = precision
+}
+
+// Write writes a row for the latest telemetry frame, preceded by the header row for the first frame. Repeated frames
+// are ignored.
+func (v *VideoCSVWriter) Write(t *Transformer) error {
+	sequenceID := t.SequenceID()
+
+	if !v.started {
+		columns, ok := videoCSVColumns[v.preset]
+		if !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownVideoCSVPreset, v.preset)
+		}
+
+		err := v.writer.Write(columns)
+		if err != nil {
+			return fmt.Errorf("write video CSV header: %w", err)
+		}
+	} else {
+		if sequenceID == v.lastSequence {
+			return nil
+		}
+
+		if sequenceID > v.lastSequence {
+			v.frames += uint64(sequenceID - v.lastSequence)
+		}
+	}
+
+	v.started = true
+	v.lastSequence = sequenceID
+
+	elapsed := t.CorrectedDuration(framesDuration(v.frames))
+	coordinates := t.PositionalMapCoordinates()
+	position := v.anchor.Project(float64(coordinates.X), float64(coordinates.Z))
+
+	timestamp := formatDecimal(elapsed.Seconds(), 3)
+	if v.preset == VideoCSVTelemetryOverlay {
+		timestamp = strconv.FormatInt(elapsed.Milliseconds(), 10)
+	}
+
+	err := v.writer.Write([]string{
+		timestamp,
+		strconv.Itoa(int(t.CurrentLap())),
+		formatDecimal(position.Latitude, 8),
+		formatDecimal(position.Longitude, 8),
+		v.precision.format("positionY", coordinates.Y, 2),
+		v.precision.format("groundSpeedKPH", t.GroundSpeedKPH(), 2),
+		formatDecimal(headingDegrees(t.Heading(), v.anchor.Rotation), 1),
+		v.precision.format("engineRPM", t.EngineRPM(), 0),
+		t.CurrentGearString(),
+		v.precision.format("throttleInputPercent", t.ThrottleInputPercent(), 1),
+		v.precision.format("brakeInputPercent", t.BrakeInputPercent(), 1),
+	})
+	if err != nil {
+		return fmt.Errorf("write video CSV row: %w", err)
+	}
+
+	return nil
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (v *VideoCSVWriter) Flush() error {
+	v.writer.Flush()
+
+	return v.writer.Error()
+}
+
+// headingDegrees converts the heading reported by the game in radians into a compass bearing in degrees, rotated in
+// the same way as the map coordinates.
+func headingDegrees(heading float32, rotation float64) float64 {
+	degrees := math.Mod(float64(units.RadiansToDegrees(heading))+rotation, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+
+	return degrees
+}
@@ -0,0 +1,119 @@
+package gttelemetry_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+	"github.com/zetetos/gt-telemetry/v2/pkg/geo"
+	"github.com/zetetos/gt-telemetry/v2/pkg/vehicles"
+)
+
+type VideoCSVTestSuite struct {
+	suite.Suite
+
+	transformer *gttelemetry.Transformer
+}
+
+func TestVideoCSVTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(VideoCSVTestSuite))
+}
+
+func (suite *VideoCSVTestSuite) SetupTest() {
+	inventory, err := vehicles.NewDB([]byte(`{}`), vehicles.DBOptions{})
+	suite.Require().NoError(err)
+
+	suite.transformer = gttelemetry.NewTransformer(inventory)
+	suite.transformer.RawTelemetry.CurrentLap = 1
+	suite.transformer.RawTelemetry.GroundSpeed = 50
+	suite.transformer.SetTransmissionGear(4, 15)
+	suite.transformer.SetMapPositionCoordinates(0, 12.5, 0)
+}
+
+// write writes the given number of consecutive frames and returns the CSV records.
+func (suite *VideoCSVTestSuite) write(preset gttelemetry.VideoCSVPreset, frames int, precision ...gttelemetry.ChannelPrecision) [][]string {
+	var buffer bytes.Buffer
+
+	writer := gttelemetry.NewVideoCSVWriter(&buffer, preset, geo.Anchor{Latitude: 50.33, Longitude: 6.94})
+	for _, p := range precision {
+		writer.SetPrecision(p)
+	}
+
+	for range frames {
+		suite.transformer.RawTelemetry.SequenceId++
+		suite.Require().NoError(writer.Write(suite.transformer))
+		suite.Require().NoError(writer.Write(suite.transformer))
+	}
+
+	suite.Require().NoError(writer.Flush())
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	suite.Require().NoError(err)
+
+	return records
+}
+
+func (suite *VideoCSVTestSuite) TestRaceRenderLayout() {
+	// Act
+	records := suite.write(gttelemetry.VideoCSVRaceRender, 4)
+
+	// Assert
+	suite.Require().Len(records, 5)
+	suite.Equal([]string{"Time", "Lap", "Latitude", "Longitude"}, records[0][:4])
+	suite.Equal([]string{"0.050", "1", "50.33000000", "6.94000000", "12.50", "180.00"}, records[4][:6])
+	suite.Equal("4", records[4][8])
+}
+
+func (suite *VideoCSVTestSuite) TestTelemetryOverlayLayout() {
+	// Act
+	records := suite.write(gttelemetry.VideoCSVTelemetryOverlay, 2)
+
+	// Assert
+	suite.Require().Len(records, 3)
+	suite.Equal([]string{"cts (ms)", "lap", "lat (deg)", "lon (deg)"}, records[0][:4])
+	suite.Equal("16", records[2][0])
+}
+
+func (suite *VideoCSVTestSuite) TestPrecisionByChannel() {
+	// Act
+	records := suite.write(gttelemetry.VideoCSVRaceRender, 1, gttelemetry.ChannelPrecision{"groundSpeedKPH": 0, "positionY": 3})
+
+	// Assert
+	suite.Require().Len(records, 2)
+	suite.Equal([]string{"12.500", "180"}, records[1][4:6])
+}
+
+func (suite *VideoCSVTestSuite) TestParseVideoCSVPreset() {
+	tests := map[string]struct {
+		name    string
+		want    gttelemetry.VideoCSVPreset
+		wantErr error
+	}{
+		"racerender": {
+			name: "racerender",
+			want: gttelemetry.VideoCSVRaceRender,
+		},
+		"telemetry overlay": {
+			name: "telemetry-overlay",
+			want: gttelemetry.VideoCSVTelemetryOverlay,
+		},
+		"unknown": {
+			name:    "harry's laptimer",
+			wantErr: gttelemetry.ErrUnknownVideoCSVPreset,
+		},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			preset, err := gttelemetry.ParseVideoCSVPreset(test.name)
+
+			// Assert
+			suite.Require().ErrorIs(err, test.wantErr)
+			suite.Equal(test.want, preset)
+		})
+	}
+}
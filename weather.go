@@ -0,0 +1,291 @@
+package gttelemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// weatherScriptStartTolerance is how far the time of day at the start of a session may be from the start time of
+// a weather script for the script to be used, as the clock has moved on by the time the first lap starts.
+const weatherScriptStartTolerance = 30 * time.Minute
+
+// ErrInvalidWeatherScript indicates a weather script without a circuit, with an unknown weather or with changes that
+// are not in time order.
+var ErrInvalidWeatherScript = errors.New("invalid weather script")
+
+// WeatherChange is a change of weather at a time of day.
+type WeatherChange struct {
+	TimeOfDay time.Duration
+	Weather   WeatherEstimate
+}
+
+// WeatherScript is the known weather of an event on a circuit starting at a time of day, such as the weather
+// published for an online race or noted down from an earlier race, so that strategy tools can project the conditions
+// of the rest of the race. Telemetry does not report the weather, so scripts are provided by the user.
+//
+// Times of day are written as "HH:MM" in JSON. A script may run past midnight, changes are ordered by the time since
+// the start.
+type WeatherScript struct {
+	CircuitID string
+	Start     time.Duration
+	Weather   WeatherEstimate
+	Changes   []WeatherChange
+}
+
+// WeatherScriptValidation compares a weather script with the weather estimated for the laps of a session.
+type WeatherScriptValidation struct {
+	// Laps is the number of laps with an estimated weather, laps of unknown weather are not compared.
+	Laps int `json:"laps"`
+	// Matching is the number of those laps whose estimated weather matched the script.
+	Matching int `json:"matching"`
+	// Mismatched lists the laps whose estimated weather did not match the script.
+	Mismatched []int16 `json:"mismatched,omitempty"`
+}
+
+type weatherChangeJSON struct {
+	Time    string          `json:"time"`
+	Weather WeatherEstimate `json:"weather"`
+}
+
+type weatherScriptJSON struct {
+	CircuitID string              `json:"circuitId"`
+	Start     string              `json:"start"`
+	Weather   WeatherEstimate     `json:"weather"`
+	Changes   []weatherChangeJSON `json:"changes,omitempty"`
+}
+
+// MarshalJSON writes the script with times of day as "HH:MM".
+func (s WeatherScript) MarshalJSON() ([]byte, error) {
+	script := weatherScriptJSON{
+		CircuitID: s.CircuitID,
+		Start:     formatTimeOfDay(s.Start),
+		Weather:   s.Weather,
+		Changes:   make([]weatherChangeJSON, 0, len(s.Changes)),
+	}
+
+	for _, change := range s.Changes {
+		script.Changes = append(script.Changes, weatherChangeJSON{Time: formatTimeOfDay(change.TimeOfDay), Weather: change.Weather})
+	}
+
+	return json.Marshal(script) //nolint:wrapcheck // plain struct of strings
+}
+
+// UnmarshalJSON reads a script with times of day written as "HH:MM".
+func (s *WeatherScript) UnmarshalJSON(data []byte) error {
+	var script weatherScriptJSON
+
+	err := json.Unmarshal(data, &script)
+	if err != nil {
+		return fmt.Errorf("parsing weather script: %w", err)
+	}
+
+	start, err := parseTimeOfDay(script.Start)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]WeatherChange, 0, len(script.Changes))
+
+	for _, change := range script.Changes {
+		timeOfDay, err := parseTimeOfDay(change.Time)
+		if err != nil {
+			return err
+		}
+
+		changes = append(changes, WeatherChange{TimeOfDay: timeOfDay, Weather: change.Weather})
+	}
+
+	*s = WeatherScript{CircuitID: script.CircuitID, Start: start, Weather: script.Weather, Changes: changes}
+
+	return nil
+}
+
+// Validate checks the script has a circuit, a known weather for every change and changes in time order.
+func (s WeatherScript) Validate() error {
+	if s.CircuitID == "" {
+		return fmt.Errorf("%w: no circuit", ErrInvalidWeatherScript)
+	}
+
+	if s.Start < 0 || s.Start >= dayLength {
+		return fmt.Errorf("%w: start %v is not a time of day", ErrInvalidWeatherScript, s.Start)
+	}
+
+	if s.Weather != WeatherDry && s.Weather != WeatherWet {
+		return fmt.Errorf("%w: weather %q at the start is not dry or wet", ErrInvalidWeatherScript, s.Weather)
+	}
+
+	last := time.Duration(0)
+
+	for _, change := range s.Changes {
+		if change.Weather != WeatherDry && change.Weather != WeatherWet {
+			return fmt.Errorf("%w: weather %q at %s is not dry or wet", ErrInvalidWeatherScript, change.Weather, formatTimeOfDay(change.TimeOfDay))
+		}
+
+		offset := s.sinceStart(change.TimeOfDay)
+		if offset <= last {
+			return fmt.Errorf("%w: change at %s is not after the previous change", ErrInvalidWeatherScript, formatTimeOfDay(change.TimeOfDay))
+		}
+
+		last = offset
+	}
+
+	return nil
+}
+
+// WeatherAt projects the weather at a time of day.
+func (s WeatherScript) WeatherAt(timeOfDay time.Duration) WeatherEstimate {
+	weather := s.Weather
+	offset := s.sinceStart(timeOfDay)
+
+	for _, change := range s.Changes {
+		if s.sinceStart(change.TimeOfDay) > offset {
+			break
+		}
+
+		weather = change.Weather
+	}
+
+	return weather
+}
+
+// NextChange returns the first change of weather after a time of day, or false when the weather does not change again.
+func (s WeatherScript) NextChange(timeOfDay time.Duration) (WeatherChange, bool) {
+	offset := s.sinceStart(timeOfDay)
+
+	for _, change := range s.Changes {
+		if s.sinceStart(change.TimeOfDay) > offset {
+			return change, true
+		}
+	}
+
+	return WeatherChange{}, false
+}
+
+// ValidateHistory compares the script with the weather estimated for completed laps, such as the snapshots of a
+// LapHistory, to check the script matches the event being driven. The estimate is taken from the tyre temperatures
+// so lags a change of weather by a lap or so, a few mismatched laps around a change are to be expected.
+func (s WeatherScript) ValidateHistory(snapshots []LapSnapshot) WeatherScriptValidation {
+	validation := WeatherScriptValidation{}
+
+	for _, snapshot := range snapshots {
+		if snapshot.Weather == WeatherUnknown {
+			continue
+		}
+
+		validation.Laps++
+
+		if s.WeatherAt(snapshot.TimeOfDay) == snapshot.Weather {
+			validation.Matching++
+		} else {
+			validation.Mismatched = append(validation.Mismatched, snapshot.Lap)
+		}
+	}
+
+	return validation
+}
+
+// sinceStart returns the time from the start of the script to a time of day, wrapping past midnight.
+func (s WeatherScript) sinceStart(timeOfDay time.Duration) time.Duration {
+	return ((timeOfDay-s.Start)%dayLength + dayLength) % dayLength
+}
+
+// WeatherScripts holds the weather scripts registered by the user, looked up by circuit and start time of day. It is
+// safe for concurrent use.
+type WeatherScripts struct {
+	mu      sync.RWMutex
+	scripts []WeatherScript
+}
+
+// NewWeatherScripts creates an empty set of weather scripts.
+func NewWeatherScripts() *WeatherScripts {
+	return &WeatherScripts{}
+}
+
+// Register adds a script, replacing any script for the same circuit and start time.
+func (w *WeatherScripts) Register(script WeatherScript) error {
+	err := script.Validate()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.scripts = slices.DeleteFunc(w.scripts, func(existing WeatherScript) bool {
+		return existing.CircuitID == script.CircuitID && existing.Start == script.Start
+	})
+	w.scripts = append(w.scripts, script)
+
+	return nil
+}
+
+// Load registers the scripts of a JSON array, registering none of them when any is invalid.
+func (w *WeatherScripts) Load(data []byte) error {
+	var scripts []WeatherScript
+
+	err := json.Unmarshal(data, &scripts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidWeatherScript, err)
+	}
+
+	for i, script := range scripts {
+		err := script.Validate()
+		if err != nil {
+			return fmt.Errorf("script %d: %w", i, err)
+		}
+	}
+
+	for _, script := range scripts {
+		_ = w.Register(script)
+	}
+
+	return nil
+}
+
+// Lookup returns the script of a circuit whose start time is closest to the time of day at the start of a session,
+// or false when no script starts within half an hour of it.
+func (w *WeatherScripts) Lookup(circuitID string, start time.Duration) (WeatherScript, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var (
+		found   WeatherScript
+		closest = weatherScriptStartTolerance + 1
+	)
+
+	for _, script := range w.scripts {
+		if script.CircuitID != circuitID {
+			continue
+		}
+
+		// Compare the shorter way around the clock, so 23:50 is close to 00:10
+		distance := script.sinceStart(start)
+		distance = min(distance, dayLength-distance)
+
+		if distance < closest {
+			found = script
+			closest = distance
+		}
+	}
+
+	return found, closest <= weatherScriptStartTolerance
+}
+
+// formatTimeOfDay formats a time of day as "HH:MM".
+func formatTimeOfDay(timeOfDay time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(timeOfDay.Hours()), int(timeOfDay.Minutes())%60)
+}
+
+// parseTimeOfDay parses a time of day written as "HH:MM".
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: time of day %q is not HH:MM", ErrInvalidWeatherScript, value)
+	}
+
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
@@ -0,0 +1,180 @@
+package gttelemetry_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	gttelemetry "github.com/zetetos/gt-telemetry/v2"
+)
+
+type WeatherScriptTestSuite struct {
+	suite.Suite
+
+	script gttelemetry.WeatherScript
+}
+
+func TestWeatherScriptTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(WeatherScriptTestSuite))
+}
+
+func (suite *WeatherScriptTestSuite) SetupTest() {
+	// A race starting late in the evening that runs past midnight
+	suite.script = gttelemetry.WeatherScript{
+		CircuitID: "spa",
+		Start:     23 * time.Hour,
+		Weather:   gttelemetry.WeatherDry,
+		Changes: []gttelemetry.WeatherChange{
+			{TimeOfDay: 23*time.Hour + 30*time.Minute, Weather: gttelemetry.WeatherWet},
+			{TimeOfDay: 30 * time.Minute, Weather: gttelemetry.WeatherDry},
+		},
+	}
+}
+
+func (suite *WeatherScriptTestSuite) TestWeatherAtProjectsChanges() {
+	tests := map[string]struct {
+		timeOfDay time.Duration
+		want      gttelemetry.WeatherEstimate
+	}{
+		"at the start":         {timeOfDay: 23 * time.Hour, want: gttelemetry.WeatherDry},
+		"before the rain":      {timeOfDay: 23*time.Hour + 29*time.Minute, want: gttelemetry.WeatherDry},
+		"when the rain starts": {timeOfDay: 23*time.Hour + 30*time.Minute, want: gttelemetry.WeatherWet},
+		"past midnight":        {timeOfDay: 10 * time.Minute, want: gttelemetry.WeatherWet},
+		"after drying":         {timeOfDay: time.Hour, want: gttelemetry.WeatherDry},
+	}
+
+	for name, test := range tests {
+		suite.Run(name, func() {
+			// Act
+			got := suite.script.WeatherAt(test.timeOfDay)
+
+			// Assert
+			suite.Equal(test.want, got)
+		})
+	}
+}
+
+func (suite *WeatherScriptTestSuite) TestNextChange() {
+	// Act
+	next, found := suite.script.NextChange(23*time.Hour + 45*time.Minute)
+	_, foundAfterLast := suite.script.NextChange(time.Hour)
+
+	// Assert
+	suite.Require().True(found)
+	suite.Equal(30*time.Minute, next.TimeOfDay)
+	suite.Equal(gttelemetry.WeatherDry, next.Weather)
+	suite.False(foundAfterLast)
+}
+
+func (suite *WeatherScriptTestSuite) TestJSONUsesClockTimes() {
+	// Act
+	data, err := json.Marshal(suite.script)
+	suite.Require().NoError(err)
+
+	var decoded gttelemetry.WeatherScript
+
+	err = json.Unmarshal(data, &decoded)
+
+	// Assert
+	suite.Require().NoError(err)
+	suite.JSONEq(`{
+		"circuitId": "spa",
+		"start": "23:00",
+		"weather": "dry",
+		"changes": [{"time": "23:30", "weather": "wet"}, {"time": "00:30", "weather": "dry"}]
+	}`, string(data))
+	suite.Equal(suite.script, decoded)
+}
+
+func (suite *WeatherScriptTestSuite) TestValidateRejectsInvalidScripts() {
+	tests := map[string]func(script *gttelemetry.WeatherScript){
+		"no circuit":      func(script *gttelemetry.WeatherScript) { script.CircuitID = "" },
+		"unknown weather": func(script *gttelemetry.WeatherScript) { script.Weather = gttelemetry.WeatherUnknown },
+		"start past midnight": func(script *gttelemetry.WeatherScript) {
+			script.Start = 25 * time.Hour
+		},
+		"changes out of order": func(script *gttelemetry.WeatherScript) {
+			script.Changes[0], script.Changes[1] = script.Changes[1], script.Changes[0]
+		},
+		"change at the start": func(script *gttelemetry.WeatherScript) {
+			script.Changes[0].TimeOfDay = script.Start
+		},
+	}
+
+	for name, modify := range tests {
+		suite.Run(name, func() {
+			// Arrange
+			script := suite.script
+			script.Changes = append([]gttelemetry.WeatherChange(nil), suite.script.Changes...)
+			modify(&script)
+
+			// Act
+			err := script.Validate()
+
+			// Assert
+			suite.ErrorIs(err, gttelemetry.ErrInvalidWeatherScript)
+		})
+	}
+}
+
+func (suite *WeatherScriptTestSuite) TestValidateHistoryComparesEstimatedWeather() {
+	// Arrange
+	snapshots := []gttelemetry.LapSnapshot{
+		{Lap: 1, TimeOfDay: 23*time.Hour + 10*time.Minute, Weather: gttelemetry.WeatherUnknown},
+		{Lap: 2, TimeOfDay: 23*time.Hour + 20*time.Minute, Weather: gttelemetry.WeatherDry},
+		{Lap: 3, TimeOfDay: 23*time.Hour + 40*time.Minute, Weather: gttelemetry.WeatherDry},
+		{Lap: 4, TimeOfDay: 23*time.Hour + 50*time.Minute, Weather: gttelemetry.WeatherWet},
+	}
+
+	// Act
+	validation := suite.script.ValidateHistory(snapshots)
+
+	// Assert
+	suite.Equal(3, validation.Laps)
+	suite.Equal(2, validation.Matching)
+	suite.Equal([]int16{3}, validation.Mismatched)
+}
+
+func (suite *WeatherScriptTestSuite) TestLookupFindsClosestStart() {
+	// Arrange
+	scripts := gttelemetry.NewWeatherScripts()
+	suite.Require().NoError(scripts.Register(suite.script))
+
+	afternoon := suite.script
+	afternoon.Start = 14 * time.Hour
+	afternoon.Changes = nil
+	suite.Require().NoError(scripts.Register(afternoon))
+
+	// Act
+	late, foundLate := scripts.Lookup("spa", 23*time.Hour+10*time.Minute)
+	early, foundEarly := scripts.Lookup("spa", 22*time.Hour+50*time.Minute)
+	_, foundEvening := scripts.Lookup("spa", 18*time.Hour)
+	_, foundOtherCircuit := scripts.Lookup("monza", 14*time.Hour)
+
+	// Assert
+	suite.Require().True(foundLate)
+	suite.Equal(23*time.Hour, late.Start)
+	suite.Require().True(foundEarly)
+	suite.Equal(23*time.Hour, early.Start)
+	suite.False(foundEvening)
+	suite.False(foundOtherCircuit)
+}
+
+func (suite *WeatherScriptTestSuite) TestLoadRegistersNoneWhenAnyIsInvalid() {
+	// Arrange
+	scripts := gttelemetry.NewWeatherScripts()
+	data := []byte(`[
+		{"circuitId": "spa", "start": "14:00", "weather": "dry"},
+		{"circuitId": "monza", "start": "14:00", "weather": "snow"}
+	]`)
+
+	// Act
+	err := scripts.Load(data)
+	_, found := scripts.Lookup("spa", 14*time.Hour)
+
+	// Assert
+	suite.ErrorIs(err, gttelemetry.ErrInvalidWeatherScript)
+	suite.False(found)
+}